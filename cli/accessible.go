@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// isAccessible reports whether output suits screen readers, with
+// --accessible or display.accessible in the config file: no tables, box
+// drawing or color, and statuses spelled out
+func (c *CLI) isAccessible() bool {
+	return c.accessible || c.config.Display.Accessible
+}
+
+// statusLabel returns how a status is printed among task details
+func (c *CLI) statusLabel(status task.TaskStatus) string {
+	if c.isAccessible() {
+		return spellStatus(c.tr.Status(status))
+	}
+	return strings.ToUpper(c.tr.Status(status))
+}
+
+// printTaskRecords prints tasks as "field: value" lines, a blank line
+// between tasks
+func (c *CLI) printTaskRecords(tasks []task.Task, showAge bool) {
+	fmt.Fprintln(c.stdout, c.tr.Sprintf(c.tr.Plural(len(tasks), "%d tasks"), len(tasks)))
+	for _, t := range tasks {
+		field := func(format string, value any) {
+			fmt.Fprintln(c.stdout, c.tr.Sprintf(format, value))
+		}
+		fmt.Fprintln(c.stdout)
+		field("Task %d", t.ID)
+		field("Status: %s", c.statusLabel(t.Status))
+		field("Description: %s", t.Description)
+		field("Created: %s", c.formatTime(t.CreatedAt, "2006-01-02 15:04"))
+		field("Updated: %s", c.formatTime(t.UpdatedAt, "2006-01-02 15:04"))
+		if t.DueAt != nil {
+			field("Due: %s", c.formatTime(*t.DueAt, "2006-01-02 15:04"))
+		}
+		if t.Priority != task.PriorityNone {
+			field("Priority: %s", t.Priority)
+		}
+		if len(t.Tags) > 0 {
+			field("Tags: %s", strings.Join(t.Tags, ", "))
+		}
+		if t.Project != "" {
+			field("Project: %s", t.Project)
+		}
+		if t.Milestone != "" {
+			field("Milestone: %s", t.Milestone)
+		}
+		if t.Sprint != "" {
+			field("Sprint: %s", t.Sprint)
+		}
+		if t.Assignee != "" {
+			field("Assignee: %s", t.Assignee)
+		}
+		if showAge {
+			age, inStatus := c.taskAge(t)
+			field("Age: %s", age)
+			field("In status for: %s", inStatus)
+		}
+	}
+}
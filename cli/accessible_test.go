@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_Accessible tests that --accessible replaces tables and columns
+// with "field: value" lines and spells out statuses
+func TestCLI_Accessible(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)
+	tasks[1].Assignee = "sam"
+	h := newCLIHarness(t, tasks)
+
+	h.run("--accessible", "list", "in-progress")
+	want := "1 task\n\nTask 2\nStatus: in progress\nDescription: Write report\n" +
+		"Created: 2024-01-01 12:00\nUpdated: 2024-01-01 12:01\nAssignee: sam\n"
+	if out := h.stdout.String(); out != want {
+		t.Errorf("list output = %q, want %q", out, want)
+	}
+
+	h.run("--accessible", "list", "--columns", "id,status,project")
+	if out := h.stdout.String(); out != "ID: 1\nStatus: todo\n\nID: 2\nStatus: in progress\n\nID: 3\nStatus: done\n" {
+		t.Errorf("list --columns output = %q", out)
+	}
+	// Delimited formats are for programs, not readers
+	h.run("--accessible", "list", "--format", "tsv", "--columns", "id", "--no-header")
+	if out := h.stdout.String(); out != "1\n2\n3\n" {
+		t.Errorf("list --format tsv output = %q", out)
+	}
+
+	h.cli.config.Display.Accessible = true
+	h.run("board")
+	want = "todo: 1 task\n  Task 1: Buy groceries\n\n" +
+		"in progress: 1 task\n  Task 2: Write report, assigned to sam\n\n" +
+		"done: 1 task\n  Task 3: Call mom\n"
+	if out := h.stdout.String(); out != want {
+		t.Errorf("board output = %q, want %q", out, want)
+	}
+	h.run("show", "2")
+	if out := h.stdout.String(); !strings.Contains(out, "Status:   in progress\n") {
+		t.Errorf("show output = %q, want the status spelled out", out)
+	}
+}
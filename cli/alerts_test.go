@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// fakeNotifier records notifications instead of displaying them
+type fakeNotifier struct {
+	sent []string
+}
+
+func (n *fakeNotifier) Send(_ context.Context, title, body string) error {
+	n.sent = append(n.sent, title+": "+body)
+	return nil
+}
+
+// TestCLI_Notify tests the notify command with an injected notifier
+func TestCLI_Notify(t *testing.T) {
+	due := tasktest.FixedTime().Add(20 * time.Minute)
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].DueAt = &due
+
+	h := newCLIHarness(t, tasks)
+	notifier := &fakeNotifier{}
+	h.cli.WithNotifier(notifier)
+
+	if code := h.run("notify", "--stale-after", "100000h"); code != 0 {
+		t.Fatalf("notify exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+
+	if len(notifier.sent) != 1 || !strings.Contains(notifier.sent[0], "Buy groceries is due in 20m") {
+		t.Errorf("notifications = %q, want one due-soon alert", notifier.sent)
+	}
+
+	t.Run("dry run prints alerts", func(t *testing.T) {
+		h := newCLIHarness(t, tasks)
+		if code := h.run("notify", "--dry-run"); code != 0 {
+			t.Fatalf("notify exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if !strings.Contains(h.stdout.String(), "Task due soon: #1 Buy groceries") {
+			t.Errorf("stdout = %q, want due-soon alert", h.stdout.String())
+		}
+	})
+
+	t.Run("interval must be positive", func(t *testing.T) {
+		h := newCLIHarness(t, tasks)
+		h.cli.WithNotifier(&fakeNotifier{})
+		if code := h.run("notify", "--daemon", "--interval", "0s"); code != 1 {
+			t.Errorf("notify --daemon --interval 0s exit code = %d, want 1", code)
+		}
+		if !strings.Contains(h.stderr.String(), "--interval must be positive") {
+			t.Errorf("stderr = %q, want the interval refused", h.stderr.String())
+		}
+	})
+
+	t.Run("missing notifier", func(t *testing.T) {
+		h := newCLIHarness(t, tasks)
+		if code := h.run("notify"); code != 1 {
+			t.Errorf("notify without notifier exit code = %d, want 1", code)
+		}
+	})
+}
+
+// TestCLI_Due tests setting and clearing deadlines
+func TestCLI_Due(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	if code := h.run("due", "1", "tomorrow 9am"); code != 0 {
+		t.Fatalf("due exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	task, _ := h.repo.GetTask(1)
+	want := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+	if task.DueAt == nil || !task.DueAt.Equal(want) {
+		t.Errorf("DueAt = %v, want %v", task.DueAt, want)
+	}
+
+	if code := h.run("due", "1", "none"); code != 0 {
+		t.Fatalf("due none exit code = %d", code)
+	}
+	task, _ = h.repo.GetTask(1)
+	if task.DueAt != nil {
+		t.Errorf("DueAt = %v, want cleared", task.DueAt)
+	}
+
+	if code := h.run("add", "Pay rent", "--due", "2024-02-01"); code != 0 {
+		t.Fatalf("add --due exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	task, _ = h.repo.GetTask(4)
+	if task.DueAt == nil || task.DueAt.Format("2006-01-02 15:04") != "2024-02-01 23:59" {
+		t.Errorf("add --due DueAt = %v, want end of 2024-02-01", task.DueAt)
+	}
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alnah/task-tracker/repository"
+)
+
+// maxAliasDepth bounds how many aliases may expand into one another, which
+// stops aliases that refer to themselves
+const maxAliasDepth = 10
+
+// WithConfigFile sets the config file the alias command writes to
+func (c *CLI) WithConfigFile(path string) *CLI {
+	c.configFile = path
+	return c
+}
+
+// runAlias expands the alias named by args[1] and dispatches the result
+// with the remaining arguments appended. Built-in commands are dispatched
+// before aliases are looked at, so an alias cannot hide one.
+func (c *CLI) runAlias(ctx context.Context, args []string) (int, bool) {
+	expansion, ok := c.config.Aliases[args[1]]
+	if !ok {
+		return 0, false
+	}
+	if c.aliasDepth >= maxAliasDepth {
+		c.errorf("Error: alias %s expands into itself\n", args[1])
+		return 1, true
+	}
+
+	words, err := splitCommandLine(expansion)
+	if err != nil || len(words) == 0 {
+		c.errorf("Error: invalid alias %s: %q\n", args[1], expansion)
+		return 1, true
+	}
+	expanded := append([]string{args[0]}, words...)
+	expanded = append(expanded, args[2:]...)
+
+	c.aliasDepth++
+	defer func() { c.aliasDepth-- }()
+	return c.dispatch(ctx, expanded[1], expanded), true
+}
+
+// aliasedCommand returns the command an alias finally runs, or name itself
+// when it is not an alias
+func (c *CLI) aliasedCommand(name string) string {
+	for range maxAliasDepth {
+		words, err := splitCommandLine(c.config.Aliases[name])
+		if err != nil || len(words) == 0 {
+			return name
+		}
+		name = words[0]
+	}
+	return name
+}
+
+func (c *CLI) handleAlias(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "list":
+		names := make([]string, 0, len(c.config.Aliases))
+		for name := range c.config.Aliases {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			fmt.Fprintf(c.stdout, "%s = %s\n", name, c.config.Aliases[name])
+		}
+		return 0
+
+	case "set":
+		if len(args) < 3 {
+			c.errorf("Error: Alias name and command are required\n")
+			c.errorf("Usage: task-cli alias set <name> <command> [arguments]\n")
+			return 1
+		}
+		name := args[1]
+		if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t") {
+			c.errorf("Error: invalid alias name %q\n", name)
+			return 1
+		}
+		// A single argument is a whole command line, as in "list --all"
+		expansion := args[2]
+		if len(args) > 3 {
+			expansion = joinCommandLine(args[2:])
+		}
+		if _, err := splitCommandLine(expansion); err != nil {
+			return c.fail(err)
+		}
+
+		aliases := make(map[string]string, len(c.config.Aliases)+1)
+		for k, v := range c.config.Aliases {
+			aliases[k] = v
+		}
+		aliases[name] = expansion
+		if err := c.saveAliases(aliases); err != nil {
+			return c.fail(err)
+		}
+		c.successf("Alias %s = %s\n", name, expansion)
+		return 0
+
+	case "unset":
+		if len(args) < 2 {
+			c.errorf("Error: Alias name is required\n")
+			c.errorf("Usage: task-cli alias unset <name>\n")
+			return 1
+		}
+		if _, ok := c.config.Aliases[args[1]]; !ok {
+			c.errorf("Error: no alias named %s\n", args[1])
+			return 1
+		}
+		aliases := make(map[string]string, len(c.config.Aliases))
+		for k, v := range c.config.Aliases {
+			if k != args[1] {
+				aliases[k] = v
+			}
+		}
+		if err := c.saveAliases(aliases); err != nil {
+			return c.fail(err)
+		}
+		c.successf("Alias %s removed\n", args[1])
+		return 0
+
+	default:
+		c.errorf("Unknown alias command: %s\n", args[0])
+		c.errorf("Usage: task-cli alias list|set|unset\n")
+		return 1
+	}
+}
+
+// saveAliases writes the aliases to the config file and uses them from now on
+func (c *CLI) saveAliases(aliases map[string]string) error {
+	if c.configFile == "" {
+		return fmt.Errorf("no config file to save aliases to")
+	}
+	if err := repository.SetConfigKey(c.configFile, "aliases", aliases); err != nil {
+		return err
+	}
+	c.config.Aliases = aliases
+	return nil
+}
+
+// splitCommandLine splits an alias into arguments like a shell would:
+// on blanks, keeping quoted text together, with backslash escaping the
+// next character
+func splitCommandLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// joinCommandLine quotes arguments so that splitCommandLine gives them back
+func joinCommandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+)
+
+// TestCLI_Alias tests running, defining and removing aliases
+func TestCLI_Alias(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	config := repository.DefaultConfig()
+	config.Aliases = map[string]string{
+		"wip":   "list in-progress",
+		"later": "wip --all",
+		"loop":  "loop",
+		"list":  "board",
+	}
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	h.cli.WithConfig(config).WithConfigFile(configFile)
+
+	if code := h.run("later"); code != 0 {
+		t.Fatalf("later exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if out := h.stdout.String(); !strings.Contains(out, "Write report") || strings.Contains(out, "Buy groceries") {
+		t.Errorf("later output = %q, want only the in-progress task", out)
+	}
+	if code := h.run("loop"); code != 1 || !strings.Contains(h.stderr.String(), "expands into itself") {
+		t.Errorf("loop exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	h.run("list")
+	if !strings.Contains(h.stdout.String(), "Buy groceries") {
+		t.Errorf("list output = %q, want the built-in command", h.stdout.String())
+	}
+
+	if code := h.run("alias", "set", "todo", "list", "todo", "--project", "Home Stuff"); code != 0 {
+		t.Fatalf("alias set exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("alias", "unset", "loop"); code != 0 {
+		t.Fatalf("alias unset exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	h.run("alias", "list")
+	want := "later = wip --all\nlist = board\ntodo = list todo --project 'Home Stuff'\nwip = list in-progress\n"
+	if h.stdout.String() != want {
+		t.Errorf("alias list = %q, want %q", h.stdout.String(), want)
+	}
+
+	saved, err := repository.LoadConfig(configFile)
+	if err != nil || len(saved.Aliases) != 4 || saved.Aliases["todo"] != "list todo --project 'Home Stuff'" {
+		t.Errorf("saved aliases = %v, %v", saved.Aliases, err)
+	}
+}
+
+// TestSplitCommandLine tests splitting aliases into arguments
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{"list in-progress", []string{"list", "in-progress"}, false},
+		{`  add "Buy milk"  --due 'next week' `, []string{"add", "Buy milk", "--due", "next week"}, false},
+		{`add it\'s ''`, []string{"add", "it's", ""}, false},
+		{`add "unterminated`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := splitCommandLine(tt.line)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommandLine(%q) = %q, %v; want %q", tt.line, got, err, tt.want)
+		}
+	}
+
+	args := []string{"add", "it's", "a b", ""}
+	if got, _ := splitCommandLine(joinCommandLine(args)); !reflect.DeepEqual(got, args) {
+		t.Errorf("splitCommandLine(joinCommandLine(%q)) = %q", args, got)
+	}
+}
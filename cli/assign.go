@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleAssign(ctx context.Context, args []string) int {
+	if len(args) < 2 {
+		c.errorf("Error: ID and user are required\n")
+		c.errorf("Usage: task-cli assign <id> <user|me|none>\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	user := c.resolveUser(args[1])
+	if err := c.service.AssignTask(ctx, id, user); err != nil {
+		return c.fail(err)
+	}
+
+	if user == "" {
+		c.successf("Task %d unassigned\n", id)
+	} else {
+		c.successf("Task %d assigned to %s\n", id, user)
+	}
+	return 0
+}
+
+// resolveUser turns "me" into the configured user and "none" into no user
+func (c *CLI) resolveUser(name string) string {
+	switch name {
+	case "me":
+		return c.currentUser()
+	case "none":
+		return ""
+	default:
+		return name
+	}
+}
+
+// currentUser is the user from the config file, $TASK_CLI_USER or the login
+func (c *CLI) currentUser() string {
+	if c.config.User != "" {
+		return c.config.User
+	}
+	return task.CurrentActor()
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+func (c *CLI) handleAttach(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("attach")
+	label := fs.String("label", "", "a short name shown instead of the target")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) < 2 {
+		c.errorf("Error: ID and URL or path are required\n")
+		c.errorf("Usage: task-cli attach <id> <url|path> [--label <label>]\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	target := args[1]
+	if !isURL(target) {
+		// Store files by absolute path so open works from any directory
+		if target, err = filepath.Abs(repository.ExpandPath(target)); err == nil {
+			_, err = os.Stat(target)
+		}
+		if err != nil {
+			return c.fail(err)
+		}
+	}
+
+	n, err := c.service.AttachToTask(ctx, id, target, *label)
+	if err != nil {
+		return c.fail(err)
+	}
+	c.successf("Attachment %d added to task %d\n", n, id)
+	return 0
+}
+
+func (c *CLI) handleOpen(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		c.errorf("Error: ID is required\n")
+		c.errorf("Usage: task-cli open <id> [<n>]\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+	n := 1
+	if len(args) > 1 {
+		if n, err = strconv.Atoi(args[1]); err != nil {
+			c.errorf("Error: invalid attachment number %q\n", args[1])
+			return 1
+		}
+	}
+
+	task, err := c.service.GetTask(ctx, id)
+	if err != nil {
+		return c.fail(err)
+	}
+	attachment, err := task.Attachment(n)
+	if err != nil {
+		return c.fail(err)
+	}
+	if c.opener == nil {
+		return c.fail(service.ErrOpenerUnavailable)
+	}
+	if err := c.opener.Open(ctx, attachment.Target); err != nil {
+		return c.fail(err)
+	}
+	c.successf("Opened %s\n", attachment.Target)
+	return 0
+}
+
+// isURL reports whether an attachment target names a URL rather than a file
+func isURL(target string) bool {
+	u, err := url.Parse(target)
+	// One-letter schemes are Windows drive letters
+	return err == nil && len(u.Scheme) > 1
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// fakeOpener records the targets it is asked to open
+type fakeOpener struct {
+	opened []string
+}
+
+func (o *fakeOpener) Open(_ context.Context, target string) error {
+	o.opened = append(o.opened, target)
+	return nil
+}
+
+// TestCLI_AttachOpen tests attaching URLs and files and opening them
+func TestCLI_AttachOpen(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	opener := &fakeOpener{}
+	h.cli.WithOpener(opener)
+
+	if code := h.run("attach", "2", "https://example.com/spec", "--label", "spec"); code != 0 {
+		t.Fatalf("attach URL exit code = %d, stderr: %s", code, h.stderr)
+	}
+	file := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(file, []byte("draft"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code := h.run("attach", "2", file); code != 0 || h.stdout.String() != "Attachment 2 added to task 2\n" {
+		t.Fatalf("attach file = %d, %q, stderr: %s", code, h.stdout, h.stderr)
+	}
+	if code := h.run("attach", "2", filepath.Join(t.TempDir(), "missing.txt")); code != 1 {
+		t.Errorf("missing file exit code = %d, want 1", code)
+	}
+
+	if code := h.run("show", "2"); code != 0 {
+		t.Fatal(h.stderr)
+	}
+	want := "Attachments:\n  1. spec  https://example.com/spec\n  2. " + file + "\n"
+	if !strings.Contains(h.stdout.String(), want) {
+		t.Errorf("show missing %q:\n%s", want, h.stdout)
+	}
+
+	h.run("open", "2")
+	h.run("open", "2", "2")
+	if len(opener.opened) != 2 || opener.opened[0] != "https://example.com/spec" || opener.opened[1] != file {
+		t.Errorf("opened = %q", opener.opened)
+	}
+	if code := h.run("open", "2", "3"); code != exitNotFound {
+		t.Errorf("unknown attachment exit code = %d, want %d", code, exitNotFound)
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://example.com":    true,
+		"mailto:ana@example.com": true,
+		"docs/spec.pdf":          false,
+		`C:\docs\spec.pdf`:       false,
+		"/tmp/spec.pdf":          false,
+	}
+	for target, want := range tests {
+		if got := isURL(target); got != want {
+			t.Errorf("isURL(%q) = %v, want %v", target, got, want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+)
+
+// TestCLI_Log tests the log command
+func TestCLI_Log(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	audit := repository.NewFileAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	h.cli.service.WithAuditLog(audit)
+
+	if code := h.run("log"); code != 0 || h.stdout.String() != "No changes recorded\n" {
+		t.Errorf("empty log exit code = %d, stdout = %q", code, h.stdout.String())
+	}
+
+	h.run("mark-done", "2")
+	h.run("update", "1", "Buy milk")
+
+	if code := h.run("log", "2"); code != 0 {
+		t.Fatalf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	out := h.stdout.String()
+	if !strings.Contains(out, "mark-done #2: Write report") ||
+		!strings.Contains(out, `status "in-progress" -> "done"`) ||
+		strings.Contains(out, "Buy milk") {
+		t.Errorf("log 2 output:\n%s", out)
+	}
+
+	if code := h.run("log", "--since", "2099-01-01"); code != 0 || h.stdout.String() != "No changes recorded\n" {
+		t.Errorf("log --since 2099-01-01 = %q", h.stdout.String())
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithBackups enables the backup command
+func (c *CLI) WithBackups(backups *repository.BackupManager) *CLI {
+	c.backups = backups
+	return c
+}
+
+func (c *CLI) handleBackup(_ context.Context, args []string) int {
+	if c.backups == nil {
+		c.errorf("Error: backups are not available for this store\n")
+		return 1
+	}
+
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "list":
+		backups, err := c.backups.List()
+		if err != nil {
+			return c.fail(err)
+		}
+		if len(backups) == 0 {
+			c.successf("No backups found\n")
+			return 0
+		}
+		for _, backup := range backups {
+			fmt.Fprintf(c.stdout, "%s  %s  %d bytes\n", backup.Name,
+				backup.CreatedAt.Local().Format("2006-01-02 15:04:05"), backup.Size)
+		}
+		return 0
+
+	case "create":
+		backup, err := c.backups.Create()
+		if err != nil {
+			return c.fail(err)
+		}
+		if backup == nil {
+			c.successf("Nothing to back up yet\n")
+			return 0
+		}
+		if c.quiet {
+			fmt.Fprintln(c.stdout, backup.Name)
+			return 0
+		}
+		c.successf("Backup created: %s\n", backup.Name)
+		return 0
+
+	case "restore":
+		if len(args) < 2 {
+			c.errorf("Error: Backup name is required\n")
+			c.errorf("Usage: task-cli backup restore <name>\n")
+			return 1
+		}
+		if c.service.ReadOnly() {
+			return c.fail(task.ErrReadOnly)
+		}
+		if err := c.backups.Restore(args[1]); err != nil {
+			return c.fail(err)
+		}
+		c.successf("Restored %s (previous state saved as a new backup)\n", args[1])
+		return 0
+
+	default:
+		c.errorf("Unknown backup command: %s\n", args[0])
+		c.errorf("Usage: task-cli backup [list|create|restore <name>]\n")
+		return 1
+	}
+}
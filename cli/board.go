@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// defaultBoardWidth is used when the terminal width is unknown
+const defaultBoardWidth = 100
+
+func (c *CLI) handleBoard(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("board")
+	all := fs.Bool("all", false, "include the cancelled column and snoozed tasks")
+	width := fs.Int("width", terminalWidth(), "total width in characters")
+	assignee := fs.String("assignee", "", "only tasks assigned to this user, \"me\" or \"none\"")
+	sprint := fs.String("sprint", "", "only tasks committed to this sprint, \"current\" for the running one")
+	taskContext := c.contextFlags(fs)
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	tasks, err := c.service.ListTasks(ctx, "")
+	if err != nil {
+		return c.fail(err)
+	}
+	if *assignee != "" {
+		user := c.resolveUser(*assignee)
+		tasks = slices.DeleteFunc(tasks, func(task task.Task) bool {
+			return task.Assignee != user
+		})
+	}
+
+	if *sprint != "" {
+		name := *sprint
+		if name == "current" {
+			status, err := c.service.SprintStatus(ctx, "", c.clock())
+			if err != nil {
+				return c.fail(err)
+			}
+			name = status.Name
+		}
+		tasks = slices.DeleteFunc(tasks, func(task task.Task) bool {
+			return task.Sprint != name
+		})
+	}
+
+	if !*all {
+		now := c.clock()
+		tasks = slices.DeleteFunc(tasks, func(task task.Task) bool {
+			return task.IsSnoozed(now)
+		})
+	}
+	if name := taskContext(); name != "" {
+		tasks = slices.DeleteFunc(tasks, func(task task.Task) bool {
+			return !task.InContext(name)
+		})
+	}
+
+	workflow := c.service.Workflow()
+	statuses := slices.Clone(workflow.Statuses)
+	if !*all {
+		statuses = slices.DeleteFunc(statuses, func(status task.TaskStatus) bool {
+			return status == task.StatusCancelled
+		})
+	}
+
+	if c.isAccessible() {
+		RenderBoardList(c.stdout, statuses, tasks, workflow.Limits)
+		return 0
+	}
+	RenderBoard(c.stdout, statuses, tasks, workflow.Limits, *width)
+	return 0
+}
+
+// terminalWidth reads $COLUMNS, which shells set for interactive terminals
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultBoardWidth
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Board rendering (Presentation Layer)
+
+// boardGap separates the columns of the board
+const boardGap = " | "
+
+// boardMinColumn is the narrowest a column gets, even on small terminals
+const boardMinColumn = 16
+
+// RenderBoard writes one column per status side by side, with tasks as
+// cards, fitting width when the columns can be at least boardMinColumn wide.
+// A column with a WIP limit shows its count against the limit, marked with
+// "!" when over it.
+func RenderBoard(w io.Writer, statuses []task.TaskStatus, tasks []task.Task, limits map[task.TaskStatus]int, width int) {
+	if len(statuses) == 0 {
+		return
+	}
+	columnWidth := max((width-len(boardGap)*(len(statuses)-1))/len(statuses), boardMinColumn)
+
+	columns := make([][]string, len(statuses))
+	height := 0
+	for i, status := range statuses {
+		var cards []task.Task
+		for _, task := range tasks {
+			if task.Status == status {
+				cards = append(cards, task)
+			}
+		}
+
+		lines := []string{
+			boardHeading(status, len(cards), limits),
+			strings.Repeat("-", columnWidth),
+		}
+		for j, task := range cards {
+			if j > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, boardCard(task, columnWidth)...)
+		}
+		columns[i] = lines
+		height = max(height, len(lines))
+	}
+
+	for row := range height {
+		cells := make([]string, len(columns))
+		for i, lines := range columns {
+			if row < len(lines) {
+				cells[i] = padRight(lines[row], columnWidth)
+			} else {
+				cells[i] = strings.Repeat(" ", columnWidth)
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(cells, boardGap), " "))
+	}
+}
+
+// boardHeading names a column with its task count, and its WIP limit if any
+func boardHeading(status task.TaskStatus, count int, limits map[task.TaskStatus]int) string {
+	name := strings.ToUpper(string(status))
+	limit, ok := limits[status]
+	switch {
+	case !ok:
+		return fmt.Sprintf("%s (%d)", name, count)
+	case count > limit:
+		return fmt.Sprintf("! %s (%d/%d)", name, count, limit)
+	default:
+		return fmt.Sprintf("%s (%d/%d)", name, count, limit)
+	}
+}
+
+// boardCard renders a task as wrapped lines: its ID and description, then
+// the priority, assignee and due date when set
+func boardCard(t task.Task, width int) []string {
+	lines := wrapWords(fmt.Sprintf("#%d %s", t.ID, t.Description), width)
+
+	var meta []string
+	if t.Priority != task.PriorityNone {
+		meta = append(meta, string(t.Priority))
+	}
+	if t.Assignee != "" {
+		meta = append(meta, "@"+t.Assignee)
+	}
+	if t.DueAt != nil {
+		meta = append(meta, "due "+t.DueAt.Format("Jan 2"))
+	}
+	for _, line := range wrapWords(strings.Join(meta, " "), width-2) {
+		lines = append(lines, "  "+line)
+	}
+	return lines
+}
+
+// wrapWords breaks text into lines of at most width runes, cutting words
+// longer than a line
+func wrapWords(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// RenderBoardList writes the board as a list, one heading per status with
+// its tasks below, for screen readers that cannot follow columns laid side
+// by side
+func RenderBoardList(w io.Writer, statuses []task.TaskStatus, tasks []task.Task, limits map[task.TaskStatus]int) {
+	for i, status := range statuses {
+		var cards []task.Task
+		for _, task := range tasks {
+			if task.Status == status {
+				cards = append(cards, task)
+			}
+		}
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		noun := "tasks"
+		if len(cards) == 1 {
+			noun = "task"
+		}
+		if limit, ok := limits[status]; ok && len(cards) > limit {
+			fmt.Fprintf(w, "%s: %d %s, over the limit of %d\n", spellStatus(string(status)), len(cards), noun, limit)
+		} else {
+			fmt.Fprintf(w, "%s: %d %s\n", spellStatus(string(status)), len(cards), noun)
+		}
+		for _, t := range cards {
+			details := []string{fmt.Sprintf("Task %d: %s", t.ID, t.Description)}
+			if t.Priority != task.PriorityNone {
+				details = append(details, "priority "+string(t.Priority))
+			}
+			if t.Assignee != "" {
+				details = append(details, "assigned to "+t.Assignee)
+			}
+			if t.DueAt != nil {
+				details = append(details, "due "+t.DueAt.Format("January 2"))
+			}
+			fmt.Fprintf(w, "  %s\n", strings.Join(details, ", "))
+		}
+	}
+}
+
+// spellStatus turns a status name into words a screen reader says as
+// such, "in-progress" becoming "in progress"
+func spellStatus(status string) string {
+	return strings.ReplaceAll(status, "-", " ")
+}
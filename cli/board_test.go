@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestWrapWords tests wrapping card text to a column width
+func TestWrapWords(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"#1 Buy groceries", 20, []string{"#1 Buy groceries"}},
+		{"#1 Buy groceries and milk", 12, []string{"#1 Buy", "groceries", "and milk"}},
+		{"#2 supercalifragilistic", 10, []string{"#2", "supercalif", "ragilistic"}},
+		{"", 10, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := wrapWords(tt.text, tt.width); !slices.Equal(got, tt.want) {
+				t.Errorf("wrapWords(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRenderBoard tests that columns follow the workflow and fit the width
+func TestRenderBoard(t *testing.T) {
+	var b strings.Builder
+	statuses := []task.TaskStatus{task.StatusTodo, "review", task.StatusDone}
+	tasks := []task.Task{
+		{ID: 1, Description: "Write a rather long description that wraps", Status: task.StatusTodo, Assignee: "ana"},
+		{ID: 2, Description: "Check", Status: "review"},
+	}
+	RenderBoard(&b, statuses, tasks, nil, 70)
+
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	if !strings.HasPrefix(lines[0], "TODO (1)") || !strings.Contains(lines[0], "REVIEW (1)") ||
+		!strings.Contains(lines[0], "DONE (0)") {
+		t.Errorf("header = %q, want one column per status", lines[0])
+	}
+	for _, line := range lines {
+		if len(line) > 70 {
+			t.Errorf("line %q is wider than 70", line)
+		}
+	}
+	if !strings.Contains(b.String(), "@ana") {
+		t.Errorf("board = %q, want the assignee on the card", b.String())
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// chartBarWidth is the length of the longest bar of a chart
+const chartBarWidth = 40
+
+func (c *CLI) handleChart(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("chart")
+	since := fs.String("since", "30d", "first day, as a date or a duration back from today")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	if len(args) == 0 || (args[0] != "burndown" && args[0] != "throughput") {
+		c.errorf("Error: chart type is required\n")
+		c.errorf("Usage: task-cli chart burndown|throughput [--since 30d]\n")
+		return 1
+	}
+
+	now := c.clock()
+	from, err := parseSince(*since, now)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	stats, err := c.service.DayStats(ctx, from, now)
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(stats) == 0 {
+		c.successf("No days to chart\n")
+		return 0
+	}
+
+	days := make([]time.Time, len(stats))
+	values := make([]int, len(stats))
+	total := 0
+	for i, day := range stats {
+		days[i] = day.Day
+		if args[0] == "burndown" {
+			values[i] = day.Open
+		} else {
+			values[i] = day.Completed
+		}
+		total += day.Completed
+	}
+
+	if args[0] == "burndown" {
+		fmt.Fprintf(c.stdout, "Open tasks since %s: %d -> %d\n",
+			days[0].Format("2006-01-02"), values[0], values[len(values)-1])
+	} else {
+		fmt.Fprintf(c.stdout, "Completed tasks since %s: %d (%.1f per day)\n",
+			days[0].Format("2006-01-02"), total, float64(total)/float64(len(stats)))
+	}
+	// Bars only show values to those who can see them
+	if c.isAccessible() {
+		fmt.Fprintln(c.stdout)
+		for i, value := range values {
+			fmt.Fprintf(c.stdout, "%s: %d\n", days[i].Format("2006-01-02"), value)
+		}
+		return 0
+	}
+	fmt.Fprintf(c.stdout, "%s\n\n", task.Sparkline(values))
+	task.RenderBarChart(c.stdout, days, values, chartBarWidth)
+	return 0
+}
+
+// parseSince reads a start time given as a duration back from now, such as
+// "30d", or as a date
+func parseSince(input string, now time.Time) (time.Time, error) {
+	if d, err := task.ParseDuration(input); err == nil {
+		return now.Add(-d), nil
+	}
+	return task.ParseWhen(input, now)
+}
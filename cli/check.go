@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+func (c *CLI) handleCheck(ctx context.Context, args []string) int {
+	if len(args) < 3 || (args[0] != "add" && args[0] != "toggle") {
+		c.errorf("Error: a subcommand, ID and item are required\n")
+		c.errorf("Usage: task-cli check add <id> \"Step\" | toggle <id> <n>\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[1])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if args[0] == "add" {
+		n, err := c.service.AddCheckItem(ctx, id, strings.Join(args[2:], " "))
+		if err != nil {
+			return c.fail(err)
+		}
+		c.successf("Checklist item %d added to task %d\n", n, id)
+		return 0
+	}
+
+	n, err := strconv.Atoi(args[2])
+	if err != nil {
+		c.errorf("Error: invalid item number %q\n", args[2])
+		return 1
+	}
+	item, err := c.service.ToggleCheckItem(ctx, id, n)
+	if err != nil {
+		return c.fail(err)
+	}
+	if item.Done {
+		c.successf("Checked item %d: %s\n", n, item.Text)
+	} else {
+		c.successf("Unchecked item %d: %s\n", n, item.Text)
+	}
+	return 0
+}
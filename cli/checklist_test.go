@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_Check tests adding and toggling checklist items
+func TestCLI_Check(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	for _, step := range []string{"write tests", "update docs"} {
+		if code := h.run("check", "add", "2", step); code != 0 {
+			t.Fatalf("check add exit code = %d, stderr: %s", code, h.stderr)
+		}
+	}
+	if h.stdout.String() != "Checklist item 2 added to task 2\n" {
+		t.Errorf("output = %q", h.stdout)
+	}
+	if code := h.run("check", "toggle", "2", "1"); code != 0 || h.stdout.String() != "Checked item 1: write tests\n" {
+		t.Errorf("check toggle = %d, %q, stderr: %s", code, h.stdout, h.stderr)
+	}
+	if code := h.run("check", "toggle", "2", "3"); code != exitNotFound {
+		t.Errorf("unknown item exit code = %d, want %d", code, exitNotFound)
+	}
+	if code := h.run("check", "add", "2", " "); code != exitInvalid {
+		t.Errorf("empty item exit code = %d, want %d", code, exitInvalid)
+	}
+
+	if code := h.run("show", "2"); code != 0 {
+		t.Fatal(h.stderr)
+	}
+	want := "Checklist: 1/2\n  1. [x] write tests\n  2. [ ] update docs\n"
+	if !strings.Contains(h.stdout.String(), want) {
+		t.Errorf("show missing %q:\n%s", want, h.stdout)
+	}
+}
+
+// TestCLI_CheckGatesDone tests that open items keep a task from being done
+// when the workflow requires it
+func TestCLI_CheckGatesDone(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	workflow := task.DefaultWorkflow()
+	workflow.RequireChecklist = true
+	h.cli.service.WithWorkflow(workflow)
+	h.run("check", "add", "2", "write tests")
+
+	if code := h.run("mark-done", "2"); code != exitRefused {
+		t.Errorf("open checklist exit code = %d, want %d", code, exitRefused)
+	}
+	want := "Error: Checklist has open items: task 2 has 1 of 1 items left [CHECKLIST_OPEN]\n" +
+		"Hint: check the remaining items with 'task-cli check toggle', or add --force\n"
+	if h.stderr.String() != want {
+		t.Errorf("stderr = %q, want %q", h.stderr, want)
+	}
+	if code := h.run("mark-done", "--force", "2"); code != 0 {
+		t.Errorf("forced exit code = %d, stderr: %s", code, h.stderr)
+	}
+
+	h.run("move", "2", "in-progress")
+	h.run("check", "toggle", "2", "1")
+	if code := h.run("mark-done", "2"); code != 0 {
+		t.Errorf("checked list exit code = %d, stderr: %s", code, h.stderr)
+	}
+}
@@ -0,0 +1,1024 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+// CLI Interface (Presentation Layer)
+type CLI struct {
+	service     *service.TaskService
+	stdout      io.Writer
+	stderr      io.Writer
+	stdin       io.Reader
+	clock       task.Clock
+	config      repository.Config
+	configFile  string
+	notifier    service.Notifier
+	opener      service.Opener
+	mailer      service.Mailer
+	prompter    Prompter
+	tr          *task.Translator
+	backups     *repository.BackupManager
+	syncDir     string
+	schedule    string
+	contextFile string
+	socketFile  string
+	dataFile    string
+	store       string
+	work        repository.UnitOfWork
+	quiet       bool
+	relative    bool
+	accessible  bool
+	timeout     time.Duration
+	noDaemon    bool
+	aliasDepth  int
+	viaSocket   bool
+	// list is the list given with --list, empty for the default one
+	list string
+}
+
+func NewCLI(service *service.TaskService, stdout, stderr io.Writer, clock task.Clock) *CLI {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &CLI{
+		service: service,
+		stdout:  stdout,
+		stderr:  stderr,
+		clock:   clock,
+		config:  repository.DefaultConfig(),
+	}
+}
+
+// WithConfig applies user preferences loaded from the config file
+func (c *CLI) WithConfig(config repository.Config) *CLI {
+	c.config = config
+	return c
+}
+
+// WithStdin sets where commands read "-" input from
+func (c *CLI) WithStdin(stdin io.Reader) *CLI {
+	c.stdin = stdin
+	return c
+}
+
+// WithMailer sets how the digest command sends email
+func (c *CLI) WithMailer(mailer service.Mailer) *CLI {
+	c.mailer = mailer
+	return c
+}
+
+// WithNotifier sets how the notify command delivers notifications
+func (c *CLI) WithNotifier(notifier service.Notifier) *CLI {
+	c.notifier = notifier
+	return c
+}
+
+// WithOpener sets how the open command launches attachments
+func (c *CLI) WithOpener(opener service.Opener) *CLI {
+	c.opener = opener
+	return c
+}
+
+// WithPrompter sets who confirms destructive commands; without one they
+// run without asking
+func (c *CLI) WithPrompter(prompter Prompter) *CLI {
+	c.prompter = prompter
+	return c
+}
+
+// WithTranslator prints messages in the language of t
+func (c *CLI) WithTranslator(t *task.Translator) *CLI {
+	c.tr = t
+	return c
+}
+
+// WithUnitOfWork batches the loads and saves of each command through work
+func (c *CLI) WithUnitOfWork(work repository.UnitOfWork) *CLI {
+	c.work = work
+	return c
+}
+
+// Run dispatches the command line and returns the process exit code
+func (c *CLI) Run(ctx context.Context, args []string) int {
+	// Like --file, --list may follow the command name
+	list, args, err := extractFlag(args, "a list name", "--list")
+	if err == nil {
+		c.list = list
+		args, err = c.parseGlobalFlags(args)
+	}
+	if err != nil {
+		c.printError(err)
+		c.printUsageTo(c.stderr)
+		return 1
+	}
+
+	if len(args) < 2 {
+		c.printUsageTo(c.stderr)
+		return 1
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	command := args[1]
+	if code, ok := c.proxy(ctx, args[1:]); ok {
+		return code
+	}
+	if c.viaSocket && (longRunningCommands[c.aliasedCommand(command)] || command == "client") {
+		c.errorf("Error: %s cannot run through the daemon\n", command)
+		return 1
+	}
+	leaveList, err := c.enterList(command)
+	if err != nil {
+		return c.fail(err)
+	}
+	defer leaveList()
+	if c.work == nil || longRunningCommands[c.aliasedCommand(command)] {
+		return c.dispatch(ctx, command, args)
+	}
+
+	// Other commands read the store once and write it at most once
+	c.work.Begin()
+	code := c.dispatch(ctx, command, args)
+	if err := c.work.Commit(ctx); err != nil {
+		// The tasks were saved, but not what depends on them, such as hooks
+		var after *repository.AfterCommitError
+		if errors.As(err, &after) {
+			return c.fail(after.Err)
+		}
+		return c.fail(repository.StorageError("failed to save tasks", err))
+	}
+	return code
+}
+
+// longRunningCommands keep running until interrupted, or for mcp until the
+// assistant closes stdin; they work outside a unit of work so that each
+// change is written at once and changes made by other processes are seen
+var longRunningCommands = map[string]bool{
+	"watch":    true,
+	"daemon":   true,
+	"serve":    true,
+	"notify":   true,
+	"pomodoro": true,
+	"mcp":      true,
+}
+
+// dispatch runs one command and returns the process exit code
+func (c *CLI) dispatch(ctx context.Context, command string, args []string) int {
+	switch command {
+	case "add":
+		return c.handleAdd(ctx, args[2:])
+	case "update":
+		return c.handleUpdate(ctx, args[2:])
+	case "set":
+		return c.handleSet(ctx, args[2:])
+	case "edit":
+		return c.handleEdit(ctx, args[2:])
+	case "delete":
+		return c.handleDelete(ctx, args[2:])
+	case "mark-in-progress":
+		return c.handleMarkInProgress(ctx, args[2:])
+	case "mark-done":
+		return c.handleMarkDone(ctx, args[2:])
+	case "list":
+		return c.handleList(ctx, args[2:])
+	case "show":
+		return c.handleShow(ctx, args[2:])
+	case "list-of-lists":
+		return c.handleListOfLists(ctx, args[2:])
+	case "next":
+		return c.handleNext(ctx, args[2:])
+	case "in":
+		return c.handleIn(ctx, args[2:])
+	case "triage":
+		return c.handleTriage(ctx, args[2:])
+	case "context":
+		return c.handleContext(ctx, args[2:])
+	case "snooze":
+		return c.handleSnooze(ctx, args[2:])
+	case "check":
+		return c.handleCheck(ctx, args[2:])
+	case "attach":
+		return c.handleAttach(ctx, args[2:])
+	case "open":
+		return c.handleOpen(ctx, args[2:])
+	case "tag":
+		return c.handleTag(ctx, args[2:])
+	case "link":
+		return c.handleLink(ctx, args[2:])
+	case "unlink":
+		return c.handleUnlink(ctx, args[2:])
+	case "project":
+		return c.handleProject(ctx, args[2:])
+	case "milestone":
+		return c.handleMilestone(ctx, args[2:])
+	case "sprint":
+		return c.handleSprint(ctx, args[2:])
+	case "board":
+		return c.handleBoard(ctx, args[2:])
+	case "chart":
+		return c.handleChart(ctx, args[2:])
+	case "move":
+		return c.handleMove(ctx, args[2:])
+	case "cancel":
+		return c.handleCancel(ctx, args[2:])
+	case "assign":
+		return c.handleAssign(ctx, args[2:])
+	case "due":
+		return c.handleDue(ctx, args[2:])
+	case "pomodoro":
+		return c.handlePomodoro(ctx, args[2:])
+	case "estimate":
+		return c.handleEstimate(ctx, args[2:])
+	case "estimates":
+		return c.handleEstimates(ctx, args[2:])
+	case "notify":
+		return c.handleNotify(ctx, args[2:])
+	case "remind":
+		return c.handleRemind(ctx, args[2:])
+	case "daemon":
+		return c.handleDaemon(ctx, args[2:])
+	case "tick":
+		return c.handleTick(ctx, args[2:])
+	case "habit":
+		return c.handleHabit(ctx, args[2:])
+	case "digest":
+		return c.handleDigest(ctx, args[2:])
+	case "backup":
+		return c.handleBackup(ctx, args[2:])
+	case "import":
+		return c.handleImport(ctx, args[2:])
+	case "push":
+		return c.handlePush(ctx, args[2:])
+	case "export":
+		return c.handleExport(ctx, args[2:])
+	case "log":
+		return c.handleLog(ctx, args[2:])
+	case "history":
+		return c.handleHistory(ctx, args[2:])
+	case "sync":
+		return c.handleSync(ctx, args[2:])
+	case "doctor":
+		return c.handleDoctor(ctx, args[2:])
+	case "dedupe":
+		return c.handleDedupe(ctx, args[2:])
+	case "split":
+		return c.handleSplit(ctx, args[2:])
+	case "snapshot":
+		return c.handleSnapshot(ctx, args[2:])
+	case "diff":
+		return c.handleDiff(ctx, args[2:])
+	case "storage":
+		return c.handleStorage(ctx, args[2:])
+	case "watch":
+		return c.handleWatch(ctx, args[2:])
+	case "api-spec":
+		return c.handleAPISpec(ctx, args[2:])
+	case "mcp":
+		return c.handleMCP(ctx, args[2:])
+	case "serve":
+		return c.handleServe(ctx, args[2:])
+	case "view":
+		return c.handleView(ctx, args[2:])
+	case "summary":
+		return c.handleSummary(ctx, args[2:])
+	case "client":
+		return c.handleClient(ctx, args[2:])
+	case "alias":
+		return c.handleAlias(ctx, args[2:])
+	case "plugins":
+		return c.handlePlugins(ctx, args[2:])
+	case "help", "--help", "-h":
+		c.printUsage()
+		return 0
+	default:
+		if code, ok := c.runAlias(ctx, args); ok {
+			return code
+		}
+		if code, ok := c.runPlugin(ctx, args); ok {
+			return code
+		}
+		c.errorf("Unknown command: %s\n", command)
+		c.printUsageTo(c.stderr)
+		return 1
+	}
+}
+
+// ExtractDataFile takes --file (or --data-file) out of the arguments and
+// returns its value. The store is built before the CLI runs, so unlike the
+// other global flags it is looked for anywhere up to "--", which also makes
+// it usable after the command name.
+func ExtractDataFile(args []string) (string, []string, error) {
+	return extractFlag(args, "a path", "--file", "--data-file")
+}
+
+// extractFlag takes every occurrence of a valued flag out of the arguments,
+// up to "--", and returns the last value. what describes the value in
+// errors.
+func extractFlag(args []string, what string, names ...string) (string, []string, error) {
+	var found string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		if !slices.Contains(names, name) {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("flag %s requires %s", name, what)
+			}
+			i++
+			value = args[i]
+		}
+		if value == "" {
+			return "", nil, fmt.Errorf("flag %s requires %s", name, what)
+		}
+		found = value
+	}
+	return found, rest, nil
+}
+
+// parseGlobalFlags consumes the flags placed before the command name
+func (c *CLI) parseGlobalFlags(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return args, nil
+	}
+
+	rest := []string{args[0]}
+	i := 1
+	for ; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			i++
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" || arg == "--help" || arg == "-h" {
+			break
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "-q", "--quiet":
+			c.quiet = true
+		case "--relative":
+			c.relative = true
+		case "--accessible":
+			c.accessible = true
+		case "--read-only":
+			if c.viaSocket {
+				return nil, fmt.Errorf("%s cannot be sent to the daemon", name)
+			}
+			c.service.WithReadOnly(true)
+		case "--no-daemon":
+			c.noDaemon = true
+		case "--timeout":
+			if !hasValue {
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("flag %s requires a duration", name)
+				}
+				i++
+				value = args[i]
+			}
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("invalid timeout %q", value)
+			}
+			c.timeout = timeout
+		default:
+			return nil, fmt.Errorf("unknown flag: %s", arg)
+		}
+	}
+
+	return append(rest, args[i:]...), nil
+}
+
+func (c *CLI) handleAdd(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("add")
+	due := fs.String("due", "", "deadline, e.g. \"tomorrow 5pm\" or 2024-06-01")
+	eachLine := fs.Bool("each-line", false, "read stdin and add one task per line")
+	fromFile := fs.String("from-file", "", "add one task per line of a todo.txt style file")
+	assignee := fs.String("assignee", "", "who should do the task, \"me\" for yourself")
+	estimate := fs.String("estimate", "", "how long the task should take, e.g. 2h, 1d or a size S, M or L")
+	project := fs.String("project", "", "file the task under this project")
+	milestone := fs.String("milestone", "", "plan the task for this milestone")
+	taskContext := fs.String("context", "", "where the task can be done, such as @home")
+	force := fs.Bool("force", false, "add the task even when it looks like a duplicate of an open one")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	fromStdin := *eachLine || (len(args) > 0 && args[0] == "-") ||
+		(len(args) == 0 && *fromFile == "" && c.stdinPiped())
+	if len(args) == 0 && !fromStdin && *fromFile == "" {
+		c.errorf("Error: Description is required\n")
+		c.errorf("Usage: task-cli add \"Task description\" [--due <when>] [--force]\n")
+		return 1
+	}
+
+	var opts []task.TaskOption
+	if *due != "" {
+		deadline, err := task.ParseDeadline(*due, c.clock())
+		if err != nil {
+			return c.fail(err)
+		}
+		opts = append(opts, task.WithDueDate(deadline))
+	}
+	if *assignee != "" {
+		opts = append(opts, task.WithAssignee(c.resolveUser(*assignee)))
+	}
+	if *project != "" {
+		opts = append(opts, task.WithProject(*project))
+	}
+	if *milestone != "" {
+		opts = append(opts, task.WithMilestone(*milestone))
+	}
+	if *taskContext != "" {
+		opts = append(opts, task.WithTags(task.ContextTag(*taskContext)))
+	}
+	if *estimate != "" {
+		d, err := task.ParseEffort(*estimate)
+		if err != nil {
+			return c.fail(err)
+		}
+		opts = append(opts, task.WithEstimate(d))
+	}
+
+	var drafts []task.TaskDraft
+	skipped := 0
+	switch {
+	case *fromFile != "":
+		drafts, skipped, err = readTaskFile(*fromFile)
+	case fromStdin:
+		drafts, err = c.readDescriptions(*eachLine)
+	default:
+		drafts = []task.TaskDraft{{Description: args[0]}}
+	}
+	if err != nil {
+		return c.fail(err)
+	}
+	if !fromStdin && *fromFile == "" && !*force {
+		if code, done := c.checkSimilar(ctx, drafts[0].Description); done {
+			return code
+		}
+	}
+
+	tasks, err := c.service.AddTasks(ctx, drafts, opts...)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if *fromFile != "" && !c.quiet {
+		c.successf("Added %d tasks from %s, skipped %d blank or comment lines\n", len(tasks), *fromFile, skipped)
+		return 0
+	}
+
+	for _, task := range tasks {
+		if c.quiet {
+			fmt.Fprintln(c.stdout, task.ID)
+			continue
+		}
+		c.successf("Task added successfully (ID: %d)\n", task.ID)
+	}
+	return 0
+}
+
+// stdinPiped reports whether stdin carries piped input rather than a terminal
+func (c *CLI) stdinPiped() bool {
+	if c.stdin == nil {
+		return false
+	}
+	f, ok := c.stdin.(*os.File)
+	if !ok {
+		return true
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// readDescriptions reads task descriptions from stdin: one per non-blank
+// line with eachLine, otherwise all lines joined into one description
+func (c *CLI) readDescriptions(eachLine bool) ([]task.TaskDraft, error) {
+	if c.stdin == nil {
+		return nil, task.ErrEmptyDescription
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(c.stdin)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+
+	if len(lines) == 0 {
+		return nil, task.ErrEmptyDescription
+	}
+	if !eachLine {
+		return []task.TaskDraft{{Description: strings.Join(lines, " ")}}, nil
+	}
+
+	drafts := make([]task.TaskDraft, len(lines))
+	for i, line := range lines {
+		drafts[i] = task.TaskDraft{Description: line}
+	}
+	return drafts, nil
+}
+
+// readTaskFile parses one task per line of a todo.txt style file, skipping
+// blank lines and lines starting with "#"
+func readTaskFile(path string) ([]task.TaskDraft, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var drafts []task.TaskDraft
+	skipped, lineNo := 0, 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			skipped++
+			continue
+		}
+
+		draft := task.ParseTaskLine(line)
+		if draft.Description == "" {
+			return nil, 0, fmt.Errorf("%s:%d: %w", path, lineNo, task.ErrEmptyDescription)
+		}
+		drafts = append(drafts, draft)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return drafts, skipped, nil
+}
+
+func (c *CLI) handleUpdate(ctx context.Context, args []string) int {
+	if len(args) < 2 {
+		c.errorf("Error: ID and description are required\n")
+		c.errorf("Usage: task-cli update <id> \"New description\"\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	description := args[1]
+	err = c.service.UpdateTask(ctx, id, description)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	c.successf("Task updated successfully\n")
+	return 0
+}
+
+func (c *CLI) handleMarkInProgress(ctx context.Context, args []string) int {
+	return c.markStatus(ctx, "mark-in-progress", args, task.StatusInProgress, "Task marked as in progress\n")
+}
+
+func (c *CLI) handleMarkDone(ctx context.Context, args []string) int {
+	return c.markStatus(ctx, "mark-done", args, task.StatusDone, "Task marked as done\n")
+}
+
+// markStatus moves the task named by args to status, honoring --force
+func (c *CLI) markStatus(ctx context.Context, command string, args []string, status task.TaskStatus, success string) int {
+	fs := c.newFlagSet(command)
+	force := fs.Bool("force", false, "ignore the workflow's transition rules and WIP limits")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	if len(args) == 0 {
+		if args, err = c.pickArgs(ctx, false); err != nil {
+			c.printError(err)
+			c.errorf("Usage: task-cli %s <id> [--force]\n", command)
+			return 1
+		}
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if err := c.moveTask(ctx, id, status, *force); err != nil {
+		return c.fail(err)
+	}
+
+	c.successf(success)
+	return 0
+}
+
+// moveTask changes the status of a task, warning first when configured to
+// and the task skips in-progress on its way to done, and after a forced
+// move that exceeds a WIP limit
+func (c *CLI) moveTask(ctx context.Context, id int, status task.TaskStatus, force bool) error {
+	if c.config.Workflow.WarnOnSkip && status == task.StatusDone && !c.quiet {
+		if t, err := c.service.GetTask(ctx, id); err == nil && t.Status == task.StatusTodo {
+			c.errorf("Warning: task %d was never in progress\n", id)
+		}
+	}
+
+	switch {
+	case force:
+		if err := c.service.ForceMoveTask(ctx, id, status); err != nil {
+			return err
+		}
+		c.warnWIP(ctx, status)
+		return nil
+	case status == task.StatusDone:
+		return c.service.MarkTaskDone(ctx, id)
+	case status == task.StatusInProgress:
+		return c.service.MarkTaskInProgress(ctx, id)
+	default:
+		return c.service.MoveTask(ctx, id, status)
+	}
+}
+
+func (c *CLI) handleList(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("list")
+	filters := c.listFlags(fs)
+	viewName := fs.String("view", "", "start from a saved view, the other flags override it")
+	offset := fs.Int("offset", 0, "skip this many matching tasks first")
+	count := fs.Bool("count", false, "print how many tasks match, by status, instead of the tasks")
+	format := fs.String("format", "", "print tasks as text, table, tsv, csv, quickfix or a format named in the config file")
+	templateSpec := fs.String("template", "", "print each task through this template or named format")
+	columnSpec := fs.String("columns", "", "comma-separated columns for --format table, tsv or csv")
+	noHeader := fs.Bool("no-header", false, "leave out the header row of --format table, tsv or csv")
+	showAge := fs.Bool("show-age", false, "show how old each task is and how long it has been in its status")
+	taskContext := c.contextFlags(fs)
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		filters.Status = args[0]
+	}
+
+	view := *filters
+	if *viewName != "" {
+		saved, err := c.service.GetView(ctx, *viewName)
+		if err != nil {
+			return c.fail(err)
+		}
+		view = saved.Override(*filters)
+	}
+
+	status := view.Status
+	// Validate status
+	if status != "" && !c.service.Workflow().Has(task.TaskStatus(status)) {
+		c.errorf(
+			"Error: Invalid status '%s'. Valid options: %s\n",
+			status, c.statusOptions(),
+		)
+		return 1
+	}
+
+	if view.Limit < 0 || *offset < 0 {
+		c.errorf("Error: --limit and --offset cannot be negative\n")
+		return 1
+	}
+
+	match, err := c.viewMatch(view)
+	if err != nil {
+		c.queryErrorf(err)
+		return 1
+	}
+	match = inContext(match, taskContext())
+	// Choosing columns without a format asks for a table
+	if *columnSpec != "" && *format == "" && *templateSpec == "" {
+		*format = "table"
+	}
+	spec := *templateSpec
+	switch {
+	case *format != "" && spec != "":
+		c.errorf("Error: --format and --template cannot be combined\n")
+		return 1
+	case *format == "" && spec == "":
+		spec = c.config.Display.Template
+	case *format != "" && *format != "text" && *format != "quickfix" && !tableFormats[*format]:
+		spec = *format
+	}
+	tmpl, err := c.taskTemplate(spec)
+	if err != nil {
+		return c.fail(err)
+	}
+	var columns []Column
+	if tableFormats[*format] {
+		if *columnSpec == "" {
+			*columnSpec = defaultColumns
+		}
+		if columns, err = ParseColumns(*columnSpec); err != nil {
+			return c.fail(err)
+		}
+	} else if *columnSpec != "" {
+		c.errorf("Error: --columns needs --format table, tsv or csv\n")
+		return 1
+	}
+	if *count {
+		return c.printCounts(ctx, match)
+	}
+
+	page := repository.Page{Offset: *offset, Limit: view.Limit}
+	var tasks []task.Task
+	if view.Sort == "" {
+		tasks, err = c.service.ListPage(ctx, page, match)
+	} else {
+		// Sorting needs every match before the page can be cut
+		tasks, err = c.service.ListPage(ctx, repository.Page{}, match)
+		if err == nil {
+			err = task.SortTasks(tasks, view.Sort)
+		}
+		tasks = repository.PageTasks(tasks, page, nil)
+	}
+	if err != nil {
+		return c.fail(err)
+	}
+
+	switch {
+	case *format == "quickfix":
+		return c.printQuickfix(ctx, tasks)
+	case columns != nil:
+		write := func() error { return WriteTable(c.stdout, *format, columns, tasks, !*noHeader) }
+		// Columns laid side by side are hard to follow with a screen reader
+		if *format == "table" && c.isAccessible() {
+			write = func() error { return WriteRecords(c.stdout, columns, tasks) }
+		}
+		if err := write(); err != nil {
+			return c.fail(err)
+		}
+		return 0
+	case tmpl != nil:
+		if err := task.RenderTasks(c.stdout, tmpl, tasks); err != nil {
+			return c.fail(err)
+		}
+		return 0
+	}
+
+	if len(tasks) == 0 {
+		if status == "" {
+			c.successf("No tasks found\n")
+		} else {
+			c.successf("No tasks with status '%s' found\n", status)
+		}
+		return 0
+	}
+
+	c.printTasks(tasks, *showAge)
+	if status == "" {
+		c.warnWIP(ctx)
+	} else {
+		c.warnWIP(ctx, task.TaskStatus(status))
+	}
+	return 0
+}
+
+// warnWIP warns about the statuses holding more tasks than their WIP limit,
+// among those given or all of them
+func (c *CLI) warnWIP(ctx context.Context, statuses ...task.TaskStatus) {
+	if c.quiet {
+		return
+	}
+	loads, err := c.service.WIPLoads(ctx)
+	if err != nil {
+		return
+	}
+	for _, load := range loads {
+		if load.Over() && (len(statuses) == 0 || slices.Contains(statuses, load.Status)) {
+			c.errorf("Warning: %s holds %d tasks, over its WIP limit of %d\n", load.Status, load.Count, load.Limit)
+		}
+	}
+}
+
+// printQuickfix prints tasks as quickfix entries pointing into the data file
+func (c *CLI) printQuickfix(ctx context.Context, tasks []task.Task) int {
+	file, lines, err := c.service.LocateTasks(ctx)
+	if err != nil {
+		return c.fail(err)
+	}
+	for _, task := range tasks {
+		fmt.Fprintln(c.stdout, repository.QuickfixLine(task, file, lines[task.ID]))
+	}
+	return 0
+}
+
+func (c *CLI) printTasks(tasks []task.Task, showAge bool) {
+	if c.isAccessible() {
+		c.printTaskRecords(tasks, showAge)
+		return
+	}
+	fmt.Fprintln(c.stdout, c.tr.Text("Tasks:"))
+	fmt.Fprintln(c.stdout, "------")
+	for _, t := range tasks {
+		statusDisplay := c.statusLabel(t.Status)
+		fmt.Fprint(c.stdout, c.tr.Sprintf("ID: %d | Status: %s | Description: %s\n",
+			t.ID, statusDisplay, t.Description))
+		fmt.Fprint(c.stdout, c.tr.Sprintf("Created: %s | Updated: %s",
+			c.formatTime(t.CreatedAt, "2006-01-02 15:04:05"),
+			c.formatTime(t.UpdatedAt, "2006-01-02 15:04:05")))
+		if t.DueAt != nil {
+			fmt.Fprint(c.stdout, c.tr.Sprintf(" | Due: %s", c.formatTime(*t.DueAt, "2006-01-02 15:04")))
+		}
+		if t.Priority != task.PriorityNone {
+			fmt.Fprint(c.stdout, c.tr.Sprintf(" | Priority: %s", t.Priority))
+		}
+		if len(t.Tags) > 0 {
+			fmt.Fprint(c.stdout, c.tr.Sprintf(" | Tags: %s", strings.Join(t.Tags, ", ")))
+		}
+		if t.Project != "" {
+			fmt.Fprint(c.stdout, c.tr.Sprintf(" | Project: %s", t.Project))
+		}
+		if t.Milestone != "" {
+			fmt.Fprint(c.stdout, c.tr.Sprintf(" | Milestone: %s", t.Milestone))
+		}
+		if t.Assignee != "" {
+			fmt.Fprint(c.stdout, c.tr.Sprintf(" | Assignee: %s", t.Assignee))
+		}
+		if showAge {
+			age, inStatus := c.taskAge(t)
+			fmt.Fprint(c.stdout, c.tr.Sprintf(" | Age: %s | %s for %s", age, c.tr.Status(t.Status), inStatus))
+		}
+		fmt.Fprintln(c.stdout)
+		fmt.Fprintln(c.stdout, "------")
+	}
+}
+
+// taskAge describes how old a task is and how long it has been in its
+// status
+func (c *CLI) taskAge(t task.Task) (string, string) {
+	now := c.clock()
+	return task.HumanizeDuration(max(now.Sub(t.CreatedAt), 0)),
+		task.HumanizeDuration(max(now.Sub(t.StatusSince()), 0))
+}
+
+// formatTime renders a timestamp with layout, or relative to the clock
+// with --relative or display.relative in the config file
+func (c *CLI) formatTime(t time.Time, layout string) string {
+	if c.relative || c.config.Display.Relative {
+		return task.HumanizeTime(t, c.clock())
+	}
+	return t.Format(layout)
+}
+
+func (c *CLI) printUsage() {
+	c.printUsageTo(c.stdout)
+}
+
+func (c *CLI) printUsageTo(w io.Writer) {
+	fmt.Fprintln(w, c.tr.Text("Task Tracker CLI"))
+	fmt.Fprintln(w, c.tr.Text("Usage:"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli [--quiet] [--relative] [--accessible] [--read-only] [--no-daemon] [--timeout <duration>] [--file <path>] [--list <name>] <command> [arguments]"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, c.tr.Text("Commands:"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli add \"Task description\" [--due <when>] [--assignee <user>] [--force]"))
+	fmt.Fprintln(w, c.tr.Text("               [--estimate 2h] [--project <name>] [--milestone <name>] [--context @<name>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli add - | --each-line [--due <when>]   (read from stdin)"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli add --from-file <file> [--due <when>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli in \"Thought to sort out later\""))
+	fmt.Fprintln(w, c.tr.Text("  task-cli triage"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli update <id> \"New description\""))
+	fmt.Fprintln(w, c.tr.Text("  task-cli set <id> field=value... (description, priority, due, tags, tags+=, tags-=, assignee, estimate)"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli edit --where <filter> [--set field=value]... [--add-tag <tag>]..."))
+	fmt.Fprintln(w, c.tr.Text("               [--remove-tag <tag>]... [--dry-run]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli delete <id|from-to>... [--yes]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli mark-in-progress <id> [--force]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli mark-done <id> [--force]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli move <id> <status> [--force]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli move <id> --to <list>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli list-of-lists"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli cancel <id> [--reason \"Why\"]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli assign <id> <user|me|none>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli list [status] [--all] [--assignee <user|me|none>] [--created-by <user>]"))
+	fmt.Fprintln(w, c.tr.Text("               [--project <name>] [--milestone <name>] [--sprint <name>] [--tag <tag>]..."))
+	fmt.Fprintln(w, c.tr.Text("               [--where <filter>] [--sort id|created|updated|due|priority] [--view <name>]"))
+	fmt.Fprintln(w, c.tr.Text("               [--limit <n>] [--offset <n>] [--count] [--stale <age>] [--show-age] [--max-effort <effort>]"))
+	fmt.Fprintln(w, c.tr.Text("               [--format text|table|tsv|csv|quickfix|<name>] [--columns id,desc,...]"))
+	fmt.Fprintln(w, c.tr.Text("               [--no-header] [--template <template>] [--context @<name> | --all-contexts]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli show <id> [--template <template>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli check add <id> \"Step\""))
+	fmt.Fprintln(w, c.tr.Text("  task-cli check toggle <id> <n>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli attach <id> <url|path> [--label <label>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli open <id> [<n>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli tag list | rename <tag> <new-tag> | merge <tag>... --into <tag>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli tag prune [--dry-run]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli link <id> duplicates|relates-to <id>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli unlink <id> <id>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli next [--explain] [--quick] [--tag <tag>] [--project <name>] [--assignee <user>] [--where <expr>]"))
+	fmt.Fprintln(w, c.tr.Text("               [--context @<name> | --all-contexts]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli summary"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli view save <name> [status] [list filters]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli view list"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli view delete <name>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli project add|close <name>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli project list [--all]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli project rename <name> <new-name>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli project set <id> <name|none>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli milestone create <name> --due <when>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli milestone status"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli milestone set <id> <name|none>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli sprint create <name> --start <when> --end <when> [--capacity 40h]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli sprint add <id>... [--sprint <name>] | remove <id>..."))
+	fmt.Fprintln(w, c.tr.Text("  task-cli sprint list | status [name] | close [name] | rollover"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli board [--all] [--assignee <user>] [--sprint <name|current>] [--width <n>] [--context @<name> | --all-contexts]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli context [show] | set @<name> | clear | list"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli chart burndown|throughput [--since 30d]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli due <id> <when|none>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli estimate <id> <duration|S|M|L|none>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli pomodoro <id> [--work 25m] [--break 5m] [--rounds 1]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli estimates [--since 90d] [--weekly]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli notify [--daemon] [--due-within 1h] [--stale-after 3d]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli remind <id> --at <when|none>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli snooze <id> [until] <when|none>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli daemon [--once] [--socket[=<path>]]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli client [--socket <path>] <command> [arguments]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli tick"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli habit [list] [--weeks <n>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli digest [--daily] [--html] [--send] [--skip-empty]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli backup [list|create|restore <name>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli import github --repo owner/name [--label <label>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli import todoist [--token <token> | --csv <file>] [--dry-run]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli import ticktick --csv <file> [--dry-run]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli import jira --jql <query> [--keep-key] [--dry-run]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli import --format json|taskwarrior [<file>|-] [--dry-run]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli export [--format json|taskwarrior|todotxt] [--output <file>] [list filters]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli push github --repo owner/name [--label <label>] [--dry-run] [ids...]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli log [<id>] [--since <when>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli history <id>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli sync [<path|url>] [--prefer local|remote|newer] [--dry-run]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli doctor [--repair] [--dry-run]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli dedupe [--tags] [--keep progressed|oldest] [--dry-run]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli split <list filters> -o <file> [--move] [--renumber] [--dry-run]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli snapshot create [name] | list"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli diff <snapshot> [<other snapshot>] [--json]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli storage test [<location>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli watch [--json]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli serve [--grpc <addr>] [--http <addr>]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli api-spec"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli mcp"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli alias list"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli alias set <name> <command> [arguments]"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli alias unset <name>"))
+	fmt.Fprintln(w, c.tr.Text("  task-cli plugins list"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, c.tr.Text("Status options for list command:"))
+	fmt.Fprintln(w, c.tr.Text("  todo, in-progress, done, cancelled (hidden from list without --all)"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, c.tr.Text("Global flags:"))
+	fmt.Fprintln(w, c.tr.Text("  -q, --quiet           Print only IDs on success, errors still go to stderr"))
+	fmt.Fprintln(w, c.tr.Text("  --accessible          Spell out output as \"field: value\" lines, without tables or colors"))
+	fmt.Fprintln(w, c.tr.Text("  --read-only           Refuse every change, for viewers of a shared task file"))
+	fmt.Fprintln(w, c.tr.Text("  --no-daemon           Read the task file directly even when a daemon is running"))
+	fmt.Fprintln(w, c.tr.Text("  --timeout <duration>  Abort the command after the given time (e.g. 5s)"))
+	fmt.Fprintln(w, c.tr.Text("  --file <path>         Use another task file, also $TASK_CLI_FILE (.txt for todo.txt)"))
+	fmt.Fprintln(w, c.tr.Text("  --list <name>         Work on another list of the task file than the default one"))
+}
+
+// successf prints confirmation messages, suppressed in quiet mode
+func (c *CLI) successf(format string, a ...any) {
+	if c.quiet {
+		return
+	}
+	fmt.Fprint(c.stdout, c.tr.Sprintf(format, a...))
+}
+
+// errorf prints error messages to stderr
+func (c *CLI) errorf(format string, a ...any) {
+	fmt.Fprint(c.stderr, c.tr.Sprintf(format, a...))
+}
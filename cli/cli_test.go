@@ -0,0 +1,575 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata/")
+
+// cliHarness wires a CLI to a mock repository and captured output streams
+type cliHarness struct {
+	cli    *CLI
+	repo   *tasktest.MockTaskRepository
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+}
+
+// newCLIHarness creates a CLI with a fixed clock over the given tasks
+func newCLIHarness(t *testing.T, tasks []task.Task) *cliHarness {
+	t.Helper()
+
+	repo := tasktest.NewMockRepository().WithTasks(tasks)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cli := NewCLI(service.NewTaskService(repo), stdout, stderr, tasktest.FixedTime)
+
+	return &cliHarness{cli: cli, repo: repo, stdout: stdout, stderr: stderr}
+}
+
+// run executes the CLI with the given arguments and returns the exit code
+func (h *cliHarness) run(args ...string) int {
+	h.stdout.Reset()
+	h.stderr.Reset()
+	return h.cli.Run(context.Background(), append([]string{"task-cli"}, args...))
+}
+
+// assertGolden compares output against testdata/<name>.golden
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatalf("failed to create testdata: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s: %v (run with -update)", path, err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output mismatch for %s\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+// TestCLI_GoldenOutput tests rendered output against golden files
+func TestCLI_GoldenOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		stream   string
+	}{
+		{name: "list_all", args: []string{"list"}, stream: "stdout"},
+		{name: "list_done", args: []string{"list", "done"}, stream: "stdout"},
+		{name: "list_relative", args: []string{"--relative", "list", "todo"}, stream: "stdout"},
+		{name: "show", args: []string{"show", "2"}, stream: "stdout"},
+		{name: "board", args: []string{"board", "--width", "60"}, stream: "stdout"},
+		{name: "chart_burndown", args: []string{"chart", "burndown", "--since", "2d"}, stream: "stdout"},
+		{name: "usage", args: []string{"help"}, stream: "stdout"},
+		{name: "unknown_command", args: []string{"frobnicate"}, wantCode: 1, stream: "stderr"},
+		{name: "invalid_status", args: []string{"list", "blocked"}, wantCode: 1, stream: "stderr"},
+		{name: "not_found", args: []string{"mark-done", "42"}, wantCode: exitNotFound, stream: "stderr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newCLIHarness(t, tasktest.FixedTasks(t))
+			code := h.run(tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("Run(%v) exit code = %d, want %d", tt.args, code, tt.wantCode)
+			}
+
+			got := h.stdout.Bytes()
+			if tt.stream == "stderr" {
+				got = h.stderr.Bytes()
+				if h.stdout.Len() != 0 {
+					t.Errorf("Run(%v) wrote to stdout on error: %q", tt.args, h.stdout.String())
+				}
+			}
+			assertGolden(t, tt.name, got)
+		})
+	}
+}
+
+// TestCLI_Quiet tests that quiet mode only prints IDs
+func TestCLI_Quiet(t *testing.T) {
+	t.Run("add prints only the ID", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+		if code := h.run("--quiet", "add", "New task"); code != 0 {
+			t.Fatalf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if got := h.stdout.String(); got != "4\n" {
+			t.Errorf("quiet add output = %q, want %q", got, "4\n")
+		}
+	})
+
+	t.Run("mutations print nothing", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+		if code := h.run("-q", "mark-done", "1"); code != 0 {
+			t.Fatalf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if h.stdout.Len() != 0 {
+			t.Errorf("quiet mark-done output = %q, want empty", h.stdout.String())
+		}
+	})
+
+	t.Run("errors still reach stderr", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+		if code := h.run("-q", "delete", "abc"); code != exitNotFound {
+			t.Errorf("Run() exit code = %d, want %d", code, exitNotFound)
+		}
+		if h.stderr.String() != "Error: Task not found: \"abc\" [NOT_FOUND]\nHint: run 'task-cli list --all' to see task IDs\n" {
+			t.Errorf("stderr = %q", h.stderr.String())
+		}
+	})
+
+	t.Run("lists are still printed", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+		if code := h.run("-q", "list", "done"); code != 0 {
+			t.Fatalf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if !strings.Contains(h.stdout.String(), "Call mom") {
+			t.Errorf("quiet list output = %q, want the done task", h.stdout.String())
+		}
+	})
+}
+
+// TestCLI_ErrorsGoToStderr tests that failing commands leave stdout empty,
+// so that scripts piping the output do not read error messages
+func TestCLI_ErrorsGoToStderr(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "missing description", args: []string{"add"}, want: "Error: Task description cannot be empty"},
+		{name: "missing update arguments", args: []string{"update", "1"}, want: "Error: ID and description are required"},
+		{name: "invalid global flag", args: []string{"--timeout", "nope", "list"}, want: "Error: invalid timeout \"nope\""},
+		{name: "unknown task", args: []string{"update", "42", "Renamed"}, want: "Error: Task not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newCLIHarness(t, tasktest.FixedTasks(t))
+			h.cli.WithStdin(strings.NewReader(""))
+
+			if code := h.run(tt.args...); code == 0 {
+				t.Fatalf("Run(%v) exit code = 0, want an error", tt.args)
+			}
+			if h.stdout.Len() != 0 {
+				t.Errorf("Run(%v) wrote to stdout: %q", tt.args, h.stdout.String())
+			}
+			if !strings.HasPrefix(h.stderr.String(), tt.want) {
+				t.Errorf("Run(%v) stderr = %q, want it to start with %q", tt.args, h.stderr.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestCLI_AddFromStdin tests reading descriptions from piped input
+func TestCLI_AddFromStdin(t *testing.T) {
+	t.Run("dash reads one description", func(t *testing.T) {
+		h := newCLIHarness(t, nil)
+		h.cli.WithStdin(strings.NewReader("Buy milk\n  and eggs\n"))
+
+		if code := h.run("add", "-"); code != 0 {
+			t.Fatalf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if task, _ := h.repo.GetTask(1); task.Description != "Buy milk and eggs" {
+			t.Errorf("description = %q", task.Description)
+		}
+	})
+
+	t.Run("each line adds a task", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		h.cli.WithStdin(strings.NewReader("Milk\n\nEggs\r\nBread"))
+
+		if code := h.run("-q", "add", "--each-line"); code != 0 {
+			t.Fatalf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if got := h.stdout.String(); got != "4\n5\n6\n" {
+			t.Errorf("quiet output = %q, want one ID per line", got)
+		}
+		if task, _ := h.repo.GetTask(5); task.Description != "Eggs" {
+			t.Errorf("task 5 = %q, want Eggs", task.Description)
+		}
+	})
+
+	t.Run("piped input without arguments", func(t *testing.T) {
+		h := newCLIHarness(t, nil)
+		h.cli.WithStdin(strings.NewReader("Call mom\n"))
+
+		if code := h.run("add"); code != 0 {
+			t.Fatalf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if h.repo.TaskCount() != 1 {
+			t.Errorf("TaskCount() = %d, want 1", h.repo.TaskCount())
+		}
+	})
+
+	t.Run("empty input is rejected", func(t *testing.T) {
+		h := newCLIHarness(t, nil)
+		h.cli.WithStdin(strings.NewReader("\n  \n"))
+
+		if code := h.run("add", "--each-line"); code != exitInvalid {
+			t.Errorf("Run() exit code = %d, want %d", code, exitInvalid)
+		}
+		if h.repo.SaveCallCount() != 0 {
+			t.Errorf("empty input should not save")
+		}
+	})
+}
+
+// TestCLI_AddFromFile tests adding a task per line of a file
+func TestCLI_AddFromFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.txt")
+	content := "# Weekend\n(A) call mom +home\n\nBuy groceries @errands\n"
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("adds lines in one save", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+		if code := h.run("add", "--from-file", file); code != 0 {
+			t.Fatalf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		want := "Added 2 tasks from " + file + ", skipped 2 blank or comment lines\n"
+		if got := h.stdout.String(); got != want {
+			t.Errorf("output = %q, want %q", got, want)
+		}
+		if h.repo.SaveCallCount() != 1 {
+			t.Errorf("Save() called %d times, want 1", h.repo.SaveCallCount())
+		}
+		if item, _ := h.repo.GetTask(4); item.Description != "call mom" || item.Priority != task.PriorityUrgent {
+			t.Errorf("task 4 = %+v", item)
+		}
+	})
+
+	t.Run("line without description fails", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.txt")
+		os.WriteFile(bad, []byte("Fine\n+home\n"), 0o644)
+		h := newCLIHarness(t, nil)
+
+		if code := h.run("add", "--from-file", bad); code != exitInvalid {
+			t.Errorf("Run() exit code = %d, want %d", code, exitInvalid)
+		}
+		if !strings.Contains(h.stderr.String(), "bad.txt:2") || h.repo.SaveCallCount() != 0 {
+			t.Errorf("stderr = %q, saves = %d", h.stderr.String(), h.repo.SaveCallCount())
+		}
+	})
+}
+
+// TestCLI_Move tests moving tasks through a custom workflow
+func TestCLI_Move(t *testing.T) {
+	workflow, err := task.NewWorkflow([]string{"review"}, map[string][]string{"in-progress": {"review"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStatus task.TaskStatus
+		wantStderr string
+	}{
+		{"allowed", []string{"move", "2", "review"}, 0, "review", ""},
+		{"forbidden", []string{"move", "2", "done"}, exitInvalid, task.StatusInProgress,
+			"Error: Invalid task status: cannot move task 2 from in-progress to done [INVALID_STATUS]\n" +
+				"Hint: add --force to make the move anyway\n"},
+		{"unknown", []string{"move", "2", "shipped"}, 1, task.StatusInProgress,
+			"Error: Invalid status 'shipped'. Valid options: todo, in-progress, done, cancelled, review\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newCLIHarness(t, tasktest.FixedTasks(t))
+			h.cli.service.WithWorkflow(workflow)
+
+			if code := h.run(tt.args...); code != tt.wantCode {
+				t.Errorf("Run(%v) exit code = %d, want %d", tt.args, code, tt.wantCode)
+			}
+			if got := h.stderr.String(); got != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", got, tt.wantStderr)
+			}
+			if task, _ := h.repo.GetTask(2); task.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", task.Status, tt.wantStatus)
+			}
+		})
+	}
+
+	t.Run("list accepts custom statuses", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		h.cli.service.WithWorkflow(workflow)
+
+		if code := h.run("list", "review"); code != 0 {
+			t.Errorf("list review exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+	})
+}
+
+// TestCLI_MarkEnforcesWorkflow tests that mark commands follow transitions
+func TestCLI_MarkEnforcesWorkflow(t *testing.T) {
+	workflow, err := task.NewWorkflow(nil, map[string][]string{"todo": {"in-progress"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("forbidden transition", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		h.cli.service.WithWorkflow(workflow)
+
+		if code := h.run("mark-done", "1"); code != exitInvalid {
+			t.Errorf("exit code = %d, want %d", code, exitInvalid)
+		}
+		want := "Error: Invalid task status: cannot move task 1 from todo to done [INVALID_STATUS]\n" +
+			"Hint: add --force to make the move anyway\n"
+		if got := h.stderr.String(); got != want {
+			t.Errorf("stderr = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("force overrides", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		h.cli.service.WithWorkflow(workflow)
+
+		if code := h.run("mark-done", "--force", "1"); code != 0 {
+			t.Fatalf("exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if item, _ := h.repo.GetTask(1); item.Status != task.StatusDone {
+			t.Errorf("status = %s, want done", item.Status)
+		}
+	})
+
+	t.Run("warn when skipping in-progress", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		config := repository.DefaultConfig()
+		config.Workflow.WarnOnSkip = true
+		h.cli.WithConfig(config)
+
+		if code := h.run("mark-done", "1"); code != 0 {
+			t.Fatalf("exit code = %d", code)
+		}
+		if got := h.stderr.String(); got != "Warning: task 1 was never in progress\n" {
+			t.Errorf("stderr = %q", got)
+		}
+
+		if code := h.run("mark-done", "2"); code != 0 || h.stderr.Len() != 0 {
+			t.Errorf("in-progress task warned: %q", h.stderr.String())
+		}
+	})
+}
+
+// TestCLI_Cancel tests cancelling tasks and hiding them from list
+func TestCLI_Cancel(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	if code := h.run("cancel", "1", "--reason", "Fridge is full"); code != 0 {
+		t.Fatalf("cancel exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	item, _ := h.repo.GetTask(1)
+	if item.Status != task.StatusCancelled || len(item.Notes) != 1 {
+		t.Errorf("task = %+v", item)
+	}
+
+	tests := []struct {
+		args      []string
+		wantShown bool
+	}{
+		{[]string{"list"}, false},
+		{[]string{"list", "--all"}, true},
+		{[]string{"list", "cancelled"}, true},
+	}
+	for _, tt := range tests {
+		h.run(tt.args...)
+		if shown := strings.Contains(h.stdout.String(), "Buy groceries"); shown != tt.wantShown {
+			t.Errorf("%v shows cancelled task = %v, want %v", tt.args, shown, tt.wantShown)
+		}
+	}
+
+	if code := h.run("mark-done", "1"); code != exitInvalid {
+		t.Errorf("mark-done on cancelled task exit code = %d, want %d", code, exitInvalid)
+	}
+}
+
+// TestCLI_Assign tests assigning tasks and filtering by assignee
+func TestCLI_Assign(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	config := repository.DefaultConfig()
+	config.User = "alice"
+	h.cli.WithConfig(config)
+
+	if code := h.run("assign", "1", "bob"); code != 0 {
+		t.Fatalf("assign exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("add", "Plan offsite", "--assignee", "me"); code != 0 {
+		t.Fatalf("add exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if task, _ := h.repo.GetTask(4); task.Assignee != "alice" {
+		t.Errorf("assignee = %q, want me resolved to alice", task.Assignee)
+	}
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"bob", []string{"Buy groceries"}},
+		{"me", []string{"Plan offsite"}},
+		{"none", []string{"Write report", "Call mom"}},
+	}
+	for _, tt := range tests {
+		h.run("list", "--assignee", tt.filter)
+		out := h.stdout.String()
+		if got := strings.Count(out, "ID: "); got != len(tt.want) {
+			t.Errorf("list --assignee %s shows %d tasks, want %d:\n%s", tt.filter, got, len(tt.want), out)
+		}
+		for _, description := range tt.want {
+			if !strings.Contains(out, description) {
+				t.Errorf("list --assignee %s misses %q", tt.filter, description)
+			}
+		}
+	}
+
+	h.run("list", "--assignee", "bob")
+	if !strings.Contains(h.stdout.String(), "| Assignee: bob") {
+		t.Errorf("list output misses assignee:\n%s", h.stdout.String())
+	}
+
+	if code := h.run("assign", "1", "none"); code != 0 {
+		t.Fatalf("unassign exit code = %d", code)
+	}
+	if task, _ := h.repo.GetTask(1); task.Assignee != "" {
+		t.Errorf("assignee = %q after unassign", task.Assignee)
+	}
+}
+
+// TestCLI_CreatedBy tests showing and filtering attribution
+func TestCLI_CreatedBy(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	ctx := task.WithActor(t.Context(), "alice")
+	if code := h.cli.Run(ctx, []string{"task-cli", "add", "Plan offsite"}); code != 0 {
+		t.Fatalf("add exit code = %d", code)
+	}
+
+	h.run("list", "--created-by", "alice")
+	if out := h.stdout.String(); strings.Count(out, "ID: ") != 1 || !strings.Contains(out, "Plan offsite") {
+		t.Errorf("list --created-by alice =\n%s", out)
+	}
+
+	h.run("show", "4")
+	if out := h.stdout.String(); strings.Count(out, " by alice\n") != 2 {
+		t.Errorf("show output misses attribution:\n%s", out)
+	}
+}
+
+// TestCLI_GlobalFlags tests flags accepted before the command name
+func TestCLI_GlobalFlags(t *testing.T) {
+	t.Run("valid timeout", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+		if code := h.run("--timeout=5s", "list"); code != 0 {
+			t.Errorf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		h := newCLIHarness(t, nil)
+
+		if code := h.run("--timeout", "soon", "list"); code != 1 {
+			t.Errorf("Run() exit code = %d, want 1", code)
+		}
+	})
+
+	t.Run("unknown global flag", func(t *testing.T) {
+		h := newCLIHarness(t, nil)
+
+		if code := h.run("--loud", "list"); code != 1 {
+			t.Errorf("Run() exit code = %d, want 1", code)
+		}
+	})
+}
+
+// TestCLI_ListLimit tests paging through the task list
+func TestCLI_ListLimit(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	h.run("list", "--limit", "1", "--offset", "1")
+	out := h.stdout.String()
+	if !strings.Contains(out, "Write report") || strings.Contains(out, "Buy groceries") || strings.Contains(out, "Call mom") {
+		t.Errorf("list --limit 1 --offset 1 output = %q, want only Write report", out)
+	}
+
+	if code := h.run("list", "--limit", "-1"); code != 1 {
+		t.Errorf("negative limit exit code = %d, want 1", code)
+	}
+}
+
+// TestExtractDataFile tests taking the --file flag out of the arguments
+func TestExtractDataFile(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		file string
+		rest []string
+	}{
+		{"absent", []string{"task-cli", "list"}, "", []string{"task-cli", "list"}},
+		{"global", []string{"task-cli", "--file", "sprint.json", "list"}, "sprint.json", []string{"task-cli", "list"}},
+		{"after command", []string{"task-cli", "list", "--data-file=b.json", "done"}, "b.json", []string{"task-cli", "list", "done"}},
+		{"after --", []string{"task-cli", "add", "--", "--file"}, "", []string{"task-cli", "add", "--", "--file"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, rest, err := ExtractDataFile(tt.args)
+			if err != nil || file != tt.file || !slices.Equal(rest, tt.rest) {
+				t.Errorf("ExtractDataFile() = %q, %q, %v; want %q, %q", file, rest, err, tt.file, tt.rest)
+			}
+		})
+	}
+
+	if _, _, err := ExtractDataFile([]string{"task-cli", "list", "--file"}); err == nil {
+		t.Error("ExtractDataFile() without a path should fail")
+	}
+}
+
+// TestCLI_Cancellation tests that a cancelled context aborts the command
+func TestCLI_Cancellation(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	code := h.cli.Run(ctx, []string{"task-cli", "add", "Never saved"})
+	if code != 1 {
+		t.Errorf("Run() exit code = %d, want 1", code)
+	}
+	if !strings.Contains(h.stderr.String(), context.Canceled.Error()) {
+		t.Errorf("stderr = %q, want cancellation error", h.stderr.String())
+	}
+	if h.repo.TaskCount() != 3 {
+		t.Errorf("cancelled add should not store a task, have %d", h.repo.TaskCount())
+	}
+}
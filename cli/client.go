@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/alnah/task-tracker/service"
+)
+
+// WithSocketFile sets where daemon --socket listens and client connects
+// by default
+func (c *CLI) WithSocketFile(path string) *CLI {
+	c.socketFile = path
+	return c
+}
+
+// socketFlag is --socket, which takes an optional path: --socket alone
+// uses the default socket file
+type socketFlag struct {
+	enabled bool
+	path    string
+}
+
+func (f *socketFlag) String() string {
+	return f.path
+}
+
+func (f *socketFlag) Set(value string) error {
+	switch value {
+	case "true":
+		f.enabled = true
+	case "false":
+		f.enabled = false
+	default:
+		f.enabled, f.path = true, value
+	}
+	return nil
+}
+
+func (f *socketFlag) IsBoolFlag() bool {
+	return true
+}
+
+// listenSocket starts answering commands on the socket in the background.
+// The returned channel yields the outcome once ctx is done.
+func (c *CLI) listenSocket(ctx context.Context, path string) (<-chan error, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no socket file, pass --socket=<path>")
+	}
+	listener, err := service.ListenSocket(path)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- service.ServeSocket(ctx, listener, c.socketRequest) }()
+	c.successf("Listening on %s\n", path)
+	return done, nil
+}
+
+// socketRequest runs a command sent to the daemon as if it had been typed
+// after task-cli, capturing what it prints. Nobody can answer a prompt, so
+// deleting needs --yes.
+func (c *CLI) socketRequest(ctx context.Context, args []string) service.SocketResponse {
+	var stdout, stderr bytes.Buffer
+	request := *c
+	request.stdout, request.stderr = &stdout, &stderr
+	request.stdin = strings.NewReader("")
+	request.prompter = NewLinePrompter(strings.NewReader(""), &stderr)
+	request.quiet, request.relative, request.accessible, request.timeout = false, false, false, 0
+	request.aliasDepth = 0
+	request.viaSocket = true
+
+	code := request.Run(ctx, append([]string{"task-cli"}, args...))
+	return service.SocketResponse{Code: code, Stdout: stdout.String(), Stderr: stderr.String()}
+}
+
+// handleClient sends a command to a running daemon instead of running it
+// in this process
+func (c *CLI) handleClient(ctx context.Context, args []string) int {
+	path := c.socketFile
+	// Only a leading --socket belongs to client, the rest is the command
+	if len(args) > 0 {
+		name, value, hasValue := strings.Cut(args[0], "=")
+		switch {
+		case name == "--socket" && hasValue:
+			path, args = value, args[1:]
+		case name == "--socket" && len(args) > 1:
+			path, args = args[1], args[2:]
+		}
+	}
+	if len(args) == 0 {
+		c.errorf("Error: command is required\n")
+		c.errorf("Usage: task-cli client [--socket <path>] <command> [arguments]\n")
+		return 1
+	}
+
+	response, err := service.CallSocket(ctx, path, append(c.globalFlags(), args...))
+	if err != nil {
+		return c.fail(err)
+	}
+	fmt.Fprint(c.stdout, response.Stdout)
+	fmt.Fprint(c.stderr, response.Stderr)
+	return response.Code
+}
+
+// globalFlags repeats the global flags given to this process for a command
+// sent to the daemon
+func (c *CLI) globalFlags() []string {
+	var global []string
+	if c.quiet {
+		global = append(global, "--quiet")
+	}
+	if c.relative {
+		global = append(global, "--relative")
+	}
+	if c.accessible {
+		global = append(global, "--accessible")
+	}
+	if c.list != "" {
+		global = append(global, "--list="+c.list)
+	}
+	return global
+}
+
+// proxiedCommands only work on the store, so the daemon can run them in
+// place of this process. Commands reading stdin, local files or the
+// terminal keep running here.
+var proxiedCommands = map[string]bool{
+	"add": true, "update": true, "set": true, "edit": true, "delete": true, "mark-in-progress": true, "mark-done": true,
+	"list": true, "show": true, "next": true, "in": true, "snooze": true, "check": true,
+	"project": true, "milestone": true, "sprint": true, "board": true, "chart": true, "move": true,
+	"cancel": true, "assign": true, "due": true, "estimate": true, "estimates": true,
+	"list-of-lists": true, "tag": true, "link": true, "unlink": true, "remind": true, "habit": true, "log": true, "history": true, "view": true, "summary": true,
+}
+
+// proxy sends the command to the daemon when one is listening on the
+// socket file, so the store is not read again and changes are serialized
+// with the daemon's own. ok is false when the command must run in this
+// process: no daemon is running, --no-daemon was given, the command is not
+// proxied, or it would need a picker or a prompt the daemon cannot show.
+func (c *CLI) proxy(ctx context.Context, args []string) (code int, ok bool) {
+	if c.viaSocket || c.noDaemon || c.socketFile == "" || c.service.ReadOnly() {
+		return 0, false
+	}
+	// Aliases run here, as their arguments may need this process too
+	if !proxiedCommands[args[0]] || !c.proxyable(args) {
+		return 0, false
+	}
+	if _, err := os.Stat(c.socketFile); err != nil {
+		return 0, false
+	}
+
+	response, err := service.CallSocket(ctx, c.socketFile, append(c.globalFlags(), args...))
+	if errors.Is(err, service.ErrDaemonUnreachable) {
+		// A socket left behind by a daemon that crashed
+		return 0, false
+	}
+	if err != nil {
+		c.printError(err)
+		return 1, true
+	}
+	fmt.Fprint(c.stdout, response.Stdout)
+	fmt.Fprint(c.stderr, response.Stderr)
+	return response.Code, true
+}
+
+// pickingCommands let the user pick the task when given no ID, keyed to
+// the flags of theirs that take a value
+var pickingCommands = map[string][]string{
+	"mark-in-progress": nil, "mark-done": nil, "delete": nil,
+	"show": {"template"}, "cancel": {"reason"},
+}
+
+// proxyable reports whether the arguments of a proxied command need
+// nothing from this process: no stdin, no file path relative to the
+// current directory, no picker and no confirmation prompt. add needs
+// --force, as it asks before adding a task like an open one.
+func (c *CLI) proxyable(args []string) bool {
+	forced := false
+	for _, arg := range args[1:] {
+		name, value, _ := strings.Cut(arg, "=")
+		switch name {
+		case "-", "--each-line", "--from-file":
+			return false
+		case "--force", "-force":
+			forced = value != "false"
+		}
+	}
+	if args[0] == "add" && !forced {
+		return false
+	}
+	if valueFlags, ok := pickingCommands[args[0]]; ok && !hasPositional(args[1:], valueFlags) {
+		return false
+	}
+	if args[0] == "delete" && c.config.Confirm.Delete {
+		return slices.Contains(args, "--yes") || slices.Contains(args, "-yes")
+	}
+	return true
+}
+
+// hasPositional reports whether args hold an argument besides flags,
+// valueFlags naming those that take the next argument as their value
+func hasPositional(args []string, valueFlags []string) bool {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return i+1 < len(args)
+		case len(arg) < 2 || arg[0] != '-':
+			return true
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !hasValue && slices.Contains(valueFlags, name) {
+			i++
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"io"
+	"os"
+)
+
+// colorOutput reports whether w takes ANSI colors: it is a terminal,
+// NO_COLOR is unset, TERM is not dumb, and on Windows the console accepts
+// escape sequences once virtual terminal processing is enabled
+func colorOutput(w io.Writer, getenv func(string) string) bool {
+	f, ok := w.(*os.File)
+	return supportsColor(isTerminal(w), getenv, func() bool { return ok && enableVirtualTerminal(f) })
+}
+
+func supportsColor(terminal bool, getenv func(string) string, enableVT func() bool) bool {
+	if !terminal || getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" {
+		return false
+	}
+	// Consoles that cannot enable escape sequences would print them raw
+	return enableVT()
+}
@@ -0,0 +1,30 @@
+package cli
+
+import "testing"
+
+// TestSupportsColor tests when colors are written
+func TestSupportsColor(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+	enabled := func() bool { return true }
+	refused := func() bool { return false }
+	tests := []struct {
+		name     string
+		terminal bool
+		env      map[string]string
+		enableVT func() bool
+		want     bool
+	}{
+		{"terminal", true, nil, enabled, true},
+		{"pipe", false, nil, enabled, false},
+		{"NO_COLOR", true, map[string]string{"NO_COLOR": "1"}, enabled, false},
+		{"dumb terminal", true, map[string]string{"TERM": "dumb"}, enabled, false},
+		{"old Windows console", true, nil, refused, false},
+	}
+	for _, tt := range tests {
+		if got := supportsColor(tt.terminal, env(tt.env), tt.enableVT); got != tt.want {
+			t.Errorf("%s: supportsColor() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
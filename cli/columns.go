@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Column is a task attribute list can print as a column
+type Column struct {
+	Name string
+	// Aliases are other names --columns accepts for the column
+	Aliases []string
+	// Label names the column in headers, upper-cased, and in the
+	// "label: value" lines of accessible output
+	Label string
+	Value func(task.Task) string
+}
+
+// taskColumns is every column list --columns can select, in the order
+// they are documented
+var taskColumns = []Column{
+	{Name: "id", Label: "ID", Value: func(t task.Task) string { return strconv.Itoa(t.ID) }},
+	{Name: "uuid", Label: "UUID", Value: task.TaskUUID},
+	{Name: "status", Label: "Status", Value: func(t task.Task) string { return string(t.Status) }},
+	{Name: "priority", Aliases: []string{"pri"}, Label: "Priority", Value: func(t task.Task) string { return string(t.Priority) }},
+	{Name: "due", Label: "Due", Value: func(t task.Task) string { return columnTime(t.DueAt) }},
+	{Name: "description", Aliases: []string{"desc"}, Label: "Description", Value: func(t task.Task) string { return t.Description }},
+	{Name: "tags", Aliases: []string{"tag"}, Label: "Tags", Value: func(t task.Task) string { return strings.Join(t.Tags, ",") }},
+	{Name: "list", Label: "List", Value: func(t task.Task) string { return t.List }},
+	{Name: "project", Label: "Project", Value: func(t task.Task) string { return t.Project }},
+	{Name: "milestone", Label: "Milestone", Value: func(t task.Task) string { return t.Milestone }},
+	{Name: "sprint", Label: "Sprint", Value: func(t task.Task) string { return t.Sprint }},
+	{Name: "assignee", Label: "Assignee", Value: func(t task.Task) string { return t.Assignee }},
+	{Name: "creator", Aliases: []string{"created-by"}, Label: "Creator", Value: func(t task.Task) string { return t.CreatedBy }},
+	{Name: "created", Label: "Created", Value: func(t task.Task) string { return columnTime(&t.CreatedAt) }},
+	{Name: "updated", Label: "Updated", Value: func(t task.Task) string { return columnTime(&t.UpdatedAt) }},
+	{Name: "estimate", Label: "Estimate", Value: func(t task.Task) string {
+		if t.Estimate == 0 {
+			return ""
+		}
+		return task.FormatDuration(time.Duration(t.Estimate))
+	}},
+	{Name: "pomodoros", Label: "Pomodoros", Value: func(t task.Task) string { return strconv.Itoa(t.Pomodoros) }},
+}
+
+// defaultColumns are printed by the table formats without --columns
+const defaultColumns = "id,status,priority,due,description"
+
+func columnTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// columnNames lists the names --columns accepts, without aliases
+func columnNames() []string {
+	names := make([]string, len(taskColumns))
+	for i, column := range taskColumns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+// ParseColumns returns the columns of a comma-separated list of names
+func ParseColumns(spec string) ([]Column, error) {
+	var columns []Column
+	for name := range strings.SplitSeq(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		column, ok := findColumn(name)
+		if !ok {
+			return nil, task.TaskError{
+				Code:    task.ErrInvalidColumn.Code,
+				Message: fmt.Sprintf("%s '%s'. Valid options: %s", task.ErrInvalidColumn.Message, name, strings.Join(columnNames(), ", ")),
+			}
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil, task.ErrInvalidColumn
+	}
+	return columns, nil
+}
+
+func findColumn(name string) (Column, bool) {
+	for _, column := range taskColumns {
+		if column.Name == name {
+			return column, true
+		}
+		for _, alias := range column.Aliases {
+			if alias == name {
+				return column, true
+			}
+		}
+	}
+	return Column{}, false
+}
+
+// tableFormats are the list formats that print tasks as rows of columns
+var tableFormats = map[string]bool{"table": true, "tsv": true, "csv": true}
+
+// WriteTable prints tasks as rows of columns: an aligned table, tab or
+// comma separated values. The first row holds the headers unless header is
+// false.
+func WriteTable(w io.Writer, format string, columns []Column, tasks []task.Task, header bool) error {
+	rows := make([][]string, 0, len(tasks)+1)
+	if header {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = strings.ToUpper(column.Label)
+		}
+		rows = append(rows, row)
+	}
+	for _, task := range tasks {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = column.Value(task)
+		}
+		rows = append(rows, row)
+	}
+
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.WriteAll(rows)
+		return cw.Error()
+	case "tsv":
+		// Tabs and line breaks inside values would split them
+		clean := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+		for _, row := range rows {
+			for i, value := range row {
+				row[i] = clean.Replace(value)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		clean := strings.NewReplacer("\t", " ", "\n", " ")
+		for _, row := range rows {
+			for i, value := range row {
+				row[i] = clean.Replace(value)
+			}
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	default:
+		return task.InvalidFormat(fmt.Sprintf("'%s' is not a table format", format))
+	}
+}
+
+// WriteRecords prints tasks as "label: value" lines, a blank line between
+// tasks, for screen readers that cannot follow table columns. Empty values
+// are left out.
+func WriteRecords(w io.Writer, columns []Column, tasks []task.Task) error {
+	for i, task := range tasks {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		for _, column := range columns {
+			value := column.Value(task)
+			if column.Name == "status" {
+				value = spellStatus(value)
+			}
+			if value != "" {
+				if _, err := fmt.Fprintf(w, "%s: %s\n", column.Label, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestParseColumns tests column names, aliases and unknown columns
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns("id, desc,PRI,,due")
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+	var names []string
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	if strings.Join(names, ",") != "id,description,priority,due" {
+		t.Errorf("ParseColumns() = %v", names)
+	}
+
+	if _, err := ParseColumns("id,size"); !errors.Is(err, task.ErrInvalidColumn) ||
+		!strings.Contains(err.Error(), "'size'") {
+		t.Errorf("ParseColumns(id,size) error = %v, want %s naming size", err, task.ErrInvalidColumn.Code)
+	}
+	if _, err := ParseColumns(" , "); !errors.Is(err, task.ErrInvalidColumn) {
+		t.Errorf("ParseColumns(empty) error = %v, want %v", err, task.ErrInvalidColumn)
+	}
+}
+
+// TestCLI_ListColumns tests the table, tsv and csv formats of list
+func TestCLI_ListColumns(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].Description = "Buy milk, eggs\tand bread"
+	tasks[1].Tags = []string{"work", "q3"}
+	h := newCLIHarness(t, tasks)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{
+			[]string{"--columns", "id,status,desc"},
+			"ID  STATUS       DESCRIPTION\n" +
+				"1   todo         Buy milk, eggs and bread\n" +
+				"2   in-progress  Write report\n",
+		},
+		{
+			[]string{"--format", "tsv", "--columns", "id,tags", "--no-header"},
+			"1\t\n2\twork,q3\n",
+		},
+		{
+			[]string{"--format", "csv", "--columns", "id,desc"},
+			"ID,DESCRIPTION\n1,\"Buy milk, eggs\tand bread\"\n2,Write report\n",
+		},
+		{
+			[]string{"--format", "tsv"},
+			"ID\tSTATUS\tPRIORITY\tDUE\tDESCRIPTION\n1\ttodo\t\t\tBuy milk, eggs and bread\n2\tin-progress\t\t\tWrite report\n",
+		},
+	}
+	for _, tt := range tests {
+		args := append([]string{"list", "--where", "status != done"}, tt.args...)
+		if code := h.run(args...); code != 0 {
+			t.Fatalf("%v exit code = %d, stderr = %q", tt.args, code, h.stderr.String())
+		}
+		if out := h.stdout.String(); out != tt.want {
+			t.Errorf("%v output = %q, want %q", tt.args, out, tt.want)
+		}
+	}
+
+	if code := h.run("list", "--format", "quickfix", "--columns", "id"); code != 1 {
+		t.Errorf("list --columns with quickfix exit code = %d, want 1", code)
+	}
+	if code := h.run("list", "--columns", "id,size"); code != exitInvalid || !strings.Contains(h.stderr.String(), "Invalid column 'size'") {
+		t.Errorf("list --columns id,size exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package cli
+
+import "os"
+
+// enableVirtualTerminal reports whether the terminal f writes to takes ANSI
+// escape sequences, which terminals outside Windows always do
+func enableVirtualTerminal(f *os.File) bool {
+	return true
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithContextFile sets where the active context is remembered
+func (c *CLI) WithContextFile(path string) *CLI {
+	c.contextFile = path
+	return c
+}
+
+// activeContext returns the context set with context set, empty when none
+// is or it cannot be read
+func (c *CLI) activeContext() string {
+	if c.contextFile == "" {
+		return ""
+	}
+	data, err := os.ReadFile(c.contextFile)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// contextFlags adds --context and --all-contexts, returning a function
+// that gives the context to filter on: the one asked for, else the active
+// one unless --all-contexts was passed
+func (c *CLI) contextFlags(fs *flag.FlagSet) func() string {
+	name := fs.String("context", "", "only tasks in this context or in none, instead of the active context")
+	all := fs.Bool("all-contexts", false, "ignore the active context")
+	return func() string {
+		switch {
+		case *name != "":
+			return task.ContextTag(*name)
+		case *all:
+			return ""
+		default:
+			return c.activeContext()
+		}
+	}
+}
+
+// inContext narrows match to the tasks that can be done in the context,
+// when there is one
+func inContext(match func(task.Task) bool, name string) func(task.Task) bool {
+	if name == "" {
+		return match
+	}
+	return func(task task.Task) bool {
+		return task.InContext(name) && match(task)
+	}
+}
+
+func (c *CLI) handleContext(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{"show"}
+	}
+
+	switch args[0] {
+	case "show":
+		if active := c.activeContext(); active != "" {
+			fmt.Fprintln(c.stdout, active)
+		} else {
+			c.successf("No active context\n")
+		}
+		return 0
+
+	case "set":
+		if len(args) < 2 || task.ContextTag(args[1]) == "@" {
+			c.errorf("Error: Context name is required\n")
+			c.errorf("Usage: task-cli context set @<name>\n")
+			return 1
+		}
+		name := task.ContextTag(args[1])
+		if err := c.saveContext(name); err != nil {
+			return c.fail(err)
+		}
+		c.successf("Context set to %s\n", name)
+		return 0
+
+	case "clear":
+		if err := c.saveContext(""); err != nil {
+			return c.fail(err)
+		}
+		c.successf("Context cleared\n")
+		return 0
+
+	case "list":
+		tasks, err := c.service.ListTasks(ctx, "")
+		if err != nil {
+			return c.fail(err)
+		}
+		counts := map[string]int{}
+		for _, task := range tasks {
+			if task.IsOpen() {
+				for _, name := range task.Contexts() {
+					counts[name]++
+				}
+			}
+		}
+		if len(counts) == 0 {
+			c.successf("No contexts in use\n")
+			return 0
+		}
+		active := c.activeContext()
+		for _, name := range slices.Sorted(maps.Keys(counts)) {
+			marker := " "
+			if name == active {
+				marker = "*"
+			}
+			fmt.Fprintf(c.stdout, "%s %s  %s\n", marker, name,
+				c.tr.Sprintf(c.tr.Plural(counts[name], "%d tasks"), counts[name]))
+		}
+		return 0
+
+	default:
+		c.errorf("Unknown context command: %s\n", args[0])
+		c.errorf("Usage: task-cli context [show] | set @<name> | clear | list\n")
+		return 1
+	}
+}
+
+// saveContext remembers the active context, removing the file to clear it
+func (c *CLI) saveContext(name string) error {
+	if c.contextFile == "" {
+		return errors.New("contexts are not available with this storage")
+	}
+	if name == "" {
+		if err := os.Remove(c.contextFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.WriteFile(c.contextFile, []byte(name+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to save the context: %w", err)
+	}
+	return nil
+}
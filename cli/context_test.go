@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_Context tests that the active context filters list, next and
+// board until cleared or overridden
+func TestCLI_Context(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithContextFile(filepath.Join(t.TempDir(), "tasks.context"))
+	h.run("add", "Fix the sink", "--context", "home")
+	h.run("add", "Print slides", "--context", "@work")
+
+	if code := h.run("context", "set", "home"); code != 0 || h.stdout.String() != "Context set to @home\n" {
+		t.Fatalf("context set = %d, %q, stderr: %s", code, h.stdout, h.stderr)
+	}
+	if h.run("context"); h.stdout.String() != "@home\n" {
+		t.Errorf("context = %q", h.stdout)
+	}
+
+	h.run("list")
+	if !strings.Contains(h.stdout.String(), "Fix the sink") || strings.Contains(h.stdout.String(), "Print slides") ||
+		!strings.Contains(h.stdout.String(), "Buy groceries") {
+		t.Errorf("list in @home:\n%s", h.stdout)
+	}
+	h.run("board", "--width", "80")
+	if strings.Contains(h.stdout.String(), "Print slides") {
+		t.Errorf("board shows tasks of other contexts:\n%s", h.stdout)
+	}
+	for _, args := range [][]string{{"list", "--all-contexts"}, {"list", "--context", "work"}} {
+		if h.run(args...); !strings.Contains(h.stdout.String(), "Print slides") {
+			t.Errorf("%v does not show @work tasks:\n%s", args, h.stdout)
+		}
+	}
+
+	if h.run("context", "list"); h.stdout.String() != "* @home  1 task\n  @work  1 task\n" {
+		t.Errorf("context list = %q", h.stdout)
+	}
+	h.run("context", "clear")
+	if h.run("list"); !strings.Contains(h.stdout.String(), "Print slides") {
+		t.Errorf("list after clear:\n%s", h.stdout)
+	}
+	if h.run("show", "5"); !strings.Contains(h.stdout.String(), "Contexts: @work\n") {
+		t.Errorf("show:\n%s", h.stdout)
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleDedupe(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("dedupe")
+	sameTags := fs.Bool("tags", false, "only merge tasks that also have the same tags")
+	keep := fs.String("keep", "progressed", "task to keep: progressed (furthest along) or oldest")
+	dryRun := fs.Bool("dry-run", false, "show what would be merged without saving")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+	if *keep != "progressed" && *keep != "oldest" {
+		c.errorf("Error: --keep must be progressed or oldest\n")
+		c.errorf("Usage: task-cli dedupe [--tags] [--keep progressed|oldest] [--dry-run]\n")
+		return 1
+	}
+
+	groups, err := c.service.Dedupe(ctx, task.DedupeOptions{
+		SameTags:   *sameTags,
+		KeepOldest: *keep == "oldest",
+		DryRun:     *dryRun,
+		Now:        c.clock(),
+	})
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(groups) == 0 {
+		c.successf("No duplicates found\n")
+		return 0
+	}
+
+	removed := 0
+	for _, group := range groups {
+		ids := make([]string, len(group.Removed))
+		for i, task := range group.Removed {
+			ids[i] = strconv.Itoa(task.ID)
+		}
+		removed += len(group.Removed)
+		if *dryRun {
+			c.successf("Would merge %s into task %d: %s\n", strings.Join(ids, ", "), group.Kept.ID, group.Kept.Description)
+		} else {
+			c.successf("Merged %s into task %d: %s\n", strings.Join(ids, ", "), group.Kept.ID, group.Kept.Description)
+		}
+	}
+	c.successf(c.tr.Plural(removed, "%d duplicates removed\n"), removed)
+	if *dryRun {
+		c.successf("Dry run, nothing was saved\n")
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+func TestCLI_Dedupe(t *testing.T) {
+	tasks := append(tasktest.FixedTasks(t), *tasktest.NewTaskBuilder().WithID(4).WithDescription("buy groceries!").
+		WithTimestamps(tasktest.TimeAfter(tasktest.FixedTime()), tasktest.TimeAfter(tasktest.FixedTime())).BuildInvalid())
+	h := newCLIHarness(t, tasks)
+
+	if code := h.run("dedupe", "--dry-run"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	want := "Would merge 4 into task 1: Buy groceries\n1 duplicate removed\nDry run, nothing was saved\n"
+	if h.stdout.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", h.stdout, want)
+	}
+
+	if code := h.run("dedupe"); code != 0 || h.repo.TaskCount() != 3 {
+		t.Fatalf("exit code = %d, %d tasks left", code, h.repo.TaskCount())
+	}
+	if code := h.run("dedupe"); code != 0 || h.stdout.String() != "No duplicates found\n" {
+		t.Errorf("second run = %q", h.stdout)
+	}
+	if code := h.run("dedupe", "--keep", "newest"); code != 1 {
+		t.Errorf("invalid --keep exit code = %d, want 1", code)
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// idRangePattern matches an ID range such as 3-7. UUID prefixes with a dash
+// have eight characters before it, so short numbers cannot be taken for one.
+var idRangePattern = regexp.MustCompile(`^(\d{1,7})-(\d{1,7})$`)
+
+func (c *CLI) handleDelete(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("delete")
+	yes := fs.Bool("yes", false, "delete without asking for confirmation")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(positional) == 0 {
+		if positional, err = c.pickArgs(ctx, true); err != nil {
+			c.printError(err)
+			c.errorf("Usage: task-cli delete <id|from-to>... [--yes]\n")
+			return 1
+		}
+	}
+
+	tasks, err := c.selectTasks(ctx, positional)
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(tasks) == 0 {
+		return c.fail(task.ErrTaskNotFound)
+	}
+
+	if c.config.Confirm.Delete && !*yes {
+		question := c.tr.Sprintf("Delete task %d %q?", tasks[0].ID, tasks[0].Description)
+		if len(tasks) > 1 {
+			question = c.tr.Sprintf(c.tr.Plural(len(tasks), "Delete %d tasks (%s)?"), len(tasks), taskIDList(tasks))
+		}
+		ok, err := c.confirm(question)
+		if err != nil {
+			return c.fail(err)
+		}
+		if !ok {
+			c.errorf("Error: deletion not confirmed, nothing was deleted (use --yes to skip the question)\n")
+			return 1
+		}
+	}
+
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	if err := c.service.DeleteTasks(ctx, ids); err != nil {
+		return c.fail(err)
+	}
+
+	if len(tasks) == 1 {
+		c.successf("Task deleted successfully\n")
+	} else {
+		c.successf(c.tr.Plural(len(tasks), "%d tasks deleted successfully\n"), len(tasks))
+	}
+	return 0
+}
+
+// selectTasks returns the tasks named by IDs, UUID prefixes and ID ranges,
+// in the order given and each once. A range selects the tasks whose IDs fall
+// in it, so gaps left by deleted tasks are skipped, while a single ID must
+// name an existing task.
+func (c *CLI) selectTasks(ctx context.Context, refs []string) ([]task.Task, error) {
+	var selected []task.Task
+	seen := make(map[int]bool)
+	add := func(task task.Task) {
+		if !seen[task.ID] {
+			seen[task.ID] = true
+			selected = append(selected, task)
+		}
+	}
+
+	for _, ref := range refs {
+		if m := idRangePattern.FindStringSubmatch(ref); m != nil {
+			from, _ := strconv.Atoi(m[1])
+			to, _ := strconv.Atoi(m[2])
+			if from > to {
+				return nil, fmt.Errorf("invalid range %s: %d is after %d", ref, from, to)
+			}
+			tasks, err := c.service.ListPage(ctx, repository.Page{}, func(task task.Task) bool {
+				return task.ID >= from && task.ID <= to
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, task := range tasks {
+				add(task)
+			}
+			continue
+		}
+
+		id, err := c.service.ResolveTaskID(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		task, err := c.service.GetTask(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		add(*task)
+	}
+	return selected, nil
+}
+
+// confirm asks the prompter, if there is one, before a destructive command
+func (c *CLI) confirm(question string) (bool, error) {
+	if c.prompter == nil {
+		return true, nil
+	}
+	return c.prompter.Confirm(question)
+}
+
+// taskIDList lists task IDs for a prompt, shortening long lists
+func taskIDList(tasks []task.Task) string {
+	const shown = 10
+	list := ""
+	for i, task := range tasks {
+		if i == shown {
+			return fmt.Sprintf("%s and %d more", list, len(tasks)-shown)
+		}
+		if i > 0 {
+			list += ", "
+		}
+		list += "#" + strconv.Itoa(task.ID)
+	}
+	return list
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/service"
+)
+
+func (c *CLI) handleDigest(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("digest")
+	fs.Bool("daily", true, "summarize yesterday and today (the only period so far)")
+	html := fs.Bool("html", false, "print HTML instead of text")
+	send := fs.Bool("send", false, "email the digest over the configured SMTP server")
+	skipEmpty := fs.Bool("skip-empty", false, "send nothing when there is nothing to report")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	digest, err := c.service.Digest(ctx, c.clock())
+	if err != nil {
+		return c.fail(err)
+	}
+	if *skipEmpty && digest.Empty() {
+		c.successf("Nothing to report\n")
+		return 0
+	}
+
+	if !*send {
+		if *html {
+			fmt.Fprint(c.stdout, digest.HTML())
+		} else {
+			fmt.Fprint(c.stdout, digest.Text())
+		}
+		return 0
+	}
+
+	if c.mailer == nil {
+		return c.fail(service.ErrMailNotConfigured)
+	}
+	mail := service.Mail{Subject: digest.Subject(), Text: digest.Text(), HTML: digest.HTML()}
+	if err := c.mailer.Send(ctx, mail); err != nil {
+		return c.fail(err)
+	}
+	c.successf("Sent %s\n", digest.Subject())
+	return 0
+}
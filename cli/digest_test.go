@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+// fakeMailer records mail instead of sending it
+type fakeMailer struct {
+	sent []service.Mail
+}
+
+func (m *fakeMailer) Send(_ context.Context, mail service.Mail) error {
+	m.sent = append(m.sent, mail)
+	return nil
+}
+
+// TestCLI_Digest tests printing and sending the digest
+func TestCLI_Digest(t *testing.T) {
+	due := tasktest.FixedTime().Add(-48 * time.Hour)
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].DueAt = &due
+
+	t.Run("prints text", func(t *testing.T) {
+		h := newCLIHarness(t, tasks)
+		if code := h.run("digest", "--daily"); code != 0 {
+			t.Fatalf("digest exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if !strings.Contains(h.stdout.String(), "Overdue (1)\n  #1 Buy groceries") {
+			t.Errorf("stdout = %q, want the overdue task", h.stdout.String())
+		}
+	})
+
+	t.Run("sends mail", func(t *testing.T) {
+		h := newCLIHarness(t, tasks)
+		mailer := &fakeMailer{}
+		h.cli.WithMailer(mailer)
+		if code := h.run("digest", "--send"); code != 0 {
+			t.Fatalf("digest exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if len(mailer.sent) != 1 || !strings.Contains(mailer.sent[0].HTML, "Buy groceries") {
+			t.Errorf("sent = %+v, want one digest", mailer.sent)
+		}
+	})
+
+	t.Run("skips empty digests", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		mailer := &fakeMailer{}
+		h.cli.WithMailer(mailer)
+		if code := h.run("digest", "--send", "--skip-empty"); code != 0 {
+			t.Fatalf("digest exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if len(mailer.sent) != 0 {
+			t.Errorf("sent %d mails, want none", len(mailer.sent))
+		}
+	})
+
+	t.Run("unconfigured SMTP", func(t *testing.T) {
+		h := newCLIHarness(t, tasks)
+		h.cli.WithMailer(service.NewSMTPMailer(repository.SMTPConfig{}))
+		if code := h.run("digest", "--send"); code != 1 {
+			t.Errorf("digest exit code = %d, want 1", code)
+		}
+		if !strings.Contains(h.stderr.String(), "no SMTP server configured") {
+			t.Errorf("stderr = %q, want a configuration hint", h.stderr.String())
+		}
+	})
+}
@@ -0,0 +1,5 @@
+// Package cli is the task-cli command line: CLI parses the arguments of a
+// command, runs it on a service.TaskService and prints the result. Main
+// sets everything up from the user's config file, as the task-cli binary
+// does.
+package cli
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleDoctor(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("doctor")
+	repair := fs.Bool("repair", false, "fix repairable problems and save the result")
+	dryRun := fs.Bool("dry-run", false, "show what --repair would change without saving")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	report, err := c.service.Doctor(ctx, task.DoctorOptions{
+		Repair: *repair || *dryRun,
+		DryRun: *dryRun,
+		Now:    c.clock(),
+	})
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if len(report.Issues) == 0 {
+		c.successf("No problems found\n")
+		return 0
+	}
+
+	for _, issue := range report.Issues {
+		fmt.Fprintln(c.stdout, issue)
+	}
+
+	if len(report.Fixes) > 0 {
+		verb := "Fixed"
+		if *dryRun {
+			verb = "Would fix"
+		}
+		fmt.Fprintln(c.stdout)
+		for _, fix := range report.Fixes {
+			fmt.Fprintf(c.stdout, "%s: %s\n", verb, fix)
+		}
+	}
+
+	switch {
+	case !*repair && !*dryRun:
+		c.errorf("%d problem(s) found, run 'task-cli doctor --repair' to fix them\n", len(report.Issues))
+		return 1
+	case len(report.Remaining) > 0:
+		c.errorf("%d problem(s) need manual attention\n", len(report.Remaining))
+		return 1
+	case *dryRun:
+		c.successf("Run 'task-cli doctor --repair' to apply these fixes\n")
+		return 0
+	default:
+		return 0
+	}
+}
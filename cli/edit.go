@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleEdit(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("edit")
+	where := fs.String("where", "", "change the tasks passing this filter expression")
+	var sets, addTags, removeTags []string
+	fs.Func("set", "field=value to change, repeat for several", func(s string) error {
+		sets = append(sets, s)
+		return nil
+	})
+	fs.Func("add-tag", "tag to add, repeat for several", func(tag string) error {
+		addTags = append(addTags, tag)
+		return nil
+	})
+	fs.Func("remove-tag", "tag to remove, repeat for several", func(tag string) error {
+		removeTags = append(removeTags, tag)
+		return nil
+	})
+	dryRun := fs.Bool("dry-run", false, "show the changes without saving them")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+	if *where == "" || len(sets)+len(addTags)+len(removeTags) == 0 {
+		c.errorf("Error: A filter and at least one change are required\n")
+		c.errorf("Usage: task-cli edit --where <filter> [--set field=value]... [--add-tag <tag>]... [--remove-tag <tag>]... [--dry-run]\n")
+		return 1
+	}
+
+	query, err := task.ParseQuery(*where, task.QueryEnv{
+		Now:      c.clock(),
+		Workflow: c.service.Workflow(),
+		User:     c.currentUser(),
+	})
+	if err != nil {
+		c.queryErrorf(err)
+		return 1
+	}
+	edits, err := c.parseFieldEdits(sets)
+	if err != nil {
+		return c.fail(err)
+	}
+	for _, tag := range addTags {
+		edits = append(edits, task.FieldEdit{Field: "tags", Op: "+=", Value: tag})
+	}
+	for _, tag := range removeTags {
+		edits = append(edits, task.FieldEdit{Field: "tags", Op: "-=", Value: tag})
+	}
+
+	changes, err := c.service.EditTasks(ctx, query.Match, edits, c.clock(), *dryRun)
+	if err != nil {
+		if c.fieldErrorf(err) {
+			c.errorf("Error: no task was changed\n")
+		}
+		return exitCode(err)
+	}
+
+	if *dryRun {
+		for _, change := range changes {
+			fmt.Fprintf(c.stdout, "~ #%d %s\n", change.Task.ID, change.Previous.Description)
+			for _, field := range change.Fields() {
+				if field.Field != "updatedAt" {
+					fmt.Fprintf(c.stdout, "    %s: %q -> %q\n", field.Field, field.Old, field.New)
+				}
+			}
+		}
+		c.successf(c.tr.Plural(len(changes), "%d tasks would be changed\n"), len(changes))
+		return 0
+	}
+	if c.quiet {
+		for _, change := range changes {
+			fmt.Fprintln(c.stdout, change.Task.ID)
+		}
+		return 0
+	}
+	c.successf(c.tr.Plural(len(changes), "%d tasks changed\n"), len(changes))
+	return 0
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Exit statuses a script can tell apart. Usage mistakes and errors without
+// a code, such as a network failure, exit with exitFailure.
+const (
+	exitFailure = 1
+	// exitInvalid is for input the command cannot accept
+	exitInvalid = 2
+	// exitNotFound is for a task or other item that does not exist
+	exitNotFound = 3
+	// exitRefused is for a change the current state of the tasks refuses,
+	// such as a WIP limit or a conflicting change
+	exitRefused = 4
+	// exitReadOnly is for a change to a read-only task file
+	exitReadOnly = 5
+)
+
+// exitCodes maps error codes to exit statuses; other codes exit with
+// exitFailure
+var exitCodes = map[string]int{
+	task.ErrInvalidStatus.Code:      exitInvalid,
+	task.ErrEmptyDescription.Code:   exitInvalid,
+	task.ErrDescriptionTooLong.Code: exitInvalid,
+	task.ErrInvalidPriority.Code:    exitInvalid,
+	task.ErrInvalidEstimate.Code:    exitInvalid,
+	task.ErrInvalidID.Code:          exitInvalid,
+	task.ErrAmbiguousID.Code:        exitInvalid,
+	task.ErrAmbiguousTask.Code:      exitInvalid,
+	task.ErrInvalidCheckItem.Code:   exitInvalid,
+	task.ErrInvalidAttachment.Code:  exitInvalid,
+	task.ErrInvalidRelation.Code:    exitInvalid,
+	task.ErrInvalidTag.Code:         exitInvalid,
+	task.ErrInvalidList.Code:        exitInvalid,
+	task.ErrInvalidProject.Code:     exitInvalid,
+	task.ErrInvalidMilestone.Code:   exitInvalid,
+	task.ErrInvalidSprint.Code:      exitInvalid,
+	task.ErrInvalidSprintDates.Code: exitInvalid,
+	task.ErrInvalidView.Code:        exitInvalid,
+	task.ErrInvalidSort.Code:        exitInvalid,
+	task.ErrInvalidSnapshot.Code:    exitInvalid,
+	task.ErrInvalidFormat.Code:      exitInvalid,
+	task.ErrInvalidColumn.Code:      exitInvalid,
+	task.ErrInvalidExport.Code:      exitInvalid,
+
+	task.ErrTaskNotFound.Code:       exitNotFound,
+	task.ErrCheckItemNotFound.Code:  exitNotFound,
+	task.ErrAttachmentNotFound.Code: exitNotFound,
+	task.ErrRelationNotFound.Code:   exitNotFound,
+	task.ErrTagNotFound.Code:        exitNotFound,
+	task.ErrProjectNotFound.Code:    exitNotFound,
+	task.ErrMilestoneNotFound.Code:  exitNotFound,
+	task.ErrSprintNotFound.Code:     exitNotFound,
+	task.ErrViewNotFound.Code:       exitNotFound,
+	task.ErrSnapshotNotFound.Code:   exitNotFound,
+
+	task.ErrChecklistOpen.Code:   exitRefused,
+	task.ErrWIPLimit.Code:        exitRefused,
+	task.ErrConflict.Code:        exitRefused,
+	task.ErrInvalidTasks.Code:    exitRefused,
+	task.ErrTagExists.Code:       exitRefused,
+	task.ErrProjectExists.Code:   exitRefused,
+	task.ErrProjectClosed.Code:   exitRefused,
+	task.ErrProjectOpenWork.Code: exitRefused,
+	task.ErrMilestoneExists.Code: exitRefused,
+	task.ErrSprintExists.Code:    exitRefused,
+	task.ErrSprintClosed.Code:    exitRefused,
+	task.ErrNoActiveSprint.Code:  exitRefused,
+	task.ErrNoNextSprint.Code:    exitRefused,
+	task.ErrSnapshotExists.Code:  exitRefused,
+	task.ErrIDTaken.Code:         exitRefused,
+	task.ErrHookRejected.Code:    exitRefused,
+
+	task.ErrReadOnly.Code: exitReadOnly,
+}
+
+// exitCode returns the exit status of a command failing with err
+func exitCode(err error) int {
+	var taskErr task.TaskError
+	if errors.As(err, &taskErr) {
+		if code, ok := exitCodes[taskErr.Code]; ok {
+			return code
+		}
+	}
+	return exitFailure
+}
+
+// printError prints err on stderr the same way for every command: the
+// message, the code of the TaskError it carries, if any, at the end of its
+// first line, then the hint on what to do about it
+func (c *CLI) printError(err error) {
+	var taskErr task.TaskError
+	if !errors.As(err, &taskErr) {
+		c.errorf("Error: %s\n", c.tr.Error(err))
+		return
+	}
+	first, rest, multiline := strings.Cut(c.tr.Error(err), "\n")
+	c.errorf("Error: %s [%s]\n", first, taskErr.Code)
+	if multiline {
+		c.errorf("%s\n", rest)
+	}
+	if taskErr.Hint != "" {
+		c.errorf("Hint: %s\n", c.tr.Text(taskErr.Hint))
+	}
+}
+
+// fail prints err and returns the exit status for it, for commands to
+// return
+func (c *CLI) fail(err error) int {
+	c.printError(err)
+	return exitCode(err)
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestExitCode tests the exit status of each kind of failure
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{errors.New("connection refused"), exitFailure},
+		{task.ErrStorage, exitFailure},
+		{task.ErrInvalidPriority.Withf("%q", "asap"), exitInvalid},
+		{fmt.Errorf("task 4: %w", task.ErrTaskNotFound), exitNotFound},
+		{task.ErrWIPLimit, exitRefused},
+		{task.ErrReadOnly, exitReadOnly},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestCLI_PrintError tests the code and hint printed with an error
+func TestCLI_PrintError(t *testing.T) {
+	var stderr bytes.Buffer
+	c := &CLI{stderr: &stderr}
+
+	c.printError(errors.New("connection refused"))
+	c.printError(task.ErrAmbiguousTask.Withf("%q\n  #1 A\n  #2 B", "a"))
+	want := "Error: connection refused\n" +
+		"Error: Several tasks match the description: \"a\" [AMBIGUOUS_TASK]\n  #1 A\n  #2 B\n" +
+		"Hint: use one of the task numbers listed\n"
+	if stderr.String() != want {
+		t.Errorf("stderr = %q, want %q", stderr.String(), want)
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleEstimate(ctx context.Context, args []string) int {
+	if len(args) < 2 {
+		c.errorf("Error: ID and estimate are required\n")
+		c.errorf("Usage: task-cli estimate <id> <duration|S|M|L|none>\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	var estimate time.Duration
+	if args[1] != "none" {
+		if estimate, err = task.ParseEffort(args[1]); err != nil {
+			return c.fail(err)
+		}
+	}
+
+	if err := c.service.SetTaskEstimate(ctx, id, estimate); err != nil {
+		return c.fail(err)
+	}
+
+	if estimate == 0 {
+		c.successf("Estimate cleared\n")
+	} else {
+		c.successf("Task estimated at %s\n", task.FormatDuration(estimate))
+	}
+	return 0
+}
+
+func (c *CLI) handleEstimates(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("estimates")
+	since := fs.String("since", "90d", "only tasks completed after this date or duration back")
+	weekly := fs.Bool("weekly", false, "show the estimated effort completed each week instead")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	from, err := parseSince(*since, c.clock())
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if *weekly {
+		return c.printWeeklyEffort(ctx, from)
+	}
+
+	all, byTag, err := c.service.EstimateReport(ctx, from)
+	if err != nil {
+		return c.fail(err)
+	}
+	if all.Tasks == 0 {
+		c.successf("No completed tasks with an estimate\n")
+		return 0
+	}
+
+	fmt.Fprintf(c.stdout, "%-16s %5s %10s %10s %6s\n", "Tag", "Tasks", "Estimated", "Actual", "Ratio")
+	for _, stats := range append(byTag, all) {
+		tag := stats.Tag
+		if tag == "" {
+			tag = "(all)"
+		}
+		line := fmt.Sprintf("%-16s %5d %10s %10s %5.1fx", tag, stats.Tasks,
+			task.FormatDuration(stats.Estimated), task.FormatDuration(stats.Actual), stats.Ratio())
+		if stats.Underestimated() {
+			line += "  underestimated"
+		}
+		fmt.Fprintln(c.stdout, line)
+	}
+	return 0
+}
+
+// printWeeklyEffort prints the estimated effort of the tasks completed each
+// week since from
+func (c *CLI) printWeeklyEffort(ctx context.Context, from time.Time) int {
+	weeks, err := c.service.WeeklyEffort(ctx, from, c.clock())
+	if err != nil {
+		return c.fail(err)
+	}
+
+	fmt.Fprintf(c.stdout, "%-10s %5s %10s\n", "Week", "Tasks", "Effort")
+	for _, week := range weeks {
+		effort := "-"
+		if week.Effort > 0 {
+			effort = task.FormatDuration(week.Effort)
+		}
+		fmt.Fprintf(c.stdout, "%-10s %5d %10s\n", week.Week.Format("2006-01-02"), week.Tasks, effort)
+	}
+	return 0
+}
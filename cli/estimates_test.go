@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_Estimate tests setting estimates and printing the report
+func TestCLI_Estimate(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	if code := h.run("add", "Plan trip", "--estimate", "1d2h"); code != 0 {
+		t.Fatalf("add exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	task, _ := h.repo.GetTask(4)
+	if time.Duration(task.Estimate) != 26*time.Hour {
+		t.Errorf("Estimate = %v, want 26h", time.Duration(task.Estimate))
+	}
+
+	if code := h.run("estimates"); code != 0 {
+		t.Fatalf("estimates exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if h.stdout.String() != "No completed tasks with an estimate\n" {
+		t.Errorf("stdout = %q, want no report before the task is done", h.stdout.String())
+	}
+
+	if code := h.run("mark-done", "4", "--force"); code != 0 {
+		t.Fatalf("mark-done exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("estimates"); code != 0 {
+		t.Fatalf("estimates exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if !strings.Contains(h.stdout.String(), "(all)                1       1d2h         0m   0.0x") {
+		t.Errorf("stdout = %q, want the report of task 4", h.stdout.String())
+	}
+
+	if code := h.run("estimate", "4", "none"); code != 0 {
+		t.Fatalf("estimate none exit code = %d", code)
+	}
+	if code := h.run("estimate", "3", "-1h"); code != exitInvalid {
+		t.Errorf("negative estimate exit code = %d, want %d", code, exitInvalid)
+	}
+}
+
+// TestCLI_MaxEffort tests finding quick wins with list and next
+func TestCLI_MaxEffort(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.run("estimate", "1", "S")
+	h.run("estimate", "2", "L")
+
+	if code := h.run("list", "--max-effort", "15m"); code != 0 {
+		t.Fatal(h.stderr)
+	}
+	if out := h.stdout.String(); !strings.Contains(out, "Buy groceries") || strings.Contains(out, "Write report") {
+		t.Errorf("list --max-effort 15m:\n%s", out)
+	}
+	// In progress work usually wins, but does not fit a quarter hour
+	if h.run("next", "--quick"); h.stdout.String() != "#1 Buy groceries\n" {
+		t.Errorf("next --quick = %q", h.stdout)
+	}
+}
@@ -0,0 +1,32 @@
+package cli_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alnah/task-tracker/cli"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+// Example runs task-cli commands from another program
+func Example() {
+	dir, err := os.MkdirTemp("", "tasks")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tasks := service.NewTaskService(repository.NewFileTaskRepository(filepath.Join(dir, "tasks.json")))
+	now := func() time.Time { return time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC) }
+	app := cli.NewCLI(tasks, os.Stdout, os.Stderr, now)
+	ctx := context.Background()
+	app.Run(ctx, []string{"task-cli", "add", "Buy milk"})
+	app.Run(ctx, []string{"task-cli", "list", "--format", "tsv"})
+	// Output:
+	// Task added successfully (ID: 1)
+	// ID	STATUS	PRIORITY	DUE	DESCRIPTION
+	// 1	todo			Buy milk
+}
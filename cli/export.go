@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleExport(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("export")
+	format := fs.String("format", "json", "output format: json, taskwarrior or todotxt")
+	output := fs.String("output", "", "file to write instead of stdout")
+	filters := c.listFlags(fs)
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	tasks, err := c.filteredTasks(ctx, *filters)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	var exported any
+	switch *format {
+	case "json":
+		if tasks == nil {
+			tasks = []task.Task{}
+		}
+		exported = service.NewExportEnvelope(tasks, viewFlags(*filters), c.clock())
+	case "taskwarrior":
+		exported = service.ToTaskwarrior(tasks)
+	case "todotxt":
+		var b strings.Builder
+		for _, task := range tasks {
+			b.WriteString(repository.FormatTodoTxt(task, time.Local) + "\n")
+		}
+		return c.writeExport([]byte(b.String()), *output, len(tasks))
+	default:
+		c.errorf("Error: Unsupported export format: %q\n", *format)
+		c.errorf("Usage: task-cli export [--format json|taskwarrior|todotxt] [--output <file>] [list filters]\n")
+		return 1
+	}
+
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.writeExport(append(data, '\n'), *output, len(tasks))
+}
+
+// filteredTasks returns the tasks the list filters select for commands that
+// copy tasks elsewhere. Unlike list, they include cancelled tasks unless a
+// status is asked for.
+func (c *CLI) filteredTasks(ctx context.Context, view task.View) ([]task.Task, error) {
+	if view.Status != "" && !c.service.Workflow().Has(task.TaskStatus(view.Status)) {
+		return nil, task.TaskError{
+			Code:    task.ErrInvalidStatus.Code,
+			Message: fmt.Sprintf("%s '%s'. Valid options: %s", task.ErrInvalidStatus.Message, view.Status, c.statusOptions()),
+		}
+	}
+	if view.Limit < 0 {
+		return nil, errors.New("--limit cannot be negative")
+	}
+	view.All = true
+	match, err := c.viewMatch(view)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := c.service.ListPage(ctx, repository.Page{}, match)
+	if err != nil {
+		return nil, err
+	}
+	if view.Sort != "" {
+		if err := task.SortTasks(tasks, view.Sort); err != nil {
+			return nil, err
+		}
+	}
+	return repository.PageTasks(tasks, repository.Page{Limit: view.Limit}, nil), nil
+}
+
+// writeExport writes exported data to stdout or to the output file
+func (c *CLI) writeExport(data []byte, output string, count int) int {
+	if output == "" {
+		c.stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(output, data, 0o644); err != nil {
+		return c.fail(err)
+	}
+	c.successf("Exported %d tasks to %s\n", count, output)
+	return 0
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_ExportJSON tests the envelope around exported tasks
+func TestCLI_ExportJSON(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	if code := h.run("export", "--status", "todo"); code != 0 {
+		t.Fatalf("export exit code = %d, stderr: %s", code, h.stderr)
+	}
+	var envelope service.ExportEnvelope
+	if err := json.Unmarshal(h.stdout.Bytes(), &envelope); err != nil {
+		t.Fatalf("export is not JSON: %v\n%s", err, h.stdout)
+	}
+
+	if envelope.Format != service.ExportFormatName || envelope.FormatVersion != service.ExportFormatVersion {
+		t.Errorf("format = %q version %d", envelope.Format, envelope.FormatVersion)
+	}
+	if envelope.ToolVersion != service.Version || envelope.SchemaVersion != repository.CurrentSchemaVersion {
+		t.Errorf("tool %q schema %d", envelope.ToolVersion, envelope.SchemaVersion)
+	}
+	if !envelope.ExportedAt.Equal(tasktest.FixedTime()) {
+		t.Errorf("exportedAt = %v, want %v", envelope.ExportedAt, tasktest.FixedTime())
+	}
+	if strings.Join(envelope.Filters, " ") != "--status todo" {
+		t.Errorf("filters = %q", envelope.Filters)
+	}
+	if envelope.Count != 1 || len(envelope.Tasks) != 1 || envelope.Tasks[0].Description != "Buy groceries" {
+		t.Fatalf("tasks = %+v, count %d", envelope.Tasks, envelope.Count)
+	}
+	if envelope.Tasks[0].UUID == "" {
+		t.Error("exported task has no UUID")
+	}
+
+	// Without filters every task is exported, cancelled ones included
+	cancelled := append(tasktest.FixedTasks(t), *tasktest.NewTaskBuilder().WithID(4).WithDescription("Old idea").
+		WithStatus(task.StatusCancelled).WithTimestamps(tasktest.FixedTime(), tasktest.FixedTime()).BuildInvalid())
+	h = newCLIHarness(t, cancelled)
+	if code := h.run("export"); code != 0 {
+		t.Fatalf("export exit code = %d, stderr: %s", code, h.stderr)
+	}
+	envelope = service.ExportEnvelope{}
+	if err := json.Unmarshal(h.stdout.Bytes(), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Count != 4 || envelope.Filters != nil {
+		t.Errorf("count = %d, filters = %q, want 4 and none", envelope.Count, envelope.Filters)
+	}
+
+	if code := h.run("export", "--status", "nope"); code != exitInvalid {
+		t.Errorf("invalid status exit code = %d, want %d", code, exitInvalid)
+	}
+}
+
+// TestCLI_ImportJSON tests importing an export back, renumbering tasks
+// whose ID is taken
+func TestCLI_ImportJSON(t *testing.T) {
+	source := newCLIHarness(t, tasktest.FixedTasks(t))
+	if code := source.run("export"); code != 0 {
+		t.Fatalf("export exit code = %d", code)
+	}
+	data := source.stdout.String()
+
+	// Task 2 holds a different task in the target list
+	later := tasktest.TimeAfter(tasktest.FixedTime())
+	target := newCLIHarness(t, []task.Task{
+		*tasktest.NewTaskBuilder().WithID(2).WithDescription("Water plants").
+			WithTimestamps(later, later).BuildInvalid(),
+	})
+	target.cli.WithStdin(strings.NewReader(data))
+	if code := target.run("import", "--format", "json", "--dry-run"); code != 0 {
+		t.Fatalf("dry run exit code = %d, stderr: %s", code, target.stderr)
+	}
+	if target.repo.TaskCount() != 1 {
+		t.Errorf("dry run saved tasks")
+	}
+
+	target.cli.WithStdin(strings.NewReader(data))
+	if code := target.run("import", "--format", "json"); code != 0 {
+		t.Fatalf("import exit code = %d, stderr: %s", code, target.stderr)
+	}
+	want := "Imported task 1: Buy groceries\n" +
+		"Imported task 3: Call mom\n" +
+		"Imported task 2 as 4: Write report\n" +
+		"3 to import, 0 duplicates skipped\n"
+	if target.stdout.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", target.stdout, want)
+	}
+	if item, ok := target.repo.GetTask(4); !ok || item.Description != "Write report" || item.Status != task.StatusInProgress {
+		t.Errorf("task 4 = %+v", item)
+	}
+	if task, _ := target.repo.GetTask(2); task.Description != "Water plants" {
+		t.Errorf("task 2 was replaced by %q", task.Description)
+	}
+
+	// Importing again finds every task by UUID, whatever its new ID
+	target.cli.WithStdin(strings.NewReader(data))
+	if code := target.run("import", "--format", "json"); code != 0 {
+		t.Fatalf("second import exit code = %d", code)
+	}
+	if target.repo.TaskCount() != 4 || !strings.Contains(target.stdout.String(), "3 duplicates skipped") {
+		t.Errorf("second import duplicated tasks: %s", target.stdout)
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_Set tests changing several fields in one command, and that one
+// refused field leaves the task unchanged
+func TestCLI_Set(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].Tags = []string{"shopping", "old"}
+	h := newCLIHarness(t, tasks)
+
+	code := h.run("set", "1", "description=Buy milk", "priority=high", "due=2024-01-05",
+		"tags+=errand", "tags-=old", "estimate=30m")
+	if code != 0 {
+		t.Fatalf("set exit code = %d, stderr: %s", code, h.stderr)
+	}
+	item, _ := h.repo.GetTask(1)
+	if item.Description != "Buy milk" || item.Priority != task.PriorityHigh || item.Estimate != task.Duration(30*time.Minute) {
+		t.Errorf("task = %+v", item)
+	}
+	if item.DueAt == nil || item.DueAt.Format("2006-01-02") != "2024-01-05" {
+		t.Errorf("due = %v, want 2024-01-05", item.DueAt)
+	}
+	if !slices.Equal(item.Tags, []string{"shopping", "errand"}) {
+		t.Errorf("tags = %v, want [shopping errand]", item.Tags)
+	}
+
+	code = h.run("set", "1", "description=Changed", "priority=asap", "due=someday", "color=red")
+	if code != exitInvalid {
+		t.Fatalf("invalid set exit code = %d, want %d", code, exitInvalid)
+	}
+	for _, want := range []string{"Error: priority: ", "Error: due: ", "Error: color: unknown field", "task 1 was not changed"} {
+		if !strings.Contains(h.stderr.String(), want) {
+			t.Errorf("stderr missing %q:\n%s", want, h.stderr)
+		}
+	}
+	if task, _ := h.repo.GetTask(1); task.Description != "Buy milk" {
+		t.Errorf("description = %q after a refused set, want it unchanged", task.Description)
+	}
+}
+
+// TestCLI_Edit tests changing every task passing a filter, with a preview
+// first
+func TestCLI_Edit(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)
+	for i := range tasks {
+		tasks[i].Tags = []string{"sprint1"}
+	}
+	h := newCLIHarness(t, tasks)
+
+	code := h.run("edit", "--where", "tag:sprint1 && status != done", "--set", "priority=high",
+		"--add-tag", "carryover", "--dry-run")
+	if code != 0 {
+		t.Fatalf("dry run exit code = %d, stderr: %s", code, h.stderr)
+	}
+	want := "~ #1 Buy groceries\n" +
+		"    priority: \"\" -> \"high\"\n" +
+		"    tags: \"sprint1\" -> \"sprint1,carryover\"\n" +
+		"~ #2 Write report\n" +
+		"    priority: \"\" -> \"high\"\n" +
+		"    tags: \"sprint1\" -> \"sprint1,carryover\"\n" +
+		"2 tasks would be changed\n"
+	if got := h.stdout.String(); got != want {
+		t.Errorf("dry run output = %q, want %q", got, want)
+	}
+	if item, _ := h.repo.GetTask(1); item.Priority != task.PriorityNone {
+		t.Fatal("dry run saved the changes")
+	}
+
+	if code := h.run("edit", "--where", "tag:sprint1", "--set", "priority=asap"); code != exitInvalid ||
+		!strings.Contains(h.stderr.String(), "task 3: priority: ") {
+		t.Errorf("invalid edit = %d, stderr: %s", code, h.stderr)
+	}
+
+	h.run("edit", "--where", "tag:sprint1 && status != done", "--set", "priority=high", "--add-tag", "carryover")
+	if out := h.stdout.String(); out != "2 tasks changed\n" {
+		t.Errorf("edit output = %q", out)
+	}
+	for id, want := range map[int]task.Priority{1: task.PriorityHigh, 2: task.PriorityHigh, 3: task.PriorityNone} {
+		if task, _ := h.repo.GetTask(id); task.Priority != want {
+			t.Errorf("task %d priority = %q, want %q", id, task.Priority, want)
+		}
+	}
+
+	h.run("edit", "--where", "tag:sprint1 && status != done", "--set", "priority=high")
+	if out := h.stdout.String(); out != "0 tasks changed\n" {
+		t.Errorf("repeated edit output = %q, want nothing left to change", out)
+	}
+}
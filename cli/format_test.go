@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_ListFormat tests the quickfix and template formats of list
+func TestCLI_ListFormat(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.config.Formats = map[string]string{"short": "{{.ID}}:{{.Status | upper}}"}
+
+	// The mock cannot tell where tasks are, so the ID stands in for the line
+	if code := h.run("list", "todo", "--format", "quickfix"); code != 0 {
+		t.Fatalf("list --format quickfix exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if out := h.stdout.String(); out != "task-cli:1: #1 [TODO] Buy groceries\n" {
+		t.Errorf("quickfix output = %q", out)
+	}
+
+	h.run("list", "--format", `{{.ID}} {{.Description}}{{if .DueAt}} {{date "2006-01-02" .DueAt}}{{end}}`)
+	if out := h.stdout.String(); out != "1 Buy groceries\n2 Write report\n3 Call mom\n" {
+		t.Errorf("template output = %q", out)
+	}
+	h.run("list", "--format", "short", "--sort", "-id")
+	if out := h.stdout.String(); out != "3:DONE\n2:IN-PROGRESS\n1:TODO\n" {
+		t.Errorf("named format output = %q", out)
+	}
+
+	for _, format := range []string{"xml", "{{.Nope}}", "{{.ID"} {
+		if code := h.run("list", "--format", format); code != exitInvalid || !strings.Contains(h.stderr.String(), "Invalid output format") {
+			t.Errorf("list --format %q exit code = %d, stderr = %q", format, code, h.stderr.String())
+		}
+	}
+}
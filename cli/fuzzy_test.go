@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_Pick tests picking tasks for commands given no ID
+func TestCLI_Pick(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	prompts := &bytes.Buffer{}
+	h.cli.WithPrompter(NewLinePrompter(strings.NewReader("groc\n\n"), prompts))
+
+	if code := h.run("mark-done"); code != 0 {
+		t.Fatalf("mark-done exit code = %d, stderr: %s", code, h.stderr)
+	}
+	if item, _ := h.repo.GetTask(1); item.Status != task.StatusDone {
+		t.Errorf("task 1 status = %s, want done after searching and picking it", item.Status)
+	}
+	if !strings.Contains(h.stderr.String(), "1. #1 Buy groceries (TODO)\n  2. #2 Write report") {
+		t.Errorf("picker did not list the open tasks:\n%s", h.stderr)
+	}
+
+	h.cli.WithPrompter(NewLinePrompter(strings.NewReader("1-2\n"), prompts))
+	h.run("add", "Call the bank")
+	if code := h.run("delete", "--yes"); code != 0 {
+		t.Fatalf("delete exit code = %d, stderr: %s", code, h.stderr)
+	}
+	if out := h.stdout.String(); out != "2 tasks deleted successfully\n" {
+		t.Errorf("delete output = %q", out)
+	}
+
+	h.run("add", "Pay rent")
+	h.cli.WithPrompter(NewLinePrompter(strings.NewReader(""), prompts))
+	if code := h.run("show"); code != 1 || !strings.Contains(h.stderr.String(), "no task picked") {
+		t.Errorf("show with no answer = %d, stderr: %s", code, h.stderr)
+	}
+	h.cli.WithPrompter(nil)
+	if code := h.run("show"); code != 1 || !strings.Contains(h.stderr.String(), "ID is required") {
+		t.Errorf("show without a prompter = %d, stderr: %s", code, h.stderr)
+	}
+}
+
+// TestCLI_PickLive tests the picker narrowing matches down at each key
+func TestCLI_PickLive(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)[:2]
+	tasks = append(tasks, *tasktest.NewTaskBuilder().WithID(4).WithDescription("Write tests").BuildValid(t))
+
+	tests := []struct {
+		name  string
+		keys  string
+		multi bool
+		want  []string
+	}{
+		{name: "search", keys: "groc\r", want: []string{"1"}},
+		{name: "backspace", keys: "writx\x7f tes\r", want: []string{"4"}},
+		{name: "arrow keys", keys: "wr\x1b[A\r", want: []string{"4"}},
+		{name: "ctrl keys", keys: "\x10\x10\x0e\r", want: []string{"2"}},
+		{name: "clear", keys: "zzz\x15\r", want: []string{"1"}},
+		{name: "no match waits for more keys", keys: "zzz\r\x7f\x7f\x7fcall\x15rent\x15groc\r", want: []string{"1"}},
+		{name: "tab picks several", keys: "\t\t\r", multi: true, want: []string{"1", "2"}},
+		{name: "tab again unpicks", keys: "\t\x0e\t\r", multi: true, want: []string{"2"}},
+		{name: "other escape sequences are ignored", keys: "groc\x1b[3~\r", want: []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newCLIHarness(t, nil)
+			ids, err := h.cli.pickLive(bufio.NewReader(strings.NewReader(tt.keys)), tasks, tt.multi)
+			if err != nil || strings.Join(ids, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("pickLive(%q) = %v, %v; want %v", tt.keys, ids, err, tt.want)
+			}
+		})
+	}
+
+	t.Run("draws matches as the search changes", func(t *testing.T) {
+		h := newCLIHarness(t, nil)
+		h.cli.pickLive(bufio.NewReader(strings.NewReader("wr\r")), tasks, false)
+		if out := h.stderr.String(); !strings.Contains(out, "  #4 Write tests (TODO)\n> #2 Write report (IN-PROGRESS)\n  2/3\n> wr") {
+			t.Errorf("picker output = %q", out)
+		}
+		if out := h.stderr.String(); !strings.HasSuffix(out, "\r\x1b[J") {
+			t.Errorf("picker output = %q, want the picker erased", out)
+		}
+	})
+
+	for _, keys := range []string{"\x1b", "gr\x03", ""} {
+		h := newCLIHarness(t, nil)
+		if _, err := h.cli.pickLive(bufio.NewReader(strings.NewReader(keys)), tasks, false); !errors.Is(err, errNothingPick) {
+			t.Errorf("pickLive(%q) error = %v, want %v", keys, err, errNothingPick)
+		}
+	}
+}
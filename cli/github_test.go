@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+// fakeGitHub serves a minimal issues API for one repository
+type fakeGitHub struct {
+	mu     sync.Mutex
+	issues []service.GitHubIssue
+	labels map[int]string
+	token  string
+}
+
+func newFakeGitHub(t *testing.T, titles ...string) (*fakeGitHub, *httptest.Server) {
+	t.Helper()
+
+	gh := &fakeGitHub{labels: make(map[int]string), token: "gh-token"}
+	for _, title := range titles {
+		gh.add(title, "tracker")
+	}
+	// Pull requests are listed by the issues endpoint and must be skipped
+	gh.issues = append(gh.issues, service.GitHubIssue{
+		Number: 99, Title: "A pull request", State: "open", PullRequest: &struct{}{},
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/app/issues", gh.list)
+	mux.HandleFunc("POST /repos/octo/app/issues", gh.create)
+	mux.HandleFunc("PATCH /repos/octo/app/issues/{number}", gh.patch)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+gh.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "Bad credentials"}`))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return gh, server
+}
+
+func (gh *fakeGitHub) add(title, label string) service.GitHubIssue {
+	issue := service.GitHubIssue{Number: len(gh.issues) + 1, Title: title, State: "open", CreatedAt: tasktest.FixedTime()}
+	gh.issues = append(gh.issues, issue)
+	gh.labels[issue.Number] = label
+	return issue
+}
+
+func (gh *fakeGitHub) state(number int) string {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	for _, issue := range gh.issues {
+		if issue.Number == number {
+			return issue.State
+		}
+	}
+	return ""
+}
+
+func (gh *fakeGitHub) list(w http.ResponseWriter, r *http.Request) {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+
+	label := r.URL.Query().Get("labels")
+	issues := []service.GitHubIssue{}
+	for _, issue := range gh.issues {
+		if issue.State == "open" && (label == "" || gh.labels[issue.Number] == label || issue.PullRequest != nil) {
+			issues = append(issues, issue)
+		}
+	}
+	json.NewEncoder(w).Encode(issues)
+}
+
+func (gh *fakeGitHub) create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Title  string   `json:"title"`
+		Labels []string `json:"labels"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	issue := gh.add(req.Title, strings.Join(req.Labels, ","))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(issue)
+}
+
+func (gh *fakeGitHub) patch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		State string `json:"state"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	number, _ := strconv.Atoi(r.PathValue("number"))
+
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	for i := range gh.issues {
+		if gh.issues[i].Number == number {
+			gh.issues[i].State = req.State
+			json.NewEncoder(w).Encode(gh.issues[i])
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// TestCLI_GitHub tests the import and push commands
+func TestCLI_GitHub(t *testing.T) {
+	_, server := newFakeGitHub(t, "Fix login")
+
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	config := repository.DefaultConfig()
+	config.GitHub = repository.GitHubConfig{APIURL: server.URL, Token: "gh-token", Label: "tracker"}
+	h.cli.WithConfig(config)
+
+	if code := h.run("import", "github", "--repo", "octo/app"); code != 0 {
+		t.Fatalf("import exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if !strings.Contains(h.stdout.String(), "Imported octo/app#1 as task 4: Fix login") {
+		t.Errorf("import output = %q", h.stdout.String())
+	}
+
+	if code := h.run("push", "github", "--repo", "octo/app", "--dry-run", "1"); code != 0 {
+		t.Fatalf("push exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if h.stdout.String() != "Would create an issue for task 1: Buy groceries\n" {
+		t.Errorf("push output = %q", h.stdout.String())
+	}
+
+	if code := h.run("import", "github"); code != 1 || !strings.Contains(h.stderr.String(), "--repo is required") {
+		t.Errorf("import without repo exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+
+	config.GitHub.Token = "wrong"
+	h.cli.WithConfig(config)
+	if code := h.run("import", "github", "--repo", "octo/app"); code != 1 ||
+		!strings.Contains(h.stderr.String(), "Bad credentials") {
+		t.Errorf("bad token exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+}
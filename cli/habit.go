@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleHabit(ctx context.Context, args []string) int {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		if args[0] != "list" {
+			c.errorf("Unknown habit command: %s\n", args[0])
+			c.errorf("Usage: task-cli habit list [--weeks <n>]\n")
+			return 1
+		}
+		args = args[1:]
+	}
+
+	fs := c.newFlagSet("habit list")
+	weeks := fs.Int("weeks", 12, "weeks of history in the calendar")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+	if *weeks < 1 {
+		c.errorf("Error: --weeks must be at least 1\n")
+		return 1
+	}
+
+	schedules, err := c.schedules()
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(schedules) == 0 {
+		c.successf("No schedules configured\n")
+		return 0
+	}
+
+	habits, err := c.service.Habits(ctx, schedules, *weeks, c.clock())
+	if err != nil {
+		return c.fail(err)
+	}
+	for i, habit := range habits {
+		if i > 0 {
+			fmt.Fprintln(c.stdout)
+		}
+		c.printHabit(habit, *weeks)
+	}
+	return 0
+}
+
+// printHabit writes a habit's streaks and its calendar, one row per weekday
+// and one column per week: ■ done, □ missed, · nothing scheduled
+func (c *CLI) printHabit(habit task.Habit, weeks int) {
+	if c.isAccessible() {
+		scheduled, done := 0, 0
+		for _, day := range habit.Days {
+			if day.Scheduled {
+				scheduled++
+			}
+			if day.Done {
+				done++
+			}
+		}
+		fmt.Fprintln(c.stdout, c.tr.Sprintf("Habit: %s", habit.Name))
+		fmt.Fprintln(c.stdout, c.tr.Sprintf("Current streak: %d", habit.Current))
+		fmt.Fprintln(c.stdout, c.tr.Sprintf("Best streak: %d", habit.Best))
+		fmt.Fprintln(c.stdout, c.tr.Sprintf("Done on %d of %d scheduled days in the last %d weeks", done, scheduled, weeks))
+		return
+	}
+
+	fmt.Fprintln(c.stdout, c.tr.Sprintf("%s  current streak %d, best %d", habit.Name, habit.Current, habit.Best))
+	rows := make([]strings.Builder, 7)
+	for i := range rows {
+		rows[i].WriteString("  " + time.Weekday((i + 1) % 7).String()[:3] + " ")
+	}
+	for i, day := range habit.Days {
+		cell := "·"
+		switch {
+		case day.Done:
+			cell = "■"
+		case day.Scheduled:
+			cell = "□"
+		}
+		rows[i%7].WriteString(cell)
+	}
+	for _, row := range rows {
+		fmt.Fprintln(c.stdout, strings.TrimRight(row.String(), " "))
+	}
+}
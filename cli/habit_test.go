@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_Habit tests that completions survive deleting their tasks
+func TestCLI_Habit(t *testing.T) {
+	var tasks []task.Task
+	for i, occurrence := range []time.Time{
+		time.Date(2023, time.December, 30, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, time.December, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC),
+	} {
+		item := tasktest.NewTaskBuilder().WithID(i+1).WithDescription("Stretch").
+			WithStatus(task.StatusDone).WithTimestamps(occurrence, occurrence).BuildInvalid()
+		item.ExternalRefs = map[string]string{task.ScheduleRefSystem: task.ScheduleRef("stretch", occurrence)}
+		tasks = append(tasks, *item)
+	}
+	tasks[2].Status = task.StatusTodo
+
+	h := newCLIHarness(t, tasks)
+	h.cli.WithConfig(repository.Config{Schedules: []repository.ScheduleConfig{{Name: "stretch", Cron: "0 9 * * *", Description: "Stretch"}}})
+	h.cli.service.WithHabits(repository.NewFileHabitLog(filepath.Join(t.TempDir(), "tasks.habits.jsonl")))
+
+	if code := h.run("habit", "--weeks", "1"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	if !strings.HasPrefix(h.stdout.String(), "stretch  current streak 2, best 2\n  Mon □\n  Tue\n") {
+		t.Errorf("output = %q", h.stdout)
+	}
+
+	h.run("mark-done", "3")
+	h.run("delete", "1", "2", "3", "--yes")
+	if code := h.run("habit", "list", "--weeks", "1"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	// Tasks 1 and 2 were done before the log existed, so only task 3 counts
+	if !strings.HasPrefix(h.stdout.String(), "stretch  current streak 1, best 1\n  Mon ■\n") {
+		t.Errorf("output after deleting tasks = %q", h.stdout)
+	}
+}
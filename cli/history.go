@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleHistory(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		c.errorf("Error: ID is required\n")
+		c.errorf("Usage: task-cli history <id>\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	entries, err := c.service.TaskHistory(ctx, id)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	fmt.Fprintf(c.stdout, "History of task %d:\n", id)
+	for _, entry := range entries {
+		fmt.Fprintf(c.stdout, "%-16s  %.7s  %s\n",
+			c.formatTime(entry.Time.Local(), "2006-01-02 15:04"), entry.Revision, entry.Message)
+
+		switch entry.Change.Type {
+		case task.ChangeAdded:
+			fmt.Fprintf(c.stdout, "    created %q\n", entry.Change.Task.Description)
+		case task.ChangeDeleted:
+			fmt.Fprintln(c.stdout, "    deleted")
+		default:
+			for _, field := range entry.Change.Fields() {
+				if field.Field == "updatedAt" {
+					continue
+				}
+				fmt.Fprintf(c.stdout, "    %s %q -> %q\n", field.Field, field.Old, field.New)
+			}
+		}
+	}
+
+	return 0
+}
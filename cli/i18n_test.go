@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_Language tests that commands print through the translator
+func TestCLI_Language(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	fr, _ := task.NewTranslator("fr")
+	h.cli.WithTranslator(fr)
+
+	h.run("summary")
+	if out := h.stdout.String(); out != "1 à faire, 1 en cours, 1 terminée, 0 en retard\n" {
+		t.Errorf("summary output = %q", out)
+	}
+	h.run("list", "todo")
+	if out := h.stdout.String(); !strings.Contains(out, "N° : 1 | Statut : À FAIRE | Description : Buy groceries") {
+		t.Errorf("list output = %q", out)
+	}
+	h.run("delete", "9")
+	if errOut := h.stderr.String(); errOut != "Erreur : Tâche introuvable: 9 [NOT_FOUND]\nAstuce : lancez 'task-cli list --all' pour voir les numéros des tâches\n" {
+		t.Errorf("delete stderr = %q", errOut)
+	}
+	h.run("delete", "1", "2", "--yes")
+	if out := h.stdout.String(); out != "2 tâches supprimées\n" {
+		t.Errorf("delete output = %q", out)
+	}
+	h.run("help")
+	if out := h.stdout.String(); !strings.Contains(out, "Options globales :") {
+		t.Errorf("usage output = %q, want French headings", out)
+	}
+
+	// Descriptions are user data and print as they were typed
+	h.run("add", "Error")
+	h.run("add", "Warning: Task not found")
+	h.run("list")
+	if out := h.stdout.String(); !strings.Contains(out, "Description : Error\n") || !strings.Contains(out, "Description : Warning: Task not found\n") {
+		t.Errorf("list output = %q, want the descriptions untranslated", out)
+	}
+}
+
+// TestCLI_CatalogsComplete tests that every message the CLI passes to the
+// translator as a literal has a translation in each catalog
+func TestCLI_CatalogsComplete(t *testing.T) {
+	messages := literalMessages(t)
+	for _, lang := range task.Languages() {
+		if lang == task.DefaultLanguage {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join("..", "task", "locales", lang+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var catalog task.Catalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			t.Fatalf("%s: %v", lang, err)
+		}
+		for _, message := range messages {
+			if _, ok := catalog.Messages[message]; !ok {
+				t.Errorf("%s has no translation of %q", lang, message)
+			}
+		}
+	}
+}
+
+// formatVerb matches the verbs of a format string, such as %s or %-20s
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.*]*[a-zA-Z%]`)
+
+// literalMessages collects the string literals the CLI sources pass to
+// errorf, successf, Text, Sprintf and field, trailing newlines left out
+func literalMessages(t *testing.T) []string {
+	t.Helper()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	var messages []string
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				// The field helper of the accessible output
+				if fun.Name != "field" {
+					return true
+				}
+			case *ast.SelectorExpr:
+				switch fun.Sel.Name {
+				case "errorf", "successf", "Text":
+				case "Sprintf":
+					// Only the translator's Sprintf, not fmt's
+					if inner, ok := fun.X.(*ast.SelectorExpr); !ok || inner.Sel.Name != "tr" {
+						return true
+					}
+				default:
+					return true
+				}
+			default:
+				return true
+			}
+			literal, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				return true
+			}
+			message, err := strconv.Unquote(literal.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			message = strings.TrimRight(message, "\n")
+			if !strings.ContainsFunc(formatVerb.ReplaceAllString(message, ""), unicode.IsLetter) || seen[message] {
+				return true
+			}
+			seen[message] = true
+			messages = append(messages, message)
+			return true
+		})
+	}
+	return messages
+}
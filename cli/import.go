@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alnah/task-tracker/service"
+)
+
+func (c *CLI) handleImport(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		c.errorf("Error: Import source is required\n")
+		c.errorf("Usage: task-cli import github|todoist|ticktick|jira [flags]\n")
+		return 1
+	}
+	if strings.HasPrefix(args[0], "-") {
+		return c.handleImportFormat(ctx, args)
+	}
+
+	switch args[0] {
+	case "github":
+		return c.handleImportGitHub(ctx, args[1:])
+	case "todoist":
+		return c.handleImportTodoist(ctx, args[1:])
+	case "ticktick":
+		return c.handleImportTickTick(ctx, args[1:])
+	case "jira":
+		return c.handleImportJira(ctx, args[1:])
+	default:
+		c.errorf("Unknown import source: %s\n", args[0])
+		c.errorf("Usage: task-cli import github|todoist|ticktick|jira [flags]\n")
+		return 1
+	}
+}
+
+func (c *CLI) handlePush(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		c.errorf("Error: Push target is required\n")
+		c.errorf("Usage: task-cli push github --repo owner/name [--label <label>] [--dry-run] [ids...]\n")
+		return 1
+	}
+
+	switch args[0] {
+	case "github":
+		return c.handlePushGitHub(ctx, args[1:])
+	default:
+		c.errorf("Unknown push target: %s\n", args[0])
+		c.errorf("Usage: task-cli push github --repo owner/name [--label <label>] [--dry-run] [ids...]\n")
+		return 1
+	}
+}
+
+func (c *CLI) handleImportTodoist(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("import todoist")
+	token := fs.String("token", c.config.Todoist.Token, "API token, used unless --csv is given")
+	csvFile := fs.String("csv", "", "project exported as CSV instead of using the API")
+	project := fs.String("project", "", "tag for tasks from --csv (default: file name)")
+	dryRun := fs.Bool("dry-run", false, "show what would be imported without saving")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	var source service.TaskSource = service.NewTodoistAPI(c.config.Todoist.APIURL, *token, c.clock())
+	if *csvFile != "" {
+		f, err := os.Open(*csvFile)
+		if err != nil {
+			return c.fail(err)
+		}
+		defer f.Close()
+
+		if *project == "" {
+			*project = strings.TrimSuffix(filepath.Base(*csvFile), filepath.Ext(*csvFile))
+		}
+		source = service.NewTodoistCSV(f, *project, c.clock())
+	}
+
+	return c.importTasks(ctx, source, *dryRun)
+}
+
+func (c *CLI) handleImportTickTick(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("import ticktick")
+	csvFile := fs.String("csv", "", "backup CSV from TickTick settings")
+	dryRun := fs.Bool("dry-run", false, "show what would be imported without saving")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	if *csvFile == "" {
+		c.errorf("Error: --csv is required\n")
+		c.errorf("Usage: task-cli import ticktick --csv backup.csv [--dry-run]\n")
+		return 1
+	}
+
+	f, err := os.Open(*csvFile)
+	if err != nil {
+		return c.fail(err)
+	}
+	defer f.Close()
+
+	return c.importTasks(ctx, service.NewTickTickCSV(f), *dryRun)
+}
+
+func (c *CLI) handleImportJira(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("import jira")
+	jql := fs.String("jql", c.config.Jira.JQL, "JQL query selecting the issues")
+	keepKey := fs.Bool("keep-key", c.config.Jira.KeepKey, "record the issue key in each task")
+	dryRun := fs.Bool("dry-run", false, "show what would be imported without saving")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	if *jql == "" {
+		c.errorf("Error: --jql is required (or set jira.jql in the config file)\n")
+		c.errorf("Usage: task-cli import jira --jql <query> [--keep-key] [--dry-run]\n")
+		return 1
+	}
+
+	return c.importTasks(ctx, service.NewJiraSearch(c.config.Jira, *jql, *keepKey, c.clock()), *dryRun)
+}
+
+// handleImportFormat imports a file written by export or another tool's
+// export
+func (c *CLI) handleImportFormat(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("import")
+	format := fs.String("format", "", "format of the file: json or taskwarrior")
+	dryRun := fs.Bool("dry-run", false, "show what would be imported without saving")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	if *format != "json" && *format != "taskwarrior" {
+		c.errorf("Error: Unsupported import format: %q\n", *format)
+		c.errorf("Usage: task-cli import --format json|taskwarrior [<file>|-] [--dry-run]\n")
+		return 1
+	}
+
+	in := c.stdin
+	if len(positional) > 0 && positional[0] != "-" {
+		f, err := os.Open(positional[0])
+		if err != nil {
+			return c.fail(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	if in == nil {
+		in = strings.NewReader("")
+	}
+
+	if *format == "json" {
+		return c.importExport(ctx, in, *dryRun)
+	}
+	return c.importTasks(ctx, service.NewTaskwarriorSource(in), *dryRun)
+}
+
+// importExport imports a file written by export --format json
+func (c *CLI) importExport(ctx context.Context, in io.Reader, dryRun bool) int {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return c.fail(err)
+	}
+	envelope, err := service.DecodeExport(data, c.service.Workflow())
+	if err != nil {
+		return c.fail(err)
+	}
+	report, err := c.service.ImportExport(ctx, envelope, dryRun)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	for _, task := range report.Added {
+		from, renumbered := report.Renumbered[task.ID]
+		switch {
+		case renumbered && dryRun:
+			c.successf("Would import task %d as %d: %s\n", from, task.ID, task.Description)
+		case renumbered:
+			c.successf("Imported task %d as %d: %s\n", from, task.ID, task.Description)
+		case dryRun:
+			c.successf("Would import task %d: %s\n", task.ID, task.Description)
+		default:
+			c.successf("Imported task %d: %s\n", task.ID, task.Description)
+		}
+	}
+	for _, task := range report.Duplicates {
+		c.successf("Skipped duplicate: %s\n", task.Description)
+	}
+	c.successf("%d to import, %d duplicates skipped\n", len(report.Added), len(report.Duplicates))
+	if dryRun {
+		c.successf("Dry run, nothing was saved\n")
+	}
+	return 0
+}
+
+func (c *CLI) importTasks(ctx context.Context, source service.TaskSource, dryRun bool) int {
+	report, err := c.service.ImportTasks(ctx, source, c.clock(), dryRun)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	for _, task := range report.Added {
+		if dryRun {
+			c.successf("Would import task %d: %s\n", task.ID, task.Description)
+		} else {
+			c.successf("Imported task %d: %s\n", task.ID, task.Description)
+		}
+	}
+	for _, item := range report.Duplicates {
+		c.successf("Skipped duplicate: %s\n", item.Description)
+	}
+	c.successf("%d to import, %d duplicates skipped\n", len(report.Added), len(report.Duplicates))
+	if dryRun {
+		c.successf("Dry run, nothing was saved\n")
+	}
+	return 0
+}
+
+func (c *CLI) handleImportGitHub(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("import github")
+	newTracker := c.githubFlags(fs)
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	tracker, ok := newTracker()
+	if !ok {
+		return 1
+	}
+
+	report, err := c.service.ImportIssues(ctx, tracker)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	for _, task := range report.Added {
+		c.successf("Imported %s as task %d: %s\n",
+			task.ExternalRefs[tracker.Name()], task.ID, task.Description)
+	}
+	for _, task := range report.Updated {
+		c.successf("Updated task %d from %s: %s\n",
+			task.ID, task.ExternalRefs[tracker.Name()], task.Description)
+	}
+	c.successf("%d imported, %d updated\n", len(report.Added), len(report.Updated))
+	return 0
+}
+
+func (c *CLI) handlePushGitHub(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("push github")
+	newTracker := c.githubFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "show what would change without touching GitHub")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	var ids []int
+	for _, arg := range args {
+		id, err := c.service.ResolveTaskID(ctx, arg)
+		if err != nil {
+			return c.fail(err)
+		}
+		ids = append(ids, id)
+	}
+
+	tracker, ok := newTracker()
+	if !ok {
+		return 1
+	}
+
+	report, err := c.service.PushIssues(ctx, tracker, ids, *dryRun)
+	if report != nil {
+		c.printPushReport(tracker, report, *dryRun)
+	}
+	if err != nil {
+		return c.fail(err)
+	}
+	return 0
+}
+
+// githubFlags registers --repo and --label and returns a constructor for the
+// tracker they describe, to be called after parsing
+func (c *CLI) githubFlags(fs *flag.FlagSet) func() (service.IssueTracker, bool) {
+	repo := fs.String("repo", c.config.GitHub.Repo, "repository as owner/name")
+	label := fs.String("label", c.config.GitHub.Label, "only issues with this label")
+
+	return func() (service.IssueTracker, bool) {
+		if *repo == "" {
+			c.errorf("Error: --repo is required (or set github.repo in the config file)\n")
+			return nil, false
+		}
+
+		tracker, err := service.NewGitHubTracker(c.config.GitHub.APIURL, *repo, *label, c.config.GitHub.Token)
+		if err != nil {
+			c.printError(err)
+			return nil, false
+		}
+		return tracker, true
+	}
+}
+
+func (c *CLI) printPushReport(tracker service.IssueTracker, report *service.PushReport, dryRun bool) {
+	if len(report.Created)+len(report.Closed)+len(report.Reopened) == 0 {
+		c.successf("Nothing to push\n")
+		return
+	}
+
+	for _, task := range report.Created {
+		if dryRun {
+			c.successf("Would create an issue for task %d: %s\n", task.ID, task.Description)
+		} else {
+			c.successf("Created %s for task %d: %s\n",
+				task.ExternalRefs[tracker.Name()], task.ID, task.Description)
+		}
+	}
+	for _, task := range report.Closed {
+		if dryRun {
+			c.successf("Would close %s (task %d is done)\n", task.ExternalRefs[tracker.Name()], task.ID)
+		} else {
+			c.successf("Closed %s (task %d is done)\n", task.ExternalRefs[tracker.Name()], task.ID)
+		}
+	}
+	for _, task := range report.Reopened {
+		if dryRun {
+			c.successf("Would reopen %s (task %d is %s)\n", task.ExternalRefs[tracker.Name()], task.ID, c.tr.Status(task.Status))
+		} else {
+			c.successf("Reopened %s (task %d is %s)\n", task.ExternalRefs[tracker.Name()], task.ID, c.tr.Status(task.Status))
+		}
+	}
+}
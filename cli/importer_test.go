@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+const todoistCSVExport = `TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE
+section,Errands,,,,,,,,
+task,Buy groceries,,1,1,Me (1),,2024-01-05,en,Europe/Paris
+task,Water plants,,4,1,Me (1),,every monday,en,Europe/Paris
+note,Remember the list,,,,,,,,
+task,Call mom,,3,1,Me (1),,,en,Europe/Paris
+`
+
+// TestCLI_ImportTodoist tests the CSV import preview
+func TestCLI_ImportTodoist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Errands.csv")
+	if err := os.WriteFile(path, []byte(todoistCSVExport), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	if code := h.run("import", "todoist", "--csv", path, "--dry-run"); code != 0 {
+		t.Fatalf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+
+	// The export has no creation dates, so matching descriptions are duplicates
+	want := "Would import task 4: Water plants\n" +
+		"Skipped duplicate: Buy groceries\nSkipped duplicate: Call mom\n" +
+		"1 to import, 2 duplicates skipped\nDry run, nothing was saved\n"
+	if h.stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", h.stdout.String(), want)
+	}
+
+	if code := h.run("import", "ticktick"); code != 1 {
+		t.Errorf("ticktick without --csv exit code = %d, want 1", code)
+	}
+}
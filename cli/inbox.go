@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+// handleIn captures a task into the inbox with no options to think about
+func (c *CLI) handleIn(ctx context.Context, args []string) int {
+	description := strings.Join(args, " ")
+	task, err := c.service.AddTask(ctx, description, task.WithTags(service.InboxTag))
+	if err != nil {
+		return c.fail(err)
+	}
+	c.successf("Captured task %d\n", task.ID)
+	return 0
+}
+
+// triageQuestion is asked for every inbox task
+const triageQuestion = "Status, priority:<level>, due:<when>, +tag; Enter to skip, d to delete, q to quit:"
+
+// handleTriage walks through the inbox, asking what to do with each task
+func (c *CLI) handleTriage(ctx context.Context, args []string) int {
+	if len(args) > 0 {
+		c.errorf("Usage: task-cli triage\n")
+		return 1
+	}
+	if c.prompter == nil {
+		c.errorf("Error: triage needs a terminal to ask questions on\n")
+		return 1
+	}
+
+	tasks, err := c.service.Inbox(ctx)
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(tasks) == 0 {
+		c.successf("Inbox is empty\n")
+		return 0
+	}
+
+	triaged, deleted := 0, 0
+tasks:
+	for _, task := range tasks {
+		fmt.Fprintf(c.stdout, "#%d %s\n", task.ID, task.Description)
+		for {
+			answer, err := c.prompter.Ask(triageQuestion)
+			if errors.Is(err, io.EOF) {
+				break tasks
+			}
+			if err != nil {
+				return c.fail(err)
+			}
+
+			switch strings.ToLower(answer) {
+			case "", "s", "skip":
+				continue tasks
+			case "q", "quit":
+				break tasks
+			case "d", "delete":
+				err = c.service.DeleteTask(ctx, task.ID)
+				if err == nil {
+					deleted++
+				}
+			default:
+				var triage service.Triage
+				if triage, err = c.parseTriage(answer, c.clock()); err == nil {
+					err = c.service.TriageTask(ctx, task.ID, triage)
+				}
+				if err == nil {
+					triaged++
+				}
+			}
+			if err == nil {
+				continue tasks
+			}
+			// Ask again about the same task
+			c.printError(err)
+		}
+	}
+
+	c.successf("Inbox: %d triaged, %d deleted, %d left\n", triaged, deleted, len(tasks)-triaged-deleted)
+	return 0
+}
+
+// parseTriage reads a triage answer: a status of the workflow,
+// priority:<level>, due:<when> and +tag words, in any order
+func (c *CLI) parseTriage(answer string, now time.Time) (service.Triage, error) {
+	var triage service.Triage
+	for _, word := range strings.Fields(answer) {
+		key, value, _ := strings.Cut(word, ":")
+		switch {
+		case strings.HasPrefix(word, "+") && len(word) > 1:
+			triage.Tags = append(triage.Tags, word[1:])
+		case key == "priority" || key == "p":
+			triage.Priority = task.Priority(strings.ToLower(value))
+			if triage.Priority == task.PriorityNone || !triage.Priority.IsValid() {
+				return service.Triage{}, fmt.Errorf("%w: %q", task.ErrInvalidPriority, value)
+			}
+		case key == "due":
+			due, err := task.ParseDeadline(value, now)
+			if err != nil {
+				return service.Triage{}, err
+			}
+			triage.Due = &due
+		case c.service.Workflow().Has(task.TaskStatus(word)):
+			triage.Status = task.TaskStatus(word)
+		default:
+			return service.Triage{}, fmt.Errorf("unknown triage word %q, expected a status (%s), priority:, due: or +tag",
+				word, c.statusOptions())
+		}
+	}
+	return triage, nil
+}
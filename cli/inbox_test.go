@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_InTriage tests capturing into the inbox and triaging it
+func TestCLI_InTriage(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	for _, thought := range []string{"Renew passport", "Maybe learn Go", "Fix the sink"} {
+		if code := h.run("in", thought); code != 0 {
+			t.Fatalf("in exit code = %d, stderr: %s", code, h.stderr)
+		}
+	}
+	if h.stdout.String() != "Captured task 6\n" {
+		t.Errorf("output = %q", h.stdout)
+	}
+
+	prompts := &bytes.Buffer{}
+	answers := "in-progress priority:high due:2024-01-05 +admin\n" +
+		"d\n" +
+		"sooner\n" + // not understood, asked again
+		"\n"
+	h.cli.WithPrompter(NewLinePrompter(strings.NewReader(answers), prompts))
+	if code := h.run("triage"); code != 0 {
+		t.Fatalf("triage exit code = %d, stderr: %s", code, h.stderr)
+	}
+	if !strings.HasSuffix(h.stdout.String(), "Inbox: 1 triaged, 1 deleted, 1 left\n") {
+		t.Errorf("output = %q", h.stdout)
+	}
+	if !strings.Contains(h.stderr.String(), `unknown triage word "sooner"`) {
+		t.Errorf("stderr = %q", h.stderr)
+	}
+
+	item, _ := h.repo.GetTask(4)
+	if item.Status != task.StatusInProgress || item.Priority != task.PriorityHigh || item.DueAt == nil ||
+		!slices.Equal(item.Tags, []string{"admin"}) {
+		t.Errorf("triaged task = %+v", item)
+	}
+	if _, ok := h.repo.GetTask(5); ok {
+		t.Error("task 5 still exists after d")
+	}
+	if task, _ := h.repo.GetTask(6); !slices.Contains(task.Tags, service.InboxTag) {
+		t.Errorf("skipped task tags = %v, want it left in the inbox", task.Tags)
+	}
+}
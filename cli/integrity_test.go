@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_Doctor tests the doctor command output and exit codes
+func TestCLI_Doctor(t *testing.T) {
+	t.Run("healthy store", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		if code := h.run("doctor"); code != 0 {
+			t.Errorf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if h.stdout.String() != "No problems found\n" {
+			t.Errorf("stdout = %q", h.stdout.String())
+		}
+	})
+
+	t.Run("problems found", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.BrokenTasks())
+		if code := h.run("doctor"); code != 1 {
+			t.Errorf("Run() exit code = %d, want 1", code)
+		}
+		if !strings.Contains(h.stdout.String(), "[duplicate-id] task 1: ID is used by 2 tasks") {
+			t.Errorf("stdout = %q", h.stdout.String())
+		}
+		if !strings.Contains(h.stderr.String(), "doctor --repair") {
+			t.Errorf("stderr = %q, want repair hint", h.stderr.String())
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.BrokenTasks())
+		if code := h.run("doctor", "--dry-run"); code != 0 {
+			t.Errorf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if !strings.Contains(h.stdout.String(), "Would fix: [duplicate-id] task 1: renumbered \"Duplicate\" to 5") {
+			t.Errorf("stdout = %q", h.stdout.String())
+		}
+		if h.repo.SaveCallCount() != 0 {
+			t.Error("dry run saved tasks")
+		}
+	})
+
+	t.Run("repair", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.BrokenTasks())
+		if code := h.run("doctor", "--repair"); code != 0 {
+			t.Errorf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if code := h.run("doctor"); code != 0 {
+			t.Errorf("doctor after repair exit code = %d, stdout = %q", code, h.stdout.String())
+		}
+	})
+}
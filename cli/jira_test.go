@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+func TestCLI_ImportJira(t *testing.T) {
+	server := tasktest.NewFakeJira(t)
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithConfig(repository.Config{Jira: repository.JiraConfig{URL: server.URL, Email: "me@example.com", Token: "secret"}})
+
+	if code := h.run("import", "jira"); code != 1 {
+		t.Errorf("without --jql exit code = %d, want 1", code)
+	}
+	if code := h.run("import", "jira", "--jql", "assignee = currentUser()", "--keep-key"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	want := "Imported task 4: Rotate certificates\n" +
+		"Imported task 5: Write report\n" +
+		"Imported task 6: Upgrade database\n" +
+		"3 to import, 0 duplicates skipped\n"
+	if h.stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", h.stdout, want)
+	}
+	if task, _ := h.repo.GetTask(4); task.ExternalRefs[service.JiraRefSystem] != "OPS-1" {
+		t.Errorf("task 4 refs = %v", task.ExternalRefs)
+	}
+
+	// Issue keys identify the tasks on the next import
+	if code := h.run("import", "jira", "--jql", "assignee = currentUser()", "--keep-key"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	if got := h.stdout.String(); !strings.HasSuffix(got, "0 to import, 3 duplicates skipped\n") {
+		t.Errorf("re-import output = %q", got)
+	}
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleLink(ctx context.Context, args []string) int {
+	if len(args) != 3 {
+		c.errorf("Error: Two IDs and a relation are required\n")
+		c.errorf("Usage: task-cli link <id> duplicates|relates-to <id>\n")
+		return 1
+	}
+
+	kind, err := task.ParseRelationType(args[1])
+	if err != nil {
+		return c.fail(err)
+	}
+	a, b, err := c.resolveTaskPair(ctx, args[0], args[2])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if err := c.service.LinkTasks(ctx, a, kind, b); err != nil {
+		return c.fail(err)
+	}
+	c.successf("Task %d %s task %d\n", a, kind, b)
+	return 0
+}
+
+func (c *CLI) handleUnlink(ctx context.Context, args []string) int {
+	if len(args) != 2 {
+		c.errorf("Error: Two IDs are required\n")
+		c.errorf("Usage: task-cli unlink <id> <id>\n")
+		return 1
+	}
+
+	a, b, err := c.resolveTaskPair(ctx, args[0], args[1])
+	if err != nil {
+		return c.fail(err)
+	}
+	if err := c.service.UnlinkTasks(ctx, a, b); err != nil {
+		return c.fail(err)
+	}
+	c.successf("Tasks %d and %d unlinked\n", a, b)
+	return 0
+}
+
+// resolveTaskPair resolves the two tasks a relation is between
+func (c *CLI) resolveTaskPair(ctx context.Context, a, b string) (int, int, error) {
+	first, err := c.service.ResolveTaskID(ctx, a)
+	if err != nil {
+		return 0, 0, err
+	}
+	second, err := c.service.ResolveTaskID(ctx, b)
+	if err != nil {
+		return 0, 0, err
+	}
+	return first, second, nil
+}
+
+// printRelations lists the tasks related to task, with their description
+// when they can still be read
+func (c *CLI) printRelations(ctx context.Context, task task.Task) {
+	if len(task.Relations) == 0 {
+		return
+	}
+	fmt.Fprintln(c.stdout, "Relations:")
+	for _, relation := range task.Relations {
+		line := fmt.Sprintf("  %s #%d", relation.Type, relation.Task)
+		if other, err := c.service.GetTask(ctx, relation.Task); err == nil {
+			line += fmt.Sprintf(" %s (%s)", other.Description, other.Status)
+		}
+		fmt.Fprintln(c.stdout, line)
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+)
+
+// fileCommands work on the whole task file, or serve it, so they see the
+// tasks of every list and do not take --list
+var fileCommands = map[string]bool{
+	"list-of-lists": true,
+	"doctor":        true,
+	"sync":          true,
+	"backup":        true,
+	"snapshot":      true,
+	"diff":          true,
+	"storage":       true,
+	"export":        true,
+	"split":         true,
+	"watch":         true,
+	"serve":         true,
+	"mcp":           true,
+	"daemon":        true,
+	"notify":        true,
+	"digest":        true,
+}
+
+// enterList narrows the service to the list named by --list, else the
+// default list, until the returned function restores it
+func (c *CLI) enterList(command string) (func(), error) {
+	if fileCommands[c.aliasedCommand(command)] {
+		if c.list != "" {
+			return nil, fmt.Errorf("--list does not apply to %s, which covers every list", command)
+		}
+		return func() {}, nil
+	}
+
+	name := c.list
+	if name == "" {
+		name = c.service.DefaultList()
+	}
+	service := c.service
+	scoped, err := service.InList(name)
+	if err != nil {
+		return nil, err
+	}
+	c.service = scoped
+	return func() { c.service = service }, nil
+}
+
+// currentList names the list commands work on
+func (c *CLI) currentList() string {
+	if c.list != "" {
+		return c.list
+	}
+	return c.service.DefaultList()
+}
+
+func (c *CLI) handleListOfLists(ctx context.Context, args []string) int {
+	if len(args) > 0 {
+		c.errorf("Error: list-of-lists takes no arguments\n")
+		c.errorf("Usage: task-cli list-of-lists\n")
+		return 1
+	}
+
+	lists, err := c.service.Lists(ctx)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	width := 0
+	for _, list := range lists {
+		width = max(width, len(list.Name))
+	}
+	current := c.currentList()
+	for _, list := range lists {
+		if c.quiet {
+			fmt.Fprintln(c.stdout, list.Name)
+			continue
+		}
+		mark := " "
+		if list.Name == current {
+			mark = "*"
+		}
+		fmt.Fprintf(c.stdout, "%s %-*s  %s, %d open\n", mark, width, list.Name,
+			c.tr.Sprintf(c.tr.Plural(list.Total, "%d tasks"), list.Total), list.Open)
+	}
+	return 0
+}
+
+// moveToList moves the task named by ref out of the current list
+func (c *CLI) moveToList(ctx context.Context, ref, list string) int {
+	id, err := c.service.ResolveTaskID(ctx, ref)
+	if err != nil {
+		return c.fail(err)
+	}
+	if err := c.service.MoveToList(ctx, id, list); err != nil {
+		return c.fail(err)
+	}
+	c.successf("Task moved to list %s\n", list)
+	return 0
+}
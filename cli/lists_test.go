@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_Lists tests --list before and after the command, move --to and
+// list-of-lists
+func TestCLI_Lists(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	if code := h.run("--list", "groceries", "add", "Milk"); code != 0 {
+		t.Fatalf("add exit code = %d, stderr: %s", code, h.stderr)
+	}
+	h.run("list", "--list", "groceries")
+	if !strings.Contains(h.stdout.String(), "Milk") || strings.Contains(h.stdout.String(), "Buy groceries") {
+		t.Errorf("list --list groceries:\n%s", h.stdout)
+	}
+	if h.run("list"); strings.Contains(h.stdout.String(), "Milk") {
+		t.Errorf("default list shows other lists:\n%s", h.stdout)
+	}
+
+	if code := h.run("move", "1", "--to", "groceries"); code != 0 || h.stdout.String() != "Task moved to list groceries\n" {
+		t.Errorf("move --to = %d, %q, stderr: %s", code, h.stdout, h.stderr)
+	}
+	if h.run("mark-done", "1"); !strings.Contains(h.stderr.String(), "Task not found") {
+		t.Errorf("mark-done of a task in another list: %q", h.stderr)
+	}
+
+	h.run("list-of-lists")
+	if want := "* default    2 tasks, 1 open\n  groceries  2 tasks, 2 open\n"; h.stdout.String() != want {
+		t.Errorf("list-of-lists = %q, want %q", h.stdout, want)
+	}
+	if code := h.run("--list", "groceries", "doctor"); code != 1 {
+		t.Errorf("--list with doctor exit code = %d, want 1", code)
+	}
+}
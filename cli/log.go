@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleLog(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("log")
+	since := fs.String("since", "", "only show changes after this time, e.g. yesterday or 2024-06-01")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	var filter repository.AuditFilter
+	if len(args) > 0 {
+		filter.TaskID, err = c.service.ResolveTaskID(ctx, args[0])
+		if err != nil {
+			return c.fail(err)
+		}
+	}
+	if *since != "" {
+		filter.Since, err = task.ParseWhen(*since, c.clock())
+		if err != nil {
+			return c.fail(err)
+		}
+	}
+
+	events, err := c.service.AuditTrail(ctx, filter)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if len(events) == 0 {
+		c.successf("No changes recorded\n")
+		return 0
+	}
+
+	for _, event := range events {
+		fmt.Fprintf(c.stdout, "%-19s  %-10s  %s\n",
+			c.formatTime(event.Time.Local(), "2006-01-02 15:04:05"), event.Actor, event)
+		for _, change := range event.Changes {
+			fmt.Fprintf(c.stdout, "    %s %q -> %q\n", change.Field, change.Old, change.New)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Main runs task-cli on the command line args, with the stores, hooks and
+// settings of the user's config file, and returns the exit status
+func Main(args []string) int {
+	config, configFile, err := loadUserConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		return 1
+	}
+
+	fileFlag, args, err := ExtractDataFile(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		return 1
+	}
+
+	// Dependency injection
+	dataFile, explicit := repository.ResolveDataFile(fileFlag, config)
+	backups := repository.NewBackupManager(dataFile, config.Backup.Dir, config.Backup.Keep)
+	var repo repository.TaskRepository
+	// localFile is the task file on this machine, checked for write
+	// permission; remote stores have none
+	localFile := dataFile
+	// storeFile is the task file handed to plugins, only when it holds the
+	// tasks: a store chosen by the config is found again through the config
+	storeFile := dataFile
+	if explicit && strings.EqualFold(filepath.Ext(dataFile), ".txt") {
+		repo = repository.NewTodoTxtRepository(dataFile)
+		backups = nil
+	} else if explicit && strings.EqualFold(filepath.Ext(dataFile), ".jsonl") {
+		repo = repository.NewEventLogRepository(dataFile)
+		backups = nil
+	} else if explicit && strings.EqualFold(filepath.Ext(dataFile), ".db") {
+		repo = repository.NewBoltTaskRepository(dataFile)
+		backups = nil
+	} else if config.Remote.URL != "" && !explicit {
+		repo, err = repository.OpenRepository(config.Remote.URL, config)
+		localFile = ""
+		storeFile = ""
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+			return 1
+		}
+		// Backups only cover the local data file
+		backups = nil
+	} else if config.S3.Bucket != "" && !explicit {
+		repo = repository.NewS3TaskRepository(config.S3)
+		localFile = ""
+		storeFile = ""
+		backups = nil
+	} else if config.WebDAV.URL != "" && !explicit {
+		repo = repository.NewWebDAVTaskRepository(config.WebDAV)
+		localFile = ""
+		storeFile = ""
+		backups = nil
+	} else if config.TodoTxt.File != "" && !explicit {
+		repo = repository.NewTodoTxtRepository(config.TodoTxt.File)
+		localFile = config.TodoTxt.File
+		storeFile = ""
+		backups = nil
+	} else if config.EventLog.File != "" && !explicit {
+		eventLog := repository.NewEventLogRepository(config.EventLog.File)
+		localFile = config.EventLog.File
+		storeFile = ""
+		if config.EventLog.CompactAfter > 0 {
+			eventLog.WithCompactAfter(config.EventLog.CompactAfter)
+		}
+		repo = eventLog
+		backups = nil
+	} else if config.Bolt.File != "" && !explicit {
+		repo = repository.NewBoltTaskRepository(config.Bolt.File)
+		localFile = config.Bolt.File
+		storeFile = ""
+		backups = nil
+	} else {
+		fileRepo := repository.NewFileTaskRepository(dataFile)
+		if config.Backup.Enabled {
+			fileRepo.WithBackups(backups)
+		}
+		repo = fileRepo
+		if config.Git.Enabled {
+			repo = repository.NewGitTaskRepository(fileRepo)
+		}
+	}
+	store := localFile
+	if named, ok := repository.RepositoryAs[fmt.Stringer](repo); ok {
+		store = named.String()
+	}
+	workflow, err := task.NewWorkflow(config.Workflow.Statuses, config.Workflow.Transitions)
+	if err == nil {
+		workflow, err = workflow.WithLimits(config.Workflow.WIPLimits)
+		workflow.RequireChecklist = config.Workflow.RequireChecklist
+		workflow.KeepDuplicatesOpen = config.Workflow.KeepDuplicatesOpen
+		workflow.Descriptions = task.DescriptionRules{
+			CollapseWhitespace:       config.Descriptions.CollapseWhitespace,
+			Abbreviations:            config.Descriptions.Abbreviations,
+			StripTrailingPunctuation: config.Descriptions.StripTrailingPunctuation,
+			Capitalize:               config.Descriptions.Capitalize,
+			MaxLength:                config.Descriptions.MaxLength,
+			Truncate:                 config.Descriptions.TooLong == "truncate",
+		}
+		switch config.Descriptions.TooLong {
+		case "", "error", "truncate":
+		default:
+			err = fmt.Errorf("descriptions.tooLong must be error or truncate, not %q", config.Descriptions.TooLong)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid workflow in config: %s\n", err.Error())
+		return 1
+	}
+	// Retaining tasks between units only matters to daemon --socket, which
+	// runs one unit per request
+	cache := repository.NewCachingRepository(repo).Retain()
+	svc := service.NewTaskService(cache).
+		WithWorkflow(workflow).
+		WithProjects(repository.NewFileProjectRepository(repository.SidecarFile(dataFile, "projects", ".json"))).
+		WithMilestones(repository.NewFileMilestoneRepository(repository.SidecarFile(dataFile, "milestones", ".json"))).
+		WithSprints(repository.NewFileSprintRepository(repository.SidecarFile(dataFile, "sprints", ".json"))).
+		WithViews(repository.NewFileViewRepository(repository.SidecarFile(dataFile, "views", ".json"))).
+		WithSnapshots(repository.NewFileSnapshotRepository(repository.SidecarFile(dataFile, "snapshots", ""))).
+		WithHabits(repository.NewFileHabitLog(repository.SidecarFile(dataFile, "habits", ".jsonl"))).
+		WithDefaultList(config.Lists.Default).
+		WithReadOnly(config.ReadOnly || service.FileReadOnly(localFile))
+	if config.Audit.Enabled {
+		auditFile := config.Audit.File
+		if auditFile == "" {
+			auditFile = repository.SidecarFile(dataFile, "audit", ".jsonl")
+		}
+		svc.WithAuditLog(repository.NewFileAuditLog(auditFile))
+	}
+	if len(config.Hooks.Scripts) > 0 || len(config.Hooks.Commands) > 0 {
+		hooks, err := service.LoadHooks(config.Hooks, os.Stderr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid hooks in config: %s\n", err.Error())
+			return 1
+		}
+		svc.WithHooks(hooks)
+	}
+	translator, err := task.NewTranslator(task.DetectLanguage(config.Language, os.Getenv))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		return 1
+	}
+	cli := NewCLI(svc, os.Stdout, os.Stderr, time.Now).
+		WithConfig(config).
+		WithConfigFile(configFile).
+		WithStdin(os.Stdin).
+		WithPrompter(NewLinePrompter(os.Stdin, os.Stderr)).
+		WithTranslator(translator).
+		WithNotifier(service.NewDesktopNotifier()).
+		WithOpener(service.NewSystemOpener()).
+		WithMailer(service.NewSMTPMailer(config.SMTP)).
+		WithBackups(backups).
+		WithSyncDir(".task-sync").
+		WithScheduleFile(repository.SidecarFile(dataFile, "schedules", ".json")).
+		WithContextFile(repository.SidecarFile(dataFile, "context", "")).
+		WithSocketFile(repository.SidecarFile(dataFile, "daemon", ".sock")).
+		WithDataFile(storeFile).
+		WithStore(store).
+		WithUnitOfWork(cache)
+
+	// Cancel in-flight operations on Ctrl+C
+	actor := config.User
+	if actor == "" {
+		actor = task.CurrentActor()
+	}
+	ctx, stop := signal.NotifyContext(task.WithActor(context.Background(), actor), os.Interrupt)
+	code := cli.Run(ctx, args)
+	stop()
+
+	return code
+}
+
+// loadUserConfig reads the config file from its default location and
+// returns it with its path
+func loadUserConfig() (repository.Config, string, error) {
+	path, err := repository.DefaultConfigPath()
+	if err != nil {
+		return repository.DefaultConfig(), "", err
+	}
+	config, err := repository.LoadConfig(path)
+	if err != nil {
+		return config, path, err
+	}
+
+	// Secrets may come from the environment instead of the file
+	if config.GitHub.Token == "" {
+		config.GitHub.Token = os.Getenv("GITHUB_TOKEN")
+	}
+	if config.Todoist.Token == "" {
+		config.Todoist.Token = os.Getenv("TODOIST_TOKEN")
+	}
+	if config.Jira.Token == "" {
+		config.Jira.Token = os.Getenv("JIRA_API_TOKEN")
+	}
+	if config.SMTP.Password == "" {
+		config.SMTP.Password = os.Getenv("TASK_CLI_SMTP_PASSWORD")
+	}
+	if config.S3.AccessKeyID == "" {
+		config.S3.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		config.S3.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		config.S3.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if config.WebDAV.Password == "" {
+		config.WebDAV.Password = os.Getenv("TASK_CLI_WEBDAV_PASSWORD")
+	}
+	return config, path, nil
+}
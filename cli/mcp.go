@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alnah/task-tracker/service"
+)
+
+// handleMCP serves the tasks to an AI assistant over the Model Context
+// Protocol on stdin and stdout, until the assistant closes stdin
+func (c *CLI) handleMCP(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("mcp")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	// The assistant starts this command itself, so nothing but protocol
+	// messages may reach stdout
+	server := service.NewMCPServer(c.service, c.clock, c.currentUser()).
+		WithDestructive(c.config.MCP.AllowDestructive)
+	stdin := c.stdin
+	if stdin == nil {
+		stdin = strings.NewReader("")
+	}
+	if err := server.Serve(ctx, stdin, c.stdout); err != nil {
+		return c.fail(err)
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+func TestCLI_MCP(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithStdin(strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"))
+	if code := h.run("mcp"); code != 0 {
+		t.Fatalf("exit code = %d, stderr = %q", code, h.stderr)
+	}
+	if got, want := h.stdout.String(), `{"jsonrpc":"2.0","id":1,"result":{}}`+"\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+}
+
+// TestCLI_MCPWritesThrough tests that each tool call is saved at once, so
+// other processes see it while the session is still open
+func TestCLI_MCPWritesThrough(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tasks.json")
+	cache := repository.NewCachingRepository(repository.NewFileTaskRepository(file))
+	stdinReader, stdin := io.Pipe()
+	stdoutReader, stdout := io.Pipe()
+	cli := NewCLI(service.NewTaskService(cache), stdout, &bytes.Buffer{}, tasktest.FixedTime).
+		WithUnitOfWork(cache).
+		WithStdin(stdinReader)
+
+	exited := make(chan int)
+	go func() { exited <- cli.Run(context.Background(), []string{"task-cli", "mcp"}) }()
+
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"add_task","arguments":{"description":"Plan trip"}}}` + "\n"
+	go io.WriteString(stdin, call)
+	if _, err := bufio.NewReader(stdoutReader).ReadString('\n'); err != nil {
+		t.Fatalf("reading the response: %v", err)
+	}
+
+	tasks, err := repository.NewFileTaskRepository(file).Load(context.Background())
+	if err != nil || len(tasks) != 1 || tasks[0].Description != "Plan trip" {
+		t.Errorf("another repository loaded %v, %v; want the task added through MCP", tasks, err)
+	}
+
+	stdin.Close()
+	if code := <-exited; code != 0 {
+		t.Errorf("exit code = %d", code)
+	}
+}
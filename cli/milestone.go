@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleMilestone(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{"status"}
+	}
+
+	switch args[0] {
+	case "create":
+		return c.handleMilestoneCreate(ctx, args[1:])
+
+	case "status":
+		return c.handleMilestoneStatus(ctx)
+
+	case "set":
+		if len(args) < 3 {
+			c.errorf("Error: ID and milestone are required\n")
+			c.errorf("Usage: task-cli milestone set <id> <name|none>\n")
+			return 1
+		}
+		id, err := c.service.ResolveTaskID(ctx, args[1])
+		if err != nil {
+			return c.fail(err)
+		}
+		name := args[2]
+		if name == "none" {
+			name = ""
+		}
+		if err := c.service.SetTaskMilestone(ctx, id, name); err != nil {
+			return c.fail(err)
+		}
+		if name == "" {
+			c.successf("Task %d removed from its milestone\n", id)
+		} else {
+			c.successf("Task %d planned for %s\n", id, name)
+		}
+		return 0
+
+	default:
+		c.errorf("Error: Unknown milestone command '%s'\n", args[0])
+		c.errorf("Usage: task-cli milestone create|status|set\n")
+		return 1
+	}
+}
+
+func (c *CLI) handleMilestoneCreate(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("milestone create")
+	due := fs.String("due", "", "target date, e.g. \"next friday\" or 2024-06-01")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) == 0 || *due == "" {
+		c.errorf("Error: Name and target date are required\n")
+		c.errorf("Usage: task-cli milestone create <name> --due <when>\n")
+		return 1
+	}
+
+	target, err := task.ParseDeadline(*due, c.clock())
+	if err != nil {
+		return c.fail(err)
+	}
+	milestone, err := c.service.CreateMilestone(ctx, args[0], target)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	c.successf("Milestone %s created, due %s\n", milestone.Name,
+		c.formatTime(milestone.DueAt, "2006-01-02 15:04"))
+	return 0
+}
+
+func (c *CLI) handleMilestoneStatus(ctx context.Context) int {
+	now := c.clock()
+	report, err := c.service.MilestoneStatus(ctx, now)
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(report) == 0 {
+		c.successf("No milestones found\n")
+		return 0
+	}
+
+	for i, milestone := range report {
+		if i > 0 {
+			fmt.Fprintln(c.stdout)
+		}
+		line := fmt.Sprintf("%s  due %s  %d/%d done (%d%%)", milestone.Name,
+			c.formatTime(milestone.DueAt, "2006-01-02 15:04"),
+			milestone.Done, milestone.Total, milestone.Percent())
+		if milestone.Overdue(now) {
+			line += "  OVERDUE"
+		}
+		fmt.Fprintln(c.stdout, line)
+		if len(milestone.AtRisk) > 0 {
+			fmt.Fprintln(c.stdout, "  At risk:")
+		}
+		for _, risky := range milestone.AtRisk {
+			fmt.Fprintf(c.stdout, "    #%d %s (%s)\n", risky.ID, risky.Description, risky.Reason)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+)
+
+// TestCLI_Milestone tests creating milestones, planning tasks and the report
+func TestCLI_Milestone(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.service.WithMilestones(repository.NewFileMilestoneRepository(filepath.Join(t.TempDir(), "milestones.json")))
+
+	if code := h.run("milestone", "create", "v1.0", "--due", "2024-01-03"); code != 0 {
+		t.Fatalf("create exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("milestone", "create", "v1.0", "--due", "2024-02-01"); code != exitRefused {
+		t.Errorf("duplicate create exit code = %d, want %d", code, exitRefused)
+	}
+	if code := h.run("add", "Write changelog", "--milestone", "v1.0"); code != 0 {
+		t.Fatalf("add exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("add", "Stray", "--milestone", "v9"); code != exitNotFound ||
+		!strings.Contains(h.stderr.String(), "Milestone not found: v9") {
+		t.Errorf("add to missing milestone = %d, stderr = %q", code, h.stderr.String())
+	}
+	for _, id := range []string{"2", "3"} {
+		if code := h.run("milestone", "set", id, "v1.0"); code != 0 {
+			t.Fatalf("set exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+	}
+
+	h.run("list", "--milestone", "v1.0")
+	if out := h.stdout.String(); strings.Contains(out, "Buy groceries") || !strings.Contains(out, "Milestone: v1.0") {
+		t.Errorf("list --milestone output = %q", out)
+	}
+
+	h.run("milestone", "status")
+	want := "v1.0  due 2024-01-03 23:59  1/3 done (33%)\n" +
+		"  At risk:\n" +
+		"    #4 Write changelog (not started)\n"
+	if got := h.stdout.String(); got != want {
+		t.Errorf("status output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleMove(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("move")
+	force := fs.Bool("force", false, "ignore the workflow's transition rules and WIP limits")
+	to := fs.String("to", "", "move the task to this list instead of changing its status")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	if *to != "" && len(args) == 1 {
+		return c.moveToList(ctx, args[0], *to)
+	}
+	if len(args) < 2 || *to != "" {
+		c.errorf("Error: ID and status, or ID and --to, are required\n")
+		c.errorf("Usage: task-cli move <id> <status> [--force] | move <id> --to <list>\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	status := task.TaskStatus(args[1])
+	if !c.service.Workflow().Has(status) {
+		c.errorf("Error: Invalid status '%s'. Valid options: %s\n", status, c.statusOptions())
+		return 1
+	}
+
+	if err := c.moveTask(ctx, id, status, *force); err != nil {
+		return c.fail(err)
+	}
+
+	c.successf("Task moved to %s\n", status)
+	return 0
+}
+
+func (c *CLI) handleCancel(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("cancel")
+	reason := fs.String("reason", "", "why the task will not be done, kept as a note")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	if len(args) == 0 {
+		if args, err = c.pickArgs(ctx, false); err != nil {
+			c.printError(err)
+			c.errorf("Usage: task-cli cancel <id> [--reason \"Why\"]\n")
+			return 1
+		}
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if err := c.service.CancelTask(ctx, id, *reason); err != nil {
+		return c.fail(err)
+	}
+
+	c.successf("Task cancelled\n")
+	return 0
+}
+
+// statusOptions lists the statuses of the workflow for error messages
+func (c *CLI) statusOptions() string {
+	statuses := c.service.Workflow().Statuses
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return strings.Join(names, ", ")
+}
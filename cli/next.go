@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleNext(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("next")
+	explain := fs.Bool("explain", false, "show how the task was scored")
+	quick := fs.Bool("quick", false, "only tasks estimated to take 15 minutes or less")
+	view := task.View{}
+	fs.Func("tag", "only tasks with this tag, repeat for several", func(tag string) error {
+		view.Tags = append(view.Tags, tag)
+		return nil
+	})
+	fs.StringVar(&view.Assignee, "assignee", "", "only tasks assigned to this user, \"me\" or \"none\"")
+	fs.StringVar(&view.Project, "project", "", "only tasks in this project")
+	fs.StringVar(&view.Where, "where", "", "only tasks passing this filter expression")
+	taskContext := c.contextFlags(fs)
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	if *quick {
+		view.MaxEffort = task.Duration(task.QuickEffort)
+	}
+
+	weights, err := task.NextWeights(c.config.Next.Weights)
+	if err != nil {
+		return c.fail(err)
+	}
+	match, err := c.viewMatch(view)
+	if err != nil {
+		return c.fail(err)
+	}
+	suggestions, err := c.service.Suggest(ctx, weights, inContext(match, taskContext()), c.clock())
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(suggestions) == 0 {
+		c.successf("Nothing to do\n")
+		return 0
+	}
+
+	best := suggestions[0]
+	fmt.Fprintf(c.stdout, "#%d %s\n", best.Task.ID, best.Task.Description)
+	if *explain {
+		for _, reason := range best.Reasons {
+			fmt.Fprintf(c.stdout, "  %-11s %+7.1f  %s\n", reason.Factor, reason.Points, reason.Detail)
+		}
+		fmt.Fprintf(c.stdout, "  %-11s %7.1f\n", c.tr.Text("score"), best.Score)
+	}
+	return 0
+}
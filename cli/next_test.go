@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+)
+
+// TestCLI_Next tests picking and explaining the next task
+func TestCLI_Next(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	// Only task 2 is started and nothing else differs
+	if code := h.run("next"); code != 0 || h.stdout.String() != "#2 Write report\n" {
+		t.Errorf("next = %d, %q", code, h.stdout)
+	}
+
+	h.run("due", "1", "2023-12-31")
+	if code := h.run("next", "--explain"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	want := "#1 Buy groceries\n" +
+		"  due           +35.0  overdue, due 12 hours ago\n" +
+		"  score          35.0\n"
+	if h.stdout.String() != want {
+		t.Errorf("next --explain = %q, want %q", h.stdout, want)
+	}
+
+	h.cli.WithConfig(repository.Config{Next: repository.NextConfig{Weights: map[string]float64{"due": 0}}})
+	if code := h.run("next", "--where", "id != 2"); code != 0 || h.stdout.String() != "#1 Buy groceries\n" {
+		t.Errorf("next --where = %d, %q", code, h.stdout)
+	}
+	if code := h.run("next", "--project", "none-such"); code != 0 || h.stdout.String() != "Nothing to do\n" {
+		t.Errorf("next with no match = %d, %q", code, h.stdout)
+	}
+}
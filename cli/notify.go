@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleDue(ctx context.Context, args []string) int {
+	if len(args) < 2 {
+		c.errorf("Error: ID and date are required\n")
+		c.errorf("Usage: task-cli due <id> <when|none>\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	var due *time.Time
+	if args[1] != "none" {
+		deadline, err := task.ParseDeadline(args[1], c.clock())
+		if err != nil {
+			return c.fail(err)
+		}
+		due = &deadline
+	}
+
+	if err := c.service.SetTaskDue(ctx, id, due); err != nil {
+		return c.fail(err)
+	}
+
+	if due == nil {
+		c.successf("Due date cleared\n")
+	} else {
+		c.successf("Task due %s\n", due.Format("2006-01-02 15:04"))
+	}
+	return 0
+}
+
+func (c *CLI) handleNotify(ctx context.Context, args []string) int {
+	settings := c.config.Notify
+	fs := c.newFlagSet("notify")
+	daemon := fs.Bool("daemon", false, "keep running and check periodically")
+	interval := fs.String("interval", time.Duration(settings.Interval).String(), "delay between checks in daemon mode")
+	dueWithin := fs.String("due-within", time.Duration(settings.DueWithin).String(), "alert for tasks due within this duration")
+	staleAfter := fs.String("stale-after", time.Duration(settings.StaleAfter).String(), "alert for in-progress tasks untouched for this long")
+	dryRun := fs.Bool("dry-run", false, "print alerts instead of sending notifications")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	var thresholds task.AlertThresholds
+	var every time.Duration
+	for _, flag := range []struct {
+		value string
+		into  *time.Duration
+	}{
+		{*dueWithin, &thresholds.DueWithin},
+		{*staleAfter, &thresholds.StaleAfter},
+		{*interval, &every},
+	} {
+		d, err := task.ParseDuration(flag.value)
+		if err != nil {
+			return c.fail(err)
+		}
+		*flag.into = d
+	}
+	if every <= 0 {
+		c.errorf("Error: --interval must be positive\n")
+		return 1
+	}
+
+	if c.notifier == nil && !*dryRun {
+		return c.fail(service.ErrNotifierUnavailable)
+	}
+
+	sent := make(map[string]bool)
+	for {
+		if !*dryRun {
+			if err := c.wakeTasks(ctx, nil); err != nil {
+				c.printError(err)
+			}
+		}
+		if err := c.sendAlerts(ctx, thresholds, sent, *dryRun); err != nil {
+			c.printError(err)
+			if !*daemon {
+				return 1
+			}
+		}
+
+		if !*daemon {
+			return 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-time.After(every):
+		}
+	}
+}
+
+// sendAlerts notifies about every alert not already present in sent
+func (c *CLI) sendAlerts(ctx context.Context, thresholds task.AlertThresholds, sent map[string]bool, dryRun bool) error {
+	now := c.clock()
+	alerts, err := c.service.Alerts(ctx, now, thresholds)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, alert := range alerts {
+		if sent[alert.Key()] {
+			continue
+		}
+
+		title := alertTitles[alert.Kind]
+		body := alert.Message(now)
+		if dryRun {
+			fmt.Fprintf(c.stdout, "%s: %s\n", title, body)
+		} else if err := c.notifier.Send(ctx, title, body); err != nil {
+			return fmt.Errorf("failed to send notification: %w", err)
+		}
+
+		sent[alert.Key()] = true
+		count++
+	}
+
+	if !dryRun {
+		c.successf("%d notification(s) sent\n", count)
+	}
+	return nil
+}
+
+var alertTitles = map[task.AlertKind]string{
+	task.AlertDueSoon: "Task due soon",
+	task.AlertOverdue: "Task overdue",
+	task.AlertStale:   "Task going stale",
+}
@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCLI_APISpec(t *testing.T) {
+	h := newCLIHarness(t, nil)
+	if code := h.run("api-spec"); code != 0 {
+		t.Fatalf("exit code = %d, stderr = %q", code, h.stderr)
+	}
+	var spec map[string]any
+	if err := json.Unmarshal(h.stdout.Bytes(), &spec); err != nil || spec["openapi"] != "3.0.3" {
+		t.Errorf("output is not an OpenAPI document: %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// pickShown is how many matches the picker lists at a time
+const pickShown = 10
+
+var (
+	errIDRequired  = errors.New("ID is required")
+	errNothingPick = errors.New("no task picked")
+)
+
+// pickArgs stands in for missing ID arguments: it lets the user pick open
+// tasks by fuzzy search and returns their IDs, several when multi is set.
+// On a terminal the matches update at each key; elsewhere, or when the
+// terminal cannot be put in raw mode, the user types a search or the number
+// of a match at a prompt. Without a prompter, or when stdin is piped so
+// nobody can answer, it fails with errIDRequired as the command did before.
+func (c *CLI) pickArgs(ctx context.Context, multi bool) ([]string, error) {
+	if c.prompter == nil || c.stdinPiped() {
+		return nil, errIDRequired
+	}
+	now := c.clock()
+	tasks, err := c.service.ListPage(ctx, repository.Page{}, func(task task.Task) bool {
+		return task.IsOpen() && !task.IsSnoozed(now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("%w, there is no open task to pick", errIDRequired)
+	}
+
+	// On a terminal the list narrows down as the user types
+	if in, ok := c.stdin.(*os.File); ok {
+		if out, ok := c.stderr.(*os.File); ok && isTerminal(out) && enableVirtualTerminal(out) {
+			if restore, err := makeRaw(in); err == nil {
+				defer restore()
+				return c.pickLive(bufio.NewReader(in), tasks, multi)
+			}
+		}
+	}
+
+	question := "Pick a task by number, or type to search:"
+	if multi {
+		question = "Pick tasks by numbers or ranges like 1 3-5, or type to search:"
+	}
+	query := ""
+	for {
+		matches := task.FuzzyFilter(tasks, query)
+		shown := matches[:min(len(matches), pickShown)]
+		for i, task := range shown {
+			fmt.Fprintf(c.stderr, "%3d. #%d %s (%s)\n", i+1, task.ID, task.Description, c.statusLabel(task.Status))
+		}
+		switch {
+		case len(matches) == 0:
+			fmt.Fprintf(c.stderr, "No open task matches %q\n", query)
+		case len(matches) > len(shown):
+			fmt.Fprintf(c.stderr, "     %d more, type to narrow down\n", len(matches)-len(shown))
+		}
+
+		answer, err := c.prompter.Ask(question)
+		if errors.Is(err, io.EOF) {
+			return nil, errNothingPick
+		}
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" && len(matches) == 1 {
+			return []string{strconv.Itoa(matches[0].ID)}, nil
+		}
+		if picked, ok := pickNumbers(answer, shown, multi); ok {
+			return picked, nil
+		}
+		query = answer
+	}
+}
+
+// pickNumbers reads an answer made only of numbers of the tasks shown,
+// or ranges of them when multi is set, and returns the IDs of those tasks.
+// ok is false for anything else, which the picker takes as a search.
+func pickNumbers(answer string, shown []task.Task, multi bool) ([]string, bool) {
+	fields := strings.Fields(answer)
+	if len(fields) == 0 || len(fields) > 1 && !multi {
+		return nil, false
+	}
+	var ids []string
+	for _, field := range fields {
+		from, to, isRange := strings.Cut(field, "-")
+		if isRange && !multi {
+			return nil, false
+		}
+		if !isRange {
+			to = from
+		}
+		first, err1 := strconv.Atoi(from)
+		last, err2 := strconv.Atoi(to)
+		if err1 != nil || err2 != nil || first < 1 || last < first || last > len(shown) {
+			return nil, false
+		}
+		for n := first; n <= last; n++ {
+			ids = append(ids, strconv.Itoa(shown[n-1].ID))
+		}
+	}
+	return ids, true
+}
+
+// pickKey is a key the live picker acts on
+type pickKey int
+
+const (
+	keyOther pickKey = iota
+	keyRune
+	keyEnter
+	keyBackspace
+	keyClear
+	keyUp
+	keyDown
+	keyTab
+	keyCancel
+)
+
+// picker is the state of the live picker: the search typed so far, the
+// matches it leaves, the one under the cursor and, with multi, those
+// picked with Tab
+type picker struct {
+	tasks   []task.Task
+	multi   bool
+	query   []rune
+	matches []task.Task
+	cursor  int
+	picked  map[int]bool
+	// drawn is how many lines the last drawing took
+	drawn int
+}
+
+// pickLive runs the picker on keys read from a terminal in raw mode,
+// drawing on stderr. The best match sits just above the prompt; arrows or
+// Ctrl+P and Ctrl+N move between matches, Tab picks several when multi is
+// set, Enter returns and Esc or Ctrl+C gives up.
+func (c *CLI) pickLive(in *bufio.Reader, tasks []task.Task, multi bool) ([]string, error) {
+	p := &picker{tasks: tasks, multi: multi, matches: tasks, picked: make(map[int]bool)}
+	defer c.clearPicker(p)
+	for {
+		c.drawPicker(p)
+
+		key, r, err := readPickKey(in)
+		if errors.Is(err, io.EOF) {
+			return nil, errNothingPick
+		}
+		if err != nil {
+			return nil, err
+		}
+		shown := p.shown()
+		switch key {
+		case keyRune:
+			p.search(append(p.query, r))
+		case keyBackspace:
+			if len(p.query) > 0 {
+				p.search(p.query[:len(p.query)-1])
+			}
+		case keyClear:
+			p.search(nil)
+		case keyUp:
+			p.cursor = min(p.cursor+1, max(len(shown)-1, 0))
+		case keyDown:
+			p.cursor = max(p.cursor-1, 0)
+		case keyTab:
+			if multi && len(shown) > 0 {
+				if id := shown[p.cursor].ID; p.picked[id] {
+					delete(p.picked, id)
+				} else {
+					p.picked[id] = true
+				}
+				p.cursor = min(p.cursor+1, len(shown)-1)
+			}
+		case keyEnter:
+			if ids := p.result(); len(ids) > 0 {
+				return ids, nil
+			}
+		case keyCancel:
+			return nil, errNothingPick
+		}
+	}
+}
+
+// search narrows the matches down to query, the cursor back on the best
+func (p *picker) search(query []rune) {
+	p.query = query
+	p.matches = task.FuzzyFilter(p.tasks, string(query))
+	p.cursor = 0
+}
+
+// shown returns the matches that fit on screen, best first
+func (p *picker) shown() []task.Task {
+	return p.matches[:min(len(p.matches), pickShown)]
+}
+
+// result returns the IDs of the picked tasks in list order, or else that
+// of the match under the cursor
+func (p *picker) result() []string {
+	var ids []string
+	for _, task := range p.tasks {
+		if p.picked[task.ID] {
+			ids = append(ids, strconv.Itoa(task.ID))
+		}
+	}
+	if len(ids) == 0 && len(p.matches) > 0 {
+		ids = append(ids, strconv.Itoa(p.shown()[p.cursor].ID))
+	}
+	return ids
+}
+
+// drawPicker draws the matches, the best at the bottom, then how many
+// there are and the prompt, over what the last drawing left
+func (c *CLI) drawPicker(p *picker) {
+	width := terminalWidth() - 1
+	shown := p.shown()
+	lines := make([]string, 0, len(shown)+2)
+	for i, task := range slices.Backward(shown) {
+		mark := "  "
+		if i == p.cursor {
+			mark = "> "
+		}
+		if p.picked[task.ID] {
+			mark = mark[:1] + "*"
+		}
+		lines = append(lines, clipLine(fmt.Sprintf("%s#%d %s (%s)", mark, task.ID, task.Description, c.statusLabel(task.Status)), width))
+	}
+	count := fmt.Sprintf("  %d/%d", len(p.matches), len(p.tasks))
+	if len(p.matches) == 0 {
+		count = fmt.Sprintf("  No open task matches %q", string(p.query))
+	}
+	switch {
+	case len(p.picked) > 0:
+		count += fmt.Sprintf(" (%d picked)", len(p.picked))
+	case p.multi:
+		count += " (Tab picks several)"
+	}
+	lines = append(lines, count, clipLine("> "+string(p.query), width))
+
+	c.clearPicker(p)
+	io.WriteString(c.stderr, strings.Join(lines, "\n"))
+	p.drawn = len(lines)
+}
+
+// clearPicker erases the last drawing, leaving the cursor where it began
+func (c *CLI) clearPicker(p *picker) {
+	if p.drawn > 1 {
+		fmt.Fprintf(c.stderr, "\x1b[%dA", p.drawn-1)
+	}
+	io.WriteString(c.stderr, "\r\x1b[J")
+	p.drawn = 0
+}
+
+// readPickKey reads one key press. Escape sequences of arrow keys arrive
+// at once, so an escape with nothing after it is the Esc key itself.
+func readPickKey(in *bufio.Reader) (pickKey, rune, error) {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return keyOther, 0, err
+	}
+	switch r {
+	case '\r', '\n':
+		return keyEnter, r, nil
+	case 0x7f, '\b':
+		return keyBackspace, r, nil
+	case 0x15: // Ctrl+U
+		return keyClear, r, nil
+	case 0x10: // Ctrl+P
+		return keyUp, r, nil
+	case 0x0e: // Ctrl+N
+		return keyDown, r, nil
+	case '\t':
+		return keyTab, r, nil
+	case 0x03, 0x04: // Ctrl+C, Ctrl+D
+		return keyCancel, r, nil
+	case 0x1b:
+		if in.Buffered() == 0 {
+			return keyCancel, r, nil
+		}
+		// ESC [ A and ESC O A are the up arrow, depending on the terminal;
+		// other keys such as Delete send parameters before the final byte
+		prefix, _ := in.ReadByte()
+		if prefix != '[' && prefix != 'O' {
+			return keyOther, r, nil
+		}
+		final, err := in.ReadByte()
+		for err == nil && final < 0x40 {
+			final, err = in.ReadByte()
+		}
+		if err != nil {
+			return keyOther, r, err
+		}
+		switch final {
+		case 'A':
+			return keyUp, r, nil
+		case 'B':
+			return keyDown, r, nil
+		}
+		return keyOther, r, nil
+	}
+	if unicode.IsPrint(r) {
+		return keyRune, r, nil
+	}
+	return keyOther, r, nil
+}
+
+// clipLine cuts a line to width characters, so that it does not wrap and
+// throw off the redrawing
+func clipLine(line string, width int) string {
+	if runes := []rune(line); width > 0 && len(runes) > width {
+		return string(runes[:width])
+	}
+	return line
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// pluginPrefix starts the name of the executables that add commands, so
+// that "task-cli hello" runs task-cli-hello from PATH
+const pluginPrefix = "task-cli-"
+
+// pluginContextVersion is bumped when fields of PluginContext change
+// meaning, so plugins can tell what they were given
+const pluginContextVersion = 2
+
+// PluginContext is written as JSON on a plugin's stdin: where the tasks are
+// and how the command was run
+type PluginContext struct {
+	Version int      `json:"version"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	// File is the task file, empty when a store chosen by the config
+	// holds the tasks instead
+	File string `json:"file,omitempty"`
+	// Store names where the tasks are kept, such as a file or a URL
+	Store      string `json:"store,omitempty"`
+	ConfigFile string `json:"configFile,omitempty"`
+	List       string `json:"list,omitempty"`
+	Context    string `json:"context,omitempty"`
+	Actor      string `json:"actor"`
+	Language   string `json:"language"`
+	ReadOnly   bool   `json:"readOnly"`
+	Quiet      bool   `json:"quiet"`
+}
+
+// Plugin is an executable found on PATH that adds a command
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// WithDataFile sets the task file plugins are pointed at, left empty when
+// the tasks are not kept in that file
+func (c *CLI) WithDataFile(path string) *CLI {
+	c.dataFile = path
+	return c
+}
+
+// WithStore sets the description of the store plugins are told about
+func (c *CLI) WithStore(store string) *CLI {
+	c.store = store
+	return c
+}
+
+// FindPlugins returns the plugins in the directories of path, a PATH-style
+// list, by name. Like the shell, the first directory holding a name wins.
+func FindPlugins(path string) []Plugin {
+	var plugins []Plugin
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(path) {
+		// Like exec.LookPath, relative directories are not searched
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || seen[name] || entry.IsDir() {
+				continue
+			}
+			full := filepath.Join(dir, entry.Name())
+			if !isExecutable(full) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: full})
+		}
+	}
+	slices.SortFunc(plugins, func(a, b Plugin) int { return strings.Compare(a.Name, b.Name) })
+	return plugins
+}
+
+// pluginName returns the command an executable adds, without the prefix
+// and, on Windows, the extension
+func pluginName(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		file = strings.TrimSuffix(file, filepath.Ext(file))
+	}
+	name, ok := strings.CutPrefix(file, pluginPrefix)
+	return name, ok && name != ""
+}
+
+// isExecutable reports whether the file at path can be run; Windows runs
+// any file with an executable extension
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		_, err := exec.LookPath(path)
+		return err == nil
+	}
+	return info.Mode()&0o111 != 0
+}
+
+// runPlugin runs the plugin adding the command named by args[1], if there
+// is one on PATH, with the rest of the arguments. Built-in commands and
+// aliases are dispatched first, so a plugin cannot hide one.
+func (c *CLI) runPlugin(ctx context.Context, args []string) (int, bool) {
+	name := args[1]
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\`) {
+		return 0, false
+	}
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return 0, false
+	}
+	if c.viaSocket {
+		c.errorf("Error: plugin %s cannot run through the daemon\n", name)
+		return 1, true
+	}
+
+	pluginCtx := c.pluginContext(ctx, name, args[2:])
+	input, err := json.Marshal(pluginCtx)
+	if err != nil {
+		return c.fail(err), true
+	}
+	cmd := exec.CommandContext(ctx, path, args[2:]...)
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	cmd.Stdout = c.stdout
+	cmd.Stderr = c.stderr
+	// So that the plugin can call task-cli back on the same tasks. The file
+	// is only passed when it holds them: as an explicit file it would win
+	// over the store the config chose.
+	cmd.Env = append(os.Environ(), "TASK_CLI_USER="+pluginCtx.Actor)
+	if pluginCtx.File != "" {
+		cmd.Env = append(cmd.Env, repository.DataFileEnvVar+"="+pluginCtx.File)
+	}
+	if pluginCtx.ConfigFile != "" {
+		cmd.Env = append(cmd.Env, repository.ConfigEnvVar+"="+pluginCtx.ConfigFile)
+	}
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), true
+	}
+	if err != nil {
+		c.errorf("Error: failed to run plugin %s: %s\n", name, c.tr.Error(err))
+		return 1, true
+	}
+	return 0, true
+}
+
+// pluginContext describes the command run for the plugin adding it
+func (c *CLI) pluginContext(ctx context.Context, name string, args []string) PluginContext {
+	file := c.dataFile
+	if abs, err := filepath.Abs(file); err == nil && file != "" {
+		file = abs
+	}
+	language := task.DefaultLanguage
+	if c.tr != nil {
+		language = c.tr.Language()
+	}
+	return PluginContext{
+		Version:    pluginContextVersion,
+		Command:    name,
+		Args:       append([]string{}, args...),
+		File:       file,
+		Store:      c.store,
+		ConfigFile: c.configFile,
+		List:       c.list,
+		Context:    c.activeContext(),
+		Actor:      task.ActorFrom(ctx),
+		Language:   language,
+		ReadOnly:   c.service.ReadOnly(),
+		Quiet:      c.quiet,
+	}
+}
+
+func (c *CLI) handlePlugins(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "list":
+		plugins := FindPlugins(os.Getenv("PATH"))
+		if len(plugins) == 0 {
+			c.successf("No plugins found, add task-cli-<name> executables to PATH\n")
+			return 0
+		}
+		for _, plugin := range plugins {
+			fmt.Fprintf(c.stdout, "%s\t%s\n", plugin.Name, plugin.Path)
+		}
+		return 0
+
+	default:
+		c.errorf("Unknown plugins command: %s\n", args[0])
+		c.errorf("Usage: task-cli plugins list\n")
+		return 1
+	}
+}
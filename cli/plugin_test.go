@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// writePlugin puts an executable shell script named task-cli-<name> in dir
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, pluginPrefix+name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestCLI_Plugins tests that unknown commands run task-cli-<name> from PATH
+func TestCLI_Plugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts here")
+	}
+	dir, other := t.TempDir(), t.TempDir()
+	out := filepath.Join(t.TempDir(), "context.json")
+	// PATH holds only the plugins, so the script sticks to shell builtins
+	hello := writePlugin(t, dir, "hello", `read -r context && printf '%s' "$context" > "`+out+`"
+echo "hello $1 from $TASK_CLI_FILE"
+exit 7
+`)
+	writePlugin(t, other, "hello", "echo shadowed\n")
+	writePlugin(t, other, "list", "echo never run\n")
+	writePlugin(t, dir, "broken", "")
+	if err := os.Chmod(filepath.Join(dir, pluginPrefix+"broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+other)
+
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithDataFile("tasks.json").WithConfigFile("/etc/task-cli.json")
+
+	if code := h.run("--list", "work", "hello", "world"); code != 7 {
+		t.Fatalf("plugin exit code = %d, want 7; stderr: %s", code, h.stderr)
+	}
+	abs, _ := filepath.Abs("tasks.json")
+	if want := "hello world from " + abs + "\n"; h.stdout.String() != want {
+		t.Errorf("plugin stdout = %q, want %q", h.stdout, want)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got PluginContext
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("plugin stdin %q: %v", data, err)
+	}
+	if got.Version != pluginContextVersion || got.Command != "hello" || strings.Join(got.Args, " ") != "world" ||
+		got.File != abs || got.ConfigFile != "/etc/task-cli.json" || got.List != "work" {
+		t.Errorf("plugin context = %+v", got)
+	}
+
+	// Built-in commands win over plugins
+	if code := h.run("list"); code != 0 || strings.Contains(h.stdout.String(), "never run") {
+		t.Errorf("list exit code = %d, stdout = %q", code, h.stdout)
+	}
+	if code := h.run("broken"); code != 1 || !strings.Contains(h.stderr.String(), "Unknown command: broken") {
+		t.Errorf("non-executable plugin exit code = %d, stderr = %q", code, h.stderr)
+	}
+
+	if code := h.run("plugins", "list"); code != 0 {
+		t.Fatalf("plugins list exit code = %d", code)
+	}
+	want := "hello\t" + hello + "\nlist\t" + filepath.Join(other, pluginPrefix+"list") + "\n"
+	if h.stdout.String() != want {
+		t.Errorf("plugins list = %q, want %q", h.stdout, want)
+	}
+}
+
+// TestCLI_PluginStore tests that a plugin is not pointed at a file when a
+// store chosen by the config holds the tasks
+func TestCLI_PluginStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts here")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "where", `read -r context
+echo "${TASK_CLI_FILE-unset}"
+echo "$context"
+`)
+	t.Setenv("PATH", dir)
+	t.Setenv("TASK_CLI_FILE", "")
+	os.Unsetenv("TASK_CLI_FILE")
+
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithStore("s3://tasks/tasks.json")
+
+	if code := h.run("where"); code != 0 {
+		t.Fatalf("plugin exit code = %d, stderr: %s", code, h.stderr)
+	}
+	file, context, _ := strings.Cut(h.stdout.String(), "\n")
+	if file != "unset" {
+		t.Errorf("TASK_CLI_FILE = %q, want it unset", file)
+	}
+	var got PluginContext
+	if err := json.Unmarshal([]byte(context), &got); err != nil {
+		t.Fatalf("plugin stdin %q: %v", context, err)
+	}
+	if got.File != "" || got.Store != "s3://tasks/tasks.json" {
+		t.Errorf("plugin file = %q, store = %q; want none and the bucket", got.File, got.Store)
+	}
+}
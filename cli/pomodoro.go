@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handlePomodoro(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("pomodoro")
+	work := fs.String("work", "25m", "length of a work interval")
+	rest := fs.String("break", "5m", "length of the break after it")
+	rounds := fs.Int("rounds", 1, "how many work intervals to run")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	if len(args) == 0 {
+		c.errorf("Error: ID is required\n")
+		c.errorf("Usage: task-cli pomodoro <id> [--work 25m] [--break 5m] [--rounds 1]\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	var workLength, breakLength time.Duration
+	for _, flag := range []struct {
+		value string
+		into  *time.Duration
+	}{{*work, &workLength}, {*rest, &breakLength}} {
+		if *flag.into, err = task.ParseDuration(flag.value); err != nil {
+			return c.fail(err)
+		}
+	}
+
+	t, err := c.service.GetTask(ctx, id)
+	if err != nil {
+		return c.fail(err)
+	}
+	if !t.IsOpen() {
+		c.errorf("Error: task %d is %s\n", id, c.tr.Status(t.Status))
+		return 1
+	}
+	if t.Status == task.StatusTodo {
+		if err := c.moveTask(ctx, id, task.StatusInProgress, false); err != nil {
+			return c.fail(err)
+		}
+		c.successf("Task %d is now in progress\n", id)
+	}
+
+	for round := 1; round <= *rounds; round++ {
+		label := c.tr.Sprintf("Pomodoro %d/%d on #%d %s", round, *rounds, id, t.Description)
+		if !c.countdown(ctx, label, workLength) {
+			c.successf("Pomodoro interrupted, not counted\n")
+			return 0
+		}
+
+		count, err := c.service.RecordPomodoro(ctx, id)
+		if err != nil {
+			return c.fail(err)
+		}
+		c.successf("Pomodoro done, %d completed on task %d\n", count, id)
+		if c.notifier != nil {
+			c.notifier.Send(ctx, "Pomodoro done", fmt.Sprintf("#%d %s: time for a break", id, t.Description))
+		}
+
+		if breakLength > 0 && !c.countdown(ctx, c.tr.Text("Break"), breakLength) {
+			return 0
+		}
+	}
+	return 0
+}
+
+// countdown waits for d, redrawing the remaining time every second on
+// terminals, and reports whether it ran to the end
+func (c *CLI) countdown(ctx context.Context, label string, d time.Duration) bool {
+	done := time.After(d)
+	live := !c.quiet && isTerminal(c.stdout)
+	if !live {
+		c.successf("%s (%s)\n", label, task.FormatDuration(d))
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	end := time.Now().Add(d)
+	for {
+		if live {
+			remaining := time.Until(end).Round(time.Second)
+			fmt.Fprintf(c.stdout, "\r%s  %02d:%02d ", label, int(remaining.Minutes()), int(remaining.Seconds())%60)
+		}
+		select {
+		case <-ctx.Done():
+			if live {
+				fmt.Fprintln(c.stdout)
+			}
+			return false
+		case <-done:
+			if live {
+				fmt.Fprintln(c.stdout)
+			}
+			return true
+		case <-ticker.C:
+		}
+	}
+}
+
+// isTerminal reports whether w is an interactive terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
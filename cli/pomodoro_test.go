@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_Pomodoro tests running pomodoros bound to a task
+func TestCLI_Pomodoro(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	notifier := &fakeNotifier{}
+	h.cli.WithNotifier(notifier)
+
+	if code := h.run("pomodoro", "1", "--work", "1ms", "--break", "1ms", "--rounds", "2"); code != 0 {
+		t.Fatalf("pomodoro exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+
+	item, _ := h.repo.GetTask(1)
+	if item.Status != task.StatusInProgress || item.Pomodoros != 2 {
+		t.Errorf("task = %s with %d pomodoros, want in-progress with 2", item.Status, item.Pomodoros)
+	}
+	for _, want := range []string{
+		"Task 1 is now in progress",
+		"Pomodoro 2/2 on #1 Buy groceries",
+		"Pomodoro done, 2 completed on task 1",
+	} {
+		if !strings.Contains(h.stdout.String(), want) {
+			t.Errorf("stdout = %q, want it to contain %q", h.stdout.String(), want)
+		}
+	}
+	if len(notifier.sent) != 2 {
+		t.Errorf("notifications = %q, want one per pomodoro", notifier.sent)
+	}
+
+	if code := h.run("show", "1"); code != 0 || !strings.Contains(h.stdout.String(), "Pomodoro: 2 completed") {
+		t.Errorf("show = %q, want the pomodoro count", h.stdout.String())
+	}
+
+	t.Run("interrupted", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		code := h.cli.Run(ctx, []string{"task-cli", "pomodoro", "2", "--work", "1h"})
+		if task, _ := h.repo.GetTask(2); code != 0 || task.Pomodoros != 0 {
+			t.Errorf("exit code = %d with %d pomodoros, want 0 and none counted", code, task.Pomodoros)
+		}
+	})
+
+	t.Run("closed task", func(t *testing.T) {
+		if code := h.run("pomodoro", "3"); code != 1 {
+			t.Errorf("pomodoro on a done task exit code = %d, want 1", code)
+		}
+	})
+}
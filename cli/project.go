@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+)
+
+func (c *CLI) handleProject(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 2 {
+			c.errorf("Error: Project name is required\n")
+			c.errorf("Usage: task-cli project add <name>\n")
+			return 1
+		}
+		project, err := c.service.AddProject(ctx, args[1])
+		if err != nil {
+			return c.fail(err)
+		}
+		c.successf("Project %s added\n", project.Name)
+		return 0
+
+	case "list":
+		return c.handleProjectList(ctx, args[1:])
+
+	case "rename":
+		if len(args) < 3 {
+			c.errorf("Error: Old and new names are required\n")
+			c.errorf("Usage: task-cli project rename <name> <new-name>\n")
+			return 1
+		}
+		moved, err := c.service.RenameProject(ctx, args[1], args[2])
+		if err != nil {
+			return c.fail(err)
+		}
+		c.successf("Project %s renamed to %s (%d tasks moved)\n", args[1], args[2], moved)
+		return 0
+
+	case "close":
+		if len(args) < 2 {
+			c.errorf("Error: Project name is required\n")
+			c.errorf("Usage: task-cli project close <name>\n")
+			return 1
+		}
+		if err := c.service.CloseProject(ctx, args[1]); err != nil {
+			return c.fail(err)
+		}
+		c.successf("Project %s closed\n", args[1])
+		return 0
+
+	case "set":
+		if len(args) < 3 {
+			c.errorf("Error: ID and project are required\n")
+			c.errorf("Usage: task-cli project set <id> <name|none>\n")
+			return 1
+		}
+		id, err := c.service.ResolveTaskID(ctx, args[1])
+		if err != nil {
+			return c.fail(err)
+		}
+		name := args[2]
+		if name == "none" {
+			name = ""
+		}
+		if err := c.service.SetTaskProject(ctx, id, name); err != nil {
+			return c.fail(err)
+		}
+		if name == "" {
+			c.successf("Task %d removed from its project\n", id)
+		} else {
+			c.successf("Task %d moved to project %s\n", id, name)
+		}
+		return 0
+
+	default:
+		c.errorf("Error: Unknown project command '%s'\n", args[0])
+		c.errorf("Usage: task-cli project add|list|rename|close|set\n")
+		return 1
+	}
+}
+
+func (c *CLI) handleProjectList(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("project list")
+	all := fs.Bool("all", false, "include closed projects")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	projects, err := c.service.Projects(ctx)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	shown := 0
+	for _, project := range projects {
+		if project.IsClosed() && !*all {
+			continue
+		}
+		if shown == 0 {
+			fmt.Fprintf(c.stdout, "%-20s %5s %5s  %s\n", "Project", "Open", "Done", "Last activity")
+		}
+		name := project.Name
+		if project.IsClosed() {
+			name += " (closed)"
+		}
+		fmt.Fprintf(c.stdout, "%-20s %5d %5d  %s\n", name, project.Open, project.Done,
+			c.formatTime(project.LastActivity, "2006-01-02 15:04"))
+		shown++
+	}
+	if shown == 0 {
+		c.successf("No projects found\n")
+	}
+	return 0
+}
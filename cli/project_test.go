@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+)
+
+// TestCLI_Project tests the project commands and list --project
+func TestCLI_Project(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.service.WithProjects(repository.NewFileProjectRepository(filepath.Join(t.TempDir(), "projects.json")))
+
+	if code := h.run("project", "list"); code != 0 || h.stdout.String() != "No projects found\n" {
+		t.Errorf("empty list = %d, %q", code, h.stdout.String())
+	}
+	if code := h.run("project", "add", "website"); code != 0 {
+		t.Fatalf("project add exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("add", "Design homepage", "--project", "website"); code != 0 {
+		t.Fatalf("add exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("add", "Stray", "--project", "nope"); code != exitNotFound ||
+		!strings.Contains(h.stderr.String(), "Project not found: nope") {
+		t.Errorf("add to missing project = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("project", "set", "3", "website"); code != 0 {
+		t.Fatalf("project set exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+
+	h.run("list", "--project", "website")
+	out := h.stdout.String()
+	if !strings.Contains(out, "Design homepage") || !strings.Contains(out, "Call mom") ||
+		strings.Contains(out, "Buy groceries") || !strings.Contains(out, "Project: website") {
+		t.Errorf("list --project output = %q", out)
+	}
+
+	h.run("project", "list")
+	out = h.stdout.String()
+	if !strings.Contains(out, "Last activity") || !strings.Contains(out, "website") {
+		t.Errorf("project list output = %q", out)
+	}
+	if fields := strings.Fields(strings.Split(out, "\n")[1]); fields[1] != "1" || fields[2] != "1" {
+		t.Errorf("project list row = %q, want 1 open and 1 done", fields)
+	}
+
+	if code := h.run("project", "close", "website"); code != exitRefused ||
+		!strings.Contains(h.stderr.String(), "open tasks") {
+		t.Errorf("close with open tasks = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("project", "rename", "website", "site"); code != 0 ||
+		!strings.Contains(h.stdout.String(), "2 tasks moved") {
+		t.Errorf("rename = %d, stdout = %q", code, h.stdout.String())
+	}
+	h.run("show", "4")
+	if !strings.Contains(h.stdout.String(), "Project:  site") {
+		t.Errorf("show output = %q, want the project", h.stdout.String())
+	}
+}
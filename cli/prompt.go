@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prompter asks the user to confirm a destructive command, or for an
+// answer in interactive commands such as triage
+type Prompter interface {
+	// Confirm asks a yes/no question and reports whether the answer was yes
+	Confirm(question string) (bool, error)
+	// Ask asks a question and returns the answer, failing with io.EOF when
+	// the input ends before one
+	Ask(question string) (string, error)
+}
+
+// LinePrompter asks on one stream and reads the answer as a line from
+// another. It works the same whether the input is a terminal or a pipe, so
+// `yes | task-cli delete 1-5` answers for a script; input that ends before
+// an answer, like /dev/null, counts as no.
+type LinePrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func NewLinePrompter(in io.Reader, out io.Writer) *LinePrompter {
+	return &LinePrompter{in: bufio.NewReader(in), out: out}
+}
+
+func (p *LinePrompter) Confirm(question string) (bool, error) {
+	line, err := p.Ask(question + " [y/N]")
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(line) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (p *LinePrompter) Ask(question string) (string, error) {
+	fmt.Fprintf(p.out, "%s ", question)
+	line, err := p.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		fmt.Fprintln(p.out)
+		return "", io.EOF
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
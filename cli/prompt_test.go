@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+)
+
+// stubPrompter answers every question the same way and remembers them
+type stubPrompter struct {
+	answer    bool
+	questions []string
+}
+
+func (p *stubPrompter) Confirm(question string) (bool, error) {
+	p.questions = append(p.questions, question)
+	return p.answer, nil
+}
+
+func (p *stubPrompter) Ask(question string) (string, error) {
+	p.questions = append(p.questions, question)
+	return "", io.EOF
+}
+
+// TestLinePrompter tests reading answers from terminals and pipes alike
+func TestLinePrompter(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"yes", "y\n", true},
+		{"yes in full and upper case", " YES \n", true},
+		{"no", "n\n", false},
+		{"empty line", "\n", false},
+		{"answer without newline", "y", true},
+		{"no input", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			got, err := NewLinePrompter(strings.NewReader(tt.input), out).Confirm("Delete task 1?")
+			if err != nil || got != tt.want {
+				t.Errorf("Confirm() = %v, %v; want %v", got, err, tt.want)
+			}
+			if !strings.HasPrefix(out.String(), "Delete task 1? [y/N] ") {
+				t.Errorf("prompt = %q", out.String())
+			}
+		})
+	}
+}
+
+// TestCLI_DeleteConfirm tests the question asked before deleting and the
+// ways to skip it
+func TestCLI_DeleteConfirm(t *testing.T) {
+	t.Run("declined", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		prompter := &stubPrompter{}
+		h.cli.WithPrompter(prompter)
+
+		if code := h.run("delete", "1"); code != 1 {
+			t.Errorf("exit code = %d, want 1", code)
+		}
+		if len(prompter.questions) != 1 || prompter.questions[0] != `Delete task 1 "Buy groceries"?` {
+			t.Errorf("questions = %q", prompter.questions)
+		}
+		if h.repo.TaskCount() != 3 || !strings.Contains(h.stderr.String(), "--yes") {
+			t.Errorf("tasks = %d, stderr = %q; want nothing deleted and a hint", h.repo.TaskCount(), h.stderr.String())
+		}
+	})
+
+	t.Run("range confirmed", func(t *testing.T) {
+		tasks := tasktest.FixedTasks(t)
+		tasks[1].ID = 5
+		h := newCLIHarness(t, tasks)
+		prompter := &stubPrompter{answer: true}
+		h.cli.WithPrompter(prompter)
+
+		if code := h.run("delete", "4-7", "1"); code != 0 {
+			t.Fatalf("exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if len(prompter.questions) != 1 || prompter.questions[0] != "Delete 2 tasks (#5, #1)?" {
+			t.Errorf("questions = %q", prompter.questions)
+		}
+		if stored := h.repo.GetStoredTasks(); len(stored) != 1 || stored[0].ID != 3 {
+			t.Errorf("stored tasks = %+v, want only task 3", stored)
+		}
+		if h.stdout.String() != "2 tasks deleted successfully\n" {
+			t.Errorf("stdout = %q", h.stdout.String())
+		}
+	})
+
+	t.Run("skipped with --yes", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		prompter := &stubPrompter{}
+		h.cli.WithPrompter(prompter)
+
+		if code := h.run("delete", "--yes", "3", "1"); code != 0 {
+			t.Fatalf("exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+		if len(prompter.questions) != 0 || h.repo.TaskCount() != 1 {
+			t.Errorf("questions = %q, tasks = %d; want no question and two tasks deleted", prompter.questions, h.repo.TaskCount())
+		}
+	})
+
+	t.Run("turned off in the config", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		prompter := &stubPrompter{}
+		config := repository.DefaultConfig()
+		config.Confirm.Delete = false
+		h.cli.WithPrompter(prompter).WithConfig(config)
+
+		if code := h.run("delete", "2"); code != 0 || len(prompter.questions) != 0 {
+			t.Errorf("exit code = %d, questions = %q; want no question", code, prompter.questions)
+		}
+	})
+
+	t.Run("missing ID deletes nothing", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		h.cli.WithPrompter(&stubPrompter{answer: true})
+
+		if code := h.run("delete", "1", "42"); code != exitNotFound {
+			t.Errorf("exit code = %d, want %d", code, exitNotFound)
+		}
+		if h.repo.TaskCount() != 3 {
+			t.Errorf("tasks = %d, want none deleted", h.repo.TaskCount())
+		}
+	})
+
+	t.Run("backwards range", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		if code := h.run("delete", "3-1"); code != 1 || !strings.Contains(h.stderr.String(), "invalid range") {
+			t.Errorf("exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+	})
+}
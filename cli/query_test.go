@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestParseQuery tests evaluating filter expressions against tasks
+func TestParseQuery(t *testing.T) {
+	now := tasktest.FixedTime()
+	due := now.Add(2 * time.Hour)
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].Tags = []string{"work"}
+	tasks[0].Priority = task.PriorityLow
+	tasks[0].Assignee = "ana"
+	tasks[1].Priority = task.PriorityHigh
+	tasks[1].DueAt = &due
+	tasks[2].Tags = []string{"work", "home"}
+	tasks[2].CreatedAt = now.AddDate(0, 0, -3)
+	env := task.QueryEnv{Now: now, Workflow: task.DefaultWorkflow(), User: "ana"}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"status != done && (tag:work || priority >= high)", []int{1, 2}},
+		{"tag:work && !tag:home", []int{1}},
+		{"priority > low || id = 3", []int{2, 3}},
+		{"created = today", []int{1, 2}},
+		{"created < '2d ago'", []int{3}},
+		{"due = none", []int{1, 3}},
+		{"due <= tomorrow", []int{2}},
+		{"assignee = me", []int{1}},
+		{`description ~ "REPORT"`, []int{2}},
+		{"status:done || status:todo && tag:nothing", []int{3}},
+	}
+	for _, tt := range tests {
+		query, err := task.ParseQuery(tt.query, env)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", tt.query, err)
+			continue
+		}
+		var got []task.Task
+		for _, task := range tasks {
+			if query.Match(task) {
+				got = append(got, task)
+			}
+		}
+		if ids := taskIDList(got); ids != taskIDList(tasksWithIDs(tt.want...)) {
+			t.Errorf("ParseQuery(%q) matches %s, want %v", tt.query, ids, tt.want)
+		}
+	}
+}
+
+// TestCLI_ListWhere tests list --where, alone and in a view
+func TestCLI_ListWhere(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	if code := h.run("list", "--where", "status != done && description ~ r"); code != 0 {
+		t.Fatalf("exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if out := h.stdout.String(); !strings.Contains(out, "Buy groceries") || !strings.Contains(out, "Write report") ||
+		strings.Contains(out, "Call mom") {
+		t.Errorf("list --where output = %q", out)
+	}
+
+	if code := h.run("list", "--where", "status != dne"); code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	want := "Error: invalid filter at column 11: invalid status \"dne\" (statuses: todo, in-progress, done, cancelled)\n" +
+		"  status != dne\n" +
+		"            ^\n"
+	if h.stderr.String() != want {
+		t.Errorf("stderr = %q, want %q", h.stderr.String(), want)
+	}
+}
+
+// tasksWithIDs returns bare tasks with the IDs, for comparing ID lists
+func tasksWithIDs(ids ...int) []task.Task {
+	tasks := make([]task.Task, len(ids))
+	for i, id := range ids {
+		tasks[i].ID = id
+	}
+	return tasks
+}
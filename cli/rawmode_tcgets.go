@@ -0,0 +1,11 @@
+//go:build aix || linux || solaris || zos
+
+package cli
+
+import "golang.org/x/sys/unix"
+
+// The requests reading and setting terminal attributes on these systems
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
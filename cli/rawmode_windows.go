@@ -0,0 +1,27 @@
+//go:build windows
+
+package cli
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// makeRaw puts the console f reads from in raw mode, so that each key
+// reaches the program as it is typed, without echo, arrow keys included as
+// ANSI escape sequences, and returns what puts it back
+func makeRaw(f *os.File) (func(), error) {
+	handle := windows.Handle(f.Fd())
+	var saved uint32
+	if err := windows.GetConsoleMode(handle, &saved); err != nil {
+		return nil, err
+	}
+
+	raw := saved &^ (windows.ENABLE_ECHO_INPUT | windows.ENABLE_PROCESSED_INPUT | windows.ENABLE_LINE_INPUT)
+	raw |= windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(handle, raw); err != nil {
+		return nil, err
+	}
+	return func() { windows.SetConsoleMode(handle, saved) }, nil
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_ReadOnly tests that --read-only lets reads through and refuses
+// changes
+func TestCLI_ReadOnly(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	for _, args := range [][]string{{"list"}, {"show", "1"}, {"summary"}} {
+		if code := h.run(append([]string{"--read-only"}, args...)...); code != 0 {
+			t.Errorf("%v exit code = %d, stderr = %q", args, code, h.stderr)
+		}
+	}
+
+	if code := h.run("--read-only", "add", "New task"); code != exitReadOnly {
+		t.Errorf("add exit code = %d, want %d", code, exitReadOnly)
+	}
+	if want := "Error: Task file is read-only [READ_ONLY]\n"; !strings.HasPrefix(h.stderr.String(), want) {
+		t.Errorf("stderr = %q, want %q", h.stderr, want)
+	}
+	// The flag sets the service read-only for the rest of the process
+	if code := h.run("mark-done", "1"); code != exitReadOnly {
+		t.Errorf("mark-done exit code = %d, want %d", code, exitReadOnly)
+	}
+	if item, _ := h.repo.GetTask(1); item.Status != task.StatusTodo {
+		t.Errorf("status = %s, want todo", item.Status)
+	}
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_Link tests linking tasks, showing relations and unlinking them
+func TestCLI_Link(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	if code := h.run("link", "1", "relates-to", "2"); code != 0 || h.stdout.String() != "Task 1 relates-to task 2\n" {
+		t.Fatalf("link = %d, %q, stderr: %s", code, h.stdout, h.stderr)
+	}
+	if code := h.run("link", "1", "blocks", "2"); code != exitInvalid {
+		t.Errorf("unknown relation exit code = %d, want %d", code, exitInvalid)
+	}
+	if code := h.run("link", "1", "duplicates", "1"); code != exitInvalid {
+		t.Errorf("self link exit code = %d, want %d", code, exitInvalid)
+	}
+
+	h.run("show", "2")
+	if want := "Relations:\n  relates-to #1 Buy groceries (todo)\n"; !strings.Contains(h.stdout.String(), want) {
+		t.Errorf("show missing %q:\n%s", want, h.stdout)
+	}
+
+	if code := h.run("unlink", "2", "1"); code != 0 {
+		t.Fatalf("unlink exit code = %d, stderr: %s", code, h.stderr)
+	}
+	for _, id := range []int{1, 2} {
+		if task, _ := h.repo.GetTask(id); len(task.Relations) != 0 {
+			t.Errorf("task %d relations = %+v after unlink", id, task.Relations)
+		}
+	}
+	if code := h.run("unlink", "2", "1"); code != exitNotFound || !strings.Contains(h.stderr.String(), "Tasks are not linked") {
+		t.Errorf("second unlink = %d, stderr: %s", code, h.stderr)
+	}
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleRemind(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("remind")
+	at := fs.String("at", "", "when to be reminded, e.g. \"tomorrow 9am\", or none")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	if len(args) == 0 || *at == "" {
+		c.errorf("Error: ID and --at are required\n")
+		c.errorf("Usage: task-cli remind <id> --at <when|none>\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	var remindAt *time.Time
+	if *at != "none" {
+		when, err := task.ParseDeadline(*at, c.clock())
+		if err != nil {
+			return c.fail(err)
+		}
+		remindAt = &when
+	}
+
+	if err := c.service.SetTaskReminder(ctx, id, remindAt); err != nil {
+		return c.fail(err)
+	}
+
+	if remindAt == nil {
+		c.successf("Reminder cleared\n")
+	} else {
+		c.successf("Reminder set for %s\n", remindAt.Format("2006-01-02 15:04"))
+	}
+	return 0
+}
+
+// handleDaemon sleeps until the next reminder or schedule is due. Reminders
+// are sent as desktop notifications and, when configured, webhooks;
+// schedules create their tasks like tick. Changes made while it sleeps wake
+// it up, so new reminders are picked up at once.
+func (c *CLI) handleDaemon(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("daemon")
+	once := fs.Bool("once", false, "send the reminders and create the tasks due now, then exit")
+	var socket socketFlag
+	fs.Var(&socket, "socket", "also answer commands from task-cli client, on the default socket or --socket=<path>")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+	if *once && socket.enabled {
+		c.errorf("Error: --once and --socket cannot be combined\n")
+		return 1
+	}
+
+	var sender *service.WebhookSender
+	if hooks := c.config.Webhooks; len(hooks.URLs) > 0 {
+		sender = service.NewWebhookSender(hooks.URLs, hooks.Secret).WithRetries(hooks.Retries, time.Second)
+	}
+	schedules, err := c.schedules()
+	if err != nil {
+		return c.fail(err)
+	}
+	remind := c.notifier != nil || sender != nil
+	if !remind && len(schedules) == 0 && !socket.enabled {
+		return c.fail(service.ErrNotifierUnavailable)
+	}
+
+	var served <-chan error
+	if socket.enabled {
+		path := socket.path
+		if path == "" {
+			path = c.socketFile
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		if served, err = c.listenSocket(ctx, path); err != nil {
+			cancel()
+			return c.fail(err)
+		}
+		if !remind && len(schedules) == 0 {
+			err := <-served
+			cancel()
+			if err != nil {
+				return c.fail(err)
+			}
+			return 0
+		}
+		// Stop serving before returning, which removes the socket file
+		defer func() {
+			cancel()
+			<-served
+		}()
+	}
+
+	// Rechecking at the notify interval also covers stores that cannot
+	// signal changes made by other machines
+	every := time.Duration(c.config.Notify.Interval)
+	if every <= 0 {
+		c.errorf("Error: notify.interval must be positive, not %s\n", every)
+		return 1
+	}
+	var changes <-chan task.TaskChange
+	if !*once {
+		if changes, err = c.service.WatchTasks(ctx, every); err != nil {
+			return c.fail(err)
+		}
+		c.successf("Waiting for reminders (Ctrl+C to stop)\n")
+	}
+
+	for {
+		failed := false
+		if remind {
+			if err := c.sendReminders(ctx, sender); err != nil {
+				c.printError(err)
+				failed = true
+			}
+			if err := c.wakeTasks(ctx, sender); err != nil {
+				c.printError(err)
+				failed = true
+			}
+		}
+		if len(schedules) > 0 {
+			if _, err := c.runSchedules(ctx, schedules); err != nil {
+				c.printError(err)
+				failed = true
+			}
+		}
+		if *once {
+			if failed {
+				return 1
+			}
+			return 0
+		}
+
+		wait := every
+		now := c.clock()
+		if next, err := c.service.NextReminder(ctx); remind && err == nil && next != nil {
+			wait = min(wait, max(next.Sub(now), 0))
+		}
+		if next, err := c.service.NextWake(ctx); remind && err == nil && next != nil {
+			wait = min(wait, max(next.Sub(now), 0))
+		}
+		if next, ok := task.NextScheduled(schedules, now); ok {
+			wait = min(wait, next.Sub(now))
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-time.After(wait):
+		case _, ok := <-changes:
+			if !ok {
+				return 0
+			}
+		}
+	}
+}
+
+// sendReminders fires the due reminders. A failed delivery is reported
+// but does not stop the other reminders.
+func (c *CLI) sendReminders(ctx context.Context, sender *service.WebhookSender) error {
+	now := c.clock()
+	reminders, err := c.service.FireReminders(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, reminder := range reminders {
+		message := reminder.Message(now)
+		if c.notifier != nil {
+			if err := c.notifier.Send(ctx, "Task reminder", message); err != nil {
+				c.errorf("Warning: failed to send notification: %s\n", c.tr.Error(err))
+			}
+		}
+		if sender != nil {
+			event := service.WebhookEvent{Event: service.EventTaskReminder, Time: now, Task: reminder.Task}
+			if err := sender.Send(ctx, event); err != nil {
+				c.errorf("Warning: %s\n", c.tr.Error(err))
+			}
+		}
+		c.successf("Reminder: %s\n", message)
+	}
+	return nil
+}
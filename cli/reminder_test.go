@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_Remind tests setting reminders and sending them with the daemon
+func TestCLI_Remind(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	notifier := &fakeNotifier{}
+	h.cli.WithNotifier(notifier)
+
+	if code := h.run("remind", "1", "--at", "in 30m"); code != 0 {
+		t.Fatalf("remind exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	task, _ := h.repo.GetTask(1)
+	if want := tasktest.FixedTime().Add(30 * time.Minute); task.RemindAt == nil || !task.RemindAt.Equal(want) {
+		t.Errorf("RemindAt = %v, want %v", task.RemindAt, want)
+	}
+
+	if code := h.run("daemon", "--once"); code != 0 {
+		t.Fatalf("daemon exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if len(notifier.sent) != 0 {
+		t.Errorf("notifications = %q, want none before the reminder is due", notifier.sent)
+	}
+
+	h.cli.clock = func() time.Time { return tasktest.FixedTime().Add(time.Hour) }
+	if code := h.run("daemon", "--once"); code != 0 {
+		t.Fatalf("daemon exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if len(notifier.sent) != 1 || !strings.Contains(notifier.sent[0], "Task reminder: #1 Buy groceries") {
+		t.Errorf("notifications = %q, want the reminder of task 1", notifier.sent)
+	}
+
+	if code := h.run("remind", "2", "--at", "none"); code != 0 {
+		t.Fatalf("remind none exit code = %d", code)
+	}
+	if code := h.run("remind", "2"); code != 1 {
+		t.Errorf("remind without --at exit code = %d, want 1", code)
+	}
+}
+
+// TestCLI_DaemonInterval tests that the daemon refuses to check without a
+// delay between checks
+func TestCLI_DaemonInterval(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithNotifier(&fakeNotifier{})
+	config := h.cli.config
+	config.Notify.Interval = 0
+	h.cli.WithConfig(config)
+
+	if code := h.run("daemon"); code != 1 {
+		t.Errorf("daemon exit code = %d, want 1", code)
+	}
+	if !strings.Contains(h.stderr.String(), "notify.interval must be positive") {
+		t.Errorf("stderr = %q, want the interval refused", h.stderr.String())
+	}
+}
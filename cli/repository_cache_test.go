@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+// TestCLI_UnitOfWork tests that a command saves the store once
+func TestCLI_UnitOfWork(t *testing.T) {
+	mock := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	cache := repository.NewCachingRepository(mock)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cli := NewCLI(service.NewTaskService(cache), stdout, stderr, tasktest.FixedTime).WithUnitOfWork(cache)
+
+	code := cli.Run(context.Background(), []string{"task-cli", "add", "Plan trip"})
+	if code != 0 {
+		t.Fatalf("add exit code = %d, stderr = %q", code, stderr.String())
+	}
+	if mock.LoadCallCount() != 2 || mock.SaveCallCount() != 1 || mock.TaskCount() != 4 {
+		t.Errorf("loads = %d, saves = %d, tasks = %d; want 2, 1 and 4",
+			mock.LoadCallCount(), mock.SaveCallCount(), mock.TaskCount())
+	}
+
+	mock.WithError(context.DeadlineExceeded)
+	code = cli.Run(context.Background(), []string{"task-cli", "add", "Never saved"})
+	if code != 1 {
+		t.Errorf("add with failing store exit code = %d, want 1", code)
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+// TestCLI_History tests the history command
+func TestCLI_History(t *testing.T) {
+	t.Run("requires git storage", func(t *testing.T) {
+		h := newCLIHarness(t, tasktest.FixedTasks(t))
+		if code := h.run("history", "1"); code != 1 {
+			t.Errorf("Run() exit code = %d, want 1", code)
+		}
+		if !strings.Contains(h.stderr.String(), "git storage") {
+			t.Errorf("stderr = %q", h.stderr.String())
+		}
+	})
+
+	t.Run("shows field changes", func(t *testing.T) {
+		repo := repository.NewGitTaskRepository(repository.NewFileTaskRepository(tasktest.GitTaskFile(t)))
+		h := newCLIHarness(t, nil)
+		h.cli.service = service.NewTaskService(repo)
+
+		h.run("add", "Buy groceries")
+		h.run("mark-in-progress", "1")
+		if code := h.run("history", "1"); code != 0 {
+			t.Fatalf("Run() exit code = %d, stderr = %q", code, h.stderr.String())
+		}
+
+		out := h.stdout.String()
+		for _, want := range []string{"add #1: Buy groceries", `created "Buy groceries"`,
+			"mark-in-progress #1", `status "todo" -> "in-progress"`} {
+			if !strings.Contains(out, want) {
+				t.Errorf("history output missing %q:\n%s", want, out)
+			}
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+)
+
+// fakeDAV serves files like a WebDAV server that leaves ETags out of PUT
+// responses, as some do
+type fakeDAV struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func (d *fakeDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if user, password, _ := r.BasicAuth(); user != "alice" || password != "app-password" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	data, exists := d.files[r.URL.Path]
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write(data)
+	case http.MethodPut:
+		if match := r.Header.Get("If-Match"); match != "" && (!exists || match != etag) ||
+			r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		d.files[r.URL.Path] = body
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		delete(d.files, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newFakeDAV(t *testing.T) (*fakeDAV, repository.WebDAVConfig) {
+	t.Helper()
+	dav := &fakeDAV{files: make(map[string][]byte)}
+	server := httptest.NewServer(dav)
+	t.Cleanup(server.Close)
+	return dav, repository.WebDAVConfig{URL: server.URL + "/remote.php/dav/files/alice/tasks.json",
+		Username: "alice", Password: "app-password"}
+}
+
+// TestCLI_StorageTest tests checking the connection to a store
+func TestCLI_StorageTest(t *testing.T) {
+	_, config := newFakeDAV(t)
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithConfig(repository.Config{WebDAV: config})
+	location := "dav://" + strings.TrimPrefix(config.URL, "http://")
+
+	if code := h.run("storage", "test", location); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	want := "Read 0 tasks from " + config.URL + "\nWrites to " + config.URL + " succeed\n"
+	if h.stdout.String() != want {
+		t.Errorf("output = %q, want %q", h.stdout, want)
+	}
+
+	config.Password = "wrong"
+	h.cli.WithConfig(repository.Config{WebDAV: config})
+	if code := h.run("storage", "test", location); code != 1 {
+		t.Errorf("bad credentials exit code = %d, want 1", code)
+	}
+	if !strings.Contains(h.stderr.String(), repository.ErrWebDAVUnauthorized.Error()) {
+		t.Errorf("stderr = %q", h.stderr)
+	}
+
+	if code := h.run("storage", "test"); code != 0 {
+		t.Fatalf("configured store exit code = %d, stderr: %s", code, h.stderr)
+	}
+	if !strings.Contains(h.stdout.String(), "Read 3 tasks from ") {
+		t.Errorf("output = %q", h.stdout)
+	}
+}
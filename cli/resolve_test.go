@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_DescriptionRef tests that commands accept a description for an ID
+func TestCLI_DescriptionRef(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.run("add", "Write tests")
+
+	if code := h.run("mark-done", "groceries"); code != 0 {
+		t.Fatalf("mark-done exit code = %d, stderr: %s", code, h.stderr)
+	}
+	if item, _ := h.repo.GetTask(1); item.Status != task.StatusDone {
+		t.Errorf("task 1 status = %s, want done", item.Status)
+	}
+
+	if code := h.run("show", "write"); code != exitInvalid {
+		t.Fatalf("show exit code = %d, want %d", code, exitInvalid)
+	}
+	if got := h.stderr.String(); !strings.Contains(got, "#2 Write report (in-progress)\n  #4 Write tests (todo)") {
+		t.Errorf("ambiguous stderr did not list the candidates:\n%s", got)
+	}
+}
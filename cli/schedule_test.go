@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/repository"
+)
+
+// TestCLI_Tick tests the tick command with a state file
+func TestCLI_Tick(t *testing.T) {
+	h := newCLIHarness(t, nil)
+	config := repository.DefaultConfig()
+	config.Schedules = []repository.ScheduleConfig{{Name: "standup", Cron: "0 9 * * 1-5", Description: "Standup notes"}}
+	h.cli.WithConfig(config).WithScheduleFile(filepath.Join(t.TempDir(), "schedules.json"))
+
+	if code := h.run("tick"); code != 0 {
+		t.Fatalf("tick exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if !strings.Contains(h.stdout.String(), "Created task 1: Standup notes") {
+		t.Errorf("stdout = %q, want the created task", h.stdout.String())
+	}
+
+	if code := h.run("tick"); code != 0 {
+		t.Fatalf("tick exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if h.stdout.String() != "Nothing to create\n" || h.repo.TaskCount() != 1 {
+		t.Errorf("second tick stdout = %q with %d tasks, want nothing created", h.stdout.String(), h.repo.TaskCount())
+	}
+
+	config.Schedules[0].Cron = "every monday"
+	h.cli.WithConfig(config)
+	if code := h.run("tick"); code != 1 {
+		t.Errorf("tick with invalid cron exit code = %d, want 1", code)
+	}
+}
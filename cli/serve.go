@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/alnah/task-tracker/service"
+)
+
+func (c *CLI) handleServe(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("serve")
+	grpcAddr := fs.String("grpc", "", "address for the gRPC server (e.g. :9090)")
+	httpAddr := fs.String("http", "", "address for the REST API and web UI (e.g. :8080)")
+	token := fs.String("token", c.config.Server.Token, "bearer token required from HTTP and gRPC clients")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	if *grpcAddr == "" && *httpAddr == "" {
+		c.errorf("Error: at least one listener is required\n")
+		c.errorf("Usage: task-cli serve [--grpc :9090] [--http :8080]\n")
+		return 1
+	}
+	server := c.config.Server
+	server.Token = *token
+	auth, err := service.NewAuthenticator(server)
+	if err != nil {
+		c.errorf("Error: invalid server config: %s\n", c.tr.Error(err))
+		return 1
+	}
+
+	// Stop every listener as soon as one of them fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var servers []func() error
+	if *grpcAddr != "" {
+		c.successf("Serving gRPC on %s\n", *grpcAddr)
+		servers = append(servers, func() error { return service.ServeGRPC(ctx, c.service, auth, *grpcAddr) })
+	}
+	if *httpAddr != "" {
+		c.successf("Serving HTTP on %s (web UI at http://%s/)\n", *httpAddr, uiHost(*httpAddr))
+		metrics := service.NewMetrics(c.service)
+		c.service.WithMetrics(metrics)
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", auth.HTTP(metrics))
+		mux.Handle("/", metrics.Instrument(service.NewHTTPHandler(c.service, auth)))
+		mux.Handle(service.QuickAddPath, auth.RequireHTTP(service.NewQuickAddHandler(c.service)))
+		if secret := c.config.Slack.SigningSecret; secret != "" {
+			mux.Handle("/slack/commands", service.NewSlackHandler(c.service, secret))
+			c.successf("Answering Slack commands at /slack/commands\n")
+		}
+		var handler http.Handler = mux
+		if server.MaxBodyBytes > 0 {
+			handler = service.LimitBody(server.MaxBodyBytes, handler)
+		}
+		if server.RateLimit > 0 {
+			handler = service.NewRateLimiter(server.RateLimit, server.RateBurst).HTTP(handler)
+		}
+		servers = append(servers, func() error { return service.ServeHTTP(ctx, *httpAddr, handler) })
+	}
+	if hooks := c.config.Webhooks; len(hooks.URLs) > 0 {
+		changes, err := c.service.WatchTasks(ctx, service.WatchPollInterval)
+		if err != nil {
+			return c.fail(err)
+		}
+		sender := service.NewWebhookSender(hooks.URLs, hooks.Secret).WithRetries(hooks.Retries, time.Second)
+		c.successf("Sending webhooks to %d URL(s)\n", len(hooks.URLs))
+		servers = append(servers, func() error {
+			service.RunWebhooks(ctx, sender, changes, func(err error) {
+				c.errorf("Warning: %s\n", c.tr.Error(err))
+			})
+			return nil
+		})
+	}
+	c.successf("Press Ctrl+C to stop\n")
+
+	errs := make(chan error, len(servers))
+	for _, serve := range servers {
+		go func() {
+			errs <- serve()
+		}()
+	}
+
+	var firstErr error
+	for range servers {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+	if firstErr != nil {
+		return c.fail(firstErr)
+	}
+
+	return 0
+}
+
+// newFlagSet creates a command flag set reporting errors to stderr
+func (c *CLI) newFlagSet(command string) *flag.FlagSet {
+	fs := flag.NewFlagSet("task-cli "+command, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+// parseFlags parses flags interleaved with positional arguments and
+// returns the positional arguments in order
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// uiHost turns a listen address into one a browser can open
+func uiHost(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" || host == "0.0.0.0" || host == "::" {
+		return net.JoinHostPort("localhost", port)
+	}
+	return addr
+}
+
+// handleAPISpec prints the OpenAPI document of the REST API serve --http
+// exposes, for generating clients
+func (c *CLI) handleAPISpec(_ context.Context, args []string) int {
+	fs := c.newFlagSet("api-spec")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	data, err := json.MarshalIndent(service.OpenAPISpec(c.service.Workflow()), "", "  ")
+	if err != nil {
+		return c.fail(err)
+	}
+	fmt.Fprintln(c.stdout, string(data))
+	return 0
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleSet(ctx context.Context, args []string) int {
+	if len(args) < 2 {
+		c.errorf("Error: ID and at least one field=value are required\n")
+		c.errorf("Usage: task-cli set <id> field=value [field=value]... (description, priority, due, tags, tags+=, tags-=, assignee, estimate)\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	edits, err := c.parseFieldEdits(args[1:])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if err := c.service.SetTaskFields(ctx, id, edits, c.clock()); err != nil {
+		if c.fieldErrorf(err) {
+			c.errorf("Error: task %d was not changed\n", id)
+		}
+		return exitCode(err)
+	}
+
+	c.successf("Task updated successfully\n")
+	return 0
+}
+
+// parseFieldEdits reads field=value arguments, resolving "me" and "none"
+// for the assignee
+func (c *CLI) parseFieldEdits(args []string) ([]task.FieldEdit, error) {
+	edits := make([]task.FieldEdit, 0, len(args))
+	for _, arg := range args {
+		edit, err := task.ParseFieldEdit(arg)
+		if err != nil {
+			return nil, err
+		}
+		if edit.Field == "assignee" {
+			edit.Value = c.resolveUser(edit.Value)
+		}
+		edits = append(edits, edit)
+	}
+	return edits, nil
+}
+
+// fieldErrorf reports an error with one line per refused field rather
+// than only the first, and reports whether it was about fields at all
+func (c *CLI) fieldErrorf(err error) bool {
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) {
+		c.printError(err)
+		return false
+	}
+	for _, fieldErr := range joined.Unwrap() {
+		c.printError(fieldErr)
+	}
+	return true
+}
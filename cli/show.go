@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleShow(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("show")
+	templateSpec := fs.String("template", "", "print the task through this template or named format")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) == 0 {
+		if args, err = c.pickArgs(ctx, false); err != nil {
+			c.printError(err)
+			c.errorf("Usage: task-cli show <id> [--template <template>]\n")
+			return 1
+		}
+	}
+	tmpl, err := c.taskTemplate(*templateSpec)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	t, err := c.service.GetTask(ctx, id)
+	if err != nil {
+		return c.fail(err)
+	}
+	if tmpl != nil {
+		if err := task.RenderTasks(c.stdout, tmpl, []task.Task{*t}); err != nil {
+			return c.fail(err)
+		}
+		return 0
+	}
+
+	fmt.Fprintf(c.stdout, "Task %d: %s\n", t.ID, t.Description)
+	fmt.Fprintf(c.stdout, "UUID:     %s\n", task.TaskUUID(*t))
+	fmt.Fprintf(c.stdout, "Status:   %s\n", c.statusLabel(t.Status))
+	if t.Priority != task.PriorityNone {
+		fmt.Fprintf(c.stdout, "Priority: %s\n", t.Priority)
+	}
+	if tags := slices.DeleteFunc(slices.Clone(t.Tags), func(tag string) bool {
+		return strings.HasPrefix(tag, "@")
+	}); len(tags) > 0 {
+		fmt.Fprintf(c.stdout, "Tags:     %s\n", strings.Join(tags, ", "))
+	}
+	if contexts := t.Contexts(); len(contexts) > 0 {
+		fmt.Fprintf(c.stdout, "Contexts: %s\n", strings.Join(contexts, ", "))
+	}
+	if t.List != "" {
+		fmt.Fprintf(c.stdout, "List:     %s\n", t.List)
+	}
+	if t.Project != "" {
+		fmt.Fprintf(c.stdout, "Project:  %s\n", t.Project)
+	}
+	if t.Milestone != "" {
+		fmt.Fprintf(c.stdout, "Milestone: %s\n", t.Milestone)
+	}
+	if t.Sprint != "" {
+		fmt.Fprintf(c.stdout, "Sprint:   %s\n", t.Sprint)
+	}
+	if t.Assignee != "" {
+		fmt.Fprintf(c.stdout, "Assignee: %s\n", t.Assignee)
+	}
+	if t.DueAt != nil {
+		fmt.Fprintf(c.stdout, "Due:      %s\n", c.formatTime(*t.DueAt, "2006-01-02 15:04"))
+	}
+	if t.Estimate != 0 {
+		fmt.Fprintf(c.stdout, "Estimate: %s\n", task.FormatDuration(time.Duration(t.Estimate)))
+	}
+	if t.Pomodoros > 0 {
+		fmt.Fprintf(c.stdout, "Pomodoro: %d completed\n", t.Pomodoros)
+	}
+	if t.IsSnoozed(c.clock()) {
+		fmt.Fprintf(c.stdout, "Snoozed:  until %s\n", c.formatTime(*t.StartAt, "2006-01-02 15:04"))
+	}
+	if t.RemindAt != nil {
+		fmt.Fprintf(c.stdout, "Remind:   %s\n", c.formatTime(*t.RemindAt, "2006-01-02 15:04"))
+	}
+	fmt.Fprintf(c.stdout, "Created:  %s%s\n", c.formatTime(t.CreatedAt, "2006-01-02 15:04:05"), byUser(t.CreatedBy))
+	fmt.Fprintf(c.stdout, "Updated:  %s%s\n", c.formatTime(t.UpdatedAt, "2006-01-02 15:04:05"), byUser(t.UpdatedBy))
+
+	if done, total := t.ChecklistProgress(); total > 0 {
+		fmt.Fprintf(c.stdout, "Checklist: %d/%d\n", done, total)
+		for i, item := range t.Checklist {
+			mark := "[ ]"
+			if item.Done {
+				mark = "[x]"
+			}
+			fmt.Fprintf(c.stdout, "  %d. %s %s\n", i+1, mark, item.Text)
+		}
+	}
+	if len(t.Attachments) > 0 {
+		fmt.Fprintln(c.stdout, "Attachments:")
+		for i, attachment := range t.Attachments {
+			if attachment.Label != "" {
+				fmt.Fprintf(c.stdout, "  %d. %s  %s\n", i+1, attachment.Label, attachment.Target)
+			} else {
+				fmt.Fprintf(c.stdout, "  %d. %s\n", i+1, attachment.Target)
+			}
+		}
+	}
+	c.printRelations(ctx, *t)
+	if len(t.Notes) > 0 {
+		fmt.Fprintln(c.stdout, "Notes:")
+		for _, note := range t.Notes {
+			fmt.Fprintf(c.stdout, "  %s  %s\n", c.formatTime(note.CreatedAt, "2006-01-02 15:04"), note.Text)
+		}
+	}
+	if len(t.ExternalRefs) > 0 {
+		fmt.Fprintln(c.stdout, "Links:")
+		for _, system := range slices.Sorted(maps.Keys(t.ExternalRefs)) {
+			fmt.Fprintf(c.stdout, "  %s: %s\n", system, t.ExternalRefs[system])
+		}
+	}
+
+	return 0
+}
+
+// byUser renders attribution after a timestamp, or nothing when unknown
+func byUser(user string) string {
+	if user == "" {
+		return ""
+	}
+	return " by " + user
+}
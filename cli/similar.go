@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// similarShown is how many similar tasks add lists in its warning
+const similarShown = 3
+
+// checkSimilar warns before adding a task that looks like a duplicate of an
+// open one. In a terminal it offers to bump the existing task instead;
+// otherwise the task is refused until add is run again with --force. done
+// reports whether add should stop, with code as its exit code.
+func (c *CLI) checkSimilar(ctx context.Context, description string) (code int, done bool) {
+	similar, err := c.service.SimilarTasks(ctx, description)
+	if err != nil {
+		c.printError(err)
+		return exitCode(err), true
+	}
+	if len(similar) == 0 {
+		return 0, false
+	}
+
+	c.errorf("Warning: %q looks like an open task:\n", description)
+	for _, match := range similar[:min(len(similar), similarShown)] {
+		c.errorf("  #%d %s (%s, %.0f%% similar)\n", match.Task.ID, match.Task.Description,
+			c.statusLabel(match.Task.Status), match.Score*100)
+	}
+	if c.prompter == nil || c.stdinPiped() {
+		c.errorf("Error: task not added, use --force to add it anyway\n")
+		return 1, true
+	}
+
+	existing := similar[0].Task
+	answer, err := c.prompter.Ask(fmt.Sprintf("Bump #%d instead, add anyway, or cancel? [b/a/N]", existing.ID))
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.printError(err)
+		return exitCode(err), true
+	}
+	switch strings.ToLower(answer) {
+	case "a", "add":
+		return 0, false
+	case "b", "bump":
+		bumped, err := c.service.BumpTask(ctx, existing.ID)
+		if err != nil {
+			c.printError(err)
+			return exitCode(err), true
+		}
+		if c.quiet {
+			fmt.Fprintln(c.stdout, bumped.ID)
+		} else {
+			c.successf("Task %d bumped to %s priority instead\n", bumped.ID, bumped.Priority)
+		}
+		return 0, true
+	default:
+		c.errorf("Error: task not added\n")
+		return 1, true
+	}
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_AddSimilar tests the warning when adding a likely duplicate
+func TestCLI_AddSimilar(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	if code := h.run("add", "buy grocereis"); code != 1 {
+		t.Fatalf("add exit code = %d, want 1", code)
+	}
+	if got := h.stderr.String(); !strings.Contains(got, "#1 Buy groceries (TODO, 85% similar)") ||
+		!strings.Contains(got, "use --force") {
+		t.Errorf("stderr = %q", got)
+	}
+	if code := h.run("add", "buy grocereis", "--force"); code != 0 {
+		t.Fatalf("add --force exit code = %d, stderr: %s", code, h.stderr)
+	}
+
+	h = newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithPrompter(NewLinePrompter(strings.NewReader("b\n"), &bytes.Buffer{}))
+	if code := h.run("add", "Buy groceries!"); code != 0 {
+		t.Fatalf("add exit code = %d, stderr: %s", code, h.stderr)
+	}
+	if out := h.stdout.String(); out != "Task 1 bumped to low priority instead\n" {
+		t.Errorf("bump output = %q", out)
+	}
+	if tasks := h.repo.GetStoredTasks(); len(tasks) != 3 {
+		t.Errorf("%d tasks after bumping, want no task added", len(tasks))
+	}
+
+	h.cli.WithPrompter(NewLinePrompter(strings.NewReader("a\n"), &bytes.Buffer{}))
+	if code := h.run("add", "Buy groceries!"); code != 0 {
+		t.Fatalf("add anyway exit code = %d, stderr: %s", code, h.stderr)
+	}
+	if out := h.stdout.String(); out != "Task added successfully (ID: 4)\n" {
+		t.Errorf("add anyway output = %q", out)
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleSnapshot(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "create":
+		name := ""
+		if len(args) > 1 {
+			name = args[1]
+		}
+		snapshot, err := c.service.CreateSnapshot(ctx, name, c.clock())
+		if err != nil {
+			return c.fail(err)
+		}
+		c.successf(c.tr.Plural(len(snapshot.Tasks), "Snapshot %s saved with %d tasks\n"), snapshot.Name, len(snapshot.Tasks))
+		return 0
+
+	case "list":
+		snapshots, err := c.service.Snapshots(ctx)
+		if err != nil {
+			return c.fail(err)
+		}
+		if len(snapshots) == 0 {
+			c.successf("No snapshots saved\n")
+			return 0
+		}
+		for _, snapshot := range snapshots {
+			fmt.Fprintf(c.stdout, "%s  %s  %s\n", snapshot.Name,
+				c.formatTime(snapshot.CreatedAt.Local(), "2006-01-02 15:04"),
+				c.tr.Sprintf(c.tr.Plural(len(snapshot.Tasks), "%d tasks"), len(snapshot.Tasks)))
+		}
+		return 0
+
+	default:
+		c.errorf("Unknown snapshot command: %s\n", args[0])
+		c.errorf("Usage: task-cli snapshot create [name] | list\n")
+		return 1
+	}
+}
+
+func (c *CLI) handleDiff(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("diff")
+	asJSON := fs.Bool("json", false, "print the changes as JSON")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) == 0 || len(args) > 2 {
+		c.errorf("Error: Snapshot name is required\n")
+		c.errorf("Usage: task-cli diff <snapshot> [<other snapshot>] [--json]\n")
+		return 1
+	}
+	to := task.CurrentState
+	if len(args) == 2 {
+		to = args[1]
+	}
+
+	diff, err := c.service.DiffSnapshots(ctx, args[0], to)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if *asJSON {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return c.fail(err)
+		}
+		fmt.Fprintf(c.stdout, "%s\n", data)
+		return 0
+	}
+
+	if len(diff.Changes) == 0 {
+		c.successf("No changes between %s and %s\n", diff.From, diff.To)
+		return 0
+	}
+	marks := map[task.ChangeType]string{task.ChangeAdded: "+", task.ChangeDeleted: "-", task.ChangeUpdated: "~"}
+	for _, change := range diff.Changes {
+		fmt.Fprintf(c.stdout, "%s #%d %s\n", marks[change.Type], change.ID, change.Description)
+		for _, field := range change.Fields {
+			fmt.Fprintf(c.stdout, "    %s: %q -> %q\n", field.Field, field.Old, field.New)
+		}
+	}
+	c.successf("%d added, %d removed, %d changed\n",
+		diff.Count(task.ChangeAdded), diff.Count(task.ChangeDeleted), diff.Count(task.ChangeUpdated))
+	return 0
+}
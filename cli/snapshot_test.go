@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+func newSnapshotHarness(t *testing.T) *cliHarness {
+	t.Helper()
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.service.WithSnapshots(repository.NewFileSnapshotRepository(filepath.Join(t.TempDir(), "tasks.snapshots")))
+	return h
+}
+
+// TestCLI_SnapshotDiff tests comparing a snapshot with later changes
+func TestCLI_SnapshotDiff(t *testing.T) {
+	h := newSnapshotHarness(t)
+
+	if code := h.run("snapshot", "create", "monday"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	if h.stdout.String() != "Snapshot monday saved with 3 tasks\n" {
+		t.Errorf("output = %q", h.stdout)
+	}
+	if code := h.run("snapshot", "create", "monday"); code != exitRefused {
+		t.Errorf("duplicate name exit code = %d, want %d", code, exitRefused)
+	}
+
+	if code := h.run("diff", "monday"); code != 0 || h.stdout.String() != "No changes between monday and current\n" {
+		t.Errorf("diff without changes = %d, %q", code, h.stdout)
+	}
+
+	h.run("mark-done", "1")
+	h.run("delete", "2", "--yes")
+	h.run("add", "Water plants")
+	if code := h.run("diff", "monday"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	for _, want := range []string{
+		"~ #1 Buy groceries\n    status: \"todo\" -> \"done\"\n",
+		"- #2 Write report\n",
+		"+ #4 Water plants\n",
+		"1 added, 1 removed, 1 changed\n",
+	} {
+		if !strings.Contains(h.stdout.String(), want) {
+			t.Errorf("diff missing %q:\n%s", want, h.stdout)
+		}
+	}
+
+	if code := h.run("diff", "monday", "--json"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	var diff task.SnapshotDiff
+	if err := json.Unmarshal(h.stdout.Bytes(), &diff); err != nil {
+		t.Fatalf("diff --json is not JSON: %v", err)
+	}
+	if diff.From != "monday" || diff.To != task.CurrentState || len(diff.Changes) != 3 {
+		t.Errorf("diff = %+v", diff)
+	}
+
+	// Two snapshots compare with each other
+	if code := h.run("snapshot", "create", "tuesday"); code != 0 {
+		t.Fatal(h.stderr)
+	}
+	if code := h.run("diff", "monday", "tuesday", "--json"); code != 0 {
+		t.Fatal(h.stderr)
+	}
+	if err := json.Unmarshal(h.stdout.Bytes(), &diff); err != nil || len(diff.Changes) != 3 {
+		t.Errorf("diff between snapshots = %+v, %v", diff, err)
+	}
+
+	if code := h.run("diff", "sunday"); code != exitNotFound {
+		t.Errorf("unknown snapshot exit code = %d, want %d", code, exitNotFound)
+	}
+	if code := h.run("snapshot", "list"); code != 0 {
+		t.Fatal(h.stderr)
+	}
+	for _, want := range []string{"monday  ", "tuesday  ", "3 tasks\n"} {
+		if !strings.Contains(h.stdout.String(), want) {
+			t.Errorf("snapshot list missing %q:\n%s", want, h.stdout)
+		}
+	}
+}
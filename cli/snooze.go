@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleSnooze(ctx context.Context, args []string) int {
+	if len(args) > 1 && args[1] == "until" {
+		args = append([]string{args[0]}, args[2:]...)
+	}
+	if len(args) < 2 {
+		c.errorf("Error: ID and date are required\n")
+		c.errorf("Usage: task-cli snooze <id> [until] <when|none>\n")
+		return 1
+	}
+
+	id, err := c.service.ResolveTaskID(ctx, args[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	var until *time.Time
+	if when := strings.Join(args[1:], " "); when != "none" {
+		start, err := task.ParseWhen(when, c.clock())
+		if err != nil {
+			return c.fail(err)
+		}
+		until = &start
+	}
+
+	if err := c.service.SnoozeTask(ctx, id, until); err != nil {
+		return c.fail(err)
+	}
+
+	if until == nil {
+		c.successf("Task %d woken\n", id)
+	} else {
+		c.successf("Task %d snoozed until %s\n", id, c.formatTime(*until, "Mon 2006-01-02 15:04"))
+	}
+	return 0
+}
+
+// wakeTasks wakes the snoozed tasks whose time has come, announcing each
+// one. A failed delivery is reported but does not stop the others.
+func (c *CLI) wakeTasks(ctx context.Context, sender *service.WebhookSender) error {
+	now := c.clock()
+	woken, err := c.service.WakeTasks(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range woken {
+		message := fmt.Sprintf("#%d %s", task.ID, task.Description)
+		if c.notifier != nil {
+			if err := c.notifier.Send(ctx, "Task back from snooze", message); err != nil {
+				c.errorf("Warning: failed to send notification: %s\n", c.tr.Error(err))
+			}
+		}
+		if sender != nil {
+			event := service.WebhookEvent{Event: service.EventTaskWoken, Time: now, Task: task}
+			if err := sender.Send(ctx, event); err != nil {
+				c.errorf("Warning: %s\n", c.tr.Error(err))
+			}
+		}
+		c.successf("Awake: %s\n", message)
+	}
+	return nil
+}
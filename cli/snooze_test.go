@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_Snooze tests hiding a task until a date and waking it with the
+// daemon
+func TestCLI_Snooze(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	notifier := &fakeNotifier{}
+	h.cli.WithNotifier(notifier)
+
+	if code := h.run("snooze", "1", "until", "tomorrow"); code != 0 {
+		t.Fatalf("snooze exit code = %d, stderr = %q", code, h.stderr)
+	}
+	if h.stdout.String() != "Task 1 snoozed until Tue 2024-01-02 00:00\n" {
+		t.Errorf("output = %q", h.stdout)
+	}
+
+	if h.run("list"); strings.Contains(h.stdout.String(), "Buy groceries") {
+		t.Errorf("list shows the snoozed task:\n%s", h.stdout)
+	}
+	if h.run("list", "--all"); !strings.Contains(h.stdout.String(), "Buy groceries") {
+		t.Errorf("list --all hides the snoozed task:\n%s", h.stdout)
+	}
+	if h.run("show", "1"); !strings.Contains(h.stdout.String(), "Snoozed:  until 2024-01-02 00:00\n") {
+		t.Errorf("show:\n%s", h.stdout)
+	}
+
+	if code := h.run("daemon", "--once"); code != 0 || len(notifier.sent) != 0 {
+		t.Fatalf("daemon before the date = %d, %q, stderr = %q", code, notifier.sent, h.stderr)
+	}
+	h.cli.clock = func() time.Time { return tasktest.FixedTime().Add(24 * time.Hour) }
+	if code := h.run("daemon", "--once"); code != 0 {
+		t.Fatalf("daemon exit code = %d, stderr = %q", code, h.stderr)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0] != "Task back from snooze: #1 Buy groceries" {
+		t.Errorf("notifications = %q, want task 1 waking", notifier.sent)
+	}
+	if task, _ := h.repo.GetTask(1); task.StartAt != nil {
+		t.Errorf("StartAt = %v after waking, want nil", task.StartAt)
+	}
+
+	// Waking is announced once
+	h.run("daemon", "--once")
+	if len(notifier.sent) != 1 {
+		t.Errorf("notifications = %q after a second run", notifier.sent)
+	}
+}
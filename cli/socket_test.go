@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_DaemonSocket tests running commands through daemon --socket with
+// task-cli client
+func TestCLI_DaemonSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "tasks.daemon.sock")
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithSocketFile(socket)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exited := make(chan int)
+	go func() { exited <- h.cli.Run(ctx, []string{"task-cli", "daemon", "--socket"}) }()
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(socket); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("daemon did not create its socket")
+		}
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	client := NewCLI(service.NewTaskService(tasktest.NewMockRepository()), stdout, stderr, tasktest.FixedTime).WithSocketFile(socket)
+	run := func(args ...string) int {
+		stdout.Reset()
+		stderr.Reset()
+		return client.Run(context.Background(), append([]string{"task-cli", "client"}, args...))
+	}
+
+	if code := run("summary"); code != 0 || stdout.String() != "1 todo, 1 in-progress, 1 done, 0 overdue\n" {
+		t.Errorf("client summary = %d, %q, stderr %q", code, stdout.String(), stderr.String())
+	}
+	if code := run("add", "Plan trip"); code != 0 || h.repo.TaskCount() != 4 {
+		t.Errorf("client add = %d, tasks = %d, stderr %q", code, h.repo.TaskCount(), stderr.String())
+	}
+	if code := run("delete", "1"); code != 1 || !strings.Contains(stderr.String(), "--yes") || h.repo.TaskCount() != 4 {
+		t.Errorf("client delete without --yes = %d, stderr %q", code, stderr.String())
+	}
+	if code := run("watch"); code != 1 || !strings.Contains(stderr.String(), "cannot run through the daemon") {
+		t.Errorf("client watch = %d, stderr %q", code, stderr.String())
+	}
+
+	cancel()
+	if code := <-exited; code != 0 {
+		t.Errorf("daemon exit code = %d, stderr %q", code, h.stderr.String())
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("socket file left behind: %v", err)
+	}
+	if code := run("summary"); code != 1 || !strings.Contains(stderr.String(), "daemon not reachable") {
+		t.Errorf("client without a daemon = %d, stderr %q", code, stderr.String())
+	}
+}
+
+// TestCLI_DaemonProxy tests that commands go to a running daemon without
+// client, and run in the process otherwise
+func TestCLI_DaemonProxy(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "tasks.daemon.sock")
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithSocketFile(socket)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exited := make(chan int)
+	go func() { exited <- h.cli.Run(ctx, []string{"task-cli", "daemon", "--socket"}) }()
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(socket); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("daemon did not create its socket")
+		}
+	}
+
+	// The local store is empty, so output about three tasks came from the
+	// daemon
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	local := tasktest.NewMockRepository()
+	cli := NewCLI(service.NewTaskService(local), stdout, stderr, tasktest.FixedTime).
+		WithSocketFile(socket).
+		WithStdin(strings.NewReader("From stdin\n"))
+	run := func(args ...string) int {
+		stdout.Reset()
+		stderr.Reset()
+		return cli.Run(context.Background(), append([]string{"task-cli"}, args...))
+	}
+
+	if code := run("summary"); code != 0 || stdout.String() != "1 todo, 1 in-progress, 1 done, 0 overdue\n" {
+		t.Errorf("proxied summary = %d, %q, stderr %q", code, stdout.String(), stderr.String())
+	}
+	if code := run("-q", "add", "--force", "Plan trip"); code != 0 || stdout.String() != "4\n" || h.repo.TaskCount() != 4 {
+		t.Errorf("proxied add = %d, %q, tasks = %d, stderr %q", code, stdout.String(), h.repo.TaskCount(), stderr.String())
+	}
+	// Reading stdin and --no-daemon stay in this process
+	if code := run("add", "-"); code != 0 || local.TaskCount() != 1 || h.repo.TaskCount() != 4 {
+		t.Errorf("add - = %d, local tasks = %d, stderr %q", code, local.TaskCount(), stderr.String())
+	}
+	// So does add without --force, which may ask about a similar task
+	if code := run("add", "Call mom"); code != 0 || local.TaskCount() != 2 || h.repo.TaskCount() != 4 {
+		t.Errorf("add without --force = %d, local tasks = %d, stderr %q", code, local.TaskCount(), stderr.String())
+	}
+	// So does a command given no ID, which needs the picker of this process
+	cli.WithPrompter(NewLinePrompter(strings.NewReader("1\n"), &bytes.Buffer{})).WithStdin(nil)
+	if code := run("mark-done", "--force"); code != 0 {
+		t.Errorf("mark-done without an ID = %d, stderr %q", code, stderr.String())
+	}
+	if item, _ := local.GetTask(1); item.Status != task.StatusDone {
+		t.Errorf("local task 1 status = %s, want done after picking it here", item.Status)
+	}
+	if item, _ := h.repo.GetTask(1); item.Status != task.StatusTodo {
+		t.Errorf("daemon task 1 status = %s, want it left alone", item.Status)
+	}
+	if code := run("show", "--template", "{{.Description}}", "2"); code != 0 || stdout.String() != "Write report\n" {
+		t.Errorf("proxied show with an ID = %d, %q, stderr %q", code, stdout.String(), stderr.String())
+	}
+	if code := run("--no-daemon", "summary"); code != 0 || stdout.String() != "1 todo, 0 in-progress, 1 done, 0 overdue\n" {
+		t.Errorf("--no-daemon summary = %d, %q", code, stdout.String())
+	}
+
+	cancel()
+	if code := <-exited; code != 0 {
+		t.Errorf("daemon exit code = %d, stderr %q", code, h.stderr.String())
+	}
+
+	// A socket file nobody answers on is left by a daemon that crashed
+	if err := os.WriteFile(socket, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code := run("summary"); code != 0 || stdout.String() != "1 todo, 0 in-progress, 1 done, 0 overdue\n" {
+		t.Errorf("summary without a daemon = %d, %q, stderr %q", code, stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+func (c *CLI) handleSplit(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("split")
+	filters := c.listFlags(fs)
+	output := fs.String("output", "", "task file or store URL to split tasks into")
+	fs.StringVar(output, "o", "", "shorthand for --output")
+	move := fs.Bool("move", false, "delete the tasks here once copied")
+	renumber := fs.Bool("renumber", false, "give the tasks the next free IDs of the target")
+	dryRun := fs.Bool("dry-run", false, "show what would be split without writing")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+	if *output == "" {
+		c.errorf("Error: --output is required\n")
+		c.errorf("Usage: task-cli split <list filters> -o <file> [--move] [--renumber] [--dry-run]\n")
+		return 1
+	}
+	// Splitting off everything is a copy, or with --move an accident
+	if len(viewFlags(*filters)) == 0 {
+		c.errorf("Error: split needs at least one list filter, such as --tag or --project\n")
+		return 1
+	}
+
+	tasks, err := c.filteredTasks(ctx, *filters)
+	if err != nil {
+		return c.fail(err)
+	}
+	target, err := repository.OpenRepository(*output, c.config)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	report, err := c.service.Split(ctx, tasks, service.SplitOptions{
+		Target:   target,
+		Move:     *move,
+		Renumber: *renumber,
+		DryRun:   *dryRun,
+	})
+	if err != nil {
+		return c.fail(err)
+	}
+
+	// Whole messages rather than a verb, so that they translate
+	format, renumberedFormat := "Copied task %d: %s\n", "Copied task %d as %d: %s\n"
+	switch {
+	case *dryRun && *move:
+		format, renumberedFormat = "Would move task %d: %s\n", "Would move task %d as %d: %s\n"
+	case *dryRun:
+		format, renumberedFormat = "Would copy task %d: %s\n", "Would copy task %d as %d: %s\n"
+	case *move:
+		format, renumberedFormat = "Moved task %d: %s\n", "Moved task %d as %d: %s\n"
+	}
+	for _, task := range report.Tasks {
+		if from, ok := report.Renumbered[task.ID]; ok && from != task.ID {
+			c.successf(renumberedFormat, from, task.ID, task.Description)
+		} else {
+			c.successf(format, task.ID, task.Description)
+		}
+	}
+	c.successf(c.tr.Plural(len(report.Tasks), "%d tasks split into %s\n"), len(report.Tasks), *output)
+	if *dryRun {
+		c.successf("Dry run, nothing was saved\n")
+	}
+	return 0
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+func taggedTasks(t *testing.T) []task.Task {
+	t.Helper()
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].Tags = []string{"home"}
+	tasks[2].Tags = []string{"home"}
+	return tasks
+}
+
+// TestCLI_Split tests copying and moving tasks into another file
+func TestCLI_Split(t *testing.T) {
+	h := newCLIHarness(t, taggedTasks(t))
+	file := filepath.Join(t.TempDir(), "home.json")
+
+	if code := h.run("split", "--tag", "home", "-o", file); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	want := "Copied task 1: Buy groceries\nCopied task 3: Call mom\n2 tasks split into " + file + "\n"
+	if h.stdout.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", h.stdout, want)
+	}
+	if h.repo.TaskCount() != 3 {
+		t.Errorf("copy left %d tasks, want 3", h.repo.TaskCount())
+	}
+	copied, err := repository.NewFileTaskRepository(file).Load(context.Background())
+	if err != nil || len(copied) != 2 || copied[1].ID != 3 || copied[1].UUID == "" {
+		t.Fatalf("target = %+v, %v", copied, err)
+	}
+
+	// The same IDs are now taken in the target
+	if code := h.run("split", "--tag", "home", "-o", file, "--move"); code != exitRefused {
+		t.Errorf("colliding split exit code = %d, want %d", code, exitRefused)
+	}
+	if code := h.run("split", "--tag", "home", "-o", file, "--move", "--renumber"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	want = "Moved task 1 as 4: Buy groceries\nMoved task 3 as 5: Call mom\n2 tasks split into " + file + "\n"
+	if h.stdout.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", h.stdout, want)
+	}
+	if h.repo.TaskCount() != 1 || !h.repo.HasTask(2) {
+		t.Errorf("move left %v", h.repo.GetStoredTasks())
+	}
+
+	if code := h.run("split", "-o", file); code != 1 {
+		t.Errorf("split without filters exit code = %d, want 1", code)
+	}
+}
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleSprint(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{"status"}
+	}
+
+	switch args[0] {
+	case "create":
+		return c.handleSprintCreate(ctx, args[1:])
+	case "list":
+		return c.handleSprintList(ctx)
+	case "add":
+		return c.handleSprintAdd(ctx, args[1:])
+	case "remove":
+		return c.handleSprintRemove(ctx, args[1:])
+	case "status":
+		return c.handleSprintStatus(ctx, args[1:])
+	case "close":
+		return c.handleSprintClose(ctx, args[1:])
+	case "rollover":
+		return c.handleSprintRollover(ctx)
+	default:
+		c.errorf("Error: Unknown sprint command '%s'\n", args[0])
+		c.errorf("Usage: task-cli sprint create|list|add|remove|status|close|rollover\n")
+		return 1
+	}
+}
+
+func (c *CLI) handleSprintCreate(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("sprint create")
+	start := fs.String("start", "", "first day of the sprint, e.g. monday or 2024-05-13")
+	end := fs.String("end", "", "last day of the sprint, e.g. \"next friday\" or 2024-05-24")
+	capacity := fs.String("capacity", "", "effort the sprint can hold, e.g. 40h")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) == 0 || *start == "" || *end == "" {
+		c.errorf("Error: Name, start and end are required\n")
+		c.errorf("Usage: task-cli sprint create <name> --start <when> --end <when> [--capacity 40h]\n")
+		return 1
+	}
+
+	now := c.clock()
+	sprint := task.Sprint{Name: args[0]}
+	if sprint.Start, err = task.ParseWhen(*start, now); err == nil {
+		// The end date is the last day of the sprint, included
+		sprint.End, err = task.ParseDeadline(*end, now)
+	}
+	if err == nil && *capacity != "" {
+		var d time.Duration
+		d, err = task.ParseEffort(*capacity)
+		sprint.Capacity = task.Duration(d)
+	}
+	if err != nil {
+		return c.fail(err)
+	}
+
+	created, err := c.service.CreateSprint(ctx, sprint)
+	if err != nil {
+		return c.fail(err)
+	}
+	c.successf("Sprint %s created, %s to %s\n", created.Name,
+		c.formatTime(created.Start, "2006-01-02"), c.formatTime(created.End, "2006-01-02"))
+	return 0
+}
+
+func (c *CLI) handleSprintList(ctx context.Context) int {
+	sprints, err := c.service.Sprints(ctx)
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(sprints) == 0 {
+		c.successf("No sprints found\n")
+		return 0
+	}
+
+	now := c.clock()
+	for _, sprint := range sprints {
+		fmt.Fprintf(c.stdout, "%-12s %s to %s  %s\n", sprint.Name,
+			sprint.Start.Format("2006-01-02"), sprint.End.Format("2006-01-02"), sprintState(sprint, now))
+	}
+	return 0
+}
+
+// sprintState describes where a sprint stands at now
+func sprintState(sprint task.Sprint, now time.Time) string {
+	switch {
+	case sprint.IsClosed():
+		return "closed"
+	case sprint.ActiveAt(now):
+		return "active"
+	case now.Before(sprint.Start):
+		return "planned"
+	default:
+		return "ended"
+	}
+}
+
+func (c *CLI) handleSprintAdd(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("sprint add")
+	name := fs.String("sprint", "", "commit to this sprint instead of the active one")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) == 0 {
+		c.errorf("Error: ID is required\n")
+		c.errorf("Usage: task-cli sprint add <id|from-to>... [--sprint <name>]\n")
+		return 1
+	}
+
+	ids, err := c.selectTaskIDs(ctx, args)
+	if err != nil {
+		return c.fail(err)
+	}
+	status, err := c.service.SetTaskSprint(ctx, ids, *name, c.clock())
+	if err != nil {
+		return c.fail(err)
+	}
+
+	c.successf(c.tr.Plural(len(ids), "%d tasks added to sprint %s\n"), len(ids), status.Name)
+	if status.OverCapacity() && !c.quiet {
+		c.errorf("Warning: sprint %s holds %s of work, over its capacity of %s\n", status.Name,
+			task.FormatDuration(status.CommittedEffort), task.FormatDuration(time.Duration(status.Capacity)))
+	}
+	return 0
+}
+
+func (c *CLI) handleSprintRemove(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		c.errorf("Error: ID is required\n")
+		c.errorf("Usage: task-cli sprint remove <id|from-to>...\n")
+		return 1
+	}
+	ids, err := c.selectTaskIDs(ctx, args)
+	if err != nil {
+		return c.fail(err)
+	}
+	if err := c.service.RemoveFromSprint(ctx, ids); err != nil {
+		return c.fail(err)
+	}
+	c.successf(c.tr.Plural(len(ids), "%d tasks removed from their sprint\n"), len(ids))
+	return 0
+}
+
+// selectTaskIDs returns the IDs of the tasks selectTasks finds
+func (c *CLI) selectTaskIDs(ctx context.Context, refs []string) ([]int, error) {
+	tasks, err := c.selectTasks(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, task.ErrTaskNotFound
+	}
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids, nil
+}
+
+func (c *CLI) handleSprintStatus(ctx context.Context, args []string) int {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	status, err := c.service.SprintStatus(ctx, name, c.clock())
+	if err != nil {
+		return c.fail(err)
+	}
+	c.printSprintStatus(*status)
+	return 0
+}
+
+// printSprintStatus prints the commitment of a sprint against its capacity
+// and the tasks left, marking those carried over from the previous sprint
+func (c *CLI) printSprintStatus(status task.SprintStatus) {
+	fmt.Fprintf(c.stdout, "%s  %s to %s  %s\n", status.Name,
+		status.Start.Format("2006-01-02"), status.End.Format("2006-01-02"), sprintState(status.Sprint, c.clock()))
+
+	committed := task.FormatDuration(status.CommittedEffort)
+	if status.Capacity > 0 {
+		committed += " of " + task.FormatDuration(time.Duration(status.Capacity)) + " capacity"
+	}
+	fmt.Fprintf(c.stdout, "Committed: %s, %s\n",
+		c.tr.Sprintf(c.tr.Plural(status.Committed, "%d tasks"), status.Committed), committed)
+	if status.OverCapacity() {
+		fmt.Fprintln(c.stdout, "  OVER CAPACITY")
+	}
+	fmt.Fprintf(c.stdout, "Done:      %d/%d, %s\n", status.Done, status.Committed, task.FormatDuration(status.DoneEffort))
+
+	if len(status.Unfinished) == 0 {
+		return
+	}
+	label := "Unfinished:"
+	if status.IsClosed() {
+		label = "Carried over:"
+	}
+	fmt.Fprintln(c.stdout, label)
+	for _, task := range status.Unfinished {
+		line := fmt.Sprintf("  #%d %s (%s)", task.ID, task.Description, task.Status)
+		if status.CarriedOver(task.ID) {
+			line += " carried over"
+		}
+		fmt.Fprintln(c.stdout, line)
+	}
+}
+
+func (c *CLI) handleSprintClose(ctx context.Context, args []string) int {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	status, err := c.service.CloseSprint(ctx, name, c.clock())
+	if err != nil {
+		return c.fail(err)
+	}
+
+	c.successf("Sprint %s closed, %d/%d tasks done\n", status.Name, status.Done, status.Committed)
+	if len(status.Unfinished) > 0 && !c.quiet {
+		c.errorf("Warning: %s carry over, move them with sprint add --sprint <next>\n",
+			c.tr.Sprintf(c.tr.Plural(len(status.Unfinished), "%d tasks"), len(status.Unfinished)))
+	}
+	return 0
+}
+
+func (c *CLI) handleSprintRollover(ctx context.Context) int {
+	from, to, moved, err := c.service.Rollover(ctx, c.clock())
+	if err != nil {
+		return c.fail(err)
+	}
+
+	c.successf("Sprint %s closed, %s moved to %s\n", from.Name,
+		c.tr.Sprintf(c.tr.Plural(len(moved), "%d tasks"), len(moved)), to.Name)
+	for _, task := range moved {
+		if c.quiet {
+			fmt.Fprintln(c.stdout, task.ID)
+			continue
+		}
+		fmt.Fprintf(c.stdout, "  #%d %s\n", task.ID, task.Description)
+	}
+	return 0
+}
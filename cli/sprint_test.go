@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+)
+
+// TestCLI_Sprint tests creating sprints, committing tasks and rolling over
+func TestCLI_Sprint(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.service.WithSprints(repository.NewFileSprintRepository(filepath.Join(t.TempDir(), "sprints.json")))
+
+	if code := h.run("sprint", "create", "2024-W01", "--start", "2024-01-01", "--end", "2024-01-05", "--capacity", "1h"); code != 0 {
+		t.Fatalf("create exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("sprint", "create", "2024-W01", "--start", "2024-01-08", "--end", "2024-01-12"); code != exitRefused {
+		t.Errorf("duplicate create exit code = %d, want %d", code, exitRefused)
+	}
+	if code := h.run("sprint", "create", "2024-W02", "--start", "2024-01-08", "--end", "2024-01-12"); code != 0 {
+		t.Fatalf("create exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+
+	h.run("estimate", "1", "2h")
+	if code := h.run("sprint", "add", "1-3"); code != 0 {
+		t.Fatalf("add exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if out := h.stdout.String(); out != "3 tasks added to sprint 2024-W01\n" {
+		t.Errorf("add output = %q", out)
+	}
+	if !strings.Contains(h.stderr.String(), "over its capacity of 1h") {
+		t.Errorf("add stderr = %q, want a capacity warning", h.stderr.String())
+	}
+
+	h.run("sprint", "status")
+	want := "2024-W01  2024-01-01 to 2024-01-05  active\n" +
+		"Committed: 3 tasks, 2h of 1h capacity\n" +
+		"  OVER CAPACITY\n" +
+		"Done:      1/3, 0m\n" +
+		"Unfinished:\n" +
+		"  #1 Buy groceries (todo)\n" +
+		"  #2 Write report (in-progress)\n"
+	if got := h.stdout.String(); got != want {
+		t.Errorf("status output = %q, want %q", got, want)
+	}
+
+	if code := h.run("sprint", "rollover"); code != 0 {
+		t.Fatalf("rollover exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if out := h.stdout.String(); !strings.HasPrefix(out, "Sprint 2024-W01 closed, 2 tasks moved to 2024-W02\n") {
+		t.Errorf("rollover output = %q", out)
+	}
+	for id, sprint := range map[int]string{1: "2024-W02", 2: "2024-W02", 3: "2024-W01"} {
+		if task, _ := h.repo.GetTask(id); task.Sprint != sprint {
+			t.Errorf("task %d sprint = %q, want %q", id, task.Sprint, sprint)
+		}
+	}
+
+	h.run("sprint", "status", "2024-W02")
+	if out := h.stdout.String(); !strings.Contains(out, "#1 Buy groceries (todo) carried over") {
+		t.Errorf("next sprint status = %q, want #1 carried over", out)
+	}
+	if code := h.run("sprint", "add", "1", "--sprint", "2024-W01"); code != exitRefused ||
+		!strings.Contains(h.stderr.String(), "Sprint is closed") {
+		t.Errorf("add to closed sprint = %d, stderr = %q", code, h.stderr.String())
+	}
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/repository"
+)
+
+func (c *CLI) handleStorage(ctx context.Context, args []string) int {
+	if len(args) == 0 || args[0] != "test" || len(args) > 2 {
+		c.errorf("Usage: task-cli storage test [<location>]\n")
+		return 1
+	}
+
+	var check repository.StorageCheck
+	var err error
+	if len(args) == 2 {
+		repo, openErr := repository.OpenRepository(args[1], c.config)
+		if openErr != nil {
+			return c.fail(openErr)
+		}
+		check, err = repository.CheckStorage(ctx, repo)
+	} else {
+		check, err = c.service.CheckStorage(ctx)
+	}
+
+	location := check.Location
+	if location == "" {
+		location = c.tr.Text("the configured store")
+	}
+	if err != nil {
+		c.errorf("Error: cannot use %s: %s\n", location, c.tr.Error(err))
+		return 1
+	}
+	c.successf(c.tr.Plural(check.Tasks, "Read %d tasks from %s\n"), check.Tasks, location)
+	if check.Probed {
+		c.successf("Writes to %s succeed\n", location)
+	}
+	return 0
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// handleSummary prints a one-line snapshot of the tasks, short enough for
+// a shell prompt or a tmux status bar
+func (c *CLI) handleSummary(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("summary")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	counts, err := c.service.CountTasks(ctx, c.clock(), nil)
+	if err != nil {
+		return c.fail(err)
+	}
+	fmt.Fprintln(c.stdout, counts.SummaryIn(c.service.Workflow(), c.tr))
+	return 0
+}
+
+// printCounts prints how many tasks match for each status and in total,
+// or with --quiet only the total
+func (c *CLI) printCounts(ctx context.Context, match func(task.Task) bool) int {
+	counts, err := c.service.CountTasks(ctx, c.clock(), match)
+	if err != nil {
+		return c.fail(err)
+	}
+	if c.quiet {
+		fmt.Fprintln(c.stdout, counts.Total)
+		return 0
+	}
+
+	for _, status := range c.service.Workflow().Statuses {
+		if n := counts.ByStatus[status]; n > 0 {
+			fmt.Fprintf(c.stdout, "%-12s %d\n", c.tr.Status(status), n)
+		}
+	}
+	fmt.Fprintf(c.stdout, "%-12s %d\n", c.tr.Text("total"), counts.Total)
+	return 0
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_Summary tests summary and list --count
+func TestCLI_Summary(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+
+	if code := h.run("summary"); code != 0 || h.stdout.String() != "1 todo, 1 in-progress, 1 done, 0 overdue\n" {
+		t.Errorf("summary = %d, %q", code, h.stdout.String())
+	}
+
+	h.run("list", "--count", "--where", "status != done")
+	if want := "todo         1\nin-progress  1\ntotal        2\n"; h.stdout.String() != want {
+		t.Errorf("list --count = %q, want %q", h.stdout.String(), want)
+	}
+	h.run("-q", "list", "done", "--count")
+	if h.stdout.String() != "1\n" {
+		t.Errorf("list --count --quiet = %q, want 1", h.stdout.String())
+	}
+}
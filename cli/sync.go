@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithSyncDir sets where sync remembers the last synced state of each remote
+func (c *CLI) WithSyncDir(dir string) *CLI {
+	c.syncDir = dir
+	return c
+}
+
+func (c *CLI) handleSync(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("sync")
+	prefer := fs.String("prefer", c.config.Sync.Prefer, "resolve every conflict: local, remote or newer")
+	resolve := fs.String("resolve", "", "resolve conflicts per task, e.g. 3=local,5=remote")
+	dryRun := fs.Bool("dry-run", false, "show what would change without writing")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	location := c.config.Sync.Remote
+	if len(args) > 0 {
+		location = args[0]
+	}
+	if location == "" {
+		c.errorf("Error: Remote store is required\n")
+		c.errorf("Usage: task-cli sync <path|url> [--prefer local|remote|newer] [--resolve id=side,...] [--dry-run]\n")
+		return 1
+	}
+	if c.syncDir == "" {
+		c.errorf("Error: sync is not available for this store\n")
+		return 1
+	}
+
+	resolver, err := parseResolutions(*prefer, *resolve)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if err := os.MkdirAll(c.syncDir, 0o700); err != nil {
+		return c.fail(err)
+	}
+
+	remote, err := repository.OpenRepository(location, c.config)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	report, err := c.service.Sync(ctx, service.SyncOptions{
+		Remote:  remote,
+		Base:    repository.NewFileTaskRepository(c.syncBasePath(location)),
+		Resolve: resolver,
+		DryRun:  *dryRun,
+	})
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if len(report.Conflicts) > 0 {
+		c.errorf("Sync stopped, %d conflict(s) need a decision:\n", len(report.Conflicts))
+		for _, conflict := range report.Conflicts {
+			c.errorf("  %s\n", conflict.Describe())
+		}
+		c.errorf("Re-run with --resolve <id>=local|remote or --prefer local|remote|newer\n")
+		return 1
+	}
+
+	if c.quiet {
+		return 0
+	}
+	for _, old := range slices.Sorted(maps.Keys(report.Renumbered)) {
+		fmt.Fprintf(c.stdout, "Local task %d collided with a remote task and is now %d\n",
+			old, report.Renumbered[old])
+	}
+	c.printSyncChanges("Pulled", report.LocalChanges)
+	c.printSyncChanges("Pushed", report.RemoteChanges)
+	switch {
+	case len(report.LocalChanges) == 0 && len(report.RemoteChanges) == 0:
+		fmt.Fprintln(c.stdout, "Already in sync")
+	case *dryRun:
+		fmt.Fprintln(c.stdout, "Dry run, nothing was written")
+	}
+
+	return 0
+}
+
+func (c *CLI) printSyncChanges(label string, changes []task.TaskChange) {
+	for _, change := range changes {
+		fmt.Fprintf(c.stdout, "%s: %s #%d %s\n", label,
+			change.Type, change.Task.ID, change.Task.Description)
+	}
+}
+
+// syncBasePath names the state file for a remote so that syncing with
+// several remotes keeps separate histories
+func (c *CLI) syncBasePath(location string) string {
+	sum := sha256.Sum256([]byte(location))
+	return filepath.Join(c.syncDir, "base-"+hex.EncodeToString(sum[:6])+".json")
+}
+
+// parseResolutions builds a conflict resolver from --prefer and --resolve
+func parseResolutions(prefer, resolve string) (func(service.SyncConflict) (service.Resolution, bool), error) {
+	valid := func(s string) (service.Resolution, error) {
+		switch r := service.Resolution(s); r {
+		case service.ResolveLocal, service.ResolveRemote, service.ResolveNewer:
+			return r, nil
+		default:
+			return "", fmt.Errorf("invalid resolution %q, use local, remote or newer", s)
+		}
+	}
+
+	var fallback service.Resolution
+	if prefer != "" {
+		r, err := valid(prefer)
+		if err != nil {
+			return nil, err
+		}
+		fallback = r
+	}
+
+	perTask := make(map[int]service.Resolution)
+	for _, item := range strings.Split(resolve, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		idText, side, ok := strings.Cut(item, "=")
+		id, err := strconv.Atoi(strings.TrimSpace(idText))
+		if !ok || err != nil {
+			return nil, fmt.Errorf("invalid --resolve entry %q, expected <id>=local|remote|newer", item)
+		}
+		r, err := valid(strings.TrimSpace(side))
+		if err != nil {
+			return nil, err
+		}
+		perTask[id] = r
+	}
+
+	return func(conflict service.SyncConflict) (service.Resolution, bool) {
+		if r, ok := perTask[conflict.ID]; ok {
+			return r, true
+		}
+		return fallback, fallback != ""
+	}, nil
+}
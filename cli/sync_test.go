@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+// TestCLI_Sync tests syncing two file stores through the CLI
+func TestCLI_Sync(t *testing.T) {
+	dir := t.TempDir()
+	remotePath := filepath.Join(dir, "shared.json")
+
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.WithSyncDir(filepath.Join(dir, "state"))
+
+	if code := h.run("sync", remotePath); code != 0 {
+		t.Fatalf("first sync exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if !strings.Contains(h.stdout.String(), "Pushed: added #3 Call mom") {
+		t.Errorf("first sync output = %q", h.stdout.String())
+	}
+
+	remote := repository.NewFileTaskRepository(remotePath)
+	other := service.NewTaskService(remote)
+	if err := other.UpdateTask(t.Context(), 1, "Buy bread"); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := h.run("sync", remotePath); code != 0 {
+		t.Fatalf("second sync exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if task, _ := h.repo.GetTask(1); task.Description != "Buy bread" {
+		t.Errorf("local task 1 = %q after pull, want %q", task.Description, "Buy bread")
+	}
+
+	if err := other.UpdateTask(t.Context(), 2, "Remote report"); err != nil {
+		t.Fatal(err)
+	}
+	if code := h.run("update", "2", "Local report"); code != 0 {
+		t.Fatal(h.stderr.String())
+	}
+
+	if code := h.run("sync", remotePath); code != 1 {
+		t.Errorf("conflicting sync exit code = %d, want 1", code)
+	}
+	if !strings.Contains(h.stderr.String(), "task 2 changed on both sides (description)") {
+		t.Errorf("conflict output = %q", h.stderr.String())
+	}
+
+	if code := h.run("sync", remotePath, "--resolve", "2=local"); code != 0 {
+		t.Fatalf("resolved sync exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if task, _ := other.GetTask(t.Context(), 2); task.Description != "Local report" {
+		t.Errorf("remote task 2 = %q, want %q", task.Description, "Local report")
+	}
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func (c *CLI) handleTag(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "list":
+		return c.handleTagList(ctx)
+	case "rename":
+		if len(args) != 3 {
+			c.errorf("Error: Old and new names are required\n")
+			c.errorf("Usage: task-cli tag rename <tag> <new-tag>\n")
+			return 1
+		}
+		changed, err := c.service.RenameTag(ctx, args[1], args[2])
+		if err != nil {
+			return c.fail(err)
+		}
+		c.successf("Tag %s renamed to %s on %s\n", task.NormalizeTag(args[1]), task.NormalizeTag(args[2]),
+			c.tr.Sprintf(c.tr.Plural(changed, "%d tasks"), changed))
+		return 0
+	case "merge":
+		return c.handleTagMerge(ctx, args[1:])
+	case "prune":
+		return c.handleTagPrune(ctx, args[1:])
+	default:
+		c.errorf("Error: Unknown tag command '%s'\n", args[0])
+		c.errorf("Usage: task-cli tag list|rename|merge|prune\n")
+		return 1
+	}
+}
+
+func (c *CLI) handleTagList(ctx context.Context) int {
+	tags, err := c.service.Tags(ctx)
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(tags) == 0 {
+		c.successf("No tags found\n")
+		return 0
+	}
+	for _, tag := range tags {
+		fmt.Fprintf(c.stdout, "%-20s %s\n", tag.Tag, c.tr.Sprintf(c.tr.Plural(tag.Tasks, "%d tasks"), tag.Tasks))
+	}
+	return 0
+}
+
+func (c *CLI) handleTagMerge(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("tag merge")
+	into := fs.String("into", "", "the tag the others are replaced with")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) == 0 || *into == "" {
+		c.errorf("Error: Tags and --into are required\n")
+		c.errorf("Usage: task-cli tag merge <tag>... --into <tag>\n")
+		return 1
+	}
+
+	changed, err := c.service.MergeTags(ctx, args, *into)
+	if err != nil {
+		return c.fail(err)
+	}
+	c.successf("Tags merged into %s on %s\n", task.NormalizeTag(*into), c.tr.Sprintf(c.tr.Plural(changed, "%d tasks"), changed))
+	return 0
+}
+
+func (c *CLI) handleTagPrune(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("tag prune")
+	dryRun := fs.Bool("dry-run", false, "list the unused tags without removing them")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	pruned, err := c.service.PruneTags(ctx, *dryRun)
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(pruned) == 0 {
+		c.successf("No unused tags found\n")
+		return 0
+	}
+	if *dryRun {
+		c.successf("Would remove unused tags from saved views: %s\n", strings.Join(pruned, ", "))
+	} else {
+		c.successf("Removed unused tags from saved views: %s\n", strings.Join(pruned, ", "))
+	}
+	return 0
+}
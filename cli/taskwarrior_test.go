@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_Taskwarrior tests exporting and importing back without duplicates
+func TestCLI_Taskwarrior(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	file := filepath.Join(t.TempDir(), "tw.json")
+
+	if code := h.run("export", "--format", "taskwarrior", "--output", file); code != 0 {
+		t.Fatalf("export exit code = %d, stderr: %s", code, h.stderr)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"status": "completed"`) {
+		t.Errorf("export missing completed task:\n%s", data)
+	}
+
+	// Importing the export into an empty list recreates the tasks
+	empty := newCLIHarness(t, nil)
+	empty.cli.WithStdin(strings.NewReader(string(data)))
+	if code := empty.run("import", "--format", "taskwarrior", "-"); code != 0 {
+		t.Fatalf("import exit code = %d, stderr: %s", code, empty.stderr)
+	}
+	if empty.repo.TaskCount() != 3 {
+		t.Fatalf("imported %d tasks, want 3", empty.repo.TaskCount())
+	}
+	if item, ok := empty.repo.GetTask(2); !ok || item.Status != task.StatusInProgress {
+		t.Errorf("task 2 = %+v, want in progress", item)
+	}
+
+	// A second import is recognized by UUID
+	empty.cli.WithStdin(strings.NewReader(string(data)))
+	empty.stdout.Reset()
+	if code := empty.run("import", "--format", "taskwarrior"); code != 0 {
+		t.Fatalf("second import exit code = %d", code)
+	}
+	if empty.repo.TaskCount() != 3 || !strings.Contains(empty.stdout.String(), "3 duplicates skipped") {
+		t.Errorf("second import duplicated tasks: %s", empty.stdout)
+	}
+
+	if code := h.run("export", "--format", "csv"); code != 1 {
+		t.Errorf("unsupported format exit code = %d, want 1", code)
+	}
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"os"
+	"text/template"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// templateEnv is what task templates see of this run
+func (c *CLI) templateEnv() task.TemplateEnv {
+	return task.TemplateEnv{
+		Now:   c.clock(),
+		Color: colorOutput(c.stdout, os.Getenv) && !c.isAccessible(),
+	}
+}
+
+// taskTemplate compiles the template given to --template, or a format named
+// in the config file, nil when spec is empty
+func (c *CLI) taskTemplate(spec string) (*template.Template, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	return task.ParseTaskTemplate(spec, c.config.Formats, c.templateEnv())
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestCLI_Template tests --template on list and show and the default list
+// template from the config file
+func TestCLI_Template(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.config.Display.Template = "{{.ID}}. {{.Description}}"
+
+	h.run("list", "done")
+	if out := h.stdout.String(); out != "3. Call mom\n" {
+		t.Errorf("list with a default template output = %q", out)
+	}
+	h.run("list", "done", "--format", "text")
+	if out := h.stdout.String(); !strings.HasPrefix(out, "Tasks:\n") {
+		t.Errorf("list --format text output = %q, want the usual list", out)
+	}
+	h.run("list", "done", "--template", "{{upper .Description}}")
+	if out := h.stdout.String(); out != "CALL MOM\n" {
+		t.Errorf("list --template output = %q", out)
+	}
+	if code := h.run("list", "--format", "quickfix", "--template", "{{.ID}}"); code != 1 {
+		t.Errorf("list with --format and --template exit code = %d, want 1", code)
+	}
+
+	h.run("show", "2", "--template", "{{.Status}}: {{.Description}}")
+	if out := h.stdout.String(); out != "in-progress: Write report\n" {
+		t.Errorf("show --template output = %q", out)
+	}
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithScheduleFile sets where tick remembers the occurrences it created
+func (c *CLI) WithScheduleFile(path string) *CLI {
+	c.schedule = path
+	return c
+}
+
+func (c *CLI) handleTick(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("tick")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+
+	schedules, err := c.schedules()
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(schedules) == 0 {
+		c.successf("No schedules configured\n")
+		return 0
+	}
+
+	added, err := c.runSchedules(ctx, schedules)
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(added) == 0 {
+		c.successf("Nothing to create\n")
+	}
+	return 0
+}
+
+// schedules converts the configured schedules
+func (c *CLI) schedules() ([]task.Schedule, error) {
+	schedules := make([]task.Schedule, 0, len(c.config.Schedules))
+	for _, config := range c.config.Schedules {
+		schedule, err := config.Schedule()
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// runSchedules creates the tasks that are due and records them in the
+// schedule file
+func (c *CLI) runSchedules(ctx context.Context, schedules []task.Schedule) ([]task.Task, error) {
+	if c.schedule == "" {
+		return nil, fmt.Errorf("schedules are not available for this store")
+	}
+
+	state, err := task.LoadScheduleState(c.schedule)
+	if err != nil {
+		return nil, err
+	}
+	added, err := c.service.RunSchedules(ctx, schedules, state, c.clock())
+	if err != nil {
+		return nil, err
+	}
+	if err := task.SaveScheduleState(c.schedule, state); err != nil {
+		return nil, err
+	}
+
+	for _, task := range added {
+		if c.quiet {
+			fmt.Fprintln(c.stdout, task.ID)
+			continue
+		}
+		c.successf("Created task %d: %s\n", task.ID, task.Description)
+	}
+	return added, nil
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_UUIDPrefix tests that commands accept a UUID prefix for an ID
+func TestCLI_UUIDPrefix(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].UUID = "c0ffee00-0000-4000-8000-000000000001"
+	h := newCLIHarness(t, tasks)
+
+	if code := h.run("mark-done", "c0ffee"); code != 0 {
+		t.Fatalf("mark-done exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if item, _ := h.repo.GetTask(1); item.Status != task.StatusDone {
+		t.Errorf("task 1 status = %s, want done", item.Status)
+	}
+
+	h.run("show", "c0ffee00-0000")
+	if out := h.stdout.String(); !strings.Contains(out, "Task 1: Buy groceries") ||
+		!strings.Contains(out, "UUID:     "+tasks[0].UUID) {
+		t.Errorf("show output = %q", out)
+	}
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// listFlags registers the filters list and view save share, filling the
+// returned view as they are parsed
+func (c *CLI) listFlags(fs *flag.FlagSet) *task.View {
+	view := &task.View{}
+	fs.StringVar(&view.Status, "status", "", "only tasks with this status")
+	fs.Func("tag", "only tasks with this tag, repeat for several", func(tag string) error {
+		view.Tags = append(view.Tags, tag)
+		return nil
+	})
+	fs.BoolVar(&view.All, "all", false, "include cancelled and snoozed tasks")
+	fs.StringVar(&view.Assignee, "assignee", "", "only tasks assigned to this user, \"me\" or \"none\"")
+	fs.StringVar(&view.CreatedBy, "created-by", "", "only tasks added by this user or \"me\"")
+	fs.StringVar(&view.Project, "project", "", "only tasks in this project")
+	fs.StringVar(&view.Milestone, "milestone", "", "only tasks planned for this milestone")
+	fs.StringVar(&view.Sprint, "sprint", "", "only tasks committed to this sprint")
+	fs.StringVar(&view.Where, "where", "", "only tasks passing this filter expression")
+	fs.StringVar(&view.Sort, "sort", "", "order by id, created, updated, due or priority, prefixed with - to reverse")
+	fs.IntVar(&view.Limit, "limit", 0, "show at most this many tasks")
+	fs.Func("stale", "only open tasks in their status for this long, such as 14d", func(value string) error {
+		stale, err := task.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		view.Stale = task.Duration(stale)
+		return nil
+	})
+	fs.Func("max-effort", "only tasks estimated to take at most this long, such as 15m or S", func(value string) error {
+		effort, err := task.ParseEffort(value)
+		if err != nil {
+			return err
+		}
+		view.MaxEffort = task.Duration(effort)
+		return nil
+	})
+	return view
+}
+
+// viewMatch returns the test for the filters of the view
+func (c *CLI) viewMatch(view task.View) (func(task.Task) bool, error) {
+	assignee := c.resolveUser(view.Assignee)
+	creator := c.resolveUser(view.CreatedBy)
+	now := c.clock()
+	where := func(task.Task) bool { return true }
+	if view.Where != "" {
+		query, err := task.ParseQuery(view.Where, task.QueryEnv{
+			Now:      c.clock(),
+			Workflow: c.service.Workflow(),
+			User:     c.currentUser(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		where = query.Match
+	}
+
+	return func(t task.Task) bool {
+		switch {
+		case view.Status != "" && string(t.Status) != view.Status:
+			return false
+		// Cancelled tasks only clutter the everyday list
+		case view.Status == "" && !view.All && t.Status == task.StatusCancelled:
+			return false
+		// Snoozed tasks stay out of sight until they wake
+		case !view.All && t.IsSnoozed(now):
+			return false
+		case view.Assignee != "" && t.Assignee != assignee:
+			return false
+		case view.CreatedBy != "" && t.CreatedBy != creator:
+			return false
+		case view.Project != "" && t.Project != view.Project:
+			return false
+		case view.Milestone != "" && t.Milestone != view.Milestone:
+			return false
+		case view.Sprint != "" && t.Sprint != view.Sprint:
+			return false
+		case view.Stale > 0 && (!t.IsOpen() || now.Sub(t.StatusSince()) < time.Duration(view.Stale)):
+			return false
+		case view.MaxEffort > 0 && (t.Estimate == 0 || t.Estimate > view.MaxEffort):
+			return false
+		case !t.HasTags(view.Tags):
+			return false
+		}
+		return where(t)
+	}, nil
+}
+
+// queryErrorf reports an error, pointing at the problem in a filter
+// expression when it is in one
+func (c *CLI) queryErrorf(err error) {
+	c.printError(err)
+	var queryErr *task.QueryError
+	if errors.As(err, &queryErr) {
+		c.errorf("  %s\n", strings.ReplaceAll(queryErr.Caret(), "\n", "\n  "))
+	}
+}
+
+func (c *CLI) handleView(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "save":
+		fs := c.newFlagSet("view save")
+		view := c.listFlags(fs)
+		positional, err := parseFlags(fs, args[1:])
+		if err != nil {
+			return 1
+		}
+		if len(positional) == 0 {
+			c.errorf("Error: View name is required\n")
+			c.errorf("Usage: task-cli view save <name> [status] [list filters]\n")
+			return 1
+		}
+		view.Name = positional[0]
+		if len(positional) > 1 {
+			view.Status = positional[1]
+		}
+		if view.Limit < 0 {
+			c.errorf("Error: --limit cannot be negative\n")
+			return 1
+		}
+		if err := c.service.SaveView(ctx, *view); err != nil {
+			c.queryErrorf(err)
+			return 1
+		}
+		c.successf("View %s saved\n", view.Name)
+		return 0
+
+	case "list":
+		views, err := c.service.Views(ctx)
+		if err != nil {
+			return c.fail(err)
+		}
+		if len(views) == 0 {
+			c.successf("No views saved\n")
+			return 0
+		}
+		for _, view := range views {
+			fmt.Fprintf(c.stdout, "%s: %s\n", view.Name, joinCommandLine(viewFlags(view)))
+		}
+		return 0
+
+	case "delete":
+		if len(args) < 2 {
+			c.errorf("Error: View name is required\n")
+			c.errorf("Usage: task-cli view delete <name>\n")
+			return 1
+		}
+		if err := c.service.DeleteView(ctx, args[1]); err != nil {
+			return c.fail(err)
+		}
+		c.successf("View %s deleted\n", args[1])
+		return 0
+
+	default:
+		c.errorf("Unknown view command: %s\n", args[0])
+		c.errorf("Usage: task-cli view save|list|delete\n")
+		return 1
+	}
+}
+
+// viewFlags returns the list flags that select the same tasks as the view
+func viewFlags(view task.View) []string {
+	var flags []string
+	add := func(name, value string) {
+		if value != "" {
+			flags = append(flags, "--"+name, value)
+		}
+	}
+	add("status", view.Status)
+	for _, tag := range view.Tags {
+		add("tag", tag)
+	}
+	add("assignee", view.Assignee)
+	add("created-by", view.CreatedBy)
+	add("project", view.Project)
+	add("milestone", view.Milestone)
+	add("sprint", view.Sprint)
+	add("where", view.Where)
+	add("sort", view.Sort)
+	if view.All {
+		flags = append(flags, "--all")
+	}
+	if view.Limit > 0 {
+		add("limit", strconv.Itoa(view.Limit))
+	}
+	if view.Stale > 0 {
+		add("stale", task.FormatDuration(time.Duration(view.Stale)))
+	}
+	if view.MaxEffort > 0 {
+		add("max-effort", task.FormatDuration(time.Duration(view.MaxEffort)))
+	}
+	return flags
+}
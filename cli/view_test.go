@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestSortTasks tests each sort order and reversing it
+func TestSortTasks(t *testing.T) {
+	due := tasktest.FixedTime().Add(24 * time.Hour)
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].Priority = task.PriorityLow
+	tasks[1].Priority = task.PriorityUrgent
+	tasks[2].DueAt = &due
+
+	tests := []struct {
+		key  string
+		want []int
+	}{
+		{"id", []int{1, 2, 3}},
+		{"-id", []int{3, 2, 1}},
+		{"priority", []int{2, 1, 3}},
+		{"due", []int{3, 1, 2}},
+		{"-updated", []int{2, 3, 1}},
+	}
+	for _, tt := range tests {
+		sorted := append([]task.Task(nil), tasks...)
+		if err := task.SortTasks(sorted, tt.key); err != nil {
+			t.Fatalf("SortTasks(%q): %v", tt.key, err)
+		}
+		for i, id := range tt.want {
+			if sorted[i].ID != id {
+				t.Errorf("SortTasks(%q) = %v, want IDs %v", tt.key, taskIDList(sorted), tt.want)
+				break
+			}
+		}
+	}
+
+	if err := task.SortTasks(tasks, "size"); !errors.Is(err, task.ErrInvalidSort) {
+		t.Errorf("SortTasks(size) error = %v, want %s", err, task.ErrInvalidSort.Code)
+	}
+}
+
+// TestCLI_View tests saving views and listing through them
+func TestCLI_View(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].Tags = []string{"backend"}
+	tasks[0].Priority = task.PriorityLow
+	tasks = append(tasks, *tasktest.NewTaskBuilder().WithID(4).WithDescription("Fix login").
+		WithTimestamps(tasktest.FixedTime(), tasktest.FixedTime()).BuildInvalid())
+	tasks[3].Tags = []string{"backend", "api"}
+	tasks[3].Priority = task.PriorityHigh
+	h := newCLIHarness(t, tasks)
+	h.cli.service.WithViews(repository.NewFileViewRepository(filepath.Join(t.TempDir(), "views.json")))
+
+	if code := h.run("view", "save", "backlog", "--status", "todo", "--tag", "Backend", "--sort", "priority"); code != 0 {
+		t.Fatalf("view save exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	h.run("list", "--view", "backlog")
+	if out := h.stdout.String(); strings.Index(out, "Fix login") > strings.Index(out, "Buy groceries") ||
+		strings.Contains(out, "Write report") {
+		t.Errorf("list --view output = %q, want Fix login then Buy groceries", out)
+	}
+
+	h.run("list", "--view", "backlog", "--tag", "api", "--sort", "-priority")
+	if out := h.stdout.String(); !strings.Contains(out, "Fix login") || strings.Contains(out, "Buy groceries") {
+		t.Errorf("list --view with --tag output = %q, want only Fix login", out)
+	}
+	h.run("list", "in-progress", "--view", "backlog")
+	if out := h.stdout.String(); out != "No tasks with status 'in-progress' found\n" {
+		t.Errorf("list status over view output = %q", out)
+	}
+
+	h.run("view", "list")
+	if out := h.stdout.String(); out != "backlog: --status todo --tag backend --sort priority\n" {
+		t.Errorf("view list output = %q", out)
+	}
+	if code := h.run("view", "save", "bad", "--sort", "size"); code != 1 {
+		t.Errorf("view save with a bad sort exit code = %d, want 1", code)
+	}
+	if code := h.run("view", "delete", "backlog"); code != 0 {
+		t.Fatalf("view delete exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+	if code := h.run("list", "--view", "backlog"); code != exitNotFound || !strings.Contains(h.stderr.String(), "View not found") {
+		t.Errorf("list of a deleted view exit code = %d, stderr = %q", code, h.stderr.String())
+	}
+}
+
+// TestCLI_ListStale tests finding tasks stuck in their status
+func TestCLI_ListStale(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)
+	started := tasktest.FixedTime().AddDate(0, 0, -20)
+	tasks[1].CreatedAt = tasktest.FixedTime().AddDate(0, 0, -30)
+	tasks[1].StatusChangedAt = &started
+	tasks[0].CreatedAt = tasktest.FixedTime().AddDate(0, 0, -3)
+	h := newCLIHarness(t, tasks)
+
+	if code := h.run("list", "--stale", "14d", "--show-age"); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, h.stderr)
+	}
+	out := h.stdout.String()
+	if !strings.Contains(out, "Write report") || strings.Contains(out, "Buy groceries") || strings.Contains(out, "Call mom") {
+		t.Errorf("list --stale 14d = %q, want only task 2", out)
+	}
+	if !strings.Contains(out, " | Age: 1 month | in-progress for 20 days\n") {
+		t.Errorf("list --show-age = %q", out)
+	}
+
+	if code := h.run("list", "--stale", "2d"); code != 0 || !strings.Contains(h.stdout.String(), "Buy groceries") {
+		t.Errorf("list --stale 2d = %d, %q", code, h.stdout)
+	}
+	if code := h.run("list", "--stale", "soon"); code != 1 {
+		t.Errorf("invalid --stale exit code = %d, want 1", code)
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// watchEvent is the JSON line emitted by `watch --json`
+type watchEvent struct {
+	Time     time.Time          `json:"time"`
+	Type     task.ChangeType    `json:"type"`
+	Task     task.Task          `json:"task"`
+	Previous *task.Task         `json:"previous,omitempty"`
+	Fields   []task.FieldChange `json:"fields,omitempty"`
+}
+
+func (c *CLI) handleWatch(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("watch")
+	asJSON := fs.Bool("json", false, "emit one JSON event per line")
+	interval := fs.Duration("interval", time.Second, "polling interval for stores without change notifications")
+	if _, err := parseFlags(fs, args); err != nil {
+		return 1
+	}
+	if *interval <= 0 {
+		c.errorf("Error: --interval must be positive\n")
+		return 1
+	}
+
+	changes, err := c.service.WatchTasks(ctx, *interval)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	c.successf("Watching for changes (Ctrl+C to stop)\n")
+
+	encoder := json.NewEncoder(c.stdout)
+	for change := range changes {
+		if *asJSON {
+			event := watchEvent{
+				Time:     c.clock(),
+				Type:     change.Type,
+				Task:     change.Task,
+				Previous: change.Previous,
+				Fields:   change.Fields(),
+			}
+			if err := encoder.Encode(event); err != nil {
+				return c.fail(err)
+			}
+			continue
+		}
+		c.printChange(change)
+	}
+
+	return 0
+}
+
+// printChange renders a single change as a human readable line
+func (c *CLI) printChange(change task.TaskChange) {
+	timestamp := c.clock().Format("15:04:05")
+	line := fmt.Sprintf("[%s] %s #%d: %s", timestamp,
+		strings.ToUpper(string(change.Type)), change.Task.ID, change.Task.Description)
+
+	var details []string
+	for _, field := range change.Fields() {
+		if field.Field == "updatedAt" {
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s %q -> %q", field.Field, field.Old, field.New))
+	}
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+
+	fmt.Fprintln(c.stdout, line)
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCLI_PrintChange tests the human readable rendering of watch events
+func TestCLI_PrintChange(t *testing.T) {
+	h := newCLIHarness(t, nil)
+	old := tasktest.FixedTasks(t)[0]
+	updated := old
+	updated.Status = task.StatusDone
+
+	h.cli.printChange(task.TaskChange{Type: task.ChangeUpdated, Task: updated, Previous: &old})
+
+	want := `[12:00:00] UPDATED #1: Buy groceries (status "todo" -> "done")`
+	if got := strings.TrimSpace(h.stdout.String()); got != want {
+		t.Errorf("printChange() = %q, want %q", got, want)
+	}
+}
+
+// TestCLI_WatchInterval tests that watch refuses to poll without a delay
+func TestCLI_WatchInterval(t *testing.T) {
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	if code := h.run("watch", "--interval", "0s"); code != 1 {
+		t.Errorf("watch --interval 0s exit code = %d, want 1", code)
+	}
+	if !strings.Contains(h.stderr.String(), "--interval must be positive") {
+		t.Errorf("stderr = %q, want the interval refused", h.stderr.String())
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+func newWIPHarness(t *testing.T) *cliHarness {
+	t.Helper()
+	workflow, err := task.DefaultWorkflow().WithLimits(map[string]int{"in-progress": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := newCLIHarness(t, tasktest.FixedTasks(t))
+	h.cli.service.WithWorkflow(workflow)
+	return h
+}
+
+// TestCLI_WIPLimit tests that a full status refuses tasks unless forced
+func TestCLI_WIPLimit(t *testing.T) {
+	h := newWIPHarness(t)
+
+	if code := h.run("mark-in-progress", "1"); code != exitRefused {
+		t.Errorf("over the limit exit code = %d, want %d", code, exitRefused)
+	}
+	want := "Error: WIP limit reached: in-progress already holds 1 of 1 tasks [WIP_LIMIT]\n" +
+		"Hint: finish a task in that status first, or add --force\n"
+	if got := h.stderr.String(); got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+	if item, _ := h.repo.GetTask(1); item.Status != task.StatusTodo {
+		t.Errorf("status = %s, want todo", item.Status)
+	}
+	// A task already in the status does not count against itself
+	if code := h.run("mark-in-progress", "2"); code != 0 {
+		t.Errorf("task already in progress exit code = %d, stderr = %q", code, h.stderr)
+	}
+
+	if code := h.run("mark-in-progress", "--force", "1"); code != 0 {
+		t.Fatalf("forced exit code = %d, stderr = %q", code, h.stderr)
+	}
+	want = "Warning: in-progress holds 2 tasks, over its WIP limit of 1\n"
+	if got := h.stderr.String(); got != want {
+		t.Errorf("forced stderr = %q, want %q", got, want)
+	}
+
+	if code := h.run("list"); code != 0 || h.stderr.String() != want {
+		t.Errorf("list = %d, stderr %q, want %q", code, h.stderr, want)
+	}
+	if h.run("list", "done"); h.stderr.Len() != 0 {
+		t.Errorf("list done stderr = %q, want no warning for other statuses", h.stderr)
+	}
+	if code := h.run("board", "--width", "80"); code != 0 {
+		t.Fatal(h.stderr)
+	}
+	if !strings.Contains(h.stdout.String(), "! IN-PROGRESS (2/1)") {
+		t.Errorf("board does not flag the column:\n%s", h.stdout)
+	}
+}
@@ -0,0 +1,13 @@
+// Command task-cli tracks tasks from the command line. The commands live in
+// package cli, so that other programs can run them too.
+package main
+
+import (
+	"os"
+
+	"github.com/alnah/task-tracker/cli"
+)
+
+func main() {
+	os.Exit(cli.Main(os.Args))
+}
@@ -1,3 +1,18 @@
 module github.com/alnah/task-tracker
 
-go 1.24.2
+go 1.25.0
+
+require (
+	github.com/fsnotify/fsnotify v1.9.0
+	go.etcd.io/bbolt v1.5.0
+	go.starlark.net v0.0.0-20231121155337-90ade8b19d09
+	golang.org/x/sys v0.47.0
+	google.golang.org/grpc v1.84.0
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	golang.org/x/net v0.57.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260706201446-f0a921348800 // indirect
+)
@@ -0,0 +1,69 @@
+package tasktest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// jiraIssue is a search result as both Jira APIs return it
+func jiraIssue(key, summary, priority, category string, labels []string, due string) map[string]any {
+	fields := map[string]any{
+		"summary":    summary,
+		"created":    "2024-01-02T09:30:00.000+0100",
+		"priority":   map[string]string{"name": priority},
+		"status":     map[string]any{"name": category, "statusCategory": map[string]string{"key": category}},
+		"labels":     labels,
+		"components": []map[string]string{{"name": "Backend"}},
+	}
+	if due != "" {
+		fields["duedate"] = due
+	}
+	return map[string]any{"key": key, "fields": fields}
+}
+
+// NewFakeJira serves two pages of search results, the way Cloud or Data
+// Center paginates depending on the endpoint. Cloud takes me@example.com
+// with the token secret, Data Center the bearer token secret.
+func NewFakeJira(t *testing.T) *httptest.Server {
+	t.Helper()
+	pages := [][]map[string]any{
+		{
+			jiraIssue("OPS-1", "Rotate certificates", "Highest", "new", []string{"security"}, "2024-01-10"),
+			jiraIssue("OPS-2", "Write report", "Blocker-ish", "indeterminate", nil, ""),
+		},
+		{jiraIssue("OPS-3", "Upgrade database", "Low", "done", []string{"db", "infra"}, "")},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/3/search/jql", func(w http.ResponseWriter, r *http.Request) {
+		if user, token, _ := r.BasicAuth(); user != "me@example.com" || token != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string][]string{"errorMessages": {"Bad credentials"}})
+			return
+		}
+		if r.URL.Query().Get("jql") != "assignee = currentUser()" {
+			t.Errorf("jql = %q", r.URL.Query().Get("jql"))
+		}
+		if r.URL.Query().Get("nextPageToken") == "" {
+			json.NewEncoder(w).Encode(map[string]any{"issues": pages[0], "nextPageToken": "p2"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"issues": pages[1], "isLast": true})
+	})
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		page, startAt := pages[0], 0
+		if r.URL.Query().Get("startAt") == "2" {
+			page, startAt = pages[1], 2
+		}
+		json.NewEncoder(w).Encode(map[string]any{"issues": page, "startAt": startAt, "total": 3})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
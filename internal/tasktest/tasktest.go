@@ -1,19 +1,23 @@
-package main
+// Package tasktest holds what the tests of every package build tasks with:
+// the TaskBuilder, fixed times and fixtures, assertions, an in-memory
+// MockTaskRepository, and a fake Jira server.
+package tasktest
 
 import (
-	"errors"
+	"context"
+	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
-)
 
-// Test Utilities for Task Tracker
-// All utilities are in the same package - no import issues!
+	"github.com/alnah/task-tracker/task"
+)
 
 // TaskBuilder provides a fluent interface for creating test tasks
 type TaskBuilder struct {
 	id          int
 	description string
-	status      TaskStatus
+	status      task.TaskStatus
 	createdAt   time.Time
 	updatedAt   time.Time
 }
@@ -24,7 +28,7 @@ func NewTaskBuilder() *TaskBuilder {
 	return &TaskBuilder{
 		id:          1,
 		description: "Default test task",
-		status:      StatusTodo,
+		status:      task.StatusTodo,
 		createdAt:   now,
 		updatedAt:   now,
 	}
@@ -43,7 +47,7 @@ func (b *TaskBuilder) WithDescription(desc string) *TaskBuilder {
 }
 
 // WithStatus sets the task status
-func (b *TaskBuilder) WithStatus(status TaskStatus) *TaskBuilder {
+func (b *TaskBuilder) WithStatus(status task.TaskStatus) *TaskBuilder {
 	b.status = status
 	return b
 }
@@ -57,46 +61,49 @@ func (b *TaskBuilder) WithTimestamps(created, updated time.Time) *TaskBuilder {
 
 // InProgress is a convenience method to set status to in-progress
 func (b *TaskBuilder) InProgress() *TaskBuilder {
-	return b.WithStatus(StatusInProgress)
+	return b.WithStatus(task.StatusInProgress)
 }
 
 // Done is a convenience method to set status to done
 func (b *TaskBuilder) Done() *TaskBuilder {
-	return b.WithStatus(StatusDone)
+	return b.WithStatus(task.StatusDone)
 }
 
 // BuildValid creates a valid Task using the domain constructor
-func (b *TaskBuilder) BuildValid(t *testing.T) *Task {
+func (b *TaskBuilder) BuildValid(t *testing.T) *task.Task {
 	t.Helper()
 
-	// Use the actual NewTask function from main package - no imports needed!
-	task, err := NewTask(b.id, b.description)
+	item, err := task.NewTask(b.id, b.description)
 	if err != nil {
 		t.Fatalf("Failed to create valid test task: %v", err)
 	}
 
 	// Set custom timestamps if provided
 	if !b.createdAt.IsZero() {
-		task.CreatedAt = b.createdAt
+		item.CreatedAt = b.createdAt
 	}
 	if !b.updatedAt.IsZero() {
-		task.UpdatedAt = b.updatedAt
+		item.UpdatedAt = b.updatedAt
 	}
 
 	// Apply status changes through domain methods
 	switch b.status {
-	case StatusInProgress:
-		task.MarkInProgress()
-	case StatusDone:
-		task.MarkDone()
+	case task.StatusInProgress:
+		item.MarkInProgress()
+	case task.StatusDone:
+		item.MarkDone()
+	case task.StatusCancelled:
+		if err := item.Cancel("", task.DefaultWorkflow(), item.UpdatedAt); err != nil {
+			t.Fatalf("Failed to cancel test task: %v", err)
+		}
 	}
 
-	return task
+	return item
 }
 
 // BuildInvalid creates a Task struct bypassing domain validation (for testing edge cases)
-func (b *TaskBuilder) BuildInvalid() *Task {
-	return &Task{
+func (b *TaskBuilder) BuildInvalid() *task.Task {
+	return &task.Task{
 		ID:          b.id,
 		Description: b.description,
 		Status:      b.status,
@@ -108,13 +115,13 @@ func (b *TaskBuilder) BuildInvalid() *Task {
 // Common test fixtures
 
 // TodoTask creates a basic todo task
-func TodoTask(t *testing.T) *Task {
+func TodoTask(t *testing.T) *task.Task {
 	t.Helper()
 	return NewTaskBuilder().WithDescription("Buy groceries").BuildValid(t)
 }
 
 // InProgressTask creates a task in progress
-func InProgressTask(t *testing.T) *Task {
+func InProgressTask(t *testing.T) *task.Task {
 	t.Helper()
 	return NewTaskBuilder().
 		WithDescription("Write report").
@@ -122,17 +129,8 @@ func InProgressTask(t *testing.T) *Task {
 		BuildValid(t)
 }
 
-// DoneTask creates a completed task
-func DoneTask(t *testing.T) *Task {
-	t.Helper()
-	return NewTaskBuilder().
-		WithDescription("Call mom").
-		Done().
-		BuildValid(t)
-}
-
 // TaskWithID creates a task with specific ID
-func TaskWithID(t *testing.T, id int) *Task {
+func TaskWithID(t *testing.T, id int) *task.Task {
 	t.Helper()
 	return NewTaskBuilder().
 		WithID(id).
@@ -141,29 +139,77 @@ func TaskWithID(t *testing.T, id int) *Task {
 }
 
 // TaskSet creates multiple tasks for testing
-func TaskSet(t *testing.T, count int) []Task {
+func TaskSet(t *testing.T, count int) []task.Task {
 	t.Helper()
-	tasks := make([]Task, count)
+	tasks := make([]task.Task, count)
 	for i := range count {
-		task := NewTaskBuilder().
-			WithID(i + 1).
-			WithDescription("Task " + string(rune(i+49))). // '1', '2', '3', etc.
-			BuildValid(t)
-		tasks[i] = *task
+		tasks[i] = *TaskWithID(t, i+1)
 	}
 	return tasks
 }
 
 // MixedStatusTasks creates a set of tasks with different statuses
-func MixedStatusTasks(t *testing.T) []Task {
+func MixedStatusTasks(t *testing.T) []task.Task {
 	t.Helper()
-	return []Task{
+	return []task.Task{
 		*NewTaskBuilder().WithID(1).WithDescription("Todo task").BuildValid(t),
 		*NewTaskBuilder().WithID(2).WithDescription("In progress task").InProgress().BuildValid(t),
 		*NewTaskBuilder().WithID(3).WithDescription("Done task").Done().BuildValid(t),
 	}
 }
 
+// FixedTasks returns tasks with deterministic timestamps for golden output
+func FixedTasks(t *testing.T) []task.Task {
+	t.Helper()
+	created := FixedTime()
+	updated := TimeAfter(created)
+	return []task.Task{
+		*NewTaskBuilder().WithID(1).WithDescription("Buy groceries").
+			WithTimestamps(created, created).BuildInvalid(),
+		*NewTaskBuilder().WithID(2).WithDescription("Write report").
+			InProgress().WithTimestamps(created, updated).BuildInvalid(),
+		*NewTaskBuilder().WithID(3).WithDescription("Call mom").
+			Done().WithTimestamps(created, updated).BuildInvalid(),
+	}
+}
+
+// BrokenTasks returns tasks with one of each repairable problem
+func BrokenTasks() []task.Task {
+	created := FixedTime()
+	return []task.Task{
+		*NewTaskBuilder().WithID(1).WithDescription("First").
+			WithTimestamps(created, created).BuildInvalid(),
+		*NewTaskBuilder().WithID(1).WithDescription("Duplicate").
+			WithTimestamps(created, created).BuildInvalid(),
+		*NewTaskBuilder().WithID(2).WithDescription("No timestamps").
+			WithTimestamps(time.Time{}, time.Time{}).BuildInvalid(),
+		*NewTaskBuilder().WithID(3).WithDescription("Backwards").
+			WithTimestamps(created, TimeBefore(created)).BuildInvalid(),
+		*NewTaskBuilder().WithID(4).WithDescription("Blocked").WithStatus("blocked").
+			WithTimestamps(created, created).BuildInvalid(),
+	}
+}
+
+// TagTasks returns tasks sharing a few tags under several names
+func TagTasks() []task.Task {
+	return []task.Task{
+		{ID: 1, Description: "Fix login", Status: task.StatusTodo, Tags: []string{"bug", "web"}},
+		{ID: 2, Description: "Crash on save", Status: task.StatusTodo, Tags: []string{"defect", "bug"}},
+		{ID: 3, Description: "Typo", Status: task.StatusTodo, Tags: []string{"issue"}},
+		{ID: 4, Description: "Release", Status: task.StatusTodo, Tags: []string{"web"}},
+	}
+}
+
+// GitTaskFile returns the path of a task file in a fresh temp dir, for a
+// git-backed store, and skips the test when git is not installed
+func GitTaskFile(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	return filepath.Join(t.TempDir(), "tasks.json")
+}
+
 // Time helpers for testing timestamps
 
 // FixedTime returns a fixed time for consistent testing
@@ -184,7 +230,7 @@ func TimeBefore(base time.Time) time.Time {
 // Assertion helpers
 
 // AssertTaskEquals compares two tasks for equality in tests
-func AssertTaskEquals(t *testing.T, expected, actual *Task) {
+func AssertTaskEquals(t *testing.T, expected, actual *task.Task) {
 	t.Helper()
 
 	if actual.ID != expected.ID {
@@ -205,7 +251,7 @@ func AssertTaskEquals(t *testing.T, expected, actual *Task) {
 }
 
 // AssertTasksEqual compares slices of tasks
-func AssertTasksEqual(t *testing.T, expected, actual []Task) {
+func AssertTasksEqual(t *testing.T, expected, actual []task.Task) {
 	t.Helper()
 
 	if len(actual) != len(expected) {
@@ -217,35 +263,9 @@ func AssertTasksEqual(t *testing.T, expected, actual []Task) {
 	}
 }
 
-// AssertTaskInSlice verifies a task exists in a slice
-func AssertTaskInSlice(t *testing.T, task *Task, slice []Task) {
-	t.Helper()
-
-	for _, sliceTask := range slice {
-		if sliceTask.ID == task.ID {
-			AssertTaskEquals(t, task, &sliceTask)
-			return
-		}
-	}
-
-	t.Errorf("Task with ID %d not found in slice", task.ID)
-}
-
-// AssertTaskNotInSlice verifies a task does not exist in a slice
-func AssertTaskNotInSlice(t *testing.T, taskID int, slice []Task) {
-	t.Helper()
-
-	for _, sliceTask := range slice {
-		if sliceTask.ID == taskID {
-			t.Errorf("Task with ID %d should not be in slice", taskID)
-			return
-		}
-	}
-}
-
 // MockTaskRepository is an in-memory implementation for testing
 type MockTaskRepository struct {
-	tasks         []Task
+	tasks         []task.Task
 	shouldError   bool
 	errorToReturn error
 	saveCallCount int
@@ -255,13 +275,13 @@ type MockTaskRepository struct {
 // NewMockRepository creates a new mock repository
 func NewMockRepository() *MockTaskRepository {
 	return &MockTaskRepository{
-		tasks: make([]Task, 0),
+		tasks: make([]task.Task, 0),
 	}
 }
 
 // WithTasks preloads the repository with tasks
-func (m *MockTaskRepository) WithTasks(tasks []Task) *MockTaskRepository {
-	m.tasks = make([]Task, len(tasks))
+func (m *MockTaskRepository) WithTasks(tasks []task.Task) *MockTaskRepository {
+	m.tasks = make([]task.Task, len(tasks))
 	copy(m.tasks, tasks)
 	return m
 }
@@ -274,33 +294,42 @@ func (m *MockTaskRepository) WithError(err error) *MockTaskRepository {
 }
 
 // Save implements TaskRepository interface
-func (m *MockTaskRepository) Save(tasks []Task) error {
+func (m *MockTaskRepository) Save(ctx context.Context, tasks []task.Task) error {
 	m.saveCallCount++
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if m.shouldError {
 		return m.errorToReturn
 	}
 
-	m.tasks = make([]Task, len(tasks))
+	m.tasks = make([]task.Task, len(tasks))
 	copy(m.tasks, tasks)
 	return nil
 }
 
 // Load implements TaskRepository interface
-func (m *MockTaskRepository) Load() ([]Task, error) {
+func (m *MockTaskRepository) Load(ctx context.Context) ([]task.Task, error) {
 	m.loadCallCount++
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if m.shouldError {
 		return nil, m.errorToReturn
 	}
 
-	result := make([]Task, len(m.tasks))
+	result := make([]task.Task, len(m.tasks))
 	copy(result, m.tasks)
 	return result, nil
 }
 
 // GetNextID implements TaskRepository interface
-func (m *MockTaskRepository) GetNextID() (int, error) {
+func (m *MockTaskRepository) GetNextID(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
 	if m.shouldError {
 		return 0, m.errorToReturn
 	}
@@ -328,24 +357,20 @@ func (m *MockTaskRepository) LoadCallCount() int {
 }
 
 // GetStoredTasks returns a copy of the currently stored tasks
-func (m *MockTaskRepository) GetStoredTasks() []Task {
-	result := make([]Task, len(m.tasks))
+func (m *MockTaskRepository) GetStoredTasks() []task.Task {
+	result := make([]task.Task, len(m.tasks))
 	copy(result, m.tasks)
 	return result
 }
 
 // HasTask checks if a task with given ID exists
 func (m *MockTaskRepository) HasTask(id int) bool {
-	for _, task := range m.tasks {
-		if task.ID == id {
-			return true
-		}
-	}
-	return false
+	_, ok := m.GetTask(id)
+	return ok
 }
 
 // GetTask retrieves a task by ID (for test verification)
-func (m *MockTaskRepository) GetTask(id int) (*Task, bool) {
+func (m *MockTaskRepository) GetTask(id int) (*task.Task, bool) {
 	for _, task := range m.tasks {
 		if task.ID == id {
 			// Return a copy to avoid accidental modification
@@ -360,41 +385,3 @@ func (m *MockTaskRepository) GetTask(id int) (*Task, bool) {
 func (m *MockTaskRepository) TaskCount() int {
 	return len(m.tasks)
 }
-
-// Clear removes all tasks (useful for test cleanup)
-func (m *MockTaskRepository) Clear() {
-	m.tasks = m.tasks[:0]
-	m.saveCallCount = 0
-	m.loadCallCount = 0
-	m.shouldError = false
-	m.errorToReturn = nil
-}
-
-// Predefined error scenarios for testing
-
-// MockRepositoryWithLoadError returns a repository that fails on Load
-func MockRepositoryWithLoadError() *MockTaskRepository {
-	return NewMockRepository().WithError(errors.New("failed to load tasks"))
-}
-
-// MockRepositoryWithSaveError returns a repository that fails on Save
-func MockRepositoryWithSaveError() *MockTaskRepository {
-	return NewMockRepository().WithError(errors.New("failed to save tasks"))
-}
-
-// Repository test scenario builders
-
-// RepositoryWithTasks creates a repository pre-loaded with tasks
-func RepositoryWithTasks(tasks []Task) *MockTaskRepository {
-	return NewMockRepository().WithTasks(tasks)
-}
-
-// EmptyRepository creates an empty repository
-func EmptyRepository() *MockTaskRepository {
-	return NewMockRepository()
-}
-
-// ErrorRepository creates a repository that always fails
-func ErrorRepository(err error) *MockTaskRepository {
-	return NewMockRepository().WithError(err)
-}
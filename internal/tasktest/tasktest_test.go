@@ -0,0 +1,88 @@
+package tasktest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestMockRepository tests our mock implementation for consistency
+func TestMockRepository(t *testing.T) {
+	t.Run("mock behaves like file repository", func(t *testing.T) {
+		mock := NewMockRepository()
+
+		// Test empty repository
+		tasks, err := mock.Load(t.Context())
+		if err != nil {
+			t.Errorf("Mock Load() failed: %v", err)
+		}
+		if len(tasks) != 0 {
+			t.Errorf("Mock Load() on empty repo should return empty slice")
+		}
+
+		// Test save and load
+		testTasks := MixedStatusTasks(t)
+		err = mock.Save(t.Context(), testTasks)
+		if err != nil {
+			t.Errorf("Mock Save() failed: %v", err)
+		}
+
+		loadedTasks, err := mock.Load(t.Context())
+		if err != nil {
+			t.Errorf("Mock Load() after save failed: %v", err)
+		}
+
+		AssertTasksEqual(t, testTasks, loadedTasks)
+	})
+
+	t.Run("mock GetNextID works correctly", func(t *testing.T) {
+		mock := NewMockRepository()
+
+		// Empty repository
+		id, err := mock.GetNextID(t.Context())
+		if err != nil {
+			t.Errorf("Mock GetNextID() failed: %v", err)
+		}
+		if id != 1 {
+			t.Errorf("Mock GetNextID() on empty repo = %v, want 1", id)
+		}
+
+		// With tasks
+		tasks := []task.Task{
+			*NewTaskBuilder().WithID(3).BuildValid(t),
+			*NewTaskBuilder().WithID(1).BuildValid(t),
+		}
+		err = mock.Save(t.Context(), tasks)
+		if err != nil {
+			t.Errorf("Mock Save() failed: %v", err)
+		}
+
+		id, err = mock.GetNextID(t.Context())
+		if err != nil {
+			t.Errorf("Mock GetNextID() failed: %v", err)
+		}
+		if id != 4 {
+			t.Errorf("Mock GetNextID() with max ID 3 = %v, want 4", id)
+		}
+	})
+
+	t.Run("mock error simulation", func(t *testing.T) {
+		mock := NewMockRepository().WithError(task.ErrTaskNotFound)
+
+		_, err := mock.Load(t.Context())
+		if !errors.Is(err, task.ErrTaskNotFound) {
+			t.Errorf("Mock Load() should return configured error")
+		}
+
+		err = mock.Save(t.Context(), []task.Task{})
+		if !errors.Is(err, task.ErrTaskNotFound) {
+			t.Errorf("Mock Save() should return configured error")
+		}
+
+		_, err = mock.GetNextID(t.Context())
+		if !errors.Is(err, task.ErrTaskNotFound) {
+			t.Errorf("Mock GetNextID() should return configured error")
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// AuditFilter selects audit events; zero values match everything
+type AuditFilter struct {
+	TaskID int
+	Since  time.Time
+}
+
+// Matches reports whether the filter selects the event
+func (f AuditFilter) Matches(event task.AuditEvent) bool {
+	if f.TaskID != 0 && event.TaskID != f.TaskID {
+		return false
+	}
+	return f.Since.IsZero() || !event.Time.Before(f.Since)
+}
+
+// AuditLog is the port for storing audit events
+type AuditLog interface {
+	Append(ctx context.Context, events []task.AuditEvent) error
+	Events(ctx context.Context, filter AuditFilter) ([]task.AuditEvent, error)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// File Audit Log Implementation (Adapter)
+//
+// FileAuditLog appends one JSON event per line, so the log survives
+// rewrites of the data file and can be read with standard tools.
+type FileAuditLog struct {
+	filename string
+}
+
+func NewFileAuditLog(filename string) *FileAuditLog {
+	return &FileAuditLog{filename: filename}
+}
+
+func (l *FileAuditLog) Append(ctx context.Context, events []task.AuditEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	return f.Close()
+}
+
+func (l *FileAuditLog) Events(ctx context.Context, filter AuditFilter) ([]task.AuditEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(l.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []task.AuditEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event task.AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", l.filename, line, err)
+		}
+		if filter.Matches(event) {
+			events = append(events, event)
+		}
+	}
+
+	return events, scanner.Err()
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestFileAuditLog_Filter tests selecting events by task and time
+func TestFileAuditLog_Filter(t *testing.T) {
+	audit := NewFileAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	base := tasktest.FixedTime()
+	events := []task.AuditEvent{
+		{Time: base, Action: "add", TaskID: 1},
+		{Time: base.Add(time.Hour), Action: "add", TaskID: 2},
+		{Time: base.Add(2 * time.Hour), Action: "mark-done", TaskID: 1},
+	}
+	if err := audit.Append(t.Context(), events); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter AuditFilter
+		want   int
+	}{
+		{"all", AuditFilter{}, 3},
+		{"by task", AuditFilter{TaskID: 1}, 2},
+		{"since", AuditFilter{Since: base.Add(time.Hour)}, 2},
+		{"task and since", AuditFilter{TaskID: 1, Since: base.Add(time.Hour)}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := audit.Events(t.Context(), tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Events() returned %d events, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// backupTimeFormat sorts lexically in chronological order
+const backupTimeFormat = "20060102T150405.000"
+
+// ErrBackupNotFound is returned when restoring an unknown backup
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Backup describes one saved copy of the data file
+type Backup struct {
+	Name      string
+	CreatedAt time.Time
+	Size      int64
+}
+
+// BackupManager keeps timestamped copies of the data file in a directory,
+// deleting the oldest ones beyond the configured limit
+type BackupManager struct {
+	dataFile string
+	dir      string
+	keep     int
+	clock    task.Clock
+}
+
+func NewBackupManager(dataFile, dir string, keep int) *BackupManager {
+	if dir == "" {
+		dir = filepath.Join(filepath.Dir(dataFile), "backups")
+	}
+	return &BackupManager{dataFile: dataFile, dir: dir, keep: keep, clock: time.Now}
+}
+
+// Create copies the current data file into the backup directory. A missing
+// data file is not an error since there is nothing to protect yet.
+func (m *BackupManager) Create() (*Backup, error) {
+	source, err := os.Open(m.dataFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data file: %w", err)
+	}
+	defer source.Close()
+
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	now := m.clock()
+	name := m.prefix() + now.UTC().Format(backupTimeFormat) + filepath.Ext(m.dataFile)
+	target, err := os.OpenFile(filepath.Join(m.dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	size, err := io.Copy(target, source)
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	if err := m.rotate(); err != nil {
+		return nil, err
+	}
+
+	return &Backup{Name: name, CreatedAt: now, Size: size}, nil
+}
+
+// List returns the available backups, newest first
+func (m *BackupManager) List() ([]Backup, error) {
+	entries, err := os.ReadDir(m.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		created, ok := m.parseName(entry.Name())
+		if entry.IsDir() || !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Name: entry.Name(), CreatedAt: created, Size: info.Size()})
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int {
+		return strings.Compare(b.Name, a.Name)
+	})
+
+	return backups, nil
+}
+
+// Restore replaces the data file with the named backup. The current file is
+// backed up first so that a restore can itself be undone.
+func (m *BackupManager) Restore(name string) error {
+	if _, ok := m.parseName(name); !ok || filepath.Base(name) != name {
+		return fmt.Errorf("%w: %s", ErrBackupNotFound, name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(m.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrBackupNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	if _, err := m.Create(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(m.dataFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	return nil
+}
+
+// rotate deletes the oldest backups beyond the retention limit
+func (m *BackupManager) rotate() error {
+	if m.keep <= 0 {
+		return nil
+	}
+
+	backups, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups[min(m.keep, len(backups)):] {
+		if err := os.Remove(filepath.Join(m.dir, backup.Name)); err != nil {
+			return fmt.Errorf("failed to remove old backup: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// prefix is the data file name without extension, e.g. "tasks-"
+func (m *BackupManager) prefix() string {
+	base := filepath.Base(m.dataFile)
+	return strings.TrimSuffix(base, filepath.Ext(base)) + "-"
+}
+
+// parseName extracts the timestamp from a backup file name
+func (m *BackupManager) parseName(name string) (time.Time, bool) {
+	stamp, ok := strings.CutPrefix(name, m.prefix())
+	if !ok {
+		return time.Time{}, false
+	}
+	stamp = strings.TrimSuffix(stamp, filepath.Ext(m.dataFile))
+
+	created, err := time.Parse(backupTimeFormat, stamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created, true
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// newTestBackupManager creates a manager with a controllable clock
+func newTestBackupManager(t *testing.T, keep int) (*BackupManager, string, *time.Time) {
+	t.Helper()
+
+	dir := t.TempDir()
+	dataFile := filepath.Join(dir, "tasks.json")
+	manager := NewBackupManager(dataFile, "", keep)
+
+	now := tasktest.FixedTime()
+	manager.clock = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	return manager, dataFile, &now
+}
+
+// TestBackupManager_Rotation tests that only the newest backups are kept
+func TestBackupManager_Rotation(t *testing.T) {
+	manager, dataFile, _ := newTestBackupManager(t, 2)
+	repo := NewFileTaskRepository(dataFile).WithBackups(manager)
+
+	for i := 1; i <= 4; i++ {
+		if err := repo.Save(t.Context(), tasktest.TaskSet(t, i)); err != nil {
+			t.Fatalf("Save() %d failed: %v", i, err)
+		}
+	}
+
+	backups, err := manager.List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+
+	// The first save had nothing to back up, the next three rotate down to two
+	if len(backups) != 2 {
+		t.Fatalf("List() returned %d backups, want 2", len(backups))
+	}
+	if !backups[0].CreatedAt.After(backups[1].CreatedAt) {
+		t.Errorf("List() should return newest first")
+	}
+	if filepath.Dir(filepath.Join(manager.dir, backups[0].Name)) != filepath.Join(filepath.Dir(dataFile), "backups") {
+		t.Errorf("backups should default to a directory next to the data file")
+	}
+}
+
+// TestBackupManager_Restore tests that a backup replaces the data file
+func TestBackupManager_Restore(t *testing.T) {
+	manager, dataFile, _ := newTestBackupManager(t, 0)
+	repo := NewFileTaskRepository(dataFile).WithBackups(manager)
+
+	if err := repo.Save(t.Context(), tasktest.TaskSet(t, 3)); err != nil {
+		t.Fatal(err)
+	}
+	// Fat-fingered delete: the pre-delete state is backed up by this save
+	if err := repo.Save(t.Context(), tasktest.TaskSet(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := manager.List()
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("List() = %v, %v; want one backup", backups, err)
+	}
+
+	if err := manager.Restore(backups[0].Name); err != nil {
+		t.Fatalf("Restore() failed: %v", err)
+	}
+
+	tasks, err := repo.Load(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 {
+		t.Errorf("Restore() left %d tasks, want 3", len(tasks))
+	}
+
+	backups, _ = manager.List()
+	if len(backups) != 2 {
+		t.Errorf("Restore() should back up the state it replaced, have %d backups", len(backups))
+	}
+
+	for _, name := range []string{"missing", "../tasks.json", "tasks-20990101T000000.000.json"} {
+		if err := manager.Restore(name); !errors.Is(err, ErrBackupNotFound) {
+			t.Errorf("Restore(%q) error = %v, want %v", name, err, ErrBackupNotFound)
+		}
+	}
+}
+
+// TestBackupManager_NoDataFile tests that creating a backup of nothing is a no-op
+func TestBackupManager_NoDataFile(t *testing.T) {
+	manager, _, _ := newTestBackupManager(t, 5)
+
+	backup, err := manager.Create()
+	if err != nil || backup != nil {
+		t.Errorf("Create() = %v, %v; want nil, nil", backup, err)
+	}
+	if _, err := os.Stat(manager.dir); !os.IsNotExist(err) {
+		t.Errorf("Create() should not create the backup directory needlessly")
+	}
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// ErrBlobConflict is returned when the task file was replaced since it was
+// read, so saving would overwrite someone else's changes
+var ErrBlobConflict = errors.New("the task file was changed by another machine, try again")
+
+// Blob is a remote file read and written whole, such as an object in a
+// bucket or a file on a WebDAV server
+type Blob interface {
+	// Get returns the content of the file and its ETag, or no content and
+	// an empty ETag when the file does not exist
+	Get(ctx context.Context) (data []byte, etag string, err error)
+	// Put replaces the file if it still has the ETag, or creates it if etag
+	// is empty and it does not exist, returning the new ETag. Otherwise it
+	// fails with ErrBlobConflict.
+	Put(ctx context.Context, data []byte, etag string) (string, error)
+	// String names the file in messages
+	String() string
+}
+
+// Blob Repository Implementation (Adapter)
+//
+// BlobTaskRepository keeps the data file, in the same format as tasks.json,
+// in a Blob. Saves are conditional on the ETag of the file last loaded, so
+// two machines saving at once cannot overwrite each other: the second one
+// fails with ErrBlobConflict and retries from fresh data.
+type BlobTaskRepository struct {
+	blob Blob
+
+	mu     sync.Mutex
+	loaded bool
+	// etag is that of the file last loaded or saved, empty when there was
+	// no file
+	etag   string
+	lastID int
+}
+
+func NewBlobTaskRepository(blob Blob) *BlobTaskRepository {
+	return &BlobTaskRepository{blob: blob}
+}
+
+// String names the file the tasks are stored in
+func (r *BlobTaskRepository) String() string {
+	return r.blob.String()
+}
+
+func (r *BlobTaskRepository) Load(ctx context.Context) ([]task.Task, error) {
+	data, etag, err := r.blob.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []task.Task{}
+	header := StoreHeader{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		tasks, header, err = decodeStore(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tasks: %w", err)
+		}
+		if !header.ChecksumValid {
+			return nil, ErrChecksumMismatch
+		}
+	}
+	lastID := header.LastID
+	for _, task := range tasks {
+		lastID = max(lastID, task.ID)
+	}
+
+	r.mu.Lock()
+	r.loaded = true
+	r.etag = etag
+	r.lastID = lastID
+	r.mu.Unlock()
+
+	return tasks, nil
+}
+
+func (r *BlobTaskRepository) Save(ctx context.Context, tasks []task.Task) error {
+	if err := r.ensureLoaded(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	lastID, etag := r.lastID, r.etag
+	r.mu.Unlock()
+
+	var buf bytes.Buffer
+	if err := writeStore(&buf, tasks, lastID); err != nil {
+		return fmt.Errorf("failed to marshal tasks: %w", err)
+	}
+	etag, err := r.blob.Put(ctx, buf.Bytes(), etag)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.etag = etag
+	for _, task := range tasks {
+		lastID = max(lastID, task.ID)
+	}
+	r.lastID = lastID
+	r.mu.Unlock()
+
+	return nil
+}
+
+// GetNextID returns the ID after the highest ever given, as the data file
+// does
+func (r *BlobTaskRepository) GetNextID(ctx context.Context) (int, error) {
+	if err := r.ensureLoaded(ctx); err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastID + 1, nil
+}
+
+func (r *BlobTaskRepository) NextSequenceID(ctx context.Context) (int, error) {
+	return r.GetNextID(ctx)
+}
+
+// Probe checks the store accepts writes, when the blob can tell by writing
+// and removing a file next to the task file
+func (r *BlobTaskRepository) Probe(ctx context.Context) error {
+	if prober, ok := r.blob.(StorageProber); ok {
+		return prober.Probe(ctx)
+	}
+	return nil
+}
+
+// ensureLoaded loads the file once, for the ETag and ID counter a save
+// depends on
+func (r *BlobTaskRepository) ensureLoaded(ctx context.Context) error {
+	r.mu.Lock()
+	loaded := r.loaded
+	r.mu.Unlock()
+	if loaded {
+		return nil
+	}
+	_, err := r.Load(ctx)
+	return err
+}
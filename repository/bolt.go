@@ -0,0 +1,339 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Bolt Repository Implementation (Adapter)
+//
+// Stores each task under its ID in an embedded bbolt database, so saving
+// one changed task rewrites only the pages holding it rather than the whole
+// store. The largest ID ever saved is kept as the sequence of a separate
+// bucket, which makes GetNextID a single read and keeps IDs of deleted tasks
+// from being handed out again. The database is opened for each call, so
+// several processes can share it; bbolt's file lock makes them take turns.
+// It is also a TaskStore, so the service reads and writes single tasks
+// without going through the whole list.
+
+var (
+	boltTasksBucket    = []byte("tasks")
+	boltSequenceBucket = []byte("sequence")
+)
+
+// boltOpenTimeout is how long a call waits for another process holding the
+// database
+const boltOpenTimeout = 5 * time.Second
+
+type BoltTaskRepository struct {
+	filename string
+}
+
+func NewBoltTaskRepository(filename string) *BoltTaskRepository {
+	return &BoltTaskRepository{filename: filename}
+}
+
+func (r *BoltTaskRepository) StoreVersion(ctx context.Context) (string, error) {
+	return fileVersion(r.filename)
+}
+
+func (r *BoltTaskRepository) Load(ctx context.Context) ([]task.Task, error) {
+	tasks := []task.Task{}
+	err := r.view(ctx, func(bucket *bolt.Bucket) error {
+		return bucket.ForEach(func(key, value []byte) error {
+			task, err := decodeBoltTask(key, value)
+			if err != nil {
+				return err
+			}
+			tasks = append(tasks, task)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *BoltTaskRepository) Get(ctx context.Context, id int) (task.Task, error) {
+	var t task.Task
+	err := r.view(ctx, func(bucket *bolt.Bucket) error {
+		value := bucket.Get(boltKey(id))
+		if value == nil {
+			return task.ErrTaskNotFound.Withf("%d", id)
+		}
+		var err error
+		t, err = decodeBoltTask(boltKey(id), value)
+		return err
+	})
+	return t, err
+}
+
+func (r *BoltTaskRepository) Put(ctx context.Context, t task.Task) error {
+	return r.Apply(ctx, []task.TaskChange{{Type: task.ChangeUpdated, Task: t}})
+}
+
+func (r *BoltTaskRepository) Delete(ctx context.Context, id int) error {
+	return r.update(ctx, func(tasks, _ *bolt.Bucket) error {
+		if tasks.Get(boltKey(id)) == nil {
+			return task.ErrTaskNotFound.Withf("%d", id)
+		}
+		return tasks.Delete(boltKey(id))
+	})
+}
+
+func (r *BoltTaskRepository) List(ctx context.Context, filter TaskFilter) ([]task.Task, error) {
+	return r.LoadPage(ctx, filter.Page, filter.Match)
+}
+
+func (r *BoltTaskRepository) NextID(ctx context.Context) (int, error) {
+	return r.NextSequenceID(ctx)
+}
+
+// Apply writes the changes in one transaction, after checking that they
+// were made to the stored versions of the tasks
+func (r *BoltTaskRepository) Apply(ctx context.Context, changes []task.TaskChange) error {
+	return r.update(ctx, func(tasks, sequence *bolt.Bucket) error {
+		err := checkRevisions(changes, func(id int) (task.Task, bool) {
+			value := tasks.Get(boltKey(id))
+			if value == nil {
+				return task.Task{}, false
+			}
+			task, err := decodeBoltTask(boltKey(id), value)
+			return task, err == nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if change.Type == task.ChangeDeleted {
+				if err := tasks.Delete(boltKey(change.Task.ID)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := putBoltTask(tasks, sequence, change.Task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReplaceAll stores tasks as the complete list
+func (r *BoltTaskRepository) ReplaceAll(ctx context.Context, tasks []task.Task) error {
+	return r.Save(ctx, tasks)
+}
+
+// LoadPage walks tasks in ID order and stops once the page is full
+func (r *BoltTaskRepository) LoadPage(ctx context.Context, page Page, match func(task.Task) bool) ([]task.Task, error) {
+	selected := []task.Task{}
+	skipped := 0
+	err := r.view(ctx, func(bucket *bolt.Bucket) error {
+		cursor := bucket.Cursor()
+		for key, value := cursor.First(); key != nil; key, value = cursor.Next() {
+			task, err := decodeBoltTask(key, value)
+			if err != nil {
+				return err
+			}
+			if match != nil && !match(task) {
+				continue
+			}
+			if skipped < page.Offset {
+				skipped++
+				continue
+			}
+			selected = append(selected, task)
+			if page.Limit > 0 && len(selected) == page.Limit {
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// Save writes the tasks that changed and deletes those no longer listed,
+// all in one transaction
+func (r *BoltTaskRepository) Save(ctx context.Context, tasks []task.Task) error {
+	wanted := make(map[int][]byte, len(tasks))
+	maxID := 0
+	for _, task := range tasks {
+		value, err := json.Marshal(task)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tasks: %w", err)
+		}
+		wanted[task.ID] = value
+		maxID = max(maxID, task.ID)
+	}
+
+	return r.update(ctx, func(bucket, sequence *bolt.Bucket) error {
+		// Compare with what is stored first, since writing while a cursor
+		// walks the bucket would invalidate it
+		var removed [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			id := int(binary.BigEndian.Uint64(key))
+			want, ok := wanted[id]
+			switch {
+			case !ok:
+				removed = append(removed, bytes.Clone(key))
+			case bytes.Equal(want, value):
+				delete(wanted, id)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range removed {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		for id, value := range wanted {
+			if err := bucket.Put(boltKey(id), value); err != nil {
+				return err
+			}
+		}
+
+		if uint64(maxID) > sequence.Sequence() {
+			return sequence.SetSequence(uint64(maxID))
+		}
+		return nil
+	})
+}
+
+func (r *BoltTaskRepository) GetNextID(ctx context.Context) (int, error) {
+	return r.NextSequenceID(ctx)
+}
+
+// NextSequenceID reads the ID sequence without loading any task
+func (r *BoltTaskRepository) NextSequenceID(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	db, err := r.open()
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	var next int
+	err = db.View(func(tx *bolt.Tx) error {
+		next = int(tx.Bucket(boltSequenceBucket).Sequence()) + 1
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read database: %w", err)
+	}
+	return next, nil
+}
+
+// update runs fn over the tasks and sequence buckets in a read-write
+// transaction
+func (r *BoltTaskRepository) update(ctx context.Context, fn func(tasks, sequence *bolt.Bucket) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	db, err := r.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(boltTasksBucket), tx.Bucket(boltSequenceBucket))
+	})
+	var taskErr task.TaskError
+	if errors.As(err, &taskErr) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write database: %w", err)
+	}
+	return nil
+}
+
+// view runs fn over the tasks bucket in a read-only transaction
+func (r *BoltTaskRepository) view(ctx context.Context, fn func(*bolt.Bucket) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	db, err := r.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = db.View(func(tx *bolt.Tx) error { return fn(tx.Bucket(boltTasksBucket)) })
+	if errors.Is(err, task.ErrTaskNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read database: %w", err)
+	}
+	return nil
+}
+
+// open opens the database, creating it and its buckets on first use
+func (r *BoltTaskRepository) open() (*bolt.DB, error) {
+	db, err := bolt.Open(r.filename, 0o600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{boltTasksBucket, boltSequenceBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, nil
+}
+
+// putBoltTask stores a task and moves the sequence past its ID
+func putBoltTask(tasks, sequence *bolt.Bucket, task task.Task) error {
+	value, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	if err := tasks.Put(boltKey(task.ID), value); err != nil {
+		return err
+	}
+	if uint64(task.ID) > sequence.Sequence() {
+		return sequence.SetSequence(uint64(task.ID))
+	}
+	return nil
+}
+
+// boltKey encodes an ID so that keys sort in ID order
+func boltKey(id int) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(id))
+}
+
+func decodeBoltTask(key, value []byte) (task.Task, error) {
+	var t task.Task
+	if err := json.Unmarshal(value, &t); err != nil {
+		return task.Task{}, fmt.Errorf("task %d: %w", binary.BigEndian.Uint64(key), err)
+	}
+	return t, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestBoltTaskRepository tests saving, loading and ID generation
+func TestBoltTaskRepository(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "tasks.db")
+	repo := NewBoltTaskRepository(filename)
+
+	if tasks, err := repo.Load(ctx); err != nil || len(tasks) != 0 {
+		t.Fatalf("Load of a new database = %v, %v; want no tasks", tasks, err)
+	}
+	if id, err := repo.GetNextID(ctx); err != nil || id != 1 {
+		t.Fatalf("GetNextID of a new database = %d, %v; want 1", id, err)
+	}
+
+	tasks := tasktest.FixedTasks(t)
+	if err := repo.Save(ctx, tasks); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	tasks[0].Description = "Buy bread"
+	tasks = tasks[:2]
+	if err := repo.Save(ctx, tasks); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := NewBoltTaskRepository(filename).Load(ctx)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Description != "Buy bread" || loaded[1].ID != 2 {
+		t.Errorf("Load = %+v, want Buy bread and task 2", loaded)
+	}
+
+	// Task 3 was deleted, its ID stays used
+	if id, err := repo.GetNextID(ctx); err != nil || id != 4 {
+		t.Errorf("GetNextID = %d, %v; want 4", id, err)
+	}
+
+	page, err := repo.LoadPage(ctx, Page{Offset: 1, Limit: 1}, nil)
+	if err != nil || len(page) != 1 || page[0].ID != 2 {
+		t.Errorf("LoadPage = %+v, %v; want task 2", page, err)
+	}
+}
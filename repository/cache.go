@@ -0,0 +1,401 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// UnitOfWork groups the loads and saves of one command so that the store
+// is read at most once and written at most once
+type UnitOfWork interface {
+	Begin()
+	Commit(ctx context.Context) error
+}
+
+// AfterCommitter is implemented by units of work that can hold back what
+// depends on their changes being saved, such as audit entries and hooks
+type AfterCommitter interface {
+	// AfterCommit queues fn to run once Commit has saved the changes of the
+	// current unit, and reports false outside a unit, where the caller runs
+	// fn itself. Commit drops the queue when saving fails.
+	AfterCommit(fn func() error) bool
+}
+
+// AfterCommitError is returned by Commit when the changes were saved but
+// work queued with AfterCommit failed
+type AfterCommitError struct {
+	Err error
+}
+
+func (e *AfterCommitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AfterCommitError) Unwrap() error {
+	return e.Err
+}
+
+// Invalidator is implemented by repositories that cache what they load
+type Invalidator interface {
+	Invalidate()
+}
+
+// StoreVersioner is implemented by repositories that can tell cheaply
+// whether their storage changed, without reading the tasks
+type StoreVersioner interface {
+	// StoreVersion returns a value that changes whenever the stored tasks do
+	StoreVersion(ctx context.Context) (string, error)
+}
+
+// Caching Repository Implementation (Decorator)
+//
+// CachingRepository wraps another repository. Outside a unit of work it
+// passes every call through. Between Begin and Commit the first Load is
+// kept in memory, later loads and GetNextID are answered from it, and saves
+// only replace it and mark it dirty. Commit writes once: it applies what
+// changed since the first Load to the stored tasks, failing with ErrConflict
+// if another process changed the same tasks meanwhile, so changes to other
+// tasks are kept. A unit that adds tasks holds the lock of the store from
+// the moment it picks their IDs until Commit, so processes adding tasks at
+// the same time take turns instead of picking the same ID. Long-running
+// commands stay outside units of work so that they see changes made by
+// other processes.
+//
+// With Retain, tasks read by a unit that wrote nothing are kept for the
+// next one as long as the store reports the same version, which lets a
+// long-lived process answer one query after another without reading the
+// store each time.
+type CachingRepository struct {
+	repo TaskRepository
+
+	mu     sync.Mutex
+	active bool
+	tasks  []task.Task // nil until loaded
+	loaded []task.Task // the tasks as first loaded, nil if not loaded
+	dirty  bool
+	// replaced asks Commit to store the list as it is, not only its changes
+	replaced bool
+	// retain keeps unchanged tasks from one unit to the next while the
+	// store stays at version
+	retain  bool
+	version string
+	// unlock releases the lock of the store taken to add tasks, nil when
+	// the unit does not hold it
+	unlock func()
+	// after is the work to run once the unit's changes are saved
+	after []func() error
+}
+
+func NewCachingRepository(repo TaskRepository) *CachingRepository {
+	return &CachingRepository{repo: repo}
+}
+
+// Unwrap returns the wrapped repository, so its optional capabilities such
+// as history or change notifications stay reachable
+func (r *CachingRepository) Unwrap() TaskRepository {
+	return r.repo
+}
+
+// Retain keeps the tasks read by a unit of work for the next unit, until
+// the store changes. It only helps with repositories implementing
+// StoreVersioner; others are read again by each unit.
+func (r *CachingRepository) Retain() *CachingRepository {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retain = true
+	return r
+}
+
+// Begin starts a unit of work with an empty cache, or with the tasks kept
+// by Retain if the store has not changed since they were read
+func (r *CachingRepository) Begin() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.release()
+	r.active = true
+	r.after = nil
+	if r.tasks != nil && r.retain && !r.dirty && r.version != "" {
+		version, err := r.storeVersion(context.Background())
+		if err == nil && version == r.version {
+			return
+		}
+	}
+	r.reset()
+}
+
+// storeVersion returns the version of the wrapped store, empty when it
+// cannot tell; callers hold r.mu
+func (r *CachingRepository) storeVersion(ctx context.Context) (string, error) {
+	versioner, ok := RepositoryAs[StoreVersioner](r.repo)
+	if !ok {
+		return "", nil
+	}
+	return versioner.StoreVersion(ctx)
+}
+
+// release gives back the lock of the store if the unit holds it; callers
+// hold r.mu
+func (r *CachingRepository) release() {
+	if r.unlock != nil {
+		r.unlock()
+		r.unlock = nil
+	}
+}
+
+// reset forgets the unit's tasks; callers hold r.mu
+func (r *CachingRepository) reset() {
+	r.tasks = nil
+	r.loaded = nil
+	r.dirty = false
+	r.replaced = false
+	r.version = ""
+}
+
+// Commit writes pending changes, if any, ends the unit of work, then runs
+// the work queued with AfterCommit
+func (r *CachingRepository) Commit(ctx context.Context) error {
+	after, err := r.commit(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, fn := range after {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &AfterCommitError{Err: errors.Join(errs...)}
+	}
+	return nil
+}
+
+// commit writes pending changes and returns the work to run now that they
+// are saved
+func (r *CachingRepository) commit(ctx context.Context) ([]func() error, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tasks, loaded, dirty, replaced, after := r.tasks, r.loaded, r.dirty, r.replaced, r.after
+	r.active = false
+	r.after = nil
+	if dirty || !r.retain {
+		r.reset()
+	}
+	defer r.release()
+	if !dirty {
+		return after, nil
+	}
+	write := func() error {
+		if replaced || loaded == nil {
+			return r.repo.Save(ctx, tasks)
+		}
+		store := &repositoryStore{repo: r.repo}
+		return store.apply(ctx, task.DiffTasks(loaded, tasks))
+	}
+	var err error
+	if r.unlock != nil {
+		err = write()
+	} else {
+		err = withLock(ctx, r.repo, write)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// AfterCommit queues fn until Commit inside a unit of work
+func (r *CachingRepository) AfterCommit(fn func() error) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.active {
+		return false
+	}
+	r.after = append(r.after, fn)
+	return true
+}
+
+// Lock takes the lock of the wrapped store outside a unit of work. Inside
+// one, saves stay in memory until Commit, which takes the lock itself.
+func (r *CachingRepository) Lock(ctx context.Context) (func(), error) {
+	r.mu.Lock()
+	active := r.active
+	r.mu.Unlock()
+	locker, ok := RepositoryAs[Locker](r.repo)
+	if active || !ok {
+		return func() {}, nil
+	}
+	return locker.Lock(ctx)
+}
+
+// Invalidate drops cached tasks that have no pending changes, so the next
+// Load reads the store again. Watchers call it when the store changed.
+func (r *CachingRepository) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.dirty {
+		r.tasks = nil
+		r.loaded = nil
+		r.version = ""
+	}
+}
+
+func (r *CachingRepository) Load(ctx context.Context) ([]task.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load(ctx)
+}
+
+// load returns a copy of the cached tasks, reading them first if needed;
+// callers hold r.mu
+func (r *CachingRepository) load(ctx context.Context) ([]task.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Tasks retained between units are only for the next unit
+	if r.active && r.tasks != nil {
+		return slices.Clone(r.tasks), nil
+	}
+
+	// The version is read first, so a change made while loading is seen
+	// by the next unit
+	var version string
+	if r.active && r.retain {
+		version, _ = r.storeVersion(ctx)
+	}
+	tasks, err := r.repo.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.active {
+		r.tasks = slices.Clone(tasks)
+		r.loaded = slices.Clone(tasks)
+		r.version = version
+	}
+	return tasks, nil
+}
+
+func (r *CachingRepository) Save(ctx context.Context, tasks []task.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active {
+		return r.repo.Save(ctx, tasks)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.tasks = slices.Clone(tasks)
+	if r.tasks == nil {
+		r.tasks = []task.Task{}
+	}
+	r.dirty = true
+	return nil
+}
+
+// ReplaceAll saves tasks as the complete list, which Commit then stores as
+// it is rather than merging it with changes made meanwhile
+func (r *CachingRepository) ReplaceAll(ctx context.Context, tasks []task.Task) error {
+	if err := r.Save(ctx, tasks); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.replaced = r.active
+	return nil
+}
+
+// LoadPage answers from the cache when it holds the tasks, and otherwise
+// lets the wrapped repository stop reading early if it can
+func (r *CachingRepository) LoadPage(ctx context.Context, page Page, match func(task.Task) bool) ([]task.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active || r.tasks == nil {
+		if loader, ok := RepositoryAs[PageLoader](r.repo); ok {
+			return loader.LoadPage(ctx, page, match)
+		}
+	}
+	tasks, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return PageTasks(tasks, page, match), nil
+}
+
+func (r *CachingRepository) GetNextID(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active {
+		return r.repo.GetNextID(ctx)
+	}
+	if err := r.hold(ctx); err != nil {
+		return 0, err
+	}
+	tasks, err := r.load(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	maxID := 0
+	for _, task := range tasks {
+		maxID = max(maxID, task.ID)
+	}
+	next := maxID + 1
+	if sequencer, ok := RepositoryAs[IDSequencer](r.repo); ok {
+		sequenced, err := sequencer.NextSequenceID(ctx)
+		if err != nil {
+			return 0, err
+		}
+		next = max(next, sequenced)
+	}
+	if r.unlock != nil {
+		// Tasks may have been added since the unit read the store
+		stored, err := r.repo.GetNextID(ctx)
+		if err != nil {
+			return 0, err
+		}
+		next = max(next, stored)
+	}
+	return next, nil
+}
+
+// hold takes the lock of the store until Commit, if the store has one and
+// the unit does not hold it yet; callers hold r.mu
+func (r *CachingRepository) hold(ctx context.Context) error {
+	if r.unlock != nil {
+		return nil
+	}
+	locker, ok := RepositoryAs[Locker](r.repo)
+	if !ok {
+		return nil
+	}
+	unlock, err := locker.Lock(ctx)
+	if err != nil {
+		return StorageError("failed to lock tasks", err)
+	}
+	r.unlock = unlock
+	return nil
+}
+
+// RepositoryAs finds a repository implementing T, looking through
+// decorators that expose what they wrap with Unwrap
+func RepositoryAs[T any](repo TaskRepository) (T, bool) {
+	for {
+		if found, ok := repo.(T); ok {
+			return found, true
+		}
+		wrapper, ok := repo.(interface{ Unwrap() TaskRepository })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		repo = wrapper.Unwrap()
+	}
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCachingRepository_PassThrough tests calls outside a unit of work
+func TestCachingRepository_PassThrough(t *testing.T) {
+	ctx := context.Background()
+	mock := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	cache := NewCachingRepository(mock)
+
+	for range 2 {
+		if _, err := cache.Load(ctx); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+	}
+	if err := cache.Save(ctx, nil); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if mock.LoadCallCount() != 2 || mock.SaveCallCount() != 1 || mock.TaskCount() != 0 {
+		t.Errorf("loads = %d, saves = %d, tasks = %d; want every call passed through",
+			mock.LoadCallCount(), mock.SaveCallCount(), mock.TaskCount())
+	}
+}
+
+// TestCachingRepository_Invalidate tests re-reading the store on demand
+func TestCachingRepository_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	mock := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	cache := NewCachingRepository(mock)
+
+	cache.Begin()
+	cache.Load(ctx)
+	mock.WithTasks(nil) // changed by another process
+	if tasks, _ := cache.Load(ctx); len(tasks) != 3 {
+		t.Errorf("cached Load = %d tasks, want 3", len(tasks))
+	}
+	cache.Invalidate()
+	if tasks, _ := cache.Load(ctx); len(tasks) != 0 {
+		t.Errorf("Load after Invalidate = %d tasks, want 0", len(tasks))
+	}
+}
+
+// versionedMock is a mock store whose version the test sets
+type versionedMock struct {
+	*tasktest.MockTaskRepository
+	version string
+}
+
+func (m *versionedMock) StoreVersion(ctx context.Context) (string, error) {
+	return m.version, nil
+}
+
+// TestCachingRepository_Retain tests keeping tasks from one unit to the
+// next until the store changes or a unit writes
+func TestCachingRepository_Retain(t *testing.T) {
+	ctx := context.Background()
+	mock := &versionedMock{MockTaskRepository: tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t)), version: "1"}
+	cache := NewCachingRepository(mock).Retain()
+	unit := func() {
+		cache.Begin()
+		cache.Load(ctx)
+		cache.Commit(ctx)
+	}
+
+	unit()
+	unit()
+	if mock.LoadCallCount() != 1 {
+		t.Errorf("loads = %d, want 1 while the version stays", mock.LoadCallCount())
+	}
+	if tasks, _ := cache.Load(ctx); len(tasks) != 3 || mock.LoadCallCount() != 2 {
+		t.Errorf("Load outside a unit = %d tasks after %d loads, want it read from the store", len(tasks), mock.LoadCallCount())
+	}
+
+	mock.version = "2"
+	unit()
+	if mock.LoadCallCount() != 3 {
+		t.Errorf("loads = %d, want 3 once the version changed", mock.LoadCallCount())
+	}
+
+	cache.Begin()
+	tasks, _ := cache.Load(ctx)
+	cache.Save(ctx, tasks[1:])
+	cache.Commit(ctx)
+	unit()
+	if mock.LoadCallCount() != 5 || mock.TaskCount() != 2 {
+		t.Errorf("loads = %d, tasks = %d; want the store read again after a write", mock.LoadCallCount(), mock.TaskCount())
+	}
+}
+
+// TestCachingRepository_AfterCommit tests that queued work runs only once
+// the unit's changes are saved
+func TestCachingRepository_AfterCommit(t *testing.T) {
+	ctx := context.Background()
+	mock := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	cache := NewCachingRepository(mock)
+	ran := 0
+	count := func() error { ran++; return nil }
+
+	if cache.AfterCommit(count) {
+		t.Error("AfterCommit outside a unit of work queued the work")
+	}
+
+	cache.Begin()
+	tasks, _ := cache.Load(ctx)
+	cache.Save(ctx, tasks[1:])
+	cache.AfterCommit(count)
+	if ran != 0 {
+		t.Fatalf("queued work ran before Commit")
+	}
+	if err := cache.Commit(ctx); err != nil || ran != 1 {
+		t.Fatalf("Commit() = %v after running the work %d times, want it run once", err, ran)
+	}
+
+	cache.Begin()
+	cache.AfterCommit(func() error { return errors.New("hook failed") })
+	var after *AfterCommitError
+	if err := cache.Commit(ctx); !errors.As(err, &after) {
+		t.Errorf("Commit() with failing work = %v, want an AfterCommitError", err)
+	}
+
+	cache.Begin()
+	tasks, _ = cache.Load(ctx)
+	cache.Save(ctx, tasks[1:])
+	cache.AfterCommit(count)
+	mock.WithError(errors.New("disk full"))
+	if err := cache.Commit(ctx); err == nil || errors.As(err, &after) {
+		t.Errorf("Commit() on a failing store = %v, want the save error", err)
+	}
+	if ran != 1 {
+		t.Errorf("queued work ran although the save failed")
+	}
+}
+
+// TestRepositoryAs tests finding capabilities behind decorators
+func TestRepositoryAs(t *testing.T) {
+	file := NewFileTaskRepository(t.TempDir() + "/tasks.json")
+	cache := NewCachingRepository(file)
+
+	if _, ok := RepositoryAs[task.StoreInspector](cache); !ok {
+		t.Error("StoreInspector not found through the cache")
+	}
+	if _, ok := RepositoryAs[TaskHistorian](cache); ok {
+		t.Error("TaskHistorian found on a plain file repository")
+	}
+}
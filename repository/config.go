@@ -0,0 +1,553 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// ConfigEnvVar overrides the location of the configuration file
+const ConfigEnvVar = "TASK_CLI_CONFIG"
+
+// DataFileEnvVar points the CLI at another task file, like --file
+const DataFileEnvVar = "TASK_CLI_FILE"
+
+// defaultDataFile is the task file used when nothing else is configured
+const defaultDataFile = "tasks.json"
+
+// Config holds user preferences read from a JSON file
+type Config struct {
+	// User names you in shared task files, defaulting to $TASK_CLI_USER or
+	// the login name
+	User string `json:"user"`
+	// Language is the language messages are printed in, such as "fr",
+	// defaulting to $LC_ALL, $LC_MESSAGES or $LANG
+	Language string `json:"language"`
+	// DataFile is the task file, empty uses tasks.json; --file and
+	// $TASK_CLI_FILE take precedence
+	DataFile string `json:"dataFile"`
+	// ReadOnly refuses every change to the tasks, as --read-only does
+	ReadOnly bool           `json:"readOnly"`
+	Notify   NotifyConfig   `json:"notify"`
+	Backup   BackupConfig   `json:"backup"`
+	Remote   RemoteConfig   `json:"remote"`
+	Server   ServerConfig   `json:"server"`
+	Webhooks WebhookConfig  `json:"webhooks"`
+	Slack    SlackConfig    `json:"slack"`
+	MCP      MCPConfig      `json:"mcp"`
+	SMTP     SMTPConfig     `json:"smtp"`
+	Sync     SyncConfig     `json:"sync"`
+	Git      GitConfig      `json:"git"`
+	Audit    AuditConfig    `json:"audit"`
+	GitHub   GitHubConfig   `json:"github"`
+	Todoist  TodoistConfig  `json:"todoist"`
+	Jira     JiraConfig     `json:"jira"`
+	TodoTxt  TodoTxtConfig  `json:"todotxt"`
+	EventLog EventLogConfig `json:"eventLog"`
+	Bolt     BoltConfig     `json:"bolt"`
+	S3       S3Config       `json:"s3"`
+	WebDAV   WebDAVConfig   `json:"webdav"`
+	Display  DisplayConfig  `json:"display"`
+	Confirm  ConfirmConfig  `json:"confirm"`
+	Workflow WorkflowConfig `json:"workflow"`
+	Next     NextConfig     `json:"next"`
+	Lists    ListsConfig    `json:"lists"`
+	// Descriptions tidies descriptions as tasks are added and renamed
+	Descriptions DescriptionsConfig `json:"descriptions"`
+	// Hooks runs Starlark scripts as tasks are added, done and deleted
+	Hooks HooksConfig `json:"hooks"`
+	// Schedules create tasks on cron schedules, see the tick command
+	Schedules []ScheduleConfig `json:"schedules"`
+	// Aliases name command lines, see the alias command
+	Aliases map[string]string `json:"aliases"`
+	// Formats name task templates for --format and --template
+	Formats map[string]string `json:"formats"`
+}
+
+// WorkflowConfig adds statuses to todo, in-progress and done, and restricts
+// which moves are allowed
+type WorkflowConfig struct {
+	Statuses []string `json:"statuses"`
+	// Transitions maps a status to the statuses it may move to; statuses
+	// without an entry may move anywhere
+	Transitions map[string][]string `json:"transitions"`
+	// WarnOnSkip warns when a task is marked done without being started
+	WarnOnSkip bool `json:"warnOnSkip"`
+	// WIPLimits caps how many tasks a status may hold, such as
+	// {"in-progress": 3}
+	WIPLimits map[string]int `json:"wipLimits"`
+	// RequireChecklist refuses marking a task done while its checklist has
+	// unchecked items
+	RequireChecklist bool `json:"requireChecklist"`
+	// KeepDuplicatesOpen stops closing a task from closing the tasks linked
+	// to it as duplicates
+	KeepDuplicatesOpen bool `json:"keepDuplicatesOpen"`
+}
+
+// DescriptionsConfig chooses the rules descriptions are tidied with, none
+// by default
+type DescriptionsConfig struct {
+	Capitalize               bool `json:"capitalize"`
+	StripTrailingPunctuation bool `json:"stripTrailingPunctuation"`
+	CollapseWhitespace       bool `json:"collapseWhitespace"`
+	// Abbreviations maps words to what they stand for, such as
+	// {"mtg": "meeting"}
+	Abbreviations map[string]string `json:"abbreviations"`
+	// MaxLength caps the characters of a description, 0 for no limit
+	MaxLength int `json:"maxLength"`
+	// TooLong is "error" to refuse a longer description, the default, or
+	// "truncate" to shorten it and keep the whole text as a note
+	TooLong string `json:"tooLong"`
+}
+
+// HooksConfig names the Starlark scripts run on task changes
+type HooksConfig struct {
+	// Scripts define functions named after events, such as pre_add or
+	// post_done, run in the order of the scripts
+	Scripts []string `json:"scripts"`
+	// MaxSteps bounds the work of one hook call, 0 for the default
+	MaxSteps int `json:"maxSteps"`
+	// Commands maps on-add, on-done and on-delete to shell commands run
+	// once the change is saved, with the task as JSON on stdin
+	Commands map[string][]string `json:"commands"`
+}
+
+// ListsConfig configures the lists of the task file
+type ListsConfig struct {
+	// Default names the list commands work on without --list, which holds
+	// the tasks filed in no list; empty names it "default"
+	Default string `json:"default"`
+}
+
+// NextConfig tunes how next picks a task
+type NextConfig struct {
+	// Weights overrides the points of priority, due, age, inProgress and
+	// blocked; 0 turns a factor off
+	Weights map[string]float64 `json:"weights"`
+}
+
+// DisplayConfig configures how commands print tasks
+type DisplayConfig struct {
+	// Relative shows times as "3 days ago" instead of dates, like --relative
+	Relative bool `json:"relative"`
+	// Template prints list through a task template, or the name of one
+	// under formats, unless --format or --template is given
+	Template string `json:"template"`
+	// Accessible prints for screen readers, like --accessible
+	Accessible bool `json:"accessible"`
+}
+
+// ConfirmConfig chooses which destructive commands ask before running
+type ConfirmConfig struct {
+	// Delete asks before deleting tasks, unless --yes is given
+	Delete bool `json:"delete"`
+}
+
+// TodoTxtConfig stores tasks in a todo.txt file instead of tasks.json
+type TodoTxtConfig struct {
+	// File is the todo.txt to read and write, empty uses tasks.json
+	File string `json:"file"`
+}
+
+// EventLogConfig stores tasks in an append-only event log instead of
+// tasks.json
+type EventLogConfig struct {
+	// File is the log to append to, empty uses tasks.json
+	File string `json:"file"`
+	// CompactAfter is how many events the log holds before it is compacted
+	CompactAfter int `json:"compactAfter"`
+}
+
+// BoltConfig stores tasks in an embedded bbolt database instead of
+// tasks.json
+type BoltConfig struct {
+	// File is the database to open, empty uses tasks.json
+	File string `json:"file"`
+}
+
+// S3Config stores the task file in an S3-compatible bucket
+type S3Config struct {
+	// Endpoint of the service, empty for AWS in Region
+	Endpoint string `json:"endpoint"`
+	// Region defaults to us-east-1
+	Region string `json:"region"`
+	// Bucket holding the task file, empty uses tasks.json
+	Bucket string `json:"bucket"`
+	// Key of the task file in the bucket, default tasks.json
+	Key string `json:"key"`
+	// AccessKeyID, SecretAccessKey and SessionToken default to
+	// $AWS_ACCESS_KEY_ID, $AWS_SECRET_ACCESS_KEY and $AWS_SESSION_TOKEN
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	SessionToken    string `json:"sessionToken"`
+}
+
+// WebDAVConfig stores the task file on a WebDAV server such as Nextcloud
+type WebDAVConfig struct {
+	// URL of the task file, for Nextcloud
+	// https://host/remote.php/dav/files/<user>/tasks.json
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	// Password defaults to $TASK_CLI_WEBDAV_PASSWORD; with Nextcloud use an
+	// app password
+	Password string `json:"password"`
+}
+
+// TodoistConfig configures import from Todoist
+type TodoistConfig struct {
+	// Token defaults to $TODOIST_TOKEN
+	Token  string `json:"token"`
+	APIURL string `json:"apiUrl"`
+}
+
+// JiraConfig configures import from Jira
+type JiraConfig struct {
+	// URL of the site, such as https://team.atlassian.net
+	URL string `json:"url"`
+	// Email of the account for Jira Cloud; leave it empty to use Token as a
+	// Data Center personal access token
+	Email string `json:"email"`
+	// Token defaults to $JIRA_API_TOKEN
+	Token string `json:"token"`
+	// JQL is the default for --jql
+	JQL string `json:"jql"`
+	// KeepKey is the default for --keep-key
+	KeepKey bool         `json:"keepKey"`
+	Fields  JiraFieldMap `json:"fields"`
+}
+
+// JiraFieldMap chooses which Jira fields fill which task fields
+type JiraFieldMap struct {
+	// Priorities maps Jira priority names to task priorities, on top of
+	// Highest, High, Medium, Low and Lowest
+	Priorities map[string]string `json:"priorities"`
+	// Tags is the field whose values become tags, default labels; components
+	// or a custom field such as customfield_10020 work too
+	Tags string `json:"tags"`
+	// Due is the date field used as due date, default duedate
+	Due string `json:"due"`
+}
+
+// GitHubConfig configures import from and push to GitHub issues
+type GitHubConfig struct {
+	// Token defaults to $GITHUB_TOKEN
+	Token string `json:"token"`
+	// Repo and Label are the defaults for --repo and --label
+	Repo  string `json:"repo"`
+	Label string `json:"label"`
+	// APIURL points at GitHub Enterprise, empty uses api.github.com
+	APIURL string `json:"apiUrl"`
+}
+
+// AuditConfig configures the audit log of changes
+type AuditConfig struct {
+	Enabled bool `json:"enabled"`
+	// File defaults to tasks.audit.jsonl next to the data file
+	File string `json:"file"`
+}
+
+// GitConfig keeps the data file in a git repository
+type GitConfig struct {
+	// Enabled commits the data file after every change
+	Enabled bool `json:"enabled"`
+}
+
+// SyncConfig configures the sync command
+type SyncConfig struct {
+	// Remote is the default store to sync with: a path or task server URL
+	Remote string `json:"remote"`
+	// Prefer resolves conflicts automatically: local, remote or newer
+	Prefer string `json:"prefer"`
+}
+
+// RemoteConfig stores tasks on a task-cli server instead of a local file
+type RemoteConfig struct {
+	// URL of a server started with "serve --http", empty uses tasks.json
+	URL   string `json:"url"`
+	Token string `json:"token"`
+	// Timeout limits each request attempt
+	Timeout task.Duration `json:"timeout"`
+	// Retries is how many times a failed request is repeated
+	Retries int `json:"retries"`
+}
+
+// ServerConfig configures the serve command
+type ServerConfig struct {
+	// Token is required from HTTP and gRPC clients when set, granting full
+	// access
+	Token string `json:"token"`
+	// Tokens are more bearer tokens, each naming who uses it
+	Tokens []ServerCredential `json:"tokens"`
+	// Users sign in with HTTP basic auth
+	Users []ServerCredential `json:"users"`
+	// MaxBodyBytes caps the size of HTTP request bodies
+	MaxBodyBytes int64 `json:"maxBodyBytes"`
+	// RateLimit is how many HTTP requests per second each client address
+	// may send on average, in bursts of up to RateBurst; 0 disables it
+	RateLimit float64 `json:"rateLimit"`
+	RateBurst int     `json:"rateBurst"`
+}
+
+// ServerCredential grants access to the servers
+type ServerCredential struct {
+	// Name is recorded as the author of changes, and is the user name of
+	// basic auth
+	Name     string `json:"name"`
+	Token    string `json:"token,omitempty"`
+	Password string `json:"password,omitempty"`
+	// Scope is "read" to only list and show tasks, the default, or "write"
+	Scope string `json:"scope"`
+}
+
+// WebhookConfig configures the events serve posts when tasks change
+type WebhookConfig struct {
+	URLs []string `json:"urls"`
+	// Secret signs every body with HMAC-SHA256 when set
+	Secret string `json:"secret"`
+	// Retries is how many times a failed delivery is repeated
+	Retries int `json:"retries"`
+}
+
+// SlackConfig enables Slack slash commands on the serve HTTP listener
+type SlackConfig struct {
+	// SigningSecret is the Slack app's signing secret, empty disables them
+	SigningSecret string `json:"signingSecret"`
+}
+
+// MCPConfig configures the tools the mcp command offers AI assistants
+type MCPConfig struct {
+	// AllowDestructive offers the tools that delete tasks
+	AllowDestructive bool `json:"allowDestructive"`
+}
+
+// ScheduleConfig creates a task on a cron schedule
+type ScheduleConfig struct {
+	// Name identifies the schedule, defaulting to the description
+	Name string `json:"name"`
+	// Cron is a five field cron expression such as "0 9 * * mon"
+	Cron        string   `json:"cron"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Priority    string   `json:"priority"`
+	Assignee    string   `json:"assignee"`
+	// Due sets the deadline this long after the scheduled time
+	Due task.Duration `json:"due"`
+}
+
+// Schedule converts the settings into a domain schedule
+func (s ScheduleConfig) Schedule() (task.Schedule, error) {
+	if s.Description == "" {
+		return task.Schedule{}, fmt.Errorf("schedule %q: %w", s.Name, task.ErrEmptyDescription)
+	}
+	name := s.Name
+	if name == "" {
+		name = s.Description
+	}
+
+	cron, err := task.ParseCron(s.Cron)
+	if err != nil {
+		return task.Schedule{}, fmt.Errorf("schedule %q: %w", name, err)
+	}
+
+	schedule := task.Schedule{Name: name, Cron: cron, Description: s.Description, Due: time.Duration(s.Due)}
+	if len(s.Tags) > 0 {
+		schedule.Options = append(schedule.Options, task.WithTags(s.Tags...))
+	}
+	if s.Priority != "" {
+		schedule.Options = append(schedule.Options, task.WithPriority(task.Priority(s.Priority)))
+	}
+	if s.Assignee != "" {
+		schedule.Options = append(schedule.Options, task.WithAssignee(s.Assignee))
+	}
+	return schedule, nil
+}
+
+// SMTPConfig configures the server digest emails are sent through
+type SMTPConfig struct {
+	Host string `json:"host"`
+	// Port defaults to 587
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	// Password defaults to $TASK_CLI_SMTP_PASSWORD
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// BackupConfig configures automatic backups before every save
+type BackupConfig struct {
+	Enabled bool `json:"enabled"`
+	// Keep is how many backups to retain, zero keeps them all
+	Keep int `json:"keep"`
+	// Dir defaults to a backups directory next to the data file
+	Dir string `json:"dir"`
+}
+
+// NotifyConfig configures the notify command
+type NotifyConfig struct {
+	DueWithin  task.Duration `json:"dueWithin"`
+	StaleAfter task.Duration `json:"staleAfter"`
+	Interval   task.Duration `json:"interval"`
+}
+
+// DefaultConfig returns the configuration used when no file exists
+func DefaultConfig() Config {
+	return Config{
+		Notify: NotifyConfig{
+			DueWithin:  task.Duration(time.Hour),
+			StaleAfter: task.Duration(3 * 24 * time.Hour),
+			Interval:   task.Duration(5 * time.Minute),
+		},
+		Backup: BackupConfig{
+			Keep: 10,
+		},
+		Audit: AuditConfig{
+			Enabled: true,
+		},
+		Confirm: ConfirmConfig{
+			Delete: true,
+		},
+		Remote: RemoteConfig{
+			Timeout: task.Duration(10 * time.Second),
+			Retries: 3,
+		},
+		Webhooks: WebhookConfig{
+			Retries: 3,
+		},
+		Server: ServerConfig{
+			MaxBodyBytes: 1 << 20,
+			RateLimit:    20,
+			RateBurst:    40,
+		},
+	}
+}
+
+// Thresholds converts the notify settings into domain alert thresholds
+func (n NotifyConfig) Thresholds() task.AlertThresholds {
+	return task.AlertThresholds{
+		DueWithin:  time.Duration(n.DueWithin),
+		StaleAfter: time.Duration(n.StaleAfter),
+	}
+}
+
+// ResolveDataFile picks the task file of one invocation: the --file flag,
+// then $TASK_CLI_FILE, then dataFile in the config, then tasks.json. It
+// reports whether the file was given explicitly, by flag or environment,
+// in which case it also takes precedence over a configured remote store.
+func ResolveDataFile(flag string, config Config) (string, bool) {
+	if flag != "" {
+		return flag, true
+	}
+	if path := os.Getenv(DataFileEnvVar); path != "" {
+		return path, true
+	}
+	if config.DataFile != "" {
+		return config.DataFile, false
+	}
+	return defaultDataFile, false
+}
+
+// SidecarFile names a file kept next to the data file, so that alternate
+// stores get their own projects, schedules and audit log, e.g. kind
+// "audit" and ext ".jsonl" turn sprint.json into sprint.audit.jsonl
+func SidecarFile(dataFile, kind, ext string) string {
+	base := strings.TrimSuffix(dataFile, filepath.Ext(dataFile))
+	return base + "." + kind + ext
+}
+
+// DefaultConfigPath returns $TASK_CLI_CONFIG or the per-user config location
+func DefaultConfigPath() (string, error) {
+	if path := os.Getenv(ConfigEnvVar); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+
+	return filepath.Join(dir, "task-cli", "config.json"), nil
+}
+
+// LoadConfig reads the configuration file, falling back to defaults for
+// missing files and missing keys
+func LoadConfig(path string) (Config, error) {
+	config := DefaultConfig()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	// The daemon waits this long between checks
+	if config.Notify.Interval <= 0 {
+		return config, fmt.Errorf("invalid config %s: notify.interval must be positive, not %s", path, time.Duration(config.Notify.Interval))
+	}
+	// A delivery is tried once more than this, so below zero nothing is sent
+	if config.Webhooks.Retries < 0 {
+		return config, fmt.Errorf("invalid config %s: webhooks.retries must be zero or more, not %d", path, config.Webhooks.Retries)
+	}
+
+	// No shell expands the paths of a config file
+	for _, path := range []*string{
+		&config.DataFile, &config.TodoTxt.File, &config.EventLog.File,
+		&config.Bolt.File, &config.Audit.File, &config.Backup.Dir,
+	} {
+		*path = ExpandPath(*path)
+	}
+	for i, script := range config.Hooks.Scripts {
+		config.Hooks.Scripts[i] = ExpandPath(script)
+	}
+	return config, nil
+}
+
+// SetConfigKey sets one top-level key of the config file to value, creating
+// the file if needed. Other keys are kept as written, so the file is not
+// filled with defaults the user never chose.
+func SetConfigKey(path, key string, value any) error {
+	file := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	file[key] = encoded
+	data, err = json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := replaceFile(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
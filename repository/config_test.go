@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLoadConfig tests reading user preferences with defaults
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing file uses defaults", func(t *testing.T) {
+		config, err := LoadConfig(filepath.Join(dir, "missing.json"))
+		if err != nil {
+			t.Fatalf("LoadConfig() unexpected error = %v", err)
+		}
+		if config.Notify.DueWithin != DefaultConfig().Notify.DueWithin {
+			t.Errorf("LoadConfig() DueWithin = %v, want default", config.Notify.DueWithin)
+		}
+	})
+
+	t.Run("partial file keeps other defaults", func(t *testing.T) {
+		path := filepath.Join(dir, "config.json")
+		if err := os.WriteFile(path, []byte(`{"notify": {"staleAfter": "7d"}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig() unexpected error = %v", err)
+		}
+		if time.Duration(config.Notify.StaleAfter) != 7*24*time.Hour {
+			t.Errorf("StaleAfter = %v, want 168h", time.Duration(config.Notify.StaleAfter))
+		}
+		if config.Notify.DueWithin != DefaultConfig().Notify.DueWithin {
+			t.Errorf("DueWithin should keep its default")
+		}
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		path := filepath.Join(dir, "bad.json")
+		if err := os.WriteFile(path, []byte(`{"notify": {"dueWithin": "soon"}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig() should reject invalid durations")
+		}
+	})
+
+	t.Run("interval must be positive", func(t *testing.T) {
+		path := filepath.Join(dir, "zero.json")
+		if err := os.WriteFile(path, []byte(`{"notify": {"interval": "0s"}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "notify.interval must be positive") {
+			t.Errorf("LoadConfig() error = %v, want notify.interval rejected", err)
+		}
+	})
+
+	t.Run("webhook retries must not be negative", func(t *testing.T) {
+		path := filepath.Join(dir, "retries.json")
+		if err := os.WriteFile(path, []byte(`{"webhooks": {"retries": -1}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "webhooks.retries must be zero or more") {
+			t.Errorf("LoadConfig() error = %v, want webhooks.retries rejected", err)
+		}
+	})
+}
+
+// TestDefaultConfigPath tests the environment override
+func TestDefaultConfigPath(t *testing.T) {
+	t.Setenv(ConfigEnvVar, "/tmp/custom.json")
+
+	path, err := DefaultConfigPath()
+	if err != nil || path != "/tmp/custom.json" {
+		t.Errorf("DefaultConfigPath() = %q, %v; want /tmp/custom.json", path, err)
+	}
+}
+
+func TestResolveDataFile(t *testing.T) {
+	config := Config{DataFile: "configured.json"}
+
+	t.Setenv(DataFileEnvVar, "")
+	if file, explicit := ResolveDataFile("", Config{}); file != "tasks.json" || explicit {
+		t.Errorf("default = %q, %v; want tasks.json", file, explicit)
+	}
+	if file, explicit := ResolveDataFile("", config); file != "configured.json" || explicit {
+		t.Errorf("config = %q, %v; want configured.json", file, explicit)
+	}
+
+	t.Setenv(DataFileEnvVar, "env.json")
+	if file, explicit := ResolveDataFile("", config); file != "env.json" || !explicit {
+		t.Errorf("env = %q, %v; want env.json, explicit", file, explicit)
+	}
+	if file, explicit := ResolveDataFile("flag.json", config); file != "flag.json" || !explicit {
+		t.Errorf("flag = %q, %v; want flag.json, explicit", file, explicit)
+	}
+}
+
+func TestSidecarFile(t *testing.T) {
+	if got := SidecarFile("data/sprint.json", "audit", ".jsonl"); got != "data/sprint.audit.jsonl" {
+		t.Errorf("SidecarFile() = %q, want data/sprint.audit.jsonl", got)
+	}
+	if got := SidecarFile("tasks.json", "projects", ".json"); got != "tasks.projects.json" {
+		t.Errorf("SidecarFile() = %q, want tasks.projects.json", got)
+	}
+}
+
+// TestSetConfigKey tests changing one key while keeping the rest as written
+func TestSetConfigKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "task-cli", "config.json")
+	if err := SetConfigKey(path, "aliases", map[string]string{"wip": "list in-progress"}); err != nil {
+		t.Fatalf("SetConfigKey() on a missing file: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"user": "ana", "aliases": {}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetConfigKey(path, "aliases", map[string]string{"wip": "list in-progress"}); err != nil {
+		t.Fatalf("SetConfigKey(): %v", err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig(): %v", err)
+	}
+	if config.User != "ana" || config.Aliases["wip"] != "list in-progress" {
+		t.Errorf("config = user %q, aliases %v; want ana and the wip alias", config.User, config.Aliases)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "notify") {
+		t.Errorf("config file = %s, want no defaults written", data)
+	}
+}
@@ -0,0 +1,10 @@
+// Package repository stores tasks: in a JSON file by default, or in a
+// todo.txt file, an event log, a bbolt database, an S3 bucket, a WebDAV
+// server or a remote task server. OpenRepository picks the store for a
+// location.
+//
+// Stores implement TaskRepository, which loads and saves the whole list, or
+// TaskStore, which gets, puts and deletes single tasks. The sidecar files of
+// projects, milestones, sprints, views, snapshots, habits and the audit log
+// are stored here too, along with the config file naming them.
+package repository
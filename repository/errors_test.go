@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskError tests that details and causes keep the error recognizable
+func TestTaskError(t *testing.T) {
+	err := task.ErrTaskNotFound.Withf("%d", 9)
+	if got, want := err.Error(), "Task not found: 9"; got != want {
+		t.Errorf("Withf() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, task.ErrTaskNotFound) || errors.Is(err, task.ErrInvalidID) {
+		t.Errorf("errors.Is() does not match on the code alone")
+	}
+	if err.Hint != task.ErrTaskNotFound.Hint {
+		t.Errorf("Withf() hint = %q, want %q", err.Hint, task.ErrTaskNotFound.Hint)
+	}
+
+	cause := errors.New("disk full")
+	wrapped := fmt.Errorf("failed to save tasks: %w", task.ErrStorage.Wrap(cause))
+	if !errors.Is(wrapped, cause) || !errors.Is(wrapped, task.ErrStorage) {
+		t.Errorf("errors.Is() lost the cause or the code of %v", wrapped)
+	}
+
+	conflict := StorageError("failed to save tasks", fmt.Errorf("commit: %w", task.ErrConflict.Withf("task 2")))
+	if !errors.Is(conflict, task.ErrConflict) || errors.Is(conflict, task.ErrStorage) {
+		t.Errorf("storageError() = %v, want it to keep %s", conflict, task.ErrConflict.Code)
+	}
+	var value task.TaskError
+	var pointer *task.TaskError
+	if !errors.As(conflict, &value) || !errors.As(conflict, &pointer) || pointer.Code != task.ErrConflict.Code {
+		t.Errorf("errors.As() did not find the TaskError in %v", conflict)
+	}
+}
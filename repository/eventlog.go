@@ -0,0 +1,389 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Event Log Repository Implementation (Adapter)
+//
+// Stores tasks as an append-only log of changes, one JSON event per line:
+//
+//	{"seq":1,"time":"2024-01-01T12:00:00Z","type":"added","id":1,"task":{...}}
+//	{"seq":2,"time":"2024-01-01T12:05:00Z","type":"deleted","id":1}
+//
+// Load replays the log. Save appends only the changes since the tasks were
+// loaded, in a single write, so writes stay small however large the store
+// grows and concurrent processes changing different tasks do not overwrite
+// each other. Once the log holds many more events than tasks it is
+// compacted into one "added" event per task, dropping older history.
+
+// defaultCompactAfter is how many events a log may hold before compaction
+// is considered
+const defaultCompactAfter = 1000
+
+// LogEvent is one line of an event log store
+type LogEvent struct {
+	Seq  int             `json:"seq"`
+	Time time.Time       `json:"time"`
+	Type task.ChangeType `json:"type"`
+	ID   int             `json:"id"`
+	// Task is the task after the change, nil for deletions
+	Task *task.Task `json:"task,omitempty"`
+}
+
+type EventLogRepository struct {
+	filename     string
+	compactAfter int
+
+	mu sync.Mutex
+	// base is the state last loaded or saved, which Save diffs against
+	base []task.Task
+}
+
+func NewEventLogRepository(filename string) *EventLogRepository {
+	return &EventLogRepository{filename: filename, compactAfter: defaultCompactAfter}
+}
+
+// WithCompactAfter sets how many events the log may hold before it is
+// compacted; compaction also waits until events outnumber tasks two to one
+func (r *EventLogRepository) WithCompactAfter(events int) *EventLogRepository {
+	r.compactAfter = events
+	return r
+}
+
+// Lock takes the lock other task-cli processes writing the log wait for
+func (r *EventLogRepository) Lock(ctx context.Context) (func(), error) {
+	return lockStore(ctx, r.filename)
+}
+
+func (r *EventLogRepository) StoreVersion(ctx context.Context) (string, error) {
+	return fileVersion(r.filename)
+}
+
+func (r *EventLogRepository) Load(ctx context.Context) ([]task.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events, err := r.events()
+	if err != nil {
+		return nil, err
+	}
+	tasks := replayEvents(events)
+	r.base = slices.Clone(tasks)
+	return tasks, nil
+}
+
+func (r *EventLogRepository) Save(ctx context.Context, tasks []task.Task) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events, complete, size, err := r.read()
+	if err != nil {
+		return err
+	}
+	base := r.base
+	if base == nil {
+		base = replayEvents(events)
+	}
+
+	changes := task.DiffTasks(base, tasks)
+	if len(changes) == 0 {
+		return nil
+	}
+	stored := replayEvents(events)
+	if err := checkRevisions(changes, task.NewTaskIndex(stored).Find); err != nil {
+		return err
+	}
+
+	seq := 0
+	if len(events) > 0 {
+		seq = events[len(events)-1].Seq
+	}
+	now := time.Now()
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, change := range changes {
+		seq++
+		event := LogEvent{Seq: seq, Time: now, Type: change.Type, ID: change.Task.ID}
+		if change.Type != task.ChangeDeleted {
+			event.Task = &change.Task
+		}
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to marshal tasks: %w", err)
+		}
+	}
+
+	// One write per save keeps concurrent appends from interleaving
+	f, err := os.OpenFile(r.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	// Drop the remains of an append cut short so the new events start on
+	// a line of their own, unless another process appended meanwhile
+	if info, err := f.Stat(); err == nil && complete < size && info.Size() == size {
+		if err := f.Truncate(complete); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	// Other processes may have appended since the tasks were loaded
+	current := applyChanges(stored, changes)
+	r.base = slices.Clone(current)
+
+	if total := len(events) + len(changes); total > r.compactAfter && total > 2*len(current) {
+		lastID := lastEventID(events)
+		for _, change := range changes {
+			lastID = max(lastID, change.Task.ID)
+		}
+		return r.compact(current, seq, lastID, now)
+	}
+	return nil
+}
+
+// GetNextID returns the ID after the highest the log ever recorded, deleted
+// tasks included, so that their IDs are not given again
+func (r *EventLogRepository) GetNextID(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	events, err := r.events()
+	r.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	return lastEventID(events) + 1, nil
+}
+
+// Compact rewrites the log as one event per task, holding the lock so
+// that no other process appends meanwhile
+func (r *EventLogRepository) Compact(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	unlock, err := r.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events, err := r.events()
+	if err != nil {
+		return err
+	}
+	seq := 0
+	if len(events) > 0 {
+		seq = events[len(events)-1].Seq
+	}
+	tasks := replayEvents(events)
+	r.base = slices.Clone(tasks)
+	return r.compact(tasks, seq, lastEventID(events), time.Now())
+}
+
+// History returns the versions of a task recorded in the log, oldest first
+func (r *EventLogRepository) History(ctx context.Context, id int) ([]TaskVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	events, err := r.events()
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []TaskVersion
+	for _, event := range events {
+		if event.ID != id {
+			continue
+		}
+		versions = append(versions, TaskVersion{
+			Revision: strconv.Itoa(event.Seq),
+			Time:     event.Time,
+			Message:  fmt.Sprintf("%s #%d", event.Type, event.ID),
+			Task:     event.Task,
+		})
+	}
+	return versions, nil
+}
+
+// compact replaces the log with one "added" event per task, numbered after
+// seq, through a temporary file so that readers never see a partial log.
+// When the task with lastID, the highest ID ever given, was deleted, its
+// "deleted" event is kept so that GetNextID does not give the ID again.
+func (r *EventLogRepository) compact(tasks []task.Task, seq, lastID int, now time.Time) error {
+	tmp, err := os.CreateTemp(filepath.Dir(r.filename), "."+filepath.Base(r.filename)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to compact event log: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(w)
+	for i := range tasks {
+		seq++
+		event := LogEvent{Seq: seq, Time: now, Type: task.ChangeAdded, ID: tasks[i].ID, Task: &tasks[i]}
+		if err := encoder.Encode(event); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to compact event log: %w", err)
+		}
+	}
+	if lastID > lastTaskID(tasks) {
+		event := LogEvent{Seq: seq + 1, Time: now, Type: task.ChangeDeleted, ID: lastID}
+		if err := encoder.Encode(event); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to compact event log: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to compact event log: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to compact event log: %w", err)
+	}
+	if err := replaceFile(tmp.Name(), r.filename); err != nil {
+		return fmt.Errorf("failed to compact event log: %w", err)
+	}
+	return nil
+}
+
+// events reads the whole log. A last line cut short by a crash during an
+// append is ignored; any other unreadable line is an error.
+func (r *EventLogRepository) events() ([]LogEvent, error) {
+	events, _, _, err := r.read()
+	return events, err
+}
+
+// read returns the events of the log, the length of the file up to the end
+// of its last complete line, and the length of the whole file
+func (r *EventLogRepository) read() ([]LogEvent, int64, int64, error) {
+	data, err := os.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, 0, nil
+	}
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var events []LogEvent
+	lines := bytes.Split(data, []byte("\n"))
+	complete := int64(len(data))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var event LogEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			if i == len(lines)-1 {
+				complete -= int64(len(line))
+				break
+			}
+			return nil, 0, 0, fmt.Errorf("%s:%d: %w", r.filename, i+1, err)
+		}
+		events = append(events, event)
+	}
+	return events, complete, int64(len(data)), nil
+}
+
+// lastEventID returns the highest task ID in a log, that of deleted tasks
+// included
+func lastEventID(events []LogEvent) int {
+	lastID := 0
+	for _, event := range events {
+		lastID = max(lastID, event.ID)
+	}
+	return lastID
+}
+
+// lastTaskID returns the highest ID among tasks
+func lastTaskID(tasks []task.Task) int {
+	lastID := 0
+	for _, task := range tasks {
+		lastID = max(lastID, task.ID)
+	}
+	return lastID
+}
+
+// replayEvents rebuilds the tasks from a log, in the order they were
+// added. Updates to tasks missing from the log, such as tasks deleted by
+// another process meanwhile, add them back.
+func replayEvents(events []LogEvent) []task.Task {
+	type slot struct {
+		task  task.Task
+		alive bool
+	}
+	var slots []slot
+	position := make(map[int]int)
+
+	for _, event := range events {
+		i, ok := position[event.ID]
+		switch {
+		case event.Type == task.ChangeDeleted:
+			if ok {
+				slots[i].alive = false
+				delete(position, event.ID)
+			}
+		case event.Task == nil:
+		case ok:
+			slots[i].task = *event.Task
+		default:
+			position[event.ID] = len(slots)
+			slots = append(slots, slot{task: *event.Task, alive: true})
+		}
+	}
+
+	tasks := make([]task.Task, 0, len(position))
+	for _, slot := range slots {
+		if slot.alive {
+			tasks = append(tasks, slot.task)
+		}
+	}
+	return tasks
+}
+
+// applyChanges applies diffed changes to tasks, like replaying their events
+func applyChanges(tasks []task.Task, changes []task.TaskChange) []task.Task {
+	events := make([]LogEvent, 0, len(tasks)+len(changes))
+	for i := range tasks {
+		events = append(events, LogEvent{Type: task.ChangeAdded, ID: tasks[i].ID, Task: &tasks[i]})
+	}
+	for i := range changes {
+		events = append(events, LogEvent{Type: changes[i].Type, ID: changes[i].Task.ID, Task: &changes[i].Task})
+	}
+	return replayEvents(events)
+}
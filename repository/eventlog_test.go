@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+func newEventLogTestRepo(t *testing.T) (*EventLogRepository, string) {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "tasks.jsonl")
+	return NewEventLogRepository(filename), filename
+}
+
+func countLines(t *testing.T, filename string) int {
+	t.Helper()
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	return bytes.Count(data, []byte("\n"))
+}
+
+// TestEventLogRepository_RoundTrip tests that saves append only changes
+// and loads replay them
+func TestEventLogRepository_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repo, filename := newEventLogTestRepo(t)
+
+	if tasks, err := repo.Load(ctx); err != nil || len(tasks) != 0 {
+		t.Fatalf("Load of missing log = %v, %v; want no tasks", tasks, err)
+	}
+	tasks := tasktest.FixedTasks(t)
+	if err := repo.Save(ctx, tasks); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got := countLines(t, filename); got != 3 {
+		t.Errorf("log has %d events after the first save, want 3", got)
+	}
+
+	tasks[0].Description = "Buy bread"
+	tasks = tasks[:2]
+	if err := repo.Save(ctx, tasks); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got := countLines(t, filename); got != 5 {
+		t.Errorf("log has %d events after an update and a delete, want 5", got)
+	}
+
+	loaded, err := NewEventLogRepository(filename).Load(ctx)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Description != "Buy bread" || loaded[1].ID != 2 {
+		t.Errorf("Load = %+v, want Buy bread and task 2", loaded)
+	}
+	if id, err := repo.GetNextID(ctx); err != nil || id != 4 {
+		t.Errorf("GetNextID = %d, %v; want 4, after the deleted task", id, err)
+	}
+
+	// Saving what was loaded appends nothing
+	if err := repo.Save(ctx, loaded); err != nil || countLines(t, filename) != 5 {
+		t.Errorf("unchanged Save = %v, log has %d events; want 5", err, countLines(t, filename))
+	}
+}
+
+// TestEventLogRepository_Concurrent tests that two processes changing
+// different tasks keep each other's changes
+func TestEventLogRepository_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	first, filename := newEventLogTestRepo(t)
+	if err := first.Save(ctx, tasktest.FixedTasks(t)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	second := NewEventLogRepository(filename)
+
+	a, _ := first.Load(ctx)
+	b, _ := second.Load(ctx)
+	a[0].Description = "Buy bread"
+	b[2].Description = "Call dad"
+	if err := first.Save(ctx, a); err != nil {
+		t.Fatalf("first Save: %v", err)
+	}
+	if err := second.Save(ctx, b); err != nil {
+		t.Fatalf("second Save: %v", err)
+	}
+
+	loaded, _ := NewEventLogRepository(filename).Load(ctx)
+	if len(loaded) != 3 || loaded[0].Description != "Buy bread" || loaded[2].Description != "Call dad" {
+		t.Errorf("Load = %+v, want both changes", loaded)
+	}
+}
+
+// TestEventLogRepository_TornLine tests recovering from an append cut short
+func TestEventLogRepository_TornLine(t *testing.T) {
+	ctx := context.Background()
+	repo, filename := newEventLogTestRepo(t)
+	if err := repo.Save(ctx, tasktest.FixedTasks(t)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	f.WriteString(`{"seq":4,"type":"added","id":4,"task":{"id":`)
+	f.Close()
+
+	fresh := NewEventLogRepository(filename)
+	tasks, err := fresh.Load(ctx)
+	if err != nil || len(tasks) != 3 {
+		t.Fatalf("Load with torn line = %d tasks, %v; want 3", len(tasks), err)
+	}
+	tasks = append(tasks, *tasktest.NewTaskBuilder().WithID(4).WithDescription("Plan trip").BuildValid(t))
+	if err := fresh.Save(ctx, tasks); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got := countLines(t, filename); got != 4 {
+		t.Errorf("log has %d lines, want the torn line replaced", got)
+	}
+	if loaded, err := NewEventLogRepository(filename).Load(ctx); err != nil || len(loaded) != 4 {
+		t.Errorf("Load = %d tasks, %v; want 4", len(loaded), err)
+	}
+
+	// A broken line in the middle is corruption, not a torn append
+	data, _ := os.ReadFile(filename)
+	os.WriteFile(filename, append([]byte("{broken\n"), data...), 0o600)
+	if _, err := NewEventLogRepository(filename).Load(ctx); err == nil {
+		t.Error("Load of a corrupt log succeeded, want an error")
+	}
+}
+
+// TestEventLogRepository_Compaction tests rewriting a long log
+func TestEventLogRepository_Compaction(t *testing.T) {
+	ctx := context.Background()
+	repo, filename := newEventLogTestRepo(t)
+	repo.WithCompactAfter(10)
+
+	tasks := tasktest.FixedTasks(t)
+	for i := range 4 {
+		tasks[0].Description = "Buy groceries " + string(rune('a'+i))
+		if err := repo.Save(ctx, tasks); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if got := countLines(t, filename); got != 6 {
+		t.Fatalf("log has %d events, want 6 before compaction", got)
+	}
+	for i := range 5 {
+		tasks[1].Description = "Write report " + string(rune('a'+i))
+		if err := repo.Save(ctx, tasks); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if got := countLines(t, filename); got != 3 {
+		t.Errorf("log has %d events after compaction, want 3", got)
+	}
+
+	loaded, _ := NewEventLogRepository(filename).Load(ctx)
+	if len(loaded) != 3 || loaded[0].Description != "Buy groceries d" || loaded[1].Description != "Write report e" {
+		t.Errorf("Load after compaction = %+v", loaded)
+	}
+	if id, _ := repo.GetNextID(ctx); id != 4 {
+		t.Errorf("GetNextID after compaction = %d, want 4", id)
+	}
+}
+
+// TestEventLogRepository_GetNextID tests that IDs of deleted tasks are not
+// given again, even once the log is compacted
+func TestEventLogRepository_GetNextID(t *testing.T) {
+	ctx := context.Background()
+	repo, filename := newEventLogTestRepo(t)
+
+	tasks := tasktest.FixedTasks(t)
+	if err := repo.Save(ctx, tasks); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := repo.Save(ctx, tasks[:2]); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if id, err := repo.GetNextID(ctx); err != nil || id != 4 {
+		t.Errorf("GetNextID after deleting task 3 = %d, %v; want 4", id, err)
+	}
+
+	if err := repo.Compact(ctx); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if got := countLines(t, filename); got != 3 {
+		t.Errorf("log has %d events after compaction, want 2 tasks and the deletion", got)
+	}
+	fresh := NewEventLogRepository(filename)
+	if id, err := fresh.GetNextID(ctx); err != nil || id != 4 {
+		t.Errorf("GetNextID after compaction = %d, %v; want 4", id, err)
+	}
+	if loaded, err := fresh.Load(ctx); err != nil || len(loaded) != 2 {
+		t.Errorf("Load after compaction = %d tasks, %v; want 2", len(loaded), err)
+	}
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Data file format
+//
+// Version 1 files are a bare JSON array of tasks. Version 2 wraps the array
+// in an envelope carrying the schema version and a checksum of the tasks so
+// that truncated or hand-mangled files are detected on load. Version 3 adds
+// lastId, the highest ID ever given to a task, so that the IDs of deleted
+// tasks are not handed out again. Older files start from their highest
+// task ID and are upgraded by the next save.
+
+// CurrentSchemaVersion is the data file version written by Save
+const CurrentSchemaVersion = 3
+
+var (
+	ErrChecksumMismatch  = errors.New("data file checksum mismatch, run 'task-cli doctor'")
+	ErrUnsupportedSchema = errors.New("data file was written by a newer version of task-cli")
+)
+
+// StoreHeader describes the envelope of a decoded data file
+type StoreHeader struct {
+	SchemaVersion int
+	// LastID is the highest ID ever given to a task, 0 before version 3
+	LastID   int
+	Checksum string
+	// ChecksumValid is false when the stored checksum does not match the tasks
+	ChecksumValid bool
+}
+
+// encodeStore serializes tasks into the current data file format
+func encodeStore(tasks []task.Task) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeStore(&buf, tasks, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeStore streams tasks to w in the current data file format, one task
+// at a time, so that large stores are never held as a single byte slice.
+// The checksum follows the tasks since it is only known once they are all
+// written; readers do not depend on the order of the fields, but lastId
+// comes first so that it can be read without the tasks. The stored lastId is
+// the higher of lastID and the highest task ID.
+func writeStore(w io.Writer, tasks []task.Task, lastID int) error {
+	for _, task := range tasks {
+		lastID = max(lastID, task.ID)
+	}
+
+	// The checksum covers the compact array, as json.Marshal(tasks) would
+	// produce it
+	hash := sha256.New()
+	hash.Write([]byte("["))
+
+	fmt.Fprintf(w, "{\n  \"schemaVersion\": %d,\n  \"lastId\": %d,\n  \"tasks\": [", CurrentSchemaVersion, lastID)
+	var indented bytes.Buffer
+	for i, task := range tasks {
+		raw, err := json.Marshal(task)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			hash.Write([]byte(","))
+			io.WriteString(w, ",")
+		}
+		hash.Write(raw)
+
+		indented.Reset()
+		if err := json.Indent(&indented, raw, "    ", "  "); err != nil {
+			return err
+		}
+		io.WriteString(w, "\n    ")
+		if _, err := indented.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	hash.Write([]byte("]"))
+
+	if len(tasks) > 0 {
+		io.WriteString(w, "\n  ")
+	}
+	_, err := fmt.Fprintf(w, "],\n  \"checksum\": \"sha256:%s\"\n}\n", hex.EncodeToString(hash.Sum(nil)))
+	return err
+}
+
+// decodeStore parses any supported data file version without rejecting
+// checksum mismatches, leaving that decision to the caller
+func decodeStore(data []byte) ([]task.Task, StoreHeader, error) {
+	tasks := []task.Task{}
+	header, err := readStore(bytes.NewReader(data), func(task task.Task) bool {
+		tasks = append(tasks, task)
+		return true
+	})
+	if err != nil {
+		return nil, header, err
+	}
+	return tasks, header, nil
+}
+
+// readStore streams the tasks of any supported data file version to visit,
+// one at a time. Reading stops early when visit returns false, in which
+// case the checksum is not verified and ChecksumValid is left false.
+func readStore(r io.Reader, visit func(task.Task) bool) (StoreHeader, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	start, err := dec.Token()
+	if err != nil {
+		return StoreHeader{}, err
+	}
+
+	switch start {
+	case json.Delim('['):
+		header := StoreHeader{SchemaVersion: 1, ChecksumValid: true}
+		_, err := readTaskArray(dec, nil, visit)
+		return header, err
+	case json.Delim('{'):
+	default:
+		return StoreHeader{}, errors.New("data file is neither a task list nor a task store")
+	}
+
+	var (
+		header   StoreHeader
+		hash     = sha256.New()
+		hasTasks bool
+		complete = true
+	)
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return header, err
+		}
+		switch key {
+		case "schemaVersion":
+			if err := dec.Decode(&header.SchemaVersion); err != nil {
+				return header, err
+			}
+			if header.SchemaVersion > CurrentSchemaVersion {
+				return header, fmt.Errorf("%w (schema version %d)", ErrUnsupportedSchema, header.SchemaVersion)
+			}
+		case "lastId":
+			if err := dec.Decode(&header.LastID); err != nil {
+				return header, err
+			}
+		case "checksum":
+			if err := dec.Decode(&header.Checksum); err != nil {
+				return header, err
+			}
+		case "tasks":
+			hasTasks = true
+			token, err := dec.Token()
+			if err != nil {
+				return header, err
+			}
+			if token == nil {
+				hash.Write([]byte("null"))
+				continue
+			}
+			if token != json.Delim('[') {
+				return header, errors.New("data file tasks are not a list")
+			}
+			hash.Write([]byte("["))
+			complete, err = readTaskArray(dec, hash, visit)
+			if err != nil {
+				return header, err
+			}
+			if !complete {
+				return header, nil
+			}
+			hash.Write([]byte("]"))
+		default:
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return header, err
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return header, err
+	}
+
+	if !hasTasks {
+		return header, errors.New("data file has no tasks field")
+	}
+	header.ChecksumValid = "sha256:"+hex.EncodeToString(hash.Sum(nil)) == header.Checksum
+	return header, nil
+}
+
+// readTaskArray decodes array elements up to and including the closing
+// bracket, writing each compacted element to hash when it is not nil. It
+// reports false when visit asked to stop before the end of the array.
+func readTaskArray(dec *json.Decoder, hash io.Writer, visit func(task.Task) bool) (bool, error) {
+	var compact bytes.Buffer
+	for i := 0; dec.More(); i++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return false, err
+		}
+		if hash != nil {
+			compact.Reset()
+			if err := json.Compact(&compact, raw); err != nil {
+				return false, err
+			}
+			if i > 0 {
+				hash.Write([]byte(","))
+			}
+			hash.Write(compact.Bytes())
+		}
+
+		var task task.Task
+		if err := json.Unmarshal(raw, &task); err != nil {
+			return false, err
+		}
+		if !visit(task) {
+			return false, nil
+		}
+	}
+	_, err := dec.Token()
+	return true, err
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Inspect reads the data file like Load but reports corruption, checksum
+// mismatches and old formats as issues instead of failing
+func (r *FileTaskRepository) Inspect(ctx context.Context) ([]task.Task, []task.Issue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	data, err := os.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return []task.Task{}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return []task.Task{}, nil, nil
+	}
+
+	tasks, header, err := decodeStore(data)
+	if errors.Is(err, ErrUnsupportedSchema) {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, []task.Issue{{
+			Kind:    task.IssueInvalidJSON,
+			Message: fmt.Sprintf("%s is not valid: %v (restore a backup with 'task-cli backup restore')", r.filename, err),
+		}}, nil
+	}
+
+	var issues []task.Issue
+	if header.SchemaVersion < CurrentSchemaVersion {
+		issues = append(issues, task.Issue{Kind: task.IssueLegacySchema, Fixable: true,
+			Message: fmt.Sprintf("schema version %d, current is %d", header.SchemaVersion, CurrentSchemaVersion)})
+	}
+	if !header.ChecksumValid {
+		issues = append(issues, task.Issue{Kind: task.IssueChecksumMismatch, Fixable: true,
+			Message: "stored checksum does not match the tasks, the file was edited or truncated"})
+	}
+
+	return tasks, issues, nil
+}
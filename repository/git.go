@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// ErrHistoryUnavailable is returned when the store does not keep history
+var ErrHistoryUnavailable = errors.New("task history requires git storage or an event log, enable git with \"git\": {\"enabled\": true}")
+
+// TaskHistorian is implemented by repositories that keep every saved version
+type TaskHistorian interface {
+	// History returns the versions of a task, oldest first. Versions where
+	// the task did not exist have a nil Task.
+	History(ctx context.Context, id int) ([]TaskVersion, error)
+}
+
+// TaskVersion is a task as it was after one recorded change
+type TaskVersion struct {
+	Revision string
+	Time     time.Time
+	Message  string
+	Task     *task.Task
+}
+
+// Git Repository Implementation (Adapter)
+//
+// GitTaskRepository keeps the data file in a git repository and commits
+// after every save, describing the change in the commit message.
+type GitTaskRepository struct {
+	*FileTaskRepository
+	dir  string
+	name string
+
+	identityOnce sync.Once
+	identity     []string
+}
+
+// NewGitTaskRepository wraps a file repository with git auto-commits. The
+// data file's directory becomes a git repository on first save unless it
+// already is the top level of one; an enclosing work tree is never used.
+func NewGitTaskRepository(file *FileTaskRepository) *GitTaskRepository {
+	dir, name := filepath.Split(file.filename)
+	if dir == "" {
+		dir = "."
+	}
+	return &GitTaskRepository{FileTaskRepository: file, dir: dir, name: name}
+}
+
+func (r *GitTaskRepository) Save(ctx context.Context, tasks []task.Task) error {
+	// A corrupt or missing file simply yields a generic commit message
+	previous, _ := r.FileTaskRepository.Load(ctx)
+
+	if err := r.FileTaskRepository.Save(ctx, tasks); err != nil {
+		return err
+	}
+
+	if err := r.ensureRepo(ctx); err != nil {
+		return fmt.Errorf("failed to initialize git repository: %w", err)
+	}
+	if _, err := r.Git(ctx, "add", "--", r.name); err != nil {
+		return fmt.Errorf("failed to stage tasks: %w", err)
+	}
+	if _, err := r.Git(ctx, "diff", "--cached", "--quiet", "--", r.name); err == nil {
+		return nil
+	}
+
+	message := CommitMessage(task.DiffTasks(previous, tasks))
+	if _, err := r.Git(ctx, "commit", "--quiet", "-m", message, "--", r.name); err != nil {
+		return fmt.Errorf("failed to commit tasks: %w", err)
+	}
+
+	return nil
+}
+
+// History walks the commits touching the data file
+func (r *GitTaskRepository) History(ctx context.Context, id int) ([]TaskVersion, error) {
+	if !r.isRepoRoot(ctx) {
+		return nil, nil
+	}
+	if _, err := r.Git(ctx, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return nil, nil
+	}
+
+	out, err := r.Git(ctx, "log", "--reverse", "--format=%H%x00%aI%x00%s", "--", r.name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git history: %w", err)
+	}
+
+	var versions []TaskVersion
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		hash, date, subject := fields[0], fields[1], fields[2]
+
+		data, err := r.Git(ctx, "show", hash+":./"+r.name)
+		if err != nil {
+			continue
+		}
+		tasks, _, err := decodeStore([]byte(data))
+		if err != nil {
+			continue
+		}
+
+		when, _ := time.Parse(time.RFC3339, date)
+		version := TaskVersion{Revision: hash, Time: when, Message: subject}
+		for i := range tasks {
+			if tasks[i].ID == id {
+				version.Task = &tasks[i]
+				break
+			}
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+// ensureRepo runs git init unless the data directory is already the top
+// level of a work tree. A repository further up, such as the project the
+// data file happens to sit in, is left alone rather than committed to.
+func (r *GitTaskRepository) ensureRepo(ctx context.Context) error {
+	if r.isRepoRoot(ctx) {
+		return nil
+	}
+	_, err := r.Git(ctx, "init", "--quiet")
+	return err
+}
+
+// isRepoRoot reports whether the data directory is the top level of a work tree
+func (r *GitTaskRepository) isRepoRoot(ctx context.Context) bool {
+	top, err := r.Git(ctx, "rev-parse", "--show-toplevel")
+	return err == nil && samePath(strings.TrimSpace(top), r.dir)
+}
+
+// samePath reports whether two paths name the same directory, resolving
+// symbolic links and the forward slashes git prints on Windows
+func samePath(a, b string) bool {
+	resolve := func(path string) string {
+		path, _ = filepath.Abs(filepath.FromSlash(path))
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			return real
+		}
+		return path
+	}
+	return resolve(a) == resolve(b)
+}
+
+// Git runs a git command in the data directory and returns its output
+func (r *GitTaskRepository) Git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.dir
+	r.identityOnce.Do(func() { r.identity = gitIdentityEnv(ctx, r.dir) })
+	cmd.Env = append(os.Environ(), r.identity...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return stdout.String(), nil
+}
+
+// gitIdentityEnv supplies a committer identity when the user has none
+// configured, so auto-commits work on fresh machines
+func gitIdentityEnv(ctx context.Context, dir string) []string {
+	if os.Getenv("GIT_AUTHOR_EMAIL") != "" {
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, "git", "config", "user.email")
+	cmd.Dir = dir
+	if out, err := cmd.Output(); err == nil && len(bytes.TrimSpace(out)) > 0 {
+		return nil
+	}
+	return []string{
+		"GIT_AUTHOR_NAME=task-cli", "GIT_AUTHOR_EMAIL=task-cli@localhost",
+		"GIT_COMMITTER_NAME=task-cli", "GIT_COMMITTER_EMAIL=task-cli@localhost",
+	}
+}
+
+// CommitMessage describes a set of changes the way the CLI command that made
+// them is named, e.g. "mark-done #12: Buy groceries"
+func CommitMessage(changes []task.TaskChange) string {
+	switch len(changes) {
+	case 0:
+		return "rewrite tasks"
+	case 1:
+		change := changes[0]
+		return fmt.Sprintf("%s #%d: %s", task.ChangeVerb(change), change.Task.ID, change.Task.Description)
+	}
+
+	ids := make([]string, len(changes))
+	for i, change := range changes {
+		ids[i] = fmt.Sprintf("#%d", change.Task.ID)
+	}
+	return fmt.Sprintf("change %d tasks: %s", len(changes), strings.Join(ids, ", "))
+}
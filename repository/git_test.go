@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCommitMessage tests commit subjects derived from task changes
+func TestCommitMessage(t *testing.T) {
+	item := *tasktest.NewTaskBuilder().WithID(12).WithDescription("Buy groceries").BuildInvalid()
+	done := item
+	done.Status = task.StatusDone
+	renamed := item
+	renamed.Description = "Buy milk"
+	other := *tasktest.NewTaskBuilder().WithID(13).WithDescription("Call mom").BuildInvalid()
+
+	tests := []struct {
+		name    string
+		changes []task.TaskChange
+		want    string
+	}{
+		{"add", []task.TaskChange{{Type: task.ChangeAdded, Task: item}}, "add #12: Buy groceries"},
+		{"delete", []task.TaskChange{{Type: task.ChangeDeleted, Task: item}}, "delete #12: Buy groceries"},
+		{"mark done", []task.TaskChange{{Type: task.ChangeUpdated, Task: done, Previous: &item}}, "mark-done #12: Buy groceries"},
+		{"update", []task.TaskChange{{Type: task.ChangeUpdated, Task: renamed, Previous: &item}}, "update #12: Buy milk"},
+		{"several", []task.TaskChange{{Type: task.ChangeAdded, Task: item}, {Type: task.ChangeDeleted, Task: other}}, "change 2 tasks: #12, #13"},
+		{"none", nil, "rewrite tasks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CommitMessage(tt.changes); got != tt.want {
+				t.Errorf("CommitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
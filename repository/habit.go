@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// HabitLog is the port for the completion history of schedules. It outlives
+// the tasks, so deleting a done task does not break its streak.
+type HabitLog interface {
+	Record(ctx context.Context, completions []task.HabitCompletion) error
+	Completions(ctx context.Context) ([]task.HabitCompletion, error)
+}
+
+// File Habit Log Implementation (Adapter)
+//
+// FileHabitLog appends one JSON completion per line, next to the data file.
+type FileHabitLog struct {
+	filename string
+}
+
+func NewFileHabitLog(filename string) *FileHabitLog {
+	return &FileHabitLog{filename: filename}
+}
+
+func (l *FileHabitLog) Record(ctx context.Context, completions []task.HabitCompletion) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	for _, completion := range completions {
+		if err := encoder.Encode(completion); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+func (l *FileHabitLog) Completions(ctx context.Context) ([]task.HabitCompletion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(l.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var completions []task.HabitCompletion
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var completion task.HabitCompletion
+		if err := json.Unmarshal(scanner.Bytes(), &completion); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", l.filename, line, err)
+		}
+		completions = append(completions, completion)
+	}
+	return completions, scanner.Err()
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+var (
+	ErrRemoteUnavailable  = errors.New("task server is unreachable, check your connection or the remote.url setting")
+	ErrRemoteUnauthorized = errors.New("task server rejected the token, check the remote.token setting")
+	ErrRemoteConflict     = errors.New("tasks were changed on the server by another client, try again")
+)
+
+// HTTP Repository Implementation (Adapter)
+//
+// HTTPTaskRepository stores tasks on a server started with
+// "task-cli serve --http". Saves are conditional on the version last loaded,
+// so concurrent edits from another machine fail instead of being overwritten.
+type HTTPTaskRepository struct {
+	baseURL string
+	token   string
+	client  *http.Client
+	retries int
+	backoff time.Duration
+
+	mu   sync.Mutex
+	etag string
+}
+
+// NewHTTPTaskRepository creates a repository for the server at baseURL.
+// An empty token sends no Authorization header.
+func NewHTTPTaskRepository(baseURL, token string) *HTTPTaskRepository {
+	return &HTTPTaskRepository{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		retries: 3,
+		backoff: 200 * time.Millisecond,
+	}
+}
+
+// WithTimeout limits how long a single request attempt may take
+// String names the server
+func (r *HTTPTaskRepository) String() string {
+	return r.baseURL
+}
+
+func (r *HTTPTaskRepository) WithTimeout(timeout time.Duration) *HTTPTaskRepository {
+	r.client.Timeout = timeout
+	return r
+}
+
+// WithRetries sets how many times failed requests are retried, doubling the
+// wait after each attempt
+func (r *HTTPTaskRepository) WithRetries(retries int, backoff time.Duration) *HTTPTaskRepository {
+	r.retries = retries
+	r.backoff = backoff
+	return r
+}
+
+func (r *HTTPTaskRepository) Load(ctx context.Context) ([]task.Task, error) {
+	resp, body, err := r.do(ctx, http.MethodGet, "/api/v1/tasks", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []task.Task
+	if err := json.Unmarshal(body, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode tasks from server: %w", err)
+	}
+	if tasks == nil {
+		tasks = []task.Task{}
+	}
+
+	r.mu.Lock()
+	r.etag = resp.Header.Get("ETag")
+	r.mu.Unlock()
+
+	return tasks, nil
+}
+
+func (r *HTTPTaskRepository) Save(ctx context.Context, tasks []task.Task) error {
+	if tasks == nil {
+		tasks = []task.Task{}
+	}
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tasks: %w", err)
+	}
+
+	r.mu.Lock()
+	header := http.Header{}
+	if r.etag != "" {
+		header.Set("If-Match", r.etag)
+	}
+	r.mu.Unlock()
+
+	resp, _, err := r.do(ctx, http.MethodPut, "/api/v1/tasks", data, header)
+	if errors.Is(err, ErrRemoteConflict) && r.landed(ctx, tasks) {
+		// A retried PUT conflicts with its own earlier, successful attempt
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.etag = resp.Header.Get("ETag")
+	r.mu.Unlock()
+
+	return nil
+}
+
+// landed reports whether the server already holds exactly these tasks
+func (r *HTTPTaskRepository) landed(ctx context.Context, tasks []task.Task) bool {
+	current, err := r.Load(ctx)
+	if err != nil {
+		return false
+	}
+	return TasksETag(current) == TasksETag(tasks)
+}
+
+func (r *HTTPTaskRepository) GetNextID(ctx context.Context) (int, error) {
+	_, body, err := r.do(ctx, http.MethodGet, "/api/v1/tasks/next-id", nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var next struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(body, &next); err != nil {
+		return 0, fmt.Errorf("failed to decode next ID from server: %w", err)
+	}
+	return next.ID, nil
+}
+
+// do sends a request, retrying network failures and server errors
+func (r *HTTPTaskRepository) do(ctx context.Context, method, path string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		if attempt > 0 {
+			wait := r.backoff << (attempt - 1)
+			select {
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		resp, respBody, err := r.send(ctx, method, path, body, header)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			lastErr = fmt.Errorf("%w (%s: %v)", ErrRemoteUnavailable, r.baseURL, err)
+			continue
+		}
+
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			lastErr = remoteError(resp, respBody)
+			continue
+		}
+		if resp.StatusCode >= 300 {
+			return nil, nil, remoteError(resp, respBody)
+		}
+
+		return resp, respBody, nil
+	}
+
+	return nil, nil, lastErr
+}
+
+func (r *HTTPTaskRepository) send(ctx context.Context, method, path string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	req.Header.Set("X-Task-Actor", task.ActorFrom(ctx))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, respBody, nil
+}
+
+// remoteError converts an error response into a domain or transport error
+func remoteError(resp *http.Response, body []byte) error {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrRemoteUnauthorized
+	case http.StatusPreconditionFailed:
+		return ErrRemoteConflict
+	}
+
+	var errBody HTTPErrorBody
+	if json.Unmarshal(body, &errBody) == nil && errBody.Error.Code != "" {
+		if resp.StatusCode < 500 {
+			return task.TaskError{Code: errBody.Error.Code, Message: errBody.Error.Message, Hint: errBody.Error.Hint}
+		}
+		return fmt.Errorf("task server error: %s", errBody.Error.Message)
+	}
+
+	return fmt.Errorf("task server returned %s", resp.Status)
+}
+
+// HTTPErrorBody is the JSON body of every error response
+type HTTPErrorBody struct {
+	Error HTTPError `json:"error"`
+}
+
+// HTTPError tells what went wrong, with the code of the TaskError when
+// there is one
+type HTTPError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Field is the path of the invalid body field, such as dueAt
+	Field string `json:"field,omitempty"`
+	// Hint tells the user what to do about the error
+	Hint string `json:"hint,omitempty"`
+}
+
+// TasksETag identifies a version of the task list
+func TasksETag(tasks []task.Task) string {
+	if tasks == nil {
+		tasks = []task.Task{}
+	}
+	data, _ := json.Marshal(tasks)
+	return `"` + checksum(data) + `"`
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// List Formats
+//
+// list --format quickfix prints one line per task in the form compilers use
+// for errors, so that editors can load open tasks into their quickfix list:
+//
+//	tasks.json:12:1: #2 [IN-PROGRESS] Write report
+//
+// The file and line point at the task in the data file when the store can
+// tell where it is written. Other stores print task-cli:<id>: instead, which
+// the same error formats read. Vim reads these lines with :cfile, or
+// :cexpr system('task-cli list --format quickfix'), using its default
+// errorformat.
+//
+// Any other format is a task template, see template.go, given inline or by
+// the name it has under formats in the config file.
+
+// TaskLocator is implemented by repositories that can tell where tasks are
+// written, for list --format quickfix
+type TaskLocator interface {
+	// LocateTasks returns the file holding the tasks and the line each task
+	// ID starts on
+	LocateTasks(ctx context.Context) (string, map[int]int, error)
+}
+
+func (r *FileTaskRepository) LocateTasks(ctx context.Context) (string, map[int]int, error) {
+	if err := ctx.Err(); err != nil {
+		return "", nil, err
+	}
+	data, err := os.ReadFile(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return r.filename, map[int]int{}, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	lines, err := jsonTaskLines(data)
+	if err != nil {
+		return "", nil, err
+	}
+	return r.filename, lines, nil
+}
+
+func (r *TodoTxtRepository) LocateTasks(ctx context.Context) (string, map[int]int, error) {
+	if err := ctx.Err(); err != nil {
+		return "", nil, err
+	}
+	f, err := os.Open(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return r.filename, map[int]int{}, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer f.Close()
+
+	// IDs are given as Load gives them, so number the same lines the same way
+	var tasks []task.Task
+	var lineNos []int
+	lineNo := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		lineNo++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		task, err := ParseTodoTxt(scanner.Text(), time.Time{})
+		if err != nil {
+			return "", nil, fmt.Errorf("%s:%d: %w", r.filename, lineNo, err)
+		}
+		tasks = append(tasks, task)
+		lineNos = append(lineNos, lineNo)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	lastID, err := r.lastID()
+	if err != nil {
+		return "", nil, err
+	}
+	assignTodoTxtIDs(tasks, lastID)
+	lines := make(map[int]int, len(tasks))
+	for i, task := range tasks {
+		lines[task.ID] = lineNos[i]
+	}
+	return r.filename, lines, nil
+}
+
+// jsonTaskLines maps the ID of each task in a data file, of any schema
+// version, to the line its object starts on
+func jsonTaskLines(data []byte) (map[int]int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	token, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tasks: %w", err)
+	}
+	// Version 1 files are the array itself, later ones hold it under tasks
+	if token == json.Delim('{') {
+		for {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, fmt.Errorf("failed to unmarshal tasks: %w", err)
+			}
+			if key == json.Delim('}') {
+				return map[int]int{}, nil
+			}
+			if key == "tasks" {
+				break
+			}
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal tasks: %w", err)
+			}
+		}
+		token, err = dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tasks: %w", err)
+		}
+	}
+	if token != json.Delim('[') {
+		return nil, fmt.Errorf("failed to unmarshal tasks: tasks are not an array")
+	}
+
+	lines := make(map[int]int)
+	line, counted := 1, 0
+	for dec.More() {
+		// The offset is just past the previous token, the task starts after
+		// the separator
+		start := int(dec.InputOffset())
+		for start < len(data) && strings.IndexByte(" \t\r\n,", data[start]) >= 0 {
+			start++
+		}
+		line += bytes.Count(data[counted:start], []byte("\n"))
+		counted = start
+
+		var task struct {
+			ID int `json:"id"`
+		}
+		if err := dec.Decode(&task); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tasks: %w", err)
+		}
+		if _, seen := lines[task.ID]; !seen {
+			lines[task.ID] = line
+		}
+	}
+	return lines, nil
+}
+
+// QuickfixLine renders a task as a quickfix entry, at line of file when it
+// is known
+func QuickfixLine(task task.Task, file string, line int) string {
+	message := fmt.Sprintf("#%d [%s] %s", task.ID, strings.ToUpper(string(task.Status)), task.Description)
+	if task.DueAt != nil {
+		message += " (due " + task.DueAt.Format("2006-01-02 15:04") + ")"
+	}
+	if file == "" || line == 0 {
+		return fmt.Sprintf("task-cli:%d: %s", task.ID, message)
+	}
+	return fmt.Sprintf("%s:%d:1: %s", file, line, message)
+}
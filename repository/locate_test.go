@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestFileTaskRepository_LocateTasks tests that each task is found on the
+// line its object starts in the data file
+func TestFileTaskRepository_LocateTasks(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	repo := NewFileTaskRepository(path)
+	if err := repo.Save(ctx, tasktest.FixedTasks(t)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	file, lines, err := repo.LocateTasks(ctx)
+	if err != nil || file != path {
+		t.Fatalf("LocateTasks() = %q, %v, want %q", file, err, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileLines := strings.Split(string(data), "\n")
+	for _, task := range tasktest.FixedTasks(t) {
+		line := lines[task.ID]
+		if line == 0 || strings.TrimSpace(fileLines[line-1]) != "{" ||
+			!strings.Contains(fileLines[line], fmt.Sprintf(`"id": %d,`, task.ID)) {
+			t.Errorf("task %d located on line %d, want the line opening it", task.ID, line)
+		}
+	}
+}
+
+// TestJSONTaskLines_BareArray tests locating tasks in a version 1 file
+func TestJSONTaskLines_BareArray(t *testing.T) {
+	lines, err := jsonTaskLines([]byte("[\n{\"id\": 4},\n\n{\"id\": 9}\n]\n"))
+	if err != nil || lines[4] != 2 || lines[9] != 4 {
+		t.Errorf("jsonTaskLines() = %v, %v; want 4 on line 2 and 9 on line 4", lines, err)
+	}
+}
+
+// TestTodoTxtRepository_LocateTasks tests that blank lines are counted
+func TestTodoTxtRepository_LocateTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.txt")
+	if err := os.WriteFile(path, []byte("Buy milk id:3\n\nCall mom id:7\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, lines, err := NewTodoTxtRepository(path).LocateTasks(context.Background())
+	if err != nil || lines[3] != 1 || lines[7] != 3 {
+		t.Errorf("LocateTasks() = %v, %v; want 3 on line 1 and 7 on line 3", lines, err)
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockRetryDelay is how long LockFile waits before trying again while
+// another holder has the lock
+const lockRetryDelay = 10 * time.Millisecond
+
+// Locker is implemented by repositories kept in local files, which other
+// task-cli processes may write at the same time
+type Locker interface {
+	// Lock waits until no one else holds the lock of the store, then takes
+	// it until unlock is called
+	Lock(ctx context.Context) (unlock func(), err error)
+}
+
+// FileLock is an advisory lock on a data file: flock on Unix and LockFileEx
+// on Windows. It is held on a ".lock" file next to the data file, since
+// saves replace the data file itself. Other processes and other FileLocks
+// of this process taking the same lock wait for it.
+type FileLock struct {
+	file *os.File
+}
+
+// LockFile takes the lock of the data file at path, waiting while someone
+// else holds it, until ctx is done
+func LockFile(ctx context.Context, path string) (*FileLock, error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			return &FileLock{file: f}, nil
+		}
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, ctx.Err()
+		case <-time.After(lockRetryDelay):
+		}
+	}
+}
+
+// Unlock releases the lock
+func (l *FileLock) Unlock() error {
+	unlockErr := unlockFile(l.file)
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	return unlockErr
+}
+
+// lockStore takes the lock of a data file for a Locker
+func lockStore(ctx context.Context, path string) (func(), error) {
+	lock, err := LockFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return func() { lock.Unlock() }, nil
+}
+
+// withLock runs write while holding the lock of repo, if it has one, so
+// that no other process writes between what write reads and what it saves
+func withLock(ctx context.Context, repo TaskRepository, write func() error) error {
+	locker, ok := RepositoryAs[Locker](repo)
+	if !ok {
+		return write()
+	}
+	unlock, err := locker.Lock(ctx)
+	if err != nil {
+		return StorageError("failed to lock tasks", err)
+	}
+	defer unlock()
+	return write()
+}
@@ -0,0 +1,27 @@
+//go:build aix
+
+package repository
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// tryLockFile takes an exclusive fcntl lock on f, as AIX has no flock,
+// reporting false when another process holds it. Unlike flock, the lock
+// belongs to the process, so on AIX it only keeps out other processes.
+func tryLockFile(f *os.File) (bool, error) {
+	lock := unix.Flock_t{Type: unix.F_WRLCK}
+	err := unix.FcntlFlock(f.Fd(), unix.F_SETLK, &lock)
+	if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EACCES) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func unlockFile(f *os.File) error {
+	lock := unix.Flock_t{Type: unix.F_UNLCK}
+	return unix.FcntlFlock(f.Fd(), unix.F_SETLK, &lock)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestLockFile tests that a second holder waits for the first to unlock
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	first, err := LockFile(context.Background(), path)
+	if err != nil {
+		t.Fatalf("LockFile: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := LockFile(ctx, path); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LockFile while held = %v, want it to wait until the deadline", err)
+	}
+
+	locked := make(chan error)
+	go func() {
+		second, err := LockFile(context.Background(), path)
+		if err == nil {
+			err = second.Unlock()
+		}
+		locked <- err
+	}()
+	time.Sleep(2 * lockRetryDelay)
+	if err := first.Unlock(); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if err := <-locked; err != nil {
+		t.Fatalf("LockFile after Unlock: %v", err)
+	}
+}
+
+// addProcessEnv names the store a helper process adds one task to
+const addProcessEnv = "TASK_CLI_TEST_ADD_TO"
+
+// TestAddProcess is not a test: it runs in the processes started by
+// TestConcurrentProcesses, adding one task the way a task-cli command does
+func TestAddProcess(t *testing.T) {
+	path := os.Getenv(addProcessEnv)
+	if path == "" {
+		t.Skip("only runs as a helper process")
+	}
+	var repo TaskRepository = NewFileTaskRepository(path)
+	if filepath.Ext(path) == ".jsonl" {
+		repo = NewEventLogRepository(path)
+	}
+	ctx := context.Background()
+	cache := NewCachingRepository(repo)
+	cache.Begin()
+	store := StoreFor(cache)
+	id, err := store.NextID(ctx)
+	if err != nil {
+		t.Fatalf("NextID: %v", err)
+	}
+	added, err := task.NewTask(id, fmt.Sprintf("added by %d", os.Getpid()))
+	if err != nil {
+		t.Fatalf("NewTask: %v", err)
+	}
+	if err := WriteChanges(ctx, store, []task.TaskChange{{Type: task.ChangeAdded, Task: *added}}); err != nil {
+		t.Fatalf("WriteChanges: %v", err)
+	}
+	if err := cache.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
+
+// TestConcurrentProcesses tests that tasks added by processes running at
+// the same time are all kept, each with its own ID
+func TestConcurrentProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts processes")
+	}
+	const processes = 8
+	for _, name := range []string{"tasks.json", "tasks.jsonl"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			commands := make([]*exec.Cmd, processes)
+			for i := range commands {
+				commands[i] = exec.Command(os.Args[0], "-test.run=^TestAddProcess$")
+				commands[i].Env = append(os.Environ(), addProcessEnv+"="+path)
+				if err := commands[i].Start(); err != nil {
+					t.Fatalf("start process: %v", err)
+				}
+			}
+			for _, cmd := range commands {
+				if err := cmd.Wait(); err != nil {
+					t.Errorf("process failed: %v", err)
+				}
+			}
+
+			var repo TaskRepository = NewFileTaskRepository(path)
+			if filepath.Ext(path) == ".jsonl" {
+				repo = NewEventLogRepository(path)
+			}
+			tasks, err := repo.Load(context.Background())
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			ids := make(map[int]bool)
+			for _, task := range tasks {
+				ids[task.ID] = true
+			}
+			if len(tasks) != processes || len(ids) != processes {
+				t.Errorf("stored %d tasks with %d IDs, want %d of each", len(tasks), len(ids), processes)
+			}
+		})
+	}
+}
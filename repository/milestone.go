@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// MilestoneRepository is the port for storing milestones
+type MilestoneRepository interface {
+	SaveMilestones(ctx context.Context, milestones []task.Milestone) error
+	LoadMilestones(ctx context.Context) ([]task.Milestone, error)
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// File Milestone Repository Implementation (Adapter)
+//
+// FileMilestoneRepository keeps milestones in a JSON file next to the data
+// file, like FileProjectRepository does for projects.
+type FileMilestoneRepository struct {
+	filename string
+}
+
+func NewFileMilestoneRepository(filename string) *FileMilestoneRepository {
+	return &FileMilestoneRepository{filename: filename}
+}
+
+func (r *FileMilestoneRepository) SaveMilestones(ctx context.Context, milestones []task.Milestone) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return writeJSONList(r.filename, milestones)
+}
+
+func (r *FileMilestoneRepository) LoadMilestones(ctx context.Context) ([]task.Milestone, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return readJSONList[task.Milestone](r.filename)
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Notify watches the data file and signals after every write, rename or
+// removal. The parent directory is watched so that editors replacing the
+// file atomically are still noticed.
+func (r *FileTaskRepository) Notify(ctx context.Context) (<-chan struct{}, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	target, err := filepath.Abs(r.filename)
+	if err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to resolve data file: %w", err)
+	}
+
+	if err := watcher.Add(filepath.Dir(target)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch data file: %w", err)
+	}
+
+	signals := make(chan struct{}, 1)
+	go func() {
+		defer close(signals)
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != target || event.Op == fsnotify.Chmod {
+					continue
+				}
+				// Coalesce bursts of events into a single pending signal
+				select {
+				case signals <- struct{}{}:
+				default:
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return signals, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedLocation is returned for store locations with unknown schemes
+var ErrUnsupportedLocation = errors.New("unsupported store location")
+
+// OpenRepository creates the repository for a store location: an http(s)
+// URL of a task server, an s3://bucket/key object, a file on a WebDAV server
+// as dav://host/path (davs:// for https), or a path (optionally file://) to
+// a data file. Paths ending in .txt are read as todo.txt files. Servers and
+// buckets are reached with the settings of config.
+func OpenRepository(location string, config Config) (TaskRepository, error) {
+	switch {
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		remote := config.Remote
+		return NewHTTPTaskRepository(location, remote.Token).
+			WithTimeout(time.Duration(remote.Timeout)).
+			WithRetries(remote.Retries, 200*time.Millisecond), nil
+	case strings.HasPrefix(location, "s3://"):
+		bucket, key, _ := strings.Cut(strings.TrimPrefix(location, "s3://"), "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("%w: %s has no bucket", ErrUnsupportedLocation, location)
+		}
+		s3 := config.S3
+		s3.Bucket, s3.Key = bucket, key
+		return NewS3TaskRepository(s3), nil
+	case strings.HasPrefix(location, "dav://"), strings.HasPrefix(location, "davs://"):
+		webdav := config.WebDAV
+		webdav.URL = webDAVURL(location)
+		return NewWebDAVTaskRepository(webdav), nil
+	case strings.HasPrefix(location, "file://"):
+		return openFileRepository(strings.TrimPrefix(location, "file://")), nil
+	case strings.Contains(location, "://"):
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLocation, location)
+	default:
+		return openFileRepository(location), nil
+	}
+}
+
+func openFileRepository(path string) TaskRepository {
+	if strings.HasSuffix(path, ".txt") {
+		return NewTodoTxtRepository(path)
+	}
+	return NewFileTaskRepository(path)
+}
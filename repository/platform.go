@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Platform Differences
+//
+// Most of task-cli behaves the same everywhere. What does not is kept here,
+// written against a GOOS string and injected functions rather than build
+// tags so that every branch runs in tests on any system. Only calls that
+// need the real system API are split by build tags: the file locks of
+// lock_unix.go, lock_aix.go and lock_windows.go, and the console calls of
+// enableVirtualTerminal and makeRaw in the cli package.
+
+// replaceAttempts and replaceDelay bound how long a save waits for another
+// process to close the data file on Windows
+const (
+	replaceAttempts = 10
+	replaceDelay    = 50 * time.Millisecond
+)
+
+// replaceFile moves a freshly written file over path
+func replaceFile(from, path string) error {
+	return replaceFileOn(runtime.GOOS, os.Rename, time.Sleep, from, path)
+}
+
+// replaceFileOn renames from over path. Windows refuses to replace a file
+// another process has open, such as a daemon or an editor reading the
+// task file, so there the rename is retried for a moment before giving up.
+func replaceFileOn(goos string, rename func(string, string) error, sleep func(time.Duration), from, path string) error {
+	attempts := 1
+	if goos == "windows" {
+		attempts = replaceAttempts
+	}
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = rename(from, path); err == nil {
+			return nil
+		}
+		if attempt < attempts {
+			sleep(time.Duration(attempt) * replaceDelay)
+		}
+	}
+	return err
+}
+
+// ExpandPath expands a leading ~ to the home directory and environment
+// variables in a path from the config file, where no shell has done it:
+// $VAR and ${VAR} everywhere, and %VAR% on Windows
+func ExpandPath(path string) string {
+	home, _ := os.UserHomeDir()
+	return expandPathOn(runtime.GOOS, path, home, os.Getenv)
+}
+
+func expandPathOn(goos, path, home string, getenv func(string) string) string {
+	if path == "" {
+		return path
+	}
+	if goos == "windows" {
+		path = expandWindowsVars(path, getenv)
+	}
+	path = os.Expand(path, getenv)
+
+	if home != "" && (path == "~" || strings.HasPrefix(path, "~/") ||
+		(goos == "windows" && strings.HasPrefix(path, `~\`))) {
+		path = home + path[1:]
+	}
+	return path
+}
+
+// expandWindowsVars replaces %VAR% with the value of VAR, leaving unknown
+// variables and lone percent signs as they are, like cmd.exe
+func expandWindowsVars(path string, getenv func(string) string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(path, '%')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(path[start+1:], '%')
+		if end < 0 {
+			break
+		}
+		end += start + 1
+		name := path[start+1 : end]
+		value := getenv(name)
+		if name == "" || value == "" {
+			b.WriteString(path[:end])
+			path = path[end:]
+			continue
+		}
+		b.WriteString(path[:start])
+		b.WriteString(value)
+		path = path[end+1:]
+	}
+	b.WriteString(path)
+	return b.String()
+}
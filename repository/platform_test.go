@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestReplaceFileOn tests that only Windows retries a rename that fails
+// while another process holds the file
+func TestReplaceFileOn(t *testing.T) {
+	errInUse := errors.New("the process cannot access the file")
+	for _, tt := range []struct {
+		goos     string
+		failures int
+		wantErr  bool
+		wantTry  int
+	}{
+		{"windows", 2, false, 3},
+		{"windows", replaceAttempts, true, replaceAttempts},
+		{"linux", 1, true, 1},
+		{"linux", 0, false, 1},
+	} {
+		tries := 0
+		var slept time.Duration
+		rename := func(from, to string) error {
+			tries++
+			if tries <= tt.failures {
+				return errInUse
+			}
+			return nil
+		}
+		err := replaceFileOn(tt.goos, rename, func(d time.Duration) { slept += d }, "tasks.json.tmp", "tasks.json")
+		if (err != nil) != tt.wantErr || tries != tt.wantTry {
+			t.Errorf("%s with %d failures: error = %v after %d tries, want error %v after %d",
+				tt.goos, tt.failures, err, tries, tt.wantErr, tt.wantTry)
+		}
+		if tt.goos == "linux" && slept != 0 {
+			t.Errorf("linux waited %v before giving up", slept)
+		}
+	}
+}
+
+// TestExpandPathOn tests home and environment expansion in config paths
+func TestExpandPathOn(t *testing.T) {
+	env := map[string]string{"APPDATA": `C:\Users\ana\AppData\Roaming`, "TASKS": "/srv/tasks"}
+	getenv := func(name string) string { return env[name] }
+	tests := []struct {
+		goos, path, want string
+	}{
+		{"linux", "~/tasks.json", "/home/ana/tasks.json"},
+		{"linux", "$TASKS/work.json", "/srv/tasks/work.json"},
+		{"linux", "~user/tasks.json", "~user/tasks.json"},
+		{"linux", "%APPDATA%/tasks.json", "%APPDATA%/tasks.json"},
+		{"windows", `%APPDATA%\task-cli\tasks.json`, `C:\Users\ana\AppData\Roaming\task-cli\tasks.json`},
+		{"windows", `~\tasks.json`, `/home/ana\tasks.json`},
+		{"windows", `100%%UNSET%\tasks.json`, `100%%UNSET%\tasks.json`},
+		{"windows", "", ""},
+	}
+	for _, tt := range tests {
+		if got := expandPathOn(tt.goos, tt.path, "/home/ana", getenv); got != tt.want {
+			t.Errorf("expandPathOn(%s, %q) = %q, want %q", tt.goos, tt.path, got, tt.want)
+		}
+	}
+}
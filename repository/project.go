@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// ProjectRepository is the port for storing projects
+type ProjectRepository interface {
+	SaveProjects(ctx context.Context, projects []task.Project) error
+	LoadProjects(ctx context.Context) ([]task.Project, error)
+}
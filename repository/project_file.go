@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// File Project Repository Implementation (Adapter)
+//
+// FileProjectRepository keeps projects in a JSON file next to the data file,
+// so projects work the same whichever backend stores the tasks.
+type FileProjectRepository struct {
+	filename string
+}
+
+func NewFileProjectRepository(filename string) *FileProjectRepository {
+	return &FileProjectRepository{filename: filename}
+}
+
+func (r *FileProjectRepository) SaveProjects(ctx context.Context, projects []task.Project) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return writeJSONList(r.filename, projects)
+}
+
+func (r *FileProjectRepository) LoadProjects(ctx context.Context) ([]task.Project, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return readJSONList[task.Project](r.filename)
+}
+
+// writeJSONList stores items as an indented JSON array
+func writeJSONList[T any](filename string, items []T) error {
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filename, err)
+	}
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// readJSONList loads a JSON array, or an empty list when the file is missing
+// or empty
+func readJSONList[T any](filename string) ([]T, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(data) == 0) {
+		return []T{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", filename, err)
+	}
+	return items, nil
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Repository Interface (Port)
+// Every operation takes a context so that slow backends can honor cancellation.
+type TaskRepository interface {
+	Save(ctx context.Context, tasks []task.Task) error
+	Load(ctx context.Context) ([]task.Task, error)
+	GetNextID(ctx context.Context) (int, error)
+}
+
+// IDSequencer is implemented by repositories that keep their own ID counter,
+// so that IDs of deleted tasks are not handed out again
+type IDSequencer interface {
+	NextSequenceID(ctx context.Context) (int, error)
+}
+
+// Page selects a window of matching tasks; a zero Limit means no limit
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+// PageLoader is implemented by repositories that can stop reading once a
+// page of matching tasks is complete. A nil match selects every task.
+type PageLoader interface {
+	LoadPage(ctx context.Context, page Page, match func(task.Task) bool) ([]task.Task, error)
+}
+
+// PageTasks applies a page to tasks already in memory
+func PageTasks(tasks []task.Task, page Page, match func(task.Task) bool) []task.Task {
+	selected := []task.Task{}
+	skipped := 0
+	for _, task := range tasks {
+		if match != nil && !match(task) {
+			continue
+		}
+		if skipped < page.Offset {
+			skipped++
+			continue
+		}
+		if page.Limit > 0 && len(selected) == page.Limit {
+			break
+		}
+		selected = append(selected, task)
+	}
+	return selected
+}
+
+// fileVersion describes a file by its size and modification time, which
+// every write changes, for StoreVersion
+func fileVersion(filename string) (string, error) {
+	info, err := os.Stat(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return "missing", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano()), nil
+}
+
+// File Repository Implementation (Adapter)
+type FileTaskRepository struct {
+	filename string
+	backups  *BackupManager
+}
+
+func NewFileTaskRepository(filename string) *FileTaskRepository {
+	return &FileTaskRepository{filename: filename}
+}
+
+// String names the data file
+func (r *FileTaskRepository) String() string {
+	return r.filename
+}
+
+// WithBackups copies the current file through the manager before every Save
+func (r *FileTaskRepository) WithBackups(backups *BackupManager) *FileTaskRepository {
+	r.backups = backups
+	return r
+}
+
+func (r *FileTaskRepository) Save(ctx context.Context, tasks []task.Task) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Stream into a temporary file next to the data file, then swap it in,
+	// so a failed write never leaves a half-written store behind
+	tmp, err := os.CreateTemp(filepath.Dir(r.filename), "."+filepath.Base(r.filename)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	// An unreadable file has no counter worth keeping, doctor reports it
+	lastID, _ := r.lastID(ctx)
+	w := bufio.NewWriter(tmp)
+	if err := writeStore(w, tasks, lastID); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to marshal tasks: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if r.backups != nil {
+		if _, err := r.backups.Create(); err != nil {
+			return fmt.Errorf("failed to back up tasks: %w", err)
+		}
+	}
+
+	if err := replaceFile(tmp.Name(), r.filename); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// Lock takes the lock other task-cli processes writing the file wait for
+func (r *FileTaskRepository) Lock(ctx context.Context) (func(), error) {
+	return lockStore(ctx, r.filename)
+}
+
+func (r *FileTaskRepository) StoreVersion(ctx context.Context) (string, error) {
+	return fileVersion(r.filename)
+}
+
+func (r *FileTaskRepository) Load(ctx context.Context) ([]task.Task, error) {
+	tasks := []task.Task{}
+	_, err := r.read(ctx, func(task task.Task) bool {
+		tasks = append(tasks, task)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// LoadPage decodes tasks only until the page is full, so listing the first
+// tasks of a large store does not parse all of it. The checksum of a file
+// read partially cannot be verified; Load and doctor still catch corruption.
+func (r *FileTaskRepository) LoadPage(ctx context.Context, page Page, match func(task.Task) bool) ([]task.Task, error) {
+	tasks := []task.Task{}
+	skipped := 0
+	_, err := r.read(ctx, func(task task.Task) bool {
+		if match != nil && !match(task) {
+			return true
+		}
+		if skipped < page.Offset {
+			skipped++
+			return true
+		}
+		tasks = append(tasks, task)
+		return page.Limit <= 0 || len(tasks) < page.Limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// read streams the tasks of the data file to visit, failing on checksum
+// mismatches unless visit stopped the read early. A missing or empty file
+// has a zero header.
+func (r *FileTaskRepository) read(ctx context.Context, visit func(task.Task) bool) (StoreHeader, error) {
+	if err := ctx.Err(); err != nil {
+		return StoreHeader{}, err
+	}
+
+	f, err := os.Open(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return StoreHeader{}, nil
+	}
+	if err != nil {
+		return StoreHeader{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer f.Close()
+
+	// Handle empty file
+	info, err := f.Stat()
+	if err != nil {
+		return StoreHeader{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	if info.Size() == 0 {
+		return StoreHeader{}, nil
+	}
+
+	complete := true
+	header, err := readStore(f, func(task task.Task) bool {
+		complete = visit(task)
+		return complete
+	})
+	if err != nil {
+		return header, fmt.Errorf("failed to unmarshal tasks: %w", err)
+	}
+	if complete && !header.ChecksumValid {
+		return header, ErrChecksumMismatch
+	}
+	return header, nil
+}
+
+// GetNextID returns the ID after the highest ever given, so IDs of deleted
+// tasks are never reused
+func (r *FileTaskRepository) GetNextID(ctx context.Context) (int, error) {
+	lastID, err := r.lastID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return lastID + 1, nil
+}
+
+func (r *FileTaskRepository) NextSequenceID(ctx context.Context) (int, error) {
+	return r.GetNextID(ctx)
+}
+
+// lastID returns the highest ID ever given to a task. Current files store
+// it ahead of the tasks, so only their header is read; older files are read
+// in full for their highest task ID.
+func (r *FileTaskRepository) lastID(ctx context.Context) (int, error) {
+	header, err := r.read(ctx, func(task.Task) bool { return false })
+	if err != nil {
+		return 0, err
+	}
+	if header.SchemaVersion == 0 || header.SchemaVersion >= 3 {
+		return header.LastID, nil
+	}
+
+	tasks, err := r.Load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	maxID := 0
+	for _, task := range tasks {
+		maxID = max(maxID, task.ID)
+	}
+	return maxID, nil
+}
@@ -0,0 +1,486 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestFileTaskRepository_SaveAndLoad tests persistence functionality
+func TestFileTaskRepository_SaveAndLoad(t *testing.T) {
+	// Create temporary file
+	tmpFile := "test_tasks.json"
+	defer os.Remove(tmpFile)
+
+	repo := NewFileTaskRepository(tmpFile)
+
+	t.Run("save and load empty task list", func(t *testing.T) {
+		emptyTasks := []task.Task{}
+
+		err := repo.Save(t.Context(), emptyTasks)
+		if err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+
+		loadedTasks, err := repo.Load(t.Context())
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+
+		if len(loadedTasks) != 0 {
+			t.Errorf("Load() returned %d tasks, want 0", len(loadedTasks))
+		}
+	})
+
+	t.Run("save and load single task", func(t *testing.T) {
+		item := tasktest.TodoTask(t)
+		tasks := []task.Task{*item}
+
+		err := repo.Save(t.Context(), tasks)
+		if err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+
+		// Verify file exists
+		if _, err := os.Stat(tmpFile); os.IsNotExist(err) {
+			t.Fatalf("Save() should create file")
+		}
+
+		loadedTasks, err := repo.Load(t.Context())
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+
+		if len(loadedTasks) != 1 {
+			t.Errorf("Load() returned %d tasks, want 1", len(loadedTasks))
+		}
+
+		tasktest.AssertTaskEquals(t, item, &loadedTasks[0])
+	})
+
+	t.Run("save and load multiple tasks", func(t *testing.T) {
+		tasks := tasktest.MixedStatusTasks(t)
+
+		err := repo.Save(t.Context(), tasks)
+		if err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+
+		loadedTasks, err := repo.Load(t.Context())
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+
+		tasktest.AssertTasksEqual(t, tasks, loadedTasks)
+	})
+
+	t.Run("overwrite existing file", func(t *testing.T) {
+		// Save initial tasks
+		initialTasks := tasktest.TaskSet(t, 2)
+		err := repo.Save(t.Context(), initialTasks)
+		if err != nil {
+			t.Fatalf("Initial save failed: %v", err)
+		}
+
+		// Overwrite with new tasks
+		newTasks := tasktest.TaskSet(t, 3)
+		err = repo.Save(t.Context(), newTasks)
+		if err != nil {
+			t.Fatalf("Overwrite save failed: %v", err)
+		}
+
+		// Verify only new tasks are present
+		loadedTasks, err := repo.Load(t.Context())
+		if err != nil {
+			t.Fatalf("Load after overwrite failed: %v", err)
+		}
+
+		if len(loadedTasks) != 3 {
+			t.Errorf("Load() after overwrite returned %d tasks, want 3", len(loadedTasks))
+		}
+	})
+}
+
+// TestFileTaskRepository_LoadNonExistentFile tests graceful handling of missing files
+func TestFileTaskRepository_LoadNonExistentFile(t *testing.T) {
+	repo := NewFileTaskRepository("non_existent_file.json")
+
+	tasks, err := repo.Load(t.Context())
+	if err != nil {
+		t.Errorf("Load() on non-existent file should not error, got: %v", err)
+	}
+
+	if len(tasks) != 0 {
+		t.Errorf("Load() on non-existent file should return empty slice, got %d tasks", len(tasks))
+	}
+}
+
+// TestFileTaskRepository_LoadEmptyFile tests handling of empty files
+func TestFileTaskRepository_LoadEmptyFile(t *testing.T) {
+	tmpFile := "empty_test.json"
+	defer os.Remove(tmpFile)
+
+	// Create empty file
+	err := os.WriteFile(tmpFile, []byte(""), 0o644)
+	if err != nil {
+		t.Fatalf("Failed to create empty test file: %v", err)
+	}
+
+	repo := NewFileTaskRepository(tmpFile)
+	tasks, err := repo.Load(t.Context())
+	if err != nil {
+		t.Errorf("Load() on empty file should not error, got: %v", err)
+	}
+
+	if len(tasks) != 0 {
+		t.Errorf("Load() on empty file should return empty slice, got %d tasks", len(tasks))
+	}
+}
+
+// TestFileTaskRepository_LoadInvalidJSON tests error handling for corrupted files
+func TestFileTaskRepository_LoadInvalidJSON(t *testing.T) {
+	tmpFile := "invalid_test.json"
+	defer os.Remove(tmpFile)
+
+	// Create file with invalid JSON
+	err := os.WriteFile(tmpFile, []byte("invalid json content"), 0o644)
+	if err != nil {
+		t.Fatalf("Failed to create invalid JSON test file: %v", err)
+	}
+
+	repo := NewFileTaskRepository(tmpFile)
+	_, err = repo.Load(t.Context())
+	if err == nil {
+		t.Errorf("Load() on invalid JSON should return error")
+	}
+}
+
+// TestFileTaskRepository_GetNextID tests ID generation
+func TestFileTaskRepository_GetNextID(t *testing.T) {
+	tmpFile := "test_nextid.json"
+	defer os.Remove(tmpFile)
+
+	repo := NewFileTaskRepository(tmpFile)
+
+	t.Run("empty repository returns ID 1", func(t *testing.T) {
+		id, err := repo.GetNextID(t.Context())
+		if err != nil {
+			t.Errorf("GetNextID() failed: %v", err)
+		}
+		if id != 1 {
+			t.Errorf("GetNextID() on empty repo = %v, want 1", id)
+		}
+	})
+
+	t.Run("returns max ID + 1", func(t *testing.T) {
+		// Add tasks with non-sequential IDs
+		tasks := []task.Task{
+			*tasktest.NewTaskBuilder().WithID(1).BuildValid(t),
+			*tasktest.NewTaskBuilder().WithID(5).BuildValid(t),
+			*tasktest.NewTaskBuilder().WithID(3).BuildValid(t),
+		}
+		err := repo.Save(t.Context(), tasks)
+		if err != nil {
+			t.Fatalf("Failed to save test tasks: %v", err)
+		}
+
+		id, err := repo.GetNextID(t.Context())
+		if err != nil {
+			t.Errorf("GetNextID() failed: %v", err)
+		}
+		if id != 6 {
+			t.Errorf("GetNextID() with max ID 5 = %v, want 6", id)
+		}
+	})
+
+	t.Run("handles single task", func(t *testing.T) {
+		tasks := []task.Task{*tasktest.NewTaskBuilder().WithID(42).BuildValid(t)}
+		err := repo.Save(t.Context(), tasks)
+		if err != nil {
+			t.Fatalf("Failed to save test task: %v", err)
+		}
+
+		id, err := repo.GetNextID(t.Context())
+		if err != nil {
+			t.Errorf("GetNextID() failed: %v", err)
+		}
+		if id != 43 {
+			t.Errorf("GetNextID() with single task ID 42 = %v, want 43", id)
+		}
+	})
+}
+
+// TestFileTaskRepository_FileFormat tests JSON format specifics
+func TestFileTaskRepository_FileFormat(t *testing.T) {
+	tmpFile := "format_test.json"
+	defer os.Remove(tmpFile)
+
+	repo := NewFileTaskRepository(tmpFile)
+	item := tasktest.TodoTask(t)
+	tasks := []task.Task{*item}
+
+	err := repo.Save(t.Context(), tasks)
+	if err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// Read raw file content
+	content, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to read saved file: %v", err)
+	}
+
+	// Verify it's properly formatted JSON
+	contentStr := string(content)
+	if !strings.Contains(contentStr, "\"id\"") {
+		t.Errorf("Saved JSON should contain id field")
+	}
+	if !strings.Contains(contentStr, "\"description\"") {
+		t.Errorf("Saved JSON should contain description field")
+	}
+	if !strings.Contains(contentStr, "\"status\"") {
+		t.Errorf("Saved JSON should contain status field")
+	}
+
+	// Verify it's indented (pretty-printed)
+	if !strings.Contains(contentStr, "\n") {
+		t.Errorf("Saved JSON should be pretty-printed with newlines")
+	}
+}
+
+// TestRepositoryInterface verifies both implementations satisfy the interface
+func TestRepositoryInterface(t *testing.T) {
+	implementations := []struct {
+		name    string
+		repo    TaskRepository
+		cleanup func()
+	}{
+		{
+			name:    "FileTaskRepository",
+			repo:    NewFileTaskRepository("interface_test.json"),
+			cleanup: func() { os.Remove("interface_test.json") },
+		},
+		{
+			name:    "MockRepository",
+			repo:    tasktest.NewMockRepository(),
+			cleanup: func() {},
+		},
+	}
+
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			defer impl.cleanup()
+			repo := impl.repo
+
+			// Test interface compliance through usage
+			tasks := tasktest.TaskSet(t, 2)
+
+			err := repo.Save(t.Context(), tasks)
+			if err != nil {
+				t.Errorf("%s Save() failed: %v", impl.name, err)
+			}
+
+			loadedTasks, err := repo.Load(t.Context())
+			if err != nil {
+				t.Errorf("%s Load() failed: %v", impl.name, err)
+			}
+
+			if len(loadedTasks) != len(tasks) {
+				t.Errorf("%s Load() returned %d tasks, want %d",
+					impl.name, len(loadedTasks), len(tasks))
+			}
+
+			nextID, err := repo.GetNextID(t.Context())
+			if err != nil {
+				t.Errorf("%s GetNextID() failed: %v", impl.name, err)
+			}
+
+			if nextID <= 0 {
+				t.Errorf("%s GetNextID() returned non-positive ID: %d", impl.name, nextID)
+			}
+		})
+	}
+}
+
+// TestFileTaskRepository_Context tests that cancelled contexts are honored
+func TestFileTaskRepository_Context(t *testing.T) {
+	tmpFile := "test_context_tasks.json"
+	defer os.Remove(tmpFile)
+
+	repo := NewFileTaskRepository(tmpFile)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := repo.Save(ctx, tasktest.TaskSet(t, 1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Save() with cancelled context error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := os.Stat(tmpFile); !os.IsNotExist(err) {
+		t.Errorf("Save() with cancelled context should not write the file")
+	}
+
+	if _, err := repo.Load(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() with cancelled context error = %v, want %v", err, context.Canceled)
+	}
+
+	if _, err := repo.GetNextID(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetNextID() with cancelled context error = %v, want %v", err, context.Canceled)
+	}
+}
+
+// TestFileTaskRepository_StoreFormat tests the versioned, checksummed file format
+func TestFileTaskRepository_StoreFormat(t *testing.T) {
+	t.Run("writes schema version and checksum", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		repo := NewFileTaskRepository(path)
+		if err := repo.Save(t.Context(), []task.Task{*tasktest.TodoTask(t)}); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+
+		data, _ := os.ReadFile(path)
+		if !strings.Contains(string(data), `"schemaVersion": 3`) ||
+			!strings.Contains(string(data), `"lastId": 1`) ||
+			!strings.Contains(string(data), `"checksum": "sha256:`) {
+			t.Errorf("file is missing header:\n%s", data)
+		}
+	})
+
+	t.Run("loads legacy array files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		legacy := `[{"id": 7, "description": "Old", "status": "todo"}]`
+		if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		repo := NewFileTaskRepository(path)
+		tasks, err := repo.Load(t.Context())
+		if err != nil || len(tasks) != 1 || tasks[0].ID != 7 {
+			t.Fatalf("Load() = %v, %v", tasks, err)
+		}
+
+		_, issues, err := repo.Inspect(t.Context())
+		if err != nil || len(issues) != 1 || issues[0].Kind != task.IssueLegacySchema {
+			t.Errorf("Inspect() issues = %v, err = %v", issues, err)
+		}
+	})
+
+	t.Run("detects edited files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		repo := NewFileTaskRepository(path)
+		item := tasktest.NewTaskBuilder().WithID(1).WithDescription("Original").BuildValid(t)
+		if err := repo.Save(t.Context(), []task.Task{*item}); err != nil {
+			t.Fatal(err)
+		}
+
+		data, _ := os.ReadFile(path)
+		edited := strings.Replace(string(data), "Original", "Tampered", 1)
+		if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := repo.Load(t.Context()); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("Load() error = %v, want %v", err, ErrChecksumMismatch)
+		}
+		tasks, issues, err := repo.Inspect(t.Context())
+		if err != nil || len(tasks) != 1 || len(issues) != 1 || issues[0].Kind != task.IssueChecksumMismatch {
+			t.Errorf("Inspect() = %v, %v, %v", tasks, issues, err)
+		}
+	})
+
+	t.Run("never reuses IDs of deleted tasks", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		legacy := `[{"id": 1, "description": "Kept", "status": "todo"},` +
+			`{"id": 5, "description": "Deleted", "status": "todo"}]`
+		if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		repo := NewFileTaskRepository(path)
+
+		// Older files start counting from their highest task ID
+		tasks, _, err := repo.Inspect(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id, err := repo.GetNextID(t.Context()); err != nil || id != 6 {
+			t.Fatalf("GetNextID() of a legacy file = %d, %v; want 6", id, err)
+		}
+
+		if err := repo.Save(t.Context(), tasks[:1]); err != nil {
+			t.Fatal(err)
+		}
+		if id, err := repo.GetNextID(t.Context()); err != nil || id != 6 {
+			t.Errorf("GetNextID() after deleting task 5 = %d, %v; want 6", id, err)
+		}
+		if data, _ := os.ReadFile(path); !strings.Contains(string(data), `"lastId": 5`) {
+			t.Errorf("upgraded file is missing lastId:\n%s", data)
+		}
+	})
+
+	t.Run("rejects newer schema versions", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		if err := os.WriteFile(path, []byte(`{"schemaVersion": 99, "tasks": []}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := NewFileTaskRepository(path).Load(t.Context()); !errors.Is(err, ErrUnsupportedSchema) {
+			t.Errorf("Load() error = %v, want %v", err, ErrUnsupportedSchema)
+		}
+	})
+}
+
+// TestFileTaskRepository_Streaming tests the streamed file format and
+// paged reads
+func TestFileTaskRepository_Streaming(t *testing.T) {
+	tasks := make([]task.Task, 50)
+	for i := range tasks {
+		tasks[i] = *tasktest.NewTaskBuilder().WithID(i + 1).WithDescription(fmt.Sprintf("Task <%d>", i+1)).BuildValid(t)
+	}
+
+	t.Run("checksum matches the compact encoding", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeStore(&buf, tasks, 0); err != nil {
+			t.Fatalf("writeStore() failed: %v", err)
+		}
+		raw, _ := json.Marshal(tasks)
+		if !strings.Contains(buf.String(), `"checksum": "`+checksum(raw)+`"`) {
+			t.Errorf("streamed checksum differs from %s", checksum(raw))
+		}
+
+		decoded, header, err := decodeStore(buf.Bytes())
+		if err != nil || !header.ChecksumValid || len(decoded) != 50 || decoded[49].Description != "Task <50>" {
+			t.Errorf("decodeStore() = %d tasks, %+v, %v", len(decoded), header, err)
+		}
+	})
+
+	t.Run("pages stop reading early", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		repo := NewFileTaskRepository(path)
+		if err := repo.Save(t.Context(), tasks); err != nil {
+			t.Fatal(err)
+		}
+
+		// Break the end of the file: full loads fail, early pages do not
+		data, _ := os.ReadFile(path)
+		cut := bytes.LastIndex(data, []byte(`"description"`))
+		if err := os.WriteFile(path, data[:cut], 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.Load(t.Context()); err == nil {
+			t.Error("Load() of a truncated file should fail")
+		}
+
+		even := func(task task.Task) bool { return task.ID%2 == 0 }
+		page, err := repo.LoadPage(t.Context(), Page{Offset: 2, Limit: 3}, even)
+		if err != nil || len(page) != 3 || page[0].ID != 6 || page[2].ID != 10 {
+			t.Errorf("LoadPage() = %v, %v; want tasks 6, 8 and 10", page, err)
+		}
+	})
+}
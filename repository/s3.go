@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// ErrBucketUnauthorized is returned when the bucket refuses the credentials
+var ErrBucketUnauthorized = errors.New("the bucket rejected the credentials, check s3.accessKeyId and s3.secretAccessKey")
+
+// S3 Blob Implementation (Adapter)
+//
+// S3Blob is the task file as one object in an S3-compatible bucket: AWS S3,
+// MinIO, Cloudflare R2, or Google Cloud Storage through its
+// interoperability API with HMAC keys. Writes use the If-Match and
+// If-None-Match conditions S3 supports.
+//
+// Requests are signed with AWS Signature Version 4 and use path-style URLs,
+// {endpoint}/{bucket}/{key}, which every S3-compatible service accepts.
+type S3Blob struct {
+	config S3Config
+	client *http.Client
+	clock  task.Clock
+}
+
+// NewS3TaskRepository creates a repository for the object config names.
+// The region defaults to us-east-1, the endpoint to AWS in that region and
+// the key to tasks.json.
+func NewS3TaskRepository(config S3Config) *BlobTaskRepository {
+	return NewBlobTaskRepository(NewS3Blob(config))
+}
+
+func NewS3Blob(config S3Config) *S3Blob {
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	if config.Key == "" {
+		config.Key = "tasks.json"
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	config.Key = strings.TrimLeft(config.Key, "/")
+	return &S3Blob{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+		clock:  time.Now,
+	}
+}
+
+func (b *S3Blob) String() string {
+	return "s3://" + b.config.Bucket + "/" + b.config.Key
+}
+
+func (b *S3Blob) Get(ctx context.Context) ([]byte, string, error) {
+	resp, body, err := b.do(ctx, http.MethodGet, b.config.Key, nil, nil)
+	if err != nil || resp.StatusCode == http.StatusNotFound {
+		return nil, "", err
+	}
+	return body, resp.Header.Get("ETag"), nil
+}
+
+func (b *S3Blob) Put(ctx context.Context, data []byte, etag string) (string, error) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	if etag != "" {
+		header.Set("If-Match", etag)
+	} else {
+		// Another machine must not have created the object meanwhile
+		header.Set("If-None-Match", "*")
+	}
+	resp, _, err := b.do(ctx, http.MethodPut, b.config.Key, data, header)
+	if err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// Probe writes and deletes an object next to the task file
+func (b *S3Blob) Probe(ctx context.Context) error {
+	key := b.config.Key + ".probe"
+	if _, _, err := b.do(ctx, http.MethodPut, key, []byte("task-cli"), nil); err != nil {
+		return err
+	}
+	_, _, err := b.do(ctx, http.MethodDelete, key, nil, nil)
+	return err
+}
+
+// do sends a signed request for an object. A missing object is returned as
+// a 404 response rather than an error.
+func (b *S3Blob) do(ctx context.Context, method, key string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	target := b.config.Endpoint + "/" + url.PathEscape(b.config.Bucket) + "/" + escapeKey(key)
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	signV4(req, body, b.config, b.clock())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, nil, fmt.Errorf("bucket %s is unreachable: %w", b.config.Bucket, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read from bucket: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && method == http.MethodGet && s3ErrorCode(respBody) != "NoSuchBucket":
+		return resp, nil, nil
+	case resp.StatusCode == http.StatusPreconditionFailed, resp.StatusCode == http.StatusConflict:
+		return nil, nil, ErrBlobConflict
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return nil, nil, ErrBucketUnauthorized
+	case resp.StatusCode >= 300:
+		if code := s3ErrorCode(respBody); code != "" {
+			return nil, nil, fmt.Errorf("bucket %s returned %s: %s", b.config.Bucket, resp.Status, code)
+		}
+		return nil, nil, fmt.Errorf("bucket %s returned %s", b.config.Bucket, resp.Status)
+	}
+	return resp, respBody, nil
+}
+
+// s3ErrorCode extracts the code of an S3 XML error body
+func s3ErrorCode(body []byte) string {
+	var e struct {
+		Code string `xml:"Code"`
+	}
+	if xml.Unmarshal(body, &e) != nil {
+		return ""
+	}
+	return e.Code
+}
+
+// escapeKey escapes an object key the way SigV4 canonicalizes the path:
+// every byte but the RFC 3986 unreserved characters, keeping the slashes.
+// url.PathEscape leaves characters such as + and = alone, which S3 would
+// escape before checking the signature.
+func escapeKey(key string) string {
+	var escaped strings.Builder
+	for _, c := range []byte(key) {
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~/", c) >= 0 {
+			escaped.WriteByte(c)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
+
+// signV4 signs a request with AWS Signature Version 4, covering the host
+// and every header already set on the request
+func signV4(req *http.Request, body []byte, config S3Config, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", config.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	canonical := map[string]string{"host": host}
+	for key, values := range req.Header {
+		canonical[strings.ToLower(key)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(canonical))
+	for name := range canonical {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var headers strings.Builder
+	for _, name := range names {
+		headers.WriteString(name + ":" + strings.TrimSpace(canonical[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	request := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		headers.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + config.Region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(request))
+
+	key := hmacSHA256([]byte("AWS4"+config.SecretAccessKey), day)
+	for _, part := range []string{config.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// SnapshotRepository stores snapshots by name
+type SnapshotRepository interface {
+	SaveSnapshot(ctx context.Context, snapshot task.Snapshot) error
+	LoadSnapshot(ctx context.Context, name string) (task.Snapshot, error)
+	// SnapshotNames lists the stored snapshots, oldest first
+	SnapshotNames(ctx context.Context) ([]string, error)
+}
+
+// File Snapshot Repository Implementation (Adapter)
+//
+// FileSnapshotRepository keeps each snapshot in its own JSON file in a
+// directory next to the data file, so creating one never rewrites the
+// others.
+type FileSnapshotRepository struct {
+	dir string
+}
+
+func NewFileSnapshotRepository(dir string) *FileSnapshotRepository {
+	return &FileSnapshotRepository{dir: dir}
+}
+
+func (r *FileSnapshotRepository) SaveSnapshot(ctx context.Context, snapshot task.Snapshot) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(r.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, snapshot.Name+".json"), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+func (r *FileSnapshotRepository) LoadSnapshot(ctx context.Context, name string) (task.Snapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return task.Snapshot{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(r.dir, filepath.Base(name)+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return task.Snapshot{}, task.ErrSnapshotNotFound.Withf("%s", name)
+	}
+	if err != nil {
+		return task.Snapshot{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	var snapshot task.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return task.Snapshot{}, fmt.Errorf("failed to unmarshal snapshot %s: %w", name, err)
+	}
+	return snapshot, nil
+}
+
+func (r *FileSnapshotRepository) SnapshotNames(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(r.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	type named struct {
+		name    string
+		modTime time.Time
+	}
+	var found []named
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+		}
+		found = append(found, named{name, info.ModTime()})
+	}
+	slices.SortFunc(found, func(a, b named) int {
+		if order := a.modTime.Compare(b.modTime); order != 0 {
+			return order
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	names := make([]string, len(found))
+	for i, f := range found {
+		names[i] = f.name
+	}
+	return names, nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// SprintRepository is the port for storing sprints
+type SprintRepository interface {
+	SaveSprints(ctx context.Context, sprints []task.Sprint) error
+	LoadSprints(ctx context.Context) ([]task.Sprint, error)
+}
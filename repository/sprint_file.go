@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// File Sprint Repository Implementation (Adapter)
+//
+// FileSprintRepository keeps sprints in a JSON file next to the data file,
+// like FileMilestoneRepository does for milestones.
+type FileSprintRepository struct {
+	filename string
+}
+
+func NewFileSprintRepository(filename string) *FileSprintRepository {
+	return &FileSprintRepository{filename: filename}
+}
+
+func (r *FileSprintRepository) SaveSprints(ctx context.Context, sprints []task.Sprint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return writeJSONList(r.filename, sprints)
+}
+
+func (r *FileSprintRepository) LoadSprints(ctx context.Context) ([]task.Sprint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return readJSONList[task.Sprint](r.filename)
+}
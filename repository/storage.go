@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// StorageProber is implemented by repositories that can check they accept
+// writes without changing the tasks
+type StorageProber interface {
+	Probe(ctx context.Context) error
+}
+
+// StorageCheck is the result of testing the connection to a store
+type StorageCheck struct {
+	// Location names the store, empty when the repository cannot tell
+	Location string
+	Tasks    int
+	// Probed reports whether writes were checked too
+	Probed bool
+}
+
+// CheckStorage tests repo by loading its tasks, past any cache, and checking
+// it accepts writes when it can tell without changing them
+func CheckStorage(ctx context.Context, repo TaskRepository) (StorageCheck, error) {
+	check := StorageCheck{}
+	if named, ok := RepositoryAs[fmt.Stringer](repo); ok {
+		check.Location = named.String()
+	}
+
+	for {
+		wrapper, ok := repo.(interface{ Unwrap() TaskRepository })
+		if !ok {
+			break
+		}
+		repo = wrapper.Unwrap()
+	}
+	tasks, err := repo.Load(ctx)
+	if err != nil {
+		return check, err
+	}
+	check.Tasks = len(tasks)
+
+	if prober, ok := repo.(StorageProber); ok {
+		if err := prober.Probe(ctx); err != nil {
+			return check, fmt.Errorf("store is readable but not writable: %w", err)
+		}
+		check.Probed = true
+	}
+	return check, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// TaskStore is the storage interface the service works against. Unlike
+// TaskRepository, which loads and saves the whole list, it reads and writes
+// one task at a time, so backends able to store tasks separately do not
+// rewrite everything for each change.
+type TaskStore interface {
+	// Get returns the task with the ID, or ErrTaskNotFound
+	Get(ctx context.Context, id int) (task.Task, error)
+	// Put adds the task, or replaces the stored task with its ID
+	Put(ctx context.Context, task task.Task) error
+	// Delete removes the task with the ID, or returns ErrTaskNotFound
+	Delete(ctx context.Context, id int) error
+	// List returns the tasks selected by the filter, in stored order
+	List(ctx context.Context, filter TaskFilter) ([]task.Task, error)
+	// NextID returns the ID the next added task will receive
+	NextID(ctx context.Context) (int, error)
+}
+
+// TaskFilter selects tasks to list; the zero filter selects every task
+type TaskFilter struct {
+	// Match keeps the tasks it returns true for, nil keeps all of them
+	Match func(task.Task) bool
+	Page  Page
+}
+
+// ChangeApplier is implemented by stores that write several changes at
+// once, all or none
+type ChangeApplier interface {
+	Apply(ctx context.Context, changes []task.TaskChange) error
+}
+
+// checkRevisions fails with ErrConflict when a change is based on another
+// version of a task than the stored one: the task was changed or deleted
+// since it was read, or a task with its ID was added meanwhile. Updates that
+// do not say which version they started from, like Put, always pass.
+func checkRevisions(changes []task.TaskChange, stored func(id int) (task.Task, bool)) error {
+	for _, change := range changes {
+		current, exists := stored(change.Task.ID)
+		var conflict bool
+		switch change.Type {
+		case task.ChangeAdded:
+			conflict = exists
+		case task.ChangeUpdated:
+			conflict = change.Previous != nil && (!exists || current.Revision != change.Previous.Revision)
+		case task.ChangeDeleted:
+			conflict = exists && current.Revision != change.Task.Revision
+		}
+		if conflict {
+			return task.TaskError{
+				Code:    task.ErrConflict.Code,
+				Message: fmt.Sprintf("%s (task %d)", task.ErrConflict.Message, change.Task.ID),
+			}
+		}
+	}
+	return nil
+}
+
+// BumpRevisions makes sure every task changed between before and after has
+// a higher revision than before, even when the change did not count itself
+func BumpRevisions(before, after []task.Task) {
+	old := make(map[int]task.Task, len(before))
+	for _, task := range before {
+		old[task.ID] = task
+	}
+	for i := range after {
+		prev, existed := old[after[i].ID]
+		if existed && after[i].Revision <= prev.Revision && !task.SameTask(prev, after[i]) {
+			after[i].Revision = prev.Revision + 1
+		}
+	}
+}
+
+// TaskReplacer is implemented by stores that can replace every task at once
+type TaskReplacer interface {
+	ReplaceAll(ctx context.Context, tasks []task.Task) error
+}
+
+// StoreFor returns the store to use for repo: the repository itself when
+// it works per task, even behind decorators such as the cache since its
+// writes are already small, and otherwise an adapter over Load and Save
+func StoreFor(repo TaskRepository) TaskStore {
+	if store, ok := RepositoryAs[TaskStore](repo); ok {
+		return store
+	}
+	return &repositoryStore{repo: repo}
+}
+
+// WriteChanges writes changes through the store, in one go when it can
+func WriteChanges(ctx context.Context, store TaskStore, changes []task.TaskChange) error {
+	if applier, ok := store.(ChangeApplier); ok {
+		return applier.Apply(ctx, changes)
+	}
+	for _, change := range changes {
+		var err error
+		if change.Type == task.ChangeDeleted {
+			err = store.Delete(ctx, change.Task.ID)
+		} else {
+			err = store.Put(ctx, change.Task)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Repository Store (Adapter)
+//
+// repositoryStore offers a TaskRepository as a TaskStore. Each call loads
+// the whole list and each write saves it, which the cache turns into one
+// read and one write per command.
+type repositoryStore struct {
+	repo TaskRepository
+}
+
+// StorageError types a failure to read or write tasks as ErrStorage, with
+// action as its message. An error that already carries a TaskError, such as
+// a conflict or a read-only file, keeps its code.
+func StorageError(action string, err error) error {
+	if errors.As(err, new(task.TaskError)) {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	storage := task.ErrStorage.Wrap(err)
+	storage.Message = action
+	return storage
+}
+
+func (s *repositoryStore) Get(ctx context.Context, id int) (task.Task, error) {
+	tasks, err := s.repo.Load(ctx)
+	if err != nil {
+		return task.Task{}, fmt.Errorf("failed to load tasks: %w", err)
+	}
+	t, ok := task.NewTaskIndex(tasks).Get(id)
+	if !ok {
+		return task.Task{}, task.ErrTaskNotFound.Withf("%d", id)
+	}
+	return *t, nil
+}
+
+func (s *repositoryStore) Put(ctx context.Context, t task.Task) error {
+	return s.Apply(ctx, []task.TaskChange{{Type: task.ChangeUpdated, Task: t}})
+}
+
+func (s *repositoryStore) Delete(ctx context.Context, id int) error {
+	return withLock(ctx, s.repo, func() error {
+		tasks, err := s.repo.Load(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load tasks: %w", err)
+		}
+		i, ok := task.NewTaskIndex(tasks).Position(id)
+		if !ok {
+			return task.ErrTaskNotFound.Withf("%d", id)
+		}
+		return s.repo.Save(ctx, slices.Delete(tasks, i, i+1))
+	})
+}
+
+func (s *repositoryStore) List(ctx context.Context, filter TaskFilter) ([]task.Task, error) {
+	if loader, ok := RepositoryAs[PageLoader](s.repo); ok {
+		return loader.LoadPage(ctx, filter.Page, filter.Match)
+	}
+	tasks, err := s.repo.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return PageTasks(tasks, filter.Page, filter.Match), nil
+}
+
+func (s *repositoryStore) NextID(ctx context.Context) (int, error) {
+	return s.repo.GetNextID(ctx)
+}
+
+func (s *repositoryStore) ReplaceAll(ctx context.Context, tasks []task.Task) error {
+	if replacer, ok := s.repo.(TaskReplacer); ok {
+		return replacer.ReplaceAll(ctx, tasks)
+	}
+	return withLock(ctx, s.repo, func() error { return s.repo.Save(ctx, tasks) })
+}
+
+// Apply loads the list once, checks that the changes were made to the tasks
+// it holds, replaces changed tasks where they are, drops deleted ones,
+// appends new ones and saves the result. The lock of the store is held from
+// the load to the save, so a change another process saves meanwhile is
+// either seen by the check or waits for the save.
+func (s *repositoryStore) Apply(ctx context.Context, changes []task.TaskChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return withLock(ctx, s.repo, func() error { return s.apply(ctx, changes) })
+}
+
+func (s *repositoryStore) apply(ctx context.Context, changes []task.TaskChange) error {
+	tasks, err := s.repo.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	index := task.NewTaskIndex(tasks)
+	if err := checkRevisions(changes, index.Find); err != nil {
+		return err
+	}
+	deleted := make(map[int]bool)
+	for _, change := range changes {
+		i, ok := index.Position(change.Task.ID)
+		switch {
+		case change.Type == task.ChangeDeleted:
+			deleted[change.Task.ID] = true
+		case ok:
+			tasks[i] = change.Task
+		default:
+			tasks = append(tasks, change.Task)
+		}
+	}
+	if len(deleted) > 0 {
+		tasks = slices.DeleteFunc(tasks, func(task task.Task) bool { return deleted[task.ID] })
+	}
+	return s.repo.Save(ctx, tasks)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// testTaskStore runs the same checks against any TaskStore holding
+// tasktest.FixedTasks
+func testTaskStore(t *testing.T, store TaskStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	if task, err := store.Get(ctx, 2); err != nil || task.Description != "Write report" {
+		t.Errorf("Get(2) = %+v, %v; want Write report", task, err)
+	}
+	if _, err := store.Get(ctx, 42); !errors.Is(err, task.ErrTaskNotFound) {
+		t.Errorf("Get(42) error = %v, want %v", err, task.ErrTaskNotFound)
+	}
+
+	item := *tasktest.NewTaskBuilder().WithID(4).WithDescription("Plan trip").BuildValid(t)
+	if err := store.Put(ctx, item); err != nil {
+		t.Fatalf("Put new: %v", err)
+	}
+	item.Description = "Plan the trip"
+	if err := store.Put(ctx, item); err != nil {
+		t.Fatalf("Put existing: %v", err)
+	}
+	if err := store.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(ctx, 1); !errors.Is(err, task.ErrTaskNotFound) {
+		t.Errorf("Delete twice error = %v, want %v", err, task.ErrTaskNotFound)
+	}
+
+	tasks, err := store.List(ctx, TaskFilter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(tasks) != 3 || tasks[0].ID != 2 || tasks[2].Description != "Plan the trip" {
+		t.Errorf("List = %+v, want tasks 2, 3 and the updated task 4", tasks)
+	}
+
+	done := func(item task.Task) bool { return item.Status == task.StatusDone }
+	if tasks, err := store.List(ctx, TaskFilter{Match: done, Page: Page{Limit: 1}}); err != nil ||
+		len(tasks) != 1 || tasks[0].ID != 3 {
+		t.Errorf("List done = %+v, %v; want task 3", tasks, err)
+	}
+	if id, err := store.NextID(ctx); err != nil || id != 5 {
+		t.Errorf("NextID = %d, %v; want 5", id, err)
+	}
+}
+
+// TestRepositoryStore tests the adapter over a whole-list repository
+func TestRepositoryStore(t *testing.T) {
+	mock := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	store := StoreFor(mock)
+	if _, ok := store.(*repositoryStore); !ok {
+		t.Fatalf("storeFor(mock) = %T, want the adapter", store)
+	}
+	testTaskStore(t, store)
+}
+
+// TestBoltTaskRepository_Store tests the bolt backend as a native store,
+// found behind the cache
+func TestBoltTaskRepository_Store(t *testing.T) {
+	bolt := NewBoltTaskRepository(filepath.Join(t.TempDir(), "tasks.db"))
+	if err := bolt.Save(context.Background(), tasktest.FixedTasks(t)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	store := StoreFor(NewCachingRepository(bolt))
+	if store != bolt {
+		t.Fatalf("storeFor(cache) = %T, want the bolt repository", store)
+	}
+	testTaskStore(t, store)
+}
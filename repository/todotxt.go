@@ -0,0 +1,336 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// todo.txt Repository Implementation (Adapter)
+//
+// Stores tasks in the plain text format described at http://todotxt.org,
+// one task per line, so the same file can be edited by other todo.txt apps:
+//
+//	x 2024-01-05 2024-01-01 Call mom +family @phone pri:A id:3
+//	(B) 2024-01-02 Write report +work due:2024-01-10 status:in-progress id:4
+//
+// Priorities (A) to (D) map to urgent, high, medium and low; completed tasks
+// keep theirs in a pri: tag as the format recommends. +projects become tags
+// and @contexts become tags starting with "@". The id:, due:, status: and
+// assignee: extensions keep what the format has no place for; cancelled
+// tasks are completed lines with status:cancelled. Notes, revisions and
+// external references are not stored.
+//
+// The format has no room for the highest ID ever given, so once the task
+// holding it is deleted the ID is kept in a file next to the list, todo.txt
+// in todo.lastid, so that GetNextID does not give it again.
+
+// todoTxtDate is the date layout of todo.txt
+const todoTxtDate = "2006-01-02"
+
+type TodoTxtRepository struct {
+	filename string
+	// Location is the time zone of the dates in the file, the local one
+	// unless changed
+	Location *time.Location
+}
+
+func NewTodoTxtRepository(filename string) *TodoTxtRepository {
+	return &TodoTxtRepository{filename: filename, Location: time.Local}
+}
+
+func (r *TodoTxtRepository) Save(ctx context.Context, tasks []task.Task) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for _, task := range tasks {
+		b.WriteString(FormatTodoTxt(task, r.Location))
+		b.WriteByte('\n')
+	}
+
+	// The mark is written first, so that it is never behind the list
+	if err := r.saveLastID(ctx, tasks); err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.filename, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// saveLastID records the highest ID ever given when tasks, about to replace
+// the list, no longer hold it
+func (r *TodoTxtRepository) saveLastID(ctx context.Context, tasks []task.Task) error {
+	stored, err := r.lastID()
+	if err != nil {
+		return err
+	}
+	lastID := stored
+	if saved, err := r.Load(ctx); err == nil {
+		lastID = max(lastID, lastTaskID(saved))
+	}
+	if lastID <= lastTaskID(tasks) || lastID == stored {
+		return nil
+	}
+	if err := os.WriteFile(r.lastIDFile(), []byte(strconv.Itoa(lastID)+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// lastID reads the highest ID ever given, 0 if the list never lost it
+func (r *TodoTxtRepository) lastID() (int, error) {
+	data, err := os.ReadFile(r.lastIDFile())
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+	lastID, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || lastID < 0 {
+		return 0, fmt.Errorf("%s: invalid last ID %q", r.lastIDFile(), strings.TrimSpace(string(data)))
+	}
+	return lastID, nil
+}
+
+func (r *TodoTxtRepository) lastIDFile() string {
+	return SidecarFile(r.filename, "lastid", "")
+}
+
+// Lock takes the lock other task-cli processes writing the file wait for
+func (r *TodoTxtRepository) Lock(ctx context.Context) (func(), error) {
+	return lockStore(ctx, r.filename)
+}
+
+func (r *TodoTxtRepository) StoreVersion(ctx context.Context) (string, error) {
+	return fileVersion(r.filename)
+}
+
+func (r *TodoTxtRepository) Load(ctx context.Context) ([]task.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(r.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return []task.Task{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer f.Close()
+
+	// Lines without a creation date fall back to the file's modification time
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	modified := info.ModTime().In(r.Location)
+
+	tasks := []task.Task{}
+	lineNo := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		lineNo++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		task, err := ParseTodoTxt(scanner.Text(), modified)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", r.filename, lineNo, err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	lastID, err := r.lastID()
+	if err != nil {
+		return nil, err
+	}
+	assignTodoTxtIDs(tasks, lastID)
+	return tasks, nil
+}
+
+// GetNextID returns the ID after the highest ever given, so IDs of deleted
+// tasks are not reused
+func (r *TodoTxtRepository) GetNextID(ctx context.Context) (int, error) {
+	tasks, err := r.Load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	lastID, err := r.lastID()
+	if err != nil {
+		return 0, err
+	}
+	return max(lastID, lastTaskID(tasks)) + 1, nil
+}
+
+// assignTodoTxtIDs numbers lines written without an id: tag, or whose id
+// repeats an earlier line, after the highest existing ID in file order or
+// lastID if higher
+func assignTodoTxtIDs(tasks []task.Task, lastID int) {
+	maxID := max(lastID, lastTaskID(tasks))
+
+	seen := make(map[int]bool)
+	for i := range tasks {
+		if tasks[i].ID == 0 || seen[tasks[i].ID] {
+			maxID++
+			tasks[i].ID = maxID
+		}
+		seen[tasks[i].ID] = true
+	}
+}
+
+// ParseTodoTxt reads one todo.txt line. Dates are in the location of
+// fallback, which is also used as the creation time when the line has none.
+// A line without an id: tag gets ID zero.
+func ParseTodoTxt(line string, fallback time.Time) (task.Task, error) {
+	words := strings.Fields(line)
+	t := task.Task{Status: task.StatusTodo}
+
+	var completed time.Time
+	if len(words) > 0 && words[0] == "x" {
+		t.Status = task.StatusDone
+		words = words[1:]
+		if date, ok := parseTodoTxtDate(words, fallback.Location()); ok {
+			completed = date
+			words = words[1:]
+		}
+	}
+	if len(words) > 0 {
+		if priority, ok := task.ParseLetterPriority(words[0]); ok && t.Status != task.StatusDone {
+			t.Priority = priority
+			words = words[1:]
+		}
+	}
+	if date, ok := parseTodoTxtDate(words, fallback.Location()); ok {
+		t.CreatedAt = date
+		words = words[1:]
+	}
+
+	var description []string
+	for _, word := range words {
+		key, value, _ := strings.Cut(word, ":")
+		switch {
+		case len(word) > 1 && word[0] == '+':
+			t.Tags = task.MergeTags(t.Tags, []string{word[1:]})
+		case len(word) > 1 && word[0] == '@':
+			t.Tags = task.MergeTags(t.Tags, []string{word})
+		case key == "id" && value != "":
+			id, err := strconv.Atoi(value)
+			if err != nil || id <= 0 {
+				return task.Task{}, fmt.Errorf("invalid id %q", value)
+			}
+			t.ID = id
+		case key == "due" && value != "":
+			due, err := task.ParseDeadline(value, fallback)
+			if err != nil {
+				return task.Task{}, fmt.Errorf("invalid due date %q", value)
+			}
+			t.DueAt = &due
+		case key == "assignee" && value != "":
+			t.Assignee = value
+		case key == "list" && value != "":
+			t.List = value
+		case key == "pri" && value != "":
+			if priority, ok := task.ParseLetterPriority("(" + value + ")"); ok {
+				t.Priority = priority
+			}
+		case key == "status" && value != "" && (t.Status != task.StatusDone || value == string(task.StatusCancelled)):
+			t.Status = task.TaskStatus(value)
+		default:
+			description = append(description, word)
+		}
+	}
+
+	t.Description = strings.Join(description, " ")
+	if t.Description == "" {
+		return task.Task{}, task.ErrEmptyDescription
+	}
+
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = fallback
+	}
+	t.UpdatedAt = t.CreatedAt
+	if completed.After(t.CreatedAt) {
+		t.UpdatedAt = completed
+	}
+	return t, nil
+}
+
+// FormatTodoTxt writes a task as one todo.txt line with dates in loc
+func FormatTodoTxt(t task.Task, loc *time.Location) string {
+	var words []string
+	letter := letterPriority(t.Priority)
+
+	closed := t.Status == task.StatusDone || t.Status == task.StatusCancelled
+	switch {
+	case closed:
+		words = append(words, "x", t.UpdatedAt.In(loc).Format(todoTxtDate))
+	case letter != "":
+		words = append(words, "("+letter+")")
+	}
+	words = append(words, t.CreatedAt.In(loc).Format(todoTxtDate), t.Description)
+
+	for _, tag := range t.Tags {
+		if strings.HasPrefix(tag, "@") {
+			words = append(words, tag)
+		} else {
+			words = append(words, "+"+tag)
+		}
+	}
+	if t.DueAt != nil {
+		due := t.DueAt.In(loc)
+		if h, m, s := due.Clock(); h == 23 && m == 59 && s == 59 {
+			words = append(words, "due:"+due.Format(todoTxtDate))
+		} else {
+			words = append(words, "due:"+due.Format("2006-01-02T15:04"))
+		}
+	}
+	if t.Assignee != "" && !strings.ContainsAny(t.Assignee, " \t") {
+		words = append(words, "assignee:"+t.Assignee)
+	}
+	if t.List != "" && !strings.ContainsAny(t.List, " \t") {
+		words = append(words, "list:"+t.List)
+	}
+	if closed && letter != "" {
+		words = append(words, "pri:"+letter)
+	}
+	if t.Status != task.StatusDone && t.Status != task.StatusTodo {
+		words = append(words, "status:"+string(t.Status))
+	}
+	words = append(words, "id:"+strconv.Itoa(t.ID))
+
+	return strings.Join(words, " ")
+}
+
+func parseTodoTxtDate(words []string, loc *time.Location) (time.Time, bool) {
+	if len(words) == 0 {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation(todoTxtDate, words[0], loc)
+	return date, err == nil
+}
+
+// letterPriority is the inverse of ParseLetterPriority
+func letterPriority(priority task.Priority) string {
+	letters := []task.Priority{task.PriorityUrgent, task.PriorityHigh, task.PriorityMedium, task.PriorityLow}
+	if i := slices.Index(letters, priority); i >= 0 {
+		return string(rune('A' + i))
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestParseTodoTxt tests reading the todo.txt line format
+func TestParseTodoTxt(t *testing.T) {
+	fallback := tasktest.FixedTime()
+
+	tests := []struct {
+		name     string
+		line     string
+		want     task.Task
+		wantTags string
+		wantDue  string
+	}{
+		{
+			name: "bare description",
+			line: "Buy groceries",
+			want: task.Task{Description: "Buy groceries", Status: task.StatusTodo, CreatedAt: fallback},
+		},
+		{
+			name:     "priority dates projects and contexts",
+			line:     "(A) 2023-12-30 Call mom +family @phone due:2024-01-05 id:7",
+			want:     task.Task{ID: 7, Description: "Call mom", Status: task.StatusTodo, Priority: task.PriorityUrgent, CreatedAt: date(2023, 12, 30)},
+			wantTags: "family,@phone",
+			wantDue:  "2024-01-05 23:59:59",
+		},
+		{
+			name: "completed with dates and kept priority",
+			line: "x 2024-01-03 2024-01-02 Pay rent pri:B",
+			want: task.Task{Description: "Pay rent", Status: task.StatusDone, Priority: task.PriorityHigh, CreatedAt: date(2024, 1, 2)},
+		},
+		{
+			name: "in progress extension",
+			line: "Write report status:in-progress http://example.com",
+			want: task.Task{Description: "Write report http://example.com", Status: task.StatusInProgress, CreatedAt: fallback},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := ParseTodoTxt(tt.line, fallback)
+			if err != nil {
+				t.Fatalf("ParseTodoTxt() failed: %v", err)
+			}
+			if task.ID != tt.want.ID || task.Description != tt.want.Description || task.Status != tt.want.Status ||
+				task.Priority != tt.want.Priority || !task.CreatedAt.Equal(tt.want.CreatedAt) {
+				t.Errorf("ParseTodoTxt() = %+v, want %+v", task, tt.want)
+			}
+			if got := strings.Join(task.Tags, ","); got != tt.wantTags {
+				t.Errorf("Tags = %q, want %q", got, tt.wantTags)
+			}
+			if tt.wantDue != "" && (task.DueAt == nil || task.DueAt.Format(time.DateTime) != tt.wantDue) {
+				t.Errorf("DueAt = %v, want %s", task.DueAt, tt.wantDue)
+			}
+		})
+	}
+
+	t.Run("completion date is the update time", func(t *testing.T) {
+		task, _ := ParseTodoTxt("x 2024-01-03 2024-01-02 Pay rent", fallback)
+		if !task.UpdatedAt.Equal(date(2024, 1, 3)) {
+			t.Errorf("UpdatedAt = %v, want 2024-01-03", task.UpdatedAt)
+		}
+	})
+
+	t.Run("invalid lines", func(t *testing.T) {
+		for _, line := range []string{"+home @phone", "Call id:abc", "Call due:someday"} {
+			if _, err := ParseTodoTxt(line, fallback); err == nil {
+				t.Errorf("ParseTodoTxt(%q) should fail", line)
+			}
+		}
+	})
+}
+
+// TestFormatTodoTxt tests writing tasks as todo.txt lines
+func TestFormatTodoTxt(t *testing.T) {
+	due := time.Date(2024, 1, 10, 23, 59, 59, 0, time.UTC)
+	item := task.Task{
+		ID: 4, Description: "Write report", Status: task.StatusInProgress, Priority: task.PriorityHigh,
+		Tags: []string{"work", "@office"}, DueAt: &due,
+		CreatedAt: tasktest.FixedTime(), UpdatedAt: tasktest.FixedTime(),
+	}
+
+	want := "(B) 2024-01-01 Write report +work @office due:2024-01-10 status:in-progress id:4"
+	if got := FormatTodoTxt(item, time.UTC); got != want {
+		t.Errorf("FormatTodoTxt() = %q, want %q", got, want)
+	}
+
+	item.Status = task.StatusCancelled
+	item.UpdatedAt = date(2024, 1, 5)
+	cancelled := FormatTodoTxt(item, time.UTC)
+	if parsed, _ := ParseTodoTxt(cancelled, tasktest.FixedTime()); parsed.Status != task.StatusCancelled {
+		t.Errorf("cancelled line %q parsed as %s", cancelled, parsed.Status)
+	}
+
+	item.Status = task.StatusDone
+	want = "x 2024-01-05 2024-01-01 Write report +work @office due:2024-01-10 pri:B id:4"
+	if got := FormatTodoTxt(item, time.UTC); got != want {
+		t.Errorf("FormatTodoTxt() done = %q, want %q", got, want)
+	}
+}
+
+// TestOpenRepository_TodoTxt tests choosing the todo.txt format by extension
+func TestOpenRepository_TodoTxt(t *testing.T) {
+	repo, err := OpenRepository("file:///tmp/todo.txt", Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := repo.(*TodoTxtRepository); !ok {
+		t.Errorf("OpenRepository() = %T, want *TodoTxtRepository", repo)
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// TestTodoTxtRepository_GetNextID tests that IDs of deleted tasks are not
+// given again
+func TestTodoTxtRepository_GetNextID(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "todo.txt")
+	repo := NewTodoTxtRepository(filename)
+
+	tasks := tasktest.FixedTasks(t)
+	if err := repo.Save(ctx, tasks); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(repo.lastIDFile()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Save wrote %s while the list holds the highest ID", repo.lastIDFile())
+	}
+	if err := repo.Save(ctx, tasks[:2]); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if id, err := NewTodoTxtRepository(filename).GetNextID(ctx); err != nil || id != 4 {
+		t.Errorf("GetNextID after deleting task 3 = %d, %v; want 4", id, err)
+	}
+
+	// Lines added by other apps are numbered after the deleted task too
+	os.WriteFile(filename, []byte("Water the plants\n"), 0o644)
+	loaded, err := repo.Load(ctx)
+	if err != nil || len(loaded) != 1 || loaded[0].ID != 4 {
+		t.Errorf("Load = %+v, %v; want the new line as task 4", loaded, err)
+	}
+}
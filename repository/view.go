@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// ViewRepository is the port for storing views
+type ViewRepository interface {
+	SaveViews(ctx context.Context, views []task.View) error
+	LoadViews(ctx context.Context) ([]task.View, error)
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// File View Repository Implementation (Adapter)
+//
+// FileViewRepository keeps saved views in a JSON file next to the data
+// file, like projects and milestones.
+type FileViewRepository struct {
+	filename string
+}
+
+func NewFileViewRepository(filename string) *FileViewRepository {
+	return &FileViewRepository{filename: filename}
+}
+
+func (r *FileViewRepository) SaveViews(ctx context.Context, views []task.View) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return writeJSONList(r.filename, views)
+}
+
+func (r *FileViewRepository) LoadViews(ctx context.Context) ([]task.View, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return readJSONList[task.View](r.filename)
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrWebDAVUnauthorized is returned when the server refuses the credentials
+var ErrWebDAVUnauthorized = errors.New("the WebDAV server rejected the credentials, check webdav.username and webdav.password")
+
+// WebDAV Blob Implementation (Adapter)
+//
+// WebDAVBlob is the task file on a WebDAV server, such as Nextcloud or
+// ownCloud, reached with basic authentication. Writes carry If-Match or
+// If-None-Match, which WebDAV servers honor on PUT.
+type WebDAVBlob struct {
+	config WebDAVConfig
+	client *http.Client
+}
+
+// NewWebDAVTaskRepository creates a repository for the file at config.URL
+func NewWebDAVTaskRepository(config WebDAVConfig) *BlobTaskRepository {
+	return NewBlobTaskRepository(NewWebDAVBlob(config))
+}
+
+func NewWebDAVBlob(config WebDAVConfig) *WebDAVBlob {
+	return &WebDAVBlob{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (b *WebDAVBlob) String() string {
+	return b.config.URL
+}
+
+func (b *WebDAVBlob) Get(ctx context.Context) ([]byte, string, error) {
+	resp, body, err := b.do(ctx, http.MethodGet, b.config.URL, nil, nil)
+	if err != nil || resp.StatusCode == http.StatusNotFound {
+		return nil, "", err
+	}
+	return body, resp.Header.Get("ETag"), nil
+}
+
+func (b *WebDAVBlob) Put(ctx context.Context, data []byte, etag string) (string, error) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	if etag != "" {
+		header.Set("If-Match", etag)
+	} else {
+		// Another machine must not have created the file meanwhile
+		header.Set("If-None-Match", "*")
+	}
+	resp, _, err := b.do(ctx, http.MethodPut, b.config.URL, data, header)
+	if err != nil {
+		return "", err
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		return etag, nil
+	}
+	// Some servers leave the ETag out of PUT responses
+	resp, _, err = b.do(ctx, http.MethodHead, b.config.URL, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// Probe writes and deletes a file next to the task file
+func (b *WebDAVBlob) Probe(ctx context.Context) error {
+	target := b.config.URL + ".probe"
+	if _, _, err := b.do(ctx, http.MethodPut, target, []byte("task-cli"), nil); err != nil {
+		return err
+	}
+	_, _, err := b.do(ctx, http.MethodDelete, target, nil, nil)
+	return err
+}
+
+// do sends an authenticated request. A missing file is returned as a 404
+// response to GET and HEAD rather than an error.
+func (b *WebDAVBlob) do(ctx context.Context, method, target string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if b.config.Username != "" || b.config.Password != "" {
+		req.SetBasicAuth(b.config.Username, b.config.Password)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, nil, fmt.Errorf("WebDAV server is unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read from WebDAV server: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && (method == http.MethodGet || method == http.MethodHead):
+		return resp, nil, nil
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return nil, nil, ErrBlobConflict
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return nil, nil, ErrWebDAVUnauthorized
+	case resp.StatusCode == http.StatusConflict:
+		// WebDAV answers 409 when the parent folder is missing
+		return nil, nil, fmt.Errorf("WebDAV server returned %s, check the folder of %s exists", resp.Status, target)
+	case resp.StatusCode >= 300:
+		return nil, nil, fmt.Errorf("WebDAV server returned %s", resp.Status)
+	}
+	return resp, respBody, nil
+}
+
+// webDAVURL turns a dav:// or davs:// location into the http(s) URL of the
+// file
+func webDAVURL(location string) string {
+	if rest, ok := strings.CutPrefix(location, "davs://"); ok {
+		return "https://" + rest
+	}
+	return "http://" + strings.TrimPrefix(location, "dav://")
+}
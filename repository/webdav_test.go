@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// fakeDAV serves files like a WebDAV server that leaves ETags out of PUT
+// responses, as some do
+type fakeDAV struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func (d *fakeDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if user, password, _ := r.BasicAuth(); user != "alice" || password != "app-password" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	data, exists := d.files[r.URL.Path]
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write(data)
+	case http.MethodPut:
+		if match := r.Header.Get("If-Match"); match != "" && (!exists || match != etag) ||
+			r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		d.files[r.URL.Path] = body
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		delete(d.files, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newFakeDAV(t *testing.T) (*fakeDAV, WebDAVConfig) {
+	t.Helper()
+	dav := &fakeDAV{files: make(map[string][]byte)}
+	server := httptest.NewServer(dav)
+	t.Cleanup(server.Close)
+	return dav, WebDAVConfig{URL: server.URL + "/remote.php/dav/files/alice/tasks.json",
+		Username: "alice", Password: "app-password"}
+}
+
+func TestWebDAVTaskRepository(t *testing.T) {
+	dav, config := newFakeDAV(t)
+	ctx := context.Background()
+	repo := NewWebDAVTaskRepository(config)
+
+	if tasks, err := repo.Load(ctx); err != nil || len(tasks) != 0 {
+		t.Fatalf("missing file = %v, %v", tasks, err)
+	}
+	if err := repo.Save(ctx, tasktest.FixedTasks(t)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dav.files["/remote.php/dav/files/alice/tasks.json"]; !ok {
+		t.Fatalf("file not written at the URL, server holds %v", dav.files)
+	}
+
+	other := NewWebDAVTaskRepository(config)
+	tasks, err := other.Load(ctx)
+	if err != nil || len(tasks) != 3 {
+		t.Fatalf("reloaded = %+v, %v", tasks, err)
+	}
+	// The ETag comes from a HEAD request after the PUT, so saving again
+	// without reloading works
+	if err := repo.Save(ctx, tasks[:2]); err != nil {
+		t.Fatalf("second save: %v", err)
+	}
+	if err := other.Save(ctx, tasks); !errors.Is(err, ErrBlobConflict) {
+		t.Errorf("stale save err = %v, want %v", err, ErrBlobConflict)
+	}
+
+	if err := repo.Probe(ctx); err != nil {
+		t.Errorf("Probe() = %v", err)
+	}
+	if len(dav.files) != 1 {
+		t.Errorf("probe file left behind: %v", dav.files)
+	}
+
+	config.Password = "wrong"
+	if _, err := NewWebDAVTaskRepository(config).Load(ctx); !errors.Is(err, ErrWebDAVUnauthorized) {
+		t.Errorf("bad credentials err = %v, want %v", err, ErrWebDAVUnauthorized)
+	}
+}
+
+func TestOpenRepository_WebDAV(t *testing.T) {
+	tests := map[string]string{
+		"davs://cloud.example.com/remote.php/dav/files/alice/tasks.json": "https://cloud.example.com/remote.php/dav/files/alice/tasks.json",
+		"dav://nas.local/tasks.json":                                     "http://nas.local/tasks.json",
+	}
+	for location, want := range tests {
+		repo, err := OpenRepository(location, Config{WebDAV: WebDAVConfig{Username: "alice"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		blob := repo.(*BlobTaskRepository).blob.(*WebDAVBlob)
+		if blob.config.URL != want || blob.config.Username != "alice" {
+			t.Errorf("OpenRepository(%q) config = %+v, want URL %s", location, blob.config, want)
+		}
+	}
+}
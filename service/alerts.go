@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Alerts lists every task needing attention at the given time
+func (s *TaskService) Alerts(ctx context.Context, now time.Time, thresholds task.AlertThresholds) ([]task.Alert, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+
+	var alerts []task.Alert
+	for _, t := range tasks {
+		if alert, ok := task.AlertFor(t, now, thresholds); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return alerts, nil
+}
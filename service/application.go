@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Application Service (Use Cases)
+type TaskService struct {
+	store repository.TaskStore
+	// repo is only consulted for optional capabilities such as history
+	repo       repository.TaskRepository
+	audit      repository.AuditLog
+	workflow   task.Workflow
+	projects   repository.ProjectRepository
+	milestones repository.MilestoneRepository
+	sprints    repository.SprintRepository
+	views      repository.ViewRepository
+	snapshots  repository.SnapshotRepository
+	habits     repository.HabitLog
+	metrics    *Metrics
+	hooks      *Hooks
+	// defaultList names the list of tasks without one, see InList
+	defaultList string
+	// readOnly refuses every change before the store is read
+	readOnly bool
+}
+
+func NewTaskService(repo repository.TaskRepository) *TaskService {
+	return &TaskService{store: repository.StoreFor(repo), repo: repo, workflow: task.DefaultWorkflow()}
+}
+
+// WithWorkflow replaces the built-in statuses and transitions
+func (s *TaskService) WithWorkflow(workflow task.Workflow) *TaskService {
+	s.workflow = workflow
+	return s
+}
+
+// Workflow returns the statuses and transitions tasks follow
+func (s *TaskService) Workflow() task.Workflow {
+	return s.workflow
+}
+
+// WithAuditLog records an event for every change the service saves
+func (s *TaskService) WithAuditLog(audit repository.AuditLog) *TaskService {
+	s.audit = audit
+	return s
+}
+
+func (s *TaskService) AddTask(ctx context.Context, description string, opts ...task.TaskOption) (*task.Task, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	nextID, err := s.store.NextID(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to get next ID", err)
+	}
+
+	t, err := s.newTask(nextID, description, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPlanning(ctx, []task.Task{*t}); err != nil {
+		return nil, err
+	}
+
+	after := []task.Task{*t}
+	if err := s.save(ctx, nil, after); err != nil {
+		return nil, repository.StorageError("failed to save tasks", err)
+	}
+
+	// Return the saved copy, which carries its attribution
+	return &after[0], nil
+}
+
+// AddTasks adds several tasks in one save, adding none if any draft is
+// invalid. The options apply to every task, after the draft's own options.
+func (s *TaskService) AddTasks(ctx context.Context, drafts []task.TaskDraft, opts ...task.TaskOption) ([]task.Task, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	nextID, err := s.store.NextID(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to get next ID", err)
+	}
+
+	added := make([]task.Task, 0, len(drafts))
+	for i, draft := range drafts {
+		task, err := s.newTask(nextID+i, draft.Description, append(slices.Clone(draft.Options), opts...)...)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, *task)
+	}
+	if err := s.checkPlanning(ctx, added); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, nil, added); err != nil {
+		return nil, repository.StorageError("failed to save tasks", err)
+	}
+
+	return added, nil
+}
+
+// newTask creates a task whose description is tidied with the workflow's
+// description rules
+func (s *TaskService) newTask(id int, description string, opts ...task.TaskOption) (*task.Task, error) {
+	return task.NewTask(id, description, append([]task.TaskOption{task.WithDescriptionRules(s.workflow.Descriptions)}, opts...)...)
+}
+
+func (s *TaskService) UpdateTask(ctx context.Context, id int, description string) error {
+	return s.updateTask(ctx, id, func(task *task.Task) error {
+		return task.UpdateDescription(description, s.workflow.Descriptions)
+	})
+}
+
+func (s *TaskService) DeleteTask(ctx context.Context, id int) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	t, err := s.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteTasks(ctx, []task.Task{t})
+}
+
+// DeleteTasks deletes several tasks in one save; when one of them does not
+// exist none is deleted
+func (s *TaskService) DeleteTasks(ctx context.Context, ids []int) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	tasks, err := s.getTasks(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteTasks(ctx, tasks)
+}
+
+// deleteTasks deletes tasks along with the relations other tasks have to
+// them
+func (s *TaskService) deleteTasks(ctx context.Context, tasks []task.Task) error {
+	before, after, err := s.unlinkDeleted(ctx, tasks)
+	if err != nil {
+		return err
+	}
+	return s.save(ctx, append(before, tasks...), after)
+}
+
+func (s *TaskService) MarkTaskInProgress(ctx context.Context, id int) error {
+	return s.MoveTask(ctx, id, task.StatusInProgress)
+}
+
+func (s *TaskService) MarkTaskDone(ctx context.Context, id int) error {
+	return s.MoveTask(ctx, id, task.StatusDone)
+}
+
+// MoveTask changes a task to any status the workflow allows from its
+// current one, unless the status is at its WIP limit
+func (s *TaskService) MoveTask(ctx context.Context, id int, status task.TaskStatus) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if err := s.checkWIPLimit(ctx, id, status); err != nil {
+		return err
+	}
+	return s.updateClosing(ctx, id, func(task *task.Task) error {
+		return task.MoveTo(status, s.workflow)
+	})
+}
+
+// CancelTask closes a task that will not be done, noting the reason if any
+func (s *TaskService) CancelTask(ctx context.Context, id int, reason string) error {
+	return s.updateClosing(ctx, id, func(task *task.Task) error {
+		return task.Cancel(reason, s.workflow, time.Now())
+	})
+}
+
+// ForceMoveTask changes a task to any status of the workflow, ignoring its
+// transition rules and WIP limits
+func (s *TaskService) ForceMoveTask(ctx context.Context, id int, status task.TaskStatus) error {
+	return s.updateClosing(ctx, id, func(task *task.Task) error {
+		return task.MoveTo(status, s.workflow.Unrestricted())
+	})
+}
+
+// AssignTask sets who is expected to do a task, or clears it with ""
+func (s *TaskService) AssignTask(ctx context.Context, id int, user string) error {
+	return s.updateTask(ctx, id, func(task *task.Task) error {
+		task.Assign(user)
+		return nil
+	})
+}
+
+func (s *TaskService) SetTaskDue(ctx context.Context, id int, due *time.Time) error {
+	return s.updateTask(ctx, id, func(task *task.Task) error {
+		task.SetDue(due)
+		return nil
+	})
+}
+
+func (s *TaskService) SetTaskEstimate(ctx context.Context, id int, estimate time.Duration) error {
+	return s.updateTask(ctx, id, func(task *task.Task) error {
+		return task.SetEstimate(estimate)
+	})
+}
+
+// AddCheckItem appends a step to a task's checklist and returns its number
+func (s *TaskService) AddCheckItem(ctx context.Context, id int, text string) (int, error) {
+	n := 0
+	err := s.updateTask(ctx, id, func(task *task.Task) error {
+		var err error
+		n, err = task.AddCheckItem(text)
+		return err
+	})
+	return n, err
+}
+
+// ToggleCheckItem checks or unchecks the nth step of a task's checklist
+func (s *TaskService) ToggleCheckItem(ctx context.Context, id, n int) (task.ChecklistItem, error) {
+	var item task.ChecklistItem
+	err := s.updateTask(ctx, id, func(task *task.Task) error {
+		var err error
+		item, err = task.ToggleCheckItem(n)
+		return err
+	})
+	return item, err
+}
+
+// AttachToTask links a URL or file to a task and returns its number
+func (s *TaskService) AttachToTask(ctx context.Context, id int, target, label string) (int, error) {
+	n := 0
+	err := s.updateTask(ctx, id, func(task *task.Task) error {
+		var err error
+		n, err = task.Attach(target, label)
+		return err
+	})
+	return n, err
+}
+
+// RecordPomodoro counts a completed work interval on the task and returns
+// the new count
+func (s *TaskService) RecordPomodoro(ctx context.Context, id int) (int, error) {
+	count := 0
+	err := s.updateTask(ctx, id, func(task *task.Task) error {
+		task.AddPomodoro()
+		count = task.Pomodoros
+		return nil
+	})
+	return count, err
+}
+
+func (s *TaskService) SetTaskReminder(ctx context.Context, id int, at *time.Time) error {
+	return s.updateTask(ctx, id, func(task *task.Task) error {
+		task.SetReminder(at)
+		return nil
+	})
+}
+
+func (s *TaskService) updateTask(ctx context.Context, id int, updateFn func(*task.Task) error) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	t, err := s.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	after := []task.Task{t}
+	if err := updateFn(&after[0]); err != nil {
+		return err
+	}
+
+	return s.save(ctx, []task.Task{t}, after)
+}
+
+func (s *TaskService) GetTask(ctx context.Context, id int) (*task.Task, error) {
+	task, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ExistsTask reports whether a task with the ID is stored
+func (s *TaskService) ExistsTask(ctx context.Context, id int) (bool, error) {
+	_, err := s.store.Get(ctx, id)
+	if errors.Is(err, task.ErrTaskNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// NextID returns the ID the next added task will receive
+func (s *TaskService) NextID(ctx context.Context) (int, error) {
+	id, err := s.store.NextID(ctx)
+	if err != nil {
+		return 0, repository.StorageError("failed to get next ID", err)
+	}
+	return id, nil
+}
+
+// ReplaceTasks stores a complete task list, rejecting lists that fail the
+// integrity checks
+func (s *TaskService) ReplaceTasks(ctx context.Context, tasks []task.Task) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if issues := task.CheckTasks(tasks, s.workflow); len(issues) > 0 {
+		return task.ErrInvalidTasks.Withf("%s", issues[0])
+	}
+
+	before, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return repository.StorageError("failed to load tasks", err)
+	}
+
+	if err := s.saveAll(ctx, before, tasks); err != nil {
+		return repository.StorageError("failed to save tasks", err)
+	}
+	return nil
+}
+
+// ListPage returns the tasks selected by match within the page, reading no
+// more of the store than needed when the repository supports it
+func (s *TaskService) ListPage(ctx context.Context, page repository.Page, match func(task.Task) bool) ([]task.Task, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{Match: match, Page: page})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	return tasks, nil
+}
+
+func (s *TaskService) ListTasks(ctx context.Context, status string) ([]task.Task, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+
+	if status == "" {
+		return tasks, nil
+	}
+
+	var filteredTasks []task.Task
+	for _, task := range tasks {
+		if string(task.Status) == status {
+			filteredTasks = append(filteredTasks, task)
+		}
+	}
+
+	return filteredTasks, nil
+}
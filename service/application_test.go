@@ -1,38 +1,41 @@
-package main
+package service
 
 import (
 	"errors"
 	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
 )
 
 // TestTaskService_AddTask tests task creation orchestration
 func TestTaskService_AddTask(t *testing.T) {
 	t.Run("successful task addition", func(t *testing.T) {
-		repo := NewMockRepository()
+		repo := tasktest.NewMockRepository()
 		service := NewTaskService(repo)
 
-		task, err := service.AddTask("Buy groceries")
+		item, err := service.AddTask(t.Context(), "Buy groceries")
 		if err != nil {
 			t.Errorf("AddTask() unexpected error = %v", err)
 			return
 		}
 
-		if task == nil {
+		if item == nil {
 			t.Errorf("AddTask() returned nil task")
 			return
 		}
 
 		// Verify task properties
-		if task.ID != 1 {
-			t.Errorf("AddTask() ID = %v, want 1", task.ID)
+		if item.ID != 1 {
+			t.Errorf("AddTask() ID = %v, want 1", item.ID)
 		}
-		if task.Description != "Buy groceries" {
-			t.Errorf("AddTask() Description = %v, want 'Buy groceries'", task.Description)
+		if item.Description != "Buy groceries" {
+			t.Errorf("AddTask() Description = %v, want 'Buy groceries'", item.Description)
 		}
-		if task.Status != StatusTodo {
-			t.Errorf("AddTask() Status = %v, want %v", task.Status, StatusTodo)
+		if item.Status != task.StatusTodo {
+			t.Errorf("AddTask() Status = %v, want %v", item.Status, task.StatusTodo)
 		}
 
 		// Verify task was saved
@@ -45,14 +48,14 @@ func TestTaskService_AddTask(t *testing.T) {
 			t.Errorf("AddTask() should save 1 task, saved %d", len(savedTasks))
 		}
 
-		AssertTaskEquals(t, task, &savedTasks[0])
+		tasktest.AssertTaskEquals(t, item, &savedTasks[0])
 	})
 
 	t.Run("empty description validation", func(t *testing.T) {
-		repo := NewMockRepository()
+		repo := tasktest.NewMockRepository()
 		service := NewTaskService(repo)
 
-		task, err := service.AddTask("")
+		task, err := service.AddTask(t.Context(), "")
 		if err == nil {
 			t.Errorf("AddTask() with empty description should return error")
 		}
@@ -66,10 +69,10 @@ func TestTaskService_AddTask(t *testing.T) {
 
 	t.Run("repository error handling", func(t *testing.T) {
 		expectedErr := errors.New("repository failed")
-		repo := NewMockRepository().WithError(expectedErr)
+		repo := tasktest.NewMockRepository().WithError(expectedErr)
 		service := NewTaskService(repo)
 
-		task, err := service.AddTask("Valid description")
+		task, err := service.AddTask(t.Context(), "Valid description")
 		if err == nil {
 			t.Errorf("AddTask() should return error when repository fails")
 		}
@@ -79,21 +82,21 @@ func TestTaskService_AddTask(t *testing.T) {
 	})
 
 	t.Run("sequential ID generation", func(t *testing.T) {
-		repo := NewMockRepository()
+		repo := tasktest.NewMockRepository()
 		service := NewTaskService(repo)
 
 		// Add multiple tasks
-		task1, err := service.AddTask("Task 1")
+		task1, err := service.AddTask(t.Context(), "Task 1")
 		if err != nil {
 			t.Fatalf("AddTask() failed for task 1: %v", err)
 		}
 
-		task2, err := service.AddTask("Task 2")
+		task2, err := service.AddTask(t.Context(), "Task 2")
 		if err != nil {
 			t.Fatalf("AddTask() failed for task 2: %v", err)
 		}
 
-		task3, err := service.AddTask("Task 3")
+		task3, err := service.AddTask(t.Context(), "Task 3")
 		if err != nil {
 			t.Fatalf("AddTask() failed for task 3: %v", err)
 		}
@@ -117,14 +120,45 @@ func TestTaskService_AddTask(t *testing.T) {
 	})
 }
 
+// TestTaskService_AddTasks tests adding several tasks in one save
+func TestTaskService_AddTasks(t *testing.T) {
+	t.Run("adds all with consecutive IDs", func(t *testing.T) {
+		repo := tasktest.NewMockRepository()
+		service := NewTaskService(repo)
+
+		tasks, err := service.AddTasks(t.Context(), []task.TaskDraft{{Description: "Milk"}, {Description: "Eggs"}})
+		if err != nil {
+			t.Fatalf("AddTasks() unexpected error = %v", err)
+		}
+		if len(tasks) != 2 || tasks[0].ID != 1 || tasks[1].ID != 2 {
+			t.Errorf("AddTasks() = %+v", tasks)
+		}
+		if repo.SaveCallCount() != 1 || repo.TaskCount() != 2 {
+			t.Errorf("AddTasks() saved %d times, %d tasks", repo.SaveCallCount(), repo.TaskCount())
+		}
+	})
+
+	t.Run("invalid description adds nothing", func(t *testing.T) {
+		repo := tasktest.NewMockRepository()
+		service := NewTaskService(repo)
+
+		if _, err := service.AddTasks(t.Context(), []task.TaskDraft{{Description: "Milk"}, {Description: "  "}}); !errors.Is(err, task.ErrEmptyDescription) {
+			t.Errorf("AddTasks() error = %v, want %v", err, task.ErrEmptyDescription)
+		}
+		if repo.SaveCallCount() != 0 {
+			t.Errorf("AddTasks() with validation error should not call Save()")
+		}
+	})
+}
+
 // TestTaskService_UpdateTask tests task modification orchestration
 func TestTaskService_UpdateTask(t *testing.T) {
 	t.Run("successful update", func(t *testing.T) {
-		existingTask := TodoTask(t)
-		repo := NewMockRepository().WithTasks([]Task{*existingTask})
+		existingTask := tasktest.TodoTask(t)
+		repo := tasktest.NewMockRepository().WithTasks([]task.Task{*existingTask})
 		service := NewTaskService(repo)
 
-		err := service.UpdateTask(existingTask.ID, "Updated description")
+		err := service.UpdateTask(t.Context(), existingTask.ID, "Updated description")
 		if err != nil {
 			t.Errorf("UpdateTask() unexpected error = %v", err)
 		}
@@ -156,12 +190,12 @@ func TestTaskService_UpdateTask(t *testing.T) {
 	})
 
 	t.Run("task not found", func(t *testing.T) {
-		repo := NewMockRepository()
+		repo := tasktest.NewMockRepository()
 		service := NewTaskService(repo)
 
-		err := service.UpdateTask(999, "New description")
-		if err != ErrTaskNotFound {
-			t.Errorf("UpdateTask() error = %v, want %v", err, ErrTaskNotFound)
+		err := service.UpdateTask(t.Context(), 999, "New description")
+		if !errors.Is(err, task.ErrTaskNotFound) {
+			t.Errorf("UpdateTask() error = %v, want %v", err, task.ErrTaskNotFound)
 		}
 
 		// Verify no save was attempted
@@ -171,34 +205,34 @@ func TestTaskService_UpdateTask(t *testing.T) {
 	})
 
 	t.Run("empty description validation", func(t *testing.T) {
-		existingTask := TodoTask(t)
-		repo := NewMockRepository().WithTasks([]Task{*existingTask})
+		existingTask := tasktest.TodoTask(t)
+		repo := tasktest.NewMockRepository().WithTasks([]task.Task{*existingTask})
 		service := NewTaskService(repo)
 
-		err := service.UpdateTask(existingTask.ID, "")
-		if err != ErrEmptyDescription {
+		err := service.UpdateTask(t.Context(), existingTask.ID, "")
+		if !errors.Is(err, task.ErrEmptyDescription) {
 			t.Errorf(
 				"UpdateTask() with empty description error = %v, want %v",
 				err,
-				ErrEmptyDescription,
+				task.ErrEmptyDescription,
 			)
 		}
 
 		// Verify task was not modified
 		savedTasks := repo.GetStoredTasks()
-		AssertTaskEquals(t, existingTask, &savedTasks[0])
+		tasktest.AssertTaskEquals(t, existingTask, &savedTasks[0])
 	})
 }
 
 // TestTaskService_DeleteTask tests task removal orchestration
 func TestTaskService_DeleteTask(t *testing.T) {
 	t.Run("successful deletion", func(t *testing.T) {
-		tasks := MixedStatusTasks(t)
-		repo := NewMockRepository().WithTasks(tasks)
+		tasks := tasktest.MixedStatusTasks(t)
+		repo := tasktest.NewMockRepository().WithTasks(tasks)
 		service := NewTaskService(repo)
 
 		taskToDelete := tasks[1] // Middle task
-		err := service.DeleteTask(taskToDelete.ID)
+		err := service.DeleteTask(t.Context(), taskToDelete.ID)
 		if err != nil {
 			t.Errorf("DeleteTask() unexpected error = %v", err)
 		}
@@ -232,12 +266,12 @@ func TestTaskService_DeleteTask(t *testing.T) {
 	})
 
 	t.Run("task not found", func(t *testing.T) {
-		repo := NewMockRepository()
+		repo := tasktest.NewMockRepository()
 		service := NewTaskService(repo)
 
-		err := service.DeleteTask(999)
-		if err != ErrTaskNotFound {
-			t.Errorf("DeleteTask() error = %v, want %v", err, ErrTaskNotFound)
+		err := service.DeleteTask(t.Context(), 999)
+		if !errors.Is(err, task.ErrTaskNotFound) {
+			t.Errorf("DeleteTask() error = %v, want %v", err, task.ErrTaskNotFound)
 		}
 
 		// Verify no save was attempted
@@ -247,11 +281,11 @@ func TestTaskService_DeleteTask(t *testing.T) {
 	})
 
 	t.Run("delete from single task list", func(t *testing.T) {
-		task := TodoTask(t)
-		repo := NewMockRepository().WithTasks([]Task{*task})
+		item := tasktest.TodoTask(t)
+		repo := tasktest.NewMockRepository().WithTasks([]task.Task{*item})
 		service := NewTaskService(repo)
 
-		err := service.DeleteTask(task.ID)
+		err := service.DeleteTask(t.Context(), item.ID)
 		if err != nil {
 			t.Errorf("DeleteTask() unexpected error = %v", err)
 		}
@@ -267,11 +301,11 @@ func TestTaskService_DeleteTask(t *testing.T) {
 // TestTaskService_MarkTaskInProgress tests status change orchestration
 func TestTaskService_MarkTaskInProgress(t *testing.T) {
 	t.Run("successful status change", func(t *testing.T) {
-		task := TodoTask(t)
-		repo := NewMockRepository().WithTasks([]Task{*task})
+		item := tasktest.TodoTask(t)
+		repo := tasktest.NewMockRepository().WithTasks([]task.Task{*item})
 		service := NewTaskService(repo)
 
-		err := service.MarkTaskInProgress(task.ID)
+		err := service.MarkTaskInProgress(t.Context(), item.ID)
 		if err != nil {
 			t.Errorf("MarkTaskInProgress() unexpected error = %v", err)
 		}
@@ -283,49 +317,49 @@ func TestTaskService_MarkTaskInProgress(t *testing.T) {
 		}
 
 		updatedTask := savedTasks[0]
-		if updatedTask.Status != StatusInProgress {
+		if updatedTask.Status != task.StatusInProgress {
 			t.Errorf(
 				"MarkTaskInProgress() status = %v, want %v",
 				updatedTask.Status,
-				StatusInProgress,
+				task.StatusInProgress,
 			)
 		}
 
 		// Verify other properties unchanged
-		if updatedTask.ID != task.ID {
+		if updatedTask.ID != item.ID {
 			t.Errorf("MarkTaskInProgress() should not change ID")
 		}
-		if updatedTask.Description != task.Description {
+		if updatedTask.Description != item.Description {
 			t.Errorf("MarkTaskInProgress() should not change description")
 		}
-		if updatedTask.CreatedAt != task.CreatedAt {
+		if updatedTask.CreatedAt != item.CreatedAt {
 			t.Errorf("MarkTaskInProgress() should not change CreatedAt")
 		}
 	})
 
 	t.Run("task not found", func(t *testing.T) {
-		repo := NewMockRepository()
+		repo := tasktest.NewMockRepository()
 		service := NewTaskService(repo)
 
-		err := service.MarkTaskInProgress(999)
-		if err != ErrTaskNotFound {
-			t.Errorf("MarkTaskInProgress() error = %v, want %v", err, ErrTaskNotFound)
+		err := service.MarkTaskInProgress(t.Context(), 999)
+		if !errors.Is(err, task.ErrTaskNotFound) {
+			t.Errorf("MarkTaskInProgress() error = %v, want %v", err, task.ErrTaskNotFound)
 		}
 	})
 
 	t.Run("idempotent operation", func(t *testing.T) {
-		task := InProgressTask(t)
-		repo := NewMockRepository().WithTasks([]Task{*task})
+		item := tasktest.InProgressTask(t)
+		repo := tasktest.NewMockRepository().WithTasks([]task.Task{*item})
 		service := NewTaskService(repo)
 
-		err := service.MarkTaskInProgress(task.ID)
+		err := service.MarkTaskInProgress(t.Context(), item.ID)
 		if err != nil {
 			t.Errorf("MarkTaskInProgress() on already in-progress task should not error")
 		}
 
 		// Verify status remains in-progress
 		savedTasks := repo.GetStoredTasks()
-		if savedTasks[0].Status != StatusInProgress {
+		if savedTasks[0].Status != task.StatusInProgress {
 			t.Errorf("MarkTaskInProgress() should remain in-progress")
 		}
 	})
@@ -334,11 +368,11 @@ func TestTaskService_MarkTaskInProgress(t *testing.T) {
 // TestTaskService_MarkTaskDone tests completion orchestration
 func TestTaskService_MarkTaskDone(t *testing.T) {
 	t.Run("successful completion", func(t *testing.T) {
-		task := InProgressTask(t)
-		repo := NewMockRepository().WithTasks([]Task{*task})
+		item := tasktest.InProgressTask(t)
+		repo := tasktest.NewMockRepository().WithTasks([]task.Task{*item})
 		service := NewTaskService(repo)
 
-		err := service.MarkTaskDone(task.ID)
+		err := service.MarkTaskDone(t.Context(), item.ID)
 		if err != nil {
 			t.Errorf("MarkTaskDone() unexpected error = %v", err)
 		}
@@ -346,47 +380,72 @@ func TestTaskService_MarkTaskDone(t *testing.T) {
 		// Verify status was changed
 		savedTasks := repo.GetStoredTasks()
 		updatedTask := savedTasks[0]
-		if updatedTask.Status != StatusDone {
-			t.Errorf("MarkTaskDone() status = %v, want %v", updatedTask.Status, StatusDone)
+		if updatedTask.Status != task.StatusDone {
+			t.Errorf("MarkTaskDone() status = %v, want %v", updatedTask.Status, task.StatusDone)
 		}
 	})
 
 	t.Run("complete todo task directly", func(t *testing.T) {
-		task := TodoTask(t)
-		repo := NewMockRepository().WithTasks([]Task{*task})
+		item := tasktest.TodoTask(t)
+		repo := tasktest.NewMockRepository().WithTasks([]task.Task{*item})
 		service := NewTaskService(repo)
 
-		err := service.MarkTaskDone(task.ID)
+		err := service.MarkTaskDone(t.Context(), item.ID)
 		if err != nil {
 			t.Errorf("MarkTaskDone() on todo task should work")
 		}
 
 		savedTasks := repo.GetStoredTasks()
-		if savedTasks[0].Status != StatusDone {
+		if savedTasks[0].Status != task.StatusDone {
 			t.Errorf("MarkTaskDone() should change todo to done")
 		}
 	})
 
 	t.Run("task not found", func(t *testing.T) {
-		repo := NewMockRepository()
+		repo := tasktest.NewMockRepository()
 		service := NewTaskService(repo)
 
-		err := service.MarkTaskDone(999)
-		if err != ErrTaskNotFound {
-			t.Errorf("MarkTaskDone() error = %v, want %v", err, ErrTaskNotFound)
+		err := service.MarkTaskDone(t.Context(), 999)
+		if !errors.Is(err, task.ErrTaskNotFound) {
+			t.Errorf("MarkTaskDone() error = %v, want %v", err, task.ErrTaskNotFound)
 		}
 	})
 }
 
+// TestTaskService_GetTask tests looking tasks up by ID
+func TestTaskService_GetTask(t *testing.T) {
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	service := NewTaskService(repo)
+
+	item, err := service.GetTask(t.Context(), 2)
+	if err != nil || item.Description != "Write report" {
+		t.Errorf("GetTask(2) = %+v, %v; want Write report", item, err)
+	}
+	if _, err := service.GetTask(t.Context(), 9); !errors.Is(err, task.ErrTaskNotFound) {
+		t.Errorf("GetTask(9) error = %v, want ErrTaskNotFound", err)
+	}
+
+	for id, want := range map[int]bool{3: true, 9: false} {
+		if exists, err := service.ExistsTask(t.Context(), id); err != nil || exists != want {
+			t.Errorf("ExistsTask(%d) = %v, %v; want %v", id, exists, err, want)
+		}
+	}
+
+	repo.WithError(errors.New("disk on fire"))
+	if _, err := service.ExistsTask(t.Context(), 3); err == nil {
+		t.Error("ExistsTask() should report load errors")
+	}
+}
+
 // TestTaskService_ListTasks tests task retrieval and filtering
 func TestTaskService_ListTasks(t *testing.T) {
 	// Setup test data
-	tasks := MixedStatusTasks(t)
-	repo := NewMockRepository().WithTasks(tasks)
+	tasks := tasktest.MixedStatusTasks(t)
+	repo := tasktest.NewMockRepository().WithTasks(tasks)
 	service := NewTaskService(repo)
 
 	t.Run("list all tasks", func(t *testing.T) {
-		result, err := service.ListTasks("")
+		result, err := service.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Errorf("ListTasks() unexpected error = %v", err)
 		}
@@ -395,11 +454,11 @@ func TestTaskService_ListTasks(t *testing.T) {
 			t.Errorf("ListTasks() returned %d tasks, want 3", len(result))
 		}
 
-		AssertTasksEqual(t, tasks, result)
+		tasktest.AssertTasksEqual(t, tasks, result)
 	})
 
 	t.Run("list todo tasks", func(t *testing.T) {
-		result, err := service.ListTasks("todo")
+		result, err := service.ListTasks(t.Context(), "todo")
 		if err != nil {
 			t.Errorf("ListTasks(todo) unexpected error = %v", err)
 		}
@@ -409,15 +468,15 @@ func TestTaskService_ListTasks(t *testing.T) {
 			t.Errorf("ListTasks(todo) returned %d tasks, want %d", len(result), expectedCount)
 		}
 
-		for _, task := range result {
-			if task.Status != StatusTodo {
-				t.Errorf("ListTasks(todo) returned task with status %v", task.Status)
+		for _, item := range result {
+			if item.Status != task.StatusTodo {
+				t.Errorf("ListTasks(todo) returned task with status %v", item.Status)
 			}
 		}
 	})
 
 	t.Run("list in-progress tasks", func(t *testing.T) {
-		result, err := service.ListTasks("in-progress")
+		result, err := service.ListTasks(t.Context(), "in-progress")
 		if err != nil {
 			t.Errorf("ListTasks(in-progress) unexpected error = %v", err)
 		}
@@ -431,15 +490,15 @@ func TestTaskService_ListTasks(t *testing.T) {
 			)
 		}
 
-		for _, task := range result {
-			if task.Status != StatusInProgress {
-				t.Errorf("ListTasks(in-progress) returned task with status %v", task.Status)
+		for _, item := range result {
+			if item.Status != task.StatusInProgress {
+				t.Errorf("ListTasks(in-progress) returned task with status %v", item.Status)
 			}
 		}
 	})
 
 	t.Run("list done tasks", func(t *testing.T) {
-		result, err := service.ListTasks("done")
+		result, err := service.ListTasks(t.Context(), "done")
 		if err != nil {
 			t.Errorf("ListTasks(done) unexpected error = %v", err)
 		}
@@ -449,18 +508,18 @@ func TestTaskService_ListTasks(t *testing.T) {
 			t.Errorf("ListTasks(done) returned %d tasks, want %d", len(result), expectedCount)
 		}
 
-		for _, task := range result {
-			if task.Status != StatusDone {
-				t.Errorf("ListTasks(done) returned task with status %v", task.Status)
+		for _, item := range result {
+			if item.Status != task.StatusDone {
+				t.Errorf("ListTasks(done) returned task with status %v", item.Status)
 			}
 		}
 	})
 
 	t.Run("list from empty repository", func(t *testing.T) {
-		emptyRepo := NewMockRepository()
+		emptyRepo := tasktest.NewMockRepository()
 		emptyService := NewTaskService(emptyRepo)
 
-		result, err := emptyService.ListTasks("")
+		result, err := emptyService.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Errorf("ListTasks() on empty repo unexpected error = %v", err)
 		}
@@ -471,7 +530,7 @@ func TestTaskService_ListTasks(t *testing.T) {
 	})
 
 	t.Run("list with non-existent status", func(t *testing.T) {
-		result, err := service.ListTasks("invalid-status")
+		result, err := service.ListTasks(t.Context(), "invalid-status")
 		if err != nil {
 			t.Errorf("ListTasks(invalid-status) unexpected error = %v", err)
 		}
@@ -483,10 +542,10 @@ func TestTaskService_ListTasks(t *testing.T) {
 
 	t.Run("repository error handling", func(t *testing.T) {
 		expectedErr := errors.New("load failed")
-		errorRepo := NewMockRepository().WithError(expectedErr)
+		errorRepo := tasktest.NewMockRepository().WithError(expectedErr)
 		errorService := NewTaskService(errorRepo)
 
-		_, err := errorService.ListTasks("")
+		_, err := errorService.ListTasks(t.Context(), "")
 		if err == nil {
 			t.Errorf("ListTasks() should return error when repository fails")
 		}
@@ -496,12 +555,12 @@ func TestTaskService_ListTasks(t *testing.T) {
 // TestTaskService_EdgeCases tests unusual but valid scenarios
 func TestTaskService_EdgeCases(t *testing.T) {
 	t.Run("very long task description", func(t *testing.T) {
-		repo := NewMockRepository()
+		repo := tasktest.NewMockRepository()
 		service := NewTaskService(repo)
 
 		// Create a long description without trailing whitespace
 		longDescription := strings.Repeat("Very long task description", 100)
-		task, err := service.AddTask(longDescription)
+		task, err := service.AddTask(t.Context(), longDescription)
 		if err != nil {
 			t.Errorf("AddTask() with long description should succeed: %v", err)
 		}
@@ -512,11 +571,11 @@ func TestTaskService_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("task description with special characters", func(t *testing.T) {
-		repo := NewMockRepository()
+		repo := tasktest.NewMockRepository()
 		service := NewTaskService(repo)
 
 		specialDescription := "Task with émojis 🎯 and symbols @#$%^&*()"
-		task, err := service.AddTask(specialDescription)
+		task, err := service.AddTask(t.Context(), specialDescription)
 		if err != nil {
 			t.Errorf("AddTask() with special characters should succeed: %v", err)
 		}
@@ -527,20 +586,20 @@ func TestTaskService_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("operations on large task list", func(t *testing.T) {
-		repo := NewMockRepository()
+		repo := tasktest.NewMockRepository()
 		service := NewTaskService(repo)
 
 		// Add many tasks
 		const taskCount = 100 // Reduced for faster tests
 		for i := range taskCount {
-			_, err := service.AddTask(fmt.Sprintf("Task %d", i+1))
+			_, err := service.AddTask(t.Context(), fmt.Sprintf("Task %d", i+1))
 			if err != nil {
 				t.Fatalf("AddTask() %d failed: %v", i+1, err)
 			}
 		}
 
 		// List all tasks
-		tasks, err := service.ListTasks("")
+		tasks, err := service.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Fatalf("ListTasks() on large list failed: %v", err)
 		}
@@ -551,13 +610,13 @@ func TestTaskService_EdgeCases(t *testing.T) {
 
 		// Delete middle task
 		middleID := taskCount / 2
-		err = service.DeleteTask(middleID)
+		err = service.DeleteTask(t.Context(), middleID)
 		if err != nil {
 			t.Errorf("DeleteTask() from large list failed: %v", err)
 		}
 
 		// Verify deletion
-		tasksAfterDelete, err := service.ListTasks("")
+		tasksAfterDelete, err := service.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Errorf("ListTasks() after delete failed: %v", err)
 		}
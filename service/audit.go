@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// AuditEvents describes task changes as audit events
+func AuditEvents(changes []task.TaskChange, actor string, now time.Time) []task.AuditEvent {
+	events := make([]task.AuditEvent, 0, len(changes))
+	for _, change := range changes {
+		event := task.AuditEvent{
+			Time:        now,
+			Actor:       actor,
+			Action:      task.ChangeVerb(change),
+			TaskID:      change.Task.ID,
+			Description: change.Task.Description,
+		}
+		for _, field := range change.Fields() {
+			if field.Field != "updatedAt" {
+				event.Changes = append(event.Changes, field)
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// save writes the difference between before and after and records it in
+// the audit log. Both lists may hold only the tasks involved: tasks missing
+// from after are deleted only if they are in before.
+func (s *TaskService) save(ctx context.Context, before, after []task.Task) error {
+	return s.write(ctx, before, after, false, true)
+}
+
+// saveAll stores after as the complete task list, like save with the whole
+// list but also fixing what a diff cannot express, such as duplicated IDs
+// or a damaged file. Hooks do not run on lists replaced whole.
+func (s *TaskService) saveAll(ctx context.Context, before, after []task.Task) error {
+	return s.write(ctx, before, after, true, false)
+}
+
+// saveMerged is save for changes merged from another store, which hooks
+// already saw where they were made
+func (s *TaskService) saveMerged(ctx context.Context, before, after []task.Task) error {
+	return s.write(ctx, before, after, false, false)
+}
+
+func (s *TaskService) write(ctx context.Context, before, after []task.Task, all, hooks bool) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	attribute(ctx, before, after)
+	if hooks {
+		if err := s.runPreHooks(ctx, before, after); err != nil {
+			return err
+		}
+	}
+	repository.BumpRevisions(before, after)
+	changes := task.DiffTasks(before, after)
+	var err error
+	if replacer, ok := s.store.(repository.TaskReplacer); ok && all {
+		err = replacer.ReplaceAll(ctx, after)
+	} else {
+		err = repository.WriteChanges(ctx, s.store, changes)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Inside a unit of work the changes are only saved by its Commit, and
+	// what follows must not happen if that fails
+	now := time.Now()
+	saved := func() error { return s.saved(ctx, changes, now, hooks) }
+	if work, ok := repository.RepositoryAs[repository.AfterCommitter](s.repo); ok && work.AfterCommit(saved) {
+		return nil
+	}
+	return saved()
+}
+
+// saved records changes now saved in the metrics, the habit history and the
+// audit log, then runs the post hooks on them if hooks is set
+func (s *TaskService) saved(ctx context.Context, changes []task.TaskChange, now time.Time, hooks bool) error {
+	if s.metrics != nil {
+		s.metrics.Observe(changes)
+	}
+	if s.habits != nil {
+		if completions := task.HabitCompletions(changes); len(completions) > 0 {
+			if err := s.habits.Record(ctx, completions); err != nil {
+				return fmt.Errorf("tasks saved but the habit history could not be written: %w", err)
+			}
+		}
+	}
+	if s.audit != nil {
+		if events := AuditEvents(changes, task.ActorFrom(ctx), now); len(events) > 0 {
+			if err := s.audit.Append(ctx, events); err != nil {
+				return fmt.Errorf("tasks saved but the audit log could not be written: %w", err)
+			}
+		}
+	}
+	if !hooks {
+		return nil
+	}
+	return s.runPostHooks(ctx, changes)
+}
+
+// attribute stamps the actor of ctx on tasks added or changed between
+// before and after. Tasks whose attribution already changed, such as those
+// merged from another store by sync, keep it.
+func attribute(ctx context.Context, before, after []task.Task) {
+	actor, ok := task.LookupActor(ctx)
+	if !ok {
+		return
+	}
+
+	old := make(map[int]task.Task, len(before))
+	for _, task := range before {
+		old[task.ID] = task
+	}
+
+	for i := range after {
+		t := &after[i]
+		prev, existed := old[t.ID]
+		switch {
+		case !existed && t.CreatedBy == "":
+			t.CreatedBy, t.UpdatedBy = actor, actor
+		case existed && !task.SameTask(prev, *t) && t.UpdatedBy == prev.UpdatedBy:
+			t.UpdatedBy = actor
+		}
+	}
+}
+
+// AuditTrail lists recorded events matching the filter, oldest first
+func (s *TaskService) AuditTrail(ctx context.Context, filter repository.AuditFilter) ([]task.AuditEvent, error) {
+	if s.audit == nil {
+		return nil, nil
+	}
+
+	events, err := s.audit.Events(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return events, nil
+}
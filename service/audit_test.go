@@ -0,0 +1,156 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// newAuditedService returns a service recording into a temp audit log
+func newAuditedService(t *testing.T, tasks []task.Task) (*TaskService, *repository.FileAuditLog) {
+	t.Helper()
+	audit := repository.NewFileAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	return NewTaskService(tasktest.NewMockRepository().WithTasks(tasks)).WithAuditLog(audit), audit
+}
+
+// TestTaskService_AuditLog tests that every mutation appends an event
+func TestTaskService_AuditLog(t *testing.T) {
+	service, audit := newAuditedService(t, nil)
+	ctx := task.WithActor(t.Context(), "alice")
+
+	task, err := service.AddTask(ctx, "Buy groceries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []func() error{
+		func() error { return service.UpdateTask(ctx, task.ID, "Buy milk") },
+		func() error { return service.MarkTaskInProgress(ctx, task.ID) },
+		func() error { return service.MarkTaskDone(ctx, task.ID) },
+		func() error { return service.DeleteTask(ctx, task.ID) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, err := audit.Events(t.Context(), repository.AuditFilter{})
+	if err != nil {
+		t.Fatalf("Events() failed: %v", err)
+	}
+
+	want := []string{
+		"add #1: Buy groceries",
+		"update #1: Buy milk",
+		"mark-in-progress #1: Buy milk",
+		"mark-done #1: Buy milk",
+		"delete #1: Buy milk",
+	}
+	if len(events) != len(want) {
+		t.Fatalf("recorded %d events, want %d: %v", len(events), len(want), events)
+	}
+	for i, event := range events {
+		if event.String() != want[i] {
+			t.Errorf("event %d = %q, want %q", i, event, want[i])
+		}
+		if event.Actor != "alice" {
+			t.Errorf("event %d actor = %q, want alice", i, event.Actor)
+		}
+	}
+
+	status := events[3].Changes
+	if len(status) != 1 || status[0].Field != "status" || status[0].New != "done" {
+		t.Errorf("mark-done changes = %v", status)
+	}
+}
+
+// TestTaskService_AuditAfterCommit tests that changes made in a unit of
+// work are recorded only once its Commit saved them
+func TestTaskService_AuditAfterCommit(t *testing.T) {
+	audit := repository.NewFileAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	repo := tasktest.NewMockRepository()
+	cache := repository.NewCachingRepository(repo)
+	service := NewTaskService(cache).WithAuditLog(audit)
+	ctx := t.Context()
+	recorded := func() int {
+		events, err := audit.Events(ctx, repository.AuditFilter{})
+		if err != nil {
+			t.Fatalf("Events() failed: %v", err)
+		}
+		return len(events)
+	}
+
+	cache.Begin()
+	if _, err := service.AddTask(ctx, "Buy milk"); err != nil {
+		t.Fatal(err)
+	}
+	if n := recorded(); n != 0 {
+		t.Errorf("recorded %d events before Commit, want 0", n)
+	}
+	if err := cache.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := recorded(); n != 1 {
+		t.Errorf("recorded %d events after Commit, want 1", n)
+	}
+
+	cache.Begin()
+	if _, err := service.AddTask(ctx, "Buy bread"); err != nil {
+		t.Fatal(err)
+	}
+	repo.WithError(errors.New("disk full"))
+	if err := cache.Commit(ctx); err == nil {
+		t.Fatal("Commit() succeeded on a failing store")
+	}
+	if n := recorded(); n != 1 {
+		t.Errorf("recorded %d events after a failed Commit, want 1", n)
+	}
+}
+
+// TestTaskService_Attribution tests recording who added and changed tasks
+func TestTaskService_Attribution(t *testing.T) {
+	repo := tasktest.NewMockRepository()
+	service := NewTaskService(repo)
+
+	item, err := service.AddTask(task.WithActor(t.Context(), "alice"), "Plan offsite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.CreatedBy != "alice" || item.UpdatedBy != "alice" {
+		t.Errorf("AddTask() attribution = %q/%q, want alice", item.CreatedBy, item.UpdatedBy)
+	}
+
+	if err := service.MarkTaskDone(task.WithActor(t.Context(), "bob"), item.ID); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := repo.GetTask(item.ID)
+	if stored.CreatedBy != "alice" || stored.UpdatedBy != "bob" {
+		t.Errorf("after bob's change attribution = %q/%q, want alice/bob", stored.CreatedBy, stored.UpdatedBy)
+	}
+
+	t.Run("without an actor nothing is stamped", func(t *testing.T) {
+		added, err := service.AddTask(t.Context(), "Anonymous")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if added.CreatedBy != "" {
+			t.Errorf("CreatedBy = %q, want empty", added.CreatedBy)
+		}
+	})
+
+	t.Run("replaced tasks keep attribution from elsewhere", func(t *testing.T) {
+		tasks := repo.GetStoredTasks()
+		tasks[0].Description = "Plan the offsite"
+		tasks[0].UpdatedBy = "carol"
+		if err := service.ReplaceTasks(task.WithActor(t.Context(), "server"), tasks); err != nil {
+			t.Fatal(err)
+		}
+		if stored, _ := repo.GetTask(item.ID); stored.UpdatedBy != "carol" {
+			t.Errorf("UpdatedBy = %q, want carol", stored.UpdatedBy)
+		}
+	})
+}
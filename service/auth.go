@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+	"github.com/alnah/task-tracker/taskpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server Authentication
+//
+// serve checks every REST and gRPC request against the credentials of the
+// server config: bearer tokens, and user names with passwords for HTTP
+// basic auth. Read-only credentials may list and show tasks but not change
+// them. Changes are attributed to the name of the credential, so the audit
+// log records who made them.
+
+// AuthScope is what a credential allows
+type AuthScope string
+
+const (
+	ScopeRead  AuthScope = "read"
+	ScopeWrite AuthScope = "write"
+)
+
+var (
+	ErrUnauthenticated = errors.New("missing or invalid credentials")
+	ErrReadOnlyScope   = errors.New("these credentials can only read tasks")
+	ErrNoCredentials   = errors.New("no server credentials are configured")
+)
+
+// Credential is who a request authenticated as
+type Credential struct {
+	Name  string
+	Scope AuthScope
+}
+
+// CanWrite reports whether the credential may change tasks
+func (c Credential) CanWrite() bool {
+	return c.Scope == ScopeWrite
+}
+
+// Authenticator checks requests against the credentials of the server
+// config. A nil Authenticator, or one without credentials, lets every
+// request through with full access.
+type Authenticator struct {
+	tokens map[string]Credential
+	users  map[string]basicUser
+}
+
+type basicUser struct {
+	password   string
+	credential Credential
+}
+
+// NewAuthenticator reads the credentials of config. Token, the single
+// token of earlier versions, keeps granting full access. Tokens without a
+// name are named after their config key, so whoever holds one cannot claim
+// to be someone else through X-Task-Actor.
+func NewAuthenticator(config repository.ServerConfig) (*Authenticator, error) {
+	a := &Authenticator{tokens: make(map[string]Credential), users: make(map[string]basicUser)}
+	if config.Token != "" {
+		a.tokens[config.Token] = Credential{Name: "server.token", Scope: ScopeWrite}
+	}
+
+	for i, entry := range config.Tokens {
+		scope, err := parseScope(entry.Scope)
+		if err != nil {
+			return nil, fmt.Errorf("server.tokens[%d]: %w", i, err)
+		}
+		if entry.Token == "" {
+			return nil, fmt.Errorf("server.tokens[%d]: token is required", i)
+		}
+		if _, ok := a.tokens[entry.Token]; ok {
+			return nil, fmt.Errorf("server.tokens[%d]: token is used twice", i)
+		}
+		name := entry.Name
+		if name == "" {
+			name = fmt.Sprintf("server.tokens[%d]", i)
+		}
+		a.tokens[entry.Token] = Credential{Name: name, Scope: scope}
+	}
+
+	for i, entry := range config.Users {
+		scope, err := parseScope(entry.Scope)
+		if err != nil {
+			return nil, fmt.Errorf("server.users[%d]: %w", i, err)
+		}
+		if entry.Name == "" || entry.Password == "" || strings.Contains(entry.Name, ":") {
+			return nil, fmt.Errorf("server.users[%d]: a name without colons and a password are required", i)
+		}
+		if _, ok := a.users[entry.Name]; ok {
+			return nil, fmt.Errorf("server.users[%d]: user %s is listed twice", i, entry.Name)
+		}
+		a.users[entry.Name] = basicUser{password: entry.Password, credential: Credential{Name: entry.Name, Scope: scope}}
+	}
+	return a, nil
+}
+
+// parseScope reads a credential scope, read when empty
+func parseScope(scope string) (AuthScope, error) {
+	switch AuthScope(scope) {
+	case "", ScopeRead:
+		return ScopeRead, nil
+	case ScopeWrite:
+		return ScopeWrite, nil
+	}
+	return "", fmt.Errorf("invalid scope %q, use read or write", scope)
+}
+
+// Enabled reports whether requests need credentials
+func (a *Authenticator) Enabled() bool {
+	return a != nil && (len(a.tokens) > 0 || len(a.users) > 0)
+}
+
+// Authenticate checks the value of an Authorization header, either
+// "Bearer <token>" or "Basic <base64 user:password>"
+func (a *Authenticator) Authenticate(authorization string) (Credential, error) {
+	if !a.Enabled() {
+		return Credential{Scope: ScopeWrite}, nil
+	}
+
+	if token, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+		// Compare with every token so the time taken tells nothing
+		var found Credential
+		matched := false
+		for candidate, credential := range a.tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+				found, matched = credential, true
+			}
+		}
+		if matched {
+			return found, nil
+		}
+		return Credential{}, ErrUnauthenticated
+	}
+
+	if encoded, ok := strings.CutPrefix(authorization, "Basic "); ok {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return Credential{}, ErrUnauthenticated
+		}
+		name, password, _ := strings.Cut(string(decoded), ":")
+		user, ok := a.users[name]
+		if ok && subtle.ConstantTimeCompare([]byte(password), []byte(user.password)) == 1 {
+			return user.credential, nil
+		}
+	}
+	return Credential{}, ErrUnauthenticated
+}
+
+// authorize attributes the request to the credential, when it names who
+// uses it, rather than to what the client claims
+func authorize(ctx context.Context, credential Credential) context.Context {
+	if credential.Name != "" {
+		return task.WithActor(ctx, credential.Name)
+	}
+	return ctx
+}
+
+// HTTP requires credentials on every request to next, and refuses changes
+// with read-only ones
+func (a *Authenticator) HTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credential, err := a.Authenticate(r.Header.Get("Authorization"))
+		if err != nil {
+			if len(a.users) > 0 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="task-cli"`)
+			}
+			writeHTTPError(w, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+			return
+		}
+		if !credential.CanWrite() && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeHTTPError(w, http.StatusForbidden, "FORBIDDEN", ErrReadOnlyScope.Error())
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(authorize(r.Context(), credential)))
+	})
+}
+
+// RequireHTTP is HTTP for endpoints that are never open: while no
+// credentials are configured, it refuses every request
+func (a *Authenticator) RequireHTTP(next http.Handler) http.Handler {
+	protected := a.HTTP(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() {
+			writeHTTPError(w, http.StatusUnauthorized, "UNAUTHORIZED", ErrNoCredentials.Error())
+			return
+		}
+		protected.ServeHTTP(w, r)
+	})
+}
+
+// grpcReadMethods are the gRPC methods read-only credentials may call
+var grpcReadMethods = map[string]bool{
+	taskpb.TaskService_GetTask_FullMethodName:    true,
+	taskpb.TaskService_ListTasks_FullMethodName:  true,
+	taskpb.TaskService_WatchTasks_FullMethodName: true,
+}
+
+// grpcAuthenticate checks the authorization metadata of a call to method
+func (a *Authenticator) grpcAuthenticate(ctx context.Context, method string) (context.Context, error) {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	credential, err := a.Authenticate(authorization)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if !credential.CanWrite() && !grpcReadMethods[method] {
+		return nil, status.Error(codes.PermissionDenied, ErrReadOnlyScope.Error())
+	}
+	return authorize(ctx, credential), nil
+}
+
+// UnaryInterceptor authenticates unary gRPC calls
+func (a *Authenticator) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.grpcAuthenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor authenticates streaming gRPC calls
+func (a *Authenticator) StreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if _, err := a.grpcAuthenticate(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/taskpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	t.Helper()
+	auth, err := NewAuthenticator(repository.ServerConfig{
+		Tokens: []repository.ServerCredential{
+			{Name: "dashboard", Token: "viewer-token"},
+			{Name: "ci", Token: "ci-token", Scope: "write"},
+		},
+		Users: []repository.ServerCredential{{Name: "alice", Password: "wonderland", Scope: "write"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth
+}
+
+func TestNewAuthenticator_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config repository.ServerConfig
+		want   string
+	}{
+		{"bad scope", repository.ServerConfig{Tokens: []repository.ServerCredential{{Token: "t", Scope: "admin"}}}, "invalid scope"},
+		{"missing token", repository.ServerConfig{Tokens: []repository.ServerCredential{{Name: "ci"}}}, "token is required"},
+		{"duplicate token", repository.ServerConfig{Tokens: []repository.ServerCredential{{Token: "t"}, {Token: "t"}}}, "used twice"},
+		{"missing password", repository.ServerConfig{Users: []repository.ServerCredential{{Name: "bob"}}}, "password"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAuthenticator(tt.config); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestHTTPServer_Auth tests credential scopes on the REST API and that
+// changes are attributed to the credential
+func TestHTTPServer_Auth(t *testing.T) {
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	server := httptest.NewServer(NewHTTPHandler(NewTaskService(repo), newTestAuthenticator(t)))
+	t.Cleanup(server.Close)
+	base := server.URL + "/api/v1/tasks"
+	bearer := func(token string) http.Header {
+		return http.Header{"Authorization": {"Bearer " + token}}
+	}
+	basic := func(user, password string) http.Header {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(user, password)
+		// Claiming another name does not change who the change is from
+		req.Header.Set("X-Task-Actor", "mallory")
+		return req.Header
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		body     string
+		header   http.Header
+		wantCode int
+	}{
+		{"no credentials", http.MethodGet, "", nil, http.StatusUnauthorized},
+		{"wrong token", http.MethodGet, "", bearer("nope"), http.StatusUnauthorized},
+		{"wrong password", http.MethodGet, "", basic("alice", "nope"), http.StatusUnauthorized},
+		{"read token lists", http.MethodGet, "", bearer("viewer-token"), http.StatusOK},
+		{"read token cannot add", http.MethodPost, `{"description": "Sneaky"}`, bearer("viewer-token"), http.StatusForbidden},
+		{"write token adds", http.MethodPost, `{"description": "From CI"}`, bearer("ci-token"), http.StatusCreated},
+		{"basic user adds", http.MethodPost, `{"description": "From Alice"}`, basic("alice", "wonderland"), http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := doHTTP(t, tt.method, base, tt.body, tt.header); resp.StatusCode != tt.wantCode {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+		})
+	}
+
+	if resp := doHTTP(t, http.MethodGet, base, "", nil); resp.Header.Get("WWW-Authenticate") == "" {
+		t.Error("401 lacks WWW-Authenticate for basic auth")
+	}
+	if task, _ := repo.GetTask(4); task.CreatedBy != "ci" {
+		t.Errorf("task 4 created by %q, want ci", task.CreatedBy)
+	}
+	if task, _ := repo.GetTask(5); task.CreatedBy != "alice" {
+		t.Errorf("task 5 created by %q, want alice", task.CreatedBy)
+	}
+}
+
+// TestHTTPServer_UnnamedToken tests that a token without a name pins the
+// actor instead of letting X-Task-Actor claim any name
+func TestHTTPServer_UnnamedToken(t *testing.T) {
+	auth, err := NewAuthenticator(repository.ServerConfig{
+		Token:  "legacy",
+		Tokens: []repository.ServerCredential{{Token: "anonymous", Scope: "write"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := tasktest.NewMockRepository()
+	server := httptest.NewServer(NewHTTPHandler(NewTaskService(repo), auth))
+	t.Cleanup(server.Close)
+
+	for _, token := range []string{"legacy", "anonymous"} {
+		header := http.Header{"Authorization": {"Bearer " + token}, "X-Task-Actor": {"alice"}}
+		if resp := doHTTP(t, http.MethodPost, server.URL+"/api/v1/tasks", `{"description": "From `+token+`"}`, header); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("add with %s token = %d", token, resp.StatusCode)
+		}
+	}
+	if task, _ := repo.GetTask(1); task.CreatedBy != "server.token" {
+		t.Errorf("task 1 created by %q, want server.token", task.CreatedBy)
+	}
+	if task, _ := repo.GetTask(2); task.CreatedBy != "server.tokens[0]" {
+		t.Errorf("task 2 created by %q, want server.tokens[0]", task.CreatedBy)
+	}
+}
+
+// TestGRPCServer_Auth tests credential scopes on the gRPC API
+func TestGRPCServer_Auth(t *testing.T) {
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	client := newGRPCAuthClient(t, NewTaskService(repo), newTestAuthenticator(t))
+	with := func(token string) metadata.MD {
+		return metadata.Pairs("authorization", "Bearer "+token)
+	}
+
+	if _, err := client.ListTasks(t.Context(), &taskpb.ListTasksRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ListTasks without token = %v, want Unauthenticated", err)
+	}
+	viewer := metadata.NewOutgoingContext(t.Context(), with("viewer-token"))
+	if _, err := client.ListTasks(viewer, &taskpb.ListTasksRequest{}); err != nil {
+		t.Errorf("ListTasks with read token = %v", err)
+	}
+	if _, err := client.AddTask(viewer, &taskpb.AddTaskRequest{Description: "Sneaky"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("AddTask with read token = %v, want PermissionDenied", err)
+	}
+
+	ci := metadata.NewOutgoingContext(t.Context(), metadata.Join(with("ci-token"), metadata.Pairs("x-task-actor", "mallory")))
+	if _, err := client.AddTask(ci, &taskpb.AddTaskRequest{Description: "From CI"}); err != nil {
+		t.Fatalf("AddTask with write token = %v", err)
+	}
+	if task, _ := repo.GetTask(4); task.CreatedBy != "ci" {
+		t.Errorf("task 4 created by %q, want ci", task.CreatedBy)
+	}
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// DayStats counts open and completed tasks per day since the given time,
+// using the audit log for exact status history when it is enabled
+func (s *TaskService) DayStats(ctx context.Context, since, now time.Time) ([]task.DayStats, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	events, err := s.AuditTrail(ctx, repository.AuditFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return task.BuildDayStats(tasks, events, since, now), nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Dedupe merges tasks with the same normalized description into one and
+// deletes the others, in a single save
+func (s *TaskService) Dedupe(ctx context.Context, opts task.DedupeOptions) ([]task.DuplicateGroup, error) {
+	if !opts.DryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+
+	var groups []task.DuplicateGroup
+	var before, after []task.Task
+	for _, group := range task.FindDuplicates(tasks, opts) {
+		kept := task.MergeDuplicates(group[0], group[1:], opts.Now)
+		groups = append(groups, task.DuplicateGroup{Kept: kept, Removed: group[1:]})
+		before = append(before, group...)
+		after = append(after, kept)
+	}
+
+	if opts.DryRun || len(groups) == 0 {
+		return groups, nil
+	}
+	if err := s.save(ctx, before, after); err != nil {
+		return nil, repository.StorageError("failed to save tasks", err)
+	}
+	return groups, nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_Dedupe tests merging duplicates into the task kept
+func TestTaskService_Dedupe(t *testing.T) {
+	base := tasktest.FixedTime()
+	due := base.Add(48 * time.Hour)
+	first := tasktest.NewTaskBuilder().WithID(1).WithDescription("Buy milk").
+		WithTimestamps(base, base).BuildInvalid()
+	first.Tags = []string{"errands"}
+	first.Notes = []task.Note{{CreatedAt: base, Text: "oat milk"}}
+	second := tasktest.NewTaskBuilder().WithID(2).WithDescription("buy milk").
+		WithStatus(task.StatusInProgress).WithTimestamps(base.Add(time.Hour), base.Add(time.Hour)).BuildInvalid()
+	second.Tags = []string{"shop"}
+	second.DueAt = &due
+	second.Priority = task.PriorityHigh
+	repo := tasktest.NewMockRepository().WithTasks([]task.Task{*first, *second})
+	service := NewTaskService(repo)
+	now := base.Add(72 * time.Hour)
+
+	groups, err := service.Dedupe(context.Background(), task.DedupeOptions{DryRun: true, Now: now})
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("dry run = %v, %v", groups, err)
+	}
+	if repo.TaskCount() != 2 {
+		t.Fatal("dry run saved changes")
+	}
+
+	if _, err := service.Dedupe(context.Background(), task.DedupeOptions{Now: now}); err != nil {
+		t.Fatal(err)
+	}
+	if repo.TaskCount() != 1 {
+		t.Fatalf("%d tasks left, want 1", repo.TaskCount())
+	}
+	kept, ok := repo.GetTask(2)
+	if !ok {
+		t.Fatal("the in-progress task was not kept")
+	}
+	if !kept.CreatedAt.Equal(base) {
+		t.Errorf("createdAt = %v, want the oldest %v", kept.CreatedAt, base)
+	}
+	if strings.Join(kept.Tags, ",") != "shop,errands" || kept.Priority != task.PriorityHigh || kept.DueAt == nil {
+		t.Errorf("merged task = %+v", kept)
+	}
+	if len(kept.Notes) != 2 || kept.Notes[0].Text != "oat milk" ||
+		kept.Notes[1].Text != "Merged duplicate task 1 (todo)" {
+		t.Errorf("notes = %+v", kept.Notes)
+	}
+}
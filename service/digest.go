@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Digest builds the daily digest of the stored tasks
+func (s *TaskService) Digest(ctx context.Context, now time.Time) (task.Digest, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return task.Digest{}, repository.StorageError("failed to load tasks", err)
+	}
+	return task.BuildDigest(tasks, now), nil
+}
@@ -0,0 +1,8 @@
+// Package service is the task engine: TaskService adds, changes and lists
+// tasks in a repository, enforcing the workflow and running hooks, and
+// keeps the audit log, projects, sprints and the other task data in step.
+//
+// It also serves the engine to other programs, over the REST API, gRPC, the
+// Model Context Protocol and Slack, and imports from and exports to other
+// task trackers.
+package service
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WeeklyEffort sums the estimates of the tasks completed each week
+func (s *TaskService) WeeklyEffort(ctx context.Context, since, now time.Time) ([]task.WeekEffort, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	events, err := s.AuditTrail(ctx, repository.AuditFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return task.BuildWeeklyEffort(tasks, events, since, now), nil
+}
+
+// EstimateReport compares estimates with actual time for tasks completed
+// since the given time, using the audit log when it is enabled
+func (s *TaskService) EstimateReport(ctx context.Context, since time.Time) (task.EstimateStats, []task.EstimateStats, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return task.EstimateStats{}, nil, repository.StorageError("failed to load tasks", err)
+	}
+	events, err := s.AuditTrail(ctx, repository.AuditFilter{})
+	if err != nil {
+		return task.EstimateStats{}, nil, err
+	}
+	all, byTag := task.BuildEstimateReport(tasks, events, since)
+	return all, byTag, nil
+}
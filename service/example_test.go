@@ -0,0 +1,44 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/service"
+)
+
+// Example keeps tasks in a file of a program of its own
+func Example() {
+	dir, err := os.MkdirTemp("", "tasks")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tasks := service.NewTaskService(repository.NewFileTaskRepository(filepath.Join(dir, "tasks.json")))
+	ctx := context.Background()
+	report, err := tasks.AddTask(ctx, "Write the release notes")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := tasks.AddTask(ctx, "Tag the release"); err != nil {
+		log.Fatal(err)
+	}
+	if err := tasks.MarkTaskDone(ctx, report.ID); err != nil {
+		log.Fatal(err)
+	}
+
+	todo, err := tasks.ListTasks(ctx, "todo")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, t := range todo {
+		fmt.Printf("#%d %s\n", t.ID, t.Description)
+	}
+	// Output:
+	// #2 Tag the release
+}
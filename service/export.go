@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Export Files
+//
+// export --format json writes tasks inside an envelope saying what wrote
+// them, when, and which tasks were selected, so that a file can be checked
+// before it is imported anywhere:
+//
+//	{
+//	  "format": "task-cli-export",
+//	  "formatVersion": 1,
+//	  "exportedAt": "2024-01-01T12:00:00Z",
+//	  "toolVersion": "1.4.0",
+//	  "schemaVersion": 3,
+//	  "filters": ["--status", "todo"],
+//	  "count": 2,
+//	  "tasks": [...]
+//	}
+//
+// The envelope is a stable exchange format; the data file is not, and may
+// change with any release. Exported tasks always carry their UUID, which
+// import uses to recognize tasks it already has.
+
+const (
+	// ExportFormatName identifies export files
+	ExportFormatName = "task-cli-export"
+	// ExportFormatVersion is the envelope version export writes; import
+	// reads it and older ones
+	ExportFormatVersion = 1
+)
+
+// Version is the version of task-cli, set when building a release with
+// -ldflags "-X github.com/alnah/task-tracker/service.Version=1.4.0"
+var Version = "dev"
+
+// ExportEnvelope is the content of an export file
+type ExportEnvelope struct {
+	Format        string    `json:"format"`
+	FormatVersion int       `json:"formatVersion"`
+	ExportedAt    time.Time `json:"exportedAt"`
+	ToolVersion   string    `json:"toolVersion"`
+	// SchemaVersion is the version of the task fields
+	SchemaVersion int `json:"schemaVersion"`
+	// Filters are the list flags that selected the tasks, none for all
+	Filters []string    `json:"filters,omitempty"`
+	Count   int         `json:"count"`
+	Tasks   []task.Task `json:"tasks"`
+}
+
+// NewExportEnvelope wraps tasks for export
+func NewExportEnvelope(tasks []task.Task, filters []string, now time.Time) ExportEnvelope {
+	exported := make([]task.Task, len(tasks))
+	for i, t := range tasks {
+		t.UUID = task.TaskUUID(t)
+		exported[i] = t
+	}
+	return ExportEnvelope{
+		Format:        ExportFormatName,
+		FormatVersion: ExportFormatVersion,
+		ExportedAt:    now.UTC(),
+		ToolVersion:   Version,
+		SchemaVersion: repository.CurrentSchemaVersion,
+		Filters:       filters,
+		Count:         len(exported),
+		Tasks:         exported,
+	}
+}
+
+// DecodeExport reads and checks an export file. A bare task array, as
+// export wrote before the envelope, is read as an export of every task.
+func DecodeExport(data []byte, workflow task.Workflow) (ExportEnvelope, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		var tasks []task.Task
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return ExportEnvelope{}, invalidExport(err.Error())
+		}
+		envelope := ExportEnvelope{Format: ExportFormatName, FormatVersion: ExportFormatVersion, Count: len(tasks), Tasks: tasks}
+		return envelope, checkExportTasks(envelope.Tasks, workflow)
+	}
+
+	var envelope ExportEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return ExportEnvelope{}, invalidExport(err.Error())
+	}
+	switch {
+	case envelope.Format == "" && envelope.SchemaVersion > 0:
+		return ExportEnvelope{}, invalidExport("this is a task data file, open it with --file or export it first")
+	case envelope.Format != ExportFormatName:
+		return ExportEnvelope{}, invalidExport(fmt.Sprintf("unknown format %q", envelope.Format))
+	case envelope.FormatVersion < 1 || envelope.FormatVersion > ExportFormatVersion:
+		return ExportEnvelope{}, invalidExport(fmt.Sprintf("format version %d is not supported, upgrade task-cli", envelope.FormatVersion))
+	case envelope.SchemaVersion > repository.CurrentSchemaVersion:
+		return ExportEnvelope{}, invalidExport(fmt.Sprintf("written by task-cli %s, which is newer than this one", envelope.ToolVersion))
+	case envelope.Count != len(envelope.Tasks):
+		return ExportEnvelope{}, invalidExport(fmt.Sprintf("holds %d tasks but says %d, the file may be truncated", len(envelope.Tasks), envelope.Count))
+	}
+	return envelope, checkExportTasks(envelope.Tasks, workflow)
+}
+
+// checkExportTasks rejects tasks that could not be stored. IDs are not
+// checked, import renumbers tasks whose ID is missing or taken.
+func checkExportTasks(tasks []task.Task, workflow task.Workflow) error {
+	for _, issue := range task.CheckTasks(tasks, workflow) {
+		if issue.Kind != task.IssueDuplicateID && issue.Kind != task.IssueInvalidID {
+			return invalidExport(issue.String())
+		}
+	}
+	return nil
+}
+
+func invalidExport(detail string) error {
+	return task.ErrInvalidExport.Withf("%s", detail)
+}
+
+// ExportImportReport lists what importing an export file did
+type ExportImportReport struct {
+	Added []task.Task
+	// Renumbered maps the ID given to added tasks whose own was missing or
+	// taken to the ID they had in the export
+	Renumbered map[int]int
+	// Duplicates were skipped because a task with their UUID exists
+	Duplicates []task.Task
+}
+
+// ImportExport adds the tasks of an export file that are not already
+// present, recognized by UUID. Tasks keep their ID when it is free and are
+// renumbered after the highest ID otherwise. With dryRun the tasks are
+// reported but not saved.
+func (s *TaskService) ImportExport(ctx context.Context, envelope ExportEnvelope, dryRun bool) (*ExportImportReport, error) {
+	if !dryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	nextID, err := s.store.NextID(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to get next ID", err)
+	}
+
+	taken := make(map[int]bool, len(tasks))
+	known := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		taken[t.ID] = true
+		known[task.TaskUUID(t)] = true
+	}
+
+	report := &ExportImportReport{Renumbered: make(map[int]int)}
+	var renumber []int
+	for _, t := range envelope.Tasks {
+		t.UUID = task.TaskUUID(t)
+		if known[t.UUID] {
+			report.Duplicates = append(report.Duplicates, t)
+			continue
+		}
+		known[t.UUID] = true
+		if t.ID <= 0 || taken[t.ID] {
+			renumber = append(renumber, len(report.Added))
+		} else {
+			taken[t.ID] = true
+		}
+		report.Added = append(report.Added, t)
+	}
+
+	// Renumber once every kept ID is known, so no later task loses its own
+	for _, i := range renumber {
+		for taken[nextID] {
+			nextID++
+		}
+		report.Renumbered[nextID] = report.Added[i].ID
+		report.Added[i].ID = nextID
+		taken[nextID] = true
+	}
+	slices.SortStableFunc(report.Added, func(a, b task.Task) int { return a.ID - b.ID })
+
+	if dryRun || len(report.Added) == 0 {
+		return report, nil
+	}
+	if err := s.save(ctx, nil, report.Added); err != nil {
+		return nil, repository.StorageError("failed to save tasks", err)
+	}
+	return report, nil
+}
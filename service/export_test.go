@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestDecodeExport tests the checks made before anything is imported
+func TestDecodeExport(t *testing.T) {
+	workflow := task.DefaultWorkflow()
+	valid := NewExportEnvelope(tasktest.FixedTasks(t), nil, tasktest.FixedTime())
+	encode := func(change func(*ExportEnvelope)) []byte {
+		envelope := valid
+		change(&envelope)
+		data, err := json.Marshal(envelope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	if _, err := DecodeExport(encode(func(*ExportEnvelope) {}), workflow); err != nil {
+		t.Errorf("valid export: %v", err)
+	}
+	// Exports written before the envelope were a bare array
+	if envelope, err := DecodeExport([]byte(`[{"id":1,"description":"a","status":"todo","createdAt":"2024-01-01T12:00:00Z","updatedAt":"2024-01-01T12:00:00Z"}]`), workflow); err != nil || envelope.Count != 1 {
+		t.Errorf("bare array: %+v, %v", envelope, err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"data file", []byte(`{"schemaVersion":3,"checksum":"x","tasks":[]}`), "task data file"},
+		{"other format", encode(func(e *ExportEnvelope) { e.Format = "todo" }), "unknown format"},
+		{"newer envelope", encode(func(e *ExportEnvelope) { e.FormatVersion = ExportFormatVersion + 1 }), "format version"},
+		{"newer schema", encode(func(e *ExportEnvelope) { e.SchemaVersion = repository.CurrentSchemaVersion + 1 }), "newer"},
+		{"truncated", encode(func(e *ExportEnvelope) { e.Tasks = e.Tasks[:2] }), "truncated"},
+		{"unknown status", encode(func(e *ExportEnvelope) {
+			e.Tasks = append([]task.Task(nil), e.Tasks...)
+			e.Tasks[0].Status = "someday"
+		}), "unknown-status"},
+		{"not JSON", []byte("{"), "Invalid export file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeExport(tt.data, workflow)
+			if !errors.Is(err, task.ErrInvalidExport) {
+				t.Fatalf("err = %v, want %s", err, task.ErrInvalidExport.Code)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %q, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestImportExport_RenumbersAfterKeptIDs tests that renumbered tasks never
+// take the ID another imported task keeps
+func TestImportExport_RenumbersAfterKeptIDs(t *testing.T) {
+	repo := tasktest.NewMockRepository().WithTasks([]task.Task{
+		*tasktest.NewTaskBuilder().WithID(1).WithDescription("Existing").BuildInvalid(),
+	})
+	service := NewTaskService(repo)
+	envelope := NewExportEnvelope([]task.Task{
+		*tasktest.NewTaskBuilder().WithID(1).WithDescription("Clashes").BuildInvalid(),
+		*tasktest.NewTaskBuilder().WithID(2).WithDescription("Keeps its ID").BuildInvalid(),
+	}, nil, tasktest.FixedTime())
+
+	report, err := service.ImportExport(context.Background(), envelope, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Added) != 2 || report.Added[0].ID != 2 || report.Added[1].ID != 3 {
+		t.Fatalf("added = %+v", report.Added)
+	}
+	if report.Renumbered[3] != 1 {
+		t.Errorf("renumbered = %v, want 3 from 1", report.Renumbered)
+	}
+}
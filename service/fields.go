@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// SetTaskFields applies every edit to a task in one save. Edits are checked
+// all together, so the error joins one FieldError per refused edit and
+// nothing is saved when there is any.
+func (s *TaskService) SetTaskFields(ctx context.Context, id int, edits []task.FieldEdit, now time.Time) error {
+	return s.updateTask(ctx, id, func(t *task.Task) error {
+		var errs []error
+		for _, edit := range edits {
+			if err := t.ApplyEdit(edit, now, s.workflow.Descriptions); err != nil {
+				errs = append(errs, &task.FieldError{Field: edit.Field, Err: err})
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// EditTasks applies the edits to every task matched, in one save, and
+// returns the changes made. Tasks the edits leave as they were are not
+// changed. As with SetTaskFields, nothing is saved when any edit is refused
+// on any task, and with dryRun nothing is saved at all.
+func (s *TaskService) EditTasks(ctx context.Context, match func(task.Task) bool, edits []task.FieldEdit, now time.Time, dryRun bool) ([]task.TaskChange, error) {
+	if !dryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{Match: match})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+
+	var before, after []task.Task
+	var errs []error
+	for _, t := range tasks {
+		edited := t
+		for _, edit := range edits {
+			if err := edited.ApplyEdit(edit, now, s.workflow.Descriptions); err != nil {
+				errs = append(errs, fmt.Errorf("task %d: %w", t.ID, &task.FieldError{Field: edit.Field, Err: err}))
+			}
+		}
+		if slices.ContainsFunc(task.ChangedFields(t, edited), func(field task.FieldChange) bool { return field.Field != "updatedAt" }) {
+			before, after = append(before, t), append(after, edited)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if !dryRun && len(after) > 0 {
+		if err := s.save(ctx, before, after); err != nil {
+			return nil, repository.StorageError("failed to save tasks", err)
+		}
+	}
+	return task.DiffTasks(before, after), nil
+}
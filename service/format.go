@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/repository"
+)
+
+// LocateTasks returns where the tasks are written, or an empty file name
+// when the store cannot tell
+func (s *TaskService) LocateTasks(ctx context.Context) (string, map[int]int, error) {
+	locator, ok := repository.RepositoryAs[repository.TaskLocator](s.repo)
+	if !ok {
+		return "", nil, nil
+	}
+	file, lines, err := locator.LocateTasks(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to locate tasks: %w", err)
+	}
+	return file, lines, nil
+}
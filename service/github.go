@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// GitHubAPIURL is the default GitHub REST API endpoint
+const GitHubAPIURL = "https://api.github.com"
+
+// GitHub Issue Tracker (Adapter)
+//
+// GitHubTracker maps tasks to issues of one repository, optionally limited
+// to issues carrying a label. Issue references look like "owner/name#12".
+type GitHubTracker struct {
+	apiURL string
+	repo   string
+	label  string
+	token  string
+	client *http.Client
+}
+
+// NewGitHubTracker creates a tracker for repo ("owner/name"). An empty
+// apiURL uses GitHubAPIURL.
+func NewGitHubTracker(apiURL, repo, label, token string) (*GitHubTracker, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid GitHub repository %q, expected owner/name", repo)
+	}
+	if apiURL == "" {
+		apiURL = GitHubAPIURL
+	}
+
+	return &GitHubTracker{
+		apiURL: strings.TrimRight(apiURL, "/"),
+		repo:   repo,
+		label:  label,
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// GitHubIssue is an issue as the GitHub API returns it
+type GitHubIssue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	State       string    `json:"state"`
+	CreatedAt   time.Time `json:"created_at"`
+	PullRequest *struct{} `json:"pull_request,omitempty"`
+}
+
+func (g *GitHubTracker) Name() string {
+	return "github"
+}
+
+func (g *GitHubTracker) OpenIssues(ctx context.Context) ([]ExternalIssue, error) {
+	var issues []ExternalIssue
+	for page := 1; ; page++ {
+		query := url.Values{"state": {"open"}, "per_page": {"100"}, "page": {strconv.Itoa(page)}}
+		if g.label != "" {
+			query.Set("labels", g.label)
+		}
+
+		var batch []GitHubIssue
+		if err := g.do(ctx, http.MethodGet, "/repos/"+g.repo+"/issues?"+query.Encode(), nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, issue := range batch {
+			// The issues endpoint also returns pull requests
+			if issue.PullRequest == nil {
+				issues = append(issues, g.toExternal(issue))
+			}
+		}
+		if len(batch) < 100 {
+			return issues, nil
+		}
+	}
+}
+
+func (g *GitHubTracker) CreateIssue(ctx context.Context, task task.Task) (ExternalIssue, error) {
+	body := map[string]any{"title": task.Description}
+	if g.label != "" {
+		body["labels"] = []string{g.label}
+	}
+
+	var issue GitHubIssue
+	if err := g.do(ctx, http.MethodPost, "/repos/"+g.repo+"/issues", body, &issue); err != nil {
+		return ExternalIssue{}, err
+	}
+	return g.toExternal(issue), nil
+}
+
+func (g *GitHubTracker) SetClosed(ctx context.Context, ref string, closed bool) error {
+	repo, number, ok := strings.Cut(ref, "#")
+	if !ok || repo != g.repo {
+		return fmt.Errorf("issue %s does not belong to %s", ref, g.repo)
+	}
+
+	state := "open"
+	if closed {
+		state = "closed"
+	}
+	return g.do(ctx, http.MethodPatch, "/repos/"+g.repo+"/issues/"+number,
+		map[string]string{"state": state}, nil)
+}
+
+func (g *GitHubTracker) toExternal(issue GitHubIssue) ExternalIssue {
+	return ExternalIssue{
+		Ref:       fmt.Sprintf("%s#%d", g.repo, issue.Number),
+		Title:     issue.Title,
+		Closed:    issue.State == "closed",
+		CreatedAt: issue.CreatedAt,
+	}
+}
+
+// do sends a request to the GitHub API and decodes the JSON response into out
+func (g *GitHubTracker) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("github: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		return fmt.Errorf("github: %s (%d)", apiErr.Message, resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("github: invalid response: %w", err)
+	}
+	return nil
+}
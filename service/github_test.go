@@ -0,0 +1,235 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// fakeGitHub serves a minimal issues API for one repository
+type fakeGitHub struct {
+	mu     sync.Mutex
+	issues []GitHubIssue
+	labels map[int]string
+	token  string
+}
+
+func newFakeGitHub(t *testing.T, titles ...string) (*fakeGitHub, *httptest.Server) {
+	t.Helper()
+
+	gh := &fakeGitHub{labels: make(map[int]string), token: "gh-token"}
+	for _, title := range titles {
+		gh.add(title, "tracker")
+	}
+	// Pull requests are listed by the issues endpoint and must be skipped
+	gh.issues = append(gh.issues, GitHubIssue{
+		Number: 99, Title: "A pull request", State: "open", PullRequest: &struct{}{},
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/app/issues", gh.list)
+	mux.HandleFunc("POST /repos/octo/app/issues", gh.create)
+	mux.HandleFunc("PATCH /repos/octo/app/issues/{number}", gh.patch)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+gh.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "Bad credentials"}`))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return gh, server
+}
+
+func (gh *fakeGitHub) add(title, label string) GitHubIssue {
+	issue := GitHubIssue{Number: len(gh.issues) + 1, Title: title, State: "open", CreatedAt: tasktest.FixedTime()}
+	gh.issues = append(gh.issues, issue)
+	gh.labels[issue.Number] = label
+	return issue
+}
+
+func (gh *fakeGitHub) state(number int) string {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	for _, issue := range gh.issues {
+		if issue.Number == number {
+			return issue.State
+		}
+	}
+	return ""
+}
+
+func (gh *fakeGitHub) list(w http.ResponseWriter, r *http.Request) {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+
+	label := r.URL.Query().Get("labels")
+	issues := []GitHubIssue{}
+	for _, issue := range gh.issues {
+		if issue.State == "open" && (label == "" || gh.labels[issue.Number] == label || issue.PullRequest != nil) {
+			issues = append(issues, issue)
+		}
+	}
+	json.NewEncoder(w).Encode(issues)
+}
+
+func (gh *fakeGitHub) create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Title  string   `json:"title"`
+		Labels []string `json:"labels"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	issue := gh.add(req.Title, strings.Join(req.Labels, ","))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(issue)
+}
+
+func (gh *fakeGitHub) patch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		State string `json:"state"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	number, _ := strconv.Atoi(r.PathValue("number"))
+
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	for i := range gh.issues {
+		if gh.issues[i].Number == number {
+			gh.issues[i].State = req.State
+			json.NewEncoder(w).Encode(gh.issues[i])
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func newTestGitHubTracker(t *testing.T, server *httptest.Server) *GitHubTracker {
+	t.Helper()
+	tracker, err := NewGitHubTracker(server.URL, "octo/app", "tracker", "gh-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tracker
+}
+
+// TestNewGitHubTracker tests repository name validation
+func TestNewGitHubTracker(t *testing.T) {
+	for _, repo := range []string{"", "octo", "octo/", "/app", "octo/app/extra"} {
+		if _, err := NewGitHubTracker("", repo, "", ""); err == nil {
+			t.Errorf("NewGitHubTracker(%q) succeeded, want error", repo)
+		}
+	}
+}
+
+// TestTaskService_ImportIssues tests importing open issues as tasks
+func TestTaskService_ImportIssues(t *testing.T) {
+	gh, server := newFakeGitHub(t, "Fix login", "Write docs")
+	gh.add("Unlabeled", "other")
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	service := NewTaskService(repo)
+	tracker := newTestGitHubTracker(t, server)
+
+	report, err := service.ImportIssues(t.Context(), tracker)
+	if err != nil {
+		t.Fatalf("ImportIssues() failed: %v", err)
+	}
+	if len(report.Added) != 2 {
+		t.Fatalf("ImportIssues() added %d tasks, want 2", len(report.Added))
+	}
+	task, _ := repo.GetTask(4)
+	if task.Description != "Fix login" || task.ExternalRefs["github"] != "octo/app#1" {
+		t.Errorf("imported task = %+v", task)
+	}
+
+	gh.mu.Lock()
+	gh.issues[0].Title = "Fix login page"
+	gh.mu.Unlock()
+
+	report, err = service.ImportIssues(t.Context(), tracker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Added) != 0 || len(report.Updated) != 1 || repo.TaskCount() != 5 {
+		t.Errorf("second import added %d, updated %d, total %d", len(report.Added), len(report.Updated), repo.TaskCount())
+	}
+}
+
+// TestTaskService_PushIssues tests mirroring tasks to issues
+func TestTaskService_PushIssues(t *testing.T) {
+	gh, server := newFakeGitHub(t, "Fix login")
+	repo := tasktest.NewMockRepository()
+	service := NewTaskService(repo)
+	tracker := newTestGitHubTracker(t, server)
+
+	if _, err := service.ImportIssues(t.Context(), tracker); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddTask(t.Context(), "Local only"); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.MarkTaskDone(t.Context(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		report, err := service.PushIssues(t.Context(), tracker, nil, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Created) != 1 || len(report.Closed) != 1 {
+			t.Errorf("PushIssues() = %+v", report)
+		}
+		if gh.state(1) != "open" || gh.state(3) != "" {
+			t.Error("dry run changed GitHub")
+		}
+	})
+
+	t.Run("push creates and closes issues", func(t *testing.T) {
+		if _, err := service.PushIssues(t.Context(), tracker, nil, false); err != nil {
+			t.Fatal(err)
+		}
+		if gh.state(1) != "closed" {
+			t.Errorf("issue 1 state = %q, want closed", gh.state(1))
+		}
+		task, _ := repo.GetTask(2)
+		if task.ExternalRefs["github"] != "octo/app#3" || gh.state(3) != "open" {
+			t.Errorf("pushed task refs = %v", task.ExternalRefs)
+		}
+
+		report, err := service.PushIssues(t.Context(), tracker, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Created)+len(report.Closed)+len(report.Reopened) != 0 {
+			t.Errorf("second push = %+v, want nothing", report)
+		}
+	})
+
+	t.Run("reopens issues of reopened tasks", func(t *testing.T) {
+		item, _ := repo.GetTask(1)
+		item.Status = task.StatusInProgress
+		tasks := repo.GetStoredTasks()
+		tasks[0] = *item
+		repo.WithTasks(tasks)
+
+		report, err := service.PushIssues(t.Context(), tracker, []int{1}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Reopened) != 1 || gh.state(1) != "open" {
+			t.Errorf("PushIssues() = %+v, issue state %q", report, gh.state(1))
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+	"github.com/alnah/task-tracker/taskpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WatchPollInterval is how often WatchTasks looks for changes
+var WatchPollInterval = 500 * time.Millisecond
+
+// gRPC Adapter (Presentation Layer)
+type grpcTaskServer struct {
+	taskpb.UnimplementedTaskServiceServer
+	service *TaskService
+}
+
+// NewGRPCServer creates a gRPC server exposing the task service. Calls must
+// carry credentials auth accepts in their authorization metadata, unless
+// auth is nil.
+func NewGRPCServer(service *TaskService, auth *Authenticator) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(auth.UnaryInterceptor, actorInterceptor),
+		grpc.StreamInterceptor(auth.StreamInterceptor),
+	)
+	taskpb.RegisterTaskServiceServer(server, &grpcTaskServer{service: service})
+	return server
+}
+
+// actorInterceptor attributes changes to the x-task-actor metadata value,
+// like the X-Task-Actor header of the REST API, when the server requires no
+// credentials; otherwise they already name who made the call
+func actorInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if _, ok := task.LookupActor(ctx); ok {
+		return handler(ctx, req)
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if actors := md.Get("x-task-actor"); len(actors) > 0 && actors[0] != "" {
+			ctx = task.WithActor(ctx, actors[0])
+		}
+	}
+	return handler(ctx, req)
+}
+
+// ServeGRPC listens on addr and serves until ctx is cancelled
+func ServeGRPC(ctx context.Context, service *TaskService, auth *Authenticator, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	server := NewGRPCServer(service, auth)
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	return server.Serve(listener)
+}
+
+func (s *grpcTaskServer) AddTask(ctx context.Context, req *taskpb.AddTaskRequest) (*taskpb.Task, error) {
+	task, err := s.service.AddTask(ctx, req.GetDescription())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return taskToProto(task), nil
+}
+
+func (s *grpcTaskServer) GetTask(ctx context.Context, req *taskpb.GetTaskRequest) (*taskpb.Task, error) {
+	task, err := s.service.GetTask(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return taskToProto(task), nil
+}
+
+func (s *grpcTaskServer) UpdateTask(ctx context.Context, req *taskpb.UpdateTaskRequest) (*taskpb.Task, error) {
+	if err := s.service.UpdateTask(ctx, int(req.GetId()), req.GetDescription()); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.GetTask(ctx, &taskpb.GetTaskRequest{Id: req.GetId()})
+}
+
+func (s *grpcTaskServer) DeleteTask(ctx context.Context, req *taskpb.DeleteTaskRequest) (*taskpb.DeleteTaskResponse, error) {
+	if err := s.service.DeleteTask(ctx, int(req.GetId())); err != nil {
+		return nil, grpcError(err)
+	}
+	return &taskpb.DeleteTaskResponse{}, nil
+}
+
+func (s *grpcTaskServer) MarkTaskInProgress(ctx context.Context, req *taskpb.MarkTaskRequest) (*taskpb.Task, error) {
+	if err := s.service.MarkTaskInProgress(ctx, int(req.GetId())); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.GetTask(ctx, &taskpb.GetTaskRequest{Id: req.GetId()})
+}
+
+func (s *grpcTaskServer) MarkTaskDone(ctx context.Context, req *taskpb.MarkTaskRequest) (*taskpb.Task, error) {
+	if err := s.service.MarkTaskDone(ctx, int(req.GetId())); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.GetTask(ctx, &taskpb.GetTaskRequest{Id: req.GetId()})
+}
+
+func (s *grpcTaskServer) ListTasks(ctx context.Context, req *taskpb.ListTasksRequest) (*taskpb.ListTasksResponse, error) {
+	filter := ""
+	if req.GetStatus() != taskpb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		status, err := statusFromProto(req.GetStatus())
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		filter = string(status)
+	}
+
+	tasks, err := s.service.ListTasks(ctx, filter)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &taskpb.ListTasksResponse{Tasks: make([]*taskpb.Task, 0, len(tasks))}
+	for i := range tasks {
+		resp.Tasks = append(resp.Tasks, taskToProto(&tasks[i]))
+	}
+	return resp, nil
+}
+
+func (s *grpcTaskServer) WatchTasks(_ *taskpb.WatchTasksRequest, stream grpc.ServerStreamingServer[taskpb.TaskEvent]) error {
+	changes, err := s.service.WatchTasks(stream.Context(), WatchPollInterval)
+	if err != nil {
+		return grpcError(err)
+	}
+
+	for change := range changes {
+		event := &taskpb.TaskEvent{
+			Type: changeTypeToProto[change.Type],
+			Task: taskToProto(&change.Task),
+		}
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Mapping between domain and protobuf types
+
+var statusToProto = map[task.TaskStatus]taskpb.TaskStatus{
+	task.StatusTodo:       taskpb.TaskStatus_TASK_STATUS_TODO,
+	task.StatusInProgress: taskpb.TaskStatus_TASK_STATUS_IN_PROGRESS,
+	task.StatusDone:       taskpb.TaskStatus_TASK_STATUS_DONE,
+}
+
+var changeTypeToProto = map[task.ChangeType]taskpb.TaskEvent_Type{
+	task.ChangeAdded:   taskpb.TaskEvent_TYPE_ADDED,
+	task.ChangeUpdated: taskpb.TaskEvent_TYPE_UPDATED,
+	task.ChangeDeleted: taskpb.TaskEvent_TYPE_DELETED,
+}
+
+func statusFromProto(status taskpb.TaskStatus) (task.TaskStatus, error) {
+	for domain, proto := range statusToProto {
+		if proto == status {
+			return domain, nil
+		}
+	}
+	return "", task.ErrInvalidStatus
+}
+
+func taskToProto(task *task.Task) *taskpb.Task {
+	return &taskpb.Task{
+		Id:          int64(task.ID),
+		Description: task.Description,
+		Status:      statusToProto[task.Status],
+		CreatedAt:   timestamppb.New(task.CreatedAt),
+		UpdatedAt:   timestamppb.New(task.UpdatedAt),
+	}
+}
+
+// grpcError translates domain errors into gRPC status errors
+func grpcError(err error) error {
+	var taskErr task.TaskError
+	if errors.As(err, &taskErr) {
+		switch taskErr.Code {
+		case task.ErrTaskNotFound.Code:
+			return status.Error(codes.NotFound, taskErr.Message)
+		case task.ErrEmptyDescription.Code, task.ErrDescriptionTooLong.Code, task.ErrInvalidStatus.Code, task.ErrInvalidID.Code:
+			return status.Error(codes.InvalidArgument, taskErr.Message)
+		case task.ErrConflict.Code:
+			return status.Error(codes.Aborted, taskErr.Message)
+		case task.ErrWIPLimit.Code, task.ErrHookRejected.Code:
+			return status.Error(codes.FailedPrecondition, taskErr.Message)
+		case task.ErrReadOnly.Code:
+			return status.Error(codes.PermissionDenied, taskErr.Message)
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/taskpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newGRPCTestClient starts an in-memory gRPC server over the given repository
+func newGRPCTestClient(t *testing.T, repo repository.TaskRepository) taskpb.TaskServiceClient {
+	t.Helper()
+	return newGRPCAuthClient(t, NewTaskService(repo), nil)
+}
+
+// newGRPCAuthClient starts an in-memory gRPC server requiring credentials
+// auth accepts
+func newGRPCAuthClient(t *testing.T, service *TaskService, auth *Authenticator) taskpb.TaskServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(service, auth)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial test server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return taskpb.NewTaskServiceClient(conn)
+}
+
+// TestGRPCServer_CRUD tests the unary RPCs against the service
+func TestGRPCServer_CRUD(t *testing.T) {
+	repo := tasktest.NewMockRepository()
+	client := newGRPCTestClient(t, repo)
+	ctx := t.Context()
+
+	created, err := client.AddTask(ctx, &taskpb.AddTaskRequest{Description: "Buy groceries"})
+	if err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+	if created.GetId() != 1 || created.GetStatus() != taskpb.TaskStatus_TASK_STATUS_TODO {
+		t.Errorf("AddTask() = %v, want todo task with ID 1", created)
+	}
+
+	done, err := client.MarkTaskDone(ctx, &taskpb.MarkTaskRequest{Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("MarkTaskDone() failed: %v", err)
+	}
+	if done.GetStatus() != taskpb.TaskStatus_TASK_STATUS_DONE {
+		t.Errorf("MarkTaskDone() status = %v, want done", done.GetStatus())
+	}
+
+	list, err := client.ListTasks(ctx, &taskpb.ListTasksRequest{
+		Status: taskpb.TaskStatus_TASK_STATUS_DONE,
+	})
+	if err != nil {
+		t.Fatalf("ListTasks() failed: %v", err)
+	}
+	if len(list.GetTasks()) != 1 {
+		t.Errorf("ListTasks(done) returned %d tasks, want 1", len(list.GetTasks()))
+	}
+
+	if _, err := client.DeleteTask(ctx, &taskpb.DeleteTaskRequest{Id: created.GetId()}); err != nil {
+		t.Fatalf("DeleteTask() failed: %v", err)
+	}
+	if repo.TaskCount() != 0 {
+		t.Errorf("DeleteTask() left %d tasks", repo.TaskCount())
+	}
+}
+
+// TestGRPCServer_Errors tests domain error translation to status codes
+func TestGRPCServer_Errors(t *testing.T) {
+	client := newGRPCTestClient(t, tasktest.NewMockRepository())
+
+	_, err := client.GetTask(t.Context(), &taskpb.GetTaskRequest{Id: 42})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetTask() code = %v, want %v", status.Code(err), codes.NotFound)
+	}
+
+	_, err = client.AddTask(t.Context(), &taskpb.AddTaskRequest{Description: "  "})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("AddTask() code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
+
+// TestGRPCServer_WatchTasks tests that changes are streamed to watchers
+func TestGRPCServer_WatchTasks(t *testing.T) {
+	previous := WatchPollInterval
+	WatchPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { WatchPollInterval = previous })
+
+	// The watcher polls concurrently, so use a real file rather than the mock
+	repo := repository.NewFileTaskRepository(filepath.Join(t.TempDir(), "tasks.json"))
+	client := newGRPCTestClient(t, repo)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchTasks(ctx, &taskpb.WatchTasksRequest{})
+	if err != nil {
+		t.Fatalf("WatchTasks() failed: %v", err)
+	}
+
+	// Give the server time to take its initial snapshot
+	time.Sleep(50 * time.Millisecond)
+	if _, err := client.AddTask(ctx, &taskpb.AddTaskRequest{Description: "Watched"}); err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() failed: %v", err)
+	}
+	if event.GetType() != taskpb.TaskEvent_TYPE_ADDED || event.GetTask().GetDescription() != "Watched" {
+		t.Errorf("Recv() = %v, want added event for 'Watched'", event)
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithHabits records the completions of scheduled tasks as they are saved
+func (s *TaskService) WithHabits(habits repository.HabitLog) *TaskService {
+	s.habits = habits
+	return s
+}
+
+// Habits returns the streaks of the schedules and their calendar over the
+// last weeks, up to now. Completions come from the habit log and from done
+// tasks, which also covers tasks done before the log existed.
+func (s *TaskService) Habits(ctx context.Context, schedules []task.Schedule, weeks int, now time.Time) ([]task.Habit, error) {
+	var completions []task.HabitCompletion
+	if s.habits != nil {
+		var err error
+		if completions, err = s.habits.Completions(ctx); err != nil {
+			return nil, fmt.Errorf("failed to read habit history: %w", err)
+		}
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	for _, t := range tasks {
+		if completion, ok := task.HabitCompletionOf(t); ok && t.Status == task.StatusDone {
+			completions = append(completions, completion)
+		}
+	}
+
+	done := make(map[string]map[int64]bool)
+	for _, completion := range completions {
+		if done[completion.Schedule] == nil {
+			done[completion.Schedule] = make(map[int64]bool)
+		}
+		done[completion.Schedule][completion.Occurrence.Unix()] = true
+	}
+
+	habits := make([]task.Habit, 0, len(schedules))
+	for _, schedule := range schedules {
+		habits = append(habits, task.BuildHabit(schedule, done[schedule.Name], weeks, now))
+	}
+	return habits, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TaskHistory lists the changes made to a task, oldest first
+func (s *TaskService) TaskHistory(ctx context.Context, id int) ([]task.HistoryEntry, error) {
+	historian, ok := repository.RepositoryAs[repository.TaskHistorian](s.repo)
+	if !ok {
+		return nil, repository.ErrHistoryUnavailable
+	}
+
+	versions, err := historian.History(ctx, id)
+	if err != nil {
+		return nil, repository.StorageError("failed to load history", err)
+	}
+
+	var entries []task.HistoryEntry
+	var previous *task.Task
+	for _, version := range versions {
+		current := version.Task
+		var change task.TaskChange
+		switch {
+		case previous == nil && current == nil:
+			continue
+		case previous == nil:
+			change = task.TaskChange{Type: task.ChangeAdded, Task: *current}
+		case current == nil:
+			change = task.TaskChange{Type: task.ChangeDeleted, Task: *previous}
+		case task.SameTask(*previous, *current):
+			continue
+		default:
+			change = task.TaskChange{Type: task.ChangeUpdated, Task: *current, Previous: previous}
+		}
+
+		entries = append(entries, task.HistoryEntry{
+			Revision: version.Revision,
+			Time:     version.Time,
+			Message:  version.Message,
+			Change:   change,
+		})
+		previous = current
+	}
+
+	if len(entries) == 0 {
+		return nil, task.ErrTaskNotFound
+	}
+	return entries, nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+	starlarkjson "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+)
+
+// HookEvent names a change hook scripts can act on. Scripts define a
+// function named after the event, with an underscore for the dash, such as
+// pre_add.
+type HookEvent string
+
+const (
+	HookPreAdd     HookEvent = "pre-add"
+	HookPostAdd    HookEvent = "post-add"
+	HookPreDone    HookEvent = "pre-done"
+	HookPostDone   HookEvent = "post-done"
+	HookPreDelete  HookEvent = "pre-delete"
+	HookPostDelete HookEvent = "post-delete"
+)
+
+// hookEvents lists the events in the order their hooks are looked up
+var hookEvents = []HookEvent{HookPreAdd, HookPostAdd, HookPreDone, HookPostDone, HookPreDelete, HookPostDelete}
+
+// defaultHookSteps bounds the work of one hook call, so that a script
+// stuck in a loop fails instead of hanging every command
+const defaultHookSteps = 1_000_000
+
+// function returns the name of the script function run on the event
+func (e HookEvent) function() string {
+	return strings.ReplaceAll(string(e), "-", "_")
+}
+
+// changesTask reports whether hooks on the event may change the task
+// about to be saved
+func (e HookEvent) changesTask() bool {
+	return e == HookPreAdd || e == HookPreDone
+}
+
+// hook is one script function run on an event
+type hook struct {
+	script string
+	fn     starlark.Callable
+}
+
+// Hooks runs Starlark functions when tasks are added, done or deleted. Pre
+// hooks get the task as a dict shaped like the task file, and may change
+// it in place or return a new dict for add and done; calling fail refuses
+// the change, which pre_delete can also do. Post hooks run once the change
+// is saved: for a command running in a unit of work, after its Commit, and
+// not at all if that fails.
+//
+// Shell commands may also run once a change is saved, see runCommands.
+type Hooks struct {
+	hooks    map[HookEvent][]hook
+	commands map[HookEvent][]string
+	maxSteps uint64
+	out      io.Writer
+}
+
+// LoadHooks runs the scripts of the config and keeps the event functions
+// they define, each event's in the order of the scripts, along with its
+// shell commands. print in a script, commands and warnings write to out.
+func LoadHooks(config repository.HooksConfig, out io.Writer) (*Hooks, error) {
+	commands, err := hookCommands(config.Commands)
+	if err != nil {
+		return nil, err
+	}
+	h := &Hooks{hooks: make(map[HookEvent][]hook), commands: commands, maxSteps: defaultHookSteps, out: out}
+	if config.MaxSteps > 0 {
+		h.maxSteps = uint64(config.MaxSteps)
+	}
+	predeclared := starlark.StringDict{"json": starlarkjson.Module}
+	for _, path := range config.Scripts {
+		thread := h.thread(path)
+		globals, err := starlark.ExecFile(thread, path, nil, predeclared)
+		if err != nil {
+			return nil, task.ErrHookFailed.Wrap(err)
+		}
+		for _, event := range hookEvents {
+			value, ok := globals[event.function()]
+			if !ok {
+				continue
+			}
+			fn, ok := value.(starlark.Callable)
+			if !ok {
+				return nil, task.ErrHookFailed.Withf("%s in %s is of type %s, not a function", event.function(), path, value.Type())
+			}
+			h.hooks[event] = append(h.hooks[event], hook{script: path, fn: fn})
+		}
+	}
+	return h, nil
+}
+
+// thread returns a Starlark thread bounded to the step limit, printing to
+// the output of the hooks
+func (h *Hooks) thread(name string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			if h.out != nil {
+				fmt.Fprintln(h.out, msg)
+			}
+		},
+	}
+	thread.SetMaxExecutionSteps(h.maxSteps)
+	return thread
+}
+
+// run calls the event's hooks on the task in turn, each one seeing the
+// changes of the one before, and returns the task as pre hooks left it
+func (h *Hooks) run(ctx context.Context, event HookEvent, t task.Task, rules task.DescriptionRules) (task.Task, error) {
+	if h == nil {
+		return t, nil
+	}
+	for _, hook := range h.hooks[event] {
+		changed, err := h.call(ctx, event, hook, t)
+		if err != nil {
+			return t, err
+		}
+		if event.changesTask() {
+			if t, err = applyHookChanges(t, changed, rules); err != nil {
+				return t, task.ErrHookFailed.Withf("%s in %s", event.function(), hook.script).Wrap(err)
+			}
+		}
+	}
+	return t, nil
+}
+
+// call runs one hook on the task and returns the task it left, as JSON
+func (h *Hooks) call(ctx context.Context, event HookEvent, hook hook, t task.Task) ([]byte, error) {
+	thread := h.thread(hook.script)
+	stop := context.AfterFunc(ctx, func() { thread.Cancel(ctx.Err().Error()) })
+	defer stop()
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	value, err := starlark.Call(thread, starlarkjson.Module.Members["decode"], starlark.Tuple{starlark.String(data)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := starlark.Call(thread, hook.fn, starlark.Tuple{value}, nil)
+	if err != nil {
+		return nil, hookError(event, hook, err)
+	}
+	if !event.changesTask() {
+		return nil, nil
+	}
+
+	switch result.(type) {
+	case starlark.NoneType:
+		// The hook changed the dict it was given, if anything
+	case *starlark.Dict:
+		value = result
+	default:
+		return nil, task.ErrHookFailed.Withf("%s in %s returned a value of type %s, not a dict or None", event.function(), hook.script, result.Type())
+	}
+	encoded, err := starlark.Call(thread, starlarkjson.Module.Members["encode"], starlark.Tuple{value}, nil)
+	if err != nil {
+		return nil, task.ErrHookFailed.Withf("%s in %s", event.function(), hook.script).Wrap(err)
+	}
+	return []byte(encoded.(starlark.String)), nil
+}
+
+// hookError tells a hook refusing the change with fail apart from one that
+// broke
+func hookError(event HookEvent, hook hook, err error) error {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		if reason, ok := strings.CutPrefix(evalErr.Msg, "fail: "); ok {
+			return task.ErrHookRejected.Withf("%s: %s", event, reason)
+		}
+		// Point at the line of the script rather than at a builtin it called
+		for i := range evalErr.CallStack {
+			if pos := evalErr.CallStack.At(i).Pos; pos.IsValid() && pos.Filename() != "<builtin>" {
+				return task.ErrHookFailed.Withf("%s: in %s", pos, event.function()).Wrap(errors.New(evalErr.Msg))
+			}
+		}
+	}
+	return task.ErrHookFailed.Withf("%s in %s", event.function(), hook.script).Wrap(err)
+}
+
+// applyHookChanges takes the fields a pre hook may change from the task it
+// returned as JSON, tidied and checked like the same change on the command
+// line. The ID, status and bookkeeping fields stay as they were.
+func applyHookChanges(t task.Task, data []byte, rules task.DescriptionRules) (task.Task, error) {
+	var changed task.Task
+	if err := json.Unmarshal(data, &changed); err != nil {
+		return t, err
+	}
+	if !changed.Priority.IsValid() {
+		return t, task.ErrInvalidPriority.Withf("%q", changed.Priority)
+	}
+
+	updated := t
+	if changed.Description != t.Description {
+		if err := updated.SetDescription(changed.Description, rules); err != nil {
+			return t, err
+		}
+	}
+	updated.Priority = changed.Priority
+	updated.Tags = task.MergeTags(nil, changed.Tags)
+	updated.Notes = changed.Notes
+	updated.DueAt = changed.DueAt
+	updated.StartAt = changed.StartAt
+	updated.RemindAt = changed.RemindAt
+	updated.Estimate = changed.Estimate
+	updated.Project = strings.TrimSpace(changed.Project)
+	updated.Milestone = strings.TrimSpace(changed.Milestone)
+	updated.Sprint = strings.TrimSpace(changed.Sprint)
+	updated.Assignee = strings.TrimSpace(changed.Assignee)
+	if len(updated.Tags) == 0 {
+		updated.Tags = nil
+	}
+	return updated, nil
+}
+
+// WithHooks runs the hooks on the changes the service saves
+func (s *TaskService) WithHooks(hooks *Hooks) *TaskService {
+	s.hooks = hooks
+	return s
+}
+
+// runPreHooks runs the pre hooks on the tasks a save adds, finishes or
+// deletes, replacing the tasks in after with the ones the hooks return.
+// A hook refusing one change refuses the whole save.
+func (s *TaskService) runPreHooks(ctx context.Context, before, after []task.Task) error {
+	if s.hooks == nil {
+		return nil
+	}
+	old := make(map[int]task.Task, len(before))
+	for _, task := range before {
+		old[task.ID] = task
+	}
+	kept := make(map[int]bool, len(after))
+	for i, t := range after {
+		kept[t.ID] = true
+		prev, existed := old[t.ID]
+		event := HookPreAdd
+		switch {
+		case existed && prev.Status != task.StatusDone && t.Status == task.StatusDone:
+			event = HookPreDone
+		case existed:
+			continue
+		}
+		changed, err := s.hooks.run(ctx, event, t, s.workflow.Descriptions)
+		if err != nil {
+			return err
+		}
+		after[i] = changed
+	}
+	for _, task := range before {
+		if !kept[task.ID] {
+			if _, err := s.hooks.run(ctx, HookPreDelete, task, s.workflow.Descriptions); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runPostHooks runs the post hooks and shell commands on the saved changes.
+// Every change gets its hooks even when one fails.
+func (s *TaskService) runPostHooks(ctx context.Context, changes []task.TaskChange) error {
+	if s.hooks == nil {
+		return nil
+	}
+	var failed error
+	for _, change := range changes {
+		var event HookEvent
+		switch {
+		case change.Type == task.ChangeAdded:
+			event = HookPostAdd
+		case change.Type == task.ChangeDeleted:
+			event = HookPostDelete
+		case change.Task.Status == task.StatusDone && change.Previous != nil && change.Previous.Status != task.StatusDone:
+			event = HookPostDone
+		default:
+			continue
+		}
+		if _, err := s.hooks.run(ctx, event, change.Task, s.workflow.Descriptions); err != nil && failed == nil {
+			failed = fmt.Errorf("tasks saved but a hook failed: %w", err)
+		}
+		s.hooks.runCommands(ctx, event, change.Task)
+	}
+	return failed
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// hookCommandEvents maps the names of shell hooks in the config file to the
+// events they run on, all once the change is saved
+var hookCommandEvents = map[string]HookEvent{
+	"on-add":    HookPostAdd,
+	"on-done":   HookPostDone,
+	"on-delete": HookPostDelete,
+}
+
+// hookCommandTimeout bounds how long a command may run, so that a stuck
+// script does not hold up every command
+const hookCommandTimeout = 10 * time.Second
+
+// hookCommands checks the shell hooks of the config file and keys them by
+// event
+func hookCommands(commands map[string][]string) (map[HookEvent][]string, error) {
+	byEvent := make(map[HookEvent][]string, len(commands))
+	for name, lines := range commands {
+		event, ok := hookCommandEvents[name]
+		if !ok {
+			return nil, task.ErrHookFailed.Withf("unknown event %q, expected on-add, on-done or on-delete", name)
+		}
+		if slices.Contains(lines, "") {
+			return nil, task.ErrHookFailed.Withf("empty command for %s", name)
+		}
+		byEvent[event] = lines
+	}
+	return byEvent, nil
+}
+
+// runCommands runs the shell commands of the event with the task as JSON on
+// stdin and its main fields in the environment. The change is already
+// saved, the unit of work of a command included, so commands reading the
+// store see it, and a command failing is only reported.
+func (h *Hooks) runCommands(ctx context.Context, event HookEvent, task task.Task) {
+	if h == nil || len(h.commands[event]) == 0 {
+		return
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		h.warnf("Warning: hooks cannot encode task %d: %s\n", task.ID, err.Error())
+		return
+	}
+	env := append(os.Environ(), hookEnv(event, task)...)
+	for _, line := range h.commands[event] {
+		cmdCtx, cancel := context.WithTimeout(ctx, hookCommandTimeout)
+		cmd := shellCommand(cmdCtx, line)
+		cmd.Stdin = strings.NewReader(string(data) + "\n")
+		// Hooks print where warnings go, keeping stdout for the output of
+		// the command, which scripts read
+		cmd.Stdout = h.out
+		cmd.Stderr = h.out
+		cmd.Env = env
+		if err := cmd.Run(); err != nil {
+			h.warnf("Warning: %s hook %q failed for task %d: %s\n", commandEventName(event), line, task.ID, err.Error())
+		}
+		cancel()
+	}
+}
+
+// warnf reports a problem with a hook where the hooks print
+func (h *Hooks) warnf(format string, args ...any) {
+	if h.out != nil {
+		fmt.Fprintf(h.out, format, args...)
+	}
+}
+
+// hookEnv describes the task to shell hooks that do not read JSON
+func hookEnv(event HookEvent, task task.Task) []string {
+	return []string{
+		"TASK_EVENT=" + commandEventName(event),
+		"TASK_ID=" + strconv.Itoa(task.ID),
+		"TASK_UUID=" + task.UUID,
+		"TASK_DESCRIPTION=" + task.Description,
+		"TASK_STATUS=" + string(task.Status),
+		"TASK_PRIORITY=" + string(task.Priority),
+		"TASK_PROJECT=" + task.Project,
+		"TASK_TAGS=" + strings.Join(task.Tags, ","),
+	}
+}
+
+// commandEventName returns the name shell hooks of the event have in the
+// config file
+func commandEventName(event HookEvent) string {
+	for name, e := range hookCommandEvents {
+		if e == event {
+			return name
+		}
+	}
+	return string(event)
+}
+
+// shellCommand runs a command line with the platform's shell
+func shellCommand(ctx context.Context, line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", line)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", line)
+}
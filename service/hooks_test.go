@@ -0,0 +1,254 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// loadTestHooks writes the script to a temporary file and loads it
+func loadTestHooks(t *testing.T, script string, out *bytes.Buffer) *Hooks {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hooks.star")
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hooks, err := LoadHooks(repository.HooksConfig{Scripts: []string{path}}, out)
+	if err != nil {
+		t.Fatalf("LoadHooks() error = %v", err)
+	}
+	return hooks
+}
+
+// TestTaskService_Hooks tests that hooks change, refuse and follow changes
+func TestTaskService_Hooks(t *testing.T) {
+	var out bytes.Buffer
+	hooks := loadTestHooks(t, `
+def pre_add(task):
+    if "review" in task["description"].lower():
+        task["tags"] = task.get("tags", []) + ["@review"]
+    if task["description"].startswith("Never"):
+        fail("no task starts with Never")
+
+def pre_done(task):
+    return dict(task, priority = "low")
+
+def post_done(task):
+    print("done:", task["description"])
+
+def pre_delete(task):
+    if "keep" in task.get("tags", []):
+        fail("task", task["id"], "is tagged keep")
+`, &out)
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].Tags = []string{"keep"}
+	repo := tasktest.NewMockRepository().WithTasks(tasks)
+	service := NewTaskService(repo).WithHooks(hooks)
+	ctx := context.Background()
+
+	added, err := service.AddTask(ctx, "Review the pull request", task.WithTags("work"))
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if want := []string{"work", "@review"}; !slices.Equal(added.Tags, want) {
+		t.Errorf("added tags = %v, want %v", added.Tags, want)
+	}
+	if stored, _ := repo.GetTask(added.ID); !slices.Equal(stored.Tags, added.Tags) {
+		t.Errorf("stored tags = %v, want %v", stored.Tags, added.Tags)
+	}
+
+	_, err = service.AddTask(ctx, "Never do this")
+	if !errors.Is(err, task.ErrHookRejected) || !strings.Contains(err.Error(), "pre-add: no task starts with Never") {
+		t.Errorf("AddTask() refused error = %v, want %s", err, task.ErrHookRejected.Code)
+	}
+	if tasks := repo.GetStoredTasks(); len(tasks) != 4 {
+		t.Errorf("refused add stored %d tasks, want 4", len(tasks))
+	}
+
+	if err := service.MarkTaskDone(ctx, 2); err != nil {
+		t.Fatalf("MarkTaskDone() error = %v", err)
+	}
+	if item, _ := repo.GetTask(2); item.Status != task.StatusDone || item.Priority != task.PriorityLow {
+		t.Errorf("done task = %s, %q; want done, low", item.Status, item.Priority)
+	}
+	if out.String() != "done: Write report\n" {
+		t.Errorf("post_done printed %q", out.String())
+	}
+
+	if err := service.DeleteTask(ctx, 1); !errors.Is(err, task.ErrHookRejected) {
+		t.Errorf("DeleteTask() error = %v, want %s", err, task.ErrHookRejected.Code)
+	}
+	if _, ok := repo.GetTask(1); !ok {
+		t.Errorf("refused delete removed the task")
+	}
+}
+
+// TestTaskService_PostHooksAfterCommit tests that post hooks of a unit of
+// work wait for its changes to be saved
+func TestTaskService_PostHooksAfterCommit(t *testing.T) {
+	var out bytes.Buffer
+	hooks := loadTestHooks(t, "def post_add(task):\n    print(\"added:\", task[\"description\"])\n", &out)
+	repo := tasktest.NewMockRepository()
+	cache := repository.NewCachingRepository(repo)
+	service := NewTaskService(cache).WithHooks(hooks)
+	ctx := context.Background()
+
+	cache.Begin()
+	if _, err := service.AddTask(ctx, "Buy milk"); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("post_add ran before Commit: %q", out.String())
+	}
+	if err := cache.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if out.String() != "added: Buy milk\n" {
+		t.Errorf("post_add after Commit printed %q", out.String())
+	}
+
+	out.Reset()
+	cache.Begin()
+	if _, err := service.AddTask(ctx, "Buy bread"); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	repo.WithError(errors.New("disk full"))
+	if err := cache.Commit(ctx); err == nil {
+		t.Fatal("Commit() succeeded on a failing store")
+	}
+	if out.Len() != 0 {
+		t.Errorf("post_add ran although the save failed: %q", out.String())
+	}
+}
+
+// TestHooks_Failures tests that broken scripts fail the change instead of
+// hanging or saving it half done
+func TestHooks_Failures(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{"runtime error", "def pre_add(task):\n    return task[\"nope\"]\n", `hooks.star:2:16: in pre_add: key "nope" not in dict`},
+		{"endless work", "def pre_add(task):\n    for i in range(100000000):\n        pass\n", "too many steps"},
+		{"wrong result", "def pre_add(task):\n    return 3\n", "returned a value of type int"},
+		{"invalid priority", "def pre_add(task):\n    task[\"priority\"] = \"asap\"\n", `Invalid task priority: "asap"`},
+		{"empty description", "def pre_add(task):\n    task[\"description\"] = \" \"\n", "description cannot be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := tasktest.NewMockRepository()
+			service := NewTaskService(repo).WithHooks(loadTestHooks(t, tt.script, nil))
+			_, err := service.AddTask(context.Background(), "Buy milk")
+			if !errors.Is(err, task.ErrHookFailed) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("AddTask() error = %v, want %s with %q", err, task.ErrHookFailed.Code, tt.want)
+			}
+			if tasks := repo.GetStoredTasks(); len(tasks) != 0 {
+				t.Errorf("failed hook stored %d tasks", len(tasks))
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "hooks.star")
+	if err := os.WriteFile(path, []byte("pre_add = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadHooks(repository.HooksConfig{Scripts: []string{path}}, nil); !errors.Is(err, task.ErrHookFailed) {
+		t.Errorf("LoadHooks() with a non-function hook error = %v, want %s", err, task.ErrHookFailed.Code)
+	}
+}
+
+// TestTaskService_HookCommands tests that shell hooks get the saved task and
+// that their failures are only reported
+func TestTaskService_HookCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks are sh command lines")
+	}
+	out := filepath.Join(t.TempDir(), "added.json")
+	var warnings bytes.Buffer
+	hooks, err := LoadHooks(repository.HooksConfig{Commands: map[string][]string{
+		"on-add":  {`cat > "` + out + `"; echo "$TASK_EVENT $TASK_ID $TASK_DESCRIPTION [$TASK_TAGS]" >&2`},
+		"on-done": {"exit 3", "echo still run >&2"},
+	}}, &warnings)
+	if err != nil {
+		t.Fatalf("LoadHooks() error = %v", err)
+	}
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	service := NewTaskService(repo).WithHooks(hooks)
+	ctx := context.Background()
+
+	if _, err := service.AddTask(ctx, "Water plants", task.WithTags("home", "garden")); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var added task.Task
+	if err := json.Unmarshal(data, &added); err != nil || added.ID != 4 || added.Description != "Water plants" {
+		t.Errorf("on-add stdin = %s (%v)", data, err)
+	}
+
+	if err := service.MarkTaskDone(ctx, 2); err != nil {
+		t.Fatalf("MarkTaskDone() with a failing hook error = %v", err)
+	}
+	if item, _ := repo.GetTask(2); item.Status != task.StatusDone {
+		t.Errorf("task 2 status = %s, want done", item.Status)
+	}
+	want := "on-add 4 Water plants [home,garden]\n" +
+		"Warning: on-done hook \"exit 3\" failed for task 2: exit status 3\n" +
+		"still run\n"
+	if warnings.String() != want {
+		t.Errorf("hook output = %q, want %q", warnings.String(), want)
+	}
+
+	if _, err := LoadHooks(repository.HooksConfig{Commands: map[string][]string{"on-update": {"true"}}}, nil); !errors.Is(err, task.ErrHookFailed) {
+		t.Errorf("LoadHooks() with an unknown event error = %v, want %s", err, task.ErrHookFailed.Code)
+	}
+}
+
+// TestTaskService_HookCommandsSeeSavedStore tests that shell hooks of a unit
+// of work run once its changes are in the data file
+func TestTaskService_HookCommandsSeeSavedStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks are sh command lines")
+	}
+	dir := t.TempDir()
+	store := filepath.Join(dir, "tasks.json")
+	seen := filepath.Join(dir, "seen.json")
+	hooks, err := LoadHooks(repository.HooksConfig{Commands: map[string][]string{
+		"on-add": {`cp "` + store + `" "` + seen + `"`},
+	}}, nil)
+	if err != nil {
+		t.Fatalf("LoadHooks() error = %v", err)
+	}
+	cache := repository.NewCachingRepository(repository.NewFileTaskRepository(store))
+	service := NewTaskService(cache).WithHooks(hooks)
+	ctx := context.Background()
+
+	cache.Begin()
+	if _, err := service.AddTask(ctx, "Water plants"); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if _, err := os.Stat(seen); err == nil {
+		t.Fatal("on-add ran before Commit")
+	}
+	if err := cache.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	tasks, err := repository.NewFileTaskRepository(seen).Load(ctx)
+	if err != nil || len(tasks) != 1 || tasks[0].Description != "Water plants" {
+		t.Errorf("on-add saw %v (%v), want the added task", tasks, err)
+	}
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// REST Adapter (Presentation Layer)
+//
+// Besides per-task endpoints the API exposes the whole task list as one
+// resource so that HTTPTaskRepository can use a server as its store. The
+// list carries an ETag; a PUT with a stale If-Match is rejected with 412.
+type httpTaskServer struct {
+	service *TaskService
+	auth    *Authenticator
+	// mu serializes writes so the If-Match check and the save are atomic
+	mu sync.Mutex
+}
+
+// NewHTTPHandler exposes the task service as a JSON REST API under /api/v1
+// and serves the web UI at /. API requests must carry credentials auth
+// accepts, unless auth is nil.
+func NewHTTPHandler(service *TaskService, auth *Authenticator) http.Handler {
+	s := &httpTaskServer{service: service, auth: auth}
+
+	mux := http.NewServeMux()
+	for _, route := range s.routes() {
+		mux.HandleFunc(route.Method+" "+route.Path, s.validateBody(route))
+	}
+
+	root := http.NewServeMux()
+	root.Handle("/api/", s.authenticate(mux))
+	// Clients fetch the document to generate code, before they have a token
+	root.HandleFunc("GET "+openAPIPath, s.openAPI)
+	root.Handle("/", webUI())
+	return root
+}
+
+// httpRoute is one endpoint of the API. NewHTTPHandler registers the routes
+// and OpenAPISpec describes them from the same table, so the document
+// cannot drift from the handlers.
+type httpRoute struct {
+	Method string
+	Path   string
+	// ID names the operation for generated clients
+	ID      string
+	Summary string
+	// Query and Header list the optional parameters the handler reads
+	Query  []httpParam
+	Header []httpParam
+	// Request is a value of the body type the handler decodes, nil when it
+	// reads no body
+	Request any
+	// Status is the success status, answered with a body of the type of
+	// Response unless it is nil
+	Status   int
+	Response any
+	// Errors lists the statuses of the errors the handler answers, besides
+	// 401 and 500 which any route can
+	Errors  []int
+	Handler http.HandlerFunc
+}
+
+type httpParam struct {
+	Name        string
+	Description string
+}
+
+// routes lists the endpoints under /api/v1. It is also called on a nil
+// server to describe them.
+func (s *httpTaskServer) routes() []httpRoute {
+	return []httpRoute{
+		{
+			Method: "GET", Path: "/api/v1/tasks", ID: "listTasks",
+			Summary: "List tasks",
+			Query:   []httpParam{{"status", "Only list tasks with this status"}},
+			Status:  http.StatusOK, Response: []task.Task{}, Errors: []int{http.StatusBadRequest},
+			Handler: s.listTasks,
+		},
+		{
+			Method: "PUT", Path: "/api/v1/tasks", ID: "replaceTasks",
+			Summary: "Replace all tasks",
+			Header:  []httpParam{{"If-Match", "ETag of the task list last read; the save is refused if it changed since"}},
+			Request: []task.Task{}, Status: http.StatusNoContent,
+			Errors:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusPreconditionFailed},
+			Handler: s.replaceTasks,
+		},
+		{
+			Method: "POST", Path: "/api/v1/tasks", ID: "addTask",
+			Summary: "Add a task",
+			Request: httpAddRequest{}, Status: http.StatusCreated, Response: task.Task{},
+			Errors:  []int{http.StatusBadRequest, http.StatusForbidden},
+			Handler: s.addTask,
+		},
+		{
+			Method: "GET", Path: "/api/v1/tasks/next-id", ID: "nextID",
+			Summary: "ID the next task will get",
+			Status:  http.StatusOK, Response: httpNextID{},
+			Handler: s.nextID,
+		},
+		{
+			Method: "GET", Path: "/api/v1/tasks/{id}", ID: "getTask",
+			Summary: "Show a task",
+			Status:  http.StatusOK, Response: task.Task{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound},
+			Handler: s.getTask,
+		},
+		{
+			Method: "PATCH", Path: "/api/v1/tasks/{id}", ID: "updateTask",
+			Summary: "Change the description or status of a task",
+			Request: httpUpdateRequest{}, Status: http.StatusOK, Response: task.Task{},
+			Errors:  []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+			Handler: s.updateTask,
+		},
+		{
+			Method: "DELETE", Path: "/api/v1/tasks/{id}", ID: "deleteTask",
+			Summary: "Delete a task",
+			Status:  http.StatusNoContent, Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+			Handler: s.deleteTask,
+		},
+		{
+			Method: "GET", Path: "/api/v1/statuses", ID: "getWorkflow",
+			Summary: "Workflow statuses and transitions",
+			Status:  http.StatusOK, Response: httpWorkflow{},
+			Handler: s.statuses,
+		},
+	}
+}
+
+// ServeHTTP listens on addr and serves handler until ctx is cancelled
+func ServeHTTP(ctx context.Context, addr string, handler http.Handler) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *httpTaskServer) authenticate(next http.Handler) http.Handler {
+	return s.auth.HTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every credential names who uses it, so the header only counts on a
+		// server that requires none
+		if _, ok := task.LookupActor(r.Context()); !ok {
+			if actor := r.Header.Get("X-Task-Actor"); actor != "" {
+				r = r.WithContext(task.WithActor(r.Context(), actor))
+			}
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+func (s *httpTaskServer) listTasks(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && !s.service.Workflow().Has(task.TaskStatus(status)) {
+		writeServiceError(w, task.ErrInvalidStatus)
+		return
+	}
+
+	tasks, err := s.service.ListTasks(r.Context(), status)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if status == "" {
+		w.Header().Set("ETag", repository.TasksETag(tasks))
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+// httpWorkflow is the body of GET /api/v1/statuses
+type httpWorkflow struct {
+	Statuses    []task.TaskStatus                     `json:"statuses"`
+	Transitions map[task.TaskStatus][]task.TaskStatus `json:"transitions,omitempty"`
+}
+
+func (s *httpTaskServer) statuses(w http.ResponseWriter, r *http.Request) {
+	workflow := s.service.Workflow()
+	writeJSON(w, http.StatusOK, httpWorkflow{
+		Statuses:    workflow.Statuses,
+		Transitions: workflow.Transitions,
+	})
+}
+
+func (s *httpTaskServer) replaceTasks(w http.ResponseWriter, r *http.Request) {
+	var tasks []task.Task
+	if err := json.NewDecoder(r.Body).Decode(&tasks); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, task.ErrInvalidBody.Code, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if match := r.Header.Get("If-Match"); match != "" {
+		current, err := s.service.ListTasks(r.Context(), "")
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		if match != repository.TasksETag(current) {
+			writeHTTPError(w, http.StatusPreconditionFailed, "CONFLICT",
+				"tasks were changed by another client")
+			return
+		}
+	}
+
+	if err := s.service.ReplaceTasks(r.Context(), tasks); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", repository.TasksETag(tasks))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// httpAddRequest is the body of POST /api/v1/tasks
+type httpAddRequest struct {
+	Description string     `json:"description"`
+	DueAt       *time.Time `json:"dueAt,omitempty"`
+}
+
+func (s *httpTaskServer) addTask(w http.ResponseWriter, r *http.Request) {
+	var req httpAddRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, task.ErrInvalidBody.Code, err.Error())
+		return
+	}
+
+	var opts []task.TaskOption
+	if req.DueAt != nil {
+		opts = append(opts, task.WithDueDate(*req.DueAt))
+	}
+
+	s.mu.Lock()
+	task, err := s.service.AddTask(r.Context(), req.Description, opts...)
+	s.mu.Unlock()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, task)
+}
+
+// httpNextID is the body of GET /api/v1/tasks/next-id
+type httpNextID struct {
+	ID int `json:"id"`
+}
+
+func (s *httpTaskServer) nextID(w http.ResponseWriter, r *http.Request) {
+	id, err := s.service.NextID(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, httpNextID{ID: id})
+}
+
+func (s *httpTaskServer) getTask(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, task.ErrInvalidID)
+		return
+	}
+
+	task, err := s.service.GetTask(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+// httpUpdateRequest is the body of PATCH /api/v1/tasks/{id}; fields left
+// out are not changed
+type httpUpdateRequest struct {
+	Description *string          `json:"description,omitempty"`
+	Status      *task.TaskStatus `json:"status,omitempty"`
+}
+
+func (s *httpTaskServer) updateTask(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, task.ErrInvalidID)
+		return
+	}
+
+	var req httpUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, task.ErrInvalidBody.Code, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	err = s.applyUpdate(r.Context(), id, req.Description, req.Status)
+	s.mu.Unlock()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.getTask(w, r)
+}
+
+func (s *httpTaskServer) applyUpdate(ctx context.Context, id int, description *string, status *task.TaskStatus) error {
+	if description != nil {
+		if err := s.service.UpdateTask(ctx, id, *description); err != nil {
+			return err
+		}
+	}
+
+	if status == nil {
+		return nil
+	}
+	switch *status {
+	case task.StatusInProgress:
+		return s.service.MarkTaskInProgress(ctx, id)
+	case task.StatusDone:
+		return s.service.MarkTaskDone(ctx, id)
+	default:
+		return s.service.MoveTask(ctx, id, *status)
+	}
+}
+
+func (s *httpTaskServer) deleteTask(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, task.ErrInvalidID)
+		return
+	}
+
+	s.mu.Lock()
+	err = s.service.DeleteTask(r.Context(), id)
+	s.mu.Unlock()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeHTTPError(w http.ResponseWriter, code int, errCode, message string) {
+	writeJSON(w, code, repository.HTTPErrorBody{Error: repository.HTTPError{Code: errCode, Message: message}})
+}
+
+// writeServiceError translates domain errors into HTTP status codes
+func writeServiceError(w http.ResponseWriter, err error) {
+	var taskErr task.TaskError
+	if errors.As(err, &taskErr) {
+		code := http.StatusBadRequest
+		switch taskErr.Code {
+		case task.ErrTaskNotFound.Code:
+			code = http.StatusNotFound
+		case task.ErrConflict.Code, task.ErrWIPLimit.Code, task.ErrHookRejected.Code:
+			code = http.StatusConflict
+		case task.ErrReadOnly.Code:
+			code = http.StatusForbidden
+		case task.ErrBodyTooLarge.Code:
+			code = http.StatusRequestEntityTooLarge
+		case task.ErrRateLimited.Code:
+			code = http.StatusTooManyRequests
+		case task.ErrStorage.Code, task.ErrHookFailed.Code:
+			code = http.StatusInternalServerError
+		}
+		writeJSON(w, code, repository.HTTPErrorBody{Error: repository.HTTPError{Code: taskErr.Code, Message: taskErr.Error(), Hint: taskErr.Hint}})
+		return
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		writeHTTPError(w, http.StatusServiceUnavailable, "UNAVAILABLE", err.Error())
+	default:
+		writeHTTPError(w, http.StatusInternalServerError, "INTERNAL", err.Error())
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// newHTTPTestServer starts a REST server over a fresh file repository
+func newHTTPTestServer(t *testing.T, token string, tasks []task.Task) *httptest.Server {
+	t.Helper()
+
+	repo := repository.NewFileTaskRepository(filepath.Join(t.TempDir(), "tasks.json"))
+	if err := repo.Save(t.Context(), tasks); err != nil {
+		t.Fatalf("failed to seed repository: %v", err)
+	}
+
+	auth, err := NewAuthenticator(repository.ServerConfig{Token: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(NewHTTPHandler(NewTaskService(repo), auth))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func doHTTP(t *testing.T, method, url, body string, header http.Header) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// TestHTTPServer_Tasks tests the per-task REST endpoints
+func TestHTTPServer_Tasks(t *testing.T) {
+	server := newHTTPTestServer(t, "", tasktest.FixedTasks(t))
+	base := server.URL + "/api/v1/tasks"
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{"list", http.MethodGet, "", "", http.StatusOK, `"Buy groceries"`},
+		{"list by status", http.MethodGet, "?status=done", "", http.StatusOK, `"Call mom"`},
+		{"invalid status", http.MethodGet, "?status=blocked", "", http.StatusBadRequest, `"INVALID_STATUS"`},
+		{"get", http.MethodGet, "/2", "", http.StatusOK, `"Write report"`},
+		{"get missing", http.MethodGet, "/42", "", http.StatusNotFound, `"NOT_FOUND"`},
+		{"get invalid id", http.MethodGet, "/abc", "", http.StatusBadRequest, `"INVALID_ID"`},
+		{"add", http.MethodPost, "", `{"description": "Remote task"}`, http.StatusCreated, `"id":4`},
+		{"add empty", http.MethodPost, "", `{"description": " "}`, http.StatusBadRequest, `"EMPTY_DESCRIPTION"`},
+		{"next id", http.MethodGet, "/next-id", "", http.StatusOK, `{"id":5}`},
+		{"update", http.MethodPatch, "/1", `{"description": "Buy milk", "status": "done"}`, http.StatusOK, `"status":"done"`},
+		{"delete", http.MethodDelete, "/3", "", http.StatusNoContent, ""},
+		{"delete missing", http.MethodDelete, "/3", "", http.StatusNotFound, `"NOT_FOUND"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := doHTTP(t, tt.method, base+tt.path, tt.body, nil)
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, resp.StatusCode, tt.wantCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(body), tt.wantBody) {
+				t.Errorf("%s %s body = %s, want %s", tt.method, tt.path, body, tt.wantBody)
+			}
+		})
+	}
+}
+
+// TestHTTPServer_ReplaceTasks tests conditional replacement of the task list
+func TestHTTPServer_ReplaceTasks(t *testing.T) {
+	server := newHTTPTestServer(t, "", tasktest.FixedTasks(t))
+	base := server.URL + "/api/v1/tasks"
+
+	etag := doHTTP(t, http.MethodGet, base, "", nil).Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("GET did not return an ETag")
+	}
+
+	data, _ := json.Marshal(tasktest.FixedTasks(t)[:1])
+	resp := doHTTP(t, http.MethodPut, base, string(data), http.Header{"If-Match": {etag}})
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("PUT status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+
+	resp = doHTTP(t, http.MethodPut, base, string(data), http.Header{"If-Match": {etag}})
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("stale PUT status = %d, want %d", resp.StatusCode, http.StatusPreconditionFailed)
+	}
+
+	duplicates, _ := json.Marshal(append(tasktest.FixedTasks(t), tasktest.FixedTasks(t)[0]))
+	resp = doHTTP(t, http.MethodPut, base, string(duplicates), nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("PUT with duplicate IDs status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+// TestHTTPServer_Token tests bearer token authentication
+func TestHTTPServer_Token(t *testing.T) {
+	server := newHTTPTestServer(t, "secret", nil)
+	base := server.URL + "/api/v1/tasks"
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		wantCode int
+	}{
+		{"missing", nil, http.StatusUnauthorized},
+		{"wrong", http.Header{"Authorization": {"Bearer nope"}}, http.StatusUnauthorized},
+		{"valid", http.Header{"Authorization": {"Bearer secret"}}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := doHTTP(t, http.MethodGet, base, "", tt.header); resp.StatusCode != tt.wantCode {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+// TestHTTPServer_WebUI tests that the web UI is public while the API is not
+func TestHTTPServer_WebUI(t *testing.T) {
+	server := newHTTPTestServer(t, "secret", nil)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"index", "/", http.StatusOK, "<title>Task Tracker</title>"},
+		{"script", "/app.js", http.StatusOK, "/statuses"},
+		{"missing", "/nope.js", http.StatusNotFound, ""},
+		{"api", "/api/v1/statuses", http.StatusUnauthorized, "UNAUTHORIZED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := doHTTP(t, http.MethodGet, server.URL+tt.path, "", nil)
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if !strings.Contains(string(body), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", body, tt.wantBody)
+			}
+		})
+	}
+
+	t.Run("statuses", func(t *testing.T) {
+		resp := doHTTP(t, http.MethodGet, server.URL+"/api/v1/statuses", "",
+			http.Header{"Authorization": {"Bearer secret"}})
+		var got httpWorkflow
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		want := task.DefaultWorkflow().Statuses
+		if len(got.Statuses) != len(want) || got.Statuses[0] != want[0] {
+			t.Errorf("statuses = %v, want %v", got.Statuses, want)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// ImportedTask is a task read from another tool, before it gets a local ID
+type ImportedTask struct {
+	Description string
+	Tags        []string
+	Priority    task.Priority
+	DueAt       *time.Time
+	// CreatedAt is zero when the source does not record it
+	CreatedAt time.Time
+	// Status defaults to todo when empty
+	Status task.TaskStatus
+	Notes  []task.Note
+	// ExternalRefs identify the task in its source for later imports
+	ExternalRefs map[string]string
+}
+
+// TaskSource is the port for tools tasks can be migrated from
+type TaskSource interface {
+	Tasks(ctx context.Context) ([]ImportedTask, error)
+}
+
+// MigrationReport lists what an import from a TaskSource did
+type MigrationReport struct {
+	Added []task.Task
+	// Duplicates were skipped because a task with the same description and
+	// creation date already exists
+	Duplicates []ImportedTask
+}
+
+// ImportTasks adds every task from the source that is not already present.
+// With dryRun the tasks are reported but not saved.
+func (s *TaskService) ImportTasks(ctx context.Context, source TaskSource, now time.Time, dryRun bool) (*MigrationReport, error) {
+	if !dryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	imported, err := source.Tasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks to import: %w", err)
+	}
+
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	before := slices.Clone(tasks)
+
+	// IDs are unique across lists, which the tasks listed may not all be in
+	nextID, err := s.store.NextID(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to get next ID", err)
+	}
+	for _, task := range tasks {
+		nextID = max(nextID, task.ID+1)
+	}
+
+	report := &MigrationReport{}
+	for _, item := range imported {
+		if slices.ContainsFunc(tasks, func(t task.Task) bool { return isDuplicateImport(t, item) }) {
+			report.Duplicates = append(report.Duplicates, item)
+			continue
+		}
+
+		opts := []task.TaskOption{task.WithTags(item.Tags...), task.WithPriority(item.Priority)}
+		if item.DueAt != nil {
+			opts = append(opts, task.WithDueDate(*item.DueAt))
+		}
+		for system, ref := range item.ExternalRefs {
+			opts = append(opts, WithExternalRef(system, ref))
+		}
+		added, err := s.newTask(nextID, item.Description, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("cannot import %q: %w", item.Description, err)
+		}
+
+		created := item.CreatedAt
+		if created.IsZero() {
+			created = now
+		}
+		added.CreatedAt, added.UpdatedAt = created, created
+		added.Notes = item.Notes
+		if item.Status != "" {
+			if !s.workflow.Has(item.Status) {
+				return nil, fmt.Errorf("cannot import %q: %w", item.Description, task.ErrInvalidStatus)
+			}
+			added.Status = item.Status
+		}
+
+		nextID++
+		tasks = append(tasks, *added)
+		report.Added = append(report.Added, *added)
+	}
+
+	if dryRun || len(report.Added) == 0 {
+		return report, nil
+	}
+	if err := s.save(ctx, before, tasks); err != nil {
+		return nil, repository.StorageError("failed to save tasks", err)
+	}
+	return report, nil
+}
+
+// isDuplicateImport matches tasks by source reference, or else by
+// description and, when the source records it, creation date
+func isDuplicateImport(task task.Task, item ImportedTask) bool {
+	for system, ref := range item.ExternalRefs {
+		if task.ExternalRefs[system] == ref {
+			return true
+		}
+	}
+	if !strings.EqualFold(strings.TrimSpace(task.Description), strings.TrimSpace(item.Description)) {
+		return false
+	}
+	if item.CreatedAt.IsZero() {
+		return true
+	}
+	return task.CreatedAt.In(item.CreatedAt.Location()).Format(time.DateOnly) ==
+		item.CreatedAt.Format(time.DateOnly)
+}
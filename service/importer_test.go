@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+const todoistCSVExport = `TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE
+section,Errands,,,,,,,,
+task,Buy groceries,,1,1,Me (1),,2024-01-05,en,Europe/Paris
+task,Water plants,,4,1,Me (1),,every monday,en,Europe/Paris
+note,Remember the list,,,,,,,,
+task,Call mom,,3,1,Me (1),,,en,Europe/Paris
+`
+
+const tickTickBackup = `"Date: 2024-01-01+0000"
+"Version: 7.1"
+"Status: 
+0 Normal
+1 Completed
+2 Archived"
+"Folder Name","List Name","Title","Kind","Tags","Content","Is Check list","Start Date","Due Date","Reminder","Repeat","Priority","Status","Created Time","Completed Time","Order","Timezone","Is All Day","Is Floating","Column Name","Column Order","View Mode","taskId","parentId"
+"","Work","Write report","TEXT","urgent, q1","","N","","2024-01-10T17:00:00+0000","","","5","0","2023-12-20T09:00:00+0000","","1","UTC","false","false","","","list","1",""
+"","Inbox","Old chore","TEXT","","","N","","","","","0","2","2023-11-01T09:00:00+0000","2023-11-02T09:00:00+0000","2","UTC","false","false","","","list","2",""
+`
+
+// staticSource is a TaskSource returning fixed tasks
+type staticSource []ImportedTask
+
+func (s staticSource) Tasks(context.Context) ([]ImportedTask, error) {
+	return s, nil
+}
+
+// TestTodoistCSV tests parsing a Todoist template export
+func TestTodoistCSV(t *testing.T) {
+	tasks, err := NewTodoistCSV(strings.NewReader(todoistCSVExport), "Home Stuff", tasktest.FixedTime()).Tasks(t.Context())
+	if err != nil {
+		t.Fatalf("Tasks() failed: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("Tasks() returned %d tasks, want 3", len(tasks))
+	}
+
+	first := tasks[0]
+	if first.Description != "Buy groceries" || first.Priority != task.PriorityUrgent ||
+		first.DueAt == nil || first.DueAt.Format(time.DateOnly) != "2024-01-05" {
+		t.Errorf("first task = %+v", first)
+	}
+	if len(first.Tags) != 1 || first.Tags[0] != "Home Stuff" {
+		t.Errorf("tags = %v, want project name", first.Tags)
+	}
+	if tasks[1].DueAt != nil || tasks[1].Priority != task.PriorityNone {
+		t.Errorf("recurring task = %+v, want no deadline", tasks[1])
+	}
+	if tasks[2].Priority != task.PriorityMedium {
+		t.Errorf("priority 3 mapped to %q, want medium", tasks[2].Priority)
+	}
+
+	if _, err := NewTodoistCSV(strings.NewReader("A,B\n1,2\n"), "", tasktest.FixedTime()).Tasks(t.Context()); err == nil {
+		t.Error("Tasks() accepted a CSV without Todoist columns")
+	}
+}
+
+// TestTodoistAPI tests importing through the Todoist API
+func TestTodoistAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer td-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/projects":
+			json.NewEncoder(w).Encode(map[string]any{"results": []map[string]string{
+				{"id": "p1", "name": "Inbox"}, {"id": "p2", "name": "Side Project"},
+			}})
+		case r.URL.Path == "/tasks" && r.URL.Query().Get("cursor") == "":
+			w.Write([]byte(`{"results": [{"content": "Ship v1", "project_id": "p2", "labels": ["work"],
+				"priority": 4, "due": {"date": "2024-02-01"}, "added_at": "2024-01-01T10:00:00Z"}],
+				"next_cursor": "page2"}`))
+		case r.URL.Path == "/tasks":
+			w.Write([]byte(`{"results": [{"content": "Read mail", "project_id": "p1", "priority": 1,
+				"due": {"date": "2024-02-02", "datetime": "2024-02-02T09:30:00Z"}}], "next_cursor": null}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tasks, err := NewTodoistAPI(server.URL, "td-token", tasktest.FixedTime()).Tasks(t.Context())
+	if err != nil {
+		t.Fatalf("Tasks() failed: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Tasks() returned %d tasks, want 2", len(tasks))
+	}
+	if got := tasks[0]; got.Priority != task.PriorityUrgent || strings.Join(got.Tags, ",") != "Side Project,work" ||
+		!got.CreatedAt.Equal(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("first task = %+v", got)
+	}
+	if got := tasks[1]; len(got.Tags) != 0 || got.DueAt == nil ||
+		!got.DueAt.Equal(time.Date(2024, 2, 2, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("second task = %+v", got)
+	}
+
+	if _, err := NewTodoistAPI(server.URL, "wrong", tasktest.FixedTime()).Tasks(t.Context()); err == nil {
+		t.Error("Tasks() with a bad token succeeded")
+	}
+	if _, err := NewTodoistAPI(server.URL, "", tasktest.FixedTime()).Tasks(t.Context()); err == nil {
+		t.Error("Tasks() without a token succeeded")
+	}
+}
+
+// TestTickTickCSV tests parsing a TickTick backup
+func TestTickTickCSV(t *testing.T) {
+	tasks, err := NewTickTickCSV(strings.NewReader(tickTickBackup)).Tasks(t.Context())
+	if err != nil {
+		t.Fatalf("Tasks() failed: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Tasks() returned %d tasks, want 2", len(tasks))
+	}
+
+	report := tasks[0]
+	if report.Priority != task.PriorityHigh || report.Status != "" || report.DueAt == nil ||
+		strings.Join(report.Tags, ",") != "Work,urgent,q1" {
+		t.Errorf("first task = %+v", report)
+	}
+	if tasks[1].Status != task.StatusDone || len(tasks[1].Tags) != 0 {
+		t.Errorf("archived task = %+v, want done without tags", tasks[1])
+	}
+}
+
+// TestTaskService_ImportTasks tests duplicate detection and dry runs
+func TestTaskService_ImportTasks(t *testing.T) {
+	created := tasktest.FixedTime()
+	source := staticSource{
+		{Description: "buy groceries", CreatedAt: created},                   // duplicate of task 1
+		{Description: "Buy groceries", CreatedAt: created.AddDate(0, 0, -7)}, // same text, other day
+		{Description: "Plan trip", Tags: []string{"Travel Plans"}, Priority: task.PriorityHigh},
+		{Description: "Old chore", Status: task.StatusDone},
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+		report, err := NewTaskService(repo).ImportTasks(t.Context(), source, created, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Added) != 3 || len(report.Duplicates) != 1 {
+			t.Errorf("ImportTasks() added %d, duplicates %d", len(report.Added), len(report.Duplicates))
+		}
+		if repo.SaveCallCount() != 0 {
+			t.Error("dry run saved tasks")
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+		service := NewTaskService(repo)
+		if _, err := service.ImportTasks(t.Context(), source, created, false); err != nil {
+			t.Fatal(err)
+		}
+
+		plan, _ := repo.GetTask(5)
+		if plan.Description != "Plan trip" || plan.Priority != task.PriorityHigh ||
+			strings.Join(plan.Tags, ",") != "travel-plans" || !plan.CreatedAt.Equal(created) {
+			t.Errorf("imported task = %+v", plan)
+		}
+		if chore, _ := repo.GetTask(6); chore.Status != task.StatusDone {
+			t.Errorf("completed task imported as %q", chore.Status)
+		}
+
+		report, err := service.ImportTasks(t.Context(), source, created, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Added) != 0 || len(report.Duplicates) != 4 {
+			t.Errorf("re-import added %d, duplicates %d, want 0 and 4", len(report.Added), len(report.Duplicates))
+		}
+	})
+}
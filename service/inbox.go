@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// InboxTag marks tasks captured with the in command and not yet triaged
+const InboxTag = "inbox"
+
+// Triage is what to make of an inbox task; empty fields keep the task as
+// it is
+type Triage struct {
+	Status   task.TaskStatus
+	Priority task.Priority
+	Due      *time.Time
+	Tags     []string
+}
+
+// Inbox returns the open tasks waiting to be triaged, oldest first
+func (s *TaskService) Inbox(ctx context.Context) ([]task.Task, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(tasks, func(task task.Task) bool {
+		return !task.IsOpen() || !slices.Contains(task.Tags, InboxTag)
+	}), nil
+}
+
+// TriageTask applies a triage decision to a task and takes it out of the
+// inbox, following the workflow and its WIP limits
+func (s *TaskService) TriageTask(ctx context.Context, id int, triage Triage) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if triage.Status != "" {
+		if err := s.checkWIPLimit(ctx, id, triage.Status); err != nil {
+			return err
+		}
+	}
+	return s.updateTask(ctx, id, func(t *task.Task) error {
+		t.Retag(triage.Tags, []string{InboxTag})
+		if triage.Priority != task.PriorityNone {
+			if err := t.SetPriority(triage.Priority); err != nil {
+				return err
+			}
+		}
+		if triage.Due != nil {
+			t.SetDue(triage.Due)
+		}
+		if triage.Status != "" {
+			return t.MoveTo(triage.Status, s.workflow)
+		}
+		return nil
+	})
+}
@@ -1,4 +1,4 @@
-package main
+package service
 
 import (
 	"fmt"
@@ -6,6 +6,10 @@ import (
 	"slices"
 	"testing"
 	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
 )
 
 // TestFullTaskLifecycle tests complete task management workflow
@@ -13,13 +17,14 @@ func TestFullTaskLifecycle(t *testing.T) {
 	// Use real file repository for true integration test
 	tmpFile := "integration_test_tasks.json"
 	defer os.Remove(tmpFile)
+	defer os.Remove(tmpFile + ".lock")
 
-	repo := NewFileTaskRepository(tmpFile)
+	repo := repository.NewFileTaskRepository(tmpFile)
 	service := NewTaskService(repo)
 
 	t.Run("complete task lifecycle", func(t *testing.T) {
 		// 1. Start with empty system
-		tasks, err := service.ListTasks("")
+		tasks, err := service.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Fatalf("Initial ListTasks() failed: %v", err)
 		}
@@ -28,23 +33,23 @@ func TestFullTaskLifecycle(t *testing.T) {
 		}
 
 		// 2. Add multiple tasks
-		task1, err := service.AddTask("Buy groceries")
+		task1, err := service.AddTask(t.Context(), "Buy groceries")
 		if err != nil {
 			t.Fatalf("AddTask() 1 failed: %v", err)
 		}
 
-		task2, err := service.AddTask("Complete project report")
+		task2, err := service.AddTask(t.Context(), "Complete project report")
 		if err != nil {
 			t.Fatalf("AddTask() 2 failed: %v", err)
 		}
 
-		task3, err := service.AddTask("Call mom")
+		task3, err := service.AddTask(t.Context(), "Call mom")
 		if err != nil {
 			t.Fatalf("AddTask() 3 failed: %v", err)
 		}
 
 		// 3. Verify all tasks are present
-		allTasks, err := service.ListTasks("")
+		allTasks, err := service.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Fatalf("ListTasks() after adding failed: %v", err)
 		}
@@ -53,25 +58,25 @@ func TestFullTaskLifecycle(t *testing.T) {
 		}
 
 		// 4. Start working on first task
-		err = service.MarkTaskInProgress(task1.ID)
+		err = service.MarkTaskInProgress(t.Context(), task1.ID)
 		if err != nil {
 			t.Fatalf("MarkTaskInProgress() failed: %v", err)
 		}
 
 		// 5. Complete second task directly
-		err = service.MarkTaskDone(task2.ID)
+		err = service.MarkTaskDone(t.Context(), task2.ID)
 		if err != nil {
 			t.Fatalf("MarkTaskDone() failed: %v", err)
 		}
 
 		// 6. Update third task description
-		err = service.UpdateTask(task3.ID, "Call mom and discuss weekend plans")
+		err = service.UpdateTask(t.Context(), task3.ID, "Call mom and discuss weekend plans")
 		if err != nil {
 			t.Fatalf("UpdateTask() failed: %v", err)
 		}
 
 		// 7. Verify task statuses
-		todoTasks, err := service.ListTasks("todo")
+		todoTasks, err := service.ListTasks(t.Context(), "todo")
 		if err != nil {
 			t.Fatalf("ListTasks(todo) failed: %v", err)
 		}
@@ -85,7 +90,7 @@ func TestFullTaskLifecycle(t *testing.T) {
 			t.Errorf("Task description not updated correctly")
 		}
 
-		inProgressTasks, err := service.ListTasks("in-progress")
+		inProgressTasks, err := service.ListTasks(t.Context(), "in-progress")
 		if err != nil {
 			t.Fatalf("ListTasks(in-progress) failed: %v", err)
 		}
@@ -96,7 +101,7 @@ func TestFullTaskLifecycle(t *testing.T) {
 			t.Errorf("Wrong task in in-progress list")
 		}
 
-		doneTasks, err := service.ListTasks("done")
+		doneTasks, err := service.ListTasks(t.Context(), "done")
 		if err != nil {
 			t.Fatalf("ListTasks(done) failed: %v", err)
 		}
@@ -108,19 +113,19 @@ func TestFullTaskLifecycle(t *testing.T) {
 		}
 
 		// 8. Complete the in-progress task
-		err = service.MarkTaskDone(task1.ID)
+		err = service.MarkTaskDone(t.Context(), task1.ID)
 		if err != nil {
 			t.Fatalf("MarkTaskDone() for task1 failed: %v", err)
 		}
 
 		// 9. Delete one completed task
-		err = service.DeleteTask(task2.ID)
+		err = service.DeleteTask(t.Context(), task2.ID)
 		if err != nil {
 			t.Fatalf("DeleteTask() failed: %v", err)
 		}
 
 		// 10. Verify final state
-		finalTasks, err := service.ListTasks("")
+		finalTasks, err := service.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Fatalf("Final ListTasks() failed: %v", err)
 		}
@@ -145,8 +150,8 @@ func TestFullTaskLifecycle(t *testing.T) {
 		}
 
 		// 11. Verify persistence by creating new service instance
-		newService := NewTaskService(NewFileTaskRepository(tmpFile))
-		persistedTasks, err := newService.ListTasks("")
+		newService := NewTaskService(repository.NewFileTaskRepository(tmpFile))
+		persistedTasks, err := newService.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Fatalf("ListTasks() with new service failed: %v", err)
 		}
@@ -155,7 +160,7 @@ func TestFullTaskLifecycle(t *testing.T) {
 			t.Errorf("Persisted tasks count = %d, want 2", len(persistedTasks))
 		}
 
-		AssertTasksEqual(t, finalTasks, persistedTasks)
+		tasktest.AssertTasksEqual(t, finalTasks, persistedTasks)
 	})
 }
 
@@ -163,20 +168,21 @@ func TestFullTaskLifecycle(t *testing.T) {
 func TestConcurrentAccess(t *testing.T) {
 	tmpFile := "concurrent_test_tasks.json"
 	defer os.Remove(tmpFile)
+	defer os.Remove(tmpFile + ".lock")
 
 	t.Run("multiple service instances", func(t *testing.T) {
 		// Create two service instances sharing the same file
-		service1 := NewTaskService(NewFileTaskRepository(tmpFile))
-		service2 := NewTaskService(NewFileTaskRepository(tmpFile))
+		service1 := NewTaskService(repository.NewFileTaskRepository(tmpFile))
+		service2 := NewTaskService(repository.NewFileTaskRepository(tmpFile))
 
 		// Service 1 adds a task
-		task1, err := service1.AddTask("Task from service 1")
+		task1, err := service1.AddTask(t.Context(), "Task from service 1")
 		if err != nil {
 			t.Fatalf("Service1 AddTask() failed: %v", err)
 		}
 
 		// Service 2 should see the task
-		tasks2, err := service2.ListTasks("")
+		tasks2, err := service2.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Fatalf("Service2 ListTasks() failed: %v", err)
 		}
@@ -185,13 +191,13 @@ func TestConcurrentAccess(t *testing.T) {
 		}
 
 		// Service 2 adds another task
-		task2, err := service2.AddTask("Task from service 2")
+		task2, err := service2.AddTask(t.Context(), "Task from service 2")
 		if err != nil {
 			t.Fatalf("Service2 AddTask() failed: %v", err)
 		}
 
 		// Service 1 should see both tasks
-		tasks1, err := service1.ListTasks("")
+		tasks1, err := service1.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Fatalf("Service1 ListTasks() after service2 add failed: %v", err)
 		}
@@ -205,18 +211,18 @@ func TestConcurrentAccess(t *testing.T) {
 		}
 
 		// Service 1 modifies task created by service 2
-		err = service1.MarkTaskInProgress(task2.ID)
+		err = service1.MarkTaskInProgress(t.Context(), task2.ID)
 		if err != nil {
 			t.Fatalf("Service1 MarkTaskInProgress() on service2's task failed: %v", err)
 		}
 
 		// Service 2 should see the modification
-		updatedTasks, err := service2.ListTasks("")
+		updatedTasks, err := service2.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Fatalf("Service2 ListTasks() after modification failed: %v", err)
 		}
 
-		var modifiedTask *Task
+		var modifiedTask *task.Task
 		for _, task := range updatedTasks {
 			if task.ID == task2.ID {
 				modifiedTask = &task
@@ -228,7 +234,7 @@ func TestConcurrentAccess(t *testing.T) {
 			t.Fatalf("Modified task not found")
 		}
 
-		if modifiedTask.Status != StatusInProgress {
+		if modifiedTask.Status != task.StatusInProgress {
 			t.Errorf("Task status should be in-progress, got %v", modifiedTask.Status)
 		}
 	})
@@ -238,27 +244,28 @@ func TestConcurrentAccess(t *testing.T) {
 func TestDataPersistence(t *testing.T) {
 	tmpFile := "persistence_test_tasks.json"
 	defer os.Remove(tmpFile)
+	defer os.Remove(tmpFile + ".lock")
 
-	originalTasks := MixedStatusTasks(t)
+	originalTasks := tasktest.MixedStatusTasks(t)
 
 	t.Run("data survives service recreation", func(t *testing.T) {
 		// Create service and add tasks
 		{
-			service := NewTaskService(NewFileTaskRepository(tmpFile))
+			service := NewTaskService(repository.NewFileTaskRepository(tmpFile))
 			for _, task := range originalTasks {
-				_, err := service.AddTask(task.Description)
+				_, err := service.AddTask(t.Context(), task.Description)
 				if err != nil {
 					t.Fatalf("AddTask() failed: %v", err)
 				}
 			}
 
 			// Modify some tasks
-			err := service.MarkTaskInProgress(2)
+			err := service.MarkTaskInProgress(t.Context(), 2)
 			if err != nil {
 				t.Fatalf("MarkTaskInProgress() failed: %v", err)
 			}
 
-			err = service.MarkTaskDone(3)
+			err = service.MarkTaskDone(t.Context(), 3)
 			if err != nil {
 				t.Fatalf("MarkTaskDone() failed: %v", err)
 			}
@@ -266,10 +273,10 @@ func TestDataPersistence(t *testing.T) {
 
 		// Create new service instance
 		{
-			newService := NewTaskService(NewFileTaskRepository(tmpFile))
+			newService := NewTaskService(repository.NewFileTaskRepository(tmpFile))
 
 			// Verify data persisted
-			tasks, err := newService.ListTasks("")
+			tasks, err := newService.ListTasks(t.Context(), "")
 			if err != nil {
 				t.Fatalf("ListTasks() with new service failed: %v", err)
 			}
@@ -279,7 +286,7 @@ func TestDataPersistence(t *testing.T) {
 			}
 
 			// Verify statuses were persisted
-			inProgressTasks, err := newService.ListTasks("in-progress")
+			inProgressTasks, err := newService.ListTasks(t.Context(), "in-progress")
 			if err != nil {
 				t.Fatalf("ListTasks(in-progress) failed: %v", err)
 			}
@@ -290,7 +297,7 @@ func TestDataPersistence(t *testing.T) {
 				t.Errorf("Wrong task marked as in-progress")
 			}
 
-			doneTasks, err := newService.ListTasks("done")
+			doneTasks, err := newService.ListTasks(t.Context(), "done")
 			if err != nil {
 				t.Fatalf("ListTasks(done) failed: %v", err)
 			}
@@ -302,12 +309,12 @@ func TestDataPersistence(t *testing.T) {
 			}
 
 			// Verify we can continue operations
-			_, err = newService.AddTask("New task after restart")
+			_, err = newService.AddTask(t.Context(), "New task after restart")
 			if err != nil {
 				t.Errorf("AddTask() after restart failed: %v", err)
 			}
 
-			finalTasks, err := newService.ListTasks("")
+			finalTasks, err := newService.ListTasks(t.Context(), "")
 			if err != nil {
 				t.Fatalf("Final ListTasks() failed: %v", err)
 			}
@@ -326,12 +333,13 @@ func TestDataPersistence(t *testing.T) {
 func TestErrorRecovery(t *testing.T) {
 	tmpFile := "error_recovery_test_tasks.json"
 	defer os.Remove(tmpFile)
+	defer os.Remove(tmpFile + ".lock")
 
 	t.Run("recovery from file corruption", func(t *testing.T) {
-		service := NewTaskService(NewFileTaskRepository(tmpFile))
+		service := NewTaskService(repository.NewFileTaskRepository(tmpFile))
 
 		// Add some tasks normally
-		_, err := service.AddTask("Task 1")
+		_, err := service.AddTask(t.Context(), "Task 1")
 		if err != nil {
 			t.Fatalf("Initial AddTask() failed: %v", err)
 		}
@@ -343,7 +351,7 @@ func TestErrorRecovery(t *testing.T) {
 		}
 
 		// Service should handle corruption gracefully
-		_, err = service.ListTasks("")
+		_, err = service.ListTasks(t.Context(), "")
 		if err == nil {
 			t.Errorf("ListTasks() should fail with corrupted file")
 		}
@@ -355,12 +363,12 @@ func TestErrorRecovery(t *testing.T) {
 		}
 
 		// Now operations should work again (starting fresh)
-		_, err = service.AddTask("Recovery task")
+		_, err = service.AddTask(t.Context(), "Recovery task")
 		if err != nil {
 			t.Errorf("AddTask() should work after removing corrupted file: %v", err)
 		}
 
-		tasks, err := service.ListTasks("")
+		tasks, err := service.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Errorf("ListTasks() should work after recovery: %v", err)
 		}
@@ -378,8 +386,9 @@ func TestPerformanceCharacteristics(t *testing.T) {
 
 	tmpFile := "performance_test_tasks.json"
 	defer os.Remove(tmpFile)
+	defer os.Remove(tmpFile + ".lock")
 
-	service := NewTaskService(NewFileTaskRepository(tmpFile))
+	service := NewTaskService(repository.NewFileTaskRepository(tmpFile))
 
 	t.Run("large task list performance", func(t *testing.T) {
 		const taskCount = 500 // Reasonable for integration test
@@ -388,7 +397,7 @@ func TestPerformanceCharacteristics(t *testing.T) {
 
 		// Add many tasks
 		for i := range taskCount {
-			_, err := service.AddTask(fmt.Sprintf("Performance test task %d", i+1))
+			_, err := service.AddTask(t.Context(), fmt.Sprintf("Performance test task %d", i+1))
 			if err != nil {
 				t.Fatalf("AddTask() %d failed: %v", i+1, err)
 			}
@@ -400,7 +409,7 @@ func TestPerformanceCharacteristics(t *testing.T) {
 
 		// Test list performance
 		start = time.Now()
-		tasks, err := service.ListTasks("")
+		tasks, err := service.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Fatalf("ListTasks() failed: %v", err)
 		}
@@ -414,7 +423,7 @@ func TestPerformanceCharacteristics(t *testing.T) {
 
 		// Test filtered list performance
 		start = time.Now()
-		todoTasks, err := service.ListTasks("todo")
+		todoTasks, err := service.ListTasks(t.Context(), "todo")
 		if err != nil {
 			t.Fatalf("ListTasks(todo) failed: %v", err)
 		}
@@ -443,8 +452,9 @@ func TestPerformanceCharacteristics(t *testing.T) {
 func TestRealWorldScenarios(t *testing.T) {
 	tmpFile := "real_world_test_tasks.json"
 	defer os.Remove(tmpFile)
+	defer os.Remove(tmpFile + ".lock")
 
-	service := NewTaskService(NewFileTaskRepository(tmpFile))
+	service := NewTaskService(repository.NewFileTaskRepository(tmpFile))
 
 	t.Run("daily task management workflow", func(t *testing.T) {
 		// Morning: Add today's tasks
@@ -458,7 +468,7 @@ func TestRealWorldScenarios(t *testing.T) {
 
 		var taskIDs []int
 		for _, desc := range morningTasks {
-			task, err := service.AddTask(desc)
+			task, err := service.AddTask(t.Context(), desc)
 			if err != nil {
 				t.Fatalf("AddTask(%s) failed: %v", desc, err)
 			}
@@ -466,42 +476,42 @@ func TestRealWorldScenarios(t *testing.T) {
 		}
 
 		// Start with first task
-		err := service.MarkTaskInProgress(taskIDs[0])
+		err := service.MarkTaskInProgress(t.Context(), taskIDs[0])
 		if err != nil {
 			t.Fatalf("MarkTaskInProgress() failed: %v", err)
 		}
 
 		// Complete first task, start second
-		err = service.MarkTaskDone(taskIDs[0])
+		err = service.MarkTaskDone(t.Context(), taskIDs[0])
 		if err != nil {
 			t.Fatalf("MarkTaskDone() failed: %v", err)
 		}
 
-		err = service.MarkTaskInProgress(taskIDs[1])
+		err = service.MarkTaskInProgress(t.Context(), taskIDs[1])
 		if err != nil {
 			t.Fatalf("MarkTaskInProgress() failed: %v", err)
 		}
 
 		// Midday: Reprioritize - update a task description
-		err = service.UpdateTask(taskIDs[3], "Work on feature X - implement user authentication")
+		err = service.UpdateTask(t.Context(), taskIDs[3], "Work on feature X - implement user authentication")
 		if err != nil {
 			t.Fatalf("UpdateTask() failed: %v", err)
 		}
 
 		// Complete meeting
-		err = service.MarkTaskDone(taskIDs[1])
+		err = service.MarkTaskDone(t.Context(), taskIDs[1])
 		if err != nil {
 			t.Fatalf("MarkTaskDone() failed: %v", err)
 		}
 
 		// Afternoon: Work on main task
-		err = service.MarkTaskInProgress(taskIDs[3])
+		err = service.MarkTaskInProgress(t.Context(), taskIDs[3])
 		if err != nil {
 			t.Fatalf("MarkTaskInProgress() failed: %v", err)
 		}
 
 		// End of day: Check progress
-		inProgressTasks, err := service.ListTasks("in-progress")
+		inProgressTasks, err := service.ListTasks(t.Context(), "in-progress")
 		if err != nil {
 			t.Fatalf("ListTasks(in-progress) failed: %v", err)
 		}
@@ -509,7 +519,7 @@ func TestRealWorldScenarios(t *testing.T) {
 			t.Errorf("Should have 1 in-progress task, got %d", len(inProgressTasks))
 		}
 
-		doneTasks, err := service.ListTasks("done")
+		doneTasks, err := service.ListTasks(t.Context(), "done")
 		if err != nil {
 			t.Fatalf("ListTasks(done) failed: %v", err)
 		}
@@ -517,7 +527,7 @@ func TestRealWorldScenarios(t *testing.T) {
 			t.Errorf("Should have 2 done tasks, got %d", len(doneTasks))
 		}
 
-		todoTasks, err := service.ListTasks("todo")
+		todoTasks, err := service.ListTasks(t.Context(), "todo")
 		if err != nil {
 			t.Fatalf("ListTasks(todo) failed: %v", err)
 		}
@@ -526,12 +536,12 @@ func TestRealWorldScenarios(t *testing.T) {
 		}
 
 		// Verify updated task description
-		allTasks, err := service.ListTasks("")
+		allTasks, err := service.ListTasks(t.Context(), "")
 		if err != nil {
 			t.Fatalf("ListTasks() failed: %v", err)
 		}
 
-		var updatedTask *Task
+		var updatedTask *task.Task
 		for _, task := range allTasks {
 			if task.ID == taskIDs[3] {
 				updatedTask = &task
@@ -563,7 +573,7 @@ func TestRealWorldScenarios(t *testing.T) {
 
 		var projectTaskIDs []int
 		for _, desc := range projectTasks {
-			task, err := service.AddTask(desc)
+			task, err := service.AddTask(t.Context(), desc)
 			if err != nil {
 				t.Fatalf("AddTask(%s) failed: %v", desc, err)
 			}
@@ -580,7 +590,7 @@ func TestRealWorldScenarios(t *testing.T) {
 		for phaseNum, taskIndices := range phases {
 			// Start all tasks in phase
 			for _, idx := range taskIndices {
-				err := service.MarkTaskInProgress(projectTaskIDs[idx])
+				err := service.MarkTaskInProgress(t.Context(), projectTaskIDs[idx])
 				if err != nil {
 					t.Fatalf("Phase %d: MarkTaskInProgress() failed: %v", phaseNum, err)
 				}
@@ -588,7 +598,7 @@ func TestRealWorldScenarios(t *testing.T) {
 
 			// Complete all tasks in phase
 			for _, idx := range taskIndices {
-				err := service.MarkTaskDone(projectTaskIDs[idx])
+				err := service.MarkTaskDone(t.Context(), projectTaskIDs[idx])
 				if err != nil {
 					t.Fatalf("Phase %d: MarkTaskDone() failed: %v", phaseNum, err)
 				}
@@ -596,7 +606,7 @@ func TestRealWorldScenarios(t *testing.T) {
 		}
 
 		// Verify project completion
-		doneTasks, err := service.ListTasks("done")
+		doneTasks, err := service.ListTasks(t.Context(), "done")
 		if err != nil {
 			t.Fatalf("ListTasks(done) failed: %v", err)
 		}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// ExternalIssue is a task-like item in an external tracker
+type ExternalIssue struct {
+	// Ref identifies the issue in its tracker, e.g. "owner/name#12"
+	Ref       string
+	Title     string
+	Closed    bool
+	CreatedAt time.Time
+}
+
+// IssueTracker is the port for external issue trackers tasks can be
+// imported from and pushed to
+type IssueTracker interface {
+	// Name is the key under which tasks store their issue reference
+	Name() string
+	// OpenIssues lists the issues in scope that are still open
+	OpenIssues(ctx context.Context) ([]ExternalIssue, error)
+	// CreateIssue opens an issue for the task
+	CreateIssue(ctx context.Context, task task.Task) (ExternalIssue, error)
+	// SetClosed closes or reopens an issue
+	SetClosed(ctx context.Context, ref string, closed bool) error
+}
+
+// WithExternalRef links a new task to an item in an external system
+func WithExternalRef(system, ref string) task.TaskOption {
+	return func(t *task.Task) error {
+		if t.ExternalRefs == nil {
+			t.ExternalRefs = make(map[string]string)
+		}
+		t.ExternalRefs[system] = ref
+		return nil
+	}
+}
+
+// ImportReport lists the tasks created and updated by an import
+type ImportReport struct {
+	Added   []task.Task
+	Updated []task.Task
+}
+
+// ImportIssues creates a task for every open issue not imported before and
+// refreshes the description of tasks already linked to an issue
+func (s *TaskService) ImportIssues(ctx context.Context, tracker IssueTracker) (*ImportReport, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	issues, err := tracker.OpenIssues(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	before := slices.Clone(tasks)
+
+	// IDs are unique across lists, which the tasks listed may not all be in
+	nextID, err := s.store.NextID(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to get next ID", err)
+	}
+	for _, task := range tasks {
+		nextID = max(nextID, task.ID+1)
+	}
+
+	report := &ImportReport{}
+	for _, issue := range issues {
+		index := slices.IndexFunc(tasks, func(t task.Task) bool {
+			return t.ExternalRefs[tracker.Name()] == issue.Ref
+		})
+		if index >= 0 {
+			if tasks[index].Description != issue.Title {
+				if err := tasks[index].UpdateDescription(issue.Title, s.workflow.Descriptions); err == nil {
+					report.Updated = append(report.Updated, tasks[index])
+				}
+			}
+			continue
+		}
+
+		task, err := s.newTask(nextID, issue.Title, WithExternalRef(tracker.Name(), issue.Ref))
+		if err != nil {
+			continue
+		}
+		nextID++
+		tasks = append(tasks, *task)
+		report.Added = append(report.Added, *task)
+	}
+
+	if len(report.Added) == 0 && len(report.Updated) == 0 {
+		return report, nil
+	}
+	if err := s.save(ctx, before, tasks); err != nil {
+		return nil, repository.StorageError("failed to save tasks", err)
+	}
+	return report, nil
+}
+
+// PushReport lists what a push changed in the external tracker
+type PushReport struct {
+	Created  []task.Task
+	Closed   []task.Task
+	Reopened []task.Task
+}
+
+// PushIssues mirrors tasks to the tracker: open unlinked tasks get a new
+// issue, and linked issues are closed or reopened to match the task status.
+// An empty ids list pushes every task.
+func (s *TaskService) PushIssues(ctx context.Context, tracker IssueTracker, ids []int, dryRun bool) (*PushReport, error) {
+	if !dryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	issues, err := tracker.OpenIssues(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+	open := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		open[issue.Ref] = true
+	}
+
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	before := slices.Clone(tasks)
+
+	report := &PushReport{}
+	for i := range tasks {
+		task := &tasks[i]
+		if len(ids) > 0 && !slices.Contains(ids, task.ID) {
+			continue
+		}
+
+		ref, linked := task.ExternalRefs[tracker.Name()]
+		switch {
+		case !linked && task.IsOpen():
+			if !dryRun {
+				issue, err := tracker.CreateIssue(ctx, *task)
+				if err != nil {
+					return report, s.savePartialPush(ctx, before, tasks, err)
+				}
+				task.SetExternalRef(tracker.Name(), issue.Ref)
+			}
+			report.Created = append(report.Created, *task)
+		case linked && !task.IsOpen() && open[ref]:
+			if !dryRun {
+				if err := tracker.SetClosed(ctx, ref, true); err != nil {
+					return report, s.savePartialPush(ctx, before, tasks, err)
+				}
+			}
+			report.Closed = append(report.Closed, *task)
+		case linked && task.IsOpen() && !open[ref]:
+			if !dryRun {
+				if err := tracker.SetClosed(ctx, ref, false); err != nil {
+					return report, s.savePartialPush(ctx, before, tasks, err)
+				}
+			}
+			report.Reopened = append(report.Reopened, *task)
+		}
+	}
+
+	if dryRun || len(report.Created) == 0 {
+		return report, nil
+	}
+	if err := s.save(ctx, before, tasks); err != nil {
+		return nil, repository.StorageError("failed to save tasks", err)
+	}
+	return report, nil
+}
+
+// savePartialPush keeps the links of issues created before a push failed,
+// so retrying does not create duplicates
+func (s *TaskService) savePartialPush(ctx context.Context, before, tasks []task.Task, pushErr error) error {
+	if err := s.save(ctx, before, tasks); err != nil {
+		return fmt.Errorf("%w (and failed to save created issue links: %v)", pushErr, err)
+	}
+	return pushErr
+}
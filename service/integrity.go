@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Doctor checks stored tasks for integrity problems and optionally repairs them
+func (s *TaskService) Doctor(ctx context.Context, opts task.DoctorOptions) (*task.DoctorReport, error) {
+	if opts.Repair && !opts.DryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	var (
+		tasks  []task.Task
+		issues []task.Issue
+		err    error
+	)
+	if inspector, ok := repository.RepositoryAs[task.StoreInspector](s.repo); ok {
+		tasks, issues, err = inspector.Inspect(ctx)
+	} else {
+		tasks, err = s.store.List(ctx, repository.TaskFilter{})
+	}
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+
+	report := &task.DoctorReport{Issues: append(issues, task.CheckTasks(tasks, s.workflow)...)}
+	report.Remaining = report.Issues
+	if !opts.Repair || len(report.Issues) == 0 {
+		return report, nil
+	}
+
+	// A file that cannot be parsed must not be overwritten with an empty list
+	for _, issue := range report.Issues {
+		if issue.Kind == task.IssueInvalidJSON {
+			return report, nil
+		}
+	}
+
+	repaired, fixes := task.RepairTasks(tasks, s.workflow, opts.Now)
+	for _, issue := range issues {
+		// Saving rewrites the file in the current format with a fresh checksum
+		fixes = append(fixes, task.Issue{Kind: issue.Kind, Message: "rewrote data file", Fixable: true})
+	}
+	report.Fixes = fixes
+	report.Remaining = task.CheckTasks(repaired, s.workflow)
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	if err := s.saveAll(ctx, tasks, repaired); err != nil {
+		return nil, repository.StorageError("failed to save tasks", err)
+	}
+	report.Saved = true
+
+	return report, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_Doctor tests checking and repairing through the service
+func TestTaskService_Doctor(t *testing.T) {
+	now := tasktest.FixedTime()
+
+	t.Run("check only does not save", func(t *testing.T) {
+		repo := tasktest.NewMockRepository().WithTasks(tasktest.BrokenTasks())
+		report, err := NewTaskService(repo).Doctor(t.Context(), task.DoctorOptions{Now: now})
+		if err != nil {
+			t.Fatalf("Doctor() failed: %v", err)
+		}
+		if len(report.Issues) != 5 || report.Saved || repo.SaveCallCount() != 0 {
+			t.Errorf("Doctor() issues = %d, saved = %v", len(report.Issues), report.Saved)
+		}
+	})
+
+	t.Run("dry run computes fixes without saving", func(t *testing.T) {
+		repo := tasktest.NewMockRepository().WithTasks(tasktest.BrokenTasks())
+		report, err := NewTaskService(repo).Doctor(t.Context(),
+			task.DoctorOptions{Repair: true, DryRun: true, Now: now})
+		if err != nil {
+			t.Fatalf("Doctor() failed: %v", err)
+		}
+		if len(report.Fixes) == 0 || len(report.Remaining) != 0 {
+			t.Errorf("Doctor() fixes = %v, remaining = %v", report.Fixes, report.Remaining)
+		}
+		if repo.SaveCallCount() != 0 {
+			t.Error("dry run saved tasks")
+		}
+	})
+
+	t.Run("repair saves fixed tasks", func(t *testing.T) {
+		repo := tasktest.NewMockRepository().WithTasks(tasktest.BrokenTasks())
+		report, err := NewTaskService(repo).Doctor(t.Context(), task.DoctorOptions{Repair: true, Now: now})
+		if err != nil {
+			t.Fatalf("Doctor() failed: %v", err)
+		}
+		if !report.Saved {
+			t.Fatal("Doctor() did not save")
+		}
+		if issues := task.CheckTasks(repo.GetStoredTasks(), task.DefaultWorkflow()); len(issues) != 0 {
+			t.Errorf("stored tasks still have issues: %v", issues)
+		}
+	})
+
+	t.Run("corrupt file is never overwritten", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		if err := os.WriteFile(path, []byte(`{"schemaVersion": 2, "tasks": [`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		report, err := NewTaskService(repository.NewFileTaskRepository(path)).Doctor(t.Context(),
+			task.DoctorOptions{Repair: true, Now: now})
+		if err != nil {
+			t.Fatalf("Doctor() failed: %v", err)
+		}
+		if len(report.Remaining) != 1 || report.Remaining[0].Kind != task.IssueInvalidJSON || report.Saved {
+			t.Errorf("Doctor() remaining = %v, saved = %v", report.Remaining, report.Saved)
+		}
+		if data, _ := os.ReadFile(path); string(data) != `{"schemaVersion": 2, "tasks": [` {
+			t.Errorf("corrupt file was modified: %q", data)
+		}
+	})
+}
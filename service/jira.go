@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// JiraRefSystem is the ExternalRefs key of Jira issue keys
+const JiraRefSystem = "jira"
+
+// defaultJiraPriorities maps the priorities of a new Jira site
+var defaultJiraPriorities = map[string]task.Priority{
+	"highest": task.PriorityUrgent,
+	"blocker": task.PriorityUrgent,
+	"high":    task.PriorityHigh,
+	"medium":  task.PriorityMedium,
+	"low":     task.PriorityLow,
+	"lowest":  task.PriorityLow,
+}
+
+// Jira Search Source (Adapter)
+//
+// JiraSearch imports the issues a JQL query finds. Summaries become
+// descriptions, and the priority, tag and due date fields are read as
+// mapped in JiraFieldMap. Status categories map to todo, in-progress and
+// done.
+//
+// Jira Cloud is used when an email is configured, with basic
+// authentication and the /rest/api/3/search/jql endpoint. Otherwise the
+// token is a Data Center personal access token and /rest/api/2/search is
+// used.
+type JiraSearch struct {
+	config  repository.JiraConfig
+	jql     string
+	keepKey bool
+	client  *http.Client
+	now     time.Time
+}
+
+// NewJiraSearch creates a source for the issues jql finds. With keepKey
+// every task records its issue key under JiraRefSystem. now resolves
+// date-only due dates.
+func NewJiraSearch(config repository.JiraConfig, jql string, keepKey bool, now time.Time) *JiraSearch {
+	config.URL = strings.TrimRight(config.URL, "/")
+	if config.Fields.Tags == "" {
+		config.Fields.Tags = "labels"
+	}
+	if config.Fields.Due == "" {
+		config.Fields.Due = "duedate"
+	}
+	return &JiraSearch{
+		config:  config,
+		jql:     jql,
+		keepKey: keepKey,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		now:     now,
+	}
+}
+
+type jiraIssue struct {
+	Key    string                     `json:"key"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+type jiraPage struct {
+	Issues []jiraIssue `json:"issues"`
+	// Cloud pages with tokens, Data Center with offsets
+	NextPageToken string `json:"nextPageToken"`
+	IsLast        bool   `json:"isLast"`
+	StartAt       int    `json:"startAt"`
+	Total         int    `json:"total"`
+}
+
+func (j *JiraSearch) Tasks(ctx context.Context) ([]ImportedTask, error) {
+	if j.config.URL == "" {
+		return nil, errors.New("jira: the site URL is required (jira.url)")
+	}
+	if j.config.Token == "" {
+		return nil, errors.New("jira: an API token is required (jira.token or $JIRA_API_TOKEN)")
+	}
+	if strings.TrimSpace(j.jql) == "" {
+		return nil, errors.New("jira: a JQL query is required (--jql or jira.jql)")
+	}
+
+	var tasks []ImportedTask
+	token, startAt := "", 0
+	for {
+		page, err := j.search(ctx, token, startAt)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range page.Issues {
+			task, err := j.toImported(issue)
+			if err != nil {
+				return nil, err
+			}
+			tasks = append(tasks, task)
+		}
+
+		startAt += len(page.Issues)
+		token = page.NextPageToken
+		if j.cloud() && (token == "" || page.IsLast) ||
+			!j.cloud() && (len(page.Issues) == 0 || startAt >= page.Total) {
+			return tasks, nil
+		}
+	}
+}
+
+func (j *JiraSearch) cloud() bool {
+	return j.config.Email != ""
+}
+
+func (j *JiraSearch) search(ctx context.Context, token string, startAt int) (*jiraPage, error) {
+	fields := []string{"summary", "created", "priority", "status", j.config.Fields.Tags, j.config.Fields.Due}
+	query := url.Values{
+		"jql":        {j.jql},
+		"fields":     {strings.Join(fields, ",")},
+		"maxResults": {"100"},
+	}
+	path := "/rest/api/2/search"
+	if j.cloud() {
+		path = "/rest/api/3/search/jql"
+		if token != "" {
+			query.Set("nextPageToken", token)
+		}
+	} else {
+		query.Set("startAt", strconv.Itoa(startAt))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.config.URL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if j.cloud() {
+		req.SetBasicAuth(j.config.Email, j.config.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.config.Token)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jira: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		var failure struct {
+			ErrorMessages []string `json:"errorMessages"`
+		}
+		message := resp.Status
+		if json.Unmarshal(body, &failure) == nil && len(failure.ErrorMessages) > 0 {
+			message = strings.Join(failure.ErrorMessages, "; ")
+		}
+		return nil, fmt.Errorf("jira: %s (%d)", message, resp.StatusCode)
+	}
+
+	var page jiraPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("jira: invalid response: %w", err)
+	}
+	return &page, nil
+}
+
+func (j *JiraSearch) toImported(issue jiraIssue) (ImportedTask, error) {
+	var summary, created string
+	json.Unmarshal(issue.Fields["summary"], &summary)
+	json.Unmarshal(issue.Fields["created"], &created)
+
+	t := ImportedTask{
+		Description: strings.TrimSpace(summary),
+		Tags:        jiraValues(issue.Fields[j.config.Fields.Tags]),
+	}
+	if t.Description == "" {
+		t.Description = issue.Key
+	}
+	// Jira writes zones without a colon, such as +0000
+	if at, err := time.Parse("2006-01-02T15:04:05.000-0700", created); err == nil {
+		t.CreatedAt = at
+	}
+
+	if names := jiraValues(issue.Fields["priority"]); len(names) > 0 {
+		priority, err := j.priority(names[0])
+		if err != nil {
+			return ImportedTask{}, fmt.Errorf("jira: %s: %w", issue.Key, err)
+		}
+		t.Priority = priority
+	}
+
+	if dates := jiraValues(issue.Fields[j.config.Fields.Due]); len(dates) > 0 {
+		value := dates[0]
+		if at, err := time.Parse("2006-01-02T15:04:05.000-0700", value); err == nil {
+			t.DueAt = &at
+		} else if due, err := task.ParseDeadline(value, j.now); err == nil {
+			t.DueAt = &due
+		}
+	}
+
+	var status struct {
+		StatusCategory struct {
+			Key string `json:"key"`
+		} `json:"statusCategory"`
+	}
+	json.Unmarshal(issue.Fields["status"], &status)
+	switch status.StatusCategory.Key {
+	case "indeterminate":
+		t.Status = task.StatusInProgress
+	case "done":
+		t.Status = task.StatusDone
+	}
+
+	if j.keepKey {
+		t.ExternalRefs = map[string]string{JiraRefSystem: issue.Key}
+	}
+	return t, nil
+}
+
+// priority maps a Jira priority name through the configured mapping, then
+// the defaults. Names mapped to neither are left unset.
+func (j *JiraSearch) priority(name string) (task.Priority, error) {
+	for jiraName, value := range j.config.Fields.Priorities {
+		if strings.EqualFold(jiraName, name) {
+			priority := task.Priority(strings.ToLower(value))
+			if !priority.IsValid() {
+				return task.PriorityNone, fmt.Errorf("%w: %q mapped from %s", task.ErrInvalidPriority, value, name)
+			}
+			return priority, nil
+		}
+	}
+	return defaultJiraPriorities[strings.ToLower(name)], nil
+}
+
+// jiraValues reads a field as text values: a string, an object with a name
+// or value such as a priority or select option, or a list of either
+func jiraValues(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) != nil {
+		list = []json.RawMessage{raw}
+	}
+
+	var values []string
+	for _, item := range list {
+		var text string
+		if json.Unmarshal(item, &text) != nil {
+			var object struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			}
+			json.Unmarshal(item, &object)
+			text = object.Name
+			if text == "" {
+				text = object.Value
+			}
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			values = append(values, text)
+		}
+	}
+	return values
+}
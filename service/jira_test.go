@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+func TestJiraSearch(t *testing.T) {
+	server := tasktest.NewFakeJira(t)
+	config := repository.JiraConfig{URL: server.URL, Email: "me@example.com", Token: "secret"}
+	config.Fields.Priorities = map[string]string{"Blocker-ish": "high"}
+
+	tasks, err := NewJiraSearch(config, "assignee = currentUser()", true, tasktest.FixedTime()).Tasks(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("got %d tasks, want 3", len(tasks))
+	}
+
+	first := tasks[0]
+	if first.Description != "Rotate certificates" || first.Priority != task.PriorityUrgent ||
+		first.ExternalRefs[JiraRefSystem] != "OPS-1" || first.Status != "" {
+		t.Errorf("first = %+v", first)
+	}
+	if len(first.Tags) != 1 || first.Tags[0] != "security" {
+		t.Errorf("tags = %v, want [security]", first.Tags)
+	}
+	if first.DueAt == nil || first.DueAt.Format(time.DateOnly) != "2024-01-10" {
+		t.Errorf("due = %v, want 2024-01-10", first.DueAt)
+	}
+	if want := time.Date(2024, 1, 2, 8, 30, 0, 0, time.UTC); !first.CreatedAt.Equal(want) {
+		t.Errorf("created = %v, want %v", first.CreatedAt, want)
+	}
+	if tasks[1].Priority != task.PriorityHigh || tasks[1].Status != task.StatusInProgress {
+		t.Errorf("mapped priority and status = %s, %s", tasks[1].Priority, tasks[1].Status)
+	}
+	if tasks[2].Status != task.StatusDone || len(tasks[2].Tags) != 2 {
+		t.Errorf("third = %+v", tasks[2])
+	}
+
+	// Data Center pages by offset and uses a bearer token
+	config.Email = ""
+	config.Fields.Tags = "components"
+	tasks, err = NewJiraSearch(config, "assignee = currentUser()", false, tasktest.FixedTime()).Tasks(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 || tasks[0].ExternalRefs != nil || tasks[0].Tags[0] != "Backend" {
+		t.Errorf("data center tasks = %+v", tasks)
+	}
+
+	config.Fields.Priorities = map[string]string{"Highest": "critical"}
+	if _, err := NewJiraSearch(config, "x", false, tasktest.FixedTime()).Tasks(t.Context()); err == nil {
+		t.Error("invalid priority mapping was accepted")
+	}
+}
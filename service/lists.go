@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithDefaultList names the list holding the tasks without one
+func (s *TaskService) WithDefaultList(name string) *TaskService {
+	s.defaultList = name
+	return s
+}
+
+// DefaultList returns the name of the list holding the tasks without one
+func (s *TaskService) DefaultList() string {
+	if s.defaultList == "" {
+		return task.DefaultListName
+	}
+	return s.defaultList
+}
+
+// CurrentList returns the list the service works on, empty when it sees
+// every task
+func (s *TaskService) CurrentList() string {
+	if scoped, ok := s.store.(*listStore); ok {
+		return scoped.list
+	}
+	return ""
+}
+
+// InList returns a copy of the service that only sees the tasks of the
+// named list and adds new tasks to it
+func (s *TaskService) InList(name string) (*TaskService, error) {
+	name, err := task.ValidateListName(name)
+	if err != nil {
+		return nil, err
+	}
+	scoped := *s
+	scoped.store = &listStore{store: s.allTasks(), list: name, defaultList: s.DefaultList()}
+	return &scoped, nil
+}
+
+// allTasks returns the store without the list scope
+func (s *TaskService) allTasks() repository.TaskStore {
+	if scoped, ok := s.store.(*listStore); ok {
+		return scoped.store
+	}
+	return s.store
+}
+
+// MoveToList moves a task to another list, keeping its ID
+func (s *TaskService) MoveToList(ctx context.Context, id int, name string) error {
+	name, err := task.ValidateListName(name)
+	if err != nil {
+		return err
+	}
+	if name == s.DefaultList() {
+		name = ""
+	}
+	return s.updateTask(ctx, id, func(task *task.Task) error {
+		task.List = name
+		return nil
+	})
+}
+
+// Lists returns every list holding tasks, the default list first and
+// always, then the others by name
+func (s *TaskService) Lists(ctx context.Context) ([]task.ListSummary, error) {
+	tasks, err := s.allTasks().List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+
+	defaultList := s.DefaultList()
+	summaries := map[string]*task.ListSummary{defaultList: {Name: defaultList}}
+	for _, t := range tasks {
+		name := t.List
+		if name == "" {
+			name = defaultList
+		}
+		summary, ok := summaries[name]
+		if !ok {
+			summary = &task.ListSummary{Name: name}
+			summaries[name] = summary
+		}
+		summary.Total++
+		if t.IsOpen() {
+			summary.Open++
+		}
+	}
+
+	lists := []task.ListSummary{*summaries[defaultList]}
+	delete(summaries, defaultList)
+	names := make([]string, 0, len(summaries))
+	for name := range summaries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		lists = append(lists, *summaries[name])
+	}
+	return lists, nil
+}
+
+// List Store (Decorator)
+//
+// listStore narrows a store to the tasks of one list. Tasks it adds without
+// a list are filed in it; every other write goes through unchanged, so a
+// task can be moved out of the list.
+type listStore struct {
+	store       repository.TaskStore
+	list        string
+	defaultList string
+}
+
+func (s *listStore) Get(ctx context.Context, id int) (task.Task, error) {
+	t, err := s.store.Get(ctx, id)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if !t.InList(s.list, s.defaultList) {
+		return task.Task{}, task.ErrTaskNotFound.Withf("%d", id)
+	}
+	return t, nil
+}
+
+func (s *listStore) Put(ctx context.Context, t task.Task) error {
+	return s.Apply(ctx, []task.TaskChange{{Type: task.ChangeUpdated, Task: t}})
+}
+
+func (s *listStore) Delete(ctx context.Context, id int) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, id)
+}
+
+func (s *listStore) List(ctx context.Context, filter repository.TaskFilter) ([]task.Task, error) {
+	match := filter.Match
+	filter.Match = func(task task.Task) bool {
+		return task.InList(s.list, s.defaultList) && (match == nil || match(task))
+	}
+	return s.store.List(ctx, filter)
+}
+
+func (s *listStore) NextID(ctx context.Context) (int, error) {
+	return s.store.NextID(ctx)
+}
+
+// Apply files added tasks in the list and writes the changes through
+func (s *listStore) Apply(ctx context.Context, changes []task.TaskChange) error {
+	changes = slices.Clone(changes)
+	for i, change := range changes {
+		if change.Type == task.ChangeAdded && change.Task.List == "" && s.list != s.defaultList {
+			changes[i].Task.List = s.list
+		}
+	}
+	return repository.WriteChanges(ctx, s.store, changes)
+}
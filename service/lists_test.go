@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_InList tests that a scoped service only sees its list,
+// files new tasks in it and keeps IDs unique across lists
+func TestTaskService_InList(t *testing.T) {
+	ctx := context.Background()
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	service := NewTaskService(repo)
+
+	groceries, err := service.InList("groceries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	added, err := groceries.AddTask(ctx, "Milk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.ID != 4 {
+		t.Errorf("ID = %d, want 4", added.ID)
+	}
+	if stored, _ := repo.GetTask(4); stored.List != "groceries" {
+		t.Errorf("List = %q, want groceries", stored.List)
+	}
+
+	if tasks, _ := groceries.ListTasks(ctx, ""); len(tasks) != 1 || tasks[0].ID != 4 {
+		t.Errorf("groceries lists %v", tasks)
+	}
+	if _, err := groceries.GetTask(ctx, 1); !errors.Is(err, task.ErrTaskNotFound) {
+		t.Errorf("GetTask(1) err = %v, want %v", err, task.ErrTaskNotFound)
+	}
+	if err := groceries.DeleteTask(ctx, 1); !errors.Is(err, task.ErrTaskNotFound) {
+		t.Errorf("DeleteTask(1) err = %v, want %v", err, task.ErrTaskNotFound)
+	}
+
+	defaults, _ := service.InList(task.DefaultListName)
+	if tasks, _ := defaults.ListTasks(ctx, ""); len(tasks) != 3 {
+		t.Errorf("default list has %d tasks, want 3", len(tasks))
+	}
+	if err := groceries.MoveToList(ctx, 4, task.DefaultListName); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := repo.GetTask(4); stored.List != "" {
+		t.Errorf("List = %q after moving to the default list, want none", stored.List)
+	}
+
+	if _, err := service.InList("two words"); !errors.Is(err, task.ErrInvalidList) {
+		t.Errorf("InList() err = %v, want %v", err, task.ErrInvalidList)
+	}
+}
+
+func TestTaskService_Lists(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].List = "work"
+	service := NewTaskService(tasktest.NewMockRepository().WithTasks(tasks)).WithDefaultList("home")
+
+	lists, err := service.Lists(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []task.ListSummary{{Name: "home", Open: 1, Total: 2}, {Name: "work", Open: 1, Total: 1}}
+	if len(lists) != len(want) || lists[0] != want[0] || lists[1] != want[1] {
+		t.Errorf("Lists() = %v, want %v", lists, want)
+	}
+}
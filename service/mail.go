@@ -0,0 +1,116 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+)
+
+// ErrMailNotConfigured is returned when sending mail without an SMTP server
+var ErrMailNotConfigured = errors.New("no SMTP server configured, set smtp.host, smtp.from and smtp.to in the config file")
+
+// Mail is an email with plain text and HTML alternatives
+type Mail struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers email
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// SMTP Mailer (Adapter)
+//
+// SMTPMailer sends mail through the server in the config file, using
+// STARTTLS when the server offers it and PLAIN auth when a username is set.
+type SMTPMailer struct {
+	config repository.SMTPConfig
+	now    func() time.Time
+}
+
+func NewSMTPMailer(config repository.SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config, now: time.Now}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, mail Mail) error {
+	if m.config.Host == "" || m.config.From == "" || len(m.config.To) == 0 {
+		return ErrMailNotConfigured
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	message, err := m.message(mail)
+	if err != nil {
+		return err
+	}
+
+	port := m.config.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	if err := smtp.SendMail(addr, auth, m.config.From, m.config.To, message); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+// message builds a multipart/alternative MIME message
+func (m *SMTPMailer) message(mail Mail) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", mail.Text},
+		{"text/html; charset=utf-8", mail.HTML},
+	} {
+		if part.content == "" {
+			continue
+		}
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		qp.Write([]byte(part.content))
+		qp.Close()
+	}
+	parts.Close()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.config.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mimeHeader(mail.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", m.now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	b.Write(body.Bytes())
+	return b.Bytes(), nil
+}
+
+// mimeHeader encodes non-ASCII header values
+func mimeHeader(value string) string {
+	return mime.QEncoding.Encode("utf-8", value)
+}
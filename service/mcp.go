@@ -0,0 +1,421 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// MCP Server
+//
+// task-cli mcp speaks the Model Context Protocol on stdin and stdout, so
+// that AI assistants can list, search and change tasks. Messages are
+// JSON-RPC 2.0, one per line. Each tool maps to a service method, and its
+// input schema is built from the Go type its arguments decode into, the
+// same way the OpenAPI document is. Tools that delete tasks are only
+// offered when the config allows it.
+
+// mcpProtocolVersions are the protocol revisions the server speaks, newest
+// first
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// MCPServer answers MCP requests with the tools of the task service
+type MCPServer struct {
+	service          *TaskService
+	clock            task.Clock
+	user             string
+	allowDestructive bool
+	tools            []mcpTool
+}
+
+// mcpTool is one tool offered to the assistant. Args is the zero value of
+// the type its arguments decode into.
+type mcpTool struct {
+	Name        string
+	Description string
+	Args        any
+	ReadOnly    bool
+	Destructive bool
+	Call        func(ctx context.Context, args json.RawMessage) (any, error)
+}
+
+// NewMCPServer serves the tools of service, resolving "me" in filters to
+// user and relative dates against clock
+func NewMCPServer(service *TaskService, clock task.Clock, user string) *MCPServer {
+	s := &MCPServer{service: service, clock: clock, user: user}
+	s.tools = s.toolTable()
+	return s
+}
+
+// WithDestructive offers the tools that delete tasks
+func (s *MCPServer) WithDestructive(allow bool) *MCPServer {
+	s.allowDestructive = allow
+	return s
+}
+
+type mcpListArgs struct {
+	// Status keeps tasks with that status; cancelled tasks are left out
+	// unless asked for
+	Status task.TaskStatus `json:"status,omitempty"`
+	Where  string          `json:"where,omitempty"`
+}
+
+type mcpSearchArgs struct {
+	Text string `json:"text"`
+}
+
+type mcpIDArgs struct {
+	ID int `json:"id"`
+}
+
+type mcpAddArgs struct {
+	Description string        `json:"description"`
+	Due         string        `json:"due,omitempty"`
+	Priority    task.Priority `json:"priority,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+}
+
+type mcpUpdateArgs struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+}
+
+type mcpMoveArgs struct {
+	ID     int             `json:"id"`
+	Status task.TaskStatus `json:"status"`
+}
+
+func (s *MCPServer) toolTable() []mcpTool {
+	return []mcpTool{
+		{
+			Name: "list_tasks",
+			Description: "List tasks, optionally with one status or a filter expression such as " +
+				`status != done && (tag:work || priority >= high) && due < "next friday"`,
+			Args:     mcpListArgs{},
+			ReadOnly: true,
+			Call: mcpCall(func(ctx context.Context, args mcpListArgs) (any, error) {
+				match := func(t task.Task) bool {
+					if args.Status == "" {
+						return t.Status != task.StatusCancelled
+					}
+					return t.Status == args.Status
+				}
+				if args.Where != "" {
+					query, err := task.ParseQuery(args.Where, task.QueryEnv{Now: s.clock(), Workflow: s.service.Workflow(), User: s.user})
+					if err != nil {
+						return nil, err
+					}
+					byStatus := match
+					match = func(task task.Task) bool { return byStatus(task) && query.Match(task) }
+				}
+				return s.listTasks(ctx, match)
+			}),
+		},
+		{
+			Name:        "search_tasks",
+			Description: "Find tasks of any status whose description contains the text, ignoring case",
+			Args:        mcpSearchArgs{},
+			ReadOnly:    true,
+			Call: mcpCall(func(ctx context.Context, args mcpSearchArgs) (any, error) {
+				text := strings.ToLower(args.Text)
+				return s.listTasks(ctx, func(task task.Task) bool {
+					return strings.Contains(strings.ToLower(task.Description), text)
+				})
+			}),
+		},
+		{
+			Name:        "get_task",
+			Description: "Show one task with all its details",
+			Args:        mcpIDArgs{},
+			ReadOnly:    true,
+			Call: mcpCall(func(ctx context.Context, args mcpIDArgs) (any, error) {
+				return s.service.GetTask(ctx, args.ID)
+			}),
+		},
+		{
+			Name:        "add_task",
+			Description: `Add a task. due accepts dates such as "2024-06-01", "tomorrow 9am" or "friday".`,
+			Args:        mcpAddArgs{},
+			Call: mcpCall(func(ctx context.Context, args mcpAddArgs) (any, error) {
+				var opts []task.TaskOption
+				if args.Due != "" {
+					due, err := task.ParseDeadline(args.Due, s.clock())
+					if err != nil {
+						return nil, err
+					}
+					opts = append(opts, task.WithDueDate(due))
+				}
+				if args.Priority != "" {
+					opts = append(opts, task.WithPriority(args.Priority))
+				}
+				if len(args.Tags) > 0 {
+					opts = append(opts, task.WithTags(args.Tags...))
+				}
+				return s.service.AddTask(ctx, args.Description, opts...)
+			}),
+		},
+		{
+			Name:        "update_task",
+			Description: "Change the description of a task",
+			Args:        mcpUpdateArgs{},
+			Call: mcpCall(func(ctx context.Context, args mcpUpdateArgs) (any, error) {
+				if err := s.service.UpdateTask(ctx, args.ID, args.Description); err != nil {
+					return nil, err
+				}
+				return s.service.GetTask(ctx, args.ID)
+			}),
+		},
+		{
+			Name:        "move_task",
+			Description: "Move a task to another status, such as in-progress or done",
+			Args:        mcpMoveArgs{},
+			Call: mcpCall(func(ctx context.Context, args mcpMoveArgs) (any, error) {
+				if err := s.service.MoveTask(ctx, args.ID, args.Status); err != nil {
+					return nil, err
+				}
+				return s.service.GetTask(ctx, args.ID)
+			}),
+		},
+		{
+			Name:        "delete_task",
+			Description: "Delete a task for good",
+			Args:        mcpIDArgs{},
+			Destructive: true,
+			Call: mcpCall(func(ctx context.Context, args mcpIDArgs) (any, error) {
+				if err := s.service.DeleteTask(ctx, args.ID); err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("Task %d deleted", args.ID), nil
+			}),
+		},
+	}
+}
+
+// listTasks returns the tasks selected by match, as an empty list rather
+// than null when there are none
+func (s *MCPServer) listTasks(ctx context.Context, match func(task.Task) bool) ([]task.Task, error) {
+	tasks, err := s.service.ListPage(ctx, repository.Page{}, match)
+	if tasks == nil && err == nil {
+		tasks = []task.Task{}
+	}
+	return tasks, err
+}
+
+// mcpCall decodes the arguments of a tool into A before calling fn
+func mcpCall[A any](fn func(context.Context, A) (any, error)) func(context.Context, json.RawMessage) (any, error) {
+	return func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var args A
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, task.TaskError{Code: task.ErrInvalidBody.Code, Message: "Invalid arguments: " + err.Error()}
+		}
+		return fn(ctx, args)
+	}
+}
+
+// Serve answers the messages read from r on w until r ends or ctx is done
+func (s *MCPServer) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return nil
+		}
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		if reply := s.handle(ctx, line); reply != nil {
+			if err := encoder.Encode(reply); err != nil {
+				return fmt.Errorf("failed to write MCP response: %w", err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read MCP request: %w", err)
+	}
+	return nil
+}
+
+// handle answers one message, or returns nil for notifications and
+// responses, which need no answer
+func (s *MCPServer) handle(ctx context.Context, line []byte) *rpcMessage {
+	var msg rpcMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return rpcFailure(json.RawMessage("null"), rpcParseError, "invalid JSON: "+err.Error())
+	}
+	if msg.ID == nil {
+		return nil
+	}
+	if msg.JSONRPC != "2.0" || msg.Method == "" {
+		return rpcFailure(msg.ID, rpcInvalidRequest, "not a JSON-RPC 2.0 request")
+	}
+
+	var result any
+	var err *rpcError
+	switch msg.Method {
+	case "initialize":
+		result, err = s.initialize(msg.Params)
+	case "ping":
+		result = struct{}{}
+	case "tools/list":
+		result = map[string]any{"tools": s.listTools()}
+	case "tools/call":
+		result, err = s.callTool(ctx, msg.Params)
+	default:
+		err = &rpcError{Code: rpcMethodNotFound, Message: "unknown method " + msg.Method}
+	}
+	if err != nil {
+		return rpcFailure(msg.ID, err.Code, err.Message)
+	}
+	return &rpcMessage{JSONRPC: "2.0", ID: msg.ID, Result: result}
+}
+
+func rpcFailure(id json.RawMessage, code int, message string) *rpcMessage {
+	return &rpcMessage{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
+
+func (s *MCPServer) initialize(params json.RawMessage) (any, *rpcError) {
+	var req struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	// Answer with the client's revision when we speak it, else our newest
+	version := mcpProtocolVersions[0]
+	if slices.Contains(mcpProtocolVersions, req.ProtocolVersion) {
+		version = req.ProtocolVersion
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{}},
+		"serverInfo":      map[string]any{"name": "task-cli", "version": Version},
+	}, nil
+}
+
+// offered returns the tools the assistant may call
+func (s *MCPServer) offered() []mcpTool {
+	var tools []mcpTool
+	for _, tool := range s.tools {
+		if !tool.Destructive || s.allowDestructive {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+func (s *MCPServer) inputSchema(tool mcpTool) map[string]any {
+	g := &schemaGenerator{workflow: s.service.Workflow(), schemas: make(map[string]any)}
+	return g.object(reflect.TypeOf(tool.Args))
+}
+
+func (s *MCPServer) listTools() []any {
+	var tools []any
+	for _, tool := range s.offered() {
+		tools = append(tools, map[string]any{
+			"name":        tool.Name,
+			"description": tool.Description,
+			"inputSchema": s.inputSchema(tool),
+			"annotations": map[string]any{
+				"readOnlyHint":    tool.ReadOnly,
+				"destructiveHint": tool.Destructive,
+			},
+		})
+	}
+	return tools
+}
+
+// callTool runs a tool. Failures of the tool itself, such as a missing
+// task, are results flagged isError, so the assistant can read them and
+// try again.
+func (s *MCPServer) callTool(ctx context.Context, params json.RawMessage) (any, *rpcError) {
+	var req struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	if len(req.Arguments) == 0 || string(req.Arguments) == "null" {
+		req.Arguments = json.RawMessage("{}")
+	}
+
+	index := slices.IndexFunc(s.tools, func(tool mcpTool) bool { return tool.Name == req.Name })
+	if index < 0 {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "unknown tool " + req.Name}
+	}
+	tool := s.tools[index]
+	if tool.Destructive && !s.allowDestructive {
+		return mcpResult(nil, errors.New(tool.Name+" is disabled; set mcp.allowDestructive in the config to enable it")), nil
+	}
+
+	var args any
+	if err := json.Unmarshal(req.Arguments, &args); err != nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	if field, reason, ok := validateJSON(args, s.inputSchema(tool), nil, ""); !ok {
+		if field == "" {
+			field = "arguments"
+		}
+		return mcpResult(nil, fmt.Errorf("invalid arguments: %s %s", field, reason)), nil
+	}
+	return mcpResult(tool.Call(ctx, req.Arguments)), nil
+}
+
+// mcpResult wraps the outcome of a tool as text content, JSON for data
+func mcpResult(value any, err error) map[string]any {
+	var text string
+	if err != nil {
+		var taskErr task.TaskError
+		if errors.As(err, &taskErr) {
+			text = taskErr.Code + ": " + taskErr.Error()
+		} else {
+			text = err.Error()
+		}
+	} else if message, ok := value.(string); ok {
+		text = message
+	} else {
+		data, marshalErr := json.MarshalIndent(value, "", "  ")
+		if marshalErr != nil {
+			return mcpResult(nil, marshalErr)
+		}
+		text = string(data)
+	}
+	return map[string]any{
+		"content": []any{map[string]any{"type": "text", "text": text}},
+		"isError": err != nil,
+	}
+}
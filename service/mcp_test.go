@@ -0,0 +1,136 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// mcpSession sends the lines to the server and returns the
+// responses by request ID
+func mcpSession(t *testing.T, server *MCPServer, lines ...string) map[string]map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := server.Serve(t.Context(), strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	responses := make(map[string]map[string]any)
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var response map[string]any
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		id, _ := json.Marshal(response["id"])
+		responses[string(id)] = response
+	}
+	return responses
+}
+
+// toolText returns the text of a tool result and whether it is an error
+func toolText(t *testing.T, response map[string]any) (string, bool) {
+	t.Helper()
+	result, ok := response["result"].(map[string]any)
+	if !ok {
+		t.Fatalf("no result in %v", response)
+	}
+	content := result["content"].([]any)[0].(map[string]any)
+	return content["text"].(string), result["isError"].(bool)
+}
+
+func TestMCPServer_Protocol(t *testing.T) {
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	server := NewMCPServer(NewTaskService(repo), tasktest.FixedTime, "alice")
+
+	responses := mcpSession(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"nope"}}`,
+		`not json`,
+	)
+	if len(responses) != 5 {
+		t.Fatalf("got %d responses, want 5 (none for the notification)", len(responses))
+	}
+
+	initialized := responses["1"]["result"].(map[string]any)
+	if initialized["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v, want the client's", initialized["protocolVersion"])
+	}
+
+	var names []string
+	for _, tool := range responses["2"]["result"].(map[string]any)["tools"].([]any) {
+		names = append(names, tool.(map[string]any)["name"].(string))
+	}
+	if strings.Contains(strings.Join(names, " "), "delete_task") {
+		t.Errorf("tools = %v, delete_task offered without allowDestructive", names)
+	}
+
+	for id, want := range map[string]float64{"3": rpcMethodNotFound, "4": rpcInvalidParams, "null": rpcParseError} {
+		rpcErr, _ := responses[id]["error"].(map[string]any)
+		if rpcErr["code"] != want {
+			t.Errorf("response %s error = %v, want code %v", id, rpcErr, want)
+		}
+	}
+}
+
+func TestMCPServer_Tools(t *testing.T) {
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	server := NewMCPServer(NewTaskService(repo), tasktest.FixedTime, "alice")
+	call := func(id int, name, arguments string) string {
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":%q,"arguments":%s}}`,
+			id, name, arguments)
+	}
+
+	responses := mcpSession(t, server,
+		call(1, "add_task", `{"description": "Pay rent", "due": "tomorrow", "priority": "high", "tags": ["home"]}`),
+		call(2, "list_tasks", `{"where": "tag:home"}`),
+		call(3, "search_tasks", `{"text": "REPORT"}`),
+		call(4, "move_task", `{"id": 1, "status": "done"}`),
+		call(5, "get_task", `{"id": 99}`),
+		call(6, "add_task", `{"due": "tomorrow"}`),
+		call(7, "delete_task", `{"id": 1}`),
+	)
+
+	item, ok := repo.GetTask(4)
+	if !ok || item.Description != "Pay rent" || item.Priority != task.PriorityHigh || item.DueAt == nil {
+		t.Errorf("added task = %+v, %v", item, ok)
+	}
+	if text, _ := toolText(t, responses["2"]); !strings.Contains(text, "Pay rent") || strings.Contains(text, "Buy groceries") {
+		t.Errorf("list_tasks = %s", text)
+	}
+	if text, _ := toolText(t, responses["3"]); !strings.Contains(text, "Write report") {
+		t.Errorf("search_tasks = %s", text)
+	}
+	if item, _ := repo.GetTask(1); item.Status != task.StatusDone {
+		t.Errorf("task 1 status = %s, want done", item.Status)
+	}
+	if text, isError := toolText(t, responses["5"]); !isError || !strings.HasPrefix(text, "NOT_FOUND") {
+		t.Errorf("get_task 99 = %s, %v", text, isError)
+	}
+	if text, isError := toolText(t, responses["6"]); !isError || !strings.Contains(text, "description is required") {
+		t.Errorf("add_task without description = %s, %v", text, isError)
+	}
+	if text, isError := toolText(t, responses["7"]); !isError || !strings.Contains(text, "allowDestructive") {
+		t.Errorf("delete_task = %s, %v", text, isError)
+	}
+	if _, ok := repo.GetTask(1); !ok {
+		t.Error("task 1 was deleted without allowDestructive")
+	}
+
+	server.WithDestructive(true)
+	responses = mcpSession(t, server, call(1, "delete_task", `{"id": 1}`))
+	if text, isError := toolText(t, responses["1"]); isError {
+		t.Errorf("delete_task = %s", text)
+	}
+	if _, ok := repo.GetTask(1); ok {
+		t.Error("task 1 was not deleted")
+	}
+}
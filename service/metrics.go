@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Prometheus Metrics (Presentation Layer)
+//
+// serve --http exposes /metrics in the Prometheus text format: counters of
+// the tasks created, completed and deleted since the server started,
+// gauges of the open tasks per status and per tag read from the store at
+// each scrape, and a latency histogram per HTTP operation.
+
+// latencyBuckets are the upper bounds, in seconds, of the latency
+// histogram buckets, the Prometheus client defaults
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics counts what the server does, for Prometheus to scrape
+type Metrics struct {
+	service *TaskService
+
+	mu        sync.Mutex
+	created   int
+	completed int
+	deleted   int
+	latencies map[string]*histogram
+}
+
+// histogram counts observations per bucket of latencyBuckets, the last
+// count being those above every bound
+type histogram struct {
+	counts []int
+	sum    float64
+	count  int
+}
+
+func NewMetrics(service *TaskService) *Metrics {
+	return &Metrics{service: service, latencies: make(map[string]*histogram)}
+}
+
+// WithMetrics counts the changes the service saves in metrics
+func (s *TaskService) WithMetrics(metrics *Metrics) *TaskService {
+	s.metrics = metrics
+	return s
+}
+
+// Observe counts the tasks created, completed and deleted by saved changes
+func (m *Metrics) Observe(changes []task.TaskChange) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, change := range changes {
+		switch {
+		case change.Type == task.ChangeDeleted:
+			m.deleted++
+			continue
+		case change.Type == task.ChangeAdded:
+			m.created++
+		}
+		if change.Task.Status == task.StatusDone && (change.Previous == nil || change.Previous.Status != task.StatusDone) {
+			m.completed++
+		}
+	}
+}
+
+// Instrument times every request next serves, by operation
+func (m *Metrics) Instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		m.observeLatency(r.Method+" "+routeLabel(r.URL.Path), time.Since(start))
+	})
+}
+
+func (m *Metrics) observeLatency(operation string, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.latencies[operation]
+	if !ok {
+		h = &histogram{counts: make([]int, len(latencyBuckets)+1)}
+		m.latencies[operation] = h
+	}
+	seconds := elapsed.Seconds()
+	i, _ := slices.BinarySearch(latencyBuckets, seconds)
+	h.counts[i]++
+	h.sum += seconds
+	h.count++
+}
+
+// routeLabel replaces task IDs in a path so that every task shares one
+// label, such as /api/v1/tasks/{id}
+func routeLabel(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tasks, err := m.service.ListTasks(r.Context(), "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(w, tasks)
+}
+
+func (m *Metrics) write(w io.Writer, tasks []task.Task) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeCounter(w, "task_tracker_tasks_created_total", "Tasks created since the server started.", m.created)
+	writeCounter(w, "task_tracker_tasks_completed_total", "Tasks marked done since the server started.", m.completed)
+	writeCounter(w, "task_tracker_tasks_deleted_total", "Tasks deleted since the server started.", m.deleted)
+
+	byStatus := make(map[task.TaskStatus]int)
+	byTag := make(map[string]int)
+	for _, task := range tasks {
+		if !task.IsOpen() {
+			continue
+		}
+		byStatus[task.Status]++
+		for _, tag := range task.Tags {
+			byTag[tag]++
+		}
+	}
+	fmt.Fprintln(w, "# HELP task_tracker_open_tasks Open tasks per status.")
+	fmt.Fprintln(w, "# TYPE task_tracker_open_tasks gauge")
+	for _, status := range m.service.Workflow().Statuses {
+		if status == task.StatusDone || status == task.StatusCancelled {
+			continue
+		}
+		fmt.Fprintf(w, "task_tracker_open_tasks{status=%s} %d\n", labelValue(string(status)), byStatus[status])
+	}
+	fmt.Fprintln(w, "# HELP task_tracker_open_tasks_by_tag Open tasks per tag.")
+	fmt.Fprintln(w, "# TYPE task_tracker_open_tasks_by_tag gauge")
+	for _, tag := range slices.Sorted(maps.Keys(byTag)) {
+		fmt.Fprintf(w, "task_tracker_open_tasks_by_tag{tag=%s} %d\n", labelValue(tag), byTag[tag])
+	}
+
+	fmt.Fprintln(w, "# HELP task_tracker_http_request_duration_seconds Time taken to answer HTTP requests.")
+	fmt.Fprintln(w, "# TYPE task_tracker_http_request_duration_seconds histogram")
+	for _, operation := range slices.Sorted(maps.Keys(m.latencies)) {
+		h := m.latencies[operation]
+		label := "operation=" + labelValue(operation)
+		cumulative := 0
+		for i, bound := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "task_tracker_http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				label, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(w, "task_tracker_http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(w, "task_tracker_http_request_duration_seconds_sum{%s} %s\n", label, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "task_tracker_http_request_duration_seconds_count{%s} %d\n", label, h.count)
+	}
+}
+
+func writeCounter(w io.Writer, name, help string, value int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, value)
+}
+
+// labelValue quotes a label value, escaping as the text format requires
+func labelValue(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return `"` + value + `"`
+}
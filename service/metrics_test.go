@@ -0,0 +1,76 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+)
+
+// TestMetrics tests the counters, gauges and histograms served at /metrics
+func TestMetrics(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].Tags = []string{"home"}
+	tasks[1].Tags = []string{"work", "home"}
+	service := NewTaskService(tasktest.NewMockRepository().WithTasks(tasks))
+	metrics := NewMetrics(service)
+	service.WithMetrics(metrics)
+
+	mux := http.NewServeMux()
+	auth, err := NewAuthenticator(repository.ServerConfig{Tokens: []repository.ServerCredential{{Token: "secret", Scope: "write"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Handle("GET /metrics", auth.HTTP(metrics))
+	mux.Handle("/", metrics.Instrument(NewHTTPHandler(service, auth)))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	token := http.Header{"Authorization": {"Bearer secret"}}
+	doHTTP(t, http.MethodPost, server.URL+"/api/v1/tasks", `{"description": "Plan trip"}`, token)
+	doHTTP(t, http.MethodPatch, server.URL+"/api/v1/tasks/1", `{"status": "done"}`, token)
+	doHTTP(t, http.MethodDelete, server.URL+"/api/v1/tasks/3", "", token)
+	doHTTP(t, http.MethodGet, server.URL+"/api/v1/tasks/2", "", token)
+
+	if resp := doHTTP(t, http.MethodGet, server.URL+"/metrics", "", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("without token status = %d, want 401", resp.StatusCode)
+	}
+	resp := doHTTP(t, http.MethodGet, server.URL+"/metrics", "", token)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	for _, want := range []string{
+		"task_tracker_tasks_created_total 1\n",
+		"task_tracker_tasks_completed_total 1\n",
+		"task_tracker_tasks_deleted_total 1\n",
+		`task_tracker_open_tasks{status="todo"} 1` + "\n",
+		`task_tracker_open_tasks{status="in-progress"} 1` + "\n",
+		`task_tracker_open_tasks_by_tag{tag="home"} 1` + "\n",
+		`task_tracker_open_tasks_by_tag{tag="work"} 1` + "\n",
+		"# TYPE task_tracker_http_request_duration_seconds histogram\n",
+		`task_tracker_http_request_duration_seconds_bucket{operation="GET /api/v1/tasks/{id}",le="+Inf"} 1` + "\n",
+		`task_tracker_http_request_duration_seconds_count{operation="PATCH /api/v1/tasks/{id}"} 1` + "\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics lack %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(string(body), "/metrics") {
+		t.Errorf("scrapes are timed:\n%s", body)
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	if got, want := labelValue("a \"b\"\\\n"), `"a \"b\"\\\n"`; got != want {
+		t.Errorf("labelValue = %s, want %s", got, want)
+	}
+}
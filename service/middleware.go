@@ -0,0 +1,271 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Serve Middleware
+//
+// Before a request reaches a handler, serve limits how many requests each
+// client address may send and how large a body may be, and the REST API
+// checks JSON bodies against the schema the OpenAPI document publishes, so
+// that a LAN client cannot exhaust the server or get a malformed task
+// stored.
+
+// LimitBody refuses request bodies larger than maxBytes with 413
+func LimitBody(maxBytes int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > maxBytes {
+			writeServiceError(w, task.ErrBodyTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validateBody checks the JSON body of a route against the schema of its
+// request type before calling its handler. Errors name the offending field.
+func (s *httpTaskServer) validateBody(route httpRoute) http.HandlerFunc {
+	if route.Request == nil {
+		return route.Handler
+	}
+	g := &schemaGenerator{workflow: s.service.Workflow(), schemas: make(map[string]any)}
+	schema := g.content(route.Request)["application/json"].(map[string]any)["schema"].(map[string]any)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeServiceError(w, task.ErrBodyTooLarge)
+			return
+		}
+		if err != nil {
+			writeServiceError(w, task.ErrInvalidBody)
+			return
+		}
+
+		var body any
+		if err := json.Unmarshal(data, &body); err != nil {
+			writeFieldError(w, "", "is not valid JSON")
+			return
+		}
+		if field, reason, ok := validateJSON(body, schema, g.schemas, ""); !ok {
+			writeFieldError(w, field, reason)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		route.Handler(w, r)
+	}
+}
+
+// writeFieldError answers 400 INVALID_BODY naming the invalid field, empty
+// for the body itself
+func writeFieldError(w http.ResponseWriter, field, reason string) {
+	name := field
+	if name == "" {
+		name = "body"
+	}
+	writeJSON(w, http.StatusBadRequest, repository.HTTPErrorBody{Error: repository.HTTPError{
+		Code:    task.ErrInvalidBody.Code,
+		Message: fmt.Sprintf("%s: %s %s", task.ErrInvalidBody.Message, name, reason),
+		Field:   field,
+	}})
+}
+
+// validateJSON checks a decoded JSON value against a schema built by
+// schemaGenerator, returning the path of the first invalid field and why.
+// Null stands for a field left out, which only required fields refuse.
+func validateJSON(value any, schema map[string]any, schemas map[string]any, path string) (string, string, bool) {
+	if ref, ok := schema["$ref"].(string); ok {
+		schema, _ = schemas[ref[len("#/components/schemas/"):]].(map[string]any)
+	}
+	if value == nil {
+		if path == "" {
+			return path, "is required", false
+		}
+		return "", "", true
+	}
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return path, "must be an object", false
+		}
+		required, _ := schema["required"].([]string)
+		for _, name := range required {
+			if v, ok := object[name]; !ok || v == nil {
+				return joinPath(path, name), "is required", false
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		extra, _ := schema["additionalProperties"].(map[string]any)
+		for name, v := range object {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				// Fields unknown to this version are ignored, as when decoding
+				if property = extra; property == nil {
+					continue
+				}
+			}
+			if field, reason, ok := validateJSON(v, property, schemas, joinPath(path, name)); !ok {
+				return field, reason, false
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return path, "must be an array", false
+		}
+		items, _ := schema["items"].(map[string]any)
+		for i, v := range array {
+			if field, reason, ok := validateJSON(v, items, schemas, path+"["+strconv.Itoa(i)+"]"); !ok {
+				return field, reason, false
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return path, "must be a string", false
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, text); err != nil {
+				return path, "must be a date-time such as 2024-06-01T17:00:00Z", false
+			}
+		}
+		if enum, ok := schema["enum"]; ok && !enumContains(enum, text) {
+			return path, fmt.Sprintf("must be one of %v", enum), false
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return path, "must be an integer", false
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return path, "must be a number", false
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return path, "must be true or false", false
+		}
+	}
+	return "", "", true
+}
+
+// enumContains reports whether an enum of strings or string types holds
+// text
+func enumContains(enum any, text string) bool {
+	switch values := enum.(type) {
+	case []task.TaskStatus:
+		return slices.Contains(values, task.TaskStatus(text))
+	case []task.Priority:
+		return slices.Contains(values, task.Priority(text))
+	}
+	return true
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// RateLimiter lets each client address send rate requests per second on
+// average, in bursts of up to burst requests
+type RateLimiter struct {
+	rate  float64
+	burst float64
+	clock task.Clock
+
+	mu      sync.Mutex
+	clients map[string]*rateBucket
+	pruned  time.Time
+}
+
+// rateBucket holds the requests a client may still send, refilled over
+// time
+type rateBucket struct {
+	tokens float64
+	seen   time.Time
+}
+
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(max(burst, 1)),
+		clock:   time.Now,
+		clients: make(map[string]*rateBucket),
+	}
+}
+
+// Allow takes one request from the client's bucket, or returns how long
+// the client must wait for the next one
+func (l *RateLimiter) Allow(client string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock()
+	l.prune(now)
+
+	bucket, ok := l.clients[client]
+	if !ok {
+		bucket = &rateBucket{tokens: l.burst, seen: now}
+		l.clients[client] = bucket
+	}
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.seen).Seconds()*l.rate)
+	bucket.seen = now
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0, true
+	}
+	wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	return wait, false
+}
+
+// prune forgets clients whose bucket has refilled, once a minute; callers
+// hold l.mu
+func (l *RateLimiter) prune(now time.Time) {
+	if now.Sub(l.pruned) < time.Minute {
+		return
+	}
+	l.pruned = now
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for client, bucket := range l.clients {
+		if now.Sub(bucket.seen) > full {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// HTTP answers 429 to clients sending requests faster than the limit
+func (l *RateLimiter) HTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		if wait, ok := l.Allow(client); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeServiceError(w, task.ErrRateLimited)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
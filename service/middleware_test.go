@@ -0,0 +1,120 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestHTTPServer_Validation tests that invalid bodies are refused with the
+// field at fault before reaching the service
+func TestHTTPServer_Validation(t *testing.T) {
+	server := newHTTPTestServer(t, "", tasktest.FixedTasks(t))
+	base := server.URL + "/api/v1/tasks"
+
+	tests := []struct {
+		name      string
+		method    string
+		url       string
+		body      string
+		wantField string
+	}{
+		{"not JSON", http.MethodPost, base, `{"description":`, ""},
+		{"missing body", http.MethodPost, base, `null`, ""},
+		{"missing description", http.MethodPost, base, `{"dueAt": "2024-06-01T17:00:00Z"}`, "description"},
+		{"wrong type", http.MethodPost, base, `{"description": 42}`, "description"},
+		{"bad date", http.MethodPost, base, `{"description": "Pay rent", "dueAt": "tomorrow"}`, "dueAt"},
+		{"unknown status", http.MethodPatch, base + "/1", `{"status": "blocked-ish"}`, "status"},
+		{"bad task in list", http.MethodPut, base, `["Buy milk"]`, "[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := doHTTP(t, tt.method, tt.url, tt.body, nil)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", resp.StatusCode)
+			}
+			var body repository.HTTPErrorBody
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Error.Code != task.ErrInvalidBody.Code || body.Error.Field != tt.wantField {
+				t.Errorf("error = %+v, want %s on %q", body.Error, task.ErrInvalidBody.Code, tt.wantField)
+			}
+		})
+	}
+
+	// Valid bodies still reach the handlers, with unknown fields ignored
+	resp := doHTTP(t, http.MethodPost, base, `{"description": "Pay rent", "dueAt": null, "extra": true}`, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("valid add status = %d, want 201", resp.StatusCode)
+	}
+}
+
+func TestLimitBody(t *testing.T) {
+	server := newHTTPTestServer(t, "", tasktest.FixedTasks(t))
+	limited := httptest.NewServer(LimitBody(64, server.Config.Handler))
+	t.Cleanup(limited.Close)
+
+	body := `{"description": "` + strings.Repeat("x", 100) + `"}`
+	if resp := doHTTP(t, http.MethodPost, limited.URL+"/api/v1/tasks", body, nil); resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", resp.StatusCode)
+	}
+	if resp := doHTTP(t, http.MethodPost, limited.URL+"/api/v1/tasks", `{"description": "Short"}`, nil); resp.StatusCode != http.StatusCreated {
+		t.Errorf("small body status = %d, want 201", resp.StatusCode)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := tasktest.FixedTime()
+	limiter := NewRateLimiter(1, 2)
+	limiter.clock = func() time.Time { return now }
+
+	for i := range 2 {
+		if _, ok := limiter.Allow("10.0.0.1"); !ok {
+			t.Fatalf("request %d refused within the burst", i+1)
+		}
+	}
+	wait, ok := limiter.Allow("10.0.0.1")
+	if ok || wait != time.Second {
+		t.Errorf("third request = %v, %v, want refused for 1s", wait, ok)
+	}
+	if _, ok := limiter.Allow("10.0.0.2"); !ok {
+		t.Error("another client is refused")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := limiter.Allow("10.0.0.1"); !ok {
+		t.Error("request refused after the bucket refilled")
+	}
+
+	// Idle clients are forgotten
+	now = now.Add(time.Hour)
+	limiter.Allow("10.0.0.3")
+	if len(limiter.clients) != 1 {
+		t.Errorf("clients = %d, want 1", len(limiter.clients))
+	}
+}
+
+func TestRateLimiter_HTTP(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	server := httptest.NewServer(NewRateLimiter(0.01, 1).HTTP(ok))
+	t.Cleanup(server.Close)
+
+	if resp := doHTTP(t, http.MethodGet, server.URL, "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("first status = %d, want 200", resp.StatusCode)
+	}
+	resp := doHTTP(t, http.MethodGet, server.URL, "", nil)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("second status = %d, want 429", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "100" {
+		t.Errorf("Retry-After = %q, want 100", got)
+	}
+}
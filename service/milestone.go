@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithMilestones stores milestones in repo; without it milestone commands fail
+func (s *TaskService) WithMilestones(repo repository.MilestoneRepository) *TaskService {
+	s.milestones = repo
+	return s
+}
+
+func (s *TaskService) loadMilestones(ctx context.Context) ([]task.Milestone, error) {
+	if s.milestones == nil {
+		return nil, task.ErrNoMilestones
+	}
+	milestones, err := s.milestones.LoadMilestones(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to load milestones", err)
+	}
+	return milestones, nil
+}
+
+func (s *TaskService) CreateMilestone(ctx context.Context, name string, due time.Time) (*task.Milestone, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, task.ErrInvalidMilestone
+	}
+
+	milestones, err := s.loadMilestones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(milestones, func(m task.Milestone) bool { return m.Name == name }) {
+		return nil, task.ErrMilestoneExists
+	}
+
+	milestone := task.Milestone{Name: name, DueAt: due, CreatedAt: time.Now()}
+	if err := s.milestones.SaveMilestones(ctx, append(milestones, milestone)); err != nil {
+		return nil, repository.StorageError("failed to save milestones", err)
+	}
+	return &milestone, nil
+}
+
+// MilestoneStatus reports the progress of every milestone at now
+func (s *TaskService) MilestoneStatus(ctx context.Context, now time.Time) ([]task.MilestoneStatus, error) {
+	milestones, err := s.loadMilestones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	return task.BuildMilestoneStatus(milestones, tasks, now), nil
+}
+
+// SetTaskMilestone plans a task for a milestone, or unplans it with ""
+func (s *TaskService) SetTaskMilestone(ctx context.Context, id int, name string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if name != "" {
+		if err := s.checkMilestones(ctx, []string{name}); err != nil {
+			return err
+		}
+	}
+	return s.updateTask(ctx, id, func(task *task.Task) error {
+		task.SetMilestone(name)
+		return nil
+	})
+}
+
+// checkMilestones verifies that every named milestone exists
+func (s *TaskService) checkMilestones(ctx context.Context, names []string) error {
+	milestones, err := s.loadMilestones(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(milestones, func(m task.Milestone) bool { return m.Name == name }) {
+			return fmt.Errorf("%w: %s", task.ErrMilestoneNotFound, name)
+		}
+	}
+	return nil
+}
+
+// checkPlanning verifies the projects and milestones new tasks refer to
+func (s *TaskService) checkPlanning(ctx context.Context, tasks []task.Task) error {
+	var projects, milestones []string
+	for _, task := range tasks {
+		if task.Project != "" && !slices.Contains(projects, task.Project) {
+			projects = append(projects, task.Project)
+		}
+		if task.Milestone != "" && !slices.Contains(milestones, task.Milestone) {
+			milestones = append(milestones, task.Milestone)
+		}
+	}
+
+	if len(projects) > 0 {
+		if err := s.checkProjects(ctx, projects); err != nil {
+			return err
+		}
+	}
+	if len(milestones) > 0 {
+		return s.checkMilestones(ctx, milestones)
+	}
+	return nil
+}
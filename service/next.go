@@ -0,0 +1,34 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Suggest scores the open tasks match selects, best first. Ties go to the
+// lower ID.
+func (s *TaskService) Suggest(ctx context.Context, weights map[string]float64, match func(task.Task) bool, now time.Time) ([]task.Suggestion, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+
+	var suggestions []task.Suggestion
+	for _, t := range tasks {
+		if t.IsOpen() && (match == nil || match(t)) {
+			suggestions = append(suggestions, task.ScoreTask(t, weights, now))
+		}
+	}
+	slices.SortStableFunc(suggestions, func(a, b task.Suggestion) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return a.Task.ID - b.Task.ID
+	})
+	return suggestions, nil
+}
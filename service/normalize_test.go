@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_DescriptionRules tests that added and renamed tasks
+// follow the workflow's description rules
+func TestTaskService_DescriptionRules(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(tasktest.NewMockRepository()).WithWorkflow(task.Workflow{
+		Statuses:     task.DefaultWorkflow().Statuses,
+		Descriptions: task.DescriptionRules{StripTrailingPunctuation: true, Capitalize: true},
+	})
+
+	item, err := service.AddTask(ctx, "buy groceries.")
+	if err != nil || item.Description != "Buy groceries" {
+		t.Fatalf("AddTask() = %+v, %v; want description %q", item, err, "Buy groceries")
+	}
+	if err := service.UpdateTask(ctx, item.ID, "buy milk!"); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if got, _ := service.GetTask(ctx, item.ID); got.Description != "Buy milk" {
+		t.Errorf("updated description = %q, want %q", got.Description, "Buy milk")
+	}
+	if _, err := service.AddTask(ctx, "..."); !errors.Is(err, task.ErrEmptyDescription) {
+		t.Errorf("AddTask(...) error = %v, want %v", err, task.ErrEmptyDescription)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Notifier delivers a message to the user outside the terminal
+type Notifier interface {
+	Send(ctx context.Context, title, body string) error
+}
+
+// ErrNotifierUnavailable is returned when no desktop notification tool exists
+var ErrNotifierUnavailable = errors.New("desktop notifications are not supported on this system")
+
+// DesktopNotifier sends OS notifications through the platform's native tool:
+// notify-send on Linux and BSD, osascript on macOS and a PowerShell toast on Windows
+type DesktopNotifier struct {
+	goos string
+}
+
+func NewDesktopNotifier() *DesktopNotifier {
+	return &DesktopNotifier{goos: runtime.GOOS}
+}
+
+func (n *DesktopNotifier) Send(ctx context.Context, title, body string) error {
+	name, args, err := n.command(title, body)
+	if err != nil {
+		return err
+	}
+
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%w: %s not found", ErrNotifierUnavailable, name)
+	}
+
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// command builds the platform specific notification command line
+func (n *DesktopNotifier) command(title, body string) (string, []string, error) {
+	switch n.goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s",
+			appleScriptString(body), appleScriptString(title))
+		return "osascript", []string{"-e", script}, nil
+	case "windows":
+		script := fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode(%s)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode(%s)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('task-cli').Show([Windows.UI.Notifications.ToastNotification]::new($template))`,
+			powerShellString(title), powerShellString(body))
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=task-cli", title, body}, nil
+	default:
+		return "", nil, ErrNotifierUnavailable
+	}
+}
+
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
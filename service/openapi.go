@@ -0,0 +1,201 @@
+package service
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// OpenAPI Document
+//
+// The REST API is described by an OpenAPI 3 document built from the route
+// table of the server and, by reflection, from the Go types its handlers
+// decode and encode. It is served at /api/v1/openapi.json and printed by
+// task-cli api-spec.
+
+// openAPIPath is where the server answers the document
+const openAPIPath = "/api/v1/openapi.json"
+
+// pathParam matches the wildcards of a route pattern, such as {id}
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// OpenAPISpec describes the REST API, with the statuses of workflow as the
+// values a task status may take
+func OpenAPISpec(workflow task.Workflow) map[string]any {
+	g := &schemaGenerator{workflow: workflow, schemas: make(map[string]any)}
+
+	paths := make(map[string]any)
+	for _, route := range (*httpTaskServer)(nil).routes() {
+		operations, _ := paths[route.Path].(map[string]any)
+		if operations == nil {
+			operations = make(map[string]any)
+			paths[route.Path] = operations
+		}
+		operations[strings.ToLower(route.Method)] = g.operation(route)
+	}
+	g.schema(reflect.TypeFor[repository.HTTPErrorBody]())
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "task-cli REST API",
+			"version": Version,
+			"description": "Tasks served by task-cli serve --http. Errors are answered with an ErrorBody " +
+				"whose code, such as NOT_FOUND or WIP_LIMIT, names the problem.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []any{map[string]any{"bearer": []any{}}},
+	}
+}
+
+// schemaGenerator builds JSON schemas from Go types, gathering named
+// structs as components
+type schemaGenerator struct {
+	workflow task.Workflow
+	schemas  map[string]any
+}
+
+func (g *schemaGenerator) operation(route httpRoute) map[string]any {
+	var params []any
+	for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		params = append(params, map[string]any{
+			"name": match[1], "in": "path", "required": true,
+			"schema": map[string]any{"type": "integer"},
+		})
+	}
+	for _, param := range route.Query {
+		params = append(params, map[string]any{
+			"name": param.Name, "in": "query", "description": param.Description,
+			"schema": map[string]any{"type": "string"},
+		})
+	}
+	for _, param := range route.Header {
+		params = append(params, map[string]any{
+			"name": param.Name, "in": "header", "description": param.Description,
+			"schema": map[string]any{"type": "string"},
+		})
+	}
+
+	success := map[string]any{"description": http.StatusText(route.Status)}
+	if route.Response != nil {
+		success["content"] = g.content(route.Response)
+	}
+	responses := map[string]any{strconv.Itoa(route.Status): success}
+	for _, status := range append(slices.Clone(route.Errors), http.StatusUnauthorized, http.StatusInternalServerError) {
+		responses[strconv.Itoa(status)] = map[string]any{
+			"description": http.StatusText(status),
+			"content":     g.content(repository.HTTPErrorBody{}),
+		}
+	}
+
+	operation := map[string]any{
+		"summary":     route.Summary,
+		"operationId": route.ID,
+		"responses":   responses,
+	}
+	if len(params) > 0 {
+		operation["parameters"] = params
+	}
+	if route.Request != nil {
+		operation["requestBody"] = map[string]any{"required": true, "content": g.content(route.Request)}
+	}
+	return operation
+}
+
+func (g *schemaGenerator) content(v any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(v))}}
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	switch t {
+	case reflect.TypeFor[time.Time]():
+		return map[string]any{"type": "string", "format": "date-time"}
+	case reflect.TypeFor[task.Duration]():
+		return map[string]any{"type": "string", "description": "A duration such as 90m or 2h"}
+	case reflect.TypeFor[task.TaskStatus]():
+		return map[string]any{"type": "string", "enum": g.workflow.Statuses}
+	case reflect.TypeFor[task.Priority]():
+		return map[string]any{"type": "string", "enum": []task.Priority{task.PriorityLow, task.PriorityMedium, task.PriorityHigh, task.PriorityUrgent}}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := schemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			// Claim the name first, in case the struct refers to itself
+			g.schemas[name] = nil
+			g.schemas[name] = g.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]any{}
+}
+
+// object describes a struct by its JSON fields. Fields without omitempty
+// are always encoded, so they are required.
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = g.schema(field.Type)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+	}
+	object := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
+
+// schemaName names a type in the document without the http prefix of the
+// server's own types, such as ErrorBody for HTTPErrorBody
+func schemaName(t reflect.Type) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(t.Name(), "http"), "HTTP")
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func (s *httpTaskServer) openAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, OpenAPISpec(s.service.Workflow()))
+}
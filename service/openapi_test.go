@@ -0,0 +1,85 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestOpenAPISpec tests that the document describes every route with the
+// schemas of the types the handlers use
+func TestOpenAPISpec(t *testing.T) {
+	workflow, err := task.NewWorkflow([]string{"todo", "review", "done"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Round trip through JSON to inspect the document as clients see it
+	data, err := json.Marshal(OpenAPISpec(workflow))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec struct {
+		OpenAPI    string                               `json:"openapi"`
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+				Required   []string                  `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.OpenAPI != "3.0.3" {
+		t.Errorf("openapi = %q", spec.OpenAPI)
+	}
+
+	for _, route := range (*httpTaskServer)(nil).routes() {
+		operation, ok := spec.Paths[route.Path][map[string]string{
+			"GET": "get", "PUT": "put", "POST": "post", "PATCH": "patch", "DELETE": "delete",
+		}[route.Method]]
+		if !ok {
+			t.Errorf("%s %s is not described", route.Method, route.Path)
+			continue
+		}
+		if operation["operationId"] != route.ID {
+			t.Errorf("%s %s operationId = %v, want %s", route.Method, route.Path, operation["operationId"], route.ID)
+		}
+	}
+
+	task := spec.Components.Schemas["Task"]
+	if !slices.Contains(task.Required, "id") || slices.Contains(task.Required, "dueAt") {
+		t.Errorf("Task required = %v, want id but not dueAt", task.Required)
+	}
+	if got, _ := task.Properties["status"]["enum"].([]any); !slices.Contains(got, any("review")) {
+		t.Errorf("status enum = %v, want the workflow statuses", got)
+	}
+	if got := task.Properties["createdAt"]["format"]; got != "date-time" {
+		t.Errorf("createdAt format = %v", got)
+	}
+	if _, ok := spec.Components.Schemas["ErrorBody"]; !ok {
+		t.Error("error model is not described")
+	}
+}
+
+// TestHTTPServer_OpenAPI tests that the document is served without a token
+func TestHTTPServer_OpenAPI(t *testing.T) {
+	server := newHTTPTestServer(t, "s3cret", tasktest.FixedTasks(t))
+
+	resp := doHTTP(t, http.MethodGet, server.URL+openAPIPath, "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var spec map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil || spec["paths"] == nil {
+		t.Errorf("document = %v, %v", spec, err)
+	}
+	if resp := doHTTP(t, http.MethodGet, server.URL+"/api/v1/tasks", "", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("tasks without token status = %d, want 401", resp.StatusCode)
+	}
+}
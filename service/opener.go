@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Opener launches a URL or file in the application the user chose for it
+type Opener interface {
+	Open(ctx context.Context, target string) error
+}
+
+// ErrOpenerUnavailable is returned when the system has no way to open files
+var ErrOpenerUnavailable = errors.New("opening files is not supported on this system")
+
+// SystemOpener opens targets with the platform's native tool: xdg-open on
+// Linux and BSD, open on macOS and the URL protocol handler on Windows
+type SystemOpener struct {
+	goos string
+}
+
+func NewSystemOpener() *SystemOpener {
+	return &SystemOpener{goos: runtime.GOOS}
+}
+
+func (o *SystemOpener) Open(ctx context.Context, target string) error {
+	name, args, err := o.command(target)
+	if err != nil {
+		return err
+	}
+
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%w: %s not found", ErrOpenerUnavailable, name)
+	}
+
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// command builds the platform specific command line opening target
+func (o *SystemOpener) command(target string) (string, []string, error) {
+	switch o.goos {
+	case "darwin":
+		return "open", []string{target}, nil
+	case "windows":
+		// Unlike "cmd /c start", rundll32 does not treat & in URLs as a
+		// command separator
+		return "rundll32", []string{"url.dll,FileProtocolHandler", target}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "xdg-open", []string{target}, nil
+	default:
+		return "", nil, ErrOpenerUnavailable
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithProjects stores projects in repo; without it project commands fail
+func (s *TaskService) WithProjects(repo repository.ProjectRepository) *TaskService {
+	s.projects = repo
+	return s
+}
+
+func (s *TaskService) loadProjects(ctx context.Context) ([]task.Project, error) {
+	if s.projects == nil {
+		return nil, task.ErrNoProjects
+	}
+	projects, err := s.projects.LoadProjects(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to load projects", err)
+	}
+	return projects, nil
+}
+
+func (s *TaskService) saveProjects(ctx context.Context, projects []task.Project) error {
+	if err := s.projects.SaveProjects(ctx, projects); err != nil {
+		return repository.StorageError("failed to save projects", err)
+	}
+	return nil
+}
+
+func (s *TaskService) AddProject(ctx context.Context, name string) (*task.Project, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	name, err := task.ValidateProjectName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.loadProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(projects, func(p task.Project) bool { return p.Name == name }) {
+		return nil, task.ErrProjectExists
+	}
+
+	project := task.Project{Name: name, CreatedAt: time.Now()}
+	if err := s.saveProjects(ctx, append(projects, project)); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// Projects returns every project, closed ones included, with its stats
+func (s *TaskService) Projects(ctx context.Context) ([]task.ProjectStats, error) {
+	projects, err := s.loadProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	return task.BuildProjectStats(projects, tasks), nil
+}
+
+// RenameProject renames a project and moves its tasks along, returning how
+// many tasks moved
+func (s *TaskService) RenameProject(ctx context.Context, oldName, newName string) (int, error) {
+	if err := s.writable(); err != nil {
+		return 0, err
+	}
+	newName, err := task.ValidateProjectName(newName)
+	if err != nil {
+		return 0, err
+	}
+
+	projects, err := s.loadProjects(ctx)
+	if err != nil {
+		return 0, err
+	}
+	i := slices.IndexFunc(projects, func(p task.Project) bool { return p.Name == oldName })
+	if i < 0 {
+		return 0, task.ErrProjectNotFound
+	}
+	if newName == oldName {
+		return 0, nil
+	}
+	if slices.ContainsFunc(projects, func(p task.Project) bool { return p.Name == newName }) {
+		return 0, task.ErrProjectExists
+	}
+
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return 0, repository.StorageError("failed to load tasks", err)
+	}
+	before := slices.Clone(tasks)
+	moved := 0
+	for j := range tasks {
+		if tasks[j].Project == oldName {
+			tasks[j].SetProject(newName)
+			moved++
+		}
+	}
+	if moved > 0 {
+		if err := s.save(ctx, before, tasks); err != nil {
+			return 0, repository.StorageError("failed to save tasks", err)
+		}
+	}
+
+	projects[i].Name = newName
+	return moved, s.saveProjects(ctx, projects)
+}
+
+// CloseProject closes a project whose tasks are all done or cancelled
+func (s *TaskService) CloseProject(ctx context.Context, name string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	projects, err := s.loadProjects(ctx)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(projects, func(p task.Project) bool { return p.Name == name })
+	if i < 0 {
+		return task.ErrProjectNotFound
+	}
+	if projects[i].IsClosed() {
+		return task.ErrProjectClosed
+	}
+
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return repository.StorageError("failed to load tasks", err)
+	}
+	if slices.ContainsFunc(tasks, func(t task.Task) bool { return t.Project == name && t.IsOpen() }) {
+		return task.ErrProjectOpenWork
+	}
+
+	now := time.Now()
+	projects[i].ClosedAt = &now
+	return s.saveProjects(ctx, projects)
+}
+
+// SetTaskProject files a task under an open project, or removes it from
+// its project with ""
+func (s *TaskService) SetTaskProject(ctx context.Context, id int, name string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if name != "" {
+		if err := s.checkProjects(ctx, []string{name}); err != nil {
+			return err
+		}
+	}
+	return s.updateTask(ctx, id, func(task *task.Task) error {
+		task.SetProject(name)
+		return nil
+	})
+}
+
+// checkProjects verifies that every named project exists and is open
+func (s *TaskService) checkProjects(ctx context.Context, names []string) error {
+	projects, err := s.loadProjects(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		i := slices.IndexFunc(projects, func(p task.Project) bool { return p.Name == name })
+		if i < 0 {
+			return fmt.Errorf("%w: %s", task.ErrProjectNotFound, name)
+		}
+		if projects[i].IsClosed() {
+			return fmt.Errorf("%w: %s", task.ErrProjectClosed, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_Projects tests the project lifecycle in the service
+func TestTaskService_Projects(t *testing.T) {
+	ctx := context.Background()
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	service := NewTaskService(repo).
+		WithProjects(repository.NewFileProjectRepository(filepath.Join(t.TempDir(), "projects.json")))
+
+	if _, err := service.AddProject(ctx, "website"); err != nil {
+		t.Fatalf("AddProject: %v", err)
+	}
+	if _, err := service.AddProject(ctx, "website"); !errors.Is(err, task.ErrProjectExists) {
+		t.Errorf("duplicate AddProject error = %v, want ErrProjectExists", err)
+	}
+	if _, err := service.AddTask(ctx, "Stray", task.WithProject("missing")); !errors.Is(err, task.ErrProjectNotFound) {
+		t.Errorf("AddTask to missing project error = %v, want ErrProjectNotFound", err)
+	}
+
+	item, err := service.AddTask(ctx, "Design homepage", task.WithProject("website"))
+	if err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	if err := service.SetTaskProject(ctx, 1, "website"); err != nil {
+		t.Fatalf("SetTaskProject: %v", err)
+	}
+
+	if err := service.CloseProject(ctx, "website"); !errors.Is(err, task.ErrProjectOpenWork) {
+		t.Errorf("CloseProject with open tasks error = %v, want ErrProjectOpenWork", err)
+	}
+
+	moved, err := service.RenameProject(ctx, "website", "site")
+	if err != nil || moved != 2 {
+		t.Fatalf("RenameProject = %d, %v, want 2 tasks moved", moved, err)
+	}
+	if got, _ := repo.GetTask(item.ID); got.Project != "site" {
+		t.Errorf("task project after rename = %q, want site", got.Project)
+	}
+
+	for _, id := range []int{1, item.ID} {
+		if err := service.MarkTaskDone(ctx, id); err != nil {
+			t.Fatalf("MarkDone(%d): %v", id, err)
+		}
+	}
+	if err := service.CloseProject(ctx, "site"); err != nil {
+		t.Fatalf("CloseProject: %v", err)
+	}
+	if err := service.SetTaskProject(ctx, 2, "site"); !errors.Is(err, task.ErrProjectClosed) {
+		t.Errorf("SetTaskProject to closed project error = %v, want ErrProjectClosed", err)
+	}
+
+	stats, err := service.Projects(ctx)
+	if err != nil {
+		t.Fatalf("Projects: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Name != "site" || !stats[0].IsClosed() || stats[0].Done != 2 {
+		t.Errorf("Projects = %+v, want closed site with 2 done", stats)
+	}
+}
+
+// TestTaskService_ProjectsUnavailable tests project commands without storage
+func TestTaskService_ProjectsUnavailable(t *testing.T) {
+	service := NewTaskService(tasktest.NewMockRepository())
+	if _, err := service.AddProject(context.Background(), "website"); !errors.Is(err, task.ErrNoProjects) {
+		t.Errorf("AddProject error = %v, want errNoProjects", err)
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Quick-Add Adapter (Presentation Layer)
+//
+// Handles POST /quick-add, whose plain-text body becomes one task, so that
+// iOS Shortcuts, IFTTT and dictated voice memos can capture tasks with a
+// single request. The text uses the shorthand of add, such as
+// "(A) Call the plumber +home", and the task lands in the inbox for triage.
+// The reply is plain text too, for shortcuts to show as is.
+
+// QuickAddPath is where serve --http answers quick-add requests
+const QuickAddPath = "/quick-add"
+
+// quickAddMaxBytes bounds a captured text; a dictated memo is far shorter
+const quickAddMaxBytes = 64 << 10
+
+type quickAddHandler struct {
+	service *TaskService
+}
+
+// NewQuickAddHandler creates a task from the plain-text body of each
+// request
+func NewQuickAddHandler(service *TaskService) http.Handler {
+	return &quickAddHandler{service: service}
+}
+
+func (h *quickAddHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeHTTPError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "quick-add text is POSTed")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, quickAddMaxBytes+1))
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, task.ErrInvalidBody.Code, err.Error())
+		return
+	}
+	if len(body) > quickAddMaxBytes {
+		writeServiceError(w, task.ErrBodyTooLarge)
+		return
+	}
+
+	// Dictation may break the text into lines; they describe one task
+	draft := task.ParseTaskLine(strings.Join(strings.Fields(string(body)), " "))
+	task, err := h.service.AddTask(r.Context(), draft.Description, append(draft.Options, task.WithTags(InboxTag))...)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintf(w, "Captured task %d: %s\n", task.ID, task.Description)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+func TestQuickAdd(t *testing.T) {
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	auth, err := NewAuthenticator(repository.ServerConfig{Tokens: []repository.ServerCredential{
+		{Name: "phone", Token: "shortcut-token", Scope: "write"},
+		{Name: "dashboard", Token: "viewer-token"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(auth.RequireHTTP(NewQuickAddHandler(NewTaskService(repo))))
+	t.Cleanup(server.Close)
+	bearer := func(token string) http.Header {
+		return http.Header{"Authorization": {"Bearer " + token}, "Content-Type": {"text/plain"}}
+	}
+
+	resp := doHTTP(t, http.MethodPost, server.URL+QuickAddPath, "(A) Call the\nplumber +home\n", bearer("shortcut-token"))
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", resp.StatusCode)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != "Captured task 4: Call the plumber\n" {
+		t.Errorf("body = %q", body)
+	}
+	item, ok := repo.GetTask(4)
+	if !ok {
+		t.Fatal("task 4 was not created")
+	}
+	if item.Priority != task.PriorityUrgent || !slices.Contains(item.Tags, "home") || !slices.Contains(item.Tags, InboxTag) {
+		t.Errorf("task = %+v, want urgent, tagged home and inbox", item)
+	}
+	if item.CreatedBy != "phone" {
+		t.Errorf("created by %q, want phone", item.CreatedBy)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		body     string
+		header   http.Header
+		wantCode int
+	}{
+		{"no token", http.MethodPost, "Sneaky", nil, http.StatusUnauthorized},
+		{"read token", http.MethodPost, "Sneaky", bearer("viewer-token"), http.StatusForbidden},
+		{"empty text", http.MethodPost, "  \n", bearer("shortcut-token"), http.StatusBadRequest},
+		{"too long", http.MethodPost, strings.Repeat("x", quickAddMaxBytes+1), bearer("shortcut-token"), http.StatusRequestEntityTooLarge},
+		{"not a POST", http.MethodGet, "", bearer("shortcut-token"), http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := doHTTP(t, tt.method, server.URL+QuickAddPath, tt.body, tt.header); resp.StatusCode != tt.wantCode {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+		})
+	}
+	if count := repo.TaskCount(); count != 4 {
+		t.Errorf("task count = %d, want 4", count)
+	}
+}
+
+// TestQuickAdd_NoCredentials tests that quick add refuses every request
+// while the server has no credentials, rather than taking anyone's tasks
+func TestQuickAdd_NoCredentials(t *testing.T) {
+	repo := tasktest.NewMockRepository()
+	auth, err := NewAuthenticator(repository.ServerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(auth.RequireHTTP(NewQuickAddHandler(NewTaskService(repo))))
+	t.Cleanup(server.Close)
+
+	header := http.Header{"Authorization": {"Bearer anything"}, "Content-Type": {"text/plain"}}
+	for name, header := range map[string]http.Header{"no token": nil, "any token": header} {
+		if resp := doHTTP(t, http.MethodPost, server.URL+QuickAddPath, "Sneaky", header); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, resp.StatusCode)
+		}
+	}
+	if count := repo.TaskCount(); count != 0 {
+		t.Errorf("task count = %d, want 0", count)
+	}
+}
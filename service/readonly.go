@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithReadOnly refuses every change, so that viewers of a shared task file
+// can list and report on it without risking a write
+func (s *TaskService) WithReadOnly(readOnly bool) *TaskService {
+	s.readOnly = readOnly
+	return s
+}
+
+// ReadOnly reports whether the service refuses changes
+func (s *TaskService) ReadOnly() bool {
+	return s.readOnly
+}
+
+// writable fails with ErrReadOnly in read-only mode. Use cases that change
+// anything call it first, before reading the store.
+func (s *TaskService) writable() error {
+	if s.readOnly {
+		return task.ErrReadOnly
+	}
+	return nil
+}
+
+// FileReadOnly reports whether path exists but cannot be opened for
+// writing, such as a task file shared without write permission
+func FileReadOnly(path string) bool {
+	if path == "" {
+		return false
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return errors.Is(err, fs.ErrPermission)
+	}
+	f.Close()
+	return false
+}
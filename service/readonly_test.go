@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_ReadOnly tests that changes fail before the store is read
+func TestTaskService_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	service := NewTaskService(repo).WithReadOnly(true)
+
+	changes := map[string]func() error{
+		"add": func() error {
+			_, err := service.AddTask(ctx, "New task")
+			return err
+		},
+		"update": func() error { return service.UpdateTask(ctx, 1, "Changed") },
+		"move":   func() error { return service.MarkTaskDone(ctx, 1) },
+		"delete": func() error { return service.DeleteTask(ctx, 1) },
+		"replace": func() error {
+			return service.ReplaceTasks(ctx, nil)
+		},
+		"reminders": func() error {
+			_, err := service.FireReminders(ctx, time.Now())
+			return err
+		},
+		"view": func() error { return service.SaveView(ctx, task.View{Name: "mine"}) },
+	}
+	for name, change := range changes {
+		if err := change(); !errors.Is(err, task.ErrReadOnly) {
+			t.Errorf("%s err = %v, want %v", name, err, task.ErrReadOnly)
+		}
+	}
+	if repo.LoadCallCount() != 0 || repo.SaveCallCount() != 0 {
+		t.Errorf("store read %d and written %d times, want neither", repo.LoadCallCount(), repo.SaveCallCount())
+	}
+
+	// Dry runs change nothing, so they still work
+	if _, err := service.Dedupe(ctx, task.DedupeOptions{DryRun: true}); err != nil {
+		t.Errorf("dry-run dedupe: %v", err)
+	}
+	if tasks, err := service.ListTasks(ctx, ""); err != nil || len(tasks) != 3 {
+		t.Errorf("ListTasks = %d tasks, %v", len(tasks), err)
+	}
+}
+
+func TestFileReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.json")
+	if FileReadOnly(path) {
+		t.Error("missing file is read-only, want writable once created")
+	}
+	if err := os.WriteFile(path, []byte("[]"), 0o444); err != nil {
+		t.Fatal(err)
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root can write read-only files")
+	}
+	if !FileReadOnly(path) {
+		t.Error("file without write permission is not read-only")
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// LinkTasks relates task a to task b, storing the relation on both so
+// either one shows it
+func (s *TaskService) LinkTasks(ctx context.Context, a int, kind task.RelationType, b int) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if a == b || (kind != task.RelationDuplicates && kind != task.RelationRelatesTo) {
+		return task.ErrInvalidRelation
+	}
+	before, err := s.getTasks(ctx, []int{a, b})
+	if err != nil {
+		return err
+	}
+
+	after := slices.Clone(before)
+	after[0].Link(kind, b)
+	after[1].Link(kind.Inverse(), a)
+	return s.save(ctx, before, after)
+}
+
+// UnlinkTasks removes the relation between two tasks from both of them
+func (s *TaskService) UnlinkTasks(ctx context.Context, a, b int) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	before, err := s.getTasks(ctx, []int{a, b})
+	if err != nil {
+		return err
+	}
+
+	after := slices.Clone(before)
+	if unlinked := after[0].Unlink(b); !after[1].Unlink(a) && !unlinked {
+		return task.ErrRelationNotFound.Withf("%d and %d", a, b)
+	}
+	return s.save(ctx, before, after)
+}
+
+// getTasks returns the tasks in the order of ids, failing on the first
+// one that does not exist
+func (s *TaskService) getTasks(ctx context.Context, ids []int) ([]task.Task, error) {
+	tasks := make([]task.Task, 0, len(ids))
+	for _, id := range ids {
+		task, err := s.store.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// unlinkDeleted returns the tasks still related to deleted ones, before and
+// after their relations to them are removed, so a delete leaves no
+// relation pointing nowhere
+func (s *TaskService) unlinkDeleted(ctx context.Context, deleted []task.Task) (before, after []task.Task, err error) {
+	gone := make(map[int]bool, len(deleted))
+	related := make(map[int]bool)
+	for _, task := range deleted {
+		gone[task.ID] = true
+		for _, relation := range task.Relations {
+			related[relation.Task] = true
+		}
+	}
+	for id := range related {
+		if gone[id] {
+			continue
+		}
+		t, err := s.store.Get(ctx, id)
+		if errors.Is(err, task.ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		updated := t
+		for _, relation := range t.Relations {
+			if gone[relation.Task] {
+				updated.Unlink(relation.Task)
+			}
+		}
+		before, after = append(before, t), append(after, updated)
+	}
+	return before, after, nil
+}
+
+// updateClosing applies updateFn to a task and, when that closes it, to
+// the open tasks linked to it as duplicates, all in one save. Duplicates
+// the change cannot apply to, such as those the workflow keeps from
+// moving, stay as they are.
+func (s *TaskService) updateClosing(ctx context.Context, id int, updateFn func(*task.Task) error) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	t, err := s.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	before, after := []task.Task{t}, []task.Task{t}
+	if err := updateFn(&after[0]); err != nil {
+		return err
+	}
+	if !t.IsOpen() || after[0].IsOpen() || s.workflow.KeepDuplicatesOpen {
+		return s.save(ctx, before, after)
+	}
+
+	for _, dupID := range t.DuplicateIDs() {
+		dup, err := s.store.Get(ctx, dupID)
+		if errors.Is(err, task.ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		closed := dup
+		if !dup.IsOpen() || updateFn(&closed) != nil {
+			continue
+		}
+		before, after = append(before, dup), append(after, closed)
+	}
+	return s.save(ctx, before, after)
+}
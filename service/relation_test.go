@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_CloseDuplicates tests that closing a task closes the
+// tasks linked to it as duplicates, unless the workflow keeps them open
+func TestTaskService_CloseDuplicates(t *testing.T) {
+	ctx := context.Background()
+	tasks := []task.Task{
+		*tasktest.NewTaskBuilder().WithID(1).WithDescription("Fix login").BuildInvalid(),
+		*tasktest.NewTaskBuilder().WithID(2).WithDescription("Login broken").BuildInvalid(),
+		*tasktest.NewTaskBuilder().WithID(3).WithDescription("Update docs").BuildInvalid(),
+	}
+	repo := tasktest.NewMockRepository().WithTasks(tasks)
+	service := NewTaskService(repo)
+
+	if err := service.LinkTasks(ctx, 2, task.RelationDuplicates, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.LinkTasks(ctx, 3, task.RelationRelatesTo, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.MarkTaskDone(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[int]task.TaskStatus{1: task.StatusDone, 2: task.StatusDone, 3: task.StatusTodo} {
+		if task, _ := repo.GetTask(id); task.Status != want {
+			t.Errorf("task %d status = %s, want %s", id, task.Status, want)
+		}
+	}
+
+	workflow := task.DefaultWorkflow()
+	workflow.KeepDuplicatesOpen = true
+	service.WithWorkflow(workflow)
+	if err := service.MoveTask(ctx, 1, task.StatusTodo); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.MoveTask(ctx, 2, task.StatusTodo); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.CancelTask(ctx, 2, "not a bug"); err != nil {
+		t.Fatal(err)
+	}
+	if item, _ := repo.GetTask(1); item.Status != task.StatusTodo {
+		t.Errorf("task 1 status = %s, want todo with duplicates kept open", item.Status)
+	}
+}
+
+// TestTaskService_DeleteUnlinks tests that deleting a task removes the
+// relations other tasks have to it
+func TestTaskService_DeleteUnlinks(t *testing.T) {
+	ctx := context.Background()
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	service := NewTaskService(repo)
+
+	if err := service.LinkTasks(ctx, 1, task.RelationDuplicates, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.LinkTasks(ctx, 3, task.RelationRelatesTo, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeleteTask(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []int{1, 3} {
+		item, _ := repo.GetTask(id)
+		if slices.ContainsFunc(item.Relations, func(r task.Relation) bool { return r.Task == 2 }) {
+			t.Errorf("task %d still relates to the deleted task: %+v", id, item.Relations)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// reminderGrace is how late a reminder may fire before it is reported as missed
+const reminderGrace = time.Minute
+
+// Reminder is a task reminder that came due
+type Reminder struct {
+	Task task.Task
+	// At is when the reminder was set for
+	At time.Time
+}
+
+// Message describes the reminder for a notification body, mentioning when
+// it was meant to fire if it was missed while nothing was running
+func (r Reminder) Message(now time.Time) string {
+	message := fmt.Sprintf("#%d %s", r.Task.ID, r.Task.Description)
+	if now.Sub(r.At) > reminderGrace {
+		message += fmt.Sprintf(" (missed, was set for %s)", r.At.Format("2006-01-02 15:04"))
+	}
+	return message
+}
+
+// FireReminders clears the reminders of open tasks that are due at now and
+// returns them. The tasks themselves are the reminder queue: clearing is
+// saved, so each reminder fires once even across restarts, and reminders
+// that came due while nothing was running fire on the next call.
+func (s *TaskService) FireReminders(ctx context.Context, now time.Time) ([]Reminder, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	before := slices.Clone(tasks)
+
+	var fired []Reminder
+	for i, task := range tasks {
+		if !task.IsOpen() || task.RemindAt == nil || task.RemindAt.After(now) {
+			continue
+		}
+		fired = append(fired, Reminder{Task: task, At: *task.RemindAt})
+		tasks[i].SetReminder(nil)
+	}
+	if len(fired) == 0 {
+		return nil, nil
+	}
+
+	if err := s.save(ctx, before, tasks); err != nil {
+		return nil, repository.StorageError("failed to save tasks", err)
+	}
+	return fired, nil
+}
+
+// NextReminder returns when the earliest pending reminder of an open task
+// is due, or nil when there is none
+func (s *TaskService) NextReminder(ctx context.Context) (*time.Time, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+
+	var next *time.Time
+	for _, task := range tasks {
+		if task.IsOpen() && task.RemindAt != nil && (next == nil || task.RemindAt.Before(*next)) {
+			next = task.RemindAt
+		}
+	}
+	return next, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestTaskService_FireReminders tests that due reminders fire once
+func TestTaskService_FireReminders(t *testing.T) {
+	now := tasktest.FixedTime()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].RemindAt = at(-time.Hour) // missed while offline
+	tasks[1].RemindAt = at(time.Hour)  // not due yet
+	tasks[2].RemindAt = at(-time.Hour) // done, never fires
+	repo := tasktest.NewMockRepository().WithTasks(tasks)
+	service := NewTaskService(repo)
+
+	fired, err := service.FireReminders(t.Context(), now)
+	if err != nil {
+		t.Fatalf("FireReminders() error = %v", err)
+	}
+	if len(fired) != 1 || fired[0].Task.ID != 1 {
+		t.Fatalf("fired = %+v, want task 1 only", fired)
+	}
+	if got := fired[0].Message(now); !strings.Contains(got, "missed, was set for 2024-01-01 11:00") {
+		t.Errorf("Message() = %q, want it marked as missed", got)
+	}
+
+	task, _ := repo.GetTask(1)
+	if task.RemindAt != nil {
+		t.Errorf("RemindAt = %v, want cleared after firing", task.RemindAt)
+	}
+
+	fired, err = service.FireReminders(t.Context(), now)
+	if err != nil || len(fired) != 0 {
+		t.Errorf("second FireReminders() = %+v, %v, want nothing", fired, err)
+	}
+
+	next, err := service.NextReminder(t.Context())
+	if err != nil || next == nil || !next.Equal(*at(time.Hour)) {
+		t.Errorf("NextReminder() = %v, %v, want %v", next, err, at(time.Hour))
+	}
+}
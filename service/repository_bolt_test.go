@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/repository"
+)
+
+// TestBoltTaskRepository_Service tests that the service keeps using the
+// sequence through the cache
+func TestBoltTaskRepository_Service(t *testing.T) {
+	ctx := context.Background()
+	cache := repository.NewCachingRepository(repository.NewBoltTaskRepository(filepath.Join(t.TempDir(), "tasks.db")))
+	service := NewTaskService(cache)
+
+	cache.Begin()
+	service.AddTask(ctx, "Buy groceries")
+	service.AddTask(ctx, "Write report")
+	if err := cache.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	cache.Begin()
+	if err := service.DeleteTask(ctx, 2); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+	if err := cache.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	cache.Begin()
+	task, err := service.AddTask(ctx, "Call mom")
+	if err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	if err := cache.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if task.ID != 3 {
+		t.Errorf("new task ID = %d, want 3 after deleting task 2", task.ID)
+	}
+}
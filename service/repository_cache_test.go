@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestCachingRepository_UnitOfWork tests that a unit of work writes the
+// store once, reading it once more only to check for conflicts
+func TestCachingRepository_UnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	mock := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	cache := repository.NewCachingRepository(mock)
+	service := NewTaskService(cache)
+
+	cache.Begin()
+	item, err := service.AddTask(ctx, "Plan trip")
+	if err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	if err := service.MarkTaskInProgress(ctx, item.ID); err != nil {
+		t.Fatalf("MarkTaskInProgress: %v", err)
+	}
+	if mock.SaveCallCount() != 0 || mock.TaskCount() != 3 {
+		t.Errorf("store written before Commit: %d saves, %d tasks", mock.SaveCallCount(), mock.TaskCount())
+	}
+	if err := cache.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	if mock.LoadCallCount() != 2 || mock.SaveCallCount() != 1 {
+		t.Errorf("loads = %d, saves = %d; want 2 and 1", mock.LoadCallCount(), mock.SaveCallCount())
+	}
+	if stored, ok := mock.GetTask(4); !ok || stored.Status != task.StatusInProgress {
+		t.Errorf("stored task 4 = %+v, want in progress", stored)
+	}
+
+	// Nothing to write when nothing changed
+	cache.Begin()
+	if _, err := service.ListTasks(ctx, ""); err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if err := cache.Commit(ctx); err != nil || mock.SaveCallCount() != 1 {
+		t.Errorf("read-only unit: Commit = %v, saves = %d; want no write", err, mock.SaveCallCount())
+	}
+}
+
+// TestCachingRepository_NextID tests that a unit of work does not hand out
+// the ID of a task deleted by an earlier command
+func TestCachingRepository_NextID(t *testing.T) {
+	ctx := context.Background()
+	cache := repository.NewCachingRepository(repository.NewFileTaskRepository(t.TempDir() + "/tasks.json"))
+	service := NewTaskService(cache)
+
+	for _, step := range []func() error{
+		func() error { _, err := service.AddTask(ctx, "Buy groceries"); return err },
+		func() error { _, err := service.AddTask(ctx, "Write report"); return err },
+		func() error { return service.DeleteTask(ctx, 2) },
+	} {
+		cache.Begin()
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.Commit(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cache.Begin()
+	task, err := service.AddTask(ctx, "Call mom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != 3 {
+		t.Errorf("new task ID = %d, want 3 after deleting task 2", task.ID)
+	}
+}
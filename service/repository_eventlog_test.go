@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+func newEventLogTestRepo(t *testing.T) (*repository.EventLogRepository, string) {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "tasks.jsonl")
+	return repository.NewEventLogRepository(filename), filename
+}
+
+// TestEventLogRepository_History tests task versions read from the log
+func TestEventLogRepository_History(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newEventLogTestRepo(t)
+	service := NewTaskService(repository.NewCachingRepository(repo))
+
+	service.AddTask(ctx, "Buy groceries")
+	service.MarkTaskInProgress(ctx, 1)
+	service.DeleteTask(ctx, 1)
+
+	versions, err := repo.History(ctx, 1)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(versions) != 3 || versions[1].Task.Status != task.StatusInProgress ||
+		versions[2].Task != nil || versions[2].Message != "deleted #1" {
+		t.Errorf("History = %+v", versions)
+	}
+
+	entries, err := service.TaskHistory(ctx, 1)
+	if err != nil {
+		t.Fatalf("TaskHistory: %v", err)
+	}
+	if len(entries) != 3 || entries[1].Change.Type != task.ChangeUpdated || entries[2].Change.Type != task.ChangeDeleted {
+		t.Errorf("TaskHistory = %+v", entries)
+	}
+}
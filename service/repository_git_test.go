@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestGitTaskRepository tests auto-commits and history
+func TestGitTaskRepository(t *testing.T) {
+	repo := repository.NewGitTaskRepository(repository.NewFileTaskRepository(tasktest.GitTaskFile(t)))
+	service := NewTaskService(repo)
+	ctx := t.Context()
+
+	item, err := service.AddTask(ctx, "Buy groceries")
+	if err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+	if err := service.UpdateTask(ctx, item.ID, "Buy milk"); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.MarkTaskDone(ctx, item.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddTask(ctx, "Unrelated"); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err := repo.Git(ctx, "log", "--format=%s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "add #2: Unrelated\nmark-done #1: Buy milk\nupdate #1: Buy milk\nadd #1: Buy groceries\n"
+	if log != want {
+		t.Errorf("git log =\n%s\nwant\n%s", log, want)
+	}
+
+	history, err := service.TaskHistory(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("TaskHistory() failed: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("TaskHistory() returned %d entries, want 3", len(history))
+	}
+	if history[0].Change.Type != task.ChangeAdded || history[2].Change.Task.Status != task.StatusDone {
+		t.Errorf("TaskHistory() = %+v", history)
+	}
+
+	if _, err := service.TaskHistory(ctx, 42); !errors.Is(err, task.ErrTaskNotFound) {
+		t.Errorf("TaskHistory(42) error = %v, want %v", err, task.ErrTaskNotFound)
+	}
+}
+
+// TestGitTaskRepository_EnclosingRepo tests that a data directory inside
+// another work tree gets a repository of its own
+func TestGitTaskRepository_EnclosingRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	project := t.TempDir()
+	if out, err := exec.Command("git", "init", "--quiet", project).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	dataDir := filepath.Join(project, "data")
+	if err := os.Mkdir(dataDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := repository.NewGitTaskRepository(repository.NewFileTaskRepository(filepath.Join(dataDir, "tasks.json")))
+	ctx := t.Context()
+	if _, err := NewTaskService(repo).AddTask(ctx, "Buy groceries"); err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+
+	if top, err := repo.Git(ctx, "rev-parse", "--show-toplevel"); err != nil || filepath.Base(strings.TrimSpace(top)) != "data" {
+		t.Errorf("repository top level = %q, %v; want the data directory", top, err)
+	}
+	if out, err := exec.Command("git", "-C", project, "rev-parse", "--verify", "--quiet", "HEAD").CombinedOutput(); err == nil {
+		t.Errorf("enclosing repository got a commit: %s", out)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestHTTPTaskRepository tests the remote repository against a real server
+func TestHTTPTaskRepository(t *testing.T) {
+	t.Run("round trip through the service", func(t *testing.T) {
+		server := newHTTPTestServer(t, "secret", tasktest.FixedTasks(t))
+		service := NewTaskService(repository.NewHTTPTaskRepository(server.URL, "secret"))
+
+		item, err := service.AddTask(t.Context(), "Remote task")
+		if err != nil {
+			t.Fatalf("AddTask() failed: %v", err)
+		}
+		if item.ID != 4 {
+			t.Errorf("AddTask() ID = %d, want 4", item.ID)
+		}
+		if err := service.MarkTaskDone(t.Context(), item.ID); err != nil {
+			t.Fatalf("MarkTaskDone() failed: %v", err)
+		}
+
+		other := NewTaskService(repository.NewHTTPTaskRepository(server.URL+"/", "secret"))
+		got, err := other.GetTask(t.Context(), item.ID)
+		if err != nil {
+			t.Fatalf("GetTask() from second client failed: %v", err)
+		}
+		if got.Status != task.StatusDone {
+			t.Errorf("status seen by second client = %q, want %q", got.Status, task.StatusDone)
+		}
+	})
+
+	t.Run("concurrent edit is a conflict", func(t *testing.T) {
+		server := newHTTPTestServer(t, "", tasktest.FixedTasks(t))
+		first := repository.NewHTTPTaskRepository(server.URL, "")
+		second := repository.NewHTTPTaskRepository(server.URL, "")
+
+		tasks, _ := first.Load(t.Context())
+		if _, err := second.Load(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if err := first.Save(t.Context(), tasks[:2]); err != nil {
+			t.Fatalf("first Save() failed: %v", err)
+		}
+		if err := second.Save(t.Context(), tasks[:1]); !errors.Is(err, repository.ErrRemoteConflict) {
+			t.Errorf("second Save() error = %v, want %v", err, repository.ErrRemoteConflict)
+		}
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		server := newHTTPTestServer(t, "secret", nil)
+		repo := repository.NewHTTPTaskRepository(server.URL, "guess")
+
+		if _, err := repo.Load(t.Context()); !errors.Is(err, repository.ErrRemoteUnauthorized) {
+			t.Errorf("Load() error = %v, want %v", err, repository.ErrRemoteUnauthorized)
+		}
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(`[]`))
+		}))
+		t.Cleanup(server.Close)
+
+		repo := repository.NewHTTPTaskRepository(server.URL, "").WithRetries(3, time.Millisecond)
+		if _, err := repo.Load(t.Context()); err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if calls.Load() != 3 {
+			t.Errorf("server saw %d requests, want 3", calls.Load())
+		}
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL
+		server.Close()
+
+		repo := repository.NewHTTPTaskRepository(url, "").WithRetries(1, time.Millisecond)
+		if _, err := repo.Load(t.Context()); !errors.Is(err, repository.ErrRemoteUnavailable) {
+			t.Errorf("Load() error = %v, want %v", err, repository.ErrRemoteUnavailable)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		repo := repository.NewHTTPTaskRepository(server.URL, "").
+			WithTimeout(20*time.Millisecond).
+			WithRetries(0, 0)
+		if _, err := repo.Load(t.Context()); !errors.Is(err, repository.ErrRemoteUnavailable) {
+			t.Errorf("Load() error = %v, want %v", err, repository.ErrRemoteUnavailable)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTodoTxtRepository tests using a todo.txt file as the task store
+func TestTodoTxtRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.txt")
+	content := "(A) 2024-01-01 Call mom +family id:3\n\nBuy milk @store\nx 2024-01-02 2024-01-01 Old chore id:3\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := repository.NewTodoTxtRepository(path)
+	repo.Location = time.UTC
+
+	tasks, err := repo.Load(t.Context())
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	// Missing and repeated IDs are numbered after the highest one
+	var ids []int
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	if len(ids) != 3 || ids[0] != 3 || ids[1] != 4 || ids[2] != 5 {
+		t.Fatalf("IDs = %v, want [3 4 5]", ids)
+	}
+
+	service := NewTaskService(repo)
+	if err := service.MarkTaskDone(t.Context(), 4); err != nil {
+		t.Fatalf("MarkTaskDone() failed: %v", err)
+	}
+	if _, err := service.AddTask(t.Context(), "Water plants"); err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("file has %d lines, want 4:\n%s", len(lines), data)
+	}
+	if lines[0] != "(A) 2024-01-01 Call mom +family id:3" {
+		t.Errorf("untouched line changed: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "x ") || !strings.HasSuffix(lines[1], "Buy milk @store id:4") {
+		t.Errorf("completed line = %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[3], "Water plants id:6") {
+		t.Errorf("added line = %q", lines[3])
+	}
+
+	reloaded, err := repo.Load(t.Context())
+	if err != nil || len(reloaded) != 4 || reloaded[1].Status != task.StatusDone {
+		t.Errorf("reload = %+v, %v", reloaded, err)
+	}
+}
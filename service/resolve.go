@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// ResolveTaskID turns what a user typed to name a task into its ID, trying
+// in turn:
+//   - a number, which is the ID itself
+//   - a UUID or a unique prefix of one, like git abbreviates commit hashes
+//   - a description, matched whole, then as a prefix, then anywhere in it,
+//     ignoring case
+//
+// A description matching several tasks names the open one when only one
+// of them is open, and is otherwise refused with the candidates listed.
+func (s *TaskService) ResolveTaskID(ctx context.Context, ref string) (int, error) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if id, err := strconv.Atoi(ref); err == nil {
+		return id, nil
+	}
+	if ref == "" {
+		return 0, task.ErrInvalidID
+	}
+
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return 0, repository.StorageError("failed to load tasks", err)
+	}
+
+	if len(ref) >= task.MinUUIDPrefix && strings.Trim(ref, "0123456789abcdef-") == "" {
+		var matches []task.Task
+		for _, t := range tasks {
+			if strings.HasPrefix(task.TaskUUID(t), ref) {
+				matches = append(matches, t)
+			}
+		}
+		switch {
+		case len(matches) == 1:
+			return matches[0].ID, nil
+		case len(matches) > 1:
+			ids := make([]string, len(matches))
+			for i, task := range matches {
+				ids[i] = strconv.Itoa(task.ID)
+			}
+			return 0, task.ErrAmbiguousID.Withf("%s", strings.Join(ids, ", "))
+		}
+	}
+
+	return resolveDescription(tasks, ref)
+}
+
+// descriptionMatchers rank how a reference matches a lowercased
+// description, best first
+var descriptionMatchers = []func(description, ref string) bool{
+	func(description, ref string) bool { return description == ref },
+	strings.HasPrefix,
+	strings.Contains,
+}
+
+// resolveDescription finds the task a lowercased reference describes, using
+// the best way of matching that finds any task
+func resolveDescription(tasks []task.Task, ref string) (int, error) {
+	for _, matches := range descriptionMatchers {
+		var found, open []task.Task
+		for _, task := range tasks {
+			if matches(strings.ToLower(task.Description), ref) {
+				found = append(found, task)
+				if task.IsOpen() {
+					open = append(open, task)
+				}
+			}
+		}
+		switch {
+		case len(found) == 0:
+			continue
+		case len(found) == 1:
+			return found[0].ID, nil
+		case len(open) == 1:
+			return open[0].ID, nil
+		}
+
+		candidates := make([]string, len(found))
+		for i, task := range found {
+			candidates[i] = fmt.Sprintf("  #%d %s (%s)", task.ID, task.Description, task.Status)
+		}
+		return 0, task.ErrAmbiguousTask.Withf("%q\n%s", ref, strings.Join(candidates, "\n"))
+	}
+	return 0, task.ErrTaskNotFound.Withf("%q", ref)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_ResolveTaskID_Description tests naming tasks by their description
+func TestTaskService_ResolveTaskID_Description(t *testing.T) {
+	tasks := append(tasktest.FixedTasks(t),
+		task.Task{ID: 4, Description: "Write tests", Status: task.StatusTodo},
+		task.Task{ID: 5, Description: "Call", Status: task.StatusTodo},
+		task.Task{ID: 6, Description: "Pay rent", Status: task.StatusDone},
+		task.Task{ID: 7, Description: "Pay rent", Status: task.StatusTodo},
+	)
+	service := NewTaskService(tasktest.NewMockRepository().WithTasks(tasks))
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    int
+		wantErr error
+	}{
+		{"substring", "groceries", 1, nil},
+		{"ignores case", "BUY", 1, nil},
+		{"exact over prefix", "call", 5, nil},
+		{"prefix over substring", "call m", 3, nil},
+		{"only open match", "rent", 7, nil},
+		{"ambiguous", "write", 0, task.ErrAmbiguousTask},
+		{"no match", "dentist", 0, task.ErrTaskNotFound},
+		{"empty", " ", 0, task.ErrInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := service.ResolveTaskID(context.Background(), tt.ref)
+			switch {
+			case tt.wantErr == nil && (err != nil || id != tt.want):
+				t.Errorf("ResolveTaskID(%q) = %d, %v; want %d", tt.ref, id, err, tt.want)
+			case tt.wantErr != nil && (!errors.Is(err, tt.wantErr)):
+				t.Errorf("ResolveTaskID(%q) error = %v, want %v", tt.ref, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// RunSchedules creates the task of each schedule's latest occurrence up to
+// now. Missed occurrences are not replayed one by one: a schedule that has
+// not run for three weeks creates one task, not three. An occurrence is
+// created at most once, whether state records it or a task already links to
+// it, so running twice or from two machines does not duplicate tasks. state
+// is updated with the occurrences handled.
+func (s *TaskService) RunSchedules(ctx context.Context, schedules []task.Schedule, state task.ScheduleState, now time.Time) ([]task.Task, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+
+	var drafts []task.TaskDraft
+	var handled []task.Schedule
+	var occurrences []time.Time
+	for _, schedule := range schedules {
+		occurrence, ok := schedule.Cron.Previous(now)
+		if !ok || !occurrence.After(state[schedule.Name]) {
+			continue
+		}
+
+		ref := task.ScheduleRef(schedule.Name, occurrence)
+		handled = append(handled, schedule)
+		occurrences = append(occurrences, occurrence)
+		if slices.ContainsFunc(tasks, func(t task.Task) bool { return t.ExternalRefs[task.ScheduleRefSystem] == ref }) {
+			continue
+		}
+
+		opts := append(slices.Clone(schedule.Options), WithExternalRef(task.ScheduleRefSystem, ref))
+		if schedule.Due > 0 {
+			opts = append(opts, task.WithDueDate(occurrence.Add(schedule.Due)))
+		}
+		drafts = append(drafts, task.TaskDraft{Description: schedule.Description, Options: opts})
+	}
+
+	var added []task.Task
+	if len(drafts) > 0 {
+		if added, err = s.AddTasks(ctx, drafts); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, schedule := range handled {
+		state[schedule.Name] = occurrences[i]
+	}
+	return added, nil
+}
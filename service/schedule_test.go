@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_RunSchedules tests that occurrences are created once
+func TestTaskService_RunSchedules(t *testing.T) {
+	weekly, err := repository.ScheduleConfig{
+		Cron:        "0 9 * * mon",
+		Description: "Weekly report",
+		Tags:        []string{"work"},
+		Due:         task.Duration(8 * time.Hour),
+	}.Schedule()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := tasktest.NewMockRepository()
+	service := NewTaskService(repo)
+	state := task.ScheduleState{}
+
+	added, err := service.RunSchedules(t.Context(), []task.Schedule{weekly}, state, tasktest.FixedTime())
+	if err != nil {
+		t.Fatalf("RunSchedules() error = %v", err)
+	}
+	if len(added) != 1 || added[0].Description != "Weekly report" || added[0].Tags[0] != "work" {
+		t.Fatalf("added = %+v, want the weekly report", added)
+	}
+	if want := time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC); added[0].DueAt == nil || !added[0].DueAt.Equal(want) {
+		t.Errorf("DueAt = %v, want %v", added[0].DueAt, want)
+	}
+
+	t.Run("second run creates nothing", func(t *testing.T) {
+		added, err := service.RunSchedules(t.Context(), []task.Schedule{weekly}, state, tasktest.FixedTime().Add(time.Hour))
+		if err != nil || len(added) != 0 {
+			t.Errorf("RunSchedules() = %+v, %v, want nothing", added, err)
+		}
+	})
+
+	t.Run("task link prevents duplicates without state", func(t *testing.T) {
+		added, err := service.RunSchedules(t.Context(), []task.Schedule{weekly}, task.ScheduleState{}, tasktest.FixedTime())
+		if err != nil || len(added) != 0 {
+			t.Errorf("RunSchedules() = %+v, %v, want nothing", added, err)
+		}
+	})
+
+	t.Run("missed weeks create one task", func(t *testing.T) {
+		added, err := service.RunSchedules(t.Context(), []task.Schedule{weekly}, state, tasktest.FixedTime().AddDate(0, 0, 21))
+		if err != nil || len(added) != 1 {
+			t.Errorf("RunSchedules() = %+v, %v, want one task", added, err)
+		}
+		if repo.TaskCount() != 2 {
+			t.Errorf("TaskCount() = %d, want 2", repo.TaskCount())
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// SimilarTasks returns the open tasks a new task with the description would
+// likely duplicate
+func (s *TaskService) SimilarTasks(ctx context.Context, description string) ([]task.SimilarTask, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	return task.FindSimilar(tasks, description, task.SimilarThreshold), nil
+}
+
+// BumpTask raises an existing task's priority one level
+func (s *TaskService) BumpTask(ctx context.Context, id int) (*task.Task, error) {
+	var bumped task.Task
+	err := s.updateTask(ctx, id, func(task *task.Task) error {
+		task.Bump()
+		bumped = *task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &bumped, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Slack Adapter (Presentation Layer)
+//
+// Handles Slack slash commands such as "/task add Buy milk +home" posted to
+// /slack/commands. Slack signs every request with the app's signing secret;
+// unsigned or stale requests are rejected. Tasks are attributed to the
+// Slack user who ran the command.
+
+// slackMaxAge rejects replayed requests older than Slack's own limit
+const slackMaxAge = 5 * time.Minute
+
+const slackUsage = "Usage: `/task add <description>`, `/task list [status]`, " +
+	"`/task start <id>`, `/task done <id>`, `/task cancel <id> [reason]`"
+
+type slackHandler struct {
+	service *TaskService
+	secret  string
+	now     func() time.Time
+}
+
+// slackResponse is the JSON reply to a slash command. In-channel replies are
+// visible to everyone, ephemeral ones only to the user who ran the command.
+type slackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// NewSlackHandler answers Slack slash commands with the task service
+func NewSlackHandler(service *TaskService, signingSecret string) http.Handler {
+	return &slackHandler{service: service, secret: signingSecret, now: time.Now}
+}
+
+func (h *slackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeHTTPError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "slash commands are POSTed")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, task.ErrInvalidBody.Code, err.Error())
+		return
+	}
+	if !h.verify(r.Header, body) {
+		writeHTTPError(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid Slack signature")
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, task.ErrInvalidBody.Code, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	if user := form.Get("user_name"); user != "" {
+		ctx = task.WithActor(ctx, user)
+	}
+
+	reply := h.run(ctx, form.Get("text"))
+	writeJSON(w, http.StatusOK, reply)
+}
+
+// verify checks the X-Slack-Signature header, an HMAC-SHA256 of
+// "v0:<timestamp>:<body>" keyed with the signing secret
+func (h *slackHandler) verify(header http.Header, body []byte) bool {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := h.now().Sub(time.Unix(seconds, 0)); age > slackMaxAge || age < -slackMaxAge {
+		return false
+	}
+
+	want := SignSlackRequest(h.secret, timestamp, body)
+	return hmac.Equal([]byte(header.Get("X-Slack-Signature")), []byte(want))
+}
+
+// SignSlackRequest returns the X-Slack-Signature of a request
+func SignSlackRequest(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// run executes the command text and builds the reply
+func (h *slackHandler) run(ctx context.Context, text string) slackResponse {
+	command, rest, _ := strings.Cut(strings.TrimSpace(text), " ")
+	rest = strings.TrimSpace(rest)
+
+	switch command {
+	case "add":
+		draft := task.ParseTaskLine(rest)
+		task, err := h.service.AddTask(ctx, draft.Description, draft.Options...)
+		if err != nil {
+			return slackError(err)
+		}
+		return slackResponse{"in_channel", "Added " + slackTaskLine(*task)}
+
+	case "list":
+		if rest != "" && !h.service.Workflow().Has(task.TaskStatus(rest)) {
+			return slackError(task.ErrInvalidStatus)
+		}
+		tasks, err := h.service.ListTasks(ctx, rest)
+		if err != nil {
+			return slackError(err)
+		}
+		return slackResponse{"in_channel", slackTaskList(tasks, rest)}
+
+	case "start", "done", "cancel":
+		idText, reason, _ := strings.Cut(rest, " ")
+		id, err := strconv.Atoi(idText)
+		if err != nil {
+			return slackResponse{"ephemeral", "Invalid task ID. " + slackUsage}
+		}
+		switch command {
+		case "start":
+			err = h.service.MarkTaskInProgress(ctx, id)
+		case "done":
+			err = h.service.MarkTaskDone(ctx, id)
+		default:
+			err = h.service.CancelTask(ctx, id, strings.TrimSpace(reason))
+		}
+		if err != nil {
+			return slackError(err)
+		}
+		task, err := h.service.GetTask(ctx, id)
+		if err != nil {
+			return slackError(err)
+		}
+		return slackResponse{"in_channel", "Updated " + slackTaskLine(*task)}
+
+	default:
+		return slackResponse{"ephemeral", slackUsage}
+	}
+}
+
+func slackError(err error) slackResponse {
+	return slackResponse{"ephemeral", "Error: " + slackEscape(err.Error())}
+}
+
+// slackTaskList formats tasks as a bulleted message
+func slackTaskList(tasks []task.Task, status string) string {
+	title := "*Tasks*"
+	if status != "" {
+		title = "*Tasks (" + slackEscape(status) + ")*"
+	}
+	if len(tasks) == 0 {
+		return title + "\nNo tasks found"
+	}
+
+	var b bytes.Buffer
+	b.WriteString(title)
+	for _, task := range tasks {
+		b.WriteString("\n• ")
+		b.WriteString(slackTaskLine(task))
+	}
+	return b.String()
+}
+
+func slackTaskLine(task task.Task) string {
+	line := fmt.Sprintf("`#%d` %s _%s_", task.ID, slackEscape(task.Description), task.Status)
+	if task.Assignee != "" {
+		line += " @" + slackEscape(task.Assignee)
+	}
+	return line
+}
+
+// slackEscape escapes the characters Slack treats as markup
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
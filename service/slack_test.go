@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestSlackHandler tests slash commands end to end through the handler
+func TestSlackHandler(t *testing.T) {
+	const secret = "slack-secret"
+
+	send := func(t *testing.T, handler http.Handler, text, signature string) (int, slackResponse) {
+		t.Helper()
+		body := url.Values{"command": {"/task"}, "text": {text}, "user_name": {"ana"}}.Encode()
+		timestamp := strconv.FormatInt(tasktest.FixedTime().Unix(), 10)
+		if signature == "" {
+			signature = SignSlackRequest(secret, timestamp, []byte(body))
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader(body))
+		req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		req.Header.Set("X-Slack-Signature", signature)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var reply slackResponse
+		json.Unmarshal(rec.Body.Bytes(), &reply)
+		return rec.Code, reply
+	}
+
+	newHandler := func(t *testing.T) (*slackHandler, *tasktest.MockTaskRepository) {
+		repo := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+		handler := NewSlackHandler(NewTaskService(repo), secret).(*slackHandler)
+		handler.now = func() time.Time { return tasktest.FixedTime().Add(time.Minute) }
+		return handler, repo
+	}
+
+	t.Run("rejects bad signatures", func(t *testing.T) {
+		handler, _ := newHandler(t)
+		if code, _ := send(t, handler, "list", "v0=forged"); code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("rejects stale requests", func(t *testing.T) {
+		handler, _ := newHandler(t)
+		handler.now = func() time.Time { return tasktest.FixedTime().Add(time.Hour) }
+		if code, _ := send(t, handler, "list", ""); code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", code, http.StatusUnauthorized)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		text     string
+		wantType string
+		wantText string
+	}{
+		{"list", "list", "in_channel", "`#1` Buy groceries _todo_"},
+		{"list by status", "list done", "in_channel", "*Tasks (done)*\n• `#3` Call mom _done_"},
+		{"invalid status", "list later", "ephemeral", "Error: Invalid task status"},
+		{"add", "add (A) Fix <login> +web", "in_channel", "Added `#4` Fix &lt;login&gt; _todo_"},
+		{"done", "done 2", "in_channel", "Updated `#2` Write report _done_"},
+		{"missing task", "start 42", "ephemeral", "Error: Task not found"},
+		{"help", "", "ephemeral", "Usage:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _ := newHandler(t)
+			code, reply := send(t, handler, tt.text, "")
+			if code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", code, http.StatusOK)
+			}
+			if reply.ResponseType != tt.wantType || !strings.Contains(reply.Text, tt.wantText) {
+				t.Errorf("reply = %+v, want %s containing %q", reply, tt.wantType, tt.wantText)
+			}
+		})
+	}
+
+	t.Run("attributes changes to the Slack user", func(t *testing.T) {
+		handler, repo := newHandler(t)
+		send(t, handler, "add Plan offsite", "")
+		task, ok := repo.GetTask(4)
+		if !ok || task.CreatedBy != "ana" {
+			t.Errorf("CreatedBy = %q, want %q", task.CreatedBy, "ana")
+		}
+	})
+}
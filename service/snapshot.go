@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithSnapshots stores snapshots in repo; without it snapshot commands fail
+func (s *TaskService) WithSnapshots(repo repository.SnapshotRepository) *TaskService {
+	s.snapshots = repo
+	return s
+}
+
+// CreateSnapshot saves the current tasks under name, or under the time
+// when name is empty
+func (s *TaskService) CreateSnapshot(ctx context.Context, name string, now time.Time) (task.Snapshot, error) {
+	if err := s.writable(); err != nil {
+		return task.Snapshot{}, err
+	}
+	if s.snapshots == nil {
+		return task.Snapshot{}, task.ErrNoSnapshots
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = now.Format(task.SnapshotNameFormat)
+	}
+	if name == task.CurrentState || strings.ContainsAny(name, " \t\n/\\") || strings.HasPrefix(name, ".") {
+		return task.Snapshot{}, task.ErrInvalidSnapshot
+	}
+	names, err := s.snapshots.SnapshotNames(ctx)
+	if err != nil {
+		return task.Snapshot{}, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if slices.Contains(names, name) {
+		return task.Snapshot{}, task.ErrSnapshotExists.Withf("%s", name)
+	}
+
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return task.Snapshot{}, repository.StorageError("failed to load tasks", err)
+	}
+	snapshot := task.Snapshot{Name: name, CreatedAt: now, Tasks: tasks}
+	if err := s.snapshots.SaveSnapshot(ctx, snapshot); err != nil {
+		return task.Snapshot{}, repository.StorageError("failed to save snapshot", err)
+	}
+	return snapshot, nil
+}
+
+// Snapshots returns the stored snapshots, oldest first
+func (s *TaskService) Snapshots(ctx context.Context) ([]task.Snapshot, error) {
+	if s.snapshots == nil {
+		return nil, task.ErrNoSnapshots
+	}
+	names, err := s.snapshots.SnapshotNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	snapshots := make([]task.Snapshot, 0, len(names))
+	for _, name := range names {
+		snapshot, err := s.snapshots.LoadSnapshot(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// snapshotTasks returns the tasks of a snapshot, or the current tasks
+func (s *TaskService) snapshotTasks(ctx context.Context, name string) ([]task.Task, error) {
+	if name == task.CurrentState {
+		tasks, err := s.store.List(ctx, repository.TaskFilter{})
+		if err != nil {
+			return nil, repository.StorageError("failed to load tasks", err)
+		}
+		return tasks, nil
+	}
+	if s.snapshots == nil {
+		return nil, task.ErrNoSnapshots
+	}
+	snapshot, err := s.snapshots.LoadSnapshot(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Tasks, nil
+}
+
+// DiffSnapshots compares two snapshots; to may be CurrentState
+func (s *TaskService) DiffSnapshots(ctx context.Context, from, to string) (task.SnapshotDiff, error) {
+	before, err := s.snapshotTasks(ctx, from)
+	if err != nil {
+		return task.SnapshotDiff{}, err
+	}
+	after, err := s.snapshotTasks(ctx, to)
+	if err != nil {
+		return task.SnapshotDiff{}, err
+	}
+
+	diff := task.SnapshotDiff{From: from, To: to, Changes: []task.SnapshotChange{}}
+	for _, change := range task.DiffTasks(before, after) {
+		fields := change.Fields()
+		// Revisions and authors alone are bookkeeping, not a change to show
+		if change.Type == task.ChangeUpdated && len(fields) == 0 {
+			continue
+		}
+		diff.Changes = append(diff.Changes, task.SnapshotChange{
+			Type:        change.Type,
+			ID:          change.Task.ID,
+			Description: change.Task.Description,
+			Fields:      fields,
+		})
+	}
+	return diff, nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+func TestTaskService_CreateSnapshot(t *testing.T) {
+	service := NewTaskService(tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t)))
+	ctx := context.Background()
+
+	if _, err := service.CreateSnapshot(ctx, "", tasktest.FixedTime()); !errors.Is(err, task.ErrNoSnapshots) {
+		t.Errorf("without storage err = %v", err)
+	}
+
+	service.WithSnapshots(repository.NewFileSnapshotRepository(filepath.Join(t.TempDir(), "snapshots")))
+	snapshot, err := service.CreateSnapshot(ctx, "", tasktest.FixedTime())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Name != tasktest.FixedTime().Format(task.SnapshotNameFormat) || len(snapshot.Tasks) != 3 {
+		t.Errorf("snapshot = %s with %d tasks", snapshot.Name, len(snapshot.Tasks))
+	}
+
+	for _, name := range []string{"a b", "../escape", task.CurrentState} {
+		if _, err := service.CreateSnapshot(ctx, name, tasktest.FixedTime()); !errors.Is(err, task.ErrInvalidSnapshot) {
+			t.Errorf("CreateSnapshot(%q) err = %v, want %v", name, err, task.ErrInvalidSnapshot)
+		}
+	}
+}
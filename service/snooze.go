@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// SnoozeTask hides a task until the given time, or wakes it (nil)
+func (s *TaskService) SnoozeTask(ctx context.Context, id int, until *time.Time) error {
+	return s.updateTask(ctx, id, func(task *task.Task) error {
+		task.Snooze(until)
+		return nil
+	})
+}
+
+// WakeTasks clears the start dates of open tasks that have come at now and
+// returns the tasks, so each one is announced once, as FireReminders does
+// for reminders
+func (s *TaskService) WakeTasks(ctx context.Context, now time.Time) ([]task.Task, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	before := slices.Clone(tasks)
+
+	var woken []task.Task
+	for i, task := range tasks {
+		if !task.IsOpen() || task.StartAt == nil || task.StartAt.After(now) {
+			continue
+		}
+		tasks[i].Snooze(nil)
+		woken = append(woken, tasks[i])
+	}
+	if len(woken) == 0 {
+		return nil, nil
+	}
+
+	if err := s.save(ctx, before, tasks); err != nil {
+		return nil, repository.StorageError("failed to save tasks", err)
+	}
+	return woken, nil
+}
+
+// NextWake returns when the earliest snoozed open task wakes, or nil when
+// none is snoozed
+func (s *TaskService) NextWake(ctx context.Context) (*time.Time, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+
+	var next *time.Time
+	for _, task := range tasks {
+		if task.IsOpen() && task.StartAt != nil && (next == nil || task.StartAt.Before(*next)) {
+			next = task.StartAt
+		}
+	}
+	return next, nil
+}
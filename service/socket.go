@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Socket Protocol
+//
+// daemon --socket listens on a Unix socket so that status bars and editors
+// can run commands without starting a process and reading the store each
+// time. A request is one JSON line holding the arguments of a command as
+// typed after task-cli, and is answered with one JSON line holding the exit
+// code and what the command printed:
+//
+//	{"args":["summary"]}
+//	{"code":0,"stdout":"12 todo, 3 in-progress, 45 done, 2 overdue\n"}
+//
+// A connection may send any number of requests, one after the other.
+
+// SocketRequest is a command sent to the daemon
+type SocketRequest struct {
+	Args []string `json:"args"`
+}
+
+// SocketResponse is the outcome of a command run by the daemon
+type SocketResponse struct {
+	Code   int    `json:"code"`
+	Stdout string `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"`
+}
+
+// SocketHandler runs the command of one request
+type SocketHandler func(ctx context.Context, args []string) SocketResponse
+
+// ErrDaemonUnreachable is returned when no daemon answers on the socket, so
+// the command was not sent
+var ErrDaemonUnreachable = errors.New("daemon not reachable")
+
+// socketDialTimeout bounds how long a client waits for the daemon to accept
+const socketDialTimeout = 2 * time.Second
+
+// ListenSocket listens on the socket file at path. A file left behind by a
+// daemon that did not exit cleanly is replaced, while one a daemon still
+// answers on is an error.
+func ListenSocket(path string) (net.Listener, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, socketDialTimeout); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("a daemon is already listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	// Only the user running the daemon may talk to it
+	if err := os.Chmod(path, 0o600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	return listener, nil
+}
+
+// ServeSocket answers requests on listener until ctx is done, then closes
+// it. Connections are served concurrently; the handler is called for one
+// request at a time.
+func ServeSocket(ctx context.Context, listener net.Listener, handle SocketHandler) error {
+	var mu sync.Mutex
+	var conns sync.WaitGroup
+	defer conns.Wait()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			defer conn.Close()
+			// Unblock reads once the daemon stops
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			defer stop()
+
+			scanner := bufio.NewScanner(conn)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			encoder := json.NewEncoder(conn)
+			for scanner.Scan() {
+				var request SocketRequest
+				var response SocketResponse
+				if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+					response = SocketResponse{Code: 1, Stderr: fmt.Sprintf("Error: invalid request: %s\n", err.Error())}
+				} else {
+					mu.Lock()
+					response = handle(ctx, request.Args)
+					mu.Unlock()
+				}
+				if err := encoder.Encode(response); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// CallSocket sends one command to the daemon listening at path and returns
+// its outcome
+func CallSocket(ctx context.Context, path string, args []string) (SocketResponse, error) {
+	dialer := net.Dialer{Timeout: socketDialTimeout}
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return SocketResponse{}, fmt.Errorf("%w at %s, start it with task-cli daemon --socket: %w", ErrDaemonUnreachable, path, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if err := json.NewEncoder(conn).Encode(SocketRequest{Args: args}); err != nil {
+		return SocketResponse{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	var response SocketResponse
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return SocketResponse{}, ctx.Err()
+		}
+		return SocketResponse{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	return response, nil
+}
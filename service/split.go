@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// SplitOptions says where split copies tasks and what happens to them
+type SplitOptions struct {
+	Target repository.TaskRepository
+	// Move deletes the tasks from this store once the target is saved
+	Move bool
+	// Renumber gives the tasks the next free IDs of the target instead of
+	// keeping theirs
+	Renumber bool
+	DryRun   bool
+}
+
+// SplitReport lists the tasks split into the target, with the IDs they have
+// there
+type SplitReport struct {
+	Tasks []task.Task
+	// Renumbered maps the IDs tasks were given in the target to the IDs
+	// they had here
+	Renumbered map[int]int
+}
+
+// Split copies tasks into another store, or moves them with opts.Move.
+// Tasks keep their UUID so they can be recognized again wherever they go.
+// The target is saved before anything is deleted here, so a failure leaves
+// the tasks in both stores rather than in neither.
+func (s *TaskService) Split(ctx context.Context, tasks []task.Task, opts SplitOptions) (*SplitReport, error) {
+	if opts.Move && !opts.DryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	existing, err := opts.Target.Load(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to load target tasks", err)
+	}
+	taken := make(map[int]bool, len(existing))
+	for _, task := range existing {
+		taken[task.ID] = true
+	}
+	nextID, err := opts.Target.GetNextID(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to get next ID", err)
+	}
+
+	report := &SplitReport{Renumbered: make(map[int]int)}
+	for _, t := range tasks {
+		t.UUID = task.TaskUUID(t)
+		if opts.Renumber {
+			for taken[nextID] {
+				nextID++
+			}
+			report.Renumbered[nextID] = t.ID
+			t.ID = nextID
+		} else if taken[t.ID] {
+			return nil, task.ErrIDTaken.Withf("%d", t.ID).WithHint("split with --renumber")
+		}
+		taken[t.ID] = true
+		report.Tasks = append(report.Tasks, t)
+	}
+
+	if opts.DryRun || len(tasks) == 0 {
+		return report, nil
+	}
+	if err := opts.Target.Save(ctx, append(existing, report.Tasks...)); err != nil {
+		return nil, repository.StorageError("failed to save target tasks", err)
+	}
+	if opts.Move {
+		if err := s.save(ctx, tasks, nil); err != nil {
+			return nil, fmt.Errorf("copied to the target but failed to delete here: %w", err)
+		}
+	}
+	return report, nil
+}
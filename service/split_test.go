@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+func taggedTasks(t *testing.T) []task.Task {
+	t.Helper()
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].Tags = []string{"home"}
+	tasks[2].Tags = []string{"home"}
+	return tasks
+}
+
+func TestTaskService_Split_DryRun(t *testing.T) {
+	repo := tasktest.NewMockRepository().WithTasks(taggedTasks(t))
+	target := tasktest.NewMockRepository()
+	service := NewTaskService(repo)
+	tasks := repo.GetStoredTasks()[:1]
+
+	report, err := service.Split(context.Background(), tasks, SplitOptions{Target: target, Move: true, DryRun: true})
+	if err != nil || len(report.Tasks) != 1 {
+		t.Fatalf("report = %+v, %v", report, err)
+	}
+	if target.SaveCallCount() != 0 || repo.TaskCount() != 3 {
+		t.Error("dry run wrote tasks")
+	}
+
+	target.WithTasks(tasks)
+	_, err = service.Split(context.Background(), tasks, SplitOptions{Target: target})
+	if !errors.Is(err, task.ErrIDTaken) {
+		t.Errorf("err = %v, want %s", err, task.ErrIDTaken.Code)
+	}
+}
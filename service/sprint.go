@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithSprints stores sprints in repo; without it sprint commands fail
+func (s *TaskService) WithSprints(repo repository.SprintRepository) *TaskService {
+	s.sprints = repo
+	return s
+}
+
+func (s *TaskService) loadSprints(ctx context.Context) ([]task.Sprint, error) {
+	if s.sprints == nil {
+		return nil, task.ErrNoSprints
+	}
+	sprints, err := s.sprints.LoadSprints(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to load sprints", err)
+	}
+	return sprints, nil
+}
+
+func (s *TaskService) saveSprints(ctx context.Context, sprints []task.Sprint) error {
+	if err := s.sprints.SaveSprints(ctx, sprints); err != nil {
+		return repository.StorageError("failed to save sprints", err)
+	}
+	return nil
+}
+
+// CreateSprint plans a sprint from start to end with an optional capacity
+func (s *TaskService) CreateSprint(ctx context.Context, sprint task.Sprint) (*task.Sprint, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	sprint, err := task.ValidateSprint(sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	sprints, err := s.loadSprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(sprints, func(other task.Sprint) bool { return other.Name == sprint.Name }) {
+		return nil, task.ErrSprintExists
+	}
+
+	sprint.CreatedAt, sprint.ClosedAt, sprint.CarriedOver = time.Now(), nil, nil
+	if err := s.saveSprints(ctx, append(sprints, sprint)); err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+// Sprints returns every sprint, earliest start first
+func (s *TaskService) Sprints(ctx context.Context) ([]task.Sprint, error) {
+	sprints, err := s.loadSprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(sprints, func(a, b task.Sprint) int { return a.Start.Compare(b.Start) })
+	return sprints, nil
+}
+
+// SprintStatus reports the named sprint, or the active one when name is
+// empty
+func (s *TaskService) SprintStatus(ctx context.Context, name string, now time.Time) (*task.SprintStatus, error) {
+	sprints, err := s.loadSprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i, err := task.FindSprint(sprints, name, now)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	status := task.BuildSprintStatus(sprints[i], tasks)
+	return &status, nil
+}
+
+// SetTaskSprint commits tasks to the named sprint, the active one when
+// name is empty, and returns the sprint's status afterwards so callers can
+// warn about its capacity. Closed sprints accept no tasks.
+func (s *TaskService) SetTaskSprint(ctx context.Context, ids []int, name string, now time.Time) (*task.SprintStatus, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	sprints, err := s.loadSprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i, err := task.FindSprint(sprints, name, now)
+	if err != nil {
+		return nil, err
+	}
+	if sprints[i].IsClosed() {
+		return nil, task.ErrSprintClosed
+	}
+
+	if err := s.setSprint(ctx, ids, sprints[i].Name); err != nil {
+		return nil, err
+	}
+	return s.SprintStatus(ctx, sprints[i].Name, now)
+}
+
+// RemoveFromSprint takes tasks out of whichever sprint they are in
+func (s *TaskService) RemoveFromSprint(ctx context.Context, ids []int) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	return s.setSprint(ctx, ids, "")
+}
+
+// setSprint moves every task to the sprint in one save, none when one of
+// them does not exist
+func (s *TaskService) setSprint(ctx context.Context, ids []int, name string) error {
+	before, err := s.getTasks(ctx, ids)
+	if err != nil {
+		return err
+	}
+	after := slices.Clone(before)
+	for i := range after {
+		after[i].SetSprint(name)
+	}
+	return s.save(ctx, before, after)
+}
+
+// CloseSprint closes the named sprint, the active one when name is empty,
+// and returns its final status; its unfinished tasks are the carry-over.
+func (s *TaskService) CloseSprint(ctx context.Context, name string, now time.Time) (*task.SprintStatus, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	sprints, err := s.loadSprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i, err := task.FindSprint(sprints, name, now)
+	if err != nil {
+		return nil, err
+	}
+	if sprints[i].IsClosed() {
+		return nil, task.ErrSprintClosed
+	}
+
+	sprints[i].ClosedAt = &now
+	if err := s.saveSprints(ctx, sprints); err != nil {
+		return nil, err
+	}
+	return s.SprintStatus(ctx, sprints[i].Name, now)
+}
+
+// Rollover closes the current sprint, the active one or else the last open
+// sprint that has ended, and moves its unfinished tasks to the next open
+// sprint, which records them as carried over. It returns both sprints and
+// the tasks moved.
+func (s *TaskService) Rollover(ctx context.Context, now time.Time) (from, to task.Sprint, moved []task.Task, err error) {
+	if err := s.writable(); err != nil {
+		return task.Sprint{}, task.Sprint{}, nil, err
+	}
+	sprints, err := s.Sprints(ctx)
+	if err != nil {
+		return task.Sprint{}, task.Sprint{}, nil, err
+	}
+
+	current := -1
+	for i, sprint := range sprints {
+		if !sprint.IsClosed() && !now.Before(sprint.Start) {
+			current = i
+		}
+	}
+	if current < 0 {
+		return task.Sprint{}, task.Sprint{}, nil, task.ErrNoActiveSprint
+	}
+	next := slices.IndexFunc(sprints[current+1:], func(sprint task.Sprint) bool { return !sprint.IsClosed() })
+	if next < 0 {
+		return task.Sprint{}, task.Sprint{}, nil, task.ErrNoNextSprint
+	}
+	next += current + 1
+
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return task.Sprint{}, task.Sprint{}, nil, repository.StorageError("failed to load tasks", err)
+	}
+	unfinished := task.BuildSprintStatus(sprints[current], tasks).Unfinished
+	if len(unfinished) > 0 {
+		after := slices.Clone(unfinished)
+		for i := range after {
+			after[i].SetSprint(sprints[next].Name)
+			sprints[next].CarriedOver = append(sprints[next].CarriedOver, after[i].ID)
+		}
+		if err := s.save(ctx, unfinished, after); err != nil {
+			return task.Sprint{}, task.Sprint{}, nil, repository.StorageError("failed to save tasks", err)
+		}
+		moved = after
+	}
+
+	sprints[current].ClosedAt = &now
+	if err := s.saveSprints(ctx, sprints); err != nil {
+		return task.Sprint{}, task.Sprint{}, nil, err
+	}
+	return sprints[current], sprints[next], moved, nil
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/repository"
+)
+
+// CheckStorage tests the store the service works on
+func (s *TaskService) CheckStorage(ctx context.Context) (repository.StorageCheck, error) {
+	return repository.CheckStorage(ctx, s.repo)
+}
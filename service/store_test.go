@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_Store tests that single-task changes write only that task
+func TestTaskService_Store(t *testing.T) {
+	ctx := context.Background()
+	mock := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	service := NewTaskService(mock)
+
+	if err := service.UpdateTask(ctx, 1, "Buy bread"); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	if err := service.DeleteTask(ctx, 3); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+	if _, err := service.AddTask(ctx, "Plan trip"); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+
+	stored := mock.GetStoredTasks()
+	if len(stored) != 3 || stored[0].Description != "Buy bread" || stored[1].ID != 2 ||
+		stored[2].Description != "Plan trip" {
+		t.Errorf("stored tasks = %+v, want Buy bread, Write report and Plan trip in order", stored)
+	}
+	if err := service.DeleteTask(ctx, 42); !errors.Is(err, task.ErrTaskNotFound) {
+		t.Errorf("DeleteTask(42) error = %v, want %v", err, task.ErrTaskNotFound)
+	}
+}
+
+// TestCachingRepository_Conflict tests two commands sharing a file: changes
+// to different tasks are merged, changes to the same task conflict
+func TestCachingRepository_Conflict(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "tasks.json")
+	if err := repository.NewFileTaskRepository(filename).Save(ctx, tasktest.FixedTasks(t)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	first := repository.NewCachingRepository(repository.NewFileTaskRepository(filename))
+	second := repository.NewCachingRepository(repository.NewFileTaskRepository(filename))
+	firstService, secondService := NewTaskService(first), NewTaskService(second)
+
+	first.Begin()
+	second.Begin()
+	firstService.UpdateTask(ctx, 1, "Buy bread")
+	secondService.UpdateTask(ctx, 2, "Write the report")
+	if err := first.Commit(ctx); err != nil {
+		t.Fatalf("first Commit: %v", err)
+	}
+	if err := second.Commit(ctx); err != nil {
+		t.Fatalf("second Commit: %v", err)
+	}
+	tasks, _ := repository.NewFileTaskRepository(filename).Load(ctx)
+	if tasks[0].Description != "Buy bread" || tasks[1].Description != "Write the report" {
+		t.Errorf("stored tasks = %+v, want both updates", tasks)
+	}
+
+	first.Begin()
+	second.Begin()
+	firstService.UpdateTask(ctx, 3, "Call dad")
+	secondService.DeleteTask(ctx, 3)
+	if err := first.Commit(ctx); err != nil {
+		t.Fatalf("first Commit: %v", err)
+	}
+	if err := second.Commit(ctx); !errors.Is(err, task.ErrConflict) {
+		t.Errorf("conflicting Commit error = %v, want %s", err, task.ErrConflict.Code)
+	}
+	if task, _ := repository.StoreFor(repository.NewFileTaskRepository(filename)).Get(ctx, 3); task.Description != "Call dad" {
+		t.Errorf("task 3 = %+v, want the first update kept", task)
+	}
+}
+
+// TestTaskStore_Conflict tests that stores refuse changes made to an older
+// revision
+func TestTaskStore_Conflict(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	stores := map[string]repository.TaskRepository{
+		"file":   repository.NewFileTaskRepository(filepath.Join(dir, "tasks.json")),
+		"bolt":   repository.NewBoltTaskRepository(filepath.Join(dir, "tasks.db")),
+		"events": repository.NewEventLogRepository(filepath.Join(dir, "tasks.jsonl")),
+	}
+	for name, repo := range stores {
+		t.Run(name, func(t *testing.T) {
+			if err := repo.Save(ctx, tasktest.FixedTasks(t)); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			service := NewTaskService(repo)
+			stale, _ := service.GetTask(ctx, 1)
+			if err := service.UpdateTask(ctx, 1, "Buy bread"); err != nil {
+				t.Fatalf("UpdateTask: %v", err)
+			}
+
+			changed := *stale
+			changed.UpdateDescription("Buy milk", task.DescriptionRules{})
+			err := service.save(ctx, []task.Task{*stale}, []task.Task{changed})
+			if !errors.Is(err, task.ErrConflict) {
+				t.Errorf("save of a stale task error = %v, want %s", err, task.ErrConflict.Code)
+			}
+			if task, _ := service.GetTask(ctx, 1); task.Description != "Buy bread" || task.Revision != stale.Revision+1 {
+				t.Errorf("task 1 = %+v, want the first update at the next revision", task)
+			}
+		})
+	}
+}
+
+// TestBumpRevisions tests that every saved change raises the revision
+func TestBumpRevisions(t *testing.T) {
+	ctx := context.Background()
+	mock := tasktest.NewMockRepository().WithTasks(tasktest.FixedTasks(t))
+	service := NewTaskService(mock)
+
+	tasks := tasktest.FixedTasks(t)
+	tasks[1].Description = "Write the report" // changed without touch
+	revision := tasks[1].Revision
+	if err := service.ReplaceTasks(ctx, tasks); err != nil {
+		t.Fatalf("ReplaceTasks: %v", err)
+	}
+	stored := mock.GetStoredTasks()
+	if stored[1].Revision != revision+1 || stored[0].Revision != tasktest.FixedTasks(t)[0].Revision {
+		t.Errorf("revisions = %d and %d, want only the changed task bumped", stored[0].Revision, stored[1].Revision)
+	}
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// CountTasks counts the tasks selected by match, nil counting all of them.
+// Tasks are counted as the store reads them rather than collected, so
+// stores that stream keep memory flat however many tasks they hold.
+func (s *TaskService) CountTasks(ctx context.Context, now time.Time, match func(task.Task) bool) (task.TaskCounts, error) {
+	var counts task.TaskCounts
+	_, err := s.store.List(ctx, repository.TaskFilter{Match: func(task task.Task) bool {
+		if match == nil || match(task) {
+			counts.Add(task, now)
+		}
+		return false
+	}})
+	if err != nil {
+		return task.TaskCounts{}, repository.StorageError("failed to load tasks", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Resolution picks the side that wins a sync conflict
+type Resolution string
+
+const (
+	ResolveLocal  Resolution = "local"
+	ResolveRemote Resolution = "remote"
+	// ResolveNewer keeps the side updated last, then the higher revision
+	ResolveNewer Resolution = "newer"
+)
+
+// SyncConflict is a task changed differently on both sides since the last
+// sync. A nil side means the task was deleted there.
+type SyncConflict struct {
+	ID     int
+	Base   *task.Task
+	Local  *task.Task
+	Remote *task.Task
+	// Fields lists the fields both sides changed to different values
+	Fields []string
+}
+
+// Describe summarizes the conflict for display
+func (c SyncConflict) Describe() string {
+	switch {
+	case c.Local == nil:
+		return fmt.Sprintf("task %d was deleted locally but changed remotely", c.ID)
+	case c.Remote == nil:
+		return fmt.Sprintf("task %d was changed locally but deleted remotely", c.ID)
+	default:
+		return fmt.Sprintf("task %d changed on both sides (%s)", c.ID, strings.Join(c.Fields, ", "))
+	}
+}
+
+// MergeResult is the outcome of a three-way merge
+type MergeResult struct {
+	Tasks     []task.Task
+	Conflicts []SyncConflict
+	// Renumbered maps local IDs that collided with unrelated remote tasks
+	// to their new IDs
+	Renumbered map[int]int
+}
+
+// MergeTasks merges local and remote changes made since base. Conflicts are
+// passed to resolve; those it cannot decide are left out of the result
+// tasks and reported instead, keeping the local version.
+func MergeTasks(base, local, remote []task.Task, resolve func(SyncConflict) (Resolution, bool)) MergeResult {
+	byID := func(tasks []task.Task) map[int]*task.Task {
+		m := make(map[int]*task.Task, len(tasks))
+		for i := range tasks {
+			m[tasks[i].ID] = &tasks[i]
+		}
+		return m
+	}
+	baseByID, localByID, remoteByID := byID(base), byID(local), byID(remote)
+
+	ids := make(map[int]bool)
+	maxID := 0
+	for _, tasks := range [][]task.Task{base, local, remote} {
+		for _, task := range tasks {
+			ids[task.ID] = true
+			maxID = max(maxID, task.ID)
+		}
+	}
+	sorted := make([]int, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	slices.Sort(sorted)
+
+	result := MergeResult{Renumbered: make(map[int]int)}
+	for _, id := range sorted {
+		b, l, r := baseByID[id], localByID[id], remoteByID[id]
+
+		// Both sides created a task with this ID independently
+		if b == nil && l != nil && r != nil && !sameIdentity(*l, *r) {
+			maxID++
+			renumbered := *l
+			renumbered.ID = maxID
+			result.Renumbered[id] = maxID
+			result.Tasks = append(result.Tasks, *r, renumbered)
+			continue
+		}
+
+		merged, conflict := mergeTask(b, l, r)
+		if conflict != nil {
+			conflict.ID = id
+			merged = nil
+			resolution, ok := Resolution(""), false
+			if resolve != nil {
+				resolution, ok = resolve(*conflict)
+			}
+			if !ok {
+				result.Conflicts = append(result.Conflicts, *conflict)
+				if l != nil {
+					result.Tasks = append(result.Tasks, *l)
+				}
+				continue
+			}
+			merged = pickSide(l, r, resolution)
+		}
+		if merged != nil {
+			result.Tasks = append(result.Tasks, *merged)
+		}
+	}
+
+	slices.SortFunc(result.Tasks, func(a, b task.Task) int { return a.ID - b.ID })
+	return result
+}
+
+// sameIdentity reports whether two tasks with the same ID are versions of
+// one task, by UUID when both have one and else by creation time
+func sameIdentity(a, b task.Task) bool {
+	if a.UUID != "" && b.UUID != "" {
+		return a.UUID == b.UUID
+	}
+	return a.CreatedAt.Equal(b.CreatedAt)
+}
+
+// mergeTask merges one task, returning nil for a deletion
+func mergeTask(b, l, r *task.Task) (*task.Task, *SyncConflict) {
+	changed := func(x *task.Task) bool {
+		switch {
+		case b == nil || x == nil:
+			return b != x
+		default:
+			return !task.SameTask(*b, *x)
+		}
+	}
+	localChanged, remoteChanged := changed(l), changed(r)
+
+	switch {
+	case !localChanged:
+		return r, nil
+	case !remoteChanged:
+		return l, nil
+	case l == nil && r == nil:
+		return nil, nil
+	case l == nil || r == nil:
+		return nil, &SyncConflict{Base: b, Local: l, Remote: r}
+	case task.SameTask(*l, *r):
+		return l, nil
+	}
+
+	// Both sides edited the task: combine fields changed on one side only
+	base := b
+	if base == nil {
+		base = &task.Task{}
+	}
+	merged := *l
+	var fields []string
+	mergeField := func(name string, baseV, localV, remoteV string, takeRemote func()) {
+		switch {
+		case localV == remoteV, remoteV == baseV:
+		case localV == baseV:
+			takeRemote()
+		default:
+			fields = append(fields, name)
+		}
+	}
+	mergeField("description", base.Description, l.Description, r.Description,
+		func() { merged.Description = r.Description })
+	mergeField("status", string(base.Status), string(l.Status), string(r.Status),
+		func() { merged.Status, merged.StatusChangedAt = r.Status, r.StatusChangedAt })
+	mergeField("dueAt", task.FormatDue(base.DueAt), task.FormatDue(l.DueAt), task.FormatDue(r.DueAt),
+		func() { merged.DueAt = r.DueAt })
+	mergeField("remindAt", task.FormatDue(base.RemindAt), task.FormatDue(l.RemindAt), task.FormatDue(r.RemindAt),
+		func() { merged.RemindAt = r.RemindAt })
+	mergeField("startAt", task.FormatDue(base.StartAt), task.FormatDue(l.StartAt), task.FormatDue(r.StartAt),
+		func() { merged.StartAt = r.StartAt })
+	mergeField("priority", string(base.Priority), string(l.Priority), string(r.Priority),
+		func() { merged.Priority = r.Priority })
+	mergeField("tags", strings.Join(base.Tags, ","), strings.Join(l.Tags, ","), strings.Join(r.Tags, ","),
+		func() { merged.Tags = r.Tags })
+	mergeField("checklist", task.JoinChecklist(base.Checklist), task.JoinChecklist(l.Checklist), task.JoinChecklist(r.Checklist),
+		func() { merged.Checklist = r.Checklist })
+	mergeField("attachments", task.JoinAttachments(base.Attachments), task.JoinAttachments(l.Attachments), task.JoinAttachments(r.Attachments),
+		func() { merged.Attachments = r.Attachments })
+	mergeField("relations", task.JoinRelations(base.Relations), task.JoinRelations(l.Relations), task.JoinRelations(r.Relations),
+		func() { merged.Relations = r.Relations })
+	mergeField("list", base.List, l.List, r.List,
+		func() { merged.List = r.List })
+	mergeField("project", base.Project, l.Project, r.Project,
+		func() { merged.Project = r.Project })
+	mergeField("milestone", base.Milestone, l.Milestone, r.Milestone,
+		func() { merged.Milestone = r.Milestone })
+	mergeField("sprint", base.Sprint, l.Sprint, r.Sprint,
+		func() { merged.Sprint = r.Sprint })
+	mergeField("assignee", base.Assignee, l.Assignee, r.Assignee,
+		func() { merged.Assignee = r.Assignee })
+	mergeField("estimate", task.FormatEstimate(base.Estimate), task.FormatEstimate(l.Estimate), task.FormatEstimate(r.Estimate),
+		func() { merged.Estimate = r.Estimate })
+	systems := make(map[string]bool)
+	for _, refs := range []map[string]string{base.ExternalRefs, l.ExternalRefs, r.ExternalRefs} {
+		for system := range refs {
+			systems[system] = true
+		}
+	}
+	for _, system := range slices.Sorted(maps.Keys(systems)) {
+		mergeField("externalRefs."+system, base.ExternalRefs[system], l.ExternalRefs[system], r.ExternalRefs[system],
+			func() { merged.ExternalRefs = setExternalRef(merged.ExternalRefs, system, r.ExternalRefs[system]) })
+	}
+	merged.Notes = mergeNotes(base.Notes, l.Notes, r.Notes)
+	// Pomodoros only add up, so both sides' intervals count
+	merged.Pomodoros = max(0, l.Pomodoros+r.Pomodoros-base.Pomodoros)
+
+	if len(fields) > 0 {
+		return nil, &SyncConflict{Base: b, Local: l, Remote: r, Fields: fields}
+	}
+
+	if r.UpdatedAt.After(merged.UpdatedAt) {
+		merged.UpdatedAt = r.UpdatedAt
+	}
+	merged.Revision = max(l.Revision, r.Revision) + 1
+	return &merged, nil
+}
+
+// setExternalRef returns refs with the ref of system set, or removed when
+// ref is empty, without changing refs itself, which the local task shares
+func setExternalRef(refs map[string]string, system, ref string) map[string]string {
+	updated := maps.Clone(refs)
+	if ref == "" {
+		delete(updated, system)
+	} else {
+		if updated == nil {
+			updated = make(map[string]string)
+		}
+		updated[system] = ref
+	}
+	if len(updated) == 0 {
+		return nil
+	}
+	return updated
+}
+
+// mergeNotes merges notes as a set: notes added on either side since base
+// are kept and notes removed on either side are dropped, oldest first
+func mergeNotes(base, local, remote []task.Note) []task.Note {
+	key := func(note task.Note) string {
+		return note.CreatedAt.UTC().Format(time.RFC3339Nano) + "\x00" + note.Text
+	}
+	keys := func(notes []task.Note) map[string]bool {
+		m := make(map[string]bool, len(notes))
+		for _, note := range notes {
+			m[key(note)] = true
+		}
+		return m
+	}
+	inBase, inLocal, inRemote := keys(base), keys(local), keys(remote)
+
+	var merged []task.Note
+	for _, note := range local {
+		if !inBase[key(note)] || inRemote[key(note)] {
+			merged = append(merged, note)
+		}
+	}
+	for _, note := range remote {
+		if !inBase[key(note)] && !inLocal[key(note)] {
+			merged = append(merged, note)
+		}
+	}
+	slices.SortStableFunc(merged, func(a, b task.Note) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return merged
+}
+
+// pickSide applies a resolution to a conflict
+func pickSide(l, r *task.Task, resolution Resolution) *task.Task {
+	switch resolution {
+	case ResolveLocal:
+		return l
+	case ResolveRemote:
+		return r
+	}
+
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.UpdatedAt.After(r.UpdatedAt):
+		return l
+	case r.UpdatedAt.After(l.UpdatedAt):
+		return r
+	case l.Revision > r.Revision:
+		return l
+	default:
+		return r
+	}
+}
+
+// SyncOptions configures a sync with a remote store
+type SyncOptions struct {
+	// Remote is the store to merge with
+	Remote repository.TaskRepository
+	// Base holds the tasks as of the last successful sync
+	Base repository.TaskRepository
+	// Resolve decides conflicts; unresolved conflicts abort the sync
+	Resolve func(SyncConflict) (Resolution, bool)
+	// DryRun merges without writing anything
+	DryRun bool
+}
+
+// SyncReport describes what a sync changed
+type SyncReport struct {
+	LocalChanges  []task.TaskChange
+	RemoteChanges []task.TaskChange
+	Conflicts     []SyncConflict
+	Renumbered    map[int]int
+	Saved         bool
+}
+
+// Sync merges the local store with a remote one and writes the result to
+// both. Nothing is written while conflicts remain unresolved.
+func (s *TaskService) Sync(ctx context.Context, opts SyncOptions) (*SyncReport, error) {
+	if !opts.DryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	base, err := opts.Base.Load(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to load sync state", err)
+	}
+	local, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	remote, err := opts.Remote.Load(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to load remote tasks", err)
+	}
+
+	merged := MergeTasks(base, local, remote, opts.Resolve)
+	report := &SyncReport{
+		LocalChanges:  task.DiffTasks(local, merged.Tasks),
+		RemoteChanges: task.DiffTasks(remote, merged.Tasks),
+		Conflicts:     merged.Conflicts,
+		Renumbered:    merged.Renumbered,
+	}
+	if len(report.Conflicts) > 0 || opts.DryRun {
+		return report, nil
+	}
+
+	if len(report.RemoteChanges) > 0 {
+		if err := opts.Remote.Save(ctx, merged.Tasks); err != nil {
+			return nil, repository.StorageError("failed to save remote tasks", err)
+		}
+	}
+	if len(report.LocalChanges) > 0 {
+		if err := s.saveMerged(ctx, local, merged.Tasks); err != nil {
+			return nil, repository.StorageError("failed to save tasks", err)
+		}
+	}
+	if err := opts.Base.Save(ctx, merged.Tasks); err != nil {
+		return nil, repository.StorageError("failed to save sync state", err)
+	}
+	report.Saved = true
+
+	return report, nil
+}
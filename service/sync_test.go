@@ -0,0 +1,197 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// syncTask builds a task at a given revision for merge tests
+func syncTask(id int, description string, status task.TaskStatus, revision int) task.Task {
+	created := tasktest.FixedTime()
+	updated := created
+	for range revision - 1 {
+		updated = tasktest.TimeAfter(updated)
+	}
+	task := *tasktest.NewTaskBuilder().WithID(id).WithDescription(description).WithStatus(status).
+		WithTimestamps(created, updated).BuildInvalid()
+	task.Revision = revision
+	return task
+}
+
+// TestMergeTasks tests three-way merging of task lists
+func TestMergeTasks(t *testing.T) {
+	base := []task.Task{
+		syncTask(1, "Buy groceries", task.StatusTodo, 1),
+		syncTask(2, "Write report", task.StatusTodo, 1),
+	}
+
+	tests := []struct {
+		name          string
+		local         []task.Task
+		remote        []task.Task
+		want          []string
+		wantConflicts int
+	}{
+		{
+			name:   "no changes",
+			local:  base,
+			remote: base,
+			want:   []string{"1 Buy groceries todo", "2 Write report todo"},
+		},
+		{
+			name:   "remote edit is pulled",
+			local:  base,
+			remote: []task.Task{syncTask(1, "Buy milk", task.StatusTodo, 2), base[1]},
+			want:   []string{"1 Buy milk todo", "2 Write report todo"},
+		},
+		{
+			name:   "local delete is kept",
+			local:  base[:1],
+			remote: base,
+			want:   []string{"1 Buy groceries todo"},
+		},
+		{
+			name:   "added on both sides",
+			local:  append(base[:2:2], syncTask(3, "Local new", task.StatusTodo, 1)),
+			remote: append(base[:2:2], syncTask(4, "Remote new", task.StatusTodo, 1)),
+			want:   []string{"1 Buy groceries todo", "2 Write report todo", "3 Local new todo", "4 Remote new todo"},
+		},
+		{
+			name:   "different fields merge",
+			local:  []task.Task{syncTask(1, "Buy milk", task.StatusTodo, 2), base[1]},
+			remote: []task.Task{syncTask(1, "Buy groceries", task.StatusDone, 3), base[1]},
+			want:   []string{"1 Buy milk done", "2 Write report todo"},
+		},
+		{
+			name:          "same field conflicts",
+			local:         []task.Task{syncTask(1, "Buy milk", task.StatusTodo, 2), base[1]},
+			remote:        []task.Task{syncTask(1, "Buy bread", task.StatusTodo, 2), base[1]},
+			want:          []string{"1 Buy milk todo", "2 Write report todo"},
+			wantConflicts: 1,
+		},
+		{
+			name:          "edit against delete conflicts",
+			local:         []task.Task{syncTask(1, "Buy milk", task.StatusTodo, 2), base[1]},
+			remote:        base[1:],
+			want:          []string{"1 Buy milk todo", "2 Write report todo"},
+			wantConflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MergeTasks(base, tt.local, tt.remote, nil)
+
+			var got []string
+			for _, task := range result.Tasks {
+				got = append(got, fmt.Sprintf("%d %s %s", task.ID, task.Description, task.Status))
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("MergeTasks() = %v, want %v", got, tt.want)
+			}
+			if len(result.Conflicts) != tt.wantConflicts {
+				t.Errorf("MergeTasks() conflicts = %v, want %d", result.Conflicts, tt.wantConflicts)
+			}
+		})
+	}
+
+	t.Run("unrelated tasks with the same ID are renumbered", func(t *testing.T) {
+		local := []task.Task{syncTask(1, "Local", task.StatusTodo, 1)}
+		remote := []task.Task{syncTask(1, "Remote", task.StatusTodo, 1)}
+		remote[0].CreatedAt = tasktest.TimeAfter(remote[0].CreatedAt)
+
+		result := MergeTasks(nil, local, remote, nil)
+		if len(result.Tasks) != 2 || result.Renumbered[1] != 2 {
+			t.Errorf("MergeTasks() = %v, renumbered %v", result.Tasks, result.Renumbered)
+		}
+	})
+
+	t.Run("notes from both sides are kept", func(t *testing.T) {
+		noted := func(description string, revision int, notes ...string) task.Task {
+			item := syncTask(1, description, task.StatusTodo, revision)
+			for i, text := range notes {
+				item.Notes = append(item.Notes, task.Note{CreatedAt: tasktest.FixedTime().Add(time.Duration(i) * time.Hour), Text: text})
+			}
+			return item
+		}
+		base := []task.Task{noted("Buy groceries", 1, "shared", "dropped")}
+		local := []task.Task{noted("Buy milk", 2, "shared", "dropped")}
+		remote := []task.Task{noted("Buy groceries", 2, "shared")}
+		remote[0].Notes = append(remote[0].Notes, task.Note{CreatedAt: tasktest.FixedTime().Add(3 * time.Hour), Text: "call first"})
+
+		result := MergeTasks(base, local, remote, nil)
+		if len(result.Conflicts) != 0 || len(result.Tasks) != 1 {
+			t.Fatalf("MergeTasks() = %v, conflicts %v", result.Tasks, result.Conflicts)
+		}
+		var texts []string
+		for _, note := range result.Tasks[0].Notes {
+			texts = append(texts, note.Text)
+		}
+		if got := strings.Join(texts, "|"); result.Tasks[0].Description != "Buy milk" || got != "shared|call first" {
+			t.Errorf("merged %q with notes %s, want Buy milk with shared|call first", result.Tasks[0].Description, got)
+		}
+	})
+
+	t.Run("pomodoros from both sides add up", func(t *testing.T) {
+		base := []task.Task{syncTask(1, "Buy groceries", task.StatusTodo, 1)}
+		base[0].Pomodoros = 1
+		local := []task.Task{syncTask(1, "Buy milk", task.StatusTodo, 2)}
+		local[0].Pomodoros = 2
+		remote := []task.Task{syncTask(1, "Buy groceries", task.StatusTodo, 2)}
+		remote[0].Pomodoros = 3
+
+		result := MergeTasks(base, local, remote, nil)
+		if len(result.Conflicts) != 0 || len(result.Tasks) != 1 || result.Tasks[0].Pomodoros != 4 {
+			t.Errorf("MergeTasks() = %v, conflicts %v; want 4 pomodoros", result.Tasks, result.Conflicts)
+		}
+	})
+
+	t.Run("external refs merge by system", func(t *testing.T) {
+		base := []task.Task{syncTask(1, "Buy groceries", task.StatusTodo, 1)}
+		base[0].ExternalRefs = map[string]string{"jira": "OPS-1"}
+		local := []task.Task{syncTask(1, "Buy milk", task.StatusTodo, 2)}
+		local[0].ExternalRefs = map[string]string{"jira": "OPS-1"}
+		remote := []task.Task{syncTask(1, "Buy groceries", task.StatusTodo, 2)}
+		remote[0].ExternalRefs = map[string]string{"jira": "OPS-1", "github": "alnah/task-tracker#12"}
+
+		result := MergeTasks(base, local, remote, nil)
+		if len(result.Conflicts) != 0 || len(result.Tasks) != 1 {
+			t.Fatalf("MergeTasks() = %v, conflicts %v", result.Tasks, result.Conflicts)
+		}
+		if refs := result.Tasks[0].ExternalRefs; len(refs) != 2 || refs["github"] != "alnah/task-tracker#12" {
+			t.Errorf("merged refs = %v, want the jira and github refs", refs)
+		}
+		if len(local[0].ExternalRefs) != 1 {
+			t.Errorf("merging changed the local refs to %v", local[0].ExternalRefs)
+		}
+
+		remote[0].ExternalRefs["jira"] = "OPS-2"
+		local[0].ExternalRefs["jira"] = "OPS-3"
+		result = MergeTasks(base, local, remote, nil)
+		if len(result.Conflicts) != 1 || !slices.Contains(result.Conflicts[0].Fields, "externalRefs.jira") {
+			t.Errorf("MergeTasks() conflicts = %v, want one on externalRefs.jira", result.Conflicts)
+		}
+	})
+
+	t.Run("resolver decides conflicts", func(t *testing.T) {
+		local := []task.Task{syncTask(1, "Buy milk", task.StatusTodo, 2), base[1]}
+		remote := []task.Task{syncTask(1, "Buy bread", task.StatusTodo, 3), base[1]}
+
+		for resolution, want := range map[Resolution]string{
+			ResolveLocal: "Buy milk", ResolveRemote: "Buy bread", ResolveNewer: "Buy bread",
+		} {
+			result := MergeTasks(base, local, remote, func(c SyncConflict) (Resolution, bool) {
+				return resolution, c.ID == 1
+			})
+			if len(result.Conflicts) != 0 || result.Tasks[0].Description != want {
+				t.Errorf("%s: MergeTasks() = %v, conflicts %v", resolution, result.Tasks, result.Conflicts)
+			}
+		}
+	})
+}
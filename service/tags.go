@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// Tags returns every tag in use, most used first
+func (s *TaskService) Tags(ctx context.Context) ([]task.TagCount, error) {
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	return task.CountTags(tasks), nil
+}
+
+// RenameTag renames a tag on every task carrying it, refusing a new name
+// that is already in use so two tags are not merged by accident
+func (s *TaskService) RenameTag(ctx context.Context, oldName, newName string) (int, error) {
+	return s.retagAll(ctx, []string{oldName}, newName, false)
+}
+
+// MergeTags replaces the from tags with into on every task, which keeps
+// into once even when it had several of them, and returns how many tasks
+// changed
+func (s *TaskService) MergeTags(ctx context.Context, from []string, into string) (int, error) {
+	return s.retagAll(ctx, from, into, true)
+}
+
+// retagAll replaces the from tags with to in one save, then in the saved
+// views filtering on them
+func (s *TaskService) retagAll(ctx context.Context, from []string, to string, merge bool) (int, error) {
+	if err := s.writable(); err != nil {
+		return 0, err
+	}
+	to = task.NormalizeTag(to)
+	from = slices.DeleteFunc(task.MergeTags(nil, from), func(tag string) bool { return tag == to })
+	if to == "" || len(from) == 0 {
+		return 0, task.ErrInvalidTag
+	}
+
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return 0, repository.StorageError("failed to load tasks", err)
+	}
+	used := make(map[string]bool)
+	for _, count := range task.CountTags(tasks) {
+		used[count.Tag] = true
+	}
+	for _, tag := range from {
+		if !used[tag] {
+			return 0, fmt.Errorf("%w: %s", task.ErrTagNotFound, tag)
+		}
+	}
+	if used[to] && !merge {
+		return 0, fmt.Errorf("%w: %s", task.ErrTagExists, to)
+	}
+
+	var before, after []task.Task
+	for _, task := range tasks {
+		updated := task
+		if updated.ReplaceTags(from, to) {
+			before, after = append(before, task), append(after, updated)
+		}
+	}
+	if err := s.save(ctx, before, after); err != nil {
+		return 0, repository.StorageError("failed to save tasks", err)
+	}
+
+	return len(after), s.updateViewTags(ctx, func(tags []string) []string {
+		if !slices.ContainsFunc(tags, func(tag string) bool { return slices.Contains(from, tag) }) {
+			return tags
+		}
+		task := task.Task{Tags: tags}
+		task.ReplaceTags(from, to)
+		return task.Tags
+	})
+}
+
+// PruneTags drops the tags no task carries anymore from the saved views
+// and returns them; with dryRun nothing is saved
+func (s *TaskService) PruneTags(ctx context.Context, dryRun bool) ([]string, error) {
+	if !dryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, repository.StorageError("failed to load tasks", err)
+	}
+	used := make(map[string]bool)
+	for _, count := range task.CountTags(tasks) {
+		used[count.Tag] = true
+	}
+
+	var pruned []string
+	prune := func(tags []string) []string {
+		return slices.DeleteFunc(slices.Clone(tags), func(tag string) bool {
+			if used[tag] {
+				return false
+			}
+			if !slices.Contains(pruned, tag) {
+				pruned = append(pruned, tag)
+			}
+			return true
+		})
+	}
+	if dryRun && s.views != nil {
+		views, err := s.loadViews(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, view := range views {
+			prune(view.Tags)
+		}
+	} else if !dryRun {
+		if err := s.updateViewTags(ctx, prune); err != nil {
+			return nil, err
+		}
+	}
+	slices.Sort(pruned)
+	return pruned, nil
+}
+
+// updateViewTags rewrites the tag filter of every saved view with fn,
+// saving the views only when one changed. Without view storage there is
+// nothing to rewrite.
+func (s *TaskService) updateViewTags(ctx context.Context, fn func([]string) []string) error {
+	if s.views == nil {
+		return nil
+	}
+	views, err := s.loadViews(ctx)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i, view := range views {
+		tags := fn(view.Tags)
+		if !slices.Equal(tags, view.Tags) {
+			views[i].Tags = tags
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := s.views.SaveViews(ctx, views); err != nil {
+		return repository.StorageError("failed to save views", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+func TestTaskService_RenameTag(t *testing.T) {
+	ctx := context.Background()
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.TagTasks())
+	service := NewTaskService(repo)
+
+	if _, err := service.RenameTag(ctx, "web", "bug"); err == nil {
+		t.Error("renaming onto a tag in use succeeded, want ErrTagExists")
+	}
+	if _, err := service.RenameTag(ctx, "missing", "other"); err == nil {
+		t.Error("renaming an unused tag succeeded, want ErrTagNotFound")
+	}
+
+	changed, err := service.RenameTag(ctx, "web", "Front End")
+	if err != nil || changed != 2 {
+		t.Fatalf("RenameTag() = %d, %v, want 2 tasks", changed, err)
+	}
+	if task, _ := repo.GetTask(1); !slices.Equal(task.Tags, []string{"bug", "front-end"}) {
+		t.Errorf("task 1 tags = %v, want the renamed tag in place", task.Tags)
+	}
+}
+
+func TestTaskService_MergeTags(t *testing.T) {
+	ctx := context.Background()
+	repo := tasktest.NewMockRepository().WithTasks(tasktest.TagTasks())
+	service := NewTaskService(repo).
+		WithViews(repository.NewFileViewRepository(filepath.Join(t.TempDir(), "views.json")))
+	if err := service.SaveView(ctx, task.View{Name: "triage", Tags: []string{"defect", "old"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := service.MergeTags(ctx, []string{"defect", "issue", "bug"}, "bug")
+	if err != nil || changed != 2 {
+		t.Fatalf("MergeTags() = %d, %v, want 2 tasks", changed, err)
+	}
+	for id, want := range map[int][]string{1: {"bug", "web"}, 2: {"bug"}, 3: {"bug"}} {
+		if task, _ := repo.GetTask(id); !slices.Equal(task.Tags, want) {
+			t.Errorf("task %d tags = %v, want %v", id, task.Tags, want)
+		}
+	}
+	if view, _ := service.GetView(ctx, "triage"); !slices.Equal(view.Tags, []string{"bug", "old"}) {
+		t.Errorf("view tags = %v, want the merged tag", view.Tags)
+	}
+
+	pruned, err := service.PruneTags(ctx, false)
+	if err != nil || !slices.Equal(pruned, []string{"old"}) {
+		t.Fatalf("PruneTags() = %v, %v, want [old]", pruned, err)
+	}
+	if view, _ := service.GetView(ctx, "triage"); !slices.Equal(view.Tags, []string{"bug"}) {
+		t.Errorf("view tags = %v after prune, want [bug]", view.Tags)
+	}
+}
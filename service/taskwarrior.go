@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Taskwarrior Format (Adapter)
+//
+// Taskwarrior exchanges tasks as a JSON array (or one object per line) with
+// compact UTC timestamps. Statuses map as follows:
+//
+//	todo        <-> pending
+//	in-progress <-> pending with a start time
+//	done        <-> completed
+//	cancelled   <-> deleted
+//
+// Recurrence templates are not imported. Annotations
+// become notes, the project becomes a tag, and the Taskwarrior UUID is kept
+// as an external reference so a round trip does not duplicate tasks.
+
+// taskwarriorTimeLayout is the timestamp format of Taskwarrior JSON
+const taskwarriorTimeLayout = "20060102T150405Z"
+
+// taskwarriorRef is the ExternalRefs key holding the Taskwarrior UUID
+const taskwarriorRef = "taskwarrior"
+
+// TaskwarriorTask is one task in Taskwarrior's JSON format
+type TaskwarriorTask struct {
+	UUID        string                  `json:"uuid"`
+	Description string                  `json:"description"`
+	Status      string                  `json:"status"`
+	Entry       taskwarriorTime         `json:"entry"`
+	Modified    *taskwarriorTime        `json:"modified,omitempty"`
+	Start       *taskwarriorTime        `json:"start,omitempty"`
+	End         *taskwarriorTime        `json:"end,omitempty"`
+	Due         *taskwarriorTime        `json:"due,omitempty"`
+	Project     string                  `json:"project,omitempty"`
+	Priority    string                  `json:"priority,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Annotations []taskwarriorAnnotation `json:"annotations,omitempty"`
+}
+
+type taskwarriorAnnotation struct {
+	Entry       taskwarriorTime `json:"entry"`
+	Description string          `json:"description"`
+}
+
+// taskwarriorTime reads and writes Taskwarrior's compact timestamps
+type taskwarriorTime struct {
+	time.Time
+}
+
+func (t taskwarriorTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.UTC().Format(taskwarriorTimeLayout))
+}
+
+func (t *taskwarriorTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(taskwarriorTimeLayout, s)
+	if err != nil {
+		// Older versions wrote epoch seconds
+		var epoch int64
+		if _, scanErr := fmt.Sscan(s, &epoch); scanErr != nil {
+			return fmt.Errorf("invalid taskwarrior time %q", s)
+		}
+		parsed = time.Unix(epoch, 0).UTC()
+	}
+	t.Time = parsed
+	return nil
+}
+
+func twTime(t time.Time) *taskwarriorTime {
+	return &taskwarriorTime{Time: t}
+}
+
+// ToTaskwarrior converts tasks to Taskwarrior's export format
+func ToTaskwarrior(tasks []task.Task) []TaskwarriorTask {
+	exported := make([]TaskwarriorTask, 0, len(tasks))
+	for _, t := range tasks {
+		tw := TaskwarriorTask{
+			UUID:        taskwarriorUUID(t),
+			Description: t.Description,
+			Status:      "pending",
+			Entry:       taskwarriorTime{t.CreatedAt},
+			Modified:    twTime(t.UpdatedAt),
+			Priority:    taskwarriorPriority[t.Priority],
+			Tags:        t.Tags,
+		}
+		switch t.Status {
+		case task.StatusInProgress:
+			tw.Start = twTime(t.UpdatedAt)
+		case task.StatusDone:
+			tw.Status = "completed"
+			tw.End = twTime(t.UpdatedAt)
+		case task.StatusCancelled:
+			tw.Status = "deleted"
+			tw.End = twTime(t.UpdatedAt)
+		}
+		if t.DueAt != nil {
+			tw.Due = twTime(*t.DueAt)
+		}
+		for _, note := range t.Notes {
+			tw.Annotations = append(tw.Annotations, taskwarriorAnnotation{
+				Entry: taskwarriorTime{note.CreatedAt}, Description: note.Text,
+			})
+		}
+		exported = append(exported, tw)
+	}
+	return exported
+}
+
+var taskwarriorPriority = map[task.Priority]string{
+	task.PriorityLow:    "L",
+	task.PriorityMedium: "M",
+	task.PriorityHigh:   "H",
+	task.PriorityUrgent: "H",
+}
+
+// taskwarriorUUID returns the UUID a task was imported with, or its own
+// UUID so repeated exports agree
+func taskwarriorUUID(t task.Task) string {
+	if uuid := t.ExternalRefs[taskwarriorRef]; uuid != "" {
+		return uuid
+	}
+	return task.TaskUUID(t)
+}
+
+// TaskwarriorSource reads tasks exported with "task export"
+type TaskwarriorSource struct {
+	r io.Reader
+}
+
+func NewTaskwarriorSource(r io.Reader) *TaskwarriorSource {
+	return &TaskwarriorSource{r: r}
+}
+
+func (s *TaskwarriorSource) Tasks(_ context.Context) ([]ImportedTask, error) {
+	data, err := io.ReadAll(s.r)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := decodeTaskwarrior(data)
+	if err != nil {
+		return nil, fmt.Errorf("taskwarrior: %w", err)
+	}
+
+	var tasks []ImportedTask
+	for _, item := range items {
+		status, ok := map[string]task.TaskStatus{
+			"pending": task.StatusTodo, "waiting": task.StatusTodo, "completed": task.StatusDone, "deleted": task.StatusCancelled,
+		}[item.Status]
+		if !ok || strings.TrimSpace(item.Description) == "" {
+			continue
+		}
+		if status == task.StatusTodo && item.Start != nil {
+			status = task.StatusInProgress
+		}
+
+		t := ImportedTask{
+			Description: item.Description,
+			Tags:        item.Tags,
+			Priority:    map[string]task.Priority{"L": task.PriorityLow, "M": task.PriorityMedium, "H": task.PriorityHigh}[item.Priority],
+			CreatedAt:   item.Entry.Time,
+			Status:      status,
+		}
+		if item.Project != "" {
+			t.Tags = append([]string{item.Project}, t.Tags...)
+		}
+		if item.Due != nil {
+			due := item.Due.Time
+			t.DueAt = &due
+		}
+		for _, annotation := range item.Annotations {
+			t.Notes = append(t.Notes, task.Note{CreatedAt: annotation.Entry.Time, Text: annotation.Description})
+		}
+		if item.UUID != "" {
+			t.ExternalRefs = map[string]string{taskwarriorRef: item.UUID}
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, nil
+}
+
+// decodeTaskwarrior accepts a JSON array or one JSON object per line, as
+// written by older Taskwarrior versions
+func decodeTaskwarrior(data []byte) ([]TaskwarriorTask, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return nil, nil
+	}
+
+	var items []TaskwarriorTask
+	if strings.HasPrefix(trimmed, "[") {
+		err := json.Unmarshal([]byte(trimmed), &items)
+		return items, err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	for decoder.More() {
+		var item TaskwarriorTask
+		if err := decoder.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskwarriorSource tests parsing a real "task export" sample
+func TestTaskwarriorSource(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "taskwarrior_export.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tasks, err := NewTaskwarriorSource(f).Tasks(t.Context())
+	if err != nil {
+		t.Fatalf("Tasks() failed: %v", err)
+	}
+
+	// Recurrence templates are skipped
+	var descriptions []string
+	for _, task := range tasks {
+		descriptions = append(descriptions, task.Description)
+	}
+	want := "Buy groceries,Write quarterly report,Call mom,Cancel gym membership,Renew passport"
+	if got := strings.Join(descriptions, ","); got != want {
+		t.Fatalf("descriptions = %s, want %s", got, want)
+	}
+
+	groceries, report, call, gym, passport := tasks[0], tasks[1], tasks[2], tasks[3], tasks[4]
+	t.Run("pending", func(t *testing.T) {
+		if groceries.Status != task.StatusTodo || strings.Join(groceries.Tags, ",") != "home,errand" {
+			t.Errorf("task = %+v", groceries)
+		}
+		if !groceries.CreatedAt.Equal(time.Date(2024, 1, 2, 8, 15, 0, 0, time.UTC)) {
+			t.Errorf("CreatedAt = %v", groceries.CreatedAt)
+		}
+		if groceries.ExternalRefs[taskwarriorRef] != "2d5a3b1e-8c0f-4e5b-9f43-6d8f1c2a7b90" {
+			t.Errorf("refs = %v", groceries.ExternalRefs)
+		}
+	})
+
+	t.Run("started", func(t *testing.T) {
+		if report.Status != task.StatusInProgress || report.Priority != task.PriorityHigh || report.DueAt == nil {
+			t.Errorf("task = %+v", report)
+		}
+		if len(report.Notes) != 1 || report.Notes[0].Text != "Numbers are in the shared drive" {
+			t.Errorf("notes = %+v", report.Notes)
+		}
+	})
+
+	t.Run("completed, deleted and waiting", func(t *testing.T) {
+		if call.Status != task.StatusDone || call.Priority != task.PriorityLow {
+			t.Errorf("completed task = %+v", call)
+		}
+		if passport.Status != task.StatusTodo || passport.Priority != task.PriorityMedium {
+			t.Errorf("waiting task = %+v", passport)
+		}
+		if gym.Status != task.StatusCancelled {
+			t.Errorf("deleted task = %+v, want cancelled", gym)
+		}
+	})
+}
+
+// TestTaskwarriorSource_LineFormat tests the one-object-per-line format
+func TestTaskwarriorSource_LineFormat(t *testing.T) {
+	input := `{"description":"First","entry":"20240101T120000Z","status":"pending","uuid":"a"}
+{"description":"Second","entry":"1704110400","status":"completed","uuid":"b"}
+`
+	tasks, err := NewTaskwarriorSource(strings.NewReader(input)).Tasks(t.Context())
+	if err != nil {
+		t.Fatalf("Tasks() failed: %v", err)
+	}
+	if len(tasks) != 2 || tasks[1].Status != task.StatusDone || !tasks[1].CreatedAt.Equal(tasktest.FixedTime()) {
+		t.Errorf("tasks = %+v", tasks)
+	}
+}
+
+// TestToTaskwarrior tests mapping statuses, priorities and notes on export
+func TestToTaskwarrior(t *testing.T) {
+	done := tasktest.NewTaskBuilder().WithID(3).WithDescription("Call mom").Done().BuildValid(t)
+	started := tasktest.NewTaskBuilder().WithID(2).WithDescription("Write report").InProgress().BuildValid(t)
+	started.Priority = task.PriorityUrgent
+	started.Notes = []task.Note{{CreatedAt: tasktest.FixedTime(), Text: "draft first"}}
+
+	exported := ToTaskwarrior([]task.Task{*done, *started})
+
+	if exported[0].Status != "completed" || exported[0].End == nil {
+		t.Errorf("done task = %+v", exported[0])
+	}
+	if exported[1].Status != "pending" || exported[1].Start == nil || exported[1].Priority != "H" {
+		t.Errorf("in-progress task = %+v", exported[1])
+	}
+	if len(exported[1].Annotations) != 1 || exported[1].Annotations[0].Description != "draft first" {
+		t.Errorf("annotations = %+v", exported[1].Annotations)
+	}
+
+	if uuid := exported[0].UUID; uuid != done.UUID {
+		t.Errorf("uuid = %q, want the task's own %q", uuid, done.UUID)
+	}
+
+	// Tasks saved before tasks had UUIDs get a name-based one
+	done.UUID = ""
+	uuid := ToTaskwarrior([]task.Task{*done})[0].UUID
+	if len(uuid) != 36 || uuid[14] != '5' {
+		t.Errorf("uuid = %q, want a version 5 UUID", uuid)
+	}
+	if again := ToTaskwarrior([]task.Task{*done})[0].UUID; again != uuid {
+		t.Errorf("uuid changed between exports: %q, %q", uuid, again)
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// TickTick CSV Source (Adapter)
+//
+// TickTickCSV imports the backup produced by Settings > Backup. The file
+// starts with a few lines of metadata before the real header row. Lists and
+// tags become tags.
+type TickTickCSV struct {
+	r io.Reader
+}
+
+func NewTickTickCSV(r io.Reader) *TickTickCSV {
+	return &TickTickCSV{r: r}
+}
+
+// tickTickTimeLayout is the timestamp format of TickTick backups
+const tickTickTimeLayout = "2006-01-02T15:04:05-0700"
+
+func (t *TickTickCSV) Tasks(_ context.Context) ([]ImportedTask, error) {
+	reader := csv.NewReader(t.r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("ticktick csv: %w", err)
+	}
+
+	start := -1
+	for i, record := range records {
+		if _, ok := csvColumns(record)["TITLE"]; ok {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, errors.New("ticktick csv: no header row with a Title column")
+	}
+	columns := csvColumns(records[start])
+
+	var tasks []ImportedTask
+	for _, record := range records[start+1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if field("TITLE") == "" || strings.EqualFold(field("KIND"), "NOTE") {
+			continue
+		}
+
+		item := ImportedTask{
+			Description: field("TITLE"),
+			Tags:        projectTags(field("LIST NAME")),
+			Priority:    tickTickPriority(field("PRIORITY")),
+		}
+		// Status is 0 for open tasks, 1 completed and 2 archived
+		if status := field("STATUS"); status != "" && status != "0" {
+			item.Status = task.StatusDone
+		}
+		for _, tag := range strings.Split(field("TAGS"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				item.Tags = append(item.Tags, tag)
+			}
+		}
+		if due, err := time.Parse(tickTickTimeLayout, field("DUE DATE")); err == nil {
+			item.DueAt = &due
+		}
+		if created, err := time.Parse(tickTickTimeLayout, field("CREATED TIME")); err == nil {
+			item.CreatedAt = created
+		}
+		tasks = append(tasks, item)
+	}
+
+	return tasks, nil
+}
+
+// tickTickPriority maps TickTick priorities 0 (none), 1, 3 and 5 (high)
+func tickTickPriority(value string) task.Priority {
+	priority, _ := strconv.Atoi(value)
+	switch {
+	case priority >= 5:
+		return task.PriorityHigh
+	case priority >= 3:
+		return task.PriorityMedium
+	case priority >= 1:
+		return task.PriorityLow
+	default:
+		return task.PriorityNone
+	}
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// TodoistAPIURL is the default Todoist API endpoint
+const TodoistAPIURL = "https://api.todoist.com/api/v1"
+
+// Todoist API Source (Adapter)
+//
+// TodoistAPI imports the active tasks of a Todoist account. Project names
+// and labels become tags.
+type TodoistAPI struct {
+	apiURL string
+	token  string
+	client *http.Client
+	now    time.Time
+}
+
+// NewTodoistAPI creates a source for the account owning token. An empty
+// apiURL uses TodoistAPIURL; now resolves date-only deadlines.
+func NewTodoistAPI(apiURL, token string, now time.Time) *TodoistAPI {
+	if apiURL == "" {
+		apiURL = TodoistAPIURL
+	}
+	return &TodoistAPI{
+		apiURL: strings.TrimRight(apiURL, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    now,
+	}
+}
+
+type todoistProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type todoistTask struct {
+	Content   string   `json:"content"`
+	ProjectID string   `json:"project_id"`
+	Labels    []string `json:"labels"`
+	// Priority runs from 1 (normal) to 4 (urgent)
+	Priority int `json:"priority"`
+	Due      *struct {
+		Date     string `json:"date"`
+		Datetime string `json:"datetime"`
+	} `json:"due"`
+	AddedAt   time.Time `json:"added_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (t *TodoistAPI) Tasks(ctx context.Context) ([]ImportedTask, error) {
+	if t.token == "" {
+		return nil, errors.New("todoist: an API token is required (--token, todoist.token or $TODOIST_TOKEN)")
+	}
+
+	var projects []todoistProject
+	if err := t.list(ctx, "/projects", &projects); err != nil {
+		return nil, err
+	}
+	projectNames := make(map[string]string, len(projects))
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+	}
+
+	var items []todoistTask
+	if err := t.list(ctx, "/tasks", &items); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]ImportedTask, 0, len(items))
+	for _, item := range items {
+		task := ImportedTask{
+			Description: item.Content,
+			Tags:        append(projectTags(projectNames[item.ProjectID]), item.Labels...),
+			Priority:    todoistAPIPriority(item.Priority),
+			CreatedAt:   item.AddedAt,
+		}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = item.CreatedAt
+		}
+		if item.Due != nil {
+			task.DueAt = t.parseDue(item.Due.Datetime, item.Due.Date)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+func (t *TodoistAPI) parseDue(datetime, date string) *time.Time {
+	for _, value := range []string{datetime, date} {
+		if value == "" {
+			continue
+		}
+		if due, err := task.ParseDeadline(value, t.now); err == nil {
+			return &due
+		}
+		// Floating times are written without a zone
+		if due, err := time.ParseInLocation("2006-01-02T15:04:05", value, time.Local); err == nil {
+			return &due
+		}
+	}
+	return nil
+}
+
+// list fetches every page of a cursor-paginated collection
+func (t *TodoistAPI) list(ctx context.Context, path string, out any) error {
+	var all []json.RawMessage
+	cursor := ""
+	for {
+		query := url.Values{"limit": {"200"}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.apiURL+path+"?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+t.token)
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("todoist: %w", err)
+		}
+		var page struct {
+			Results    []json.RawMessage `json:"results"`
+			NextCursor string            `json:"next_cursor"`
+		}
+		err = decodeTodoistResponse(resp, &page)
+		if err != nil {
+			return err
+		}
+
+		all = append(all, page.Results...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func decodeTodoistResponse(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		message := strings.TrimSpace(string(body))
+		if message == "" {
+			message = resp.Status
+		}
+		return fmt.Errorf("todoist: %s (%d)", message, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("todoist: invalid response: %w", err)
+	}
+	return nil
+}
+
+// todoistAPIPriority maps API priorities, where 4 is the most urgent
+func todoistAPIPriority(priority int) task.Priority {
+	switch priority {
+	case 4:
+		return task.PriorityUrgent
+	case 3:
+		return task.PriorityHigh
+	case 2:
+		return task.PriorityMedium
+	default:
+		return task.PriorityNone
+	}
+}
+
+// projectTags turns a project name into a tag, skipping the default inbox
+func projectTags(project string) []string {
+	if project == "" || strings.EqualFold(project, "Inbox") {
+		return nil
+	}
+	return []string{project}
+}
+
+// Todoist CSV Source (Adapter)
+//
+// TodoistCSV imports a project exported with "Export as a template". The
+// export does not name its project, so the caller supplies it.
+type TodoistCSV struct {
+	r       io.Reader
+	project string
+	now     time.Time
+}
+
+// NewTodoistCSV reads an export from r, tagging tasks with project
+func NewTodoistCSV(r io.Reader, project string, now time.Time) *TodoistCSV {
+	return &TodoistCSV{r: r, project: project, now: now}
+}
+
+func (t *TodoistCSV) Tasks(_ context.Context) ([]ImportedTask, error) {
+	reader := csv.NewReader(t.r)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("todoist csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := csvColumns(records[0])
+	for _, required := range []string{"TYPE", "CONTENT"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("todoist csv: missing %s column", required)
+		}
+	}
+
+	var tasks []ImportedTask
+	for _, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if field("TYPE") != "task" || field("CONTENT") == "" {
+			continue
+		}
+
+		item := ImportedTask{
+			Description: field("CONTENT"),
+			Tags:        projectTags(t.project),
+			Priority:    todoistCSVPriority(field("PRIORITY")),
+		}
+		// Recurring dates such as "every monday" are not deadlines
+		if date := field("DATE"); date != "" {
+			if due, err := task.ParseDeadline(date, t.now); err == nil {
+				item.DueAt = &due
+			}
+		}
+		tasks = append(tasks, item)
+	}
+
+	return tasks, nil
+}
+
+// todoistCSVPriority maps export priorities, where 1 is the most urgent
+func todoistCSVPriority(value string) task.Priority {
+	priority, _ := strconv.Atoi(value)
+	switch priority {
+	case 1:
+		return task.PriorityUrgent
+	case 2:
+		return task.PriorityHigh
+	case 3:
+		return task.PriorityMedium
+	default:
+		return task.PriorityNone
+	}
+}
+
+// csvColumns indexes a header row by upper-cased column name
+func csvColumns(header []string) map[string]int {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	return columns
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_ResolveTaskID tests naming tasks by ID or UUID prefix
+func TestTaskService_ResolveTaskID(t *testing.T) {
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].UUID = "3f2a9c10-0000-4000-8000-000000000001"
+	tasks[1].UUID = "3f2a0000-0000-4000-8000-000000000002"
+	service := NewTaskService(tasktest.NewMockRepository().WithTasks(tasks))
+	legacy := task.TaskUUID(tasks[2])
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    int
+		wantErr error
+	}{
+		{"integer ID", "2", 2, nil},
+		{"unknown integer ID", "42", 42, nil},
+		{"full UUID", tasks[1].UUID, 2, nil},
+		{"prefix", "3f2a9", 1, nil},
+		{"upper case prefix", "3F2A0", 2, nil},
+		{"derived UUID of an older task", legacy[:8], 3, nil},
+		{"ambiguous prefix", "3f2a", 0, task.ErrAmbiguousID},
+		{"too short", "3f", 0, task.ErrTaskNotFound},
+		{"not hexadecimal", "abcz", 0, task.ErrTaskNotFound},
+		{"no match", "ffff", 0, task.ErrTaskNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := service.ResolveTaskID(context.Background(), tt.ref)
+			switch {
+			case tt.wantErr == nil && (err != nil || id != tt.want):
+				t.Errorf("ResolveTaskID(%q) = %d, %v; want %d", tt.ref, id, err, tt.want)
+			case tt.wantErr != nil && (!errors.Is(err, tt.wantErr)):
+				t.Errorf("ResolveTaskID(%q) error = %v, want %v", tt.ref, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestMergeTasks_UUIDIdentity tests telling tasks apart by UUID during sync
+func TestMergeTasks_UUIDIdentity(t *testing.T) {
+	local := tasktest.FixedTasks(t)[:1]
+	remote := tasktest.FixedTasks(t)[:1]
+	local[0].UUID, remote[0].UUID = task.NewUUID(), task.NewUUID()
+
+	merged := MergeTasks(nil, local, remote, nil)
+	if len(merged.Tasks) != 2 || merged.Renumbered[1] != 2 {
+		t.Errorf("merge = %+v, want the local task renumbered", merged)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WithViews stores views in repo; without it view commands fail
+func (s *TaskService) WithViews(repo repository.ViewRepository) *TaskService {
+	s.views = repo
+	return s
+}
+
+func (s *TaskService) loadViews(ctx context.Context) ([]task.View, error) {
+	if s.views == nil {
+		return nil, task.ErrNoViews
+	}
+	views, err := s.views.LoadViews(ctx)
+	if err != nil {
+		return nil, repository.StorageError("failed to load views", err)
+	}
+	return views, nil
+}
+
+// SaveView stores a view, replacing any view with the same name
+func (s *TaskService) SaveView(ctx context.Context, view task.View) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	view.Name = strings.TrimSpace(view.Name)
+	if view.Name == "" || strings.ContainsAny(view.Name, " \t\n") {
+		return task.ErrInvalidView
+	}
+	if view.Status != "" && !s.workflow.Has(task.TaskStatus(view.Status)) {
+		return task.ErrInvalidStatus
+	}
+	if view.Sort != "" {
+		if err := task.SortTasks(nil, view.Sort); err != nil {
+			return err
+		}
+	}
+	view.Tags = task.MergeTags(nil, view.Tags)
+	if view.Where != "" {
+		if _, err := task.ParseQuery(view.Where, task.QueryEnv{Now: time.Now(), Workflow: s.workflow}); err != nil {
+			return err
+		}
+	}
+
+	views, err := s.loadViews(ctx)
+	if err != nil {
+		return err
+	}
+	if i := slices.IndexFunc(views, func(v task.View) bool { return v.Name == view.Name }); i >= 0 {
+		views[i] = view
+	} else {
+		views = append(views, view)
+	}
+	if err := s.views.SaveViews(ctx, views); err != nil {
+		return repository.StorageError("failed to save views", err)
+	}
+	return nil
+}
+
+// Views returns every saved view in the order they were first saved
+func (s *TaskService) Views(ctx context.Context) ([]task.View, error) {
+	return s.loadViews(ctx)
+}
+
+// GetView returns the view with the name
+func (s *TaskService) GetView(ctx context.Context, name string) (task.View, error) {
+	views, err := s.loadViews(ctx)
+	if err != nil {
+		return task.View{}, err
+	}
+	i := slices.IndexFunc(views, func(v task.View) bool { return v.Name == name })
+	if i < 0 {
+		return task.View{}, task.ErrViewNotFound
+	}
+	return views[i], nil
+}
+
+func (s *TaskService) DeleteView(ctx context.Context, name string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	views, err := s.loadViews(ctx)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(views, func(v task.View) bool { return v.Name == name })
+	if i < 0 {
+		return task.ErrViewNotFound
+	}
+	if err := s.views.SaveViews(ctx, slices.Delete(views, i, i+1)); err != nil {
+		return repository.StorageError("failed to save views", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_SaveView tests that saving a view under an existing name
+// replaces it
+func TestTaskService_SaveView(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(tasktest.NewMockRepository()).
+		WithViews(repository.NewFileViewRepository(filepath.Join(t.TempDir(), "views.json")))
+
+	service.SaveView(ctx, task.View{Name: "mine", Assignee: "me"})
+	service.SaveView(ctx, task.View{Name: "mine", Assignee: "me", All: true})
+	views, err := service.Views(ctx)
+	if err != nil || len(views) != 1 || !views[0].All {
+		t.Errorf("Views() = %+v, %v; want the second version only", views, err)
+	}
+	if err := service.SaveView(ctx, task.View{Name: "my view"}); !errors.Is(err, task.ErrInvalidView) {
+		t.Errorf("SaveView(my view) error = %v, want %v", err, task.ErrInvalidView)
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// ChangeNotifier is implemented by repositories that can signal when their
+// underlying storage changes, sparing watchers from polling
+type ChangeNotifier interface {
+	Notify(ctx context.Context) (<-chan struct{}, error)
+}
+
+// WatchTasks emits every change made to the store until ctx is done.
+// Repositories implementing ChangeNotifier are re-read when they signal a
+// change; others are polled every interval, which must then be positive.
+// The returned channel is closed when watching stops.
+func (s *TaskService) WatchTasks(ctx context.Context, interval time.Duration) (<-chan task.TaskChange, error) {
+	current, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	triggers, err := s.watchTriggers(ctx, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan task.TaskChange)
+	go func() {
+		defer close(changes)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-triggers:
+				if !ok {
+					return
+				}
+			}
+
+			if cache, ok := s.repo.(repository.Invalidator); ok {
+				cache.Invalidate()
+			}
+			next, err := s.store.List(ctx, repository.TaskFilter{})
+			if err != nil {
+				// The file may be mid-write by another process, retry on next trigger
+				continue
+			}
+
+			for _, change := range task.DiffTasks(current, next) {
+				select {
+				case changes <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+			current = next
+		}
+	}()
+
+	return changes, nil
+}
+
+// watchTriggers returns a channel that fires whenever the store may have changed
+func (s *TaskService) watchTriggers(ctx context.Context, interval time.Duration) (<-chan struct{}, error) {
+	if notifier, ok := repository.RepositoryAs[ChangeNotifier](s.repo); ok {
+		return notifier.Notify(ctx)
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("polling interval must be positive, not %s", interval)
+	}
+	triggers := make(chan struct{})
+	go func() {
+		defer close(triggers)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			select {
+			case triggers <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return triggers, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestTaskService_WatchTasks tests that changes from another process are seen
+func TestTaskService_WatchTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	watcher := NewTaskService(repository.NewFileTaskRepository(path))
+	writer := NewTaskService(repository.NewFileTaskRepository(path))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	changes, err := watcher.WatchTasks(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("WatchTasks() failed: %v", err)
+	}
+
+	item, err := writer.AddTask(ctx, "Seen from elsewhere")
+	if err != nil {
+		t.Fatalf("AddTask() failed: %v", err)
+	}
+
+	select {
+	case change := <-changes:
+		if change.Type != task.ChangeAdded || change.Task.ID != item.ID {
+			t.Errorf("WatchTasks() = %s #%d, want added #%d", change.Type, change.Task.ID, item.ID)
+		}
+	case <-ctx.Done():
+		t.Fatalf("WatchTasks() did not report the change (fsnotify should beat the 1h poll)")
+	}
+
+	if err := writer.MarkTaskDone(ctx, item.ID); err != nil {
+		t.Fatalf("MarkTaskDone() failed: %v", err)
+	}
+
+	select {
+	case change := <-changes:
+		if change.Type != task.ChangeUpdated {
+			t.Errorf("WatchTasks() = %s, want updated", change.Type)
+		}
+	case <-ctx.Done():
+		t.Fatalf("WatchTasks() did not report the update")
+	}
+
+	cancel()
+	for range changes {
+	}
+}
+
+// TestTaskService_WatchTasksPolling tests the fallback for non-notifying repositories
+func TestTaskService_WatchTasksPolling(t *testing.T) {
+	repo := tasktest.NewMockRepository()
+	service := NewTaskService(repo)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	changes, err := service.WatchTasks(ctx, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchTasks() failed: %v", err)
+	}
+	cancel()
+
+	// The channel must close once the context is done
+	for range changes {
+	}
+}
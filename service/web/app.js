@@ -0,0 +1,146 @@
+// Board view of the task tracker REST API. Tasks are grouped into one
+// column per workflow status and can be dragged between columns.
+"use strict";
+
+const api = "api/v1";
+const board = document.getElementById("board");
+const errorBox = document.getElementById("error");
+
+function settings() {
+  return {
+    token: localStorage.getItem("task-tracker.token") || "",
+    name: localStorage.getItem("task-tracker.name") || "",
+  };
+}
+
+async function request(method, path, body) {
+  const { token, name } = settings();
+  const headers = { "Content-Type": "application/json" };
+  if (token) headers["Authorization"] = "Bearer " + token;
+  if (name) headers["X-Task-Actor"] = name;
+
+  const response = await fetch(api + path, {
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (response.status === 401) {
+    openSettings();
+    throw new Error("A token is required, enter it in the settings");
+  }
+  if (!response.ok) {
+    const data = await response.json().catch(() => null);
+    throw new Error(data && data.error ? data.error.message : response.statusText);
+  }
+  return response.status === 204 ? null : response.json();
+}
+
+function showError(err) {
+  errorBox.textContent = err ? err.message : "";
+  errorBox.hidden = !err;
+}
+
+function card(task) {
+  const el = document.createElement("article");
+  el.className = "card";
+  el.draggable = true;
+  el.dataset.id = task.id;
+
+  const title = document.createElement("div");
+  title.textContent = task.description;
+  el.appendChild(title);
+
+  const meta = ["#" + task.id];
+  if (task.priority) meta.push(task.priority);
+  if (task.assignee) meta.push("@" + task.assignee);
+  if (task.dueAt) meta.push("due " + new Date(task.dueAt).toLocaleString());
+  if (task.tags) meta.push(task.tags.map((tag) => "#" + tag).join(" "));
+  const info = document.createElement("div");
+  info.className = "meta";
+  info.textContent = meta.join(" · ");
+  el.appendChild(info);
+
+  el.addEventListener("dragstart", (event) => {
+    event.dataTransfer.setData("text/plain", String(task.id));
+  });
+  return el;
+}
+
+function column(status, tasks) {
+  const el = document.createElement("section");
+  el.className = "column";
+  el.dataset.status = status;
+
+  const heading = document.createElement("h2");
+  heading.textContent = status + " (" + tasks.length + ")";
+  el.appendChild(heading);
+  tasks.forEach((task) => el.appendChild(card(task)));
+
+  el.addEventListener("dragover", (event) => {
+    event.preventDefault();
+    el.classList.add("drop-target");
+  });
+  el.addEventListener("dragleave", () => el.classList.remove("drop-target"));
+  el.addEventListener("drop", async (event) => {
+    event.preventDefault();
+    el.classList.remove("drop-target");
+    const id = event.dataTransfer.getData("text/plain");
+    try {
+      await request("PATCH", "/tasks/" + id, { status });
+      showError(null);
+    } catch (err) {
+      showError(err);
+    }
+    refresh();
+  });
+  return el;
+}
+
+async function refresh() {
+  try {
+    const [workflow, tasks] = await Promise.all([
+      request("GET", "/statuses"),
+      request("GET", "/tasks"),
+    ]);
+    board.replaceChildren(
+      ...workflow.statuses.map((status) =>
+        column(status, tasks.filter((task) => task.status === status)),
+      ),
+    );
+  } catch (err) {
+    showError(err);
+  }
+}
+
+document.getElementById("add-form").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const input = document.getElementById("add-description");
+  try {
+    await request("POST", "/tasks", { description: input.value });
+    input.value = "";
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+  refresh();
+});
+
+const dialog = document.getElementById("settings");
+
+function openSettings() {
+  const { token, name } = settings();
+  document.getElementById("settings-token").value = token;
+  document.getElementById("settings-name").value = name;
+  if (!dialog.open) dialog.showModal();
+}
+
+document.getElementById("settings-button").addEventListener("click", openSettings);
+dialog.addEventListener("close", () => {
+  if (dialog.returnValue !== "save") return;
+  localStorage.setItem("task-tracker.token", document.getElementById("settings-token").value);
+  localStorage.setItem("task-tracker.name", document.getElementById("settings-name").value);
+  refresh();
+});
+
+refresh();
+setInterval(refresh, 5000);
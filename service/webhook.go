@@ -0,0 +1,173 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+// Webhook events
+const (
+	EventTaskCreated = "task.created"
+	EventTaskUpdated = "task.updated"
+	EventTaskDone    = "task.done"
+	EventTaskDeleted = "task.deleted"
+	// EventTaskReminder is sent by the daemon when a reminder fires
+	EventTaskReminder = "task.reminder"
+	// EventTaskWoken is sent by the daemon when a snoozed task wakes
+	EventTaskWoken = "task.woken"
+)
+
+// WebhookEvent is the JSON body posted to webhook URLs
+type WebhookEvent struct {
+	Event    string             `json:"event"`
+	Time     time.Time          `json:"time"`
+	Task     task.Task          `json:"task"`
+	Previous *task.Task         `json:"previous,omitempty"`
+	Changes  []task.FieldChange `json:"changes,omitempty"`
+}
+
+// NewWebhookEvent describes a change as a webhook event. An update that
+// completes a task is reported as task.done rather than task.updated.
+func NewWebhookEvent(change task.TaskChange, now time.Time) WebhookEvent {
+	event := WebhookEvent{Time: now, Task: change.Task, Previous: change.Previous, Changes: change.Fields()}
+	switch {
+	case change.Type == task.ChangeAdded:
+		event.Event = EventTaskCreated
+	case change.Type == task.ChangeDeleted:
+		event.Event = EventTaskDeleted
+	case change.Task.Status == task.StatusDone && change.Previous != nil && change.Previous.Status != task.StatusDone:
+		event.Event = EventTaskDone
+	default:
+		event.Event = EventTaskUpdated
+	}
+	return event
+}
+
+// Webhook Adapter (Infrastructure)
+//
+// WebhookSender posts events to a list of URLs. When a secret is set each
+// body is signed with HMAC-SHA256 in the X-Task-Signature header as
+// "sha256=<hex>", so receivers can check it came from this server.
+type WebhookSender struct {
+	urls    []string
+	secret  string
+	client  *http.Client
+	retries int
+	backoff time.Duration
+}
+
+func NewWebhookSender(urls []string, secret string) *WebhookSender {
+	return &WebhookSender{
+		urls:    urls,
+		secret:  secret,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		retries: 3,
+		backoff: time.Second,
+	}
+}
+
+// WithRetries sets how many times a failed delivery is retried, doubling the
+// wait after each attempt
+func (w *WebhookSender) WithRetries(retries int, backoff time.Duration) *WebhookSender {
+	w.retries = retries
+	w.backoff = backoff
+	return w
+}
+
+// Send delivers an event to every URL and returns the first failure after
+// trying them all
+func (w *WebhookSender) Send(ctx context.Context, event WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, url := range w.urls {
+		if err := w.deliver(ctx, url, event.Event, body); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("webhook %s: %w", url, err)
+		}
+	}
+	return firstErr
+}
+
+// deliver posts to one URL, retrying network failures and server errors
+func (w *WebhookSender) deliver(ctx context.Context, url, event string, body []byte) error {
+	var lastErr error
+	for attempt := 0; attempt <= w.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(w.backoff << (attempt - 1)):
+			}
+		}
+
+		status, err := w.post(ctx, url, event, body)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		if status >= 500 || status == http.StatusTooManyRequests {
+			lastErr = fmt.Errorf("receiver returned %d", status)
+			continue
+		}
+		if status >= 300 {
+			return fmt.Errorf("receiver returned %d", status)
+		}
+		return nil
+	}
+	return lastErr
+}
+
+func (w *WebhookSender) post(ctx context.Context, url, event string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-cli")
+	req.Header.Set("X-Task-Event", event)
+	if w.secret != "" {
+		req.Header.Set("X-Task-Signature", SignWebhook(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the X-Task-Signature value of a body
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// RunWebhooks sends an event for every change until changes is closed.
+// Failed deliveries are passed to onError and do not stop later events.
+func RunWebhooks(ctx context.Context, sender *WebhookSender, changes <-chan task.TaskChange, onError func(error)) {
+	for change := range changes {
+		if err := sender.Send(ctx, NewWebhookEvent(change, time.Now())); err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	"github.com/alnah/task-tracker/task"
+)
+
+// TestNewWebhookEvent tests how changes map to event names
+func TestNewWebhookEvent(t *testing.T) {
+	todo := *tasktest.NewTaskBuilder().WithID(1).BuildValid(t)
+	started := *tasktest.NewTaskBuilder().WithID(1).InProgress().BuildValid(t)
+	done := *tasktest.NewTaskBuilder().WithID(1).Done().BuildValid(t)
+
+	tests := []struct {
+		name   string
+		change task.TaskChange
+		want   string
+	}{
+		{"created", task.TaskChange{Type: task.ChangeAdded, Task: todo}, EventTaskCreated},
+		{"started", task.TaskChange{Type: task.ChangeUpdated, Task: started, Previous: &todo}, EventTaskUpdated},
+		{"completed", task.TaskChange{Type: task.ChangeUpdated, Task: done, Previous: &started}, EventTaskDone},
+		{"edited while done", task.TaskChange{Type: task.ChangeUpdated, Task: done, Previous: &done}, EventTaskUpdated},
+		{"deleted", task.TaskChange{Type: task.ChangeDeleted, Task: done}, EventTaskDeleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewWebhookEvent(tt.change, tasktest.FixedTime()).Event; got != tt.want {
+				t.Errorf("event = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWebhookSender_Send tests signing and retries of deliveries
+func TestWebhookSender_Send(t *testing.T) {
+	event := NewWebhookEvent(task.TaskChange{Type: task.ChangeAdded, Task: *tasktest.TodoTask(t)}, tasktest.FixedTime())
+
+	t.Run("signs the body", func(t *testing.T) {
+		var gotEvent WebhookEvent
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if got, want := r.Header.Get("X-Task-Signature"), SignWebhook("s3cret", body); got != want {
+				t.Errorf("signature = %q, want %q", got, want)
+			}
+			if got := r.Header.Get("X-Task-Event"); got != EventTaskCreated {
+				t.Errorf("X-Task-Event = %q, want %q", got, EventTaskCreated)
+			}
+			json.Unmarshal(body, &gotEvent)
+		}))
+		defer server.Close()
+
+		if err := NewWebhookSender([]string{server.URL}, "s3cret").Send(t.Context(), event); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if gotEvent.Task.ID != event.Task.ID || gotEvent.Event != EventTaskCreated {
+			t.Errorf("received %+v, want %+v", gotEvent, event)
+		}
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		defer server.Close()
+
+		sender := NewWebhookSender([]string{server.URL}, "").WithRetries(3, time.Millisecond)
+		if err := sender.Send(t.Context(), event); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if calls.Load() != 3 {
+			t.Errorf("calls = %d, want 3", calls.Load())
+		}
+	})
+
+	t.Run("gives up on client errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		sender := NewWebhookSender([]string{server.URL}, "").WithRetries(3, time.Millisecond)
+		if err := sender.Send(t.Context(), event); err == nil {
+			t.Fatal("Send() error = nil, want an error")
+		}
+		if calls.Load() != 1 {
+			t.Errorf("calls = %d, want 1", calls.Load())
+		}
+	})
+}
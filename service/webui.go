@@ -0,0 +1,26 @@
+package service
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// Web UI (Presentation Layer)
+//
+// A single page board served next to the REST API. It lists tasks in one
+// column per workflow status, adds tasks and moves them by drag and drop,
+// all through the /api/v1 endpoints with the token the user enters.
+
+//go:embed web
+var webFiles embed.FS
+
+// webUI serves the embedded static assets. They are public; the API calls
+// they make are authenticated like any other client's.
+func webUI() http.Handler {
+	files, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServerFS(files)
+}
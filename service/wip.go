@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alnah/task-tracker/repository"
+	"github.com/alnah/task-tracker/task"
+)
+
+// WIPLoads counts the tasks of every status with a WIP limit, in workflow
+// order
+func (s *TaskService) WIPLoads(ctx context.Context) ([]task.WIPLoad, error) {
+	if len(s.workflow.Limits) == 0 {
+		return nil, nil
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var loads []task.WIPLoad
+	for _, status := range s.workflow.Statuses {
+		limit, ok := s.workflow.Limits[status]
+		if !ok {
+			continue
+		}
+		load := task.WIPLoad{Status: status, Limit: limit}
+		for _, task := range tasks {
+			if task.Status == status {
+				load.Count++
+			}
+		}
+		loads = append(loads, load)
+	}
+	return loads, nil
+}
+
+// checkWIPLimit refuses moving a task into a status already holding as
+// many other tasks as its limit allows
+func (s *TaskService) checkWIPLimit(ctx context.Context, id int, status task.TaskStatus) error {
+	limit, ok := s.workflow.Limits[status]
+	if !ok {
+		return nil
+	}
+	tasks, err := s.store.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, task := range tasks {
+		if task.ID == id && task.Status == status {
+			// Staying in the status adds nothing to it
+			return nil
+		}
+		if task.Status == status {
+			count++
+		}
+	}
+	if count < limit {
+		return nil
+	}
+	return task.ErrWIPLimit.Withf("%s already holds %d of %d tasks", status, count, limit)
+}
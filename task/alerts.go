@@ -0,0 +1,72 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// AlertKind classifies why a task deserves attention
+type AlertKind string
+
+const (
+	AlertDueSoon AlertKind = "due-soon"
+	AlertOverdue AlertKind = "overdue"
+	AlertStale   AlertKind = "stale"
+)
+
+// AlertThresholds configures when tasks raise alerts
+type AlertThresholds struct {
+	// DueWithin raises an alert for open tasks due in less than this duration
+	DueWithin time.Duration
+	// StaleAfter raises an alert for in-progress tasks untouched for this long
+	StaleAfter time.Duration
+}
+
+// Alert is a task that needs attention now
+type Alert struct {
+	Kind AlertKind
+	Task Task
+}
+
+// Key identifies an alert so repeated checks do not notify twice
+func (a Alert) Key() string {
+	return fmt.Sprintf("%s:%d:%d", a.Kind, a.Task.ID, a.Task.UpdatedAt.Unix())
+}
+
+// Message describes the alert for a notification body
+func (a Alert) Message(now time.Time) string {
+	switch a.Kind {
+	case AlertDueSoon:
+		return fmt.Sprintf("#%d %s is due in %s", a.Task.ID, a.Task.Description,
+			a.Task.DueAt.Sub(now).Round(time.Minute))
+	case AlertOverdue:
+		return fmt.Sprintf("#%d %s was due %s ago", a.Task.ID, a.Task.Description,
+			now.Sub(*a.Task.DueAt).Round(time.Minute))
+	default:
+		return fmt.Sprintf("#%d %s has been in progress without changes for %s",
+			a.Task.ID, a.Task.Description, now.Sub(a.Task.UpdatedAt).Round(time.Hour))
+	}
+}
+
+// AlertFor returns the alert raised by a task at the given time, if any
+func AlertFor(task Task, now time.Time, thresholds AlertThresholds) (Alert, bool) {
+	if !task.IsOpen() {
+		return Alert{}, false
+	}
+
+	if task.DueAt != nil {
+		switch {
+		case !task.DueAt.After(now):
+			return Alert{Kind: AlertOverdue, Task: task}, true
+		case thresholds.DueWithin > 0 && task.DueAt.Sub(now) <= thresholds.DueWithin:
+			return Alert{Kind: AlertDueSoon, Task: task}, true
+		}
+	}
+
+	if task.Status == StatusInProgress && thresholds.StaleAfter > 0 &&
+		now.Sub(task.UpdatedAt) >= thresholds.StaleAfter {
+		return Alert{Kind: AlertStale, Task: task}, true
+	}
+
+	return Alert{}, false
+}
@@ -0,0 +1,72 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestAlertFor tests which tasks raise alerts
+func TestAlertFor(t *testing.T) {
+	now := tasktest.FixedTime()
+	thresholds := AlertThresholds{DueWithin: time.Hour, StaleAfter: 72 * time.Hour}
+	at := func(d time.Duration) *time.Time {
+		due := now.Add(d)
+		return &due
+	}
+
+	tests := []struct {
+		name     string
+		task     Task
+		wantKind AlertKind
+		wantOK   bool
+	}{
+		{
+			name:     "due within the hour",
+			task:     Task{ID: 1, Status: StatusTodo, UpdatedAt: now, DueAt: at(30 * time.Minute)},
+			wantKind: AlertDueSoon,
+			wantOK:   true,
+		},
+		{
+			name:   "due tomorrow",
+			task:   Task{ID: 2, Status: StatusTodo, UpdatedAt: now, DueAt: at(24 * time.Hour)},
+			wantOK: false,
+		},
+		{
+			name:     "overdue",
+			task:     Task{ID: 3, Status: StatusInProgress, UpdatedAt: now, DueAt: at(-time.Minute)},
+			wantKind: AlertOverdue,
+			wantOK:   true,
+		},
+		{
+			name:   "done tasks never alert",
+			task:   Task{ID: 4, Status: StatusDone, UpdatedAt: now, DueAt: at(-time.Hour)},
+			wantOK: false,
+		},
+		{
+			name:     "stale in-progress task",
+			task:     Task{ID: 5, Status: StatusInProgress, UpdatedAt: now.Add(-96 * time.Hour)},
+			wantKind: AlertStale,
+			wantOK:   true,
+		},
+		{
+			name:   "old todo task is not stale",
+			task:   Task{ID: 6, Status: StatusTodo, UpdatedAt: now.Add(-96 * time.Hour)},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert, ok := AlertFor(tt.task, now, thresholds)
+			if ok != tt.wantOK {
+				t.Fatalf("AlertFor() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && alert.Kind != tt.wantKind {
+				t.Errorf("AlertFor() kind = %v, want %v", alert.Kind, tt.wantKind)
+			}
+		})
+	}
+}
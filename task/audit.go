@@ -0,0 +1,56 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+)
+
+// AuditEvent records who changed a task, when, and what changed
+type AuditEvent struct {
+	Time        time.Time     `json:"time"`
+	Actor       string        `json:"actor"`
+	Action      string        `json:"action"`
+	TaskID      int           `json:"taskId"`
+	Description string        `json:"description"`
+	Changes     []FieldChange `json:"changes,omitempty"`
+}
+
+// String renders the event as one line, e.g. "mark-done #3: Call mom"
+func (e AuditEvent) String() string {
+	return fmt.Sprintf("%s #%d: %s", e.Action, e.TaskID, e.Description)
+}
+
+type actorKey struct{}
+
+// WithActor attaches the name of whoever is making changes to the context
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor attached to the context, or "unknown"
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := LookupActor(ctx); ok {
+		return actor
+	}
+	return "unknown"
+}
+
+// LookupActor returns the actor attached to the context, if there is one
+func LookupActor(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// CurrentActor names the local user: $TASK_CLI_USER or the login name
+func CurrentActor() string {
+	if actor := os.Getenv("TASK_CLI_USER"); actor != "" {
+		return actor
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
@@ -0,0 +1,235 @@
+package task
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChangeType describes how a task differs between two snapshots
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted"
+)
+
+// TaskChange is a single difference between two snapshots of the store
+type TaskChange struct {
+	Type     ChangeType `json:"type"`
+	Task     Task       `json:"task"`
+	Previous *Task      `json:"previous,omitempty"`
+}
+
+// FieldChange describes one field that differs between two task versions
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// DiffTasks compares two snapshots and returns the changes ordered by task ID
+func DiffTasks(before, after []Task) []TaskChange {
+	previous := make(map[int]Task, len(before))
+	for _, task := range before {
+		previous[task.ID] = task
+	}
+
+	var changes []TaskChange
+	for _, task := range after {
+		old, ok := previous[task.ID]
+		if !ok {
+			changes = append(changes, TaskChange{Type: ChangeAdded, Task: task})
+			continue
+		}
+		delete(previous, task.ID)
+
+		if !SameTask(old, task) {
+			changes = append(changes, TaskChange{Type: ChangeUpdated, Task: task, Previous: &old})
+		}
+	}
+
+	for _, task := range previous {
+		changes = append(changes, TaskChange{Type: ChangeDeleted, Task: task})
+	}
+
+	slices.SortStableFunc(changes, func(a, b TaskChange) int {
+		return a.Task.ID - b.Task.ID
+	})
+
+	return changes
+}
+
+// Fields lists the fields modified by an update, empty for other change types
+func (c TaskChange) Fields() []FieldChange {
+	if c.Type != ChangeUpdated || c.Previous == nil {
+		return nil
+	}
+	return ChangedFields(*c.Previous, c.Task)
+}
+
+// ChangedFields compares two versions of a task field by field
+func ChangedFields(old, new Task) []FieldChange {
+	var fields []FieldChange
+	add := func(field, a, b string) {
+		if a != b {
+			fields = append(fields, FieldChange{Field: field, Old: a, New: b})
+		}
+	}
+
+	add("description", old.Description, new.Description)
+	add("status", string(old.Status), string(new.Status))
+	add("createdAt", old.CreatedAt.Format(time.RFC3339), new.CreatedAt.Format(time.RFC3339))
+	add("updatedAt", old.UpdatedAt.Format(time.RFC3339), new.UpdatedAt.Format(time.RFC3339))
+	add("dueAt", FormatDue(old.DueAt), FormatDue(new.DueAt))
+	add("remindAt", FormatDue(old.RemindAt), FormatDue(new.RemindAt))
+	add("startAt", FormatDue(old.StartAt), FormatDue(new.StartAt))
+	add("priority", string(old.Priority), string(new.Priority))
+	add("tags", strings.Join(old.Tags, ","), strings.Join(new.Tags, ","))
+	add("notes", joinNotes(old.Notes), joinNotes(new.Notes))
+	add("checklist", JoinChecklist(old.Checklist), JoinChecklist(new.Checklist))
+	add("attachments", JoinAttachments(old.Attachments), JoinAttachments(new.Attachments))
+	add("relations", JoinRelations(old.Relations), JoinRelations(new.Relations))
+	add("list", old.List, new.List)
+	add("project", old.Project, new.Project)
+	add("milestone", old.Milestone, new.Milestone)
+	add("sprint", old.Sprint, new.Sprint)
+	add("assignee", old.Assignee, new.Assignee)
+	add("estimate", FormatEstimate(old.Estimate), FormatEstimate(new.Estimate))
+	add("pomodoros", strconv.Itoa(old.Pomodoros), strconv.Itoa(new.Pomodoros))
+
+	return fields
+}
+
+// SameTask reports whether two versions of a task hold the same data
+func SameTask(a, b Task) bool {
+	return a.ID == b.ID &&
+		a.UUID == b.UUID &&
+		a.Description == b.Description &&
+		a.Status == b.Status &&
+		a.CreatedAt.Equal(b.CreatedAt) &&
+		a.UpdatedAt.Equal(b.UpdatedAt) &&
+		FormatDue(a.DueAt) == FormatDue(b.DueAt) &&
+		FormatDue(a.RemindAt) == FormatDue(b.RemindAt) &&
+		FormatDue(a.StartAt) == FormatDue(b.StartAt) &&
+		FormatDue(a.StatusChangedAt) == FormatDue(b.StatusChangedAt) &&
+		a.Priority == b.Priority &&
+		slices.Equal(a.Tags, b.Tags) &&
+		joinNotes(a.Notes) == joinNotes(b.Notes) &&
+		slices.Equal(a.Checklist, b.Checklist) &&
+		slices.Equal(a.Attachments, b.Attachments) &&
+		slices.Equal(a.Relations, b.Relations) &&
+		a.List == b.List &&
+		a.Project == b.Project &&
+		a.Milestone == b.Milestone &&
+		a.Sprint == b.Sprint &&
+		a.Assignee == b.Assignee &&
+		a.Estimate == b.Estimate &&
+		a.Pomodoros == b.Pomodoros &&
+		a.CreatedBy == b.CreatedBy &&
+		a.UpdatedBy == b.UpdatedBy &&
+		a.Revision == b.Revision &&
+		maps.Equal(a.ExternalRefs, b.ExternalRefs)
+}
+
+// FormatDue renders an optional deadline for comparison and display
+func FormatDue(due *time.Time) string {
+	if due == nil {
+		return ""
+	}
+	return due.Format(time.RFC3339)
+}
+
+// FormatEstimate renders an optional estimate for comparison and display
+func FormatEstimate(estimate Duration) string {
+	if estimate == 0 {
+		return ""
+	}
+	return FormatDuration(time.Duration(estimate))
+}
+
+// joinNotes renders note texts for comparison and display
+func joinNotes(notes []Note) string {
+	texts := make([]string, len(notes))
+	for i, note := range notes {
+		texts[i] = note.Text
+	}
+	return strings.Join(texts, "; ")
+}
+
+// JoinChecklist renders checklist items for comparison and display, with
+// checked ones marked [x]
+func JoinChecklist(items []ChecklistItem) string {
+	texts := make([]string, len(items))
+	for i, item := range items {
+		mark := "[ ]"
+		if item.Done {
+			mark = "[x]"
+		}
+		texts[i] = mark + " " + item.Text
+	}
+	return strings.Join(texts, "; ")
+}
+
+// JoinAttachments renders attachment targets for comparison and display
+func JoinAttachments(attachments []Attachment) string {
+	texts := make([]string, len(attachments))
+	for i, attachment := range attachments {
+		texts[i] = attachment.Target
+		if attachment.Label != "" {
+			texts[i] = attachment.Label + " " + attachment.Target
+		}
+	}
+	return strings.Join(texts, "; ")
+}
+
+// JoinRelations renders relations for comparison and display
+func JoinRelations(relations []Relation) string {
+	texts := make([]string, len(relations))
+	for i, relation := range relations {
+		texts[i] = string(relation.Type) + " #" + strconv.Itoa(relation.Task)
+	}
+	return strings.Join(texts, "; ")
+}
+
+// ChangeVerb names a change after the command making it, such as add or
+// mark-done
+func ChangeVerb(change TaskChange) string {
+	switch change.Type {
+	case ChangeAdded:
+		return "add"
+	case ChangeDeleted:
+		return "delete"
+	}
+
+	verb := "edit"
+	for _, field := range change.Fields() {
+		switch field.Field {
+		case "status":
+			switch TaskStatus(field.New) {
+			case StatusDone:
+				return "mark-done"
+			case StatusInProgress:
+				return "mark-in-progress"
+			case StatusCancelled:
+				return "cancel"
+			default:
+				return "move"
+			}
+		case "description":
+			verb = "update"
+		case "dueAt":
+			if verb == "edit" {
+				verb = "due"
+			}
+		case "assignee":
+			if verb == "edit" {
+				verb = "assign"
+			}
+		}
+	}
+	return verb
+}
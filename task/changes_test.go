@@ -0,0 +1,57 @@
+package task_test
+
+import (
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestDiffTasks tests change detection between two snapshots
+func TestDiffTasks(t *testing.T) {
+	before := tasktest.MixedStatusTasks(t)
+
+	t.Run("identical snapshots", func(t *testing.T) {
+		if changes := DiffTasks(before, before); len(changes) != 0 {
+			t.Errorf("DiffTasks() returned %d changes, want 0", len(changes))
+		}
+	})
+
+	t.Run("added, updated and deleted", func(t *testing.T) {
+		after := []Task{before[0], before[1]}
+		after[1].Description = "Renamed"
+		after = append(after, *tasktest.TaskWithID(t, 4))
+
+		changes := DiffTasks(before, after)
+		want := []struct {
+			id   int
+			kind ChangeType
+		}{
+			{2, ChangeUpdated},
+			{3, ChangeDeleted},
+			{4, ChangeAdded},
+		}
+
+		if len(changes) != len(want) {
+			t.Fatalf("DiffTasks() returned %d changes, want %d", len(changes), len(want))
+		}
+		for i, w := range want {
+			if changes[i].Task.ID != w.id || changes[i].Type != w.kind {
+				t.Errorf("change %d = %s #%d, want %s #%d",
+					i, changes[i].Type, changes[i].Task.ID, w.kind, w.id)
+			}
+		}
+
+		if changes[0].Previous == nil || changes[0].Previous.Description != before[1].Description {
+			t.Errorf("updated change should carry the previous version")
+		}
+
+		fields := changes[0].Fields()
+		if len(fields) != 1 || fields[0].Field != "description" || fields[0].New != "Renamed" {
+			t.Errorf("Fields() = %+v, want a single description change", fields)
+		}
+		if changes[1].Fields() != nil {
+			t.Errorf("Fields() on a deletion should be empty")
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DayStats counts what happened to tasks on one day
+type DayStats struct {
+	Day time.Time
+	// Open is the number of open tasks at the end of the day
+	Open int
+	// Completed is the number of tasks marked done during the day
+	Completed int
+}
+
+// statusTimeline answers what status a task had at a given time
+type statusTimeline struct {
+	task Task
+	// changes are the audited status changes of the task, oldest first
+	changes []AuditEvent
+}
+
+// at returns the task's status at t and whether it existed then. Audited
+// status changes are exact; without them, a closed task is assumed to have
+// been open until its last update.
+func (s statusTimeline) at(t time.Time) (TaskStatus, bool) {
+	if t.Before(s.task.CreatedAt) {
+		return "", false
+	}
+	if len(s.changes) == 0 {
+		if !s.task.IsOpen() && t.Before(s.task.UpdatedAt) {
+			return StatusTodo, true
+		}
+		return s.task.Status, true
+	}
+
+	status := TaskStatus(statusChange(s.changes[0]).Old)
+	for _, event := range s.changes {
+		if event.Time.After(t) {
+			break
+		}
+		status = TaskStatus(statusChange(event).New)
+	}
+	return status, true
+}
+
+// completions returns when the task was marked done
+func (s statusTimeline) completions() []time.Time {
+	if len(s.changes) == 0 {
+		if s.task.Status == StatusDone {
+			return []time.Time{s.task.UpdatedAt}
+		}
+		return nil
+	}
+
+	var times []time.Time
+	for _, event := range s.changes {
+		if TaskStatus(statusChange(event).New) == StatusDone {
+			times = append(times, event.Time)
+		}
+	}
+	return times
+}
+
+// started returns when work on the task began: its first audited move out
+// of todo, or its creation
+func (s statusTimeline) started() time.Time {
+	for _, event := range s.changes {
+		if TaskStatus(statusChange(event).Old) == StatusTodo {
+			return event.Time
+		}
+	}
+	return s.task.CreatedAt
+}
+
+// buildTimelines pairs every task with its audited status changes. Events
+// older than a task are ignored, as they belong to a deleted task that had
+// the same ID.
+func buildTimelines(tasks []Task, events []AuditEvent) []statusTimeline {
+	timelines := make([]statusTimeline, len(tasks))
+	index := make(map[int]int, len(tasks))
+	for i, task := range tasks {
+		timelines[i] = statusTimeline{task: task}
+		index[task.ID] = i
+	}
+	for _, event := range events {
+		i, ok := index[event.TaskID]
+		if ok && statusChange(event).Field != "" && !event.Time.Before(tasks[i].CreatedAt) {
+			timelines[i].changes = append(timelines[i].changes, event)
+		}
+	}
+	for i := range timelines {
+		slices.SortStableFunc(timelines[i].changes, func(a, b AuditEvent) int {
+			return a.Time.Compare(b.Time)
+		})
+	}
+	return timelines
+}
+
+// statusChange returns the status field of an audit event
+func statusChange(event AuditEvent) FieldChange {
+	for _, change := range event.Changes {
+		if change.Field == "status" {
+			return change
+		}
+	}
+	return FieldChange{}
+}
+
+// BuildDayStats counts open and completed tasks for every day from since's
+// day to now's day, in now's location. events are audit events, used for
+// exact status history when available. Deleted tasks are not counted.
+func BuildDayStats(tasks []Task, events []AuditEvent, since, now time.Time) []DayStats {
+	timelines := buildTimelines(tasks, events)
+
+	loc := now.Location()
+	since = since.In(loc)
+	day := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, loc)
+	var stats []DayStats
+	for !day.After(now) {
+		next := day.AddDate(0, 0, 1)
+		end := next.Add(-time.Nanosecond)
+		if end.After(now) {
+			end = now
+		}
+
+		entry := DayStats{Day: day}
+		for _, timeline := range timelines {
+			if status, ok := timeline.at(end); ok && status != StatusDone && status != StatusCancelled {
+				entry.Open++
+			}
+			for _, done := range timeline.completions() {
+				if !done.Before(day) && done.Before(next) {
+					entry.Completed++
+				}
+			}
+		}
+		stats = append(stats, entry)
+		day = next
+	}
+	return stats
+}
+
+// sparkBlocks are the eighth-height bars of a sparkline
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as one line of block characters scaled to the
+// largest value, with a blank for zero
+func Sparkline(values []int) string {
+	top := slices.Max(append([]int{0}, values...))
+	var b strings.Builder
+	for _, value := range values {
+		switch {
+		case top == 0, value <= 0:
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(sparkBlocks[value*(len(sparkBlocks)-1)/top])
+		}
+	}
+	return b.String()
+}
+
+// RenderBarChart writes one dated horizontal bar per day, scaled so the
+// largest value spans width characters
+func RenderBarChart(w io.Writer, days []time.Time, values []int, width int) {
+	top := slices.Max(append([]int{0}, values...))
+	for i, value := range values {
+		bar := 0
+		if top > 0 {
+			bar = (value*width + top - 1) / top
+		}
+		fmt.Fprintf(w, "%s  %s %d\n", days[i].Format("2006-01-02"), strings.Repeat("█", bar), value)
+	}
+}
@@ -0,0 +1,68 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBuildDayStats tests daily open and completed counts
+func TestBuildDayStats(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2024, 1, d, hour, 0, 0, 0, time.UTC)
+	}
+	now := day(4, 12)
+
+	tasks := []Task{
+		{ID: 1, Status: StatusTodo, CreatedAt: day(1, 9), UpdatedAt: day(1, 9)},
+		{ID: 2, Status: StatusDone, CreatedAt: day(1, 9), UpdatedAt: day(2, 15)},
+		{ID: 3, Status: StatusCancelled, CreatedAt: day(2, 9), UpdatedAt: day(3, 9)},
+		// Done on the 2nd, reopened on the 3rd and done again on the 4th
+		{ID: 4, Status: StatusDone, CreatedAt: day(1, 9), UpdatedAt: day(4, 10)},
+	}
+	status := func(at time.Time, id int, old, new TaskStatus) AuditEvent {
+		return AuditEvent{Time: at, TaskID: id, Action: "move",
+			Changes: []FieldChange{{Field: "status", Old: string(old), New: string(new)}}}
+	}
+	events := []AuditEvent{
+		status(day(2, 10), 4, StatusTodo, StatusDone),
+		status(day(3, 10), 4, StatusDone, StatusTodo),
+		status(day(4, 10), 4, StatusTodo, StatusDone),
+		{Time: day(3, 11), TaskID: 1, Action: "update"},
+	}
+
+	got := BuildDayStats(tasks, events, day(1, 0), now)
+
+	want := []DayStats{
+		{Day: day(1, 0), Open: 3, Completed: 0},
+		{Day: day(2, 0), Open: 2, Completed: 2},
+		{Day: day(3, 0), Open: 2, Completed: 0},
+		{Day: day(4, 0), Open: 1, Completed: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d days, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Day.Equal(want[i].Day) || got[i].Open != want[i].Open || got[i].Completed != want[i].Completed {
+			t.Errorf("day %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestSparkline tests scaling values to block characters
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   string
+	}{
+		{[]int{0, 1, 4, 8}, " ▁▄█"},
+		{[]int{3, 3}, "██"},
+		{[]int{0, 0}, "  "},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Sparkline(tt.values); got != tt.want {
+			t.Errorf("Sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+package task
+
+import "time"
+
+// Clock returns the current time, injectable for deterministic output
+type Clock func() time.Time
@@ -0,0 +1,38 @@
+package task
+
+import (
+	"slices"
+	"strings"
+)
+
+// Contexts are GTD contexts, the places or tools a task needs such as
+// @home or @phone. They are stored as tags starting with "@", as todo.txt
+// does, but filtered on their own: a task without any context can be done
+// anywhere.
+
+// ContextTag normalizes a context name, adding the "@" when it is missing
+func ContextTag(name string) string {
+	tag := NormalizeTag(name)
+	if tag == "" || strings.HasPrefix(tag, "@") {
+		return tag
+	}
+	return "@" + tag
+}
+
+// Contexts returns the contexts of the task
+func (t Task) Contexts() []string {
+	var contexts []string
+	for _, tag := range t.Tags {
+		if strings.HasPrefix(tag, "@") {
+			contexts = append(contexts, tag)
+		}
+	}
+	return contexts
+}
+
+// InContext reports whether the task can be done in the context: it has
+// that context or none at all
+func (t Task) InContext(name string) bool {
+	contexts := t.Contexts()
+	return len(contexts) == 0 || slices.Contains(contexts, ContextTag(name))
+}
@@ -0,0 +1,13 @@
+package task
+
+import "testing"
+
+func TestTask_InContext(t *testing.T) {
+	task := Task{Tags: []string{"errand", "@town"}}
+	if !task.InContext("@town") || !task.InContext("town") || task.InContext("@home") {
+		t.Errorf("InContext() does not match the task's contexts %v", task.Contexts())
+	}
+	if free := (Task{Tags: []string{"errand"}}); !free.InContext("@home") {
+		t.Error("a task without contexts should fit every context")
+	}
+}
@@ -0,0 +1,187 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed five field cron expression:
+//
+//	minute hour day-of-month month day-of-week
+//
+// Fields accept "*", numbers, ranges ("1-5"), lists ("1,15") and steps
+// ("*/15"), plus month and weekday names ("jan", "mon"). As in cron, when
+// both day fields are restricted a day matching either one matches. The
+// macros @hourly, @daily, @weekly, @monthly and @yearly are also accepted.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// anyDOM and anyDOW record which day fields are "*" for the day matching rule
+	anyDOM, anyDOW bool
+}
+
+var cronMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+var cronMonths = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+var cronWeekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// cronSearchDays bounds the search for occurrences; it covers leap days
+const cronSearchDays = 8 * 366
+
+// ParseCron parses a cron expression
+func ParseCron(expr string) (CronSchedule, error) {
+	if macro, ok := cronMacros[strings.ToLower(strings.TrimSpace(expr))]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return CronSchedule{}, fmt.Errorf("invalid cron expression %q: want 5 fields", expr)
+	}
+
+	var c CronSchedule
+	for i, spec := range []struct {
+		into     *uint64
+		min, max int
+		names    []string
+		nameBase int
+	}{
+		{&c.minute, 0, 59, nil, 0},
+		{&c.hour, 0, 23, nil, 0},
+		{&c.dom, 1, 31, nil, 0},
+		{&c.month, 1, 12, cronMonths, 1},
+		{&c.dow, 0, 7, cronWeekdays, 0},
+	} {
+		bits, err := parseCronField(strings.ToLower(fields[i]), spec.min, spec.max, spec.names, spec.nameBase)
+		if err != nil {
+			return CronSchedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		*spec.into = bits
+	}
+
+	// 7 is another name for Sunday
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.anyDOM = fields[2] == "*"
+	c.anyDOW = fields[4] == "*"
+	return c, nil
+}
+
+func parseCronField(field string, min, max int, names []string, nameBase int) (uint64, error) {
+	value := func(s string) (int, error) {
+		for i, name := range names {
+			if s == name {
+				return i + nameBase, nil
+			}
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min || n > max {
+			return 0, fmt.Errorf("%q is not between %d and %d", s, min, max)
+		}
+		return n, nil
+	}
+
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = value(from); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = value(to); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = max
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		}
+
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << n
+		}
+	}
+	return bits, nil
+}
+
+// matchesDay reports whether the schedule runs on the day of t
+func (c CronSchedule) matchesDay(t time.Time) bool {
+	if c.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case c.anyDOM && c.anyDOW:
+		return true
+	case c.anyDOM:
+		return dow
+	case c.anyDOW:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Next returns the first occurrence strictly after t, in t's location
+func (c CronSchedule) Next(t time.Time) (time.Time, bool) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for range cronSearchDays {
+		if c.matchesDay(day) {
+			for h := 0; h < 24; h++ {
+				for m := 0; m < 60 && c.hour&(1<<h) != 0; m++ {
+					candidate := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, t.Location())
+					if c.minute&(1<<m) != 0 && candidate.After(t) {
+						return candidate, true
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}, false
+}
+
+// Previous returns the last occurrence at or before t, in t's location
+func (c CronSchedule) Previous(t time.Time) (time.Time, bool) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for range cronSearchDays {
+		if c.matchesDay(day) {
+			for h := 23; h >= 0; h-- {
+				for m := 59; m >= 0 && c.hour&(1<<h) != 0; m-- {
+					candidate := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, t.Location())
+					if c.minute&(1<<m) != 0 && !candidate.After(t) {
+						return candidate, true
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,77 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestParseCron tests valid and invalid cron expressions
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{"0 9 * * 1", false},
+		{"*/15 8-18 * * mon-fri", false},
+		{"0 0 1,15 * *", false},
+		{"30 6 * jan,jul sun", false},
+		{"0 0 * * 7", false},
+		{"@weekly", false},
+		{"0 9 * *", true},
+		{"60 9 * * *", true},
+		{"0 9 * * funday", true},
+		{"0 9 5-1 * *", true},
+		{"*/0 * * * *", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseCron(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCron(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestCronSchedule_NextPrevious tests finding occurrences around a time
+func TestCronSchedule_NextPrevious(t *testing.T) {
+	// tasktest.FixedTime is Monday 2024-01-01 12:00 UTC
+	now := tasktest.FixedTime()
+	date := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expr         string
+		wantPrevious time.Time
+		wantNext     time.Time
+	}{
+		{"0 9 * * mon", date(1, 9, 0), date(8, 9, 0)},
+		{"0 12 * * *", date(1, 12, 0), date(2, 12, 0)},
+		{"*/20 * * * *", date(1, 12, 0), date(1, 12, 20)},
+		{"0 9 * * 1-5", date(1, 9, 0), date(2, 9, 0)},
+		{"0 0 1 * *", date(1, 0, 0), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are restricted
+		{"0 8 15 * fri", time.Date(2023, 12, 29, 8, 0, 0, 0, time.UTC), date(5, 8, 0)},
+		{"0 0 29 2 *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, ok := cron.Previous(now); !ok || !got.Equal(tt.wantPrevious) {
+				t.Errorf("Previous() = %v, want %v", got, tt.wantPrevious)
+			}
+			if got, ok := cron.Next(now); !ok || !got.Equal(tt.wantNext) {
+				t.Errorf("Next() = %v, want %v", got, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Natural language date parsing shared by every command accepting a date
+
+var (
+	clockPattern    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+	durationPattern = regexp.MustCompile(`^(\d+)(w|d|h|m|s)`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseWhen parses a point in time relative to now. Dates without a time of
+// day resolve to midnight. Accepted forms include "2024-05-01",
+// "2024-05-01 14:30", RFC 3339, "today", "tomorrow 9am", "monday",
+// "in 2h", "+3d" and "3d ago".
+func ParseWhen(input string, now time.Time) (time.Time, error) {
+	t, _, err := parseWhen(input, now)
+	return t, err
+}
+
+// ParseDeadline parses like ParseWhen but resolves dates without a time of
+// day to the last second of that day, so "due today" is not already overdue
+func ParseDeadline(input string, now time.Time) (time.Time, error) {
+	t, dateOnly, err := parseWhen(input, now)
+	if err != nil {
+		return t, err
+	}
+	if dateOnly {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t, nil
+}
+
+// ParseDuration extends time.ParseDuration with day ("d") and week ("w") units
+func ParseDuration(input string) (time.Duration, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", input)
+	}
+
+	var total time.Duration
+	rest := s
+	for rest != "" {
+		match := durationPattern.FindStringSubmatch(rest)
+		if match == nil {
+			return 0, fmt.Errorf("invalid duration %q", input)
+		}
+		n, _ := strconv.Atoi(match[1])
+		unit := map[string]time.Duration{
+			"w": 7 * 24 * time.Hour,
+			"d": 24 * time.Hour,
+			"h": time.Hour,
+			"m": time.Minute,
+			"s": time.Second,
+		}[match[2]]
+		total += time.Duration(n) * unit
+		rest = rest[len(match[0]):]
+	}
+
+	return total, nil
+}
+
+func parseWhen(input string, now time.Time) (time.Time, bool, error) {
+	trimmed := strings.Join(strings.Fields(input), " ")
+	if trimmed == "" {
+		return time.Time{}, false, fmt.Errorf("empty date")
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, trimmed, now.Location()); err == nil {
+			return t, false, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", trimmed, now.Location()); err == nil {
+		return t, true, nil
+	}
+
+	s := strings.ToLower(trimmed)
+
+	switch {
+	case s == "now":
+		return now, false, nil
+	case strings.HasPrefix(s, "in "):
+		d, err := ParseDuration(strings.TrimPrefix(s, "in "))
+		return now.Add(d), false, err
+	case strings.HasPrefix(s, "+"):
+		d, err := ParseDuration(s[1:])
+		return now.Add(d), false, err
+	case strings.HasPrefix(s, "-"):
+		d, err := ParseDuration(s[1:])
+		return now.Add(-d), false, err
+	case strings.HasSuffix(s, " ago"):
+		d, err := ParseDuration(strings.TrimSuffix(s, " ago"))
+		return now.Add(-d), false, err
+	}
+
+	dayWord, clock, _ := strings.Cut(strings.TrimPrefix(s, "next "), " ")
+	clock = strings.TrimPrefix(clock, "at ")
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var day time.Time
+	switch dayWord {
+	case "today", "tonight":
+		day = midnight
+	case "tomorrow":
+		day = midnight.AddDate(0, 0, 1)
+	case "yesterday":
+		day = midnight.AddDate(0, 0, -1)
+	default:
+		weekday, ok := weekdays[dayWord]
+		if !ok {
+			// A bare time of day such as "9am" means today
+			if offset, err := parseClock(s); err == nil {
+				return midnight.Add(offset), false, nil
+			}
+			return time.Time{}, false, fmt.Errorf("unrecognized date %q", input)
+		}
+		days := (int(weekday) - int(now.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		day = midnight.AddDate(0, 0, days)
+	}
+
+	if clock == "" {
+		return day, true, nil
+	}
+
+	offset, err := parseClock(clock)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("unrecognized time %q in %q", clock, input)
+	}
+	return day.Add(offset), false, nil
+}
+
+// parseClock parses a time of day such as "9am", "9:30pm" or "14:00"
+func parseClock(s string) (time.Duration, error) {
+	match := clockPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute := 0
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+	switch match[3] {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	default:
+		if match[2] == "" {
+			// A bare number is ambiguous with a day count, require a suffix
+			return 0, fmt.Errorf("invalid time of day %q", s)
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// Duration is a time.Duration written as a string such as "90m" or "3d"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"3d\": %w", err)
+	}
+
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
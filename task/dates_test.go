@@ -0,0 +1,99 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestParseWhen tests natural language date parsing
+func TestParseWhen(t *testing.T) {
+	// Monday 2024-01-01 12:00 UTC
+	now := tasktest.FixedTime()
+
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{input: "2024-03-05", want: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{input: "2024-03-05 14:30", want: time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)},
+		{input: "2024-03-05T14:30:00Z", want: time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)},
+		{input: "now", want: now},
+		{input: "today", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{input: "tomorrow 9am", want: time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{input: "Tomorrow at 9:30pm", want: time.Date(2024, 1, 2, 21, 30, 0, 0, time.UTC)},
+		{input: "yesterday", want: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{input: "friday", want: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{input: "monday", want: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{input: "next wed 14:00", want: time.Date(2024, 1, 3, 14, 0, 0, 0, time.UTC)},
+		{input: "5pm", want: time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC)},
+		{input: "in 2h", want: now.Add(2 * time.Hour)},
+		{input: "+3d", want: now.AddDate(0, 0, 3)},
+		{input: "1w ago", want: now.AddDate(0, 0, -7)},
+		{input: "-1d12h", want: now.Add(-36 * time.Hour)},
+		{input: "", wantErr: true},
+		{input: "someday", wantErr: true},
+		{input: "tomorrow 25:00", wantErr: true},
+		{input: "tomorrow 9", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseWhen(tt.input, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseWhen(%q) = %v, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWhen(%q) unexpected error = %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseWhen(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseDeadline tests that date-only deadlines cover the whole day
+func TestParseDeadline(t *testing.T) {
+	got, err := ParseDeadline("today", tasktest.FixedTime())
+	if err != nil {
+		t.Fatalf("ParseDeadline() unexpected error = %v", err)
+	}
+	want := time.Date(2024, 1, 1, 23, 59, 59, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("ParseDeadline(today) = %v, want %v", got, want)
+	}
+
+	got, _ = ParseDeadline("tomorrow 9am", tasktest.FixedTime())
+	if want := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("ParseDeadline(tomorrow 9am) = %v, want %v", got, want)
+	}
+}
+
+// TestParseDuration tests durations with day and week units
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"90m":   90 * time.Minute,
+		"3d":    72 * time.Hour,
+		"1w":    7 * 24 * time.Hour,
+		"1d12h": 36 * time.Hour,
+	}
+	for input, want := range tests {
+		got, err := ParseDuration(input)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "3x", "d"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) should fail", input)
+		}
+	}
+}
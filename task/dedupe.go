@@ -0,0 +1,146 @@
+package task
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DedupeOptions chooses which tasks count as duplicates and which of them
+// is kept
+type DedupeOptions struct {
+	// SameTags also requires duplicates to have the same tags
+	SameTags bool
+	// KeepOldest keeps the task created first instead of the one furthest
+	// along in the workflow
+	KeepOldest bool
+	// DryRun finds the duplicates without saving
+	DryRun bool
+	// Now dates the notes recording the merge
+	Now time.Time
+}
+
+// DuplicateGroup is a task kept with the duplicates merged into it
+type DuplicateGroup struct {
+	Kept    Task
+	Removed []Task
+}
+
+// NormalizeDescription reduces a description to what tells tasks apart:
+// case, runs of spaces and trailing punctuation are ignored
+func NormalizeDescription(description string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(description), " "))
+	return strings.TrimRight(normalized, ".,;:!?… ")
+}
+
+// duplicateKey groups tasks that are duplicates of each other
+func duplicateKey(task Task, sameTags bool) string {
+	key := NormalizeDescription(task.Description)
+	if sameTags {
+		tags := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			tags[i] = NormalizeTag(tag)
+		}
+		slices.Sort(tags)
+		key += "\x00" + strings.Join(slices.Compact(tags), ",")
+	}
+	return key
+}
+
+// progressRank orders statuses by how far along a task is: done, then in
+// progress, then open statuses, and cancelled last
+func progressRank(status TaskStatus) int {
+	switch status {
+	case StatusDone:
+		return 3
+	case StatusInProgress:
+		return 2
+	case StatusCancelled:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// FindDuplicates groups tasks with the same normalized description, the
+// task to keep first, oldest groups first
+func FindDuplicates(tasks []Task, opts DedupeOptions) [][]Task {
+	groups := make(map[string][]Task)
+	var keys []string
+	for _, task := range tasks {
+		key := duplicateKey(task, opts.SameTags)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], task)
+	}
+
+	oldest := func(a, b Task) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	}
+	var duplicates [][]Task
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+		slices.SortFunc(group, func(a, b Task) int {
+			if !opts.KeepOldest {
+				if order := cmp.Compare(progressRank(b.Status), progressRank(a.Status)); order != 0 {
+					return order
+				}
+			}
+			return oldest(a, b)
+		})
+		duplicates = append(duplicates, group)
+	}
+	slices.SortFunc(duplicates, func(a, b []Task) int { return oldest(a[0], b[0]) })
+	return duplicates
+}
+
+// MergeDuplicates folds the notes, tags and other details of duplicates
+// into the task kept, filling only what it lacks, and notes which tasks
+// were merged so the trail of the removed ones is not lost
+func MergeDuplicates(kept Task, duplicates []Task, now time.Time) Task {
+	kept.Notes = slices.Clone(kept.Notes)
+	kept.ExternalRefs = maps.Clone(kept.ExternalRefs)
+	for _, dup := range duplicates {
+		for _, note := range dup.Notes {
+			if !slices.Contains(kept.Notes, note) {
+				kept.Notes = append(kept.Notes, note)
+			}
+		}
+		kept.Tags = MergeTags(kept.Tags, dup.Tags)
+		if dup.CreatedAt.Before(kept.CreatedAt) {
+			kept.CreatedAt = dup.CreatedAt
+		}
+		if dup.DueAt != nil && (kept.DueAt == nil || dup.DueAt.Before(*kept.DueAt)) {
+			kept.DueAt = dup.DueAt
+		}
+		if priorityRank(dup.Priority) > priorityRank(kept.Priority) {
+			kept.Priority = dup.Priority
+		}
+		kept.Pomodoros += dup.Pomodoros
+		kept.Estimate = cmp.Or(kept.Estimate, dup.Estimate)
+		kept.Project = cmp.Or(kept.Project, dup.Project)
+		kept.Milestone = cmp.Or(kept.Milestone, dup.Milestone)
+		kept.Sprint = cmp.Or(kept.Sprint, dup.Sprint)
+		kept.Assignee = cmp.Or(kept.Assignee, dup.Assignee)
+		for system, ref := range dup.ExternalRefs {
+			if _, ok := kept.ExternalRefs[system]; !ok {
+				if kept.ExternalRefs == nil {
+					kept.ExternalRefs = make(map[string]string)
+				}
+				kept.ExternalRefs[system] = ref
+			}
+		}
+		kept.Notes = append(kept.Notes, Note{CreatedAt: now,
+			Text: fmt.Sprintf("Merged duplicate task %d (%s)", dup.ID, dup.Status)})
+	}
+	slices.SortStableFunc(kept.Notes, func(a, b Note) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	kept.UpdatedAt = now
+	return kept
+}
@@ -0,0 +1,62 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+func TestNormalizeDescription(t *testing.T) {
+	tests := map[string]string{
+		"Buy milk":          "buy milk",
+		"  buy   MILK!  ":   "buy milk",
+		"Buy milk...":       "buy milk",
+		"Call mom (again)?": "call mom (again)",
+	}
+	for in, want := range tests {
+		if got := NormalizeDescription(in); got != want {
+			t.Errorf("NormalizeDescription(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func withTags(task *Task, tags ...string) Task {
+	task.Tags = tags
+	return *task
+}
+
+// TestFindDuplicates tests grouping and which task of a group is kept
+func TestFindDuplicates(t *testing.T) {
+	base := tasktest.FixedTime()
+	tasks := []Task{
+		withTags(tasktest.NewTaskBuilder().WithID(1).WithDescription("Buy milk").
+			WithTimestamps(base, base).BuildInvalid(), "errands"),
+		*tasktest.NewTaskBuilder().WithID(2).WithDescription("Write report").
+			WithTimestamps(base, base).BuildInvalid(),
+		*tasktest.NewTaskBuilder().WithID(3).WithDescription("buy milk.").WithStatus(StatusInProgress).
+			WithTimestamps(base.Add(time.Hour), base.Add(time.Hour)).BuildInvalid(),
+		withTags(tasktest.NewTaskBuilder().WithID(4).WithDescription("Buy  Milk").
+			WithTimestamps(base.Add(2*time.Hour), base.Add(2*time.Hour)).BuildInvalid(), "errands"),
+	}
+
+	groups := FindDuplicates(tasks, DedupeOptions{})
+	if len(groups) != 1 || len(groups[0]) != 3 {
+		t.Fatalf("groups = %v", groups)
+	}
+	if groups[0][0].ID != 3 {
+		t.Errorf("kept task %d, want the in-progress task 3", groups[0][0].ID)
+	}
+
+	groups = FindDuplicates(tasks, DedupeOptions{KeepOldest: true})
+	if groups[0][0].ID != 1 {
+		t.Errorf("kept task %d, want the oldest task 1", groups[0][0].ID)
+	}
+
+	// Task 3 has no tags, so only 1 and 4 match with --tags
+	groups = FindDuplicates(tasks, DedupeOptions{SameTags: true})
+	if len(groups) != 1 || len(groups[0]) != 2 || groups[0][0].ID != 1 || groups[0][1].ID != 4 {
+		t.Errorf("groups with same tags = %v", groups)
+	}
+}
@@ -0,0 +1,116 @@
+package task
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+	"time"
+)
+
+// Digest summarizes a day: what was completed the day before, what is due
+// today and what is already overdue
+type Digest struct {
+	Date      time.Time
+	Completed []Task
+	DueToday  []Task
+	Overdue   []Task
+}
+
+// BuildDigest collects the daily digest of now's day, using now's location
+// for day boundaries. Tasks due before today are overdue, not due today.
+func BuildDigest(tasks []Task, now time.Time) Digest {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	yesterday := today.AddDate(0, 0, -1)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	digest := Digest{Date: today}
+	for _, task := range tasks {
+		switch {
+		case task.Status == StatusDone:
+			if !task.UpdatedAt.Before(yesterday) && task.UpdatedAt.Before(today) {
+				digest.Completed = append(digest.Completed, task)
+			}
+		case !task.IsOpen() || task.DueAt == nil:
+		case task.DueAt.Before(today):
+			digest.Overdue = append(digest.Overdue, task)
+		case task.DueAt.Before(tomorrow):
+			digest.DueToday = append(digest.DueToday, task)
+		}
+	}
+	return digest
+}
+
+// Empty reports whether the digest has nothing to say
+func (d Digest) Empty() bool {
+	return len(d.Completed) == 0 && len(d.DueToday) == 0 && len(d.Overdue) == 0
+}
+
+// Subject is the title of the digest email
+func (d Digest) Subject() string {
+	return fmt.Sprintf("Task digest for %s: %d due today, %d overdue",
+		d.Date.Format("Mon Jan 2"), len(d.DueToday), len(d.Overdue))
+}
+
+// digestSection is one titled list of the digest
+type digestSection struct {
+	Title string
+	Tasks []Task
+}
+
+func (d Digest) sections() []digestSection {
+	return []digestSection{
+		{"Overdue", d.Overdue},
+		{"Due today", d.DueToday},
+		{"Completed yesterday", d.Completed},
+	}
+}
+
+var digestFuncs = map[string]any{
+	"due": func(task Task) string {
+		if task.DueAt == nil {
+			return ""
+		}
+		return task.DueAt.Format("Jan 2 15:04")
+	},
+}
+
+var digestText = template.Must(template.New("digest").Funcs(digestFuncs).Parse(
+	`{{.Subject}}
+{{range .Sections}}
+{{.Title}} ({{len .Tasks}})
+{{range .Tasks}}  #{{.ID}} {{.Description}}{{with due .}} (due {{.}}){{end}}
+{{else}}  nothing
+{{end}}{{end}}`))
+
+var digestHTML = htmltemplate.Must(htmltemplate.New("digest").Funcs(digestFuncs).Parse(
+	`<!doctype html>
+<html><body style="font-family: sans-serif">
+<h1 style="font-size: 18px">{{.Subject}}</h1>
+{{range .Sections}}<h2 style="font-size: 15px">{{.Title}} ({{len .Tasks}})</h2>
+{{if .Tasks}}<ul>
+{{range .Tasks}}<li>#{{.ID}} {{.Description}}{{with due .}} <small>(due {{.}})</small>{{end}}</li>
+{{end}}</ul>
+{{else}}<p>nothing</p>
+{{end}}{{end}}</body></html>
+`))
+
+// digestView is what the templates render
+type digestView struct {
+	Subject  string
+	Sections []digestSection
+}
+
+// Text renders the digest as plain text
+func (d Digest) Text() string {
+	var b bytes.Buffer
+	digestText.Execute(&b, digestView{d.Subject(), d.sections()})
+	return b.String()
+}
+
+// HTML renders the digest as an HTML page, escaping task descriptions
+func (d Digest) HTML() string {
+	var b bytes.Buffer
+	digestHTML.Execute(&b, digestView{d.Subject(), d.sections()})
+	return b.String()
+}
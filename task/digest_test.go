@@ -0,0 +1,83 @@
+package task_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestBuildDigest tests which tasks land in each section of the digest
+func TestBuildDigest(t *testing.T) {
+	now := tasktest.FixedTime()
+	at := func(hours int) *time.Time {
+		t := now.Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+	task := func(id int, status TaskStatus, updated time.Time, due *time.Time) Task {
+		return Task{ID: id, Description: "Task", Status: status, CreatedAt: updated, UpdatedAt: updated, DueAt: due}
+	}
+
+	tasks := []Task{
+		task(1, StatusDone, *at(-20), nil),     // completed yesterday
+		task(2, StatusDone, *at(-40), nil),     // completed two days ago
+		task(3, StatusDone, *at(-1), nil),      // completed today
+		task(4, StatusTodo, now, at(5)),        // due tonight
+		task(5, StatusInProgress, now, at(-2)), // due this morning
+		task(6, StatusTodo, now, at(-20)),      // due yesterday
+		task(7, StatusTodo, now, at(20)),       // due tomorrow
+		task(8, StatusCancelled, now, at(-20)), // cancelled
+		task(9, StatusDone, *at(-20), at(-30)), // done late
+		task(10, StatusTodo, now, nil),         // no deadline
+	}
+
+	digest := BuildDigest(tasks, now)
+
+	ids := func(tasks []Task) []int {
+		var ids []int
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+	for _, tt := range []struct {
+		name string
+		got  []Task
+		want []int
+	}{
+		{"completed", digest.Completed, []int{1, 9}},
+		{"due today", digest.DueToday, []int{4, 5}},
+		{"overdue", digest.Overdue, []int{6}},
+	} {
+		if got := ids(tt.got); !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestDigest_Render tests the text and HTML renderings
+func TestDigest_Render(t *testing.T) {
+	due := tasktest.FixedTime().Add(time.Hour)
+	digest := Digest{
+		Date:     tasktest.FixedTime(),
+		DueToday: []Task{{ID: 4, Description: "Fix <script>", DueAt: &due}},
+	}
+
+	text := digest.Text()
+	for _, want := range []string{
+		"Task digest for Mon Jan 1: 1 due today, 0 overdue",
+		"Due today (1)\n  #4 Fix <script> (due Jan 1 13:00)",
+		"Overdue (0)\n  nothing",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() = %q, want it to contain %q", text, want)
+		}
+	}
+
+	if html := digest.HTML(); !strings.Contains(html, "#4 Fix &lt;script&gt;") {
+		t.Errorf("HTML() = %q, want the description escaped", html)
+	}
+}
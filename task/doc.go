@@ -0,0 +1,9 @@
+// Package task holds the domain of the task tracker: the Task with its
+// statuses, priorities and notes, the Workflow that says which status
+// changes are allowed, and the rules built on them, such as filter queries,
+// shorthand and date parsing, tags, sprints, estimates and summaries.
+//
+// Tasks are plain values. Storing them is the business of package
+// repository, and changing them under the workflow the business of package
+// service.
+package task
@@ -0,0 +1,143 @@
+package task
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// underestimateRatio is how much longer than estimated tasks must take,
+	// on average, to count as underestimated
+	underestimateRatio = 1.5
+	// underestimateMinTasks keeps one slow task from flagging a whole tag
+	underestimateMinTasks = 3
+)
+
+// QuickEffort is the most effort next --quick picks
+const QuickEffort = 15 * time.Minute
+
+// effortSizes are the T-shirt sizes accepted in place of a duration
+var effortSizes = map[string]time.Duration{
+	"s": 15 * time.Minute,
+	"m": time.Hour,
+	"l": 4 * time.Hour,
+}
+
+// ParseEffort parses an estimate given as a T-shirt size, S (15m), M (1h)
+// or L (4h), or as a duration
+func ParseEffort(input string) (time.Duration, error) {
+	if size, ok := effortSizes[strings.ToLower(strings.TrimSpace(input))]; ok {
+		return size, nil
+	}
+	return ParseDuration(input)
+}
+
+// WeekEffort is the estimated effort of the tasks completed in one week
+type WeekEffort struct {
+	// Week is the Monday the week starts on
+	Week   time.Time
+	Tasks  int
+	Effort time.Duration
+}
+
+// BuildWeeklyEffort sums the estimates of the tasks completed in each week
+// from the week of since to that of now, counting a task in the week of its
+// last completion
+func BuildWeeklyEffort(tasks []Task, events []AuditEvent, since, now time.Time) []WeekEffort {
+	first := weekStart(since)
+	var weeks []WeekEffort
+	for week := first; !week.After(now); week = week.AddDate(0, 0, 7) {
+		weeks = append(weeks, WeekEffort{Week: week})
+	}
+
+	for _, timeline := range buildTimelines(tasks, events) {
+		completions := timeline.completions()
+		if timeline.task.Status != StatusDone || timeline.task.Estimate == 0 || len(completions) == 0 {
+			continue
+		}
+		done := completions[len(completions)-1]
+		i := int(weekStart(done).Sub(first).Hours() / (7 * 24))
+		if done.Before(first) || i >= len(weeks) {
+			continue
+		}
+		weeks[i].Tasks++
+		weeks[i].Effort += time.Duration(timeline.task.Estimate)
+	}
+	return weeks
+}
+
+// weekStart returns midnight on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// EstimateStats compares the estimates of completed tasks with the time
+// they actually took
+type EstimateStats struct {
+	// Tag is the tag the stats cover, empty for all tasks
+	Tag       string
+	Tasks     int
+	Estimated time.Duration
+	Actual    time.Duration
+}
+
+// Ratio is actual time over estimated time: 2 means tasks took twice as long
+func (e EstimateStats) Ratio() float64 {
+	if e.Estimated == 0 {
+		return 0
+	}
+	return float64(e.Actual) / float64(e.Estimated)
+}
+
+// Underestimated reports chronic underestimation: enough tasks taking
+// clearly longer than estimated
+func (e EstimateStats) Underestimated() bool {
+	return e.Tasks >= underestimateMinTasks && e.Ratio() >= underestimateRatio
+}
+
+// BuildEstimateReport compares estimates with actual time for tasks that
+// have an estimate and were completed since the given time. A task's actual
+// time runs from when work began (its first audited move out of todo, or
+// its creation) to its last completion. It returns the stats of all tasks
+// and of each tag, sorted by tag.
+func BuildEstimateReport(tasks []Task, events []AuditEvent, since time.Time) (EstimateStats, []EstimateStats) {
+	var all EstimateStats
+	byTag := make(map[string]*EstimateStats)
+
+	for _, timeline := range buildTimelines(tasks, events) {
+		task := timeline.task
+		completions := timeline.completions()
+		if task.Status != StatusDone || task.Estimate == 0 || len(completions) == 0 {
+			continue
+		}
+		done := completions[len(completions)-1]
+		if done.Before(since) {
+			continue
+		}
+		actual := max(done.Sub(timeline.started()), 0)
+
+		add := func(stats *EstimateStats) {
+			stats.Tasks++
+			stats.Estimated += time.Duration(task.Estimate)
+			stats.Actual += actual
+		}
+		add(&all)
+		for _, tag := range task.Tags {
+			if byTag[tag] == nil {
+				byTag[tag] = &EstimateStats{Tag: tag}
+			}
+			add(byTag[tag])
+		}
+	}
+
+	tags := make([]EstimateStats, 0, len(byTag))
+	for _, stats := range byTag {
+		tags = append(tags, *stats)
+	}
+	slices.SortFunc(tags, func(a, b EstimateStats) int {
+		return strings.Compare(a.Tag, b.Tag)
+	})
+	return all, tags
+}
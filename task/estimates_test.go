@@ -0,0 +1,90 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestBuildEstimateReport tests comparing estimates with actual time
+func TestBuildEstimateReport(t *testing.T) {
+	start := tasktest.FixedTime()
+	task := func(id int, estimate, took time.Duration, tags ...string) Task {
+		return Task{ID: id, Status: StatusDone, Tags: tags, Estimate: Duration(estimate),
+			CreatedAt: start, UpdatedAt: start.Add(took)}
+	}
+
+	tasks := []Task{
+		task(1, time.Hour, 3*time.Hour, "work"),
+		task(2, time.Hour, 2*time.Hour, "work"),
+		task(3, 2*time.Hour, 4*time.Hour, "work", "home"),
+		task(4, 4*time.Hour, 2*time.Hour, "home"),
+		task(5, 0, 5*time.Hour, "work"), // no estimate
+		{ID: 6, Status: StatusTodo, Estimate: Duration(time.Hour), CreatedAt: start, UpdatedAt: start},
+		// Created long before work began on it
+		{ID: 7, Status: StatusDone, Estimate: Duration(time.Hour), Tags: []string{"home"},
+			CreatedAt: start.Add(-240 * time.Hour), UpdatedAt: start.Add(time.Hour)},
+	}
+	events := []AuditEvent{{
+		Time: start, TaskID: 7, Action: "mark-in-progress",
+		Changes: []FieldChange{{Field: "status", Old: "todo", New: "in-progress"}},
+	}, {
+		Time: start.Add(time.Hour), TaskID: 7, Action: "mark-done",
+		Changes: []FieldChange{{Field: "status", Old: "in-progress", New: "done"}},
+	}}
+
+	all, byTag := BuildEstimateReport(tasks, events, start.Add(-time.Hour))
+
+	if all.Tasks != 5 || all.Estimated != 9*time.Hour || all.Actual != 12*time.Hour {
+		t.Errorf("all = %+v, want 5 tasks, 9h estimated, 12h actual", all)
+	}
+	if len(byTag) != 2 || byTag[0].Tag != "home" || byTag[1].Tag != "work" {
+		t.Fatalf("byTag = %+v, want home and work", byTag)
+	}
+	if home := byTag[0]; home.Tasks != 3 || home.Actual != 7*time.Hour || home.Underestimated() {
+		t.Errorf("home = %+v, want 3 tasks, 7h actual, not underestimated", home)
+	}
+	if work := byTag[1]; work.Tasks != 3 || work.Ratio() != 2.25 || !work.Underestimated() {
+		t.Errorf("work = %+v (ratio %v), want 3 tasks underestimated by 2.25x", work, work.Ratio())
+	}
+}
+
+func TestParseEffort(t *testing.T) {
+	tests := map[string]time.Duration{"S": 15 * time.Minute, "m": time.Hour, "L": 4 * time.Hour, "45m": 45 * time.Minute}
+	for input, want := range tests {
+		if got, err := ParseEffort(input); err != nil || got != want {
+			t.Errorf("ParseEffort(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseEffort("XXL"); err == nil {
+		t.Error("ParseEffort(XXL) should fail")
+	}
+}
+
+// TestBuildWeeklyEffort tests summing completed effort by week
+func TestBuildWeeklyEffort(t *testing.T) {
+	monday := tasktest.FixedTime()
+	done := func(id int, estimate time.Duration, at time.Time) Task {
+		return Task{ID: id, Status: StatusDone, Estimate: Duration(estimate), CreatedAt: monday.AddDate(0, 0, -30), UpdatedAt: at}
+	}
+	tasks := []Task{
+		done(1, time.Hour, monday.AddDate(0, 0, -5)), // Wednesday of the week before
+		done(2, 15*time.Minute, monday.AddDate(0, 0, -1)),
+		done(3, 2*time.Hour, monday.Add(time.Hour)),
+		done(4, 0, monday.Add(time.Hour)), // no estimate
+	}
+
+	weeks := BuildWeeklyEffort(tasks, nil, monday.AddDate(0, 0, -7), monday.Add(2*time.Hour))
+	if len(weeks) != 2 {
+		t.Fatalf("weeks = %+v, want 2", weeks)
+	}
+	if !weeks[0].Week.Equal(time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC)) ||
+		weeks[0].Tasks != 2 || weeks[0].Effort != 75*time.Minute {
+		t.Errorf("first week = %+v, want 2 tasks, 1h15m from 2023-12-25", weeks[0])
+	}
+	if weeks[1].Tasks != 1 || weeks[1].Effort != 2*time.Hour {
+		t.Errorf("second week = %+v, want 1 task, 2h", weeks[1])
+	}
+}
@@ -0,0 +1,40 @@
+package task_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alnah/task-tracker/task"
+)
+
+func ExampleParseTaskLine() {
+	draft := task.ParseTaskLine("(B) Call mom +family @phone")
+	t, err := task.NewTask(1, draft.Description, draft.Options...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(t.Description, t.Priority, t.Tags)
+	// Output:
+	// Call mom high [family @phone]
+}
+
+func ExampleParseQuery() {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	query, err := task.ParseQuery("status != done && (tag:work || priority >= high)", task.QueryEnv{Now: now, Workflow: task.DefaultWorkflow()})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, line := range []string{"Write report +work", "(A) Call the bank", "Buy milk"} {
+		draft := task.ParseTaskLine(line)
+		t, err := task.NewTask(1, draft.Description, draft.Options...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(t.Description, query.Match(*t))
+	}
+	// Output:
+	// Write report true
+	// Call the bank true
+	// Buy milk false
+}
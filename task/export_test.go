@@ -0,0 +1,4 @@
+package task
+
+// TruncateDescription lets the external tests reach truncateDescription
+var TruncateDescription = truncateDescription
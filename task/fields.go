@@ -0,0 +1,106 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FieldEdit is one field=value assignment of the set command. Op is "=",
+// or "+=" and "-=" to add and remove tags.
+type FieldEdit struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// settableFields lists the fields set can change, in the order reported
+var settableFields = []string{"description", "priority", "due", "tags", "assignee", "estimate"}
+
+// FieldError reports why an edit of one field was refused
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ParseFieldEdit reads a field=value, tags+=value or tags-=value argument
+func ParseFieldEdit(arg string) (FieldEdit, error) {
+	i := strings.Index(arg, "=")
+	if i <= 0 {
+		return FieldEdit{}, fmt.Errorf("%q is not field=value", arg)
+	}
+	edit := FieldEdit{Field: strings.ToLower(arg[:i]), Op: "=", Value: arg[i+1:]}
+	if op := edit.Field[len(edit.Field)-1:]; op == "+" || op == "-" {
+		edit.Field, edit.Op = edit.Field[:len(edit.Field)-1], op+"="
+	}
+	if edit.Field == "tag" {
+		edit.Field = "tags"
+	}
+	return edit, nil
+}
+
+// ApplyEdit changes one field of the task; due and estimate accept "none"
+// to clear them, dates are read relative to now and descriptions tidied
+// with the rules
+func (t *Task) ApplyEdit(edit FieldEdit, now time.Time, rules DescriptionRules) error {
+	if edit.Op != "=" && edit.Field != "tags" {
+		return fmt.Errorf("only tags can be added to or removed from, use %s=", edit.Field)
+	}
+
+	switch edit.Field {
+	case "description":
+		return t.UpdateDescription(edit.Value, rules)
+	case "priority":
+		priority := Priority(strings.ToLower(edit.Value))
+		if priority == "none" {
+			priority = PriorityNone
+		}
+		return t.SetPriority(priority)
+	case "due":
+		if edit.Value == "none" || edit.Value == "" {
+			t.SetDue(nil)
+			return nil
+		}
+		due, err := ParseDeadline(edit.Value, now)
+		if err != nil {
+			return err
+		}
+		t.SetDue(&due)
+	case "tags":
+		tags := strings.FieldsFunc(edit.Value, func(r rune) bool { return r == ',' })
+		switch edit.Op {
+		case "+=":
+			t.Retag(tags, nil)
+		case "-=":
+			t.Retag(nil, tags)
+		default:
+			t.Tags = nil
+			t.Retag(tags, nil)
+		}
+	case "assignee":
+		if edit.Value == "none" {
+			edit.Value = ""
+		}
+		t.Assign(edit.Value)
+	case "estimate":
+		if edit.Value == "none" || edit.Value == "" {
+			return t.SetEstimate(0)
+		}
+		estimate, err := ParseEffort(edit.Value)
+		if err != nil {
+			return err
+		}
+		return t.SetEstimate(estimate)
+	default:
+		return fmt.Errorf("unknown field, expected one of %s", strings.Join(settableFields, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package task
+
+import "testing"
+
+func TestParseFieldEdit(t *testing.T) {
+	tests := map[string]FieldEdit{
+		"priority=high":     {Field: "priority", Op: "=", Value: "high"},
+		"tags+=api,backend": {Field: "tags", Op: "+=", Value: "api,backend"},
+		"tag-=old":          {Field: "tags", Op: "-=", Value: "old"},
+		"description=a=b":   {Field: "description", Op: "=", Value: "a=b"},
+		"due=":              {Field: "due", Op: "=", Value: ""},
+	}
+	for arg, want := range tests {
+		if got, err := ParseFieldEdit(arg); err != nil || got != want {
+			t.Errorf("ParseFieldEdit(%q) = %+v, %v, want %+v", arg, got, err, want)
+		}
+	}
+	if _, err := ParseFieldEdit("priority"); err == nil {
+		t.Error("ParseFieldEdit without = succeeded")
+	}
+}
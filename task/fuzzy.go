@@ -0,0 +1,64 @@
+package task
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FuzzyScore reports whether every character of query appears in text in
+// order, ignoring case, as fzf matches, and scores the match: characters
+// that follow each other or start a word score higher. An empty query
+// matches everything with a score of zero.
+func FuzzyScore(query, text string) (int, bool) {
+	query, text = strings.ToLower(query), strings.ToLower(text)
+	q := []rune(strings.Join(strings.Fields(query), ""))
+	score, next := 0, 0
+	prev := -2
+	runes := []rune(text)
+	for i, r := range runes {
+		if next == len(q) {
+			break
+		}
+		if r != q[next] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 3
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 2
+		}
+		prev = i
+		next++
+	}
+	return score, next == len(q)
+}
+
+// FuzzyFilter returns the tasks whose ID, description or tags match query,
+// best match first and by ID among equals
+func FuzzyFilter(tasks []Task, query string) []Task {
+	type scored struct {
+		task  Task
+		score int
+	}
+	var matches []scored
+	for _, task := range tasks {
+		text := "#" + strconv.Itoa(task.ID) + " " + task.Description + " " + strings.Join(task.Tags, " ")
+		if score, ok := FuzzyScore(query, text); ok {
+			matches = append(matches, scored{task, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b scored) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.task.ID, b.task.ID))
+	})
+
+	tasks = make([]Task, len(matches))
+	for i, match := range matches {
+		tasks[i] = match.task
+	}
+	return tasks
+}
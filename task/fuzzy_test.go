@@ -0,0 +1,43 @@
+package task
+
+import "testing"
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query, text string
+		ok          bool
+	}{
+		{"", "anything", true},
+		{"wrep", "Write report", true},
+		{"WR", "write report", true},
+		{"rw", "write", false},
+		{"write report", "Write report", true},
+		{"xyz", "Write report", false},
+	}
+	for _, tt := range tests {
+		if _, ok := FuzzyScore(tt.query, tt.text); ok != tt.ok {
+			t.Errorf("FuzzyScore(%q, %q) matched = %v, want %v", tt.query, tt.text, ok, tt.ok)
+		}
+	}
+
+	prefix, _ := FuzzyScore("rep", "report the bug")
+	scattered, _ := FuzzyScore("rep", "read the paper")
+	if prefix <= scattered {
+		t.Errorf("consecutive match scored %d, not above scattered %d", prefix, scattered)
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	tasks := []Task{
+		{ID: 1, Description: "Read the paper"},
+		{ID: 2, Description: "Report the bug"},
+		{ID: 3, Description: "Buy milk", Tags: []string{"errand"}},
+	}
+	got := FuzzyFilter(tasks, "rep")
+	if len(got) != 2 || got[0].ID != 2 || got[1].ID != 1 {
+		t.Errorf("FuzzyFilter(rep) = %+v, want #2 then #1", got)
+	}
+	if got := FuzzyFilter(tasks, "errand"); len(got) != 1 || got[0].ID != 3 {
+		t.Errorf("FuzzyFilter(errand) = %+v, want the task tagged errand", got)
+	}
+}
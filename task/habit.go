@@ -0,0 +1,119 @@
+package task
+
+import (
+	"strings"
+	"time"
+)
+
+// habitSearchLimit bounds how many past occurrences streaks are counted over
+const habitSearchLimit = 3660
+
+// HabitCompletion records that one occurrence of a schedule was done
+type HabitCompletion struct {
+	Schedule   string    `json:"schedule"`
+	Occurrence time.Time `json:"occurrence"`
+	DoneAt     time.Time `json:"doneAt"`
+}
+
+// HabitDay is one day of a habit's calendar
+type HabitDay struct {
+	Date time.Time
+	// Scheduled reports whether an occurrence fell on the day
+	Scheduled bool
+	Done      bool
+}
+
+// Habit is a schedule seen as a habit: how many occurrences in a row were
+// done, and which days over the last weeks
+type Habit struct {
+	Name string
+	// Current counts the latest occurrences done in a row. The latest
+	// occurrence only breaks it once the next one has come.
+	Current int
+	Best    int
+	// Days run from the Monday of the first week shown to today
+	Days []HabitDay
+}
+
+// HabitCompletions returns the completions of scheduled tasks among
+// changes: tasks linked to a schedule occurrence that became done
+func HabitCompletions(changes []TaskChange) []HabitCompletion {
+	var completions []HabitCompletion
+	for _, change := range changes {
+		if change.Type == ChangeDeleted || change.Task.Status != StatusDone ||
+			change.Previous != nil && change.Previous.Status == StatusDone {
+			continue
+		}
+		if completion, ok := HabitCompletionOf(change.Task); ok {
+			completions = append(completions, completion)
+		}
+	}
+	return completions
+}
+
+// HabitCompletionOf reads the schedule occurrence a done task was created for
+func HabitCompletionOf(task Task) (HabitCompletion, bool) {
+	ref := task.ExternalRefs[ScheduleRefSystem]
+	i := strings.LastIndex(ref, "@")
+	if i <= 0 {
+		return HabitCompletion{}, false
+	}
+	occurrence, err := time.Parse(time.RFC3339, ref[i+1:])
+	if err != nil {
+		return HabitCompletion{}, false
+	}
+	return HabitCompletion{Schedule: ref[:i], Occurrence: occurrence, DoneAt: task.UpdatedAt}, true
+}
+
+// BuildHabit counts the streaks of a schedule whose done occurrences are
+// given as Unix times, and lays out its calendar over the last weeks in
+// now's location
+func BuildHabit(schedule Schedule, done map[int64]bool, weeks int, now time.Time) Habit {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := today.AddDate(0, 0, -(int(today.Weekday())+6)%7-7*(max(weeks, 1)-1))
+
+	earliest := start
+	for at := range done {
+		if completed := time.Unix(at, 0); completed.Before(earliest) {
+			earliest = completed
+		}
+	}
+
+	habit := Habit{Name: schedule.Name}
+	days := make(map[time.Time]*HabitDay)
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		habit.Days = append(habit.Days, HabitDay{Date: day})
+	}
+	for i := range habit.Days {
+		days[habit.Days[i].Date] = &habit.Days[i]
+	}
+
+	run, counting := 0, true
+	occurrence, ok := schedule.Cron.Previous(now)
+	for i := 0; ok && !occurrence.Before(earliest) && i < habitSearchLimit; i++ {
+		isDone := done[occurrence.Unix()]
+		local := occurrence.In(now.Location())
+		if day, found := days[time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, now.Location())]; found {
+			day.Scheduled = true
+			day.Done = day.Done || isDone
+		}
+
+		switch {
+		case isDone:
+			run++
+			habit.Best = max(habit.Best, run)
+		case i == 0:
+			// The latest occurrence may still be done
+		default:
+			if counting {
+				habit.Current, counting = run, false
+			}
+			run = 0
+		}
+		occurrence, ok = schedule.Cron.Previous(occurrence.Add(-time.Minute))
+	}
+	if counting {
+		habit.Current = run
+	}
+	return habit
+}
@@ -0,0 +1,50 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+func dailyHabit(t *testing.T) Schedule {
+	t.Helper()
+	cron, err := ParseCron("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Schedule{Name: "stretch", Cron: cron, Description: "Stretch"}
+}
+
+func TestBuildHabit(t *testing.T) {
+	schedule := dailyHabit(t)
+	done := map[int64]bool{}
+	for d := 27; d <= 40; d++ {
+		if d != 35 {
+			done[time.Date(2023, time.December, d, 9, 0, 0, 0, time.UTC).Unix()] = true
+		}
+	}
+	// Dec 27 to Jan 3 in a row, Jan 4 missed, Jan 5 to 9 in a row and Jan
+	// 10 not done yet
+	now := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+	habit := BuildHabit(schedule, done, 2, now)
+	if habit.Current != 5 || habit.Best != 8 {
+		t.Errorf("streaks = %d current, %d best, want 5 and 8", habit.Current, habit.Best)
+	}
+	if len(habit.Days) != 10 || !habit.Days[0].Date.Equal(tasktest.FixedTime().Truncate(24*time.Hour)) {
+		t.Fatalf("days = %+v, want Jan 1 to 10", habit.Days)
+	}
+	if jan4 := habit.Days[3]; !jan4.Scheduled || jan4.Done {
+		t.Errorf("Jan 4 = %+v, want scheduled and missed", jan4)
+	}
+	if today := habit.Days[9]; !today.Scheduled || today.Done {
+		t.Errorf("Jan 10 = %+v, want scheduled and open", today)
+	}
+
+	// Once the next occurrence comes, the missed one breaks the streak
+	habit = BuildHabit(schedule, done, 2, now.AddDate(0, 0, 1))
+	if habit.Current != 0 || habit.Best != 8 {
+		t.Errorf("streaks after a miss = %d current, %d best, want 0 and 8", habit.Current, habit.Best)
+	}
+}
@@ -0,0 +1,11 @@
+package task
+
+import "time"
+
+// HistoryEntry is one recorded change to a task
+type HistoryEntry struct {
+	Revision string
+	Time     time.Time
+	Message  string
+	Change   TaskChange
+}
@@ -0,0 +1,58 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestHumanizeTime tests relative descriptions of timestamps
+func TestHumanizeTime(t *testing.T) {
+	now := tasktest.FixedTime()
+
+	tests := []struct {
+		offset time.Duration
+		want   string
+	}{
+		{0, "just now"},
+		{-30 * time.Second, "just now"},
+		{-time.Minute, "1 minute ago"},
+		{-45 * time.Minute, "45 minutes ago"},
+		{-3 * time.Hour, "3 hours ago"},
+		{-3 * 24 * time.Hour, "3 days ago"},
+		{-65 * 24 * time.Hour, "2 months ago"},
+		{-800 * 24 * time.Hour, "2 years ago"},
+		{2 * time.Hour, "in 2 hours"},
+		{24 * time.Hour, "in 1 day"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := HumanizeTime(now.Add(tt.offset), now); got != tt.want {
+				t.Errorf("HumanizeTime(%v) = %q, want %q", tt.offset, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatDuration tests compact duration formatting
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{20 * time.Second, "0m"},
+		{45 * time.Minute, "45m"},
+		{2*time.Hour + 30*time.Minute, "2h30m"},
+		{26 * time.Hour, "1d2h"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
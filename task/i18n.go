@@ -0,0 +1,202 @@
+package task
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Message Catalog
+//
+// What the CLI prints is written in English in the code, and the English
+// text is the key a translation is looked up by, as with gettext. Each
+// language has a catalog under locales/ holding:
+//
+//	messages  English message -> translation, trailing newlines left out
+//	plurals   English plural form -> one form per plural category
+//	statuses  status name -> the label shown for it
+//
+// Messages without a translation print in English. Only the messages of
+// the code are looked up, never what they are formatted with, so a task
+// described "Error" stays "Error". Status names typed on the command line are not
+// translated, only the labels printed for them.
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// DefaultLanguage is used when neither the config file nor the environment
+// name a language with a catalog
+const DefaultLanguage = "en"
+
+// Catalog holds the translations of one language
+type Catalog struct {
+	Messages map[string]string   `json:"messages"`
+	Plurals  map[string][]string `json:"plurals"`
+	Statuses map[string]string   `json:"statuses"`
+}
+
+// Translator renders messages in one language. A nil Translator prints
+// English.
+type Translator struct {
+	lang    string
+	catalog Catalog
+}
+
+// Languages lists the languages with a catalog
+func Languages() []string {
+	entries, _ := localeFiles.ReadDir("locales")
+	langs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		langs = append(langs, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	return langs
+}
+
+// NewTranslator loads the catalog of lang, falling back to English when
+// there is none
+func NewTranslator(lang string) (*Translator, error) {
+	data, err := localeFiles.ReadFile("locales/" + lang + ".json")
+	if err != nil {
+		lang = DefaultLanguage
+		if data, err = localeFiles.ReadFile("locales/" + lang + ".json"); err != nil {
+			return nil, err
+		}
+	}
+	t := &Translator{lang: lang}
+	if err := json.Unmarshal(data, &t.catalog); err != nil {
+		return nil, fmt.Errorf("failed to read %s messages: %w", lang, err)
+	}
+	return t, nil
+}
+
+// DetectLanguage picks the language to print in: the one set in the config
+// file, or else the first of $LC_ALL, $LC_MESSAGES and $LANG that is set,
+// reduced to its language code ("fr_FR.UTF-8" is "fr")
+func DetectLanguage(configured string, getenv func(string) string) string {
+	value := configured
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value != "" {
+			break
+		}
+		value = getenv(name)
+	}
+	lang, _, _ := strings.Cut(value, ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	lang = strings.ToLower(lang)
+	// The C and POSIX locales ask for untranslated output
+	if lang == "" || lang == "c" || lang == "posix" {
+		return DefaultLanguage
+	}
+	return lang
+}
+
+// Language returns the language messages are printed in
+func (t *Translator) Language() string {
+	if t == nil {
+		return DefaultLanguage
+	}
+	return t.lang
+}
+
+// Text translates a message
+func (t *Translator) Text(message string) string {
+	if t == nil {
+		return message
+	}
+	body := strings.TrimRight(message, "\n")
+	if translated, ok := t.catalog.Messages[body]; ok {
+		return translated + message[len(body):]
+	}
+	return message
+}
+
+// Sprintf formats a translated format string. Only the format is
+// translated: arguments are printed as they are, since they hold user data
+// such as descriptions. Pass errors through Error first.
+func (t *Translator) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(t.Text(format), args...)
+}
+
+// Error translates the message of err. Each error of a chain such as
+// "failed to load tasks: Task not found: 9" is translated on its own, and
+// only the message it was created with, not the details appended to it.
+func (t *Translator) Error(err error) string {
+	message := err.Error()
+	if t == nil {
+		return message
+	}
+	if inner := errors.Unwrap(err); inner != nil {
+		if head, ok := strings.CutSuffix(message, ": "+inner.Error()); ok {
+			return t.leading(head) + ": " + t.Error(inner)
+		}
+	}
+	return t.leading(message)
+}
+
+// leading translates the longest start of message, ending before a ": ",
+// that the catalog holds, and keeps the rest, such as an ID, as it is
+func (t *Translator) leading(message string) string {
+	for end := len(message); end > 0; end = strings.LastIndex(message[:end], ": ") {
+		if translated, ok := t.catalog.Messages[message[:end]]; ok {
+			return translated + message[end:]
+		}
+	}
+	return message
+}
+
+// Plural picks the form of a message for a count of n. other is the English
+// plural form, which is also the key of the translated forms.
+func (t *Translator) Plural(n int, other string) string {
+	if t == nil {
+		t = english
+	}
+	forms, ok := t.catalog.Plurals[strings.TrimRight(other, "\n")]
+	if !ok && t != english {
+		return english.Plural(n, other)
+	}
+	if !ok {
+		return other
+	}
+	form := min(pluralCategory(t.lang, n), len(forms)-1)
+	return forms[form] + other[len(strings.TrimRight(other, "\n")):]
+}
+
+// Status returns the label printed for a status
+func (t *Translator) Status(status TaskStatus) string {
+	if t != nil {
+		if label, ok := t.catalog.Statuses[string(status)]; ok {
+			return label
+		}
+	}
+	return string(status)
+}
+
+// pluralCategory returns which plural form lang uses for n: 0 for the
+// singular, 1 for the plural
+func pluralCategory(lang string, n int) int {
+	switch lang {
+	// French uses the singular for zero as well
+	case "fr":
+		if n == 0 || n == 1 {
+			return 0
+		}
+		return 1
+	default:
+		if n == 1 {
+			return 0
+		}
+		return 1
+	}
+}
+
+// english holds the singular forms of English plurals
+var english = func() *Translator {
+	t, err := NewTranslator(DefaultLanguage)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}()
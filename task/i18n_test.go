@@ -0,0 +1,112 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestDetectLanguage tests the config file and environment precedence
+func TestDetectLanguage(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+	tests := []struct {
+		configured string
+		env        map[string]string
+		want       string
+	}{
+		{"es", map[string]string{"LANG": "fr_FR.UTF-8"}, "es"},
+		{"", map[string]string{"LANG": "fr_FR.UTF-8"}, "fr"},
+		{"", map[string]string{"LC_ALL": "es_MX", "LANG": "fr_FR.UTF-8"}, "es"},
+		{"", map[string]string{"LC_MESSAGES": "pt-BR"}, "pt"},
+		{"", map[string]string{"LANG": "C.UTF-8"}, "en"},
+		{"", nil, "en"},
+	}
+	for _, tt := range tests {
+		if got := DetectLanguage(tt.configured, env(tt.env)); got != tt.want {
+			t.Errorf("DetectLanguage(%q, %v) = %q, want %q", tt.configured, tt.env, got, tt.want)
+		}
+	}
+}
+
+// TestTranslator tests message, wrapped error and plural translation, and
+// the English fallback
+func TestTranslator(t *testing.T) {
+	fr, err := NewTranslator("fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped := fmt.Errorf("failed to load tasks: %w", ErrTaskNotFound.Withf("%d", 9))
+	if got := fr.Sprintf("Error: %s\n", fr.Error(wrapped)); got != "Erreur : impossible de charger les tâches: Tâche introuvable: 9\n" {
+		t.Errorf("Sprintf(wrapped error) = %q", got)
+	}
+	if got := fr.Error(ErrTaskNotFound.Withf("Warning")); got != "Tâche introuvable: Warning" {
+		t.Errorf("Error(details) = %q, want the details untranslated", got)
+	}
+	if got := fr.Sprintf("Description: %s", "Warning: Task not found"); got != "Description : Warning: Task not found" {
+		t.Errorf("Sprintf(user data) = %q, want the argument untranslated", got)
+	}
+	if got := fr.Text("Task added by a plugin"); got != "Task added by a plugin" {
+		t.Errorf("Text(untranslated) = %q", got)
+	}
+	if got := fr.Status(StatusInProgress); got != "en cours" {
+		t.Errorf("Status(in-progress) = %q", got)
+	}
+
+	es, _ := NewTranslator("es")
+	tests := []struct {
+		tr   *Translator
+		n    int
+		want string
+	}{
+		{fr, 0, "0 tâche supprimée"},
+		{fr, 1, "1 tâche supprimée"},
+		{fr, 2, "2 tâches supprimées"},
+		{es, 0, "0 tareas eliminadas"},
+		{es, 1, "1 tarea eliminada"},
+		{nil, 1, "1 task deleted successfully"},
+		{nil, 3, "3 tasks deleted successfully"},
+	}
+	for _, tt := range tests {
+		if got := tt.tr.Sprintf(tt.tr.Plural(tt.n, "%d tasks deleted successfully\n"), tt.n); got != tt.want+"\n" {
+			t.Errorf("%s Plural(%d) = %q, want %q", tt.tr.Language(), tt.n, got, tt.want)
+		}
+	}
+
+	de, err := NewTranslator("de")
+	if err != nil || de.Language() != "en" {
+		t.Errorf("NewTranslator(de) = %v, %v; want the English fallback", de.Language(), err)
+	}
+}
+
+// TestCatalogs tests that every translation keeps the formatting verbs of
+// its message
+func TestCatalogs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9]*[a-zA-Z%]`)
+	same := func(a, b string) bool {
+		return strings.Join(verbs.FindAllString(a, -1), " ") == strings.Join(verbs.FindAllString(b, -1), " ")
+	}
+	for _, lang := range Languages() {
+		tr, err := NewTranslator(lang)
+		if err != nil || tr.Language() != lang {
+			t.Fatalf("NewTranslator(%s) = %v, %v", lang, tr.Language(), err)
+		}
+		for message, translation := range tr.catalog.Messages {
+			if !same(message, translation) {
+				t.Errorf("%s: %q translates to %q, with other verbs", lang, message, translation)
+			}
+		}
+		for message, forms := range tr.catalog.Plurals {
+			if len(forms) != 2 {
+				t.Errorf("%s: %q has %d plural forms, want 2", lang, message, len(forms))
+			}
+			for _, form := range forms {
+				if !same(message, form) {
+					t.Errorf("%s: %q has the plural form %q, with other verbs", lang, message, form)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package task
+
+import "strings"
+
+// TaskDraft describes a task to add: its description plus options such as
+// tags and priority
+type TaskDraft struct {
+	Description string
+	Options     []TaskOption
+}
+
+// ParseTaskLine reads one line of todo.txt style shorthand:
+//
+//	(A) call mom +home @phone
+//
+// A leading "(A)" to "(D)" sets the priority (A is urgent, B high, C medium,
+// D and later low). Words starting with "+" become tags, and words starting
+// with "@" become tags keeping the "@", as todo.txt contexts do. Every other
+// word is kept in the description.
+func ParseTaskLine(line string) TaskDraft {
+	words := strings.Fields(line)
+
+	var draft TaskDraft
+	if len(words) > 0 {
+		if priority, ok := ParseLetterPriority(words[0]); ok {
+			draft.Options = append(draft.Options, WithPriority(priority))
+			words = words[1:]
+		}
+	}
+
+	var description, tags []string
+	for _, word := range words {
+		if len(word) > 1 && word[0] == '+' {
+			tags = append(tags, word[1:])
+			continue
+		}
+		if len(word) > 1 && word[0] == '@' {
+			tags = append(tags, word)
+			continue
+		}
+		description = append(description, word)
+	}
+	if len(tags) > 0 {
+		draft.Options = append(draft.Options, WithTags(tags...))
+	}
+
+	draft.Description = strings.Join(description, " ")
+	return draft
+}
+
+// ParseLetterPriority maps a todo.txt "(A)" priority onto the task priorities
+func ParseLetterPriority(word string) (Priority, bool) {
+	if len(word) != 3 || word[0] != '(' || word[2] != ')' || word[1] < 'A' || word[1] > 'Z' {
+		return PriorityNone, false
+	}
+
+	switch word[1] {
+	case 'A':
+		return PriorityUrgent, true
+	case 'B':
+		return PriorityHigh, true
+	case 'C':
+		return PriorityMedium, true
+	default:
+		return PriorityLow, true
+	}
+}
@@ -0,0 +1,44 @@
+package task
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseTaskLine tests the todo.txt style shorthand
+func TestParseTaskLine(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		wantDesc     string
+		wantPriority Priority
+		wantTags     string
+	}{
+		{"plain", "Buy groceries", "Buy groceries", PriorityNone, ""},
+		{"priority and tag", "(A) call mom +home", "call mom", PriorityUrgent, "home"},
+		{"context", "(C) Review PR @work +Team", "Review PR", PriorityMedium, "@work,team"},
+		{"late letter", "(Q) someday", "someday", PriorityLow, ""},
+		{"lowercase is text", "(a) not a priority", "(a) not a priority", PriorityNone, ""},
+		{"lone plus is text", "1 + 1", "1 + 1", PriorityNone, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draft := ParseTaskLine(tt.line)
+			if draft.Description != tt.wantDesc {
+				t.Errorf("Description = %q, want %q", draft.Description, tt.wantDesc)
+			}
+
+			task, err := NewTask(1, draft.Description, draft.Options...)
+			if err != nil {
+				t.Fatalf("NewTask() failed: %v", err)
+			}
+			if task.Priority != tt.wantPriority {
+				t.Errorf("Priority = %q, want %q", task.Priority, tt.wantPriority)
+			}
+			if got := strings.Join(task.Tags, ","); got != tt.wantTags {
+				t.Errorf("Tags = %q, want %q", got, tt.wantTags)
+			}
+		})
+	}
+}
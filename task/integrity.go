@@ -0,0 +1,168 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IssueKind classifies a data integrity problem
+type IssueKind string
+
+const (
+	IssueInvalidJSON      IssueKind = "invalid-json"
+	IssueChecksumMismatch IssueKind = "checksum-mismatch"
+	IssueLegacySchema     IssueKind = "legacy-schema"
+	IssueDuplicateID      IssueKind = "duplicate-id"
+	IssueInvalidID        IssueKind = "invalid-id"
+	IssueZeroTimestamp    IssueKind = "zero-timestamp"
+	IssueTimestampOrder   IssueKind = "timestamp-order"
+	IssueUnknownStatus    IssueKind = "unknown-status"
+	IssueEmptyDescription IssueKind = "empty-description"
+)
+
+// Issue is a problem found in stored tasks. TaskID is zero for problems
+// affecting the whole file.
+type Issue struct {
+	Kind    IssueKind
+	TaskID  int
+	Message string
+	Fixable bool
+}
+
+func (i Issue) String() string {
+	if i.TaskID == 0 {
+		return fmt.Sprintf("[%s] %s", i.Kind, i.Message)
+	}
+	return fmt.Sprintf("[%s] task %d: %s", i.Kind, i.TaskID, i.Message)
+}
+
+// CheckTasks reports every integrity problem in a task list, accepting the
+// statuses of the workflow
+func CheckTasks(tasks []Task, workflow Workflow) []Issue {
+	var issues []Issue
+
+	counts := make(map[int]int)
+	for _, task := range tasks {
+		counts[task.ID]++
+	}
+	reported := make(map[int]bool)
+
+	for _, task := range tasks {
+		switch {
+		case task.ID <= 0:
+			issues = append(issues, Issue{Kind: IssueInvalidID, TaskID: task.ID,
+				Message: fmt.Sprintf("%q has no valid ID", task.Description), Fixable: true})
+		case counts[task.ID] > 1 && !reported[task.ID]:
+			reported[task.ID] = true
+			issues = append(issues, Issue{Kind: IssueDuplicateID, TaskID: task.ID,
+				Message: fmt.Sprintf("ID is used by %d tasks", counts[task.ID]), Fixable: true})
+		}
+
+		if task.CreatedAt.IsZero() {
+			issues = append(issues, Issue{Kind: IssueZeroTimestamp, TaskID: task.ID,
+				Message: "missing creation time", Fixable: true})
+		}
+		if task.UpdatedAt.IsZero() {
+			issues = append(issues, Issue{Kind: IssueZeroTimestamp, TaskID: task.ID,
+				Message: "missing update time", Fixable: true})
+		}
+		if !task.CreatedAt.IsZero() && !task.UpdatedAt.IsZero() && task.UpdatedAt.Before(task.CreatedAt) {
+			issues = append(issues, Issue{Kind: IssueTimestampOrder, TaskID: task.ID,
+				Message: "updated before it was created", Fixable: true})
+		}
+		if !workflow.Has(task.Status) {
+			issues = append(issues, Issue{Kind: IssueUnknownStatus, TaskID: task.ID,
+				Message: fmt.Sprintf("unknown status %q", task.Status), Fixable: true})
+		}
+		if task.Description == "" {
+			issues = append(issues, Issue{Kind: IssueEmptyDescription, TaskID: task.ID,
+				Message: "description is empty"})
+		}
+	}
+
+	return issues
+}
+
+// RepairTasks fixes every repairable problem and returns the repaired tasks
+// along with a description of each change. Duplicate IDs keep their first
+// occurrence; later ones are renumbered after the highest ID.
+func RepairTasks(tasks []Task, workflow Workflow, now time.Time) ([]Task, []Issue) {
+	repaired := make([]Task, len(tasks))
+	copy(repaired, tasks)
+
+	maxID := 0
+	for _, task := range repaired {
+		maxID = max(maxID, task.ID)
+	}
+
+	var fixes []Issue
+	seen := make(map[int]bool)
+	for i := range repaired {
+		task := &repaired[i]
+
+		if task.ID <= 0 || seen[task.ID] {
+			maxID++
+			kind := IssueDuplicateID
+			if task.ID <= 0 {
+				kind = IssueInvalidID
+			}
+			fixes = append(fixes, Issue{Kind: kind, TaskID: task.ID,
+				Message: fmt.Sprintf("renumbered %q to %d", task.Description, maxID), Fixable: true})
+			task.ID = maxID
+		}
+		seen[task.ID] = true
+
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = task.UpdatedAt
+			if task.CreatedAt.IsZero() {
+				task.CreatedAt = now
+			}
+			fixes = append(fixes, Issue{Kind: IssueZeroTimestamp, TaskID: task.ID,
+				Message: "set creation time to " + task.CreatedAt.Format(time.RFC3339), Fixable: true})
+		}
+		if task.UpdatedAt.IsZero() {
+			task.UpdatedAt = task.CreatedAt
+			fixes = append(fixes, Issue{Kind: IssueZeroTimestamp, TaskID: task.ID,
+				Message: "set update time to " + task.UpdatedAt.Format(time.RFC3339), Fixable: true})
+		}
+		if task.UpdatedAt.Before(task.CreatedAt) {
+			task.UpdatedAt = task.CreatedAt
+			fixes = append(fixes, Issue{Kind: IssueTimestampOrder, TaskID: task.ID,
+				Message: "set update time to creation time", Fixable: true})
+		}
+		if !workflow.Has(task.Status) {
+			fixes = append(fixes, Issue{Kind: IssueUnknownStatus, TaskID: task.ID,
+				Message: fmt.Sprintf("reset status %q to %s", task.Status, StatusTodo), Fixable: true})
+			task.Status = StatusTodo
+		}
+	}
+
+	return repaired, fixes
+}
+
+// StoreInspector is implemented by repositories that can report problems
+// with the stored data itself, such as corrupt files or stale formats
+type StoreInspector interface {
+	// Inspect loads tasks without failing on recoverable problems and
+	// returns them along with the problems found
+	Inspect(ctx context.Context) ([]Task, []Issue, error)
+}
+
+// DoctorOptions controls what Doctor does with the problems it finds
+type DoctorOptions struct {
+	// Repair fixes repairable problems and saves the result
+	Repair bool
+	// DryRun computes repairs without saving them
+	DryRun bool
+	// Now fills in missing timestamps
+	Now time.Time
+}
+
+// DoctorReport lists the problems found and, when repairing, what was done
+type DoctorReport struct {
+	Issues    []Issue
+	Fixes     []Issue
+	Remaining []Issue
+	Saved     bool
+}
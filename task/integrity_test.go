@@ -0,0 +1,81 @@
+package task_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+func issueKinds(issues []Issue) []IssueKind {
+	kinds := make([]IssueKind, len(issues))
+	for i, issue := range issues {
+		kinds[i] = issue.Kind
+	}
+	return kinds
+}
+
+// TestCheckTasks tests detection of integrity problems
+func TestCheckTasks(t *testing.T) {
+	t.Run("valid tasks have no issues", func(t *testing.T) {
+		if issues := CheckTasks(tasktest.FixedTasks(t), DefaultWorkflow()); len(issues) != 0 {
+			t.Errorf("CheckTasks() = %v, want none", issues)
+		}
+	})
+
+	t.Run("reports every problem", func(t *testing.T) {
+		got := issueKinds(CheckTasks(tasktest.BrokenTasks(), DefaultWorkflow()))
+		want := []IssueKind{
+			IssueDuplicateID, IssueZeroTimestamp, IssueZeroTimestamp,
+			IssueTimestampOrder, IssueUnknownStatus,
+		}
+		if strings.Join(kindStrings(got), ",") != strings.Join(kindStrings(want), ",") {
+			t.Errorf("CheckTasks() kinds = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("empty description is not fixable", func(t *testing.T) {
+		task := *tasktest.NewTaskBuilder().WithID(1).WithDescription("").
+			WithTimestamps(tasktest.FixedTime(), tasktest.FixedTime()).BuildInvalid()
+		issues := CheckTasks([]Task{task}, DefaultWorkflow())
+		if len(issues) != 1 || issues[0].Kind != IssueEmptyDescription || issues[0].Fixable {
+			t.Errorf("CheckTasks() = %v, want one unfixable empty-description issue", issues)
+		}
+	})
+}
+
+func kindStrings(kinds []IssueKind) []string {
+	s := make([]string, len(kinds))
+	for i, kind := range kinds {
+		s[i] = string(kind)
+	}
+	return s
+}
+
+// TestRepairTasks tests that repairs leave no fixable problems behind
+func TestRepairTasks(t *testing.T) {
+	now := tasktest.TimeAfter(tasktest.FixedTime())
+	tasks := tasktest.BrokenTasks()
+
+	repaired, fixes := RepairTasks(tasks, DefaultWorkflow(), now)
+	if len(fixes) != 5 {
+		t.Errorf("RepairTasks() made %d fixes, want 5: %v", len(fixes), fixes)
+	}
+	if issues := CheckTasks(repaired, DefaultWorkflow()); len(issues) != 0 {
+		t.Errorf("CheckTasks() after repair = %v, want none", issues)
+	}
+
+	if repaired[0].ID != 1 || repaired[1].ID != 5 {
+		t.Errorf("duplicate renumbered to IDs %d, %d, want 1, 5", repaired[0].ID, repaired[1].ID)
+	}
+	if !repaired[2].CreatedAt.Equal(now) || !repaired[2].UpdatedAt.Equal(now) {
+		t.Errorf("missing timestamps = %v, %v, want %v", repaired[2].CreatedAt, repaired[2].UpdatedAt, now)
+	}
+	if repaired[4].Status != StatusTodo {
+		t.Errorf("unknown status repaired to %q, want %q", repaired[4].Status, StatusTodo)
+	}
+	if tasks[1].ID != 1 {
+		t.Error("RepairTasks() modified its input")
+	}
+}
@@ -0,0 +1,37 @@
+package task
+
+import "strings"
+
+// Lists split one task file into named lists, such as work, personal and
+// groceries. A task's List names its list; tasks without one belong to the
+// default list. IDs stay unique across lists, so a task keeps its ID when
+// it moves to another list.
+
+// DefaultListName names the default list unless the config file renames it
+const DefaultListName = "default"
+
+// ListSummary counts the tasks of a list
+type ListSummary struct {
+	Name  string
+	Open  int
+	Total int
+}
+
+// ValidateListName trims a list name, which cannot be empty or contain
+// spaces
+func ValidateListName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return "", ErrInvalidList
+	}
+	return name, nil
+}
+
+// InList reports whether the task belongs to the named list, given the name
+// of the default list
+func (t Task) InList(name, defaultList string) bool {
+	if name == defaultList {
+		return t.List == "" || t.List == defaultList
+	}
+	return t.List == name
+}
@@ -0,0 +1,503 @@
+package task
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TaskOption sets an optional property while a task is being created
+type TaskOption func(*Task) error
+
+// WithDueDate sets the deadline of a new task
+func WithDueDate(due time.Time) TaskOption {
+	return func(t *Task) error {
+		t.DueAt = &due
+		return nil
+	}
+}
+
+// WithEstimate sets how long a new task is expected to take
+func WithEstimate(estimate time.Duration) TaskOption {
+	return func(t *Task) error {
+		if estimate < 0 {
+			return ErrInvalidEstimate
+		}
+		t.Estimate = Duration(estimate)
+		return nil
+	}
+}
+
+// WithPriority sets the priority of a new task
+func WithPriority(priority Priority) TaskOption {
+	return func(t *Task) error {
+		if !priority.IsValid() {
+			return ErrInvalidPriority
+		}
+		t.Priority = priority
+		return nil
+	}
+}
+
+// WithTags adds normalized, de-duplicated tags to a new task
+func WithTags(tags ...string) TaskOption {
+	return func(t *Task) error {
+		t.Tags = MergeTags(t.Tags, tags)
+		return nil
+	}
+}
+
+// WithAssignee sets who is expected to do a new task
+func WithAssignee(user string) TaskOption {
+	return func(t *Task) error {
+		t.Assignee = strings.TrimSpace(user)
+		return nil
+	}
+}
+
+// WithProject files a new task under a project
+func WithProject(name string) TaskOption {
+	return func(t *Task) error {
+		t.Project = strings.TrimSpace(name)
+		return nil
+	}
+}
+
+// WithMilestone plans a new task for a milestone
+func WithMilestone(name string) TaskOption {
+	return func(t *Task) error {
+		t.Milestone = strings.TrimSpace(name)
+		return nil
+	}
+}
+
+// NewTask creates a new task with validation
+func NewTask(id int, description string, opts ...TaskOption) (*Task, error) {
+	if strings.TrimSpace(description) == "" {
+		return nil, ErrEmptyDescription
+	}
+
+	now := time.Now()
+	task := &Task{
+		ID:          id,
+		UUID:        NewUUID(),
+		Description: strings.TrimSpace(description),
+		Status:      StatusTodo,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Revision:    1,
+	}
+
+	for _, opt := range opts {
+		if err := opt(task); err != nil {
+			return nil, err
+		}
+	}
+
+	return task, nil
+}
+
+// UpdateDescription updates the task description, tidied with the rules
+func (t *Task) UpdateDescription(description string, rules DescriptionRules) error {
+	if err := t.SetDescription(description, rules); err != nil {
+		return err
+	}
+	t.touch()
+	return nil
+}
+
+// MarkInProgress changes task status to in-progress
+func (t *Task) MarkInProgress() {
+	t.setStatus(StatusInProgress)
+}
+
+// MarkDone changes task status to done
+func (t *Task) MarkDone() {
+	t.setStatus(StatusDone)
+}
+
+// setStatus moves the task to status, recording when
+func (t *Task) setStatus(status TaskStatus) {
+	t.Status = status
+	t.touch()
+	changed := t.UpdatedAt
+	t.StatusChangedAt = &changed
+}
+
+// StatusSince returns when the task moved to its current status. Tasks that
+// have not moved since StatusChangedAt was recorded fall back to their
+// creation while in todo, and to their last update otherwise.
+func (t Task) StatusSince() time.Time {
+	switch {
+	case t.StatusChangedAt != nil:
+		return *t.StatusChangedAt
+	case t.Status == StatusTodo:
+		return t.CreatedAt
+	default:
+		return t.UpdatedAt
+	}
+}
+
+// MoveTo changes the task status to any status of the workflow the current
+// status may move to
+func (t *Task) MoveTo(status TaskStatus, workflow Workflow) error {
+	if !workflow.Has(status) {
+		return TaskError{
+			Code:    ErrInvalidStatus.Code,
+			Message: fmt.Sprintf("%s %q", ErrInvalidStatus.Message, status),
+		}
+	}
+	if status == t.Status {
+		return nil
+	}
+	if !workflow.CanMove(t.Status, status) {
+		return ErrInvalidStatus.Withf("cannot move task %d from %s to %s", t.ID, t.Status, status).
+			WithHint("add --force to make the move anyway")
+	}
+	if done, total := t.ChecklistProgress(); status == StatusDone && workflow.RequireChecklist && done < total {
+		return ErrChecklistOpen.Withf("task %d has %d of %d items left", t.ID, total-done, total)
+	}
+
+	t.setStatus(status)
+	return nil
+}
+
+// Cancel closes the task without doing it, recording the reason as a note
+func (t *Task) Cancel(reason string, workflow Workflow, now time.Time) error {
+	if err := t.MoveTo(StatusCancelled, workflow); err != nil {
+		return err
+	}
+	if reason = strings.TrimSpace(reason); reason != "" {
+		t.Notes = append(slices.Clone(t.Notes), Note{CreatedAt: now, Text: "Cancelled: " + reason})
+	}
+	return nil
+}
+
+// Assign sets who is expected to do the task, or clears it with ""
+func (t *Task) Assign(user string) {
+	t.Assignee = strings.TrimSpace(user)
+	t.touch()
+}
+
+// SetProject files the task under a project, or removes it from one with ""
+func (t *Task) SetProject(name string) {
+	t.Project = strings.TrimSpace(name)
+	t.touch()
+}
+
+// SetMilestone plans the task for a milestone, or unplans it with ""
+func (t *Task) SetMilestone(name string) {
+	t.Milestone = strings.TrimSpace(name)
+	t.touch()
+}
+
+// SetSprint commits the task to a sprint, or takes it out with ""
+func (t *Task) SetSprint(name string) {
+	t.Sprint = strings.TrimSpace(name)
+	t.touch()
+}
+
+// SetDue sets or clears (nil) the task deadline
+func (t *Task) SetDue(due *time.Time) {
+	t.DueAt = due
+	t.touch()
+}
+
+// SetPriority changes how urgent the task is, PriorityNone clearing it
+func (t *Task) SetPriority(priority Priority) error {
+	if !priority.IsValid() {
+		return ErrInvalidPriority
+	}
+	t.Priority = priority
+	t.touch()
+	return nil
+}
+
+// Retag adds and removes tags, normalizing them as WithTags does
+func (t *Task) Retag(add, remove []string) {
+	tags := MergeTags(t.Tags, add)
+	for _, tag := range remove {
+		tags = slices.DeleteFunc(tags, func(existing string) bool { return existing == NormalizeTag(tag) })
+	}
+	t.Tags = tags
+	t.touch()
+}
+
+// ReplaceTags swaps any of the from tags for to, which takes the place of
+// the first one found, and reports whether the task had one of them
+func (t *Task) ReplaceTags(from []string, to string) bool {
+	i := slices.IndexFunc(t.Tags, func(tag string) bool { return slices.Contains(from, tag) })
+	if i < 0 {
+		return false
+	}
+	tags := make([]string, 0, len(t.Tags))
+	for j, tag := range t.Tags {
+		switch {
+		case j == i:
+			tags = append(tags, to)
+		case !slices.Contains(from, tag):
+			tags = append(tags, tag)
+		}
+	}
+	t.Tags = MergeTags(nil, tags)
+	t.touch()
+	return true
+}
+
+// SetEstimate sets how long the task is expected to take, zero to clear it
+func (t *Task) SetEstimate(estimate time.Duration) error {
+	if estimate < 0 {
+		return ErrInvalidEstimate
+	}
+	t.Estimate = Duration(estimate)
+	t.touch()
+	return nil
+}
+
+// AddCheckItem appends a step to the checklist and returns its number,
+// counting from 1
+func (t *Task) AddCheckItem(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, ErrInvalidCheckItem
+	}
+	// Copy so earlier snapshots sharing the slice keep their items
+	t.Checklist = append(slices.Clone(t.Checklist), ChecklistItem{Text: text})
+	t.touch()
+	return len(t.Checklist), nil
+}
+
+// ToggleCheckItem checks or unchecks the nth step, counting from 1, and
+// returns it
+func (t *Task) ToggleCheckItem(n int) (ChecklistItem, error) {
+	if n < 1 || n > len(t.Checklist) {
+		return ChecklistItem{}, ErrCheckItemNotFound.Withf("task %d has no item %d", t.ID, n)
+	}
+	t.Checklist = slices.Clone(t.Checklist)
+	t.Checklist[n-1].Done = !t.Checklist[n-1].Done
+	t.touch()
+	return t.Checklist[n-1], nil
+}
+
+// ChecklistProgress counts the checked steps and all steps
+func (t Task) ChecklistProgress() (done, total int) {
+	for _, item := range t.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(t.Checklist)
+}
+
+// Attach links a URL or file to the task and returns its number, counting
+// from 1
+func (t *Task) Attach(target, label string) (int, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return 0, ErrInvalidAttachment
+	}
+	// Copy so earlier snapshots sharing the slice keep their attachments
+	t.Attachments = append(slices.Clone(t.Attachments), Attachment{Target: target, Label: strings.TrimSpace(label)})
+	t.touch()
+	return len(t.Attachments), nil
+}
+
+// Attachment returns the nth attachment, counting from 1
+func (t Task) Attachment(n int) (Attachment, error) {
+	if n < 1 || n > len(t.Attachments) {
+		return Attachment{}, ErrAttachmentNotFound.Withf("task %d has no attachment %d", t.ID, n)
+	}
+	return t.Attachments[n-1], nil
+}
+
+// AddPomodoro counts one more completed work interval
+func (t *Task) AddPomodoro() {
+	t.Pomodoros++
+	t.touch()
+}
+
+// Snooze hides the task until the given time, or wakes it (nil)
+func (t *Task) Snooze(until *time.Time) {
+	t.StartAt = until
+	t.touch()
+}
+
+// IsSnoozed reports whether the task is hidden until later than now
+func (t Task) IsSnoozed(now time.Time) bool {
+	return t.StartAt != nil && t.StartAt.After(now)
+}
+
+// SetReminder sets or clears (nil) when to be reminded of the task
+func (t *Task) SetReminder(at *time.Time) {
+	t.RemindAt = at
+	t.touch()
+}
+
+// SetExternalRef links the task to an item in another system
+func (t *Task) SetExternalRef(system, ref string) {
+	// Copy so earlier snapshots sharing the map keep their links
+	refs := maps.Clone(t.ExternalRefs)
+	if refs == nil {
+		refs = make(map[string]string)
+	}
+	refs[system] = ref
+	t.ExternalRefs = refs
+	t.touch()
+}
+
+// touch records a modification for timestamps and sync revisions
+func (t *Task) touch() {
+	t.UpdatedAt = time.Now()
+	t.Revision++
+}
+
+// IsOpen reports whether the task still needs work
+func (t *Task) IsOpen() bool {
+	return t.Status != StatusDone && t.Status != StatusCancelled
+}
+
+// IsValid reports whether the status is one of the known task states
+func (s TaskStatus) IsValid() bool {
+	switch s {
+	case StatusTodo, StatusInProgress, StatusDone, StatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Workflow lists the statuses tasks may take and the moves allowed between
+// them. The built-in statuses are always part of a workflow.
+type Workflow struct {
+	// Statuses in display order
+	Statuses []TaskStatus
+	// Transitions lists where each status may move; a status without an
+	// entry may move to any status
+	Transitions map[TaskStatus][]TaskStatus
+	// Limits caps how many tasks a status may hold; a status without an
+	// entry has no limit
+	Limits map[TaskStatus]int
+	// RequireChecklist keeps tasks with unchecked checklist items from
+	// being done
+	RequireChecklist bool
+	// KeepDuplicatesOpen leaves duplicates open when a task they are linked
+	// to is closed
+	KeepDuplicatesOpen bool
+	// Descriptions tidies the descriptions of tasks added and renamed
+	Descriptions DescriptionRules
+}
+
+// DefaultWorkflow allows the built-in statuses and every move between them,
+// except that a cancelled task must be reopened as todo before work resumes
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses:    []TaskStatus{StatusTodo, StatusInProgress, StatusDone, StatusCancelled},
+		Transitions: map[TaskStatus][]TaskStatus{StatusCancelled: {StatusTodo}},
+	}
+}
+
+// NewWorkflow extends the default workflow with user-defined statuses and
+// transitions, rejecting transitions that mention unknown statuses. A
+// configured transition replaces the default one for the same status.
+func NewWorkflow(statuses []string, transitions map[string][]string) (Workflow, error) {
+	workflow := DefaultWorkflow()
+	for _, name := range statuses {
+		status := TaskStatus(NormalizeTag(name))
+		if status == "" {
+			return Workflow{}, fmt.Errorf("%w: empty status name", ErrInvalidStatus)
+		}
+		if !workflow.Has(status) {
+			workflow.Statuses = append(workflow.Statuses, status)
+		}
+	}
+
+	workflow.Transitions = maps.Clone(workflow.Transitions)
+	for from, targets := range transitions {
+		fromStatus := TaskStatus(NormalizeTag(from))
+		if !workflow.Has(fromStatus) {
+			return Workflow{}, fmt.Errorf("%w: transition from unknown status %q", ErrInvalidStatus, from)
+		}
+		allowed := []TaskStatus{}
+		for _, to := range targets {
+			toStatus := TaskStatus(NormalizeTag(to))
+			if !workflow.Has(toStatus) {
+				return Workflow{}, fmt.Errorf("%w: transition to unknown status %q", ErrInvalidStatus, to)
+			}
+			allowed = append(allowed, toStatus)
+		}
+		workflow.Transitions[fromStatus] = allowed
+	}
+
+	return workflow, nil
+}
+
+// Has reports whether the status belongs to the workflow
+func (w Workflow) Has(status TaskStatus) bool {
+	return slices.Contains(w.Statuses, status)
+}
+
+// CanMove reports whether a task may move from one status to another
+func (w Workflow) CanMove(from, to TaskStatus) bool {
+	if !w.Has(to) {
+		return false
+	}
+	allowed, restricted := w.Transitions[from]
+	return !restricted || slices.Contains(allowed, to)
+}
+
+// WithLimits returns the workflow with work-in-progress limits, rejecting
+// unknown statuses and limits below one
+func (w Workflow) WithLimits(limits map[string]int) (Workflow, error) {
+	if len(limits) == 0 {
+		return w, nil
+	}
+	w.Limits = make(map[TaskStatus]int, len(limits))
+	for name, limit := range limits {
+		status := TaskStatus(NormalizeTag(name))
+		if !w.Has(status) {
+			return Workflow{}, fmt.Errorf("%w: WIP limit for unknown status %q", ErrInvalidStatus, name)
+		}
+		if limit < 1 {
+			return Workflow{}, fmt.Errorf("%w: WIP limit for %s must be at least 1", ErrInvalidStatus, status)
+		}
+		w.Limits[status] = limit
+	}
+	return w, nil
+}
+
+// Unrestricted returns the workflow with every move allowed, for callers
+// that deliberately override the transition rules
+func (w Workflow) Unrestricted() Workflow {
+	w.Transitions = nil
+	w.RequireChecklist = false
+	return w
+}
+
+// IsValid reports whether the priority is unset or one of the known levels
+func (p Priority) IsValid() bool {
+	switch p {
+	case PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizeTag lowercases a tag and replaces spaces with dashes
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(tag), "#")), "-"))
+}
+
+// MergeTags appends normalized tags that are not already present
+func MergeTags(existing, tags []string) []string {
+	merged := slices.Clone(existing)
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag != "" && !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
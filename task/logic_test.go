@@ -1,10 +1,14 @@
-package main
+package task_test
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
 )
 
 // TestNewTask focuses on task creation business rules
@@ -130,9 +134,9 @@ func TestTask_UpdateDescription(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create fresh task for each test
-			task := NewTaskBuilder().
+			task := tasktest.NewTaskBuilder().
 				WithDescription("Original description").
-				WithTimestamps(FixedTime(), FixedTime()).
+				WithTimestamps(tasktest.FixedTime(), tasktest.FixedTime()).
 				BuildValid(t)
 
 			originalCreatedAt := task.CreatedAt
@@ -141,7 +145,7 @@ func TestTask_UpdateDescription(t *testing.T) {
 			// Small delay to ensure UpdatedAt changes
 			time.Sleep(1 * time.Millisecond)
 
-			err := task.UpdateDescription(tt.description)
+			err := task.UpdateDescription(tt.description, DescriptionRules{})
 
 			if tt.wantErr {
 				if err == nil {
@@ -216,9 +220,9 @@ func TestTask_MarkInProgress(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			task := NewTaskBuilder().
+			task := tasktest.NewTaskBuilder().
 				WithStatus(tt.initialStatus).
-				WithTimestamps(FixedTime(), FixedTime()).
+				WithTimestamps(tasktest.FixedTime(), tasktest.FixedTime()).
 				BuildValid(t)
 
 			originalCreatedAt := task.CreatedAt
@@ -270,9 +274,9 @@ func TestTask_MarkDone(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			task := NewTaskBuilder().
+			task := tasktest.NewTaskBuilder().
 				WithStatus(tt.initialStatus).
-				WithTimestamps(FixedTime(), FixedTime()).
+				WithTimestamps(tasktest.FixedTime(), tasktest.FixedTime()).
 				BuildValid(t)
 
 			originalCreatedAt := task.CreatedAt
@@ -301,7 +305,7 @@ func TestTask_MarkDone(t *testing.T) {
 // TestTask_StateInvariants tests that task maintains valid state
 func TestTask_StateInvariants(t *testing.T) {
 	t.Run("new task has valid initial state", func(t *testing.T) {
-		task := TodoTask(t)
+		task := tasktest.TodoTask(t)
 
 		// Business invariants
 		if task.ID <= 0 {
@@ -330,7 +334,7 @@ func TestTask_StateInvariants(t *testing.T) {
 	})
 
 	t.Run("multiple state changes maintain invariants", func(t *testing.T) {
-		task := TodoTask(t)
+		task := tasktest.TodoTask(t)
 		originalCreatedAt := task.CreatedAt
 
 		// Chain multiple operations
@@ -339,7 +343,7 @@ func TestTask_StateInvariants(t *testing.T) {
 		firstUpdate := task.UpdatedAt
 
 		time.Sleep(1 * time.Millisecond)
-		err := task.UpdateDescription("Updated description")
+		err := task.UpdateDescription("Updated description", DescriptionRules{})
 		if err != nil {
 			t.Fatalf("UpdateDescription failed: %v", err)
 		}
@@ -379,7 +383,7 @@ func TestTask_StateInvariants(t *testing.T) {
 
 // TestTaskStatus_Values tests status value objects
 func TestTaskStatus_Values(t *testing.T) {
-	validStatuses := []TaskStatus{StatusTodo, StatusInProgress, StatusDone}
+	validStatuses := []TaskStatus{StatusTodo, StatusInProgress, StatusDone, StatusCancelled}
 
 	for _, status := range validStatuses {
 		t.Run(string(status), func(t *testing.T) {
@@ -390,7 +394,7 @@ func TestTaskStatus_Values(t *testing.T) {
 			}
 
 			// Verify we can create tasks with this status
-			task := NewTaskBuilder().
+			task := tasktest.NewTaskBuilder().
 				WithStatus(status).
 				BuildValid(t)
 
@@ -403,14 +407,14 @@ func TestTaskStatus_Values(t *testing.T) {
 
 // TestTask_ImmutableCreationTime ensures creation time never changes
 func TestTask_ImmutableCreationTime(t *testing.T) {
-	task := TodoTask(t)
+	task := tasktest.TodoTask(t)
 	originalCreatedAt := task.CreatedAt
 
 	// Perform various operations
 	operations := []func(){
 		func() { task.MarkInProgress() },
 		func() { task.MarkDone() },
-		func() { _ = task.UpdateDescription("New description") },
+		func() { _ = task.UpdateDescription("New description", DescriptionRules{}) },
 	}
 
 	for i, op := range operations {
@@ -429,3 +433,120 @@ func TestTask_ImmutableCreationTime(t *testing.T) {
 		})
 	}
 }
+
+// TestNewWorkflow tests building a workflow from configuration
+func TestNewWorkflow(t *testing.T) {
+	t.Run("adds custom statuses after the built-in ones", func(t *testing.T) {
+		workflow, err := NewWorkflow([]string{"Review", "blocked", "todo"}, nil)
+		if err != nil {
+			t.Fatalf("NewWorkflow() failed: %v", err)
+		}
+		want := []TaskStatus{StatusTodo, StatusInProgress, StatusDone, StatusCancelled, "review", "blocked"}
+		if fmt.Sprint(workflow.Statuses) != fmt.Sprint(want) {
+			t.Errorf("Statuses = %v, want %v", workflow.Statuses, want)
+		}
+	})
+
+	t.Run("rejects transitions with unknown statuses", func(t *testing.T) {
+		_, err := NewWorkflow([]string{"review"}, map[string][]string{"review": {"shipped"}})
+		if !errors.Is(err, ErrInvalidStatus) {
+			t.Errorf("NewWorkflow() error = %v, want %v", err, ErrInvalidStatus)
+		}
+	})
+}
+
+// TestTask_MoveTo tests status changes constrained by a workflow
+func TestTask_MoveTo(t *testing.T) {
+	workflow, err := NewWorkflow([]string{"review", "blocked"}, map[string][]string{
+		"todo":        {"in-progress", "blocked"},
+		"in-progress": {"review", "blocked"},
+		"review":      {"done", "in-progress"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		from    TaskStatus
+		to      TaskStatus
+		wantErr string
+	}{
+		{"allowed move", StatusInProgress, "review", ""},
+		{"unrestricted status", "blocked", StatusDone, ""},
+		{"same status", StatusTodo, StatusTodo, ""},
+		{"forbidden move", StatusTodo, StatusDone, "cannot move task 1 from todo to done"},
+		{"unknown status", StatusTodo, "shipped", `Invalid task status "shipped"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tasktest.TodoTask(t)
+			task.Status = tt.from
+			revision := task.Revision
+
+			err := task.MoveTo(tt.to, workflow)
+			if tt.wantErr == "" {
+				if err != nil || task.Status != tt.to {
+					t.Errorf("MoveTo(%s) = %v, status %s", tt.to, err, task.Status)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidStatus) ||
+				!strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("MoveTo(%s) error = %v, want %q", tt.to, err, tt.wantErr)
+			}
+			if task.Status != tt.from || task.Revision != revision {
+				t.Errorf("failed move changed the task: %+v", task)
+			}
+		})
+	}
+}
+
+// TestTask_Cancel tests closing a task without doing it
+func TestTask_Cancel(t *testing.T) {
+	t.Run("records the reason as a note", func(t *testing.T) {
+		task := tasktest.TodoTask(t)
+
+		if err := task.Cancel("  no longer needed ", DefaultWorkflow(), tasktest.FixedTime()); err != nil {
+			t.Fatalf("Cancel() failed: %v", err)
+		}
+		if task.Status != StatusCancelled || task.IsOpen() {
+			t.Errorf("status = %s, open = %v", task.Status, task.IsOpen())
+		}
+		if len(task.Notes) != 1 || task.Notes[0].Text != "Cancelled: no longer needed" {
+			t.Errorf("notes = %+v", task.Notes)
+		}
+	})
+
+	t.Run("cancelled tasks must be reopened first", func(t *testing.T) {
+		task := tasktest.NewTaskBuilder().WithStatus(StatusCancelled).BuildValid(t)
+
+		if err := task.MoveTo(StatusDone, DefaultWorkflow()); !strings.Contains(fmt.Sprint(err), "from cancelled to done") {
+			t.Errorf("MoveTo(done) error = %v, want refused transition", err)
+		}
+		if err := task.MoveTo(StatusTodo, DefaultWorkflow()); err != nil {
+			t.Errorf("MoveTo(todo) error = %v", err)
+		}
+	})
+}
+
+func TestTask_StatusSince(t *testing.T) {
+	created := tasktest.FixedTime()
+	task := tasktest.NewTaskBuilder().WithID(1).WithDescription("Review PR").
+		WithTimestamps(created, tasktest.TimeAfter(created)).BuildInvalid()
+	if got := task.StatusSince(); !got.Equal(created) {
+		t.Errorf("todo StatusSince() = %v, want creation %v", got, created)
+	}
+
+	task.MarkInProgress()
+	if task.StatusChangedAt == nil || !task.StatusSince().Equal(task.UpdatedAt) {
+		t.Errorf("StatusChangedAt = %v, want the move time %v", task.StatusChangedAt, task.UpdatedAt)
+	}
+	moved := task.StatusSince()
+	task.UpdateDescription("Review the PR", DescriptionRules{})
+	if !task.StatusSince().Equal(moved) {
+		t.Errorf("StatusSince() after editing = %v, want %v", task.StatusSince(), moved)
+	}
+}
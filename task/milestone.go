@@ -0,0 +1,95 @@
+package task
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Milestone is a named target date that tasks are planned for, e.g. a
+// release. Tasks refer to their milestone by name.
+type Milestone struct {
+	Name      string    `json:"name"`
+	DueAt     time.Time `json:"dueAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ErrNoMilestones is returned when the service has no milestone storage
+var ErrNoMilestones = errors.New("milestones are not available with this storage")
+
+// milestoneRiskWindow is how close the target date gets before tasks that
+// have not been started are at risk
+const milestoneRiskWindow = 3 * 24 * time.Hour
+
+// MilestoneStatus reports the progress of a milestone
+type MilestoneStatus struct {
+	Milestone
+	// Total counts the planned tasks, cancelled ones excluded
+	Total int
+	Done  int
+	// AtRisk lists open tasks unlikely to make the target date
+	AtRisk []RiskyTask
+}
+
+// RiskyTask is an open task with why it may miss its milestone
+type RiskyTask struct {
+	Task
+	Reason string
+}
+
+// Percent is the share of planned tasks done, rounded down
+func (m MilestoneStatus) Percent() int {
+	if m.Total == 0 {
+		return 0
+	}
+	return m.Done * 100 / m.Total
+}
+
+// Overdue reports whether the target date passed with work left
+func (m MilestoneStatus) Overdue(now time.Time) bool {
+	return m.Done < m.Total && now.After(m.DueAt)
+}
+
+// BuildMilestoneStatus reports each milestone's progress, soonest target
+// first. An open task is at risk when the target date has passed, when its
+// own deadline falls after the target, or when it has not been started and
+// the target is less than three days away.
+func BuildMilestoneStatus(milestones []Milestone, tasks []Task, now time.Time) []MilestoneStatus {
+	report := make([]MilestoneStatus, 0, len(milestones))
+	for _, milestone := range milestones {
+		status := MilestoneStatus{Milestone: milestone}
+		for _, task := range tasks {
+			if task.Milestone != milestone.Name || task.Status == StatusCancelled {
+				continue
+			}
+			status.Total++
+			if task.Status == StatusDone {
+				status.Done++
+				continue
+			}
+			if reason := milestoneRisk(task, milestone, now); reason != "" {
+				status.AtRisk = append(status.AtRisk, RiskyTask{Task: task, Reason: reason})
+			}
+		}
+		report = append(report, status)
+	}
+
+	slices.SortStableFunc(report, func(a, b MilestoneStatus) int {
+		return a.DueAt.Compare(b.DueAt)
+	})
+	return report
+}
+
+// milestoneRisk says why an open task may miss the milestone, or ""
+func milestoneRisk(task Task, milestone Milestone, now time.Time) string {
+	switch {
+	case now.After(milestone.DueAt):
+		return "milestone overdue"
+	case task.DueAt != nil && task.DueAt.After(milestone.DueAt):
+		return "due after the milestone"
+	case task.Status == StatusTodo && milestone.DueAt.Sub(now) < milestoneRiskWindow:
+		return "not started"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,56 @@
+package task_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestBuildMilestoneStatus tests progress and at-risk tasks per milestone
+func TestBuildMilestoneStatus(t *testing.T) {
+	now := tasktest.FixedTime()
+	late := now.Add(10 * 24 * time.Hour)
+	milestones := []Milestone{
+		{Name: "v2.0", DueAt: now.Add(30 * 24 * time.Hour)},
+		{Name: "v1.0", DueAt: now.Add(48 * time.Hour)},
+		{Name: "beta", DueAt: now.Add(-time.Hour)},
+	}
+	tasks := []Task{
+		{ID: 1, Milestone: "v1.0", Status: StatusDone},
+		{ID: 2, Milestone: "v1.0", Status: StatusTodo},
+		{ID: 3, Milestone: "v1.0", Status: StatusInProgress},
+		{ID: 4, Milestone: "v1.0", Status: StatusInProgress, DueAt: &late},
+		{ID: 5, Milestone: "v1.0", Status: StatusCancelled},
+		{ID: 6, Milestone: "v2.0", Status: StatusTodo},
+		{ID: 7, Milestone: "beta", Status: StatusInProgress},
+		{ID: 8, Status: StatusTodo},
+	}
+
+	report := BuildMilestoneStatus(milestones, tasks, now)
+
+	if len(report) != 3 || report[0].Name != "beta" || report[1].Name != "v1.0" || report[2].Name != "v2.0" {
+		t.Fatalf("report = %+v, want beta, v1.0, v2.0", report)
+	}
+
+	v1 := report[1]
+	if v1.Total != 4 || v1.Done != 1 || v1.Percent() != 25 || v1.Overdue(now) {
+		t.Errorf("v1.0 = %d/%d (%d%%), want 1/4 (25%%), not overdue", v1.Done, v1.Total, v1.Percent())
+	}
+	var risks []string
+	for _, risky := range v1.AtRisk {
+		risks = append(risks, risky.Reason)
+	}
+	if got := strings.Join(risks, ", "); got != "not started, due after the milestone" {
+		t.Errorf("v1.0 risks = %q", got)
+	}
+
+	if beta := report[0]; !beta.Overdue(now) || len(beta.AtRisk) != 1 || beta.AtRisk[0].Reason != "milestone overdue" {
+		t.Errorf("beta = %+v, want overdue with one task at risk", beta)
+	}
+	if v2 := report[2]; len(v2.AtRisk) != 0 || v2.Percent() != 0 {
+		t.Errorf("v2.0 = %+v, want nothing at risk a month ahead", v2)
+	}
+}
@@ -0,0 +1,343 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// TaskStatus represents the possible states of a task
+type TaskStatus string
+
+const (
+	StatusTodo       TaskStatus = "todo"
+	StatusInProgress TaskStatus = "in-progress"
+	StatusDone       TaskStatus = "done"
+	// StatusCancelled closes a task that will not be done
+	StatusCancelled TaskStatus = "cancelled"
+)
+
+// Priority ranks how urgent a task is, empty meaning unset
+type Priority string
+
+const (
+	PriorityNone   Priority = ""
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+// Note is a timestamped comment attached to a task
+type Note struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Text      string    `json:"text"`
+}
+
+// ChecklistItem is a step within a task, lighter than a subtask of its own
+type ChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done,omitempty"`
+}
+
+// Attachment links a task to a URL or a file, with an optional label
+type Attachment struct {
+	Target string `json:"target"`
+	Label  string `json:"label,omitempty"`
+}
+
+// RelationType says how a task relates to another
+type RelationType string
+
+const (
+	// RelationDuplicates marks the task as a copy of the other, which is
+	// linked back with RelationDuplicatedBy
+	RelationDuplicates   RelationType = "duplicates"
+	RelationDuplicatedBy RelationType = "duplicated-by"
+	// RelationRelatesTo links two tasks without further meaning, both ways
+	RelationRelatesTo RelationType = "relates-to"
+)
+
+// Relation links the task to another task by ID
+type Relation struct {
+	Type RelationType `json:"type"`
+	Task int          `json:"task"`
+}
+
+// Task represents a single task with all its properties
+type Task struct {
+	ID          int        `json:"id"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DueAt       *time.Time `json:"dueAt,omitempty"`
+	Priority    Priority   `json:"priority,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Notes       []Note     `json:"notes,omitempty"`
+	// Checklist lists the steps of the task in order
+	Checklist []ChecklistItem `json:"checklist,omitempty"`
+	// Attachments lists URLs and files related to the task
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Relations links the task to other tasks, each stored on both sides
+	Relations []Relation `json:"relations,omitempty"`
+	// StatusChangedAt is when the task last moved to its status, unset for
+	// tasks that have not moved since it was introduced
+	StatusChangedAt *time.Time `json:"statusChangedAt,omitempty"`
+	// StartAt hides the task from list and next until then, cleared when
+	// the task wakes
+	StartAt *time.Time `json:"startAt,omitempty"`
+	// RemindAt is when to send a reminder, cleared once it is sent
+	RemindAt *time.Time `json:"remindAt,omitempty"`
+	// Estimate is how long the task is expected to take, zero when unknown
+	Estimate Duration `json:"estimate,omitempty"`
+	// Pomodoros counts the work intervals completed on the task
+	Pomodoros int `json:"pomodoros,omitempty"`
+	// List names the list the task is in, empty for the default list
+	List string `json:"list,omitempty"`
+	// Project names the project the task belongs to, empty when it has none
+	Project string `json:"project,omitempty"`
+	// Milestone names the milestone the task is planned for
+	Milestone string `json:"milestone,omitempty"`
+	// Sprint names the sprint the task is committed to
+	Sprint string `json:"sprint,omitempty"`
+	// Assignee is who is expected to do the task, empty when unassigned
+	Assignee string `json:"assignee,omitempty"`
+	// CreatedBy and UpdatedBy name who added and last changed the task
+	CreatedBy string `json:"createdBy,omitempty"`
+	UpdatedBy string `json:"updatedBy,omitempty"`
+	// Revision counts modifications so sync can tell which side changed
+	Revision int `json:"revision,omitempty"`
+	// UUID identifies the task across stores and systems, while the short ID
+	// is only unique within one store
+	UUID string `json:"uuid,omitempty"`
+	// ExternalRefs links the task to items in other systems, keyed by system
+	// name, e.g. {"github": "owner/name#12"}
+	ExternalRefs map[string]string `json:"externalRefs,omitempty"`
+}
+
+// Domain Errors
+//
+// A TaskError is identified by its code, so errors.Is matches an error
+// whose message was given details against the variable it came from.
+type TaskError struct {
+	Code    string
+	Message string
+	// Hint tells the user what to do about the error, empty when there is
+	// nothing to suggest
+	Hint string
+	// Err is the error that caused this one, if any
+	Err error
+}
+
+var (
+	ErrTaskNotFound = TaskError{
+		Code:    "NOT_FOUND",
+		Message: "Task not found",
+		Hint:    "run 'task-cli list --all' to see task IDs",
+	}
+	ErrInvalidStatus    = TaskError{Code: "INVALID_STATUS", Message: "Invalid task status"}
+	ErrEmptyDescription = TaskError{
+		Code:    "EMPTY_DESCRIPTION",
+		Message: "Task description cannot be empty",
+	}
+	ErrDescriptionTooLong = TaskError{
+		Code:    "DESCRIPTION_TOO_LONG",
+		Message: "Task description is too long",
+		Hint:    "keep long text such as logs in a file and attach it with 'task-cli attach'",
+	}
+	ErrInvalidPriority = TaskError{
+		Code:    "INVALID_PRIORITY",
+		Message: "Invalid task priority",
+		Hint:    "use low, medium, high, urgent or none",
+	}
+	ErrInvalidEstimate = TaskError{
+		Code:    "INVALID_ESTIMATE",
+		Message: "Task estimate cannot be negative",
+		Hint:    "use a duration such as 2h or 1d, or a size S, M or L",
+	}
+	ErrInvalidID = TaskError{
+		Code:    "INVALID_ID",
+		Message: "Invalid task ID",
+		Hint:    "use a task number, at least 4 characters of its UUID or part of its description",
+	}
+	ErrAmbiguousID = TaskError{
+		Code:    "AMBIGUOUS_ID",
+		Message: "Task ID prefix matches several tasks",
+		Hint:    "type more of the UUID, or use the task number",
+	}
+	ErrAmbiguousTask = TaskError{
+		Code:    "AMBIGUOUS_TASK",
+		Message: "Several tasks match the description",
+		Hint:    "use one of the task numbers listed",
+	}
+	ErrInvalidCheckItem  = TaskError{Code: "INVALID_CHECK_ITEM", Message: "Checklist item cannot be empty"}
+	ErrCheckItemNotFound = TaskError{Code: "CHECK_ITEM_NOT_FOUND", Message: "Checklist item not found"}
+	ErrChecklistOpen     = TaskError{
+		Code:    "CHECKLIST_OPEN",
+		Message: "Checklist has open items",
+		Hint:    "check the remaining items with 'task-cli check toggle', or add --force",
+	}
+	ErrInvalidAttachment  = TaskError{Code: "INVALID_ATTACHMENT", Message: "Attachment target cannot be empty"}
+	ErrAttachmentNotFound = TaskError{Code: "ATTACHMENT_NOT_FOUND", Message: "Attachment not found"}
+	ErrInvalidRelation    = TaskError{Code: "INVALID_RELATION", Message: "Relation must be duplicates or relates-to between two tasks"}
+	ErrRelationNotFound   = TaskError{Code: "RELATION_NOT_FOUND", Message: "Tasks are not linked"}
+	ErrInvalidTasks       = TaskError{
+		Code:    "INVALID_TASKS",
+		Message: "Task list failed integrity checks",
+		Hint:    "run 'task-cli doctor' to see what is wrong",
+	}
+	ErrWIPLimit = TaskError{
+		Code:    "WIP_LIMIT",
+		Message: "WIP limit reached",
+		Hint:    "finish a task in that status first, or add --force",
+	}
+	ErrReadOnly = TaskError{
+		Code:    "READ_ONLY",
+		Message: "Task file is read-only",
+		Hint:    "run without --read-only and readOnly in the config, with write access to the task file",
+	}
+	ErrStorage = TaskError{
+		Code:    "STORAGE",
+		Message: "Tasks could not be read or saved",
+		Hint:    "check that the task file can be read and written, then run 'task-cli doctor'",
+	}
+	ErrConflict = TaskError{
+		Code:    "CONFLICT",
+		Message: "Task was changed by someone else since it was read, try again",
+	}
+
+	ErrInvalidTag  = TaskError{Code: "INVALID_TAG", Message: "Tag cannot be empty"}
+	ErrTagNotFound = TaskError{
+		Code:    "TAG_NOT_FOUND",
+		Message: "No task has the tag",
+		Hint:    "run 'task-cli tag list' to see the tags in use",
+	}
+	ErrTagExists = TaskError{Code: "TAG_EXISTS", Message: "Tag is already in use, merge into it instead"}
+
+	ErrInvalidList = TaskError{Code: "INVALID_LIST", Message: "List name cannot be empty or contain spaces"}
+
+	ErrInvalidProject = TaskError{
+		Code:    "INVALID_PROJECT",
+		Message: "Project name cannot be empty or contain spaces",
+	}
+	ErrProjectNotFound = TaskError{
+		Code:    "PROJECT_NOT_FOUND",
+		Message: "Project not found",
+		Hint:    "run 'task-cli project list --all' to see the projects",
+	}
+	ErrProjectExists   = TaskError{Code: "PROJECT_EXISTS", Message: "Project already exists"}
+	ErrProjectClosed   = TaskError{Code: "PROJECT_CLOSED", Message: "Project is closed"}
+	ErrProjectOpenWork = TaskError{Code: "PROJECT_OPEN_WORK", Message: "Project still has open tasks"}
+
+	ErrInvalidMilestone  = TaskError{Code: "INVALID_MILESTONE", Message: "Milestone name cannot be empty"}
+	ErrMilestoneNotFound = TaskError{
+		Code:    "MILESTONE_NOT_FOUND",
+		Message: "Milestone not found",
+		Hint:    "run 'task-cli milestone status' to see the milestones",
+	}
+	ErrMilestoneExists = TaskError{Code: "MILESTONE_EXISTS", Message: "Milestone already exists"}
+
+	ErrInvalidSprint      = TaskError{Code: "INVALID_SPRINT", Message: "Sprint name cannot be empty or \"none\""}
+	ErrInvalidSprintDates = TaskError{Code: "INVALID_SPRINT_DATES", Message: "Sprint must end after it starts and its capacity cannot be negative"}
+	ErrSprintNotFound     = TaskError{
+		Code:    "SPRINT_NOT_FOUND",
+		Message: "Sprint not found",
+		Hint:    "run 'task-cli sprint list' to see the sprints",
+	}
+	ErrSprintExists   = TaskError{Code: "SPRINT_EXISTS", Message: "Sprint already exists"}
+	ErrSprintClosed   = TaskError{Code: "SPRINT_CLOSED", Message: "Sprint is closed"}
+	ErrNoActiveSprint = TaskError{
+		Code:    "NO_ACTIVE_SPRINT",
+		Message: "No sprint is running",
+		Hint:    "start one with 'task-cli sprint create'",
+	}
+	ErrNoNextSprint = TaskError{Code: "NO_NEXT_SPRINT", Message: "No open sprint follows the current one, create it first"}
+
+	ErrInvalidView  = TaskError{Code: "INVALID_VIEW", Message: "View name cannot be empty or contain spaces"}
+	ErrViewNotFound = TaskError{
+		Code:    "VIEW_NOT_FOUND",
+		Message: "View not found",
+		Hint:    "run 'task-cli view list' to see the saved views",
+	}
+	ErrInvalidSort = TaskError{Code: "INVALID_SORT", Message: "Invalid sort order"}
+
+	ErrInvalidSnapshot  = TaskError{Code: "INVALID_SNAPSHOT", Message: "Snapshot name cannot contain spaces or slashes, or be \"current\""}
+	ErrSnapshotNotFound = TaskError{
+		Code:    "SNAPSHOT_NOT_FOUND",
+		Message: "Snapshot not found",
+		Hint:    "run 'task-cli snapshot list' to see the snapshots",
+	}
+	ErrSnapshotExists = TaskError{Code: "SNAPSHOT_EXISTS", Message: "Snapshot already exists"}
+
+	ErrInvalidFormat = TaskError{Code: "INVALID_FORMAT", Message: "Invalid output format"}
+	ErrInvalidColumn = TaskError{Code: "INVALID_COLUMN", Message: "Invalid column"}
+	ErrInvalidExport = TaskError{Code: "INVALID_EXPORT", Message: "Invalid export file"}
+	ErrIDTaken       = TaskError{Code: "ID_TAKEN", Message: "Task ID is already used in the target store"}
+
+	ErrHookRejected = TaskError{
+		Code:    "HOOK_REJECTED",
+		Message: "Change refused by a hook",
+		Hint:    "the hook scripts are listed under hooks in the config file",
+	}
+	ErrHookFailed = TaskError{
+		Code:    "HOOK_FAILED",
+		Message: "Hook script failed",
+		Hint:    "fix the script, or remove it from hooks in the config file",
+	}
+
+	ErrInvalidBody  = TaskError{Code: "INVALID_BODY", Message: "Invalid request body"}
+	ErrBodyTooLarge = TaskError{Code: "BODY_TOO_LARGE", Message: "Request body is too large"}
+	ErrRateLimited  = TaskError{Code: "RATE_LIMITED", Message: "Too many requests, slow down"}
+)
+
+func (e TaskError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e TaskError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is a TaskError with the same code, so
+// errors.Is matches a sentinel such as ErrTaskNotFound whatever details,
+// hint or cause were added to it and however deeply it is wrapped
+func (e TaskError) Is(target error) bool {
+	switch t := target.(type) {
+	case TaskError:
+		return t.Code == e.Code
+	case *TaskError:
+		return t != nil && t.Code == e.Code
+	}
+	return false
+}
+
+// As lets errors.As fill a *TaskError as well as a TaskError
+func (e TaskError) As(target any) bool {
+	if t, ok := target.(**TaskError); ok {
+		*t = &e
+		return true
+	}
+	return false
+}
+
+// Withf returns the error with details appended to its message, such as
+// the ID of the task not found
+func (e TaskError) Withf(format string, args ...any) TaskError {
+	e.Message += ": " + fmt.Sprintf(format, args...)
+	return e
+}
+
+// WithHint returns the error with another hint
+func (e TaskError) WithHint(hint string) TaskError {
+	e.Hint = hint
+	return e
+}
+
+// Wrap returns the error caused by err
+func (e TaskError) Wrap(err error) TaskError {
+	e.Err = err
+	return e
+}
@@ -0,0 +1,111 @@
+package task
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Factors scored by Suggest, also the keys of next.weights in the config
+const (
+	FactorPriority   = "priority"
+	FactorDue        = "due"
+	FactorAge        = "age"
+	FactorInProgress = "inProgress"
+	FactorBlocked    = "blocked"
+)
+
+// DefaultNextWeights are the points each factor is worth at its strongest
+var DefaultNextWeights = map[string]float64{
+	FactorPriority:   40,
+	FactorDue:        35,
+	FactorAge:        10,
+	FactorInProgress: 25,
+	FactorBlocked:    100,
+}
+
+const (
+	// nextDueHorizon is how far ahead a deadline starts to count
+	nextDueHorizon = 14 * 24 * time.Hour
+	// nextAgeHorizon is the age at which a task gets all the age points
+	nextAgeHorizon = 30 * 24 * time.Hour
+)
+
+// ScoreReason is what one factor added to a task's score
+type ScoreReason struct {
+	Factor string
+	Points float64
+	Detail string
+}
+
+// Suggestion is a task with its score and how it was reached
+type Suggestion struct {
+	Task    Task
+	Score   float64
+	Reasons []ScoreReason
+}
+
+// NextWeights fills in the default weight of every factor configured has
+// no entry for. A zero weight turns a factor off.
+func NextWeights(configured map[string]float64) (map[string]float64, error) {
+	weights := make(map[string]float64, len(DefaultNextWeights))
+	for factor, weight := range DefaultNextWeights {
+		weights[factor] = weight
+	}
+	for factor, weight := range configured {
+		if _, ok := DefaultNextWeights[factor]; !ok {
+			names := slices.Sorted(maps.Keys(DefaultNextWeights))
+			return nil, fmt.Errorf("unknown next weight %q, valid factors: %s", factor, strings.Join(names, ", "))
+		}
+		weights[factor] = weight
+	}
+	return weights, nil
+}
+
+// ScoreTask rates how good a pick an open task is to work on now:
+//   - priority: urgent gets the full weight, low a quarter
+//   - due: overdue gets the full weight, fading to nothing two weeks ahead
+//   - age: growing to the full weight at 30 days old
+//   - inProgress: started work is worth finishing
+//   - blocked: taken off tasks that are not ready, parked in a status of the
+//     workflow other than todo and in-progress, such as blocked or waiting
+func ScoreTask(task Task, weights map[string]float64, now time.Time) Suggestion {
+	suggestion := Suggestion{Task: task}
+	add := func(factor string, strength float64, detail string) {
+		points := weights[factor] * strength
+		if factor == FactorBlocked {
+			points = -points
+		}
+		if points == 0 {
+			return
+		}
+		suggestion.Score += points
+		suggestion.Reasons = append(suggestion.Reasons, ScoreReason{Factor: factor, Points: points, Detail: detail})
+	}
+
+	if rank := priorityRank(task.Priority); rank > 0 {
+		add(FactorPriority, float64(rank)/4, string(task.Priority))
+	}
+	if task.DueAt != nil {
+		left := task.DueAt.Sub(now)
+		switch {
+		case left < 0:
+			add(FactorDue, 1, "overdue, due "+HumanizeTime(*task.DueAt, now))
+		case left < nextDueHorizon:
+			add(FactorDue, 1-float64(left)/float64(nextDueHorizon), "due "+HumanizeTime(*task.DueAt, now))
+		}
+	}
+	if age := now.Sub(task.CreatedAt); age > 0 {
+		add(FactorAge, math.Min(float64(age)/float64(nextAgeHorizon), 1), "created "+HumanizeTime(task.CreatedAt, now))
+	}
+	if task.Status == StatusInProgress {
+		add(FactorInProgress, 1, "already started")
+	}
+	if !task.Status.IsValid() {
+		add(FactorBlocked, 1, "status "+string(task.Status))
+	}
+	return suggestion
+}
@@ -0,0 +1,42 @@
+package task_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+func TestScoreTask(t *testing.T) {
+	now := tasktest.FixedTime()
+	due := now.Add(7 * 24 * time.Hour)
+	task := tasktest.NewTaskBuilder().WithID(1).WithDescription("Ship release").
+		WithStatus(StatusInProgress).WithTimestamps(now.AddDate(0, 0, -15), now).BuildInvalid()
+	task.Priority = PriorityHigh
+	task.DueAt = &due
+
+	suggestion := ScoreTask(*task, DefaultNextWeights, now)
+	// 40*3/4 for high, 35*1/2 for a week ahead, 10*15/30 for age, 25 started
+	if want := 30 + 17.5 + 5 + 25.0; math.Abs(suggestion.Score-want) > 0.001 {
+		t.Errorf("Score = %v, want %v (%+v)", suggestion.Score, want, suggestion.Reasons)
+	}
+	if len(suggestion.Reasons) != 4 || suggestion.Reasons[1].Detail != "due in 7 days" {
+		t.Errorf("Reasons = %+v", suggestion.Reasons)
+	}
+
+	task.Status = "waiting"
+	weights, err := NextWeights(map[string]float64{FactorAge: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	suggestion = ScoreTask(*task, weights, now)
+	if want := 30 + 17.5 - 100.0; math.Abs(suggestion.Score-want) > 0.001 {
+		t.Errorf("blocked Score = %v, want %v (%+v)", suggestion.Score, want, suggestion.Reasons)
+	}
+
+	if _, err := NextWeights(map[string]float64{"urgency": 1}); err == nil {
+		t.Error("unknown weight was accepted")
+	}
+}
@@ -0,0 +1,133 @@
+package task
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DescriptionRules tidy descriptions as tasks are added and renamed. The
+// zero value leaves descriptions as typed, apart from surrounding spaces.
+type DescriptionRules struct {
+	// CollapseWhitespace turns runs of spaces, tabs and newlines into one
+	// space
+	CollapseWhitespace bool
+	// Abbreviations expands whole words, ignoring case, such as "mtg" to
+	// "meeting"
+	Abbreviations map[string]string
+	// StripTrailingPunctuation removes periods, commas, colons, semicolons
+	// and exclamation marks from the end; a question mark is kept
+	StripTrailingPunctuation bool
+	// Capitalize upper-cases the first letter
+	Capitalize bool
+	// MaxLength caps how many characters a description may have once
+	// tidied, 0 for no limit
+	MaxLength int
+	// Truncate shortens a description over MaxLength with an ellipsis and
+	// keeps the whole text as a note, instead of refusing it
+	Truncate bool
+}
+
+// trailingPunctuation is what StripTrailingPunctuation removes
+const trailingPunctuation = ".,;:!… "
+
+// Apply runs the description through the rules: whitespace first, then
+// abbreviations, so an expansion can still be capitalized or stripped
+func (r DescriptionRules) Apply(description string) string {
+	description = strings.TrimSpace(description)
+	if r.CollapseWhitespace {
+		description = strings.Join(strings.Fields(description), " ")
+	}
+	if len(r.Abbreviations) > 0 {
+		description = r.expand(description)
+	}
+	if r.StripTrailingPunctuation {
+		description = strings.TrimRight(description, trailingPunctuation)
+	}
+	if r.Capitalize {
+		first, size := utf8.DecodeRuneInString(description)
+		if unicode.IsLower(first) {
+			description = string(unicode.ToUpper(first)) + description[size:]
+		}
+	}
+	return description
+}
+
+// expand replaces the words that are abbreviations, keeping the spaces
+// and the punctuation around them
+func (r DescriptionRules) expand(description string) string {
+	abbreviations := make(map[string]string, len(r.Abbreviations))
+	for short, long := range r.Abbreviations {
+		abbreviations[strings.ToLower(short)] = long
+	}
+
+	isWordRune := func(c rune) bool { return unicode.IsLetter(c) || unicode.IsDigit(c) }
+	var b strings.Builder
+	word := -1
+	flush := func(end int) {
+		if word < 0 {
+			return
+		}
+		if long, ok := abbreviations[strings.ToLower(description[word:end])]; ok {
+			b.WriteString(long)
+		} else {
+			b.WriteString(description[word:end])
+		}
+		word = -1
+	}
+	for i, c := range description {
+		if isWordRune(c) {
+			if word < 0 {
+				word = i
+			}
+			continue
+		}
+		flush(i)
+		b.WriteRune(c)
+	}
+	flush(len(description))
+	return b.String()
+}
+
+// WithDescriptionRules tidies the description of a new task with the rules,
+// failing when nothing is left of it or it is too long
+func WithDescriptionRules(rules DescriptionRules) TaskOption {
+	return func(t *Task) error {
+		return t.SetDescription(t.Description, rules)
+	}
+}
+
+// SetDescription tidies the description with the rules and holds it to
+// their length limit before setting it
+func (t *Task) SetDescription(description string, rules DescriptionRules) error {
+	description = rules.Apply(description)
+	if description == "" {
+		return ErrEmptyDescription
+	}
+
+	if length := utf8.RuneCountInString(description); rules.MaxLength > 0 && length > rules.MaxLength {
+		if !rules.Truncate {
+			return ErrDescriptionTooLong.Withf("%d characters, at most %d", length, rules.MaxLength)
+		}
+		t.Notes = append(slices.Clone(t.Notes), Note{CreatedAt: time.Now(), Text: description})
+		description = truncateDescription(description, rules.MaxLength)
+	}
+	t.Description = description
+	return nil
+}
+
+// truncateDescription shortens a description to at most limit characters,
+// the last being an ellipsis. Only its first line is kept, since what
+// follows a line break, like the frames of a stack trace, reads poorly cut.
+func truncateDescription(description string, limit int) string {
+	if first, _, ok := strings.Cut(description, "\n"); ok {
+		description = first
+	}
+	runes := []rune(description)
+	if len(runes) > limit-1 {
+		runes = runes[:max(limit-1, 0)]
+	}
+	return strings.TrimSpace(string(runes)) + "…"
+}
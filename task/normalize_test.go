@@ -0,0 +1,83 @@
+package task_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+func TestDescriptionRules_Apply(t *testing.T) {
+	all := DescriptionRules{
+		CollapseWhitespace:       true,
+		Abbreviations:            map[string]string{"mtg": "meeting", "PR": "pull request"},
+		StripTrailingPunctuation: true,
+		Capitalize:               true,
+	}
+	tests := []struct {
+		name  string
+		rules DescriptionRules
+		in    string
+		want  string
+	}{
+		{"no rules", DescriptionRules{}, "  prep  mtg notes.  ", "prep  mtg notes."},
+		{"collapse whitespace", DescriptionRules{CollapseWhitespace: true}, "prep \t mtg\nnotes", "prep mtg notes"},
+		{"strip trailing punctuation", DescriptionRules{StripTrailingPunctuation: true}, "Call mom!!.", "Call mom"},
+		{"question mark kept", DescriptionRules{StripTrailingPunctuation: true}, "Which venue?", "Which venue?"},
+		{"capitalize", DescriptionRules{Capitalize: true}, "éditer le rapport", "Éditer le rapport"},
+		{"capitalize skips non-letters", DescriptionRules{Capitalize: true}, "3 boxes", "3 boxes"},
+		{"whole words only", all, "mtgs before the mtg, then review pr.", "Mtgs before the meeting, then review pull request"},
+		{"expansion capitalized", all, "mtg  with Bob!", "Meeting with Bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Apply(tt.in); got != tt.want {
+				t.Errorf("Apply(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_DescriptionMaxLength(t *testing.T) {
+	trace := "panic: runtime error: index out of range\n\ngoroutine 1 [running]:\nmain.main()"
+
+	t.Run("refused", func(t *testing.T) {
+		task := tasktest.NewTaskBuilder().WithDescription("Fix crash").BuildValid(t)
+		err := task.UpdateDescription(trace, DescriptionRules{MaxLength: 20})
+		if !errors.Is(err, ErrDescriptionTooLong) {
+			t.Fatalf("UpdateDescription() error = %v, want %v", err, ErrDescriptionTooLong)
+		}
+		if task.Description != "Fix crash" || len(task.Notes) != 0 {
+			t.Errorf("refused description changed the task: %+v", task)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		task, err := NewTask(1, trace, WithDescriptionRules(DescriptionRules{MaxLength: 20, Truncate: true}))
+		if err != nil {
+			t.Fatalf("NewTask() error = %v", err)
+		}
+		if task.Description != "panic: runtime erro…" {
+			t.Errorf("Description = %q", task.Description)
+		}
+		if len(task.Notes) != 1 || task.Notes[0].Text != trace {
+			t.Errorf("Notes = %+v, want the whole text kept", task.Notes)
+		}
+	})
+
+	tests := []struct {
+		in    string
+		limit int
+		want  string
+	}{
+		{"Write the quarterly report", 10, "Write the…"},
+		{"Short line\nand a long second line", 20, "Short line…"},
+		{"Éditer le rapport", 7, "Éditer…"},
+	}
+	for _, tt := range tests {
+		if got := TruncateDescription(tt.in, tt.limit); got != tt.want {
+			t.Errorf("TruncateDescription(%q, %d) = %q, want %q", tt.in, tt.limit, got, tt.want)
+		}
+	}
+}
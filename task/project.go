@@ -0,0 +1,74 @@
+package task
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Project groups related tasks under a name, with a life of its own:
+// it is added before tasks are filed under it and closed once done.
+// Tasks refer to their project by name.
+type Project struct {
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ClosedAt  *time.Time `json:"closedAt,omitempty"`
+}
+
+// IsClosed reports whether the project no longer accepts tasks
+func (p Project) IsClosed() bool {
+	return p.ClosedAt != nil
+}
+
+// ErrNoProjects is returned when the service has no project storage
+var ErrNoProjects = errors.New("projects are not available with this storage")
+
+// ProjectStats summarizes the tasks filed under a project
+type ProjectStats struct {
+	Project
+	Open int
+	Done int
+	// LastActivity is the latest change to the project or one of its tasks
+	LastActivity time.Time
+}
+
+// ValidateProjectName trims the name and rejects empty names and names with
+// spaces, which could not be typed as a single command-line argument
+func ValidateProjectName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return "", ErrInvalidProject
+	}
+	return name, nil
+}
+
+// BuildProjectStats counts the open and done tasks of each project, in the
+// order of projects. Cancelled tasks count as neither.
+func BuildProjectStats(projects []Project, tasks []Task) []ProjectStats {
+	stats := make([]ProjectStats, len(projects))
+	index := make(map[string]int, len(projects))
+	for i, project := range projects {
+		stats[i] = ProjectStats{Project: project, LastActivity: project.CreatedAt}
+		if project.ClosedAt != nil {
+			stats[i].LastActivity = *project.ClosedAt
+		}
+		index[project.Name] = i
+	}
+
+	for _, task := range tasks {
+		i, ok := index[task.Project]
+		if !ok {
+			continue
+		}
+		switch {
+		case task.Status == StatusDone:
+			stats[i].Done++
+		case task.IsOpen():
+			stats[i].Open++
+		}
+		if task.UpdatedAt.After(stats[i].LastActivity) {
+			stats[i].LastActivity = task.UpdatedAt
+		}
+	}
+	return stats
+}
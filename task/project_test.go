@@ -0,0 +1,57 @@
+package task_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestBuildProjectStats tests counting tasks per project
+func TestBuildProjectStats(t *testing.T) {
+	start := tasktest.FixedTime()
+	closed := start.Add(72 * time.Hour)
+	projects := []Project{
+		{Name: "website", CreatedAt: start},
+		{Name: "garden", CreatedAt: start, ClosedAt: &closed},
+		{Name: "empty", CreatedAt: start},
+	}
+	tasks := []Task{
+		{ID: 1, Project: "website", Status: StatusTodo, UpdatedAt: start.Add(time.Hour)},
+		{ID: 2, Project: "website", Status: StatusInProgress, UpdatedAt: start.Add(5 * time.Hour)},
+		{ID: 3, Project: "website", Status: StatusDone, UpdatedAt: start.Add(2 * time.Hour)},
+		{ID: 4, Project: "website", Status: StatusCancelled, UpdatedAt: start},
+		{ID: 5, Project: "garden", Status: StatusDone, UpdatedAt: start.Add(time.Hour)},
+		{ID: 6, Project: "unknown", Status: StatusTodo, UpdatedAt: start},
+		{ID: 7, Status: StatusTodo, UpdatedAt: start.Add(100 * time.Hour)},
+	}
+
+	stats := BuildProjectStats(projects, tasks)
+
+	if len(stats) != 3 {
+		t.Fatalf("got %d projects, want 3", len(stats))
+	}
+	if s := stats[0]; s.Open != 2 || s.Done != 1 || !s.LastActivity.Equal(start.Add(5*time.Hour)) {
+		t.Errorf("website = %+v, want 2 open, 1 done, active 5h in", s)
+	}
+	if s := stats[1]; s.Open != 0 || s.Done != 1 || !s.LastActivity.Equal(closed) {
+		t.Errorf("garden = %+v, want 1 done, last active when closed", s)
+	}
+	if s := stats[2]; s.Open != 0 || s.Done != 0 || !s.LastActivity.Equal(start) {
+		t.Errorf("empty = %+v, want no tasks, active when created", s)
+	}
+}
+
+// TestValidateProjectName tests rejecting unusable project names
+func TestValidateProjectName(t *testing.T) {
+	if name, err := ValidateProjectName("  website "); err != nil || name != "website" {
+		t.Errorf("ValidateProjectName = %q, %v, want website", name, err)
+	}
+	for _, name := range []string{"", "  ", "web site"} {
+		if _, err := ValidateProjectName(name); !errors.Is(err, ErrInvalidProject) {
+			t.Errorf("ValidateProjectName(%q) error = %v, want ErrInvalidProject", name, err)
+		}
+	}
+}
@@ -0,0 +1,504 @@
+package task
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Filter expressions for list --where
+//
+// A filter combines comparisons with && (and), || (or), ! (not) and
+// parentheses, && binding tighter than ||:
+//
+//	status != done && (tag:work || priority >= high) && created > 2024-01-01
+//
+// A comparison is a field, an operator and a value. "field:value" is short
+// for "field = value", ~ tests whether text contains the value, and "none"
+// stands for an unset priority, project, milestone, assignee or due date.
+// Dates accept everything --due does; a date without a time of day covers
+// the whole day, so "created = today" matches anything created today.
+// Values with spaces or symbols are quoted: due < "next friday".
+
+// QueryEnv is what filter values are resolved against
+type QueryEnv struct {
+	// Now anchors relative dates such as "today" or "3d ago"
+	Now time.Time
+	// Workflow lists the statuses a filter may name
+	Workflow Workflow
+	// User is who "me" stands for
+	User string
+}
+
+// Query is a parsed filter expression
+type Query struct {
+	input string
+	match func(Task) bool
+}
+
+// Match reports whether the task passes the filter
+func (q *Query) Match(task Task) bool {
+	return q.match(task)
+}
+
+func (q *Query) String() string {
+	return q.input
+}
+
+// QueryError points at the part of a filter expression that is wrong
+type QueryError struct {
+	Input string
+	// Pos is the byte offset of the problem in Input
+	Pos     int
+	Message string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid filter at column %d: %s", e.Pos+1, e.Message)
+}
+
+// Caret returns the filter with a caret under the problem, for showing
+// below the error
+func (e *QueryError) Caret() string {
+	return e.Input + "\n" + strings.Repeat(" ", e.Pos) + "^"
+}
+
+// ParseQuery parses a filter expression
+func ParseQuery(input string, env QueryEnv) (*Query, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{input: input, tokens: tokens, env: env}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEnd {
+		if tok.kind == tokRParen {
+			return nil, p.errorf(tok, "unexpected ) without a matching (")
+		}
+		return nil, p.errorf(tok, "expected && or || before %s", tok.describe())
+	}
+	return &Query{input: input, match: match}, nil
+}
+
+type tokenKind int
+
+const (
+	tokEnd tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type queryToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t queryToken) describe() string {
+	switch t.kind {
+	case tokEnd:
+		return "the end of the filter"
+	case tokString:
+		return strconv.Quote(t.text)
+	default:
+		return "\"" + t.text + "\""
+	}
+}
+
+// isWordRune reports whether r may appear in a bare field name or value
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.+/@#", r)
+}
+
+func lexQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	errorAt := func(pos int, format string, args ...any) error {
+		return &QueryError{Input: input, Pos: pos, Message: fmt.Sprintf(format, args...)}
+	}
+
+	for i := 0; i < len(input); {
+		r := rune(input[i])
+		two := ""
+		if i+1 < len(input) {
+			two = input[i : i+2]
+		}
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			i++
+		case two == "&&":
+			tokens = append(tokens, queryToken{tokAnd, two, i})
+			i += 2
+		case two == "||":
+			tokens = append(tokens, queryToken{tokOr, two, i})
+			i += 2
+		case r == '&' || r == '|':
+			return nil, errorAt(i, "unexpected %c, did you mean %c%c?", r, r, r)
+		case two == "!=" || two == "<=" || two == ">=" || two == "==":
+			tokens = append(tokens, queryToken{tokOp, two, i})
+			i += 2
+		case r == '!':
+			tokens = append(tokens, queryToken{tokNot, "!", i})
+			i++
+		case strings.ContainsRune("=<>~:", r):
+			tokens = append(tokens, queryToken{tokOp, string(r), i})
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{tokLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{tokRParen, ")", i})
+			i++
+		case r == '"' || r == '\'':
+			end := strings.IndexByte(input[i+1:], byte(r))
+			if end < 0 {
+				return nil, errorAt(i, "unterminated %c quote", r)
+			}
+			tokens = append(tokens, queryToken{tokString, input[i+1 : i+1+end], i})
+			i += end + 2
+		default:
+			start := i
+			for i < len(input) {
+				r, size := utf8.DecodeRuneInString(input[i:])
+				if !isWordRune(r) {
+					break
+				}
+				i += size
+			}
+			if i == start {
+				r, _ := utf8.DecodeRuneInString(input[i:])
+				return nil, errorAt(i, "unexpected character %q", r)
+			}
+			tokens = append(tokens, queryToken{tokWord, input[start:i], start})
+		}
+	}
+	return append(tokens, queryToken{tokEnd, "", len(input)}), nil
+}
+
+type queryParser struct {
+	input  string
+	tokens []queryToken
+	next   int
+	env    QueryEnv
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.next]
+}
+
+func (p *queryParser) take() queryToken {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEnd {
+		p.next++
+	}
+	return tok
+}
+
+func (p *queryParser) errorf(tok queryToken, format string, args ...any) error {
+	return &QueryError{Input: p.input, Pos: tok.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// parseOr parses terms joined by ||
+func (p *queryParser) parseOr() (func(Task) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.take()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(task Task) bool { return l(task) || right(task) }
+	}
+	return left, nil
+}
+
+// parseAnd parses terms joined by &&
+func (p *queryParser) parseAnd() (func(Task) bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.take()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(task Task) bool { return l(task) && right(task) }
+	}
+	return left, nil
+}
+
+// parseUnary parses a negation, a parenthesized filter or a comparison
+func (p *queryParser) parseUnary() (func(Task) bool, error) {
+	tok := p.take()
+	switch tok.kind {
+	case tokNot:
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(task Task) bool { return !inner(task) }, nil
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, p.errorf(p.peek(), "expected ) to close the ( at column %d, found %s", tok.pos+1, p.peek().describe())
+		}
+		p.take()
+		return inner, nil
+	case tokWord:
+		return p.parseComparison(tok)
+	default:
+		return nil, p.errorf(tok, "expected a field name, found %s", tok.describe())
+	}
+}
+
+func (p *queryParser) parseComparison(field queryToken) (func(Task) bool, error) {
+	name := strings.ToLower(field.text)
+	compile, ok := queryFields[name]
+	if !ok {
+		return nil, p.errorf(field, "unknown field %q (fields: %s)", field.text, strings.Join(queryFieldNames(), ", "))
+	}
+
+	op := p.take()
+	if op.kind != tokOp {
+		return nil, p.errorf(op, "expected an operator after %s, found %s", name, op.describe())
+	}
+	value := p.take()
+	if value.kind != tokWord && value.kind != tokString {
+		return nil, p.errorf(value, "expected a value after %s, found %s", op.text, value.describe())
+	}
+
+	operator := op.text
+	switch operator {
+	case ":", "==":
+		operator = "="
+	}
+	match, err := compile(operator, value.text, p.env)
+	if errors.Is(err, errQueryOperator) {
+		return nil, p.errorf(op, "operator %s does not apply to %s", op.text, name)
+	}
+	if err != nil {
+		return nil, p.errorf(value, "%s", err.Error())
+	}
+	return match, nil
+}
+
+// errQueryOperator is returned by field compilers for operators the field
+// does not support
+var errQueryOperator = errors.New("unsupported operator")
+
+// queryField builds the test for one comparison on a field
+type queryField func(op, value string, env QueryEnv) (func(Task) bool, error)
+
+var queryFields = map[string]queryField{
+	"id": func(op, value string, _ QueryEnv) (func(Task) bool, error) {
+		want, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ID %q", value)
+		}
+		return orderedMatch(op, func(task Task) int { return cmp.Compare(task.ID, want) })
+	},
+	"status": func(op, value string, env QueryEnv) (func(Task) bool, error) {
+		if !env.Workflow.Has(TaskStatus(value)) {
+			return nil, fmt.Errorf("invalid status %q (statuses: %s)", value, strings.Join(statusNames(env.Workflow), ", "))
+		}
+		return textMatch(op, value, func(task Task) string { return string(task.Status) })
+	},
+	"priority": func(op, value string, _ QueryEnv) (func(Task) bool, error) {
+		want := Priority(strings.ToLower(value))
+		if want == "none" {
+			want = PriorityNone
+		}
+		if !want.IsValid() {
+			return nil, fmt.Errorf("invalid priority %q (low, medium, high, urgent or none)", value)
+		}
+		return orderedMatch(op, func(task Task) int {
+			return cmp.Compare(priorityRank(task.Priority), priorityRank(want))
+		})
+	},
+	"tag": func(op, value string, _ QueryEnv) (func(Task) bool, error) {
+		tag := NormalizeTag(value)
+		switch op {
+		case "=":
+			return func(task Task) bool { return slices.Contains(task.Tags, tag) }, nil
+		case "!=":
+			return func(task Task) bool { return !slices.Contains(task.Tags, tag) }, nil
+		case "~":
+			return func(task Task) bool {
+				return slices.ContainsFunc(task.Tags, func(t string) bool { return strings.Contains(t, tag) })
+			}, nil
+		}
+		return nil, errQueryOperator
+	},
+	"list":        optionalTextField(func(task Task) string { return task.List }),
+	"project":     optionalTextField(func(task Task) string { return task.Project }),
+	"milestone":   optionalTextField(func(task Task) string { return task.Milestone }),
+	"sprint":      optionalTextField(func(task Task) string { return task.Sprint }),
+	"description": descriptionField,
+	"text":        descriptionField,
+	"assignee":    userField(func(task Task) string { return task.Assignee }),
+	"creator":     userField(func(task Task) string { return task.CreatedBy }),
+	"created":     timeField(func(task Task) *time.Time { return &task.CreatedAt }),
+	"updated":     timeField(func(task Task) *time.Time { return &task.UpdatedAt }),
+	"due":         timeField(func(task Task) *time.Time { return task.DueAt }),
+	"estimate": func(op, value string, _ QueryEnv) (func(Task) bool, error) {
+		want, err := ParseDuration(value)
+		if value == "none" {
+			want, err = 0, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid estimate %q", value)
+		}
+		return orderedMatch(op, func(task Task) int { return cmp.Compare(time.Duration(task.Estimate), want) })
+	},
+}
+
+func queryFieldNames() []string {
+	names := make([]string, 0, len(queryFields))
+	for name := range queryFields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func statusNames(workflow Workflow) []string {
+	names := make([]string, len(workflow.Statuses))
+	for i, status := range workflow.Statuses {
+		names[i] = string(status)
+	}
+	return names
+}
+
+// orderedMatch tests the sign of compare, which compares a task's field
+// with the value
+func orderedMatch(op string, compare func(Task) int) (func(Task) bool, error) {
+	var test func(int) bool
+	switch op {
+	case "=":
+		test = func(c int) bool { return c == 0 }
+	case "!=":
+		test = func(c int) bool { return c != 0 }
+	case "<":
+		test = func(c int) bool { return c < 0 }
+	case "<=":
+		test = func(c int) bool { return c <= 0 }
+	case ">":
+		test = func(c int) bool { return c > 0 }
+	case ">=":
+		test = func(c int) bool { return c >= 0 }
+	default:
+		return nil, errQueryOperator
+	}
+	return func(task Task) bool { return test(compare(task)) }, nil
+}
+
+// textMatch compares text exactly, or case-insensitively for ~
+func textMatch(op, value string, field func(Task) string) (func(Task) bool, error) {
+	switch op {
+	case "=":
+		return func(task Task) bool { return field(task) == value }, nil
+	case "!=":
+		return func(task Task) bool { return field(task) != value }, nil
+	case "~":
+		value = strings.ToLower(value)
+		return func(task Task) bool { return strings.Contains(strings.ToLower(field(task)), value) }, nil
+	}
+	return nil, errQueryOperator
+}
+
+// optionalTextField compares a field that may be unset, "none" matching
+// tasks where it is
+func optionalTextField(field func(Task) string) queryField {
+	return func(op, value string, _ QueryEnv) (func(Task) bool, error) {
+		if value == "none" {
+			value = ""
+		}
+		return textMatch(op, value, field)
+	}
+}
+
+// userField compares a user, "me" standing for the current user
+func userField(field func(Task) string) queryField {
+	return func(op, value string, env QueryEnv) (func(Task) bool, error) {
+		if value == "me" {
+			value = env.User
+		}
+		return optionalTextField(field)(op, value, env)
+	}
+}
+
+func descriptionField(op, value string, _ QueryEnv) (func(Task) bool, error) {
+	return textMatch(op, value, func(task Task) string { return task.Description })
+}
+
+// timeField compares a time with a date, a date without a time of day
+// standing for the whole day. Tasks without the time, like tasks with no
+// due date, only match != and "= none".
+func timeField(field func(Task) *time.Time) queryField {
+	return func(op, value string, env QueryEnv) (func(Task) bool, error) {
+		if value == "none" {
+			switch op {
+			case "=":
+				return func(task Task) bool { return field(task) == nil }, nil
+			case "!=":
+				return func(task Task) bool { return field(task) != nil }, nil
+			}
+			return nil, errQueryOperator
+		}
+
+		start, dateOnly, err := parseWhen(value, env.Now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", value)
+		}
+		end := start
+		if dateOnly {
+			end = start.AddDate(0, 0, 1)
+		}
+		compare := func(task Task) int {
+			t := field(task)
+			switch {
+			case t.Before(start):
+				return -1
+			case dateOnly && t.Before(end), t.Equal(start):
+				return 0
+			default:
+				return 1
+			}
+		}
+
+		test, err := orderedMatch(op, compare)
+		if err != nil {
+			return nil, err
+		}
+		return func(task Task) bool {
+			if field(task) == nil {
+				return op == "!="
+			}
+			return test(task)
+		}, nil
+	}
+}
@@ -0,0 +1,44 @@
+package task_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestParseQuery_Errors tests that errors point at the faulty part
+func TestParseQuery_Errors(t *testing.T) {
+	env := QueryEnv{Now: tasktest.FixedTime(), Workflow: DefaultWorkflow()}
+	tests := []struct {
+		query   string
+		pos     int
+		message string
+	}{
+		{"colour = red", 0, `unknown field "colour"`},
+		{"status done", 7, `expected an operator after status, found "done"`},
+		{"status = ", 9, "expected a value after =, found the end of the filter"},
+		{"status = finished", 9, `invalid status "finished"`},
+		{"tag < work", 4, "operator < does not apply to tag"},
+		{"priority >= hgh", 12, `invalid priority "hgh"`},
+		{"(tag:a || tag:b", 15, "expected ) to close the ( at column 1"},
+		{"tag:a & tag:b", 6, "did you mean &&?"},
+		{"tag:a tag:b", 6, `expected && or || before "tag"`},
+		{"tag:a)", 5, "unexpected ) without a matching ("},
+		{`due < "next`, 6, "unterminated \" quote"},
+		{"created > someday", 10, `invalid date "someday"`},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.query, env)
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) {
+			t.Errorf("ParseQuery(%q) error = %v, want a QueryError", tt.query, err)
+			continue
+		}
+		if queryErr.Pos != tt.pos || !strings.Contains(queryErr.Message, tt.message) {
+			t.Errorf("ParseQuery(%q) error at %d %q, want at %d %q", tt.query, queryErr.Pos, queryErr.Message, tt.pos, tt.message)
+		}
+	}
+}
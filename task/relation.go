@@ -0,0 +1,70 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ParseRelationType reads the relation named on the command line; the
+// reverse side of duplicates is only ever stored, not given
+func ParseRelationType(s string) (RelationType, error) {
+	switch RelationType(s) {
+	case RelationDuplicates, RelationRelatesTo:
+		return RelationType(s), nil
+	case "relates":
+		return RelationRelatesTo, nil
+	}
+	return "", fmt.Errorf("%w, not %q", ErrInvalidRelation, s)
+}
+
+// Inverse returns the relation as stored on the other task
+func (r RelationType) Inverse() RelationType {
+	switch r {
+	case RelationDuplicates:
+		return RelationDuplicatedBy
+	case RelationDuplicatedBy:
+		return RelationDuplicates
+	}
+	return r
+}
+
+// IsDuplicate reports whether the relation marks one of the tasks as a
+// copy of the other, whichever side it is stored on
+func (r RelationType) IsDuplicate() bool {
+	return r == RelationDuplicates || r == RelationDuplicatedBy
+}
+
+// Link relates the task to another, replacing any relation it already has
+// to that task
+func (t *Task) Link(kind RelationType, id int) {
+	relations := slices.DeleteFunc(slices.Clone(t.Relations), func(r Relation) bool { return r.Task == id })
+	t.Relations = append(relations, Relation{Type: kind, Task: id})
+	t.touch()
+}
+
+// Unlink removes the task's relation to another and reports whether it
+// had one
+func (t *Task) Unlink(id int) bool {
+	if !slices.ContainsFunc(t.Relations, func(r Relation) bool { return r.Task == id }) {
+		return false
+	}
+	// Copy so earlier snapshots sharing the slice keep their relations
+	t.Relations = slices.DeleteFunc(slices.Clone(t.Relations), func(r Relation) bool { return r.Task == id })
+	if len(t.Relations) == 0 {
+		t.Relations = nil
+	}
+	t.touch()
+	return true
+}
+
+// DuplicateIDs returns the tasks linked to this one as duplicates, in
+// either direction
+func (t Task) DuplicateIDs() []int {
+	var ids []int
+	for _, relation := range t.Relations {
+		if relation.Type.IsDuplicate() {
+			ids = append(ids, relation.Task)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,70 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ScheduleRefSystem is the ExternalRefs key linking a task to the schedule
+// occurrence that created it
+const ScheduleRefSystem = "schedule"
+
+// Schedule creates a task on every occurrence of a cron expression
+type Schedule struct {
+	Name        string
+	Cron        CronSchedule
+	Description string
+	Options     []TaskOption
+	// Due sets the deadline this long after the occurrence, zero for none
+	Due time.Duration
+}
+
+// ScheduleState remembers the last occurrence materialized by each schedule
+type ScheduleState map[string]time.Time
+
+// ScheduleRef identifies one occurrence of a schedule
+func ScheduleRef(name string, occurrence time.Time) string {
+	return name + "@" + occurrence.UTC().Format(time.RFC3339)
+}
+
+// NextScheduled returns the earliest upcoming occurrence of the schedules
+func NextScheduled(schedules []Schedule, now time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, schedule := range schedules {
+		if occurrence, ok := schedule.Cron.Next(now); ok && (next.IsZero() || occurrence.Before(next)) {
+			next = occurrence
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// LoadScheduleState reads the schedule state file, empty when it is missing
+func LoadScheduleState(path string) (ScheduleState, error) {
+	state := ScheduleState{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid schedule state %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// SaveScheduleState writes the schedule state file
+func SaveScheduleState(path string, state ScheduleState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write schedule state: %w", err)
+	}
+	return nil
+}
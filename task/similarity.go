@@ -0,0 +1,118 @@
+package task
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// SimilarThreshold is the similarity from which a new task is taken for a
+// likely duplicate of an open one
+const SimilarThreshold = 0.8
+
+// Similarity scores how alike two descriptions are, from 0 for nothing in
+// common to 1 for the same normalized description. It is the better of the
+// edit distance, which catches typos and swapped letters, and the share of
+// words in common, which catches reordered or added words.
+func Similarity(a, b string) float64 {
+	a, b = NormalizeDescription(a), NormalizeDescription(b)
+	if a == b {
+		return 1
+	}
+	return max(editSimilarity(a, b), wordOverlap(a, b))
+}
+
+// editSimilarity is one minus the Levenshtein distance between a and b over
+// the length of the longer one
+func editSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein counts the single-character insertions, deletions and
+// substitutions turning a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// wordOverlap is the Dice coefficient of the distinct words of a and b:
+// twice the words in common over the words of both
+func wordOverlap(a, b string) float64 {
+	wa, wb := wordSet(a), wordSet(b)
+	if len(wa)+len(wb) == 0 {
+		return 0
+	}
+	common := 0
+	for word := range wa {
+		if wb[word] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(wa)+len(wb))
+}
+
+// wordSet returns the distinct words of a normalized description, without
+// the punctuation around them
+func wordSet(s string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(s) {
+		if word = strings.Trim(word, ".,;:!?…()\"'"); word != "" {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// SimilarTask is an existing task found close to a new description
+type SimilarTask struct {
+	Task  Task
+	Score float64
+}
+
+// FindSimilar returns the open tasks whose descriptions are at least
+// threshold similar to description, most similar first
+func FindSimilar(tasks []Task, description string, threshold float64) []SimilarTask {
+	var similar []SimilarTask
+	for _, task := range tasks {
+		if !task.IsOpen() {
+			continue
+		}
+		if score := Similarity(description, task.Description); score >= threshold {
+			similar = append(similar, SimilarTask{Task: task, Score: score})
+		}
+	}
+	slices.SortStableFunc(similar, func(a, b SimilarTask) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Task.ID, b.Task.ID))
+	})
+	return similar
+}
+
+// Bump raises the task one priority level, up to urgent, for when it comes
+// up again instead of being added twice
+func (t *Task) Bump() {
+	levels := []Priority{PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+	if rank := priorityRank(t.Priority); rank >= 0 && rank < len(levels)-1 {
+		t.Priority = levels[rank+1]
+	}
+	t.touch()
+}
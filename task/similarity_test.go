@@ -0,0 +1,68 @@
+package task
+
+import "testing"
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		similar bool
+	}{
+		{"Buy groceries", "buy groceries.", true},
+		{"Buy groceries", "Buy grocereis", true},
+		{"Buy milk", "Buy milk today", true},
+		{"Write the report", "report: write the", true},
+		{"Buy milk", "Buy a car", false},
+		{"Call mom", "Call the bank", false},
+		{"Fix the login bug", "Fix the signup page", false},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b) >= SimilarThreshold; got != tt.similar {
+			t.Errorf("Similarity(%q, %q) = %.2f, similar = %v, want %v",
+				tt.a, tt.b, Similarity(tt.a, tt.b), got, tt.similar)
+		}
+	}
+	if got := Similarity("Buy groceries", "BUY GROCERIES"); got != 1 {
+		t.Errorf("Similarity of the same description = %.2f, want 1", got)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFindSimilar(t *testing.T) {
+	tasks := []Task{
+		{ID: 1, Description: "Buy milk", Status: StatusTodo},
+		{ID: 2, Description: "Buy milk today", Status: StatusDone},
+		{ID: 3, Description: "Buy milk!", Status: StatusInProgress},
+	}
+	got := FindSimilar(tasks, "buy milk", SimilarThreshold)
+	if len(got) != 2 || got[0].Task.ID != 1 || got[1].Task.ID != 3 {
+		t.Errorf("FindSimilar() = %+v, want the open tasks #1 and #3", got)
+	}
+}
+
+func TestTask_Bump(t *testing.T) {
+	task := Task{Priority: PriorityHigh}
+	task.Bump()
+	if task.Priority != PriorityUrgent {
+		t.Errorf("bumped high priority = %q, want urgent", task.Priority)
+	}
+	task.Bump()
+	if task.Priority != PriorityUrgent {
+		t.Errorf("bumped urgent priority = %q, want it kept", task.Priority)
+	}
+}
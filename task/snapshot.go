@@ -0,0 +1,51 @@
+package task
+
+import (
+	"errors"
+	"time"
+)
+
+// Snapshot is a copy of every task at one point in time, kept to compare
+// against later
+type Snapshot struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	Tasks     []Task    `json:"tasks"`
+}
+
+// SnapshotNameFormat names snapshots created without a name; it sorts in
+// chronological order
+const SnapshotNameFormat = "20060102-150405"
+
+// ErrNoSnapshots is returned when the service has no snapshot storage
+var ErrNoSnapshots = errors.New("snapshots are not available with this storage")
+
+// CurrentState names the live task list in a diff
+const CurrentState = "current"
+
+// SnapshotDiff lists how tasks differ from one snapshot to another, or to
+// the current state
+type SnapshotDiff struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Changes []SnapshotChange `json:"changes"`
+}
+
+// SnapshotChange is a task added, removed or changed between snapshots
+type SnapshotChange struct {
+	Type        ChangeType    `json:"type"`
+	ID          int           `json:"id"`
+	Description string        `json:"description"`
+	Fields      []FieldChange `json:"fields,omitempty"`
+}
+
+// Count returns how many changes of the type the diff holds
+func (d SnapshotDiff) Count(kind ChangeType) int {
+	n := 0
+	for _, change := range d.Changes {
+		if change.Type == kind {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,113 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Sprint is a timebox that tasks are committed to. Tasks refer to their
+// sprint by name; a sprint is active from its start until its end, unless
+// closed early.
+type Sprint struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Capacity is the effort the team can take on, zero when unplanned
+	Capacity  Duration  `json:"capacity,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	// ClosedAt is when the sprint was closed, nil while it is open
+	ClosedAt *time.Time `json:"closedAt,omitempty"`
+	// CarriedOver lists the tasks moved in unfinished from the previous
+	// sprint by rollover
+	CarriedOver []int `json:"carriedOver,omitempty"`
+}
+
+// ErrNoSprints is returned when the service has no sprint storage
+var ErrNoSprints = errors.New("sprints are not available with this storage")
+
+// IsClosed reports whether the sprint was closed
+func (s Sprint) IsClosed() bool {
+	return s.ClosedAt != nil
+}
+
+// ActiveAt reports whether the sprint is open and runs at now
+func (s Sprint) ActiveAt(now time.Time) bool {
+	return !s.IsClosed() && !now.Before(s.Start) && now.Before(s.End)
+}
+
+// SprintStatus reports the commitment and progress of a sprint
+type SprintStatus struct {
+	Sprint
+	// Committed counts the tasks in the sprint, cancelled ones excluded
+	Committed int
+	Done      int
+	// CommittedEffort and DoneEffort add up the estimates of those tasks
+	CommittedEffort time.Duration
+	DoneEffort      time.Duration
+	// Unfinished lists the open tasks, which carry over when the sprint
+	// closes
+	Unfinished []Task
+}
+
+// OverCapacity reports whether more effort is committed than the sprint's
+// capacity
+func (s SprintStatus) OverCapacity() bool {
+	return s.Capacity > 0 && s.CommittedEffort > time.Duration(s.Capacity)
+}
+
+// CarriedOver reports whether the task came in unfinished from the
+// previous sprint
+func (s SprintStatus) CarriedOver(id int) bool {
+	return slices.Contains(s.Sprint.CarriedOver, id)
+}
+
+// BuildSprintStatus reports the commitment and progress of a sprint
+func BuildSprintStatus(sprint Sprint, tasks []Task) SprintStatus {
+	status := SprintStatus{Sprint: sprint}
+	for _, task := range tasks {
+		if task.Sprint != sprint.Name || task.Status == StatusCancelled {
+			continue
+		}
+		status.Committed++
+		status.CommittedEffort += time.Duration(task.Estimate)
+		if task.Status == StatusDone {
+			status.Done++
+			status.DoneEffort += time.Duration(task.Estimate)
+			continue
+		}
+		status.Unfinished = append(status.Unfinished, task)
+	}
+	return status
+}
+
+// ValidateSprint trims the sprint name and checks its dates
+func ValidateSprint(sprint Sprint) (Sprint, error) {
+	sprint.Name = strings.TrimSpace(sprint.Name)
+	if sprint.Name == "" || sprint.Name == "none" {
+		return sprint, ErrInvalidSprint
+	}
+	if !sprint.End.After(sprint.Start) || sprint.Capacity < 0 {
+		return sprint, ErrInvalidSprintDates
+	}
+	return sprint, nil
+}
+
+// FindSprint returns the named sprint, or with an empty name the sprint
+// active at now
+func FindSprint(sprints []Sprint, name string, now time.Time) (int, error) {
+	if name != "" {
+		i := slices.IndexFunc(sprints, func(sprint Sprint) bool { return sprint.Name == name })
+		if i < 0 {
+			return 0, fmt.Errorf("%w: %s", ErrSprintNotFound, name)
+		}
+		return i, nil
+	}
+	i := slices.IndexFunc(sprints, func(sprint Sprint) bool { return sprint.ActiveAt(now) })
+	if i < 0 {
+		return 0, ErrNoActiveSprint
+	}
+	return i, nil
+}
@@ -0,0 +1,34 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestBuildSprintStatus tests commitment, progress and carry-over per sprint
+func TestBuildSprintStatus(t *testing.T) {
+	now := tasktest.FixedTime()
+	sprint := Sprint{Name: "W1", Start: now, End: now.Add(7 * 24 * time.Hour), Capacity: Duration(4 * time.Hour), CarriedOver: []int{2}}
+	tasks := []Task{
+		{ID: 1, Sprint: "W1", Status: StatusDone, Estimate: Duration(time.Hour)},
+		{ID: 2, Sprint: "W1", Status: StatusInProgress, Estimate: Duration(2 * time.Hour)},
+		{ID: 3, Sprint: "W1", Status: StatusTodo, Estimate: Duration(2 * time.Hour)},
+		{ID: 4, Sprint: "W1", Status: StatusCancelled, Estimate: Duration(8 * time.Hour)},
+		{ID: 5, Sprint: "W2", Status: StatusTodo},
+	}
+
+	status := BuildSprintStatus(sprint, tasks)
+
+	if status.Committed != 3 || status.Done != 1 || status.CommittedEffort != 5*time.Hour || status.DoneEffort != time.Hour {
+		t.Errorf("status = %d/%d, %s/%s, want 1/3, 1h/5h", status.Done, status.Committed, status.DoneEffort, status.CommittedEffort)
+	}
+	if !status.OverCapacity() {
+		t.Error("OverCapacity() = false with 5h committed against 4h")
+	}
+	if len(status.Unfinished) != 2 || !status.CarriedOver(2) || status.CarriedOver(3) {
+		t.Errorf("unfinished = %+v, want #2 carried over and #3", status.Unfinished)
+	}
+}
@@ -0,0 +1,59 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TaskCounts tallies tasks by status, for summaries and list --count
+type TaskCounts struct {
+	ByStatus map[TaskStatus]int
+	// Overdue counts open tasks whose due date has passed
+	Overdue int
+	Total   int
+}
+
+// Add counts one task as of now
+func (c *TaskCounts) Add(task Task, now time.Time) {
+	if c.ByStatus == nil {
+		c.ByStatus = make(map[TaskStatus]int)
+	}
+	c.ByStatus[task.Status]++
+	c.Total++
+	if task.IsOpen() && task.DueAt != nil && !task.DueAt.After(now) {
+		c.Overdue++
+	}
+}
+
+// Summary renders the counts on one line, such as "12 todo, 3 in-progress,
+// 45 done, 2 overdue", in workflow order. Cancelled tasks only appear when
+// there are some.
+func (c TaskCounts) Summary(workflow Workflow) string {
+	return c.SummaryIn(workflow, nil)
+}
+
+// SummaryIn renders the summary with the status labels and wording of tr
+func (c TaskCounts) SummaryIn(workflow Workflow, tr *Translator) string {
+	parts := make([]string, 0, len(workflow.Statuses)+1)
+	for _, status := range workflow.Statuses {
+		n := c.ByStatus[status]
+		if status == StatusCancelled && n == 0 {
+			continue
+		}
+		parts = append(parts, statusCount(n, status, tr))
+	}
+	overdue := fmt.Sprintf(tr.Plural(c.Overdue, "%d overdue"), c.Overdue)
+	return strings.Join(append(parts, overdue), ", ")
+}
+
+// statusCount renders a count of tasks in a status, such as "3 done", with
+// the plural form of tr when its catalog has one for the status and its
+// label otherwise
+func statusCount(n int, status TaskStatus, tr *Translator) string {
+	other := "%d " + string(status)
+	if form := tr.Plural(n, other); form != other {
+		return fmt.Sprintf(form, n)
+	}
+	return fmt.Sprintf("%d %s", n, tr.Status(status))
+}
@@ -0,0 +1,41 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestTaskCounts_Summary tests the one-line snapshot
+func TestTaskCounts_Summary(t *testing.T) {
+	now := tasktest.FixedTime()
+	past := now.Add(-time.Hour)
+	tasks := tasktest.FixedTasks(t)
+	tasks[0].DueAt = &past
+	tasks[2].DueAt = &past // done, so not overdue
+
+	var counts TaskCounts
+	for _, task := range tasks {
+		counts.Add(task, now)
+	}
+	if got, want := counts.Summary(DefaultWorkflow()), "1 todo, 1 in-progress, 1 done, 1 overdue"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	counts.Add(Task{Status: StatusCancelled}, now)
+	if got, want := counts.Summary(DefaultWorkflow()), "1 todo, 1 in-progress, 1 done, 1 cancelled, 1 overdue"; got != want {
+		t.Errorf("Summary() with a cancelled task = %q, want %q", got, want)
+	}
+
+	counts.Add(Task{Status: StatusDone}, now)
+	fr, _ := NewTranslator("fr")
+	if got, want := counts.SummaryIn(DefaultWorkflow(), fr), "1 à faire, 1 en cours, 2 terminées, 1 annulée, 1 en retard"; got != want {
+		t.Errorf("SummaryIn(fr) = %q, want %q", got, want)
+	}
+	custom := Workflow{Statuses: []TaskStatus{StatusTodo, "review"}}
+	if got, want := counts.SummaryIn(custom, fr), "1 à faire, 0 review, 1 en retard"; got != want {
+		t.Errorf("SummaryIn(fr) with a custom status = %q, want %q", got, want)
+	}
+}
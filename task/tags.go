@@ -0,0 +1,30 @@
+package task
+
+import (
+	"cmp"
+	"slices"
+)
+
+// TagCount is a tag and how many tasks carry it
+type TagCount struct {
+	Tag   string
+	Tasks int
+}
+
+// CountTags counts the tasks carrying each tag, most used first
+func CountTags(tasks []Task) []TagCount {
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		for _, tag := range task.Tags {
+			counts[tag]++
+		}
+	}
+	tags := make([]TagCount, 0, len(counts))
+	for tag, n := range counts {
+		tags = append(tags, TagCount{Tag: tag, Tasks: n})
+	}
+	slices.SortFunc(tags, func(a, b TagCount) int {
+		return cmp.Or(cmp.Compare(b.Tasks, a.Tasks), cmp.Compare(a.Tag, b.Tag))
+	})
+	return tags
+}
@@ -0,0 +1,17 @@
+package task_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+func TestCountTags(t *testing.T) {
+	got := CountTags(tasktest.TagTasks())
+	want := []TagCount{{Tag: "bug", Tasks: 2}, {Tag: "web", Tasks: 2}, {Tag: "defect", Tasks: 1}, {Tag: "issue", Tasks: 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("CountTags() = %v, want %v", got, want)
+	}
+}
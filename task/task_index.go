@@ -0,0 +1,45 @@
+package task
+
+// TaskIndex finds tasks of a loaded list by ID without scanning the list.
+// It points into the list, so it is only valid until tasks are added or
+// removed.
+type TaskIndex struct {
+	tasks    []Task
+	position map[int]int
+}
+
+// NewTaskIndex indexes the tasks by ID
+func NewTaskIndex(tasks []Task) TaskIndex {
+	position := make(map[int]int, len(tasks))
+	for i, task := range tasks {
+		// Like a scan, the first task with a duplicated ID wins
+		if _, ok := position[task.ID]; !ok {
+			position[task.ID] = i
+		}
+	}
+	return TaskIndex{tasks: tasks, position: position}
+}
+
+// Get returns the task with the ID, to be modified in place
+func (x TaskIndex) Get(id int) (*Task, bool) {
+	i, ok := x.position[id]
+	if !ok {
+		return nil, false
+	}
+	return &x.tasks[i], true
+}
+
+// Find returns a copy of the task with the ID
+func (x TaskIndex) Find(id int) (Task, bool) {
+	task, ok := x.Get(id)
+	if !ok {
+		return Task{}, false
+	}
+	return *task, true
+}
+
+// Position returns where the task with the ID is in the list
+func (x TaskIndex) Position(id int) (int, bool) {
+	i, ok := x.position[id]
+	return i, ok
+}
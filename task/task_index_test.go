@@ -0,0 +1,25 @@
+package task
+
+import "testing"
+
+// TestTaskIndex tests finding tasks by ID and modifying them in place
+func TestTaskIndex(t *testing.T) {
+	tasks := []Task{{ID: 4, Description: "a"}, {ID: 7, Description: "b"}, {ID: 4, Description: "dup"}}
+	index := NewTaskIndex(tasks)
+
+	task, ok := index.Get(7)
+	if !ok || task.Description != "b" {
+		t.Fatalf("Get(7) = %+v, %v; want b", task, ok)
+	}
+	task.Description = "changed"
+	if tasks[1].Description != "changed" {
+		t.Error("Get should return a pointer into the list")
+	}
+
+	if i, ok := index.Position(4); !ok || i != 0 {
+		t.Errorf("Position(4) = %d, %v; want the first of the duplicates", i, ok)
+	}
+	if _, ok := index.Get(5); ok {
+		t.Error("Get(5) found a missing task")
+	}
+}
@@ -0,0 +1,139 @@
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Task Templates
+//
+// Commands that print tasks, such as list and show, can print them through
+// a Go text/template instead, executed once per task with the Task as its
+// data. Besides the text/template builtins, templates may call:
+//
+//	upper, lower      change the case of a string, status or priority
+//	join              join a list: {{join .Tags ", "}}
+//	date              format a time: {{date "2006-01-02" .DueAt}}
+//	humanize          a time relative to now: {{humanize .UpdatedAt}}
+//	truncate          cut to a width, ending in "…": {{truncate 30 .Description}}
+//	color             color text for a terminal: {{color "red" .Description}}
+//
+// Times that are not set render as nothing, and color leaves text plain when
+// output is not a terminal or NO_COLOR is set.
+
+// TemplateEnv is what template functions depend on besides the task
+type TemplateEnv struct {
+	Now time.Time
+	// Color enables ANSI colors for the color function
+	Color bool
+}
+
+// templateColors maps the names color accepts to ANSI codes
+var templateColors = map[string]string{
+	"black":   "30",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+	"gray":    "90",
+	"bold":    "1",
+	"dim":     "2",
+}
+
+// templateTime accepts the time fields of a task, set or not
+func templateTime(value any) (time.Time, bool) {
+	switch t := value.(type) {
+	case time.Time:
+		return t, !t.IsZero()
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// templateFuncs returns the functions task templates may call
+func templateFuncs(env TemplateEnv) template.FuncMap {
+	return template.FuncMap{
+		"upper": func(value any) string { return strings.ToUpper(fmt.Sprint(value)) },
+		"lower": func(value any) string { return strings.ToLower(fmt.Sprint(value)) },
+		"join":  strings.Join,
+		"date": func(layout string, value any) string {
+			if t, ok := templateTime(value); ok {
+				return t.Format(layout)
+			}
+			return ""
+		},
+		"humanize": func(value any) string {
+			if t, ok := templateTime(value); ok {
+				return HumanizeTime(t, env.Now)
+			}
+			return ""
+		},
+		"truncate": func(width int, value any) string {
+			runes := []rune(fmt.Sprint(value))
+			if width <= 0 || len(runes) <= width {
+				return string(runes)
+			}
+			return string(runes[:width-1]) + "…"
+		},
+		"color": func(name string, value any) (string, error) {
+			code, ok := templateColors[name]
+			if !ok {
+				return "", fmt.Errorf("unknown color %q", name)
+			}
+			if !env.Color {
+				return fmt.Sprint(value), nil
+			}
+			return "\x1b[" + code + "m" + fmt.Sprint(value) + "\x1b[0m", nil
+		},
+	}
+}
+
+// ParseTaskTemplate compiles a task template. spec is the name of a
+// template in named or the template itself.
+func ParseTaskTemplate(spec string, named map[string]string, env TemplateEnv) (*template.Template, error) {
+	name := "template"
+	if text, ok := named[spec]; ok {
+		name, spec = spec, text
+	} else if !strings.Contains(spec, "{{") {
+		return nil, InvalidFormat(fmt.Sprintf("'%s' is neither a template nor one of the formats in the config file", spec))
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs(env)).Parse(spec)
+	if err != nil {
+		return nil, InvalidFormat(err.Error())
+	}
+	return tmpl, nil
+}
+
+// RenderTasks prints each task through tmpl, on its own line unless the
+// template ends the line itself
+func RenderTasks(w io.Writer, tmpl *template.Template, tasks []Task) error {
+	var b bytes.Buffer
+	for _, task := range tasks {
+		b.Reset()
+		if err := tmpl.Execute(&b, task); err != nil {
+			return InvalidFormat(err.Error())
+		}
+		if !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+			b.WriteByte('\n')
+		}
+		if _, err := b.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InvalidFormat reports a format spec that cannot be used
+func InvalidFormat(detail string) error {
+	return ErrInvalidFormat.Withf("%s", detail)
+}
@@ -0,0 +1,68 @@
+package task_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+	. "github.com/alnah/task-tracker/task"
+)
+
+// TestRenderTasks tests that templates ending their own lines are not given
+// another newline
+func TestRenderTasks(t *testing.T) {
+	tmpl, err := ParseTaskTemplate("- {{.Description}}\n", nil, TemplateEnv{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := RenderTasks(&b, tmpl, tasktest.FixedTasks(t)[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "- Buy groceries\n- Write report\n" {
+		t.Errorf("RenderTasks() = %q", b.String())
+	}
+	if _, err := ParseTaskTemplate("plain", nil, TemplateEnv{}); !errors.As(err, new(TaskError)) {
+		t.Errorf("ParseTaskTemplate(plain) error = %v, want a TaskError", err)
+	}
+}
+
+// TestTemplateFuncs tests the helper functions templates may call
+func TestTemplateFuncs(t *testing.T) {
+	task := tasktest.FixedTasks(t)[1]
+	task.Description = "Write the quarterly report"
+	task.Tags = []string{"work", "q3"}
+
+	tests := []struct {
+		text  string
+		color bool
+		want  string
+	}{
+		{`{{truncate 10 .Description}}`, false, "Write the…"},
+		{`{{truncate 40 .Description}}`, false, "Write the quarterly report"},
+		{`{{humanize .CreatedAt}}`, false, "2 hours ago"},
+		{`{{humanize .DueAt}}|{{date "2006-01-02" .DueAt}}`, false, "|"},
+		{`{{upper .Status}} {{join .Tags ","}}`, false, "IN-PROGRESS work,q3"},
+		{`{{color "red" .ID}}`, false, "2"},
+		{`{{color "red" .ID}}`, true, "\x1b[31m2\x1b[0m"},
+	}
+	for _, tt := range tests {
+		env := TemplateEnv{Now: task.CreatedAt.Add(2 * time.Hour), Color: tt.color}
+		tmpl, err := ParseTaskTemplate(tt.text, nil, env)
+		if err != nil {
+			t.Fatalf("ParseTaskTemplate(%q) error = %v", tt.text, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, task); err != nil || b.String() != tt.want {
+			t.Errorf("%s = %q, %v; want %q", tt.text, b.String(), err, tt.want)
+		}
+	}
+
+	tmpl, _ := ParseTaskTemplate(`{{color "pink" .ID}}`, nil, TemplateEnv{})
+	if err := RenderTasks(&bytes.Buffer{}, tmpl, []Task{task}); err == nil || !strings.Contains(err.Error(), "unknown color") {
+		t.Errorf("color pink error = %v, want unknown color", err)
+	}
+}
@@ -0,0 +1,37 @@
+package task
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"fmt"
+)
+
+// MinUUIDPrefix is the shortest UUID prefix accepted in place of an ID
+const MinUUIDPrefix = 4
+
+// NewUUID returns a random version 4 UUID
+func NewUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return formatUUID(b[:])
+}
+
+// TaskUUID returns the UUID of a task. Tasks created before tasks had one
+// get a name-based UUID derived from their ID and creation time, so it stays
+// the same from one run to the next.
+func TaskUUID(task Task) string {
+	if task.UUID != "" {
+		return task.UUID
+	}
+
+	sum := sha1.Sum([]byte(fmt.Sprintf("task-cli:%d:%d", task.ID, task.CreatedAt.UnixNano())))
+	sum[6] = sum[6]&0x0f | 0x50 // version 5
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 4122 variant
+	return formatUUID(sum[:16])
+}
+
+func formatUUID(b []byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,16 @@
+package task_test
+
+import (
+	"testing"
+
+	"github.com/alnah/task-tracker/internal/tasktest"
+)
+
+// TestNewTask_UUID tests that new tasks get distinct version 4 UUIDs
+func TestNewTask_UUID(t *testing.T) {
+	a := tasktest.NewTaskBuilder().WithID(1).WithDescription("Buy groceries").BuildValid(t)
+	b := tasktest.NewTaskBuilder().WithID(1).WithDescription("Buy groceries").BuildValid(t)
+	if len(a.UUID) != 36 || a.UUID[14] != '4' || a.UUID == b.UUID {
+		t.Errorf("UUIDs = %q and %q, want distinct version 4 UUIDs", a.UUID, b.UUID)
+	}
+}
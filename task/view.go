@@ -0,0 +1,136 @@
+package task
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// View is a saved set of list filters, shown with list --view. Users are
+// kept as typed, so a view with "me" follows whoever runs it.
+type View struct {
+	Name      string   `json:"name"`
+	Status    string   `json:"status,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Assignee  string   `json:"assignee,omitempty"`
+	CreatedBy string   `json:"createdBy,omitempty"`
+	Project   string   `json:"project,omitempty"`
+	Milestone string   `json:"milestone,omitempty"`
+	Sprint    string   `json:"sprint,omitempty"`
+	Where     string   `json:"where,omitempty"`
+	Sort      string   `json:"sort,omitempty"`
+	All       bool     `json:"all,omitempty"`
+	Limit     int      `json:"limit,omitempty"`
+	// Stale keeps open tasks that have been in their status this long
+	Stale Duration `json:"stale,omitempty"`
+	// MaxEffort keeps tasks estimated to take at most this long
+	MaxEffort Duration `json:"maxEffort,omitempty"`
+}
+
+// ErrNoViews is returned when the service has no view storage
+var ErrNoViews = errors.New("views are not available with this storage")
+
+// sortKeys are the orders list --sort accepts, each comparing two tasks in
+// its natural direction: oldest first, soonest due first, most urgent first
+var sortKeys = map[string]func(a, b Task) int{
+	"id":      func(a, b Task) int { return cmp.Compare(a.ID, b.ID) },
+	"created": func(a, b Task) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"updated": func(a, b Task) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+	"due": func(a, b Task) int {
+		switch {
+		case a.DueAt == nil || b.DueAt == nil:
+			// Tasks without a due date come last
+			return cmp.Compare(boolRank(a.DueAt == nil), boolRank(b.DueAt == nil))
+		default:
+			return a.DueAt.Compare(*b.DueAt)
+		}
+	},
+	"priority": func(a, b Task) int { return cmp.Compare(priorityRank(b.Priority), priorityRank(a.Priority)) },
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// priorityRank orders priorities from unset to urgent
+func priorityRank(p Priority) int {
+	return slices.Index([]Priority{PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}, p)
+}
+
+// SortTasks orders tasks by a sort key, reversed when it starts with "-".
+// Tasks that compare equal keep their order.
+func SortTasks(tasks []Task, key string) error {
+	compare, ok := sortKeys[strings.TrimPrefix(key, "-")]
+	if !ok {
+		return TaskError{
+			Code:    ErrInvalidSort.Code,
+			Message: fmt.Sprintf("%s '%s'. Valid options: %s", ErrInvalidSort.Message, key, strings.Join(sortKeyNames(), ", ")),
+		}
+	}
+	if strings.HasPrefix(key, "-") {
+		slices.SortStableFunc(tasks, func(a, b Task) int { return compare(b, a) })
+	} else {
+		slices.SortStableFunc(tasks, compare)
+	}
+	return nil
+}
+
+func sortKeyNames() []string {
+	names := make([]string, 0, len(sortKeys))
+	for name := range sortKeys {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Override returns the view with the filters set in other replacing its
+// own, except tags and filter expressions, which add up since a task must
+// pass all of them
+func (v View) Override(other View) View {
+	set := func(value *string, override string) {
+		if override != "" {
+			*value = override
+		}
+	}
+	set(&v.Status, other.Status)
+	set(&v.Assignee, other.Assignee)
+	set(&v.CreatedBy, other.CreatedBy)
+	set(&v.Project, other.Project)
+	set(&v.Milestone, other.Milestone)
+	set(&v.Sprint, other.Sprint)
+	set(&v.Sort, other.Sort)
+	v.Tags = MergeTags(v.Tags, other.Tags)
+	switch {
+	case v.Where == "":
+		v.Where = other.Where
+	case other.Where != "":
+		v.Where = "(" + v.Where + ") && (" + other.Where + ")"
+	}
+	v.All = v.All || other.All
+	if other.Limit > 0 {
+		v.Limit = other.Limit
+	}
+	if other.Stale > 0 {
+		v.Stale = other.Stale
+	}
+	if other.MaxEffort > 0 {
+		v.MaxEffort = other.MaxEffort
+	}
+	return v
+}
+
+// HasTags reports whether the task carries every one of the tags
+func (t Task) HasTags(tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(t.Tags, NormalizeTag(tag)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,16 @@
+package task
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestView_Override tests that flags replace a view's filters and add tags
+func TestView_Override(t *testing.T) {
+	saved := View{Name: "backlog", Status: "todo", Tags: []string{"backend"}, Where: "tag:a || tag:b", Sort: "created", Limit: 5}
+	got := saved.Override(View{Status: "in-progress", Tags: []string{"api"}, Where: "id > 3"})
+	if got.Status != "in-progress" || strings.Join(got.Tags, ",") != "backend,api" ||
+		got.Where != "(tag:a || tag:b) && (id > 3)" || got.Sort != "created" || got.Limit != 5 || got.Name != "backlog" {
+		t.Errorf("Override() = %+v", got)
+	}
+}
@@ -0,0 +1,13 @@
+package task
+
+// WIPLoad is how many tasks a status holds against its WIP limit
+type WIPLoad struct {
+	Status TaskStatus
+	Count  int
+	Limit  int
+}
+
+// Over reports whether the status holds more tasks than its limit
+func (l WIPLoad) Over() bool {
+	return l.Count > l.Limit
+}
@@ -0,0 +1,18 @@
+package task
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWorkflow_WithLimits(t *testing.T) {
+	for _, limits := range []map[string]int{{"shipped": 2}, {"in-progress": 0}} {
+		if _, err := DefaultWorkflow().WithLimits(limits); !errors.Is(err, ErrInvalidStatus) {
+			t.Errorf("WithLimits(%v) err = %v, want %v", limits, err, ErrInvalidStatus)
+		}
+	}
+	workflow, err := DefaultWorkflow().WithLimits(map[string]int{"In-Progress": 3})
+	if err != nil || workflow.Limits[StatusInProgress] != 3 {
+		t.Errorf("WithLimits() = %v, %v", workflow.Limits, err)
+	}
+}